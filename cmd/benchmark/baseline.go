@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// baseline loads the JSON results of the -baseline run once, so a missing or
+// malformed file fails the run before any database is benchmarked.
+var baseline = sync.OnceValue(func() map[string]*benchmark.Results {
+	if *baselineFile == "" {
+		return nil
+	}
+
+	results, err := loadBaseline(*baselineFile)
+	if err != nil {
+		log.Fatalf("--baseline: %v", err)
+	}
+
+	return results
+})
+
+// regressionThresholds parses -max-regression once.
+var regressionThresholds = sync.OnceValue(func() benchmark.RegressionThresholds {
+	t, err := benchmark.ParseRegressionThresholds(*maxRegression)
+	if err != nil {
+		log.Fatalf("--max-regression: %v", err)
+	}
+
+	return t
+})
+
+func loadBaseline(path string) (map[string]*benchmark.Results, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file: %w", err)
+	}
+
+	// Saved stdout starts with the report header; the results follow it.
+	if i := bytes.IndexByte(data, '{'); i > 0 {
+		data = data[i:]
+	}
+
+	results, err := benchmark.DecodeResults(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse baseline file, expected the -output json of a run: %w", err)
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no results in %s", path)
+	}
+
+	return results, nil
+}
+
+// compareWithBaseline adds the deltas against the -baseline run to the
+// results, ahead of reporting them.
+func compareWithBaseline(results map[string]*benchmark.Results) {
+	if baseline() == nil {
+		return
+	}
+
+	benchmark.CompareBaseline(baseline(), results, regressionThresholds())
+
+	for _, name := range slices.Sorted(maps.Keys(results)) {
+		if _, ok := baseline()[name]; !ok {
+			log.Printf("No baseline for %s, not compared", name)
+		}
+	}
+}
+
+// logRegressions logs every metric that regressed against the baseline and
+// reports whether there was any.
+func logRegressions(results map[string]*benchmark.Results) bool {
+	if baseline() == nil {
+		return false
+	}
+
+	var count int
+
+	for _, name := range slices.Sorted(maps.Keys(results)) {
+		for _, d := range results[name].Regressions() {
+			if count == 0 {
+				log.Printf("Regressions against %s:", *baselineFile)
+			}
+
+			count++
+			log.Printf("  REGRESSION %s %s: %+.1f%% (threshold %g%%)", name, d.Metric, d.ChangePct, d.Threshold)
+		}
+	}
+
+	if count == 0 {
+		log.Printf("No regressions against %s", *baselineFile)
+	}
+
+	return count > 0
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/orchestrator"
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+)
+
+const configUsage = `Usage:
+  benchmark config check [flags]
+
+Validates the flags, the -config file and the environment as a run would
+merge them, then connects to each -db database and checks that its user
+may create, write to and drop tables, without touching the events. With
+-existing-schema it also checks the columns of the events table.`
+
+// checkTimeout limits the connection and permission check of a database.
+const checkTimeout = 30 * time.Second
+
+// runConfigCommand runs the config subcommand.
+func runConfigCommand(args []string) {
+	if len(args) == 0 || args[0] != "check" {
+		log.Fatal(configUsage)
+	}
+
+	_ = flag.CommandLine.Parse(args[1:])
+	applyFlags()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	applySchemaFlags(cfg)
+
+	fmt.Printf("Configuration OK: events=%d batch=%d workers=%d preload=%d\n\n",
+		*eventCount, *batchSize, *workers, *preloadCount)
+
+	if failed := checkDatabases(cfg); failed > 0 {
+		fmt.Printf("\n%d of %d databases failed the check\n", failed, len(getDatabases(*dbType)))
+		os.Exit(1)
+	}
+}
+
+// checkDatabases checks each database of -db and returns how many failed.
+func checkDatabases(cfg *config.Config) int {
+	runner := newRunner()
+	failed := 0
+
+	for _, dbName := range getDatabases(*dbType) {
+		if !checkDatabase(context.Background(), cfg, runnerFor(runner, dbName), dbName) {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// checkDatabase connects to a database and checks its permissions,
+// printing the outcome and, on a failure, what to do about it.
+func checkDatabase(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string) bool {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	repo, err := openRepo(ctx, dbName, cfg)
+	if err != nil {
+		printCheckFailure(dbName, "connection", err)
+		return false
+	}
+
+	defer func() { _ = repo.Close() }()
+
+	status, ok := checkRepo(ctx, repo, dbName)
+	if !ok {
+		return false
+	}
+
+	fmt.Printf("%-16s ok      %s\n", dbName, status)
+
+	if conns := cfg.MaxConns(dbName); conns > 0 && max(runner.Workers, runner.QueryWorkers) > conns {
+		fmt.Printf("%-16s        %d workers share %d pooled connections; raise its max_open_conns\n",
+			"", max(runner.Workers, runner.QueryWorkers), conns)
+	}
+
+	return true
+}
+
+// checkRepo checks the permissions of an open database, and its schema with
+// -existing-schema. It returns what passed, or prints the failure and
+// returns false.
+func checkRepo(ctx context.Context, repo benchmark.Repository, dbName string) (string, bool) {
+	status := connectedTo(ctx, repo)
+
+	if pr, ok := repo.(benchmark.PermissionRepository); ok {
+		if err := pr.CheckPermissions(ctx); err != nil {
+			printCheckFailure(dbName, "permissions", err)
+			return "", false
+		}
+
+		status += ", can create, write to and drop tables"
+	} else {
+		status += ", permissions not checked"
+	}
+
+	if *existingSchema {
+		if err := verifyExistingSchema(ctx, repo, dbName); err != nil {
+			printCheckFailure(dbName, "schema", err)
+			return "", false
+		}
+
+		status += ", events table matches"
+	}
+
+	return status, true
+}
+
+// connectedTo describes the connection to repo, with the server version
+// where the database reports it.
+func connectedTo(ctx context.Context, repo benchmark.Repository) string {
+	if vr, ok := repo.(benchmark.VersionRepository); ok {
+		if v, err := vr.ServerVersion(ctx); err == nil && v != "" {
+			return "connected to " + v
+		}
+	}
+
+	return "connected"
+}
+
+func printCheckFailure(dbName, step string, err error) {
+	fmt.Printf("%-16s FAILED  %s: %v\n", dbName, step, err)
+
+	if hint := diagnose(dbName, err); hint != "" {
+		fmt.Printf("%-16s         → %s\n", "", hint)
+	}
+}
+
+// diagnose suggests a fix for the error of a database check, or returns ""
+// when the error does not tell.
+func diagnose(dbName string, err error) string {
+	msg := strings.ToLower(err.Error())
+	settings := fmt.Sprintf("the %s_* environment variables or the %s section of -config", strings.ToUpper(dbName), dbName)
+
+	if hint := accessHint(err, msg, settings); hint != "" {
+		return hint
+	}
+
+	switch {
+	case strings.Contains(msg, "unsupported database type"):
+		return "-db takes postgres, yugabytedb, mongodb, cassandra, scylladb, clickhouse, starrocks, doris, pinot, questdb, victoriametrics, " +
+			"redis, etcd, nats, kafka, sqlite, duckdb, badger, pebble or bbolt"
+	case strings.Contains(msg, "does not exist"), strings.Contains(msg, "unknown database"):
+		return "create the database, or name an existing one in " + settings
+	case strings.Contains(msg, "no such host"):
+		return "the host does not resolve; check it in " + settings
+	case errors.Is(err, context.DeadlineExceeded), strings.Contains(msg, "connection refused"),
+		strings.Contains(msg, "timeout"), strings.Contains(msg, "no connections were made"),
+		strings.Contains(msg, "server selection"), strings.Contains(msg, "no route to host"):
+		return unreachableHint(dbName, settings)
+	default:
+		return ""
+	}
+}
+
+// accessHint suggests a fix for an error about the events table, the
+// permissions or the credentials of the user, whose lowercase message is msg.
+func accessHint(err error, msg, settings string) string {
+	switch {
+	case errors.Is(err, repository.ErrTableMissing), errors.Is(err, repository.ErrColumnsMissing):
+		return "point the table setting in " + settings + " at the events table, or drop -existing-schema to have the benchmark create it"
+	case errors.Is(err, repository.ErrCreateDenied), errors.Is(err, repository.ErrWriteDenied),
+		errors.Is(err, repository.ErrDropDenied), strings.Contains(msg, "permission denied"),
+		strings.Contains(msg, "not authorized"), strings.Contains(msg, "unauthorized"):
+		return "grant the user CREATE, INSERT and DROP on the database, or point " + settings + " at one it owns"
+	case strings.Contains(msg, "authentication"), strings.Contains(msg, "password"), strings.Contains(msg, "auth failed"):
+		return "check the user and password in " + settings
+	default:
+		return ""
+	}
+}
+
+func unreachableHint(dbName, settings string) string {
+	svc, ok := orchestrator.ServiceByName(dbName)
+	if !ok || svc.Embedded() {
+		return "the server is not reachable; check the host and port in " + settings
+	}
+
+	return fmt.Sprintf("the server is not reachable: start it with `docker compose up -d %s`, "+
+		"or check the host and port in %s", svc.Service, settings)
+}
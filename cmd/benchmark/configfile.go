@@ -0,0 +1,142 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// cliFlags are the flags set on the command line, which take precedence
+// over the -config file.
+var cliFlags = make(map[string]bool)
+
+// configFile loads the -config file once, or returns nil without one.
+var configFile = sync.OnceValue(func() *config.File {
+	if *configPath == "" {
+		return nil
+	}
+
+	f, err := config.LoadFile(*configPath)
+	if err != nil {
+		log.Fatalf("--config: %v", err)
+	}
+
+	return f
+})
+
+// applyConfigFlags sets the flags of the -config file that the command line
+// leaves unset, ahead of validating them.
+func applyConfigFlags() {
+	flag.Visit(func(f *flag.Flag) {
+		cliFlags[f.Name] = true
+	})
+
+	f := configFile()
+	if f == nil {
+		return
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(f.Flags)) {
+		switch {
+		case name == "config":
+			log.Fatal("--config: flags cannot name another config file")
+		case flag.Lookup(name) == nil:
+			log.Fatalf("--config: unknown flag %q", name)
+		case cliFlags[name]:
+			continue
+		}
+
+		if err := flag.Set(name, flagValue(f.Flags[name])); err != nil {
+			log.Fatalf("--config: flag %s: %v", name, err)
+		}
+	}
+}
+
+// flagValue returns a YAML value as a flag value, a list comma-separated.
+func flagValue(v any) string {
+	list, ok := v.([]any)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+
+	values := make([]string, len(list))
+	for i, item := range list {
+		values[i] = fmt.Sprint(item)
+	}
+
+	return strings.Join(values, ",")
+}
+
+// loadConfig returns the connection settings: those of the -config file, or
+// the defaults, with the environment applied over them.
+func loadConfig() (*config.Config, error) {
+	if f := configFile(); f != nil {
+		return &f.Config, nil
+	}
+
+	return config.Load()
+}
+
+// configScenarios returns the custom query scenarios of the -config file.
+func configScenarios() []customQueryConfig {
+	f := configFile()
+	if f == nil {
+		return nil
+	}
+
+	scenarios := make([]customQueryConfig, len(f.Scenarios))
+	for i, s := range f.Scenarios {
+		scenarios[i] = customQueryConfig(s)
+	}
+
+	return scenarios
+}
+
+// runnerFor returns the runner of a database: runner with the overrides of
+// the -config file for it, other than of flags set on the command line.
+func runnerFor(runner *benchmark.Runner, dbName string) *benchmark.Runner {
+	f := configFile()
+	if f == nil {
+		return runner
+	}
+
+	o, ok := f.Overrides[dbName]
+	if !ok {
+		return runner
+	}
+
+	return overriddenRunner(o)
+}
+
+// overriddenRunner returns a runner with the overrides o, other than of flags
+// set on the command line.
+func overriddenRunner(o config.RunOverride) *benchmark.Runner {
+	events := override(*eventCount, o.Events, "events")
+	r := newRunnerWith(events, override(*batchSize, o.Batch, "batch"), override(*workers, o.Workers, "workers"))
+	r.QueryIterations = override(r.QueryIterations, o.Queries, "queries")
+	r.QueryWorkers = override(r.QueryWorkers, o.QueryWorkers, "query-workers")
+	r.WarmupIterations = override(r.WarmupIterations, o.Warmup, "warmup")
+	r.WarmupBatches = override(r.WarmupBatches, o.Warmup, "warmup")
+	r.PreloadCount = override(r.PreloadCount, o.Preload, "preload")
+	r.Rate = override(r.Rate, o.Rate, "rate")
+	r.QueryRate = override(r.QueryRate, o.QueryRate, "query-rate")
+
+	return r
+}
+
+// override returns value, or its override when it has one and the flag is
+// not set on the command line.
+func override[T int | float64](value, o T, name string) T {
+	if o == 0 || cliFlags[name] {
+		return value
+	}
+
+	return o
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// dataset returns the -dataset file, opened once, or nil without one.
+var dataset = sync.OnceValue(func() *generator.Dataset {
+	if *datasetFile == "" {
+		return nil
+	}
+
+	d, err := generator.OpenDataset(*datasetFile)
+	if err != nil {
+		log.Fatalf("--dataset: %v", err)
+	}
+
+	log.Printf("Replaying %d events spanning %v from %s", d.Len(), d.Span().Round(time.Second), *datasetFile)
+
+	return d
+})
+
+// applyDataset opens the -dataset file and makes its size the -events count
+// unless the command line, the -config file or the -preset sets one.
+func applyDataset() {
+	d := dataset()
+	if d == nil {
+		return
+	}
+
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	if !set["events"] {
+		_ = flag.Set("events", strconv.Itoa(d.Len()))
+	}
+}
+
+// validateDatasetFlags checks -dataset against the flags that shape
+// generated events, which a replayed file has already. -tenants takes the
+// tenants of the file, so it needs a file with a tenant_id column.
+func validateDatasetFlags() {
+	if *datasetFile == "" {
+		return
+	}
+
+	if *userDist != generator.DistUniform || *timeWindow != generator.DefaultDays ||
+		*timeDist != generator.TimeExponential || *latePct != 0 || *idFormat != generator.IDComposite ||
+		*tenantDist != generator.DistUniform {
+		log.Fatal("--dataset cannot be combined with --user-dist, --time-window, --time-dist, --late-pct, --id-format or --tenant-dist, which shape generated events")
+	}
+
+	if *tenantCount > 0 && !dataset().Tenants() {
+		log.Fatalf("--tenants requires a --dataset file with a tenant_id column, which %s lacks", *datasetFile)
+	}
+
+	if *parity {
+		log.Fatal("--dataset cannot be combined with --parity, which loads a generated dataset")
+	}
+}
+
+// eventSpan returns how far back from now the created_at of the events go:
+// the span of the -dataset file, or of the time distribution.
+func eventSpan() time.Duration {
+	if d := dataset(); d != nil {
+		return d.Span()
+	}
+
+	return timeDistribution().Span()
+}
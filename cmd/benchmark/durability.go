@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// runDurabilityMatrix runs the insert benchmark once per durability level of
+// the database, recreating the schema before each level so every run starts
+// from an empty table.
+func runDurabilityMatrix(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string) *benchmark.Results {
+	levels := config.DurabilityLevels(dbName)
+	if len(levels) == 0 {
+		return &benchmark.Results{Database: dbName, Error: fmt.Errorf("no durability levels defined for %s", dbName)}
+	}
+
+	res := &benchmark.Results{Database: dbName, Timestamp: time.Now(), Warmup: runner.Warmup()}
+
+	for _, level := range levels {
+		if ctx.Err() != nil {
+			break
+		}
+
+		levelCfg := *cfg
+		level.Apply(&levelCfg)
+
+		res.Durability = append(res.Durability, runDurabilityLevel(ctx, &levelCfg, runner, dbName, level.Name))
+	}
+
+	res.MarkDegraded()
+	res.Interrupted = ctx.Err() != nil
+
+	return res
+}
+
+func runDurabilityLevel(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName, level string) *benchmark.DurabilityResult {
+	dr := &benchmark.DurabilityResult{Level: level}
+
+	repo, err := newRepo(ctx, dbName, cfg)
+	if err != nil {
+		log.Printf("Failed to initialize %s (%s): %v", dbName, level, err)
+		dr.ErrorText = err.Error()
+
+		return dr
+	}
+
+	defer closeRepo(repo, dbName)
+
+	if err := repo.InitSchema(ctx); err != nil {
+		log.Printf("Failed to initialize %s schema (%s): %v", dbName, level, err)
+		dr.ErrorText = err.Error()
+
+		return dr
+	}
+
+	log.Printf("Benchmarking inserts for %s at %s (%d events)...", dbName, level, runner.EventCount)
+	dr.Insert = runner.RunInsert(ctx, repo)
+	log.Printf("Insert benchmark done for %s at %s: %.0f/sec", dbName, level, dr.Insert.Throughput)
+
+	return dr
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/orchestrator"
+)
+
+// driverModules are the Go modules each database is reached through; the
+// databases spoken to over plain HTTP have none.
+var driverModules = map[string]string{
+	"postgres":   "github.com/lib/pq",
+	"yugabytedb": "github.com/lib/pq",
+	"questdb":    "github.com/lib/pq",
+	"mongodb":    "go.mongodb.org/mongo-driver/v2",
+	"cassandra":  "github.com/gocql/gocql",
+	"scylladb":   "github.com/gocql/gocql",
+	"clickhouse": "github.com/ClickHouse/clickhouse-go/v2",
+	"starrocks":  "github.com/go-sql-driver/mysql",
+	"doris":      "github.com/go-sql-driver/mysql",
+	"redis":      "github.com/redis/go-redis/v9",
+	"etcd":       "go.etcd.io/etcd/client/v3",
+	"nats":       "github.com/nats-io/nats.go",
+	"kafka":      "github.com/twmb/franz-go",
+	"sqlite":     "modernc.org/sqlite",
+	"duckdb":     "github.com/marcboeker/go-duckdb/v2",
+	"badger":     "github.com/dgraph-io/badger/v4",
+	"pebble":     "github.com/cockroachdb/pebble",
+	"bbolt":      "go.etcd.io/bbolt",
+}
+
+// environments holds the environment of each database, described on its
+// first connection while its server, or container, is up.
+var environments = struct {
+	sync.Mutex
+	byDB map[string]*benchmark.Environment
+}{byDB: make(map[string]*benchmark.Environment)}
+
+// describeEnvironment records the environment of a database the first time
+// a repository connects to it: the host, its driver, the version its server
+// reports, the settings of the connection and the limits of its container.
+func describeEnvironment(ctx context.Context, dbName string, repo benchmark.Repository) {
+	environments.Lock()
+	_, described := environments.byDB[dbName]
+	environments.Unlock()
+
+	if described {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	env := benchmark.HostEnvironment()
+	env.Driver = driverVersion(driverModule(dbName, repo))
+	describeServer(ctx, dbName, repo, &env)
+
+	environments.Lock()
+	environments.byDB[dbName] = &env
+	environments.Unlock()
+}
+
+// describeServer records on env the version and settings the server of a
+// database reports and the limits of its container.
+func describeServer(ctx context.Context, dbName string, repo benchmark.Repository, env *benchmark.Environment) {
+	if vr, ok := repo.(benchmark.VersionRepository); ok {
+		v, err := vr.ServerVersion(ctx)
+		if err != nil {
+			log.Printf("Could not read the %s server version: %v", dbName, err)
+		}
+
+		env.ServerVersion = v
+	}
+
+	if sr, ok := repo.(benchmark.SettingsRepository); ok {
+		settings, err := sr.Settings(ctx)
+		if err != nil {
+			log.Printf("Could not read the %s settings: %v", dbName, err)
+		}
+
+		env.Settings = settings
+	}
+
+	// Without docker, or with the database outside the suite's containers,
+	// there are no limits to report.
+	if svc, ok := orchestrator.ServiceByName(dbName); ok && !svc.Embedded() {
+		if l, err := orchestrator.ContainerLimits(ctx, svc); err == nil {
+			env.Database = &benchmark.ContainerLimits{Image: l.Image, CPUs: l.CPUs, MemoryBytes: l.Memory}
+		}
+	}
+}
+
+// driverModule returns the driver module of a database: the one its
+// repository was configured with, or its only one.
+func driverModule(dbName string, repo benchmark.Repository) string {
+	if dr, ok := repo.(benchmark.DriverRepository); ok {
+		return dr.DriverModule()
+	}
+
+	return driverModules[dbName]
+}
+
+// driverVersion returns a driver module as module@version, or "" when there
+// is none or the binary has no build information.
+func driverVersion(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == path {
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+
+			return path + "@" + dep.Version
+		}
+	}
+
+	return ""
+}
+
+// stampEnvironment adds the environment to every result: that of its
+// database, or of the host alone when the database was never reached.
+func stampEnvironment(results map[string]*benchmark.Results) {
+	environments.Lock()
+	defer environments.Unlock()
+
+	for _, res := range results {
+		env, ok := environments.byDB[res.Database]
+		if !ok {
+			host := benchmark.HostEnvironment()
+			env = &host
+		}
+
+		res.Environment = env
+	}
+}
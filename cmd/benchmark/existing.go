@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// validateExistingSchemaFlags checks -existing-schema against the flags
+// that would recreate its tables or delete its events.
+func validateExistingSchemaFlags() {
+	if *forceCleanup && !*cleanupFlag {
+		log.Fatal("--force-cleanup requires --cleanup")
+	}
+
+	if !*existingSchema {
+		return
+	}
+
+	if *cleanupFlag && !*forceCleanup {
+		log.Fatal("--cleanup would delete the events of the existing schema; add --force-cleanup to delete them")
+	}
+
+	if *managed || *durability || *sweepWorkers != "" || *tuneBatch != "" || *parity || compareInsertModes() {
+		log.Fatal("--existing-schema cannot be combined with --managed, --durability-matrix, --sweep-workers, --tune-batch, --parity " +
+			"or several --insert-mode modes, which recreate the schema")
+	}
+
+	if *retentionDays > 0 || *ttlDays > 0 || *transactions > 0 || *userCount > 0 {
+		log.Fatal("--existing-schema cannot be combined with --retention-days, --ttl-days, --transactions or --users, which delete events or recreate tables")
+	}
+}
+
+// verifyExistingSchema checks the events table of a database that
+// -existing-schema benchmarks in place of recreating it.
+func verifyExistingSchema(ctx context.Context, repo benchmark.Repository, dbName string) error {
+	sr, ok := repo.(benchmark.SchemaRepository)
+	if !ok {
+		return fmt.Errorf("%s cannot verify an existing schema", dbName)
+	}
+
+	if err := sr.VerifySchema(ctx); err != nil {
+		return fmt.Errorf("existing schema: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/export"
+)
+
+// exportLabels parses the -export-labels once, so malformed labels fail the
+// run before any database is benchmarked.
+var exportLabels = sync.OnceValue(func() map[string]string {
+	labels, err := export.ParseLabels(*exportLabelSpec)
+	if err != nil {
+		log.Fatalf("--export-labels: %v", err)
+	}
+
+	return labels
+})
+
+// exportResults sends the final metrics of the run to the -pushgateway,
+// -remote-write and -influx-url endpoints. A failure is logged and leaves the run itself
+// alone, like one of -history.
+func exportResults(results map[string]*benchmark.Results) {
+	if *pushgateway == "" && *remoteWrite == "" && *influxURL == "" {
+		return
+	}
+
+	samples := export.Samples(results)
+	labels := exportLabels()
+
+	if *pushgateway != "" {
+		if err := export.Push(*pushgateway, samples, labels); err != nil {
+			log.Printf("Failed to export results: %v", err)
+		} else {
+			log.Printf("Pushed %d result metrics to %s", len(samples), *pushgateway)
+		}
+	}
+
+	if *remoteWrite != "" {
+		// The run context may be cancelled by now; partial results are sent too.
+		if err := export.RemoteWrite(context.Background(), *remoteWrite, samples, labels, time.Now()); err != nil {
+			log.Printf("Failed to export results: %v", err)
+		} else {
+			log.Printf("Wrote %d result metrics to %s", len(samples), *remoteWrite)
+		}
+	}
+
+	if *influxURL != "" {
+		// INFLUX_TOKEN stays out of the command line, and so out of the history.
+		err := export.Influx(context.Background(), *influxURL, os.Getenv("INFLUX_TOKEN"), results, labels, time.Now())
+		if err != nil {
+			log.Printf("Failed to export results: %v", err)
+		} else {
+			log.Printf("Wrote the results to %s", *influxURL)
+		}
+	}
+}
+
+// printDashboard prints the Grafana dashboard of the -influx-url results,
+// for the grafana-dashboard subcommand.
+func printDashboard() {
+	data, err := export.GrafanaDashboard()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, _ = os.Stdout.Write(data)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+const generateUsage = `Usage:
+  benchmark generate -out file [-events N] [-seed N] [-user-dist dist] [-time-window days]
+                     [-time-dist dist] [-late-pct N] [-max-lateness duration] [-id-format format]
+                     [-tenants N] [-tenant-dist dist]
+
+Writes a dataset of generated events to a .csv, .jsonl, .ndjson or .parquet
+file, for -dataset to replay. The flags other than -out and -seed shape the
+events as they do in a benchmark run.`
+
+// generateFlags are the flags of a benchmark run that the generate
+// subcommand takes too.
+var generateFlags = []string{
+	"events", "user-dist", "time-window", "time-dist", "late-pct", "max-lateness", "id-format", "tenants", "tenant-dist",
+}
+
+// generateBatch is the number of events generated and written at once.
+const generateBatch = 10000
+
+// runGenerate runs the generate subcommand: write a dataset file of
+// generated events.
+func runGenerate(args []string) {
+	out, seed := parseGenerateFlags(args)
+
+	start := time.Now()
+
+	n, err := generator.WriteDataset(out, generateEvents(seed, start).Generate(), *tenantCount > 0)
+	if err != nil {
+		log.Fatalf("Failed to write the dataset: %v", err)
+	}
+
+	log.Printf("Wrote %d events to %s in %v", n, out, time.Since(start).Round(time.Millisecond))
+}
+
+// parseGenerateFlags parses the flags of the generate subcommand and
+// returns its -out and -seed.
+func parseGenerateFlags(args []string) (string, int64) {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	out := fs.String("out", "", "Dataset file to write: .csv, .jsonl, .ndjson or .parquet")
+	seed := fs.Int64("seed", 1, "Seed of the events; the same seed and flags give the same events, their timestamps relative to now")
+
+	for _, name := range generateFlags {
+		f := flag.Lookup(name)
+		fs.Var(f.Value, name, f.Usage)
+	}
+
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), generateUsage)
+		fs.PrintDefaults()
+	}
+
+	_ = fs.Parse(args)
+
+	if *out == "" || fs.NArg() > 0 {
+		log.Fatal(generateUsage)
+	}
+
+	return *out, *seed
+}
+
+// generateEvents checks the flags that shape the events and returns the
+// generator of the dataset, its timestamps relative to now.
+func generateEvents(seed int64, now time.Time) *generator.Generator {
+	if *eventCount <= 0 {
+		log.Fatal("--events must be positive")
+	}
+
+	dist, err := generator.ParseUserDist(*userDist)
+	if err != nil {
+		log.Fatalf("--user-dist: %v", err)
+	}
+
+	validateTimeFlags()
+
+	if err := generator.CheckIDFormat(*idFormat); err != nil {
+		log.Fatalf("--id-format: %v", err)
+	}
+
+	validateTenantFlags()
+	tenants, _ := generator.ParseUserDist(*tenantDist)
+
+	return generator.NewSeeded(*eventCount, generateBatch, seed, now).
+		WithUserDist(dist).
+		WithTimeDist(timeDistribution()).
+		WithIDFormat(*idFormat).
+		WithTenants(*tenantCount, tenants)
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/history"
+	"github.com/skoredin/db-benchmark-suite/internal/reporter"
+)
+
+// defaultHistory is the history database the history subcommand reads when
+// it is not given one.
+const defaultHistory = "~/.dbbench/history.db"
+
+// runStart is when this run started, as stored in the history.
+var runStart = time.Now()
+
+const historyUsage = `Usage:
+  benchmark history list  [-history file] [-limit N]
+  benchmark history diff  [-history file] [-output format] [-max-regression list] <old run> <new run>
+  benchmark history trend [-history file] [-limit N] -db name [-metric name]`
+
+// recordHistory appends the run and its results to the -history database.
+// A failure is logged and leaves the run itself alone.
+func recordHistory(results map[string]*benchmark.Results) {
+	if *historyFile == "" {
+		return
+	}
+
+	// The run context may be cancelled by now; partial results are stored too.
+	ctx := context.Background()
+
+	store, err := history.Open(ctx, *historyFile)
+	if err != nil {
+		log.Printf("Failed to open history: %v", err)
+		return
+	}
+
+	defer func() { _ = store.Close() }()
+
+	id, err := store.Append(ctx, history.NewRun(runStart, os.Args[1:]), results)
+	if err != nil {
+		log.Printf("Failed to record history: %v", err)
+		return
+	}
+
+	log.Printf("Run recorded in %s as #%d", *historyFile, id)
+}
+
+// runHistory runs the history subcommand: list the stored runs, diff two of
+// them, or show the trend of a metric across them.
+func runHistory(args []string) {
+	if len(args) == 0 {
+		log.Fatal(historyUsage)
+	}
+
+	opts, rest := parseHistoryFlags(args)
+
+	store, err := history.Open(context.Background(), opts.path)
+	if err != nil {
+		log.Fatalf("Failed to open history: %v", err)
+	}
+
+	defer func() { _ = store.Close() }()
+
+	if err := runHistoryCommand(store, args[0], rest, opts); err != nil {
+		_ = store.Close()
+
+		log.Fatal(err)
+	}
+}
+
+// historyOptions are the flags of the history subcommand.
+type historyOptions struct {
+	path, db, metric, format, thresholds string
+	limit                                int
+}
+
+// parseHistoryFlags parses the flags of the history command args[0] and
+// returns them with the remaining arguments.
+func parseHistoryFlags(args []string) (historyOptions, []string) {
+	var o historyOptions
+
+	fs := flag.NewFlagSet("history "+args[0], flag.ExitOnError)
+	fs.StringVar(&o.path, "history", defaultHistory, "History database")
+	fs.IntVar(&o.limit, "limit", 20, "Number of most recent runs to show")
+	fs.StringVar(&o.db, "db", "", "Database, or matrix cell, whose metric to trend")
+	fs.StringVar(&o.metric, "metric", "insert throughput", "Metric to trend: insert throughput, insert P95, <scenario> P95 or storage size")
+	fs.StringVar(&o.format, "output", "table", "Output format of diff: table, json, markdown")
+	fs.StringVar(&o.thresholds, "max-regression", "10", "Regression threshold of diff in %, overall or per kind")
+
+	_ = fs.Parse(args[1:])
+
+	return o, fs.Args()
+}
+
+// runHistoryCommand runs a history command on the store.
+func runHistoryCommand(store *history.Store, command string, args []string, o historyOptions) error {
+	switch command {
+	case "list":
+		return listRuns(store, o.limit)
+	case "diff":
+		return diffRuns(store, args, o.format, o.thresholds)
+	case "trend":
+		return trendMetric(store, o.db, o.metric, o.limit)
+	default:
+		return fmt.Errorf("unknown history command %q\n%s", command, historyUsage)
+	}
+}
+
+func listRuns(store *history.Store, limit int) error {
+	runs, err := store.Runs(context.Background(), limit)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "RUN\tSTARTED\tDURATION\tHOST\tREVISION\tRESULTS\tARGS")
+
+	for _, run := range runs {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			run.ID, run.StartedAt.Format(time.DateTime), run.FinishedAt.Sub(run.StartedAt).Round(time.Second), run.Host,
+			benchmark.ShortRevision(run.Revision), strings.Join(run.Names, ", "), strings.Join(run.Args, " "))
+	}
+
+	return w.Flush()
+}
+
+// diffRuns compares the results of the second run with those of the first,
+// as -baseline compares a run with a saved one.
+func diffRuns(store *history.Store, ids []string, format, thresholds string) error {
+	if len(ids) != 2 {
+		return fmt.Errorf("diff takes two run ids\n%s", historyUsage)
+	}
+
+	t, err := benchmark.ParseRegressionThresholds(thresholds)
+	if err != nil {
+		return fmt.Errorf("--max-regression: %w", err)
+	}
+
+	var runs [2]map[string]*benchmark.Results
+
+	for i, s := range ids {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid run id %q", s)
+		}
+
+		if runs[i], err = store.Results(context.Background(), id); err != nil {
+			return err
+		}
+	}
+
+	benchmark.CompareBaseline(runs[0], runs[1], t)
+	reporter.New(format, os.Stdout).PrintComparison(runs[1])
+
+	return nil
+}
+
+// trendMetric prints the metric of db across the stored runs, oldest first,
+// with the change from each run to the next.
+func trendMetric(store *history.Store, db, metric string, limit int) error {
+	if db == "" {
+		return fmt.Errorf("trend needs -db\n%s", historyUsage)
+	}
+
+	points, kind, err := store.Trend(context.Background(), db, metric, limit)
+	if err != nil {
+		return err
+	}
+
+	if len(points) == 0 {
+		return fmt.Errorf("no run of %s has the metric %q", db, metric)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "RUN\tSTARTED\t%s\tCHANGE\n", strings.ToUpper(metric))
+
+	for i, p := range points {
+		change := "-"
+		if i > 0 && points[i-1].Value > 0 {
+			change = fmt.Sprintf("%+.1f%%", (p.Value-points[i-1].Value)/points[i-1].Value*100)
+		}
+
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", p.RunID, p.StartedAt.Format(time.DateTime), reporter.FormatMetric(kind, p.Value), change)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// validateDeferIndexesFlags checks -defer-indexes against the flags that
+// keep the schema or replace the regular benchmark.
+func validateDeferIndexesFlags() {
+	if !*deferIndexes {
+		return
+	}
+
+	if *existingSchema || *preloadCkpt != "" {
+		log.Fatal("--defer-indexes cannot be combined with --existing-schema or --preload-checkpoint, which keep the schema")
+	}
+
+	if *durability || *sweepWorkers != "" || *parity || compareInsertModes() {
+		log.Fatal("--defer-indexes cannot be combined with --durability-matrix, --sweep-workers, --parity or several --insert-mode modes")
+	}
+}
+
+// loadWithIndexes runs the insert benchmark of -defer-indexes on a schema
+// with its secondary indexes, preloaded like the deferred one so both loads
+// start from the same table size, the reference the deferred load is
+// compared with; then it defers the indexes of the schema created next. It
+// returns nil for databases that create their indexes with the table, and
+// with -skip-insert.
+func loadWithIndexes(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string) *benchmark.InsertResult {
+	ib, ok := repo.(benchmark.IndexBuildRepository)
+	if !*deferIndexes || !ok {
+		return nil
+	}
+
+	defer ib.DeferIndexes(true)
+
+	if *skipInsert {
+		return nil
+	}
+
+	ib.DeferIndexes(false)
+
+	return runIndexedLoad(ctx, runner, repo, dbName)
+}
+
+// runIndexedLoad runs the insert benchmark on a new schema with its indexes,
+// preloaded like the deferred one.
+func runIndexedLoad(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string) *benchmark.InsertResult {
+	if err := repo.InitSchema(ctx); err != nil {
+		log.Printf("Failed to initialize %s schema for the indexed load: %v", dbName, err)
+		return nil
+	}
+
+	if err := preloadIfNeeded(ctx, runner, repo, dbName); err != nil {
+		return nil
+	}
+
+	log.Printf("Benchmarking inserts for %s with its indexes in place (%d events)...", dbName, runner.EventCount)
+
+	indexed := runner.RunInsert(ctx, repo)
+
+	log.Printf("Indexed insert benchmark done for %s: %.0f/sec", dbName, indexed.Throughput)
+
+	return indexed
+}
+
+// buildIndexes builds the indexes -defer-indexes left out of the schema
+// once the events are loaded, before the queries that use them.
+func buildIndexes(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string) *benchmark.IndexBuildResult {
+	if _, ok := repo.(benchmark.IndexBuildRepository); !ok {
+		log.Printf("%s creates its indexes with the table; they were not deferred", dbName)
+		return &benchmark.IndexBuildResult{ErrorText: "not supported"}
+	}
+
+	log.Printf("Building the deferred %s indexes...", dbName)
+
+	res := runner.RunIndexBuild(ctx, repo)
+	if res.ErrorText != "" {
+		log.Printf("Index build failed for %s: %s", dbName, res.ErrorText)
+		return res
+	}
+
+	log.Printf("Index build done for %s: %d events in %v", dbName, res.Rows, res.Duration)
+
+	return res
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// allInsertModes stands for every insert mode of each database in
+// -insert-mode.
+const allInsertModes = "all"
+
+// parseInsertModes splits the comma-separated -insert-mode list.
+func parseInsertModes(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var modes []string
+
+	for field := range strings.SplitSeq(s, ",") {
+		mode := strings.TrimSpace(field)
+		if mode == "" {
+			return nil, fmt.Errorf("empty insert mode in %q", s)
+		}
+
+		if slices.Contains(modes, mode) {
+			return nil, fmt.Errorf("insert mode %s is listed twice", mode)
+		}
+
+		modes = append(modes, mode)
+	}
+
+	if len(modes) > 1 && slices.Contains(modes, allInsertModes) {
+		return nil, fmt.Errorf("%s cannot be combined with other insert modes", allInsertModes)
+	}
+
+	return modes, nil
+}
+
+// compareInsertModes reports whether -insert-mode lists several modes, or
+// all of them, to compare rather than one to run the benchmark in.
+func compareInsertModes() bool {
+	modes, _ := parseInsertModes(*insertMode)
+
+	return len(modes) > 1 || slices.Equal(modes, []string{allInsertModes})
+}
+
+func validateInsertModeFlags() {
+	if _, err := parseInsertModes(*insertMode); err != nil {
+		log.Fatalf("--insert-mode: %v", err)
+	}
+
+	if !compareInsertModes() {
+		return
+	}
+
+	if *durability || *sweepWorkers != "" || *tuneBatch != "" || *repeat > 1 || *parity || *matrixFile != "" {
+		log.Fatal("comparing several --insert-mode modes cannot be combined with --durability-matrix, --sweep-workers, --tune-batch, --repeat, --parity or --matrix")
+	}
+}
+
+// applyInsertMode switches a database just opened to the single mode of
+// -insert-mode; a comparison switches modes itself.
+func applyInsertMode(repo benchmark.Repository, dbName string) error {
+	if *insertMode == "" || compareInsertModes() {
+		return nil
+	}
+
+	ir, ok := repo.(benchmark.InsertModeRepository)
+	if !ok {
+		return fmt.Errorf("%s has a single insert mode", dbName)
+	}
+
+	if err := ir.SetInsertMode(*insertMode); err != nil {
+		return fmt.Errorf("%s insert mode %s: %w (it has %s)", dbName, *insertMode, err, strings.Join(ir.InsertModes(), ", "))
+	}
+
+	return nil
+}
+
+// insertModesOf returns the modes of -insert-mode the database has, in the
+// order listed, or all of them.
+func insertModesOf(ir benchmark.InsertModeRepository) []string {
+	modes, _ := parseInsertModes(*insertMode)
+	if slices.Equal(modes, []string{allInsertModes}) {
+		return ir.InsertModes()
+	}
+
+	return slices.DeleteFunc(modes, func(mode string) bool { return !slices.Contains(ir.InsertModes(), mode) })
+}
+
+// runInsertModeComparison runs the insert benchmark of a database once in
+// each of its modes of -insert-mode, on the same connection, recreating the
+// schema before each mode so every run starts from an empty table.
+func runInsertModeComparison(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string) *benchmark.Results {
+	repo, err := newRepo(ctx, dbName, cfg)
+	if err != nil {
+		log.Printf("Failed to initialize %s: %v", dbName, err)
+		return &benchmark.Results{Database: dbName, Error: err}
+	}
+
+	defer closeRepo(repo, dbName)
+
+	ir, modes, err := comparedInsertModes(repo, dbName)
+	if err != nil {
+		return &benchmark.Results{Database: dbName, Error: err}
+	}
+
+	res := &benchmark.Results{Database: dbName, Timestamp: time.Now(), Warmup: runner.Warmup()}
+	res.InsertModes = runInsertModes(ctx, runner, repo, ir, dbName, modes)
+	res.MarkDegraded()
+	res.Interrupted = ctx.Err() != nil
+
+	return res
+}
+
+// comparedInsertModes returns the insert modes of -insert-mode that repo has.
+func comparedInsertModes(repo benchmark.Repository, dbName string) (benchmark.InsertModeRepository, []string, error) {
+	ir, ok := repo.(benchmark.InsertModeRepository)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s has a single insert mode", dbName)
+	}
+
+	modes := insertModesOf(ir)
+	if len(modes) == 0 {
+		return nil, nil, fmt.Errorf("%s has none of the insert modes %s (it has %s)",
+			dbName, *insertMode, strings.Join(ir.InsertModes(), ", "))
+	}
+
+	return ir, modes, nil
+}
+
+// runInsertModes runs the insert benchmark in each of modes in turn until ctx
+// is done.
+func runInsertModes(
+	ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, ir benchmark.InsertModeRepository, dbName string, modes []string,
+) []*benchmark.InsertModeResult {
+	var results []*benchmark.InsertModeResult
+
+	for _, mode := range modes {
+		if ctx.Err() != nil {
+			break
+		}
+
+		results = append(results, runInsertMode(ctx, runner, repo, ir, dbName, mode))
+	}
+
+	return results
+}
+
+func runInsertMode(
+	ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, ir benchmark.InsertModeRepository, dbName, mode string,
+) *benchmark.InsertModeResult {
+	mr := &benchmark.InsertModeResult{Mode: mode}
+
+	if err := ir.SetInsertMode(mode); err != nil {
+		mr.ErrorText = err.Error()
+		return mr
+	}
+
+	if err := repo.InitSchema(ctx); err != nil {
+		log.Printf("Failed to initialize %s schema (%s): %v", dbName, mode, err)
+		mr.ErrorText = err.Error()
+
+		return mr
+	}
+
+	log.Printf("Benchmarking inserts for %s in the %s insert mode (%d events)...", dbName, mode, runner.EventCount)
+	mr.Insert = runner.RunInsert(ctx, repo)
+	log.Printf("Insert benchmark done for %s in the %s insert mode: %.0f/sec", dbName, mode, mr.Insert.Throughput)
+
+	return mr
+}
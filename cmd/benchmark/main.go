@@ -5,36 +5,122 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
 	"github.com/skoredin/db-benchmark-suite/internal/config"
-	"github.com/skoredin/db-benchmark-suite/internal/reporter"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
 	"github.com/skoredin/db-benchmark-suite/internal/repository"
 )
 
 var (
-	dbType          = flag.String("db", "all", "Database type: postgres, mongodb, cassandra, clickhouse, all")
+	configPath = flag.String("config", "", "YAML file of connection settings, flags, query scenarios and per-database overrides; flags "+
+		"and env vars take precedence")
+	preset = flag.String("preset", "", "Workload preset setting -events, -batch, -preload, -workers, -queries and -query-workers: "+
+		"small, medium, large or stress; flags set explicitly take precedence")
+	dbType = flag.String("db", "all", "Database type: postgres, yugabytedb, mongodb, cassandra, scylladb, clickhouse, starrocks, "+
+		"doris, pinot, questdb, victoriametrics, redis, etcd, nats, kafka, sqlite, duckdb, badger, pebble, bbolt, all (comma-separated list allowed)")
 	eventCount      = flag.Int("events", 1000000, "Number of events to generate")
 	batchSize       = flag.Int("batch", 10000, "Batch size for inserts")
 	workers         = flag.Int("workers", runtime.NumCPU(), "Number of concurrent workers")
+	rate            = flag.Float64("rate", 0, "Target insert rate in events/sec, reporting batch latency at that rate (0 = max speed)")
+	steadyState     = flag.Float64("steady-state", 0, "Also report insert throughput from when the per-second rate holds within this % for 5s (0 = skip)")
+	rampUp          = flag.Duration("ramp-up", 0, "Ramp the insert workers, or the -rate schedule, up over this period and leave it out of the stats")
 	queryIterations = flag.Int("queries", 100, "Number of query iterations")
-	outputFormat    = flag.String("output", "table", "Output format: table, json, markdown")
-	skipInsert      = flag.Bool("skip-insert", false, "Skip insert benchmark")
-	skipQuery       = flag.Bool("skip-query", false, "Skip query benchmark")
-	preloadCount    = flag.Int("preload", 0, "Pre-load database with N events before benchmarking (0 = skip)")
-	cleanupFlag     = flag.Bool("cleanup", false, "Cleanup data after benchmark")
-	managed         = flag.Bool("managed", false, "Manage Docker containers automatically (start/stop per database)")
+	queryFile       = flag.String("custom-queries", "", "JSON file of additional query scenarios: name, window and a method or per-database statements")
+	scenarios       = flag.String("scenarios", "", "Comma-separated query scenarios to run, e.g. 1_hour,1_month,point_lookup (default all)")
+	skipScenarios   = flag.String("skip-scenarios", "", "Comma-separated query scenarios not to run")
+	queryWorkers    = flag.Int("query-workers", 1, "Run the iterations of each query scenario on N concurrent workers and report their QPS")
+	warmup          = flag.Int("warmup", 5, "Unmeasured warm-up iterations: N runs of each query scenario and N batches before each insert run")
+	maxErrorRate    = flag.Float64("max-error-rate", 0, "Abort an insert run or query scenario once more than this % of its batches or queries fail (0 = never)")
+	opTimeout       = flag.Duration("op-timeout", 0, "Timeout of each insert batch or query attempt (0 = none)")
+	retries         = flag.Int("retries", 0, "Retry a failed insert batch or query up to N times before counting it as an error")
+	retryBackoff    = flag.Duration("retry-backoff", 100*time.Millisecond, "Wait before the first retry, doubled for each next one")
+	queryRate       = flag.Float64("query-rate", 0, "Target rate of each query scenario in queries/sec, adding corrected percentiles (0 = back to back)")
+	outputFormat    = flag.String("output", "table", "Formats table, json, markdown, csv or template, each with an optional :path to write to, "+
+		"e.g. table,json:out.json")
+	templateFile    = flag.String("template", "", "Go template file of the template output, executed with the results; html/template for .html files")
+	outputFile      = flag.String("output-file", "", "Also write the results to this file: markdown for .md, CSV for .csv, a table for .txt, JSON otherwise")
+	baselineFile    = flag.String("baseline", "", "JSON output of a previous run; report the change of each metric and exit with status 1 on regressions")
+	maxRegression   = flag.String("max-regression", "10", "Regression threshold of -baseline in %, overall or per kind, e.g. 10,latency=20,storage=5")
+	metricsAddr     = flag.String("metrics-addr", "", "Serve live Prometheus metrics of the inserts and queries at /metrics on this address, e.g. :9100")
+	pushgateway     = flag.String("pushgateway", "", "Push the final metrics of the run to this Prometheus Pushgateway URL, e.g. http://localhost:9091")
+	remoteWrite     = flag.String("remote-write", "", "Send the final metrics of the run to this Prometheus remote-write URL, e.g. http://prom:9090/api/v1/write")
+	influxURL       = flag.String("influx-url", "", "Write the results to this InfluxDB or VictoriaMetrics line protocol URL, e.g. http://localhost:8428/write")
+	exportLabelSpec = flag.String("export-labels", "", "Comma-separated name=value labels of the run for -pushgateway, -remote-write and -influx-url, e.g. env=ci")
+	notifyURL       = flag.String("notify", "", "Post a summary of the run, and its regressions against -baseline, to this webhook or Slack "+
+		"incoming webhook URL when it completes or fails")
+	otlpEndpoint = flag.String("otlp-endpoint", "", "Send OpenTelemetry traces and metrics of each batch and query to this OTLP URL, e.g. http://otel:4318")
+	historyFile  = flag.String("history", "", "SQLite file to append the run and its results to, e.g. "+defaultHistory+"; see the history subcommand")
+	sloFile      = flag.String("slo", "", "JSON file of SLOs such as \"postgres 1_day p95 < 200ms\"; exit with status 1 when the run violates any")
+	showProgress = flag.Bool("progress", false, "Draw a progress bar with rate, errors and ETA per insert run in place of progress logs when stdout is a TTY")
+	relativeTo   = flag.String("relative-to", "", "Compare each database with this one in table and markdown output, e.g. \"2.4× faster\", \"+38% storage\"")
+	scoreWeights = flag.String("score-weights", "", "Add an overall score to the summary, weighting category scores, e.g. ingest=2,queries=1,storage=0.5")
+	verbose      = flag.Bool("verbose", false, "Add the per-worker insert breakdown and client resources to the table and markdown output, "+
+		"and latency histograms to the table")
+	skipInsert     = flag.Bool("skip-insert", false, "Skip insert benchmark")
+	skipQuery      = flag.Bool("skip-query", false, "Skip query benchmark")
+	verify         = flag.Bool("verify", false, "Count the rows each insert run added and report any mismatch with the events it inserted")
+	parity         = flag.Bool("parity", false, "Load the same seeded -events dataset into every database and compare their event stats counts with it")
+	preloadCount   = flag.Int("preload", 0, "Pre-load database with N events before benchmarking (0 = skip)")
+	preloadCkpt    = flag.String("preload-checkpoint", "", "Directory of per-database preload checkpoints; resume an interrupted -preload from them")
+	cleanupFlag    = flag.Bool("cleanup", false, "Cleanup data after benchmark")
+	existingSchema = flag.Bool("existing-schema", false, "Benchmark the events already in each database: verify its events table instead of "+
+		"dropping and recreating it")
+	forceCleanup = flag.Bool("force-cleanup", false, "Let --cleanup delete the events of an --existing-schema")
+	managed      = flag.Bool("managed", false, "Manage Docker containers automatically (start/stop per database)")
+	deferIndexes = flag.Bool("defer-indexes", false, "Load the events without their secondary indexes, then build them and report the load "+
+		"speedup and the build time")
+	durability = flag.Bool("durability-matrix", false, "Run the insert benchmark at each durability level and report a throughput matrix")
+	insertMode = flag.String("insert-mode", "", "Insert mode of the databases that have several, e.g. values; a comma-separated list, or "+
+		"all, runs the insert benchmark in each and compares them")
+	matrixFile   = flag.String("matrix", "", "JSON file of databases, workers, batch_sizes and events lists; run every combination and compare them")
+	repeat       = flag.Int("repeat", 1, "Run the benchmark of each database N times and report the run-to-run variance")
+	tuneBatch    = flag.String("tune-batch", "", "Hill-climb the insert batch size of each database within min-max, e.g. 100-100000, and run at the best")
+	sweepWorkers = flag.String("sweep-workers", "", "Rerun the insert benchmark at each worker count of a comma-separated list, e.g. 1,2,4,8,16")
+	duplicatePct = flag.Int("duplicate-pct", 0, "Percentage of inserted events that reuse an already inserted event_id (0-100)")
+	userDist     = flag.String("user-dist", generator.DistUniform, "Distribution of the user_id of the events: uniform, or zipfian:S with a "+
+		"skew S > 1 for hot users, e.g. zipfian:1.1")
+	timeWindow = flag.Int("time-window", generator.DefaultDays, "Days back from now the created_at of the events spans")
+	timeDist   = flag.String("time-dist", generator.TimeExponential, "Distribution of the created_at of the events over -time-window: "+
+		"exponential (biased to recent days), uniform, or ordered (ascending, as a live stream)")
+	latePct     = flag.Int("late-pct", 0, "Percentage of -time-dist ordered events that arrive late, up to -max-lateness behind newer events (0-100)")
+	maxLateness = flag.Duration("max-lateness", time.Hour, "How far behind its place in the stream a late event of -late-pct can be")
+	idFormat    = flag.String("id-format", generator.IDComposite, "Format of the event_id of the events: composite "+
+		"(evt_<created_at>_<random>), uuidv4, uuidv7, ulid or snowflake; time-ordered IDs append to a B-tree index, random ones land all over it")
+	tenantCount = flag.Int("tenants", 0, "Spread the events over N tenants in a tenant_id column, clustering the PostgreSQL and ClickHouse "+
+		"schemas by it, and run the tenant-scoped query scenarios; with -dataset, take the tenant_id of the file (0 = no tenants)")
+	tenantDist = flag.String("tenant-dist", generator.DistUniform, "Distribution of the events over the -tenants tenants: uniform, or "+
+		"zipfian:S with a skew S > 1 for a few large tenants")
+	datasetFile = flag.String("dataset", "", "Replay the events of this CSV, JSONL or Parquet file, shifted in time to end now, in place "+
+		"of generated ones; -events defaults to its size")
+	retentionDays  = flag.Int("retention-days", 0, "Delete events older than N days after the query benchmark and measure storage reclaim (0 = skip)")
+	ttlDays        = flag.Int("ttl-days", 0, "Expire events older than N days natively, insert another -events events and measure expiry (0 = skip)")
+	mixed          = flag.Bool("mixed", false, "Run queries continuously while inserting another -events events and report latency under ingest")
+	transactions   = flag.Int("transactions", 0, "Run N transactions that each write an event and increment its user's counter row (0 = skip)")
+	readAfterWrite = flag.Int("read-after-write", 0, "Insert N more batches and time until one event of each is readable after its insert (0 = skip)")
+	coldCache      = flag.Bool("cold-cache", false, "Restart each container after the benchmark and compare cold and warm query latency (requires -managed)")
+	indexes        = flag.String("indexes", config.IndexesFull, "Secondary index set created by the schema: none, minimal or full")
+	userCount      = flag.Int("users", 0, "Load a users dimension table with N users and run the join scenario (0 = skip, max 1000000)")
 )
 
 func main() {
+	if runSubcommand(os.Args[1:]) {
+		return
+	}
+
 	flag.Parse()
-	validateFlags()
+	applyFlags()
+	startMetricsServer()
+	startTelemetry()
 
 	if *managed {
 		runManaged()
@@ -44,7 +130,54 @@ func main() {
 	runDirect()
 }
 
+// applyFlags applies the -config file, -preset and -dataset to the parsed
+// flags and validates them.
+func applyFlags() {
+	applyConfigFlags()
+	applyPreset()
+	applyDataset()
+	validateFlags()
+}
+
+// runSubcommand runs the subcommand args name, if they name one, and reports
+// whether they did.
+func runSubcommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+
+	switch args[0] {
+	case "history":
+		runHistory(args[1:])
+	case "config":
+		runConfigCommand(args[1:])
+	case "generate":
+		runGenerate(args[1:])
+	case "grafana-dashboard":
+		printDashboard()
+	default:
+		return false
+	}
+
+	return true
+}
+
 func validateFlags() {
+	validateCountFlags()
+	validateLoadFlags()
+	validateWorkloadFlags()
+	validateExistingSchemaFlags()
+	outputs()
+	validateModeFlags()
+	slos()
+	baseline()
+	regressionThresholds()
+	exportLabels()
+	validateNotify()
+}
+
+// validateCountFlags checks the counts of events, workers, queries and runs.
+func validateCountFlags() {
 	if *eventCount <= 0 {
 		log.Fatal("--events must be positive")
 	}
@@ -60,29 +193,286 @@ func validateFlags() {
 	if *queryIterations <= 0 {
 		log.Fatal("--queries must be positive")
 	}
+
+	if *queryWorkers <= 0 {
+		log.Fatal("--query-workers must be positive")
+	}
+
+	if *repeat <= 0 {
+		log.Fatal("--repeat must be positive")
+	}
 }
 
-func runDirect() {
-	cfg, err := config.Load()
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+// validateLoadFlags checks the flags that shape how the load is applied.
+func validateLoadFlags() {
+	if *maxErrorRate < 0 || *maxErrorRate > 100 {
+		log.Fatal("--max-error-rate must be between 0 and 100")
+	}
+
+	if *opTimeout < 0 || *retries < 0 || *retryBackoff < 0 {
+		log.Fatal("--op-timeout, --retries and --retry-backoff must not be negative")
+	}
+
+	if *warmup < 0 {
+		log.Fatal("--warmup must not be negative")
+	}
+
+	if *rate < 0 || *queryRate < 0 || *rampUp < 0 {
+		log.Fatal("--rate, --query-rate and --ramp-up must not be negative")
+	}
+
+	if *steadyState < 0 || *steadyState >= 100 {
+		log.Fatal("--steady-state must be between 0 and 100")
+	}
+
+	if *preloadCkpt != "" && *preloadCount <= 0 {
+		log.Fatal("--preload-checkpoint requires --preload")
+	}
+}
+
+// validateModeFlags checks the flags that replace the regular benchmark.
+func validateModeFlags() {
+	if _, err := parseWorkerCounts(*sweepWorkers); err != nil {
+		log.Fatalf("--sweep-workers: %v", err)
+	}
+
+	if *sweepWorkers != "" && *durability {
+		log.Fatal("--sweep-workers and --durability-matrix cannot be combined")
+	}
+
+	if *repeat > 1 && (*sweepWorkers != "" || *durability) {
+		log.Fatal("--repeat cannot be combined with --sweep-workers or --durability-matrix")
+	}
+
+	if *parity && (*repeat > 1 || *sweepWorkers != "" || *durability || *matrixFile != "") {
+		log.Fatal("--parity cannot be combined with --repeat, --sweep-workers, --durability-matrix or --matrix")
+	}
+
+	validateTuneFlags()
+	validateInsertModeFlags()
+	validateDeferIndexesFlags()
+	validateMatrixFlags()
+	validateRelativeTo()
+
+	if _, err := parseScoreWeights(); err != nil {
+		log.Fatalf("--score-weights: %v", err)
+	}
+}
+
+// validateTimeFlags checks the flags that shape the created_at of the
+// events.
+func validateTimeFlags() {
+	if *timeWindow <= 0 {
+		log.Fatal("--time-window must be positive")
+	}
+
+	if err := generator.CheckTimeShape(*timeDist); err != nil {
+		log.Fatalf("--time-dist: %v", err)
+	}
+
+	if *latePct < 0 || *latePct > 100 {
+		log.Fatal("--late-pct must be between 0 and 100")
+	}
+
+	if *latePct > 0 && *timeDist != generator.TimeOrdered {
+		log.Fatal("--late-pct requires --time-dist ordered; the other distributions arrive in no order already")
+	}
+
+	if *maxLateness <= 0 {
+		log.Fatal("--max-lateness must be positive")
+	}
+}
+
+// timeDistribution returns the distribution of the created_at of the events
+// set by -time-window, -time-dist, -late-pct and -max-lateness.
+func timeDistribution() generator.TimeDist {
+	return generator.TimeDist{Shape: *timeDist, Days: *timeWindow, LatePct: *latePct, MaxLate: *maxLateness}
+}
+
+// validateTenantFlags checks the flags that spread the events over tenants.
+func validateTenantFlags() {
+	if *tenantCount < 0 {
+		log.Fatal("--tenants must not be negative")
+	}
+
+	if _, err := generator.ParseUserDist(*tenantDist); err != nil {
+		log.Fatalf("--tenant-dist: %v", err)
+	}
+
+	if *tenantDist != generator.DistUniform && *tenantCount == 0 {
+		log.Fatal("--tenant-dist requires --tenants")
+	}
+}
+
+// validateWorkloadFlags checks the flags of the optional workloads.
+func validateWorkloadFlags() {
+	validateEventFlags()
+
+	if *retentionDays < 0 {
+		log.Fatal("--retention-days must not be negative")
+	}
+
+	if *ttlDays < 0 {
+		log.Fatal("--ttl-days must not be negative")
+	}
+
+	if *transactions < 0 || *readAfterWrite < 0 {
+		log.Fatal("--transactions and --read-after-write must not be negative")
+	}
+
+	if *coldCache && !*managed {
+		log.Fatal("--cold-cache requires --managed to restart the containers")
+	}
+
+	if !config.ValidIndexSet(*indexes) {
+		log.Fatal("--indexes must be none, minimal or full")
+	}
+
+	if *userCount < 0 || *userCount > generator.UserCount {
+		log.Fatalf("--users must be between 0 and %d", generator.UserCount)
+	}
+}
+
+// validateEventFlags checks the flags that shape the inserted events.
+func validateEventFlags() {
+	if *duplicatePct < 0 || *duplicatePct > 100 {
+		log.Fatal("--duplicate-pct must be between 0 and 100")
+	}
+
+	if _, err := generator.ParseUserDist(*userDist); err != nil {
+		log.Fatalf("--user-dist: %v", err)
 	}
 
-	rep := reporter.New(*outputFormat, os.Stdout)
-	rep.PrintHeader()
+	validateTimeFlags()
+
+	if err := generator.CheckIDFormat(*idFormat); err != nil {
+		log.Fatalf("--id-format: %v", err)
+	}
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	validateTenantFlags()
+	validateDatasetFlags()
+}
+
+func runDirect() {
+	cfg := loadRunConfig()
+
+	printHeader(os.Stdout)
+
+	ctx, stop := signalContext()
 	defer stop()
 
-	databases := getDatabases(*dbType)
-	runner := newRunner()
+	databases, results := runDatabases(ctx, cfg, getDatabases(*dbType))
 
-	results := runAllBenchmarks(ctx, cfg, runner, databases)
-	rep.PrintResults(results)
+	stampResults(results)
+	printReports(os.Stdout, results)
 
 	if *cleanupFlag {
 		cleanupDatabases(ctx, cfg, databases)
 	}
+
+	finishRun(results)
+}
+
+// loadRunConfig loads the connection settings with the flags that shape the
+// schema applied.
+func loadRunConfig() *config.Config {
+	cfg, err := loadConfig()
+	if err != nil {
+		fatalRun("Failed to load config: %v", err)
+	}
+
+	applySchemaFlags(cfg)
+
+	return cfg
+}
+
+// applySchemaFlags applies the flags that shape the schema to cfg.
+func applySchemaFlags(cfg *config.Config) {
+	cfg.SetIndexes(*indexes)
+	cfg.SetEventWindow(eventSpan())
+	cfg.SetTenants(*tenantCount > 0)
+}
+
+// stampResults records the run and its environment on the results and
+// compares them with the -baseline.
+func stampResults(results map[string]*benchmark.Results) {
+	stampRun(results)
+	stampEnvironment(results)
+	compareWithBaseline(results)
+}
+
+// finishRun records, exports and notifies the results once reported, then
+// exits with a failure status on failed checks.
+func finishRun(results map[string]*benchmark.Results) {
+	recordHistory(results)
+	exportResults(results)
+	notifyRun(results)
+	flushTelemetry()
+	exitOnFailedChecks(results)
+}
+
+// runDatabases runs the benchmark of every database, or every combination
+// of -matrix, drawing the -progress bars meanwhile. It returns the databases
+// or matrix cells run and their results.
+func runDatabases(ctx context.Context, cfg *config.Config, databases []string) ([]string, map[string]*benchmark.Results) {
+	stopProgress := startProgressBars()
+	defer stopProgress()
+
+	if *matrixFile != "" {
+		return runMatrix(ctx, cfg, databases)
+	}
+
+	return databases, runAllBenchmarks(ctx, cfg, newRunner(), databases)
+}
+
+// exitInterrupted is the exit status of an interrupted run, that of a
+// shell command killed by SIGINT.
+const exitInterrupted = 130
+
+// exitOnFailedChecks exits once the results are reported: with status 1 when
+// they violate an SLO of -slo or regressed against -baseline, or
+// exitInterrupted when they are partial. Both checks log their findings
+// first.
+func exitOnFailedChecks(results map[string]*benchmark.Results) {
+	violated := logSLOViolations(results)
+	regressed := logRegressions(results)
+
+	switch {
+	case interrupted(results):
+		os.Exit(exitInterrupted)
+	case violated || regressed:
+		os.Exit(1)
+	}
+}
+
+// interrupted reports whether the benchmark of any database was cut short
+// by a signal.
+func interrupted(results map[string]*benchmark.Results) bool {
+	for _, res := range results {
+		if res != nil && res.Interrupted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// signalContext returns a context cancelled by the first SIGINT or SIGTERM.
+// The benchmarks then finish their in-flight operations and the partial
+// results are reported; a second signal kills the process.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-sig
+		signal.Stop(sig)
+		log.Println("Interrupted: finishing in-flight operations and reporting partial results, interrupt again to exit")
+		cancel()
+	}()
+
+	return ctx, cancel
 }
 
 func runAllBenchmarks(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, databases []string) map[string]*benchmark.Results {
@@ -100,7 +490,7 @@ func runAllBenchmarks(ctx context.Context, cfg *config.Config, runner *benchmark
 
 			log.Printf("Starting benchmark for %s...", dbName)
 
-			result := runBenchmark(ctx, cfg, runner, dbName)
+			result := runBenchmark(ctx, cfg, runnerFor(runner, dbName), dbName, nil)
 
 			mu.Lock()
 
@@ -118,31 +508,48 @@ func runAllBenchmarks(ctx context.Context, cfg *config.Config, runner *benchmark
 }
 
 func newRunner() *benchmark.Runner {
-	batch := *batchSize
-	maxEvents := *eventCount
-
-	if *preloadCount > maxEvents {
-		maxEvents = *preloadCount
-	}
-
-	if batch > maxEvents {
-		batch = maxEvents
-	}
-
-	totalBatches := (maxEvents + batch - 1) / batch
-	w := *workers
+	return newRunnerWith(*eventCount, *batchSize, *workers)
+}
 
-	if w > totalBatches {
-		w = totalBatches
-	}
+// newRunnerWith builds a runner for the given insert parameters, shrinking
+// the batch and the worker count to what the event count can use.
+func newRunnerWith(events, batch, workerCount int) *benchmark.Runner {
+	maxEvents := max(events, *preloadCount)
+	batch = min(batch, maxEvents)
+	w := min(workerCount, (maxEvents+batch-1)/batch)
+	dist, _ := generator.ParseUserDist(*userDist)
+	tenants, _ := generator.ParseUserDist(*tenantDist)
 
 	return &benchmark.Runner{
-		EventCount:       *eventCount,
+		EventCount:       events,
 		BatchSize:        batch,
 		Workers:          w,
 		QueryIterations:  *queryIterations,
-		WarmupIterations: 5,
+		QueryWorkers:     *queryWorkers,
+		WarmupIterations: *warmup,
+		WarmupBatches:    *warmup,
 		PreloadCount:     *preloadCount,
+		Rate:             *rate,
+		SteadyState:      *steadyState,
+		RampUp:           *rampUp,
+		QueryRate:        *queryRate,
+		MaxErrorRate:     *maxErrorRate,
+		OpTimeout:        *opTimeout,
+		Retries:          *retries,
+		RetryBackoff:     *retryBackoff,
+		DuplicatePct:     *duplicatePct,
+		UserDist:         dist,
+		TimeDist:         timeDistribution(),
+		IDFormat:         *idFormat,
+		TenantCount:      *tenantCount,
+		TenantDist:       tenants,
+		Dataset:          dataset(),
+		UserCount:        *userCount,
+		TransactionCount: *transactions,
+		ReadAfterWrite:   *readAfterWrite,
+		CustomQueries:    customQueries(),
+		Scenarios:        scenarioFilter(),
+		Verify:           *verify,
 	}
 }
 
@@ -151,32 +558,138 @@ func getDatabases(dbType string) []string {
 		return []string{"postgres", "mongodb", "clickhouse", "cassandra"}
 	}
 
-	return []string{dbType}
+	var databases []string
+
+	for _, db := range strings.Split(dbType, ",") {
+		if db = strings.TrimSpace(db); db != "" {
+			databases = append(databases, db)
+		}
+	}
+
+	return databases
+}
+
+// reconnectFunc restarts a database and opens a new repository on it.
+type reconnectFunc func(ctx context.Context) (benchmark.Repository, error)
+
+// runBenchmark runs the benchmark of one database. A non-nil reconnect adds
+// the cold cache comparison at the end.
+func runBenchmark(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string, reconnect reconnectFunc) *benchmark.Results {
+	ctx, endSpan := withTelemetry(ctx, dbName)
+	defer endSpan()
+
+	ctx = withMetrics(withProgress(ctx, dbName), dbName)
+
+	warnPoolSize(cfg, runner, dbName)
+
+	return runMode(ctx, cfg, runner, dbName, reconnect)
+}
+
+// runMode runs the benchmark of one database in the mode the flags select.
+func runMode(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string, reconnect reconnectFunc) *benchmark.Results {
+	if *parity {
+		return runParityCheck(ctx, cfg, runner, dbName)
+	}
+
+	if *durability {
+		return runDurabilityMatrix(ctx, cfg, runner, dbName)
+	}
+
+	if compareInsertModes() {
+		return runInsertModeComparison(ctx, cfg, runner, dbName)
+	}
+
+	if *sweepWorkers != "" {
+		return runWorkerSweep(ctx, cfg, runner, dbName)
+	}
+
+	if *tuneBatch != "" {
+		return runTunedBenchmark(ctx, cfg, runner, dbName, reconnect)
+	}
+
+	if *repeat > 1 {
+		return runRepeated(ctx, cfg, runner, dbName, reconnect)
+	}
+
+	return runOnce(ctx, cfg, runner, dbName, reconnect)
+}
+
+// warnPoolSize warns when a database has fewer connections than workers:
+// the extra workers would queue for a connection and the run would measure
+// the pool rather than the database.
+func warnPoolSize(cfg *config.Config, runner *benchmark.Runner, dbName string) {
+	conns := cfg.MaxConns(dbName)
+	if w := max(runner.Workers, runner.QueryWorkers); conns > 0 && w > conns {
+		log.Printf("Warning: %d workers share the %d pooled connections of %s; size its pool to match",
+			w, conns, dbName)
+	}
 }
 
-func runBenchmark(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string) *benchmark.Results {
+// runOnce runs the regular benchmark of one database on a fresh schema, or
+// on its existing one with -existing-schema.
+func runOnce(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string, reconnect reconnectFunc) *benchmark.Results {
 	repo, err := newRepo(ctx, dbName, cfg)
 	if err != nil {
 		log.Printf("Failed to initialize %s: %v", dbName, err)
 		return &benchmark.Results{Error: err}
 	}
 
-	defer func() {
-		if err := repo.Close(); err != nil {
-			log.Printf("Failed to close %s: %v", dbName, err)
-		}
-	}()
+	defer closeRepo(repo, dbName)
 
-	if err := repo.InitSchema(ctx); err != nil {
-		log.Printf("Failed to initialize %s schema: %v", dbName, err)
+	indexed := loadWithIndexes(ctx, runner, repo, dbName)
+
+	if err := prepareDatabase(ctx, runner, repo, dbName); err != nil {
 		return &benchmark.Results{Error: err}
 	}
 
+	res := executeBenchmark(ctx, runner, repo, dbName, reconnect)
+	if res.IndexBuild != nil {
+		res.IndexBuild.Indexed = indexed
+	}
+
+	return res
+}
+
+// prepareDatabase recreates the schema of a database and loads the preload
+// events and the users the flags ask for.
+func prepareDatabase(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string) error {
+	if err := initSchema(ctx, runner, repo, dbName); err != nil {
+		log.Printf("Failed to initialize %s schema: %v", dbName, err)
+		return err
+	}
+
 	if err := preloadIfNeeded(ctx, runner, repo, dbName); err != nil {
-		return &benchmark.Results{Error: err}
+		return err
 	}
 
-	return executeBenchmark(ctx, runner, repo, dbName)
+	return loadUsersIfNeeded(ctx, runner, repo, dbName)
+}
+
+// initSchema recreates the schema of a database, unless a preload checkpoint
+// of it is to be resumed: that needs the events it already inserted. With
+// -existing-schema it only verifies the schema.
+func initSchema(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string) error {
+	if *existingSchema {
+		log.Printf("Keeping the existing %s schema and its events", dbName)
+		return verifyExistingSchema(ctx, repo, dbName)
+	}
+
+	if runner.CanResumePreload(preloadCheckpoint(dbName)) {
+		log.Printf("Keeping the %s schema to resume its preload", dbName)
+		return nil
+	}
+
+	return repo.InitSchema(ctx)
+}
+
+// preloadCheckpoint returns the checkpoint file of the preload of a database,
+// or "" when -preload-checkpoint is not set.
+func preloadCheckpoint(dbName string) string {
+	if *preloadCkpt == "" {
+		return ""
+	}
+
+	return filepath.Join(*preloadCkpt, dbName+".preload.json")
 }
 
 func preloadIfNeeded(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string) error {
@@ -186,7 +699,7 @@ func preloadIfNeeded(ctx context.Context, runner *benchmark.Runner, repo benchma
 
 	log.Printf("Pre-loading %s with %d events...", dbName, runner.PreloadCount)
 
-	if err := runner.Preload(ctx, repo); err != nil {
+	if err := runner.PreloadResumable(ctx, repo, preloadCheckpoint(dbName)); err != nil {
 		log.Printf("Failed to preload %s: %v", dbName, err)
 		return err
 	}
@@ -194,46 +707,306 @@ func preloadIfNeeded(ctx context.Context, runner *benchmark.Runner, repo benchma
 	return nil
 }
 
-func executeBenchmark(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string) *benchmark.Results {
-	res := &benchmark.Results{Database: dbName, Timestamp: time.Now()}
+func loadUsersIfNeeded(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string) error {
+	if runner.UserCount <= 0 {
+		return nil
+	}
 
-	if !*skipInsert {
-		log.Printf("Benchmarking inserts for %s (%d events)...", dbName, runner.EventCount)
-		res.Insert = runner.RunInsert(ctx, repo)
-		log.Printf("Insert benchmark done for %s: %.0f/sec", dbName, res.Insert.Throughput)
+	if _, ok := repo.(benchmark.UsersRepository); !ok {
+		log.Printf("%s has no users table support, skipping the join scenario", dbName)
+		return nil
+	}
+
+	log.Printf("Loading %s with %d users...", dbName, runner.UserCount)
+
+	if err := runner.LoadUsers(ctx, repo); err != nil {
+		log.Printf("Failed to load users into %s: %v", dbName, err)
+		return err
 	}
 
-	if !*skipQuery {
+	return nil
+}
+
+func executeBenchmark(
+	ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string, reconnect reconnectFunc,
+) *benchmark.Results {
+	res := &benchmark.Results{Database: dbName, Timestamp: time.Now(), Warmup: runner.Warmup()}
+
+	if config.HasIndexSets(dbName) {
+		res.Indexes = *indexes
+	}
+
+	if ir, ok := repo.(benchmark.InsertModeRepository); ok {
+		res.InsertMode = ir.InsertMode()
+	}
+
+	if !runMainWorkloads(ctx, runner, repo, dbName, res) {
+		return res
+	}
+
+	runReadAfterWrite(ctx, runner, repo, dbName, res)
+	runWriteWorkloads(ctx, runner, repo, dbName, res)
+
+	if s := repo.GetStorageStats(ctx); s != nil {
+		res.Storage = s
+	}
+
+	if reconnect != nil && !*skipQuery {
+		res.Cache = runCacheComparison(ctx, runner, repo, dbName, reconnect)
+	}
+
+	return res
+}
+
+// runMainWorkloads runs the insert and query benchmarks and marks the results
+// degraded when either was aborted on its error rate. It returns false after
+// an aborted insert run, when the database is failing, or once the benchmark
+// is interrupted; the workloads after it are then skipped.
+func runMainWorkloads(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string, res *benchmark.Results) bool {
+	defer res.MarkDegraded()
+
+	if !*skipInsert && !runInsertBenchmark(ctx, runner, repo, dbName, res) {
+		return false
+	}
+
+	if *deferIndexes && ctx.Err() == nil {
+		res.IndexBuild = buildIndexes(ctx, runner, repo, dbName)
+	}
+
+	if !*skipQuery && ctx.Err() == nil {
 		log.Printf("Benchmarking queries for %s...", dbName)
 
 		res.Queries = runner.RunQueries(ctx, repo)
+		maps.Copy(res.Queries, runner.RunCustomQueries(ctx, repo, dbName))
 
 		log.Printf("Query benchmark done for %s", dbName)
 	}
 
-	if s := repo.GetStorageStats(ctx); s != nil {
-		res.Storage = s
+	res.Interrupted = ctx.Err() != nil
+
+	return !res.Interrupted
+}
+
+// runInsertBenchmark runs the insert benchmark into res. It returns false
+// when the run was aborted on its error rate.
+func runInsertBenchmark(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string, res *benchmark.Results) bool {
+	log.Printf("Benchmarking inserts for %s (%d events)...", dbName, runner.EventCount)
+	res.Insert = runner.RunInsert(ctx, repo)
+
+	if res.Insert.Aborted {
+		log.Printf("Insert benchmark aborted for %s on its error rate, skipping the remaining workloads", dbName)
+		return false
+	}
+
+	log.Printf("Insert benchmark done for %s: %.0f/sec", dbName, res.Insert.Throughput)
+
+	switch v := res.Insert.Verify; {
+	case v == nil || v.OK():
+	case v.ErrorText != "":
+		log.Printf("Could not verify the %s inserts: %s", dbName, v.ErrorText)
+	default:
+		log.Printf("Insert verification failed for %s: %d events inserted, %d rows added (%d missing, %d extra)",
+			dbName, v.Inserted, v.Counted, v.Missing, v.Extra)
+	}
+
+	return true
+}
+
+// runReadAfterWrite samples read-after-write visibility when
+// -read-after-write is set.
+func runReadAfterWrite(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string, res *benchmark.Results) {
+	if runner.ReadAfterWrite <= 0 || ctx.Err() != nil {
+		return
 	}
 
+	log.Printf("Sampling read-after-write visibility for %s (%d batches)...", dbName, runner.ReadAfterWrite)
+
+	res.Visibility = runner.RunReadAfterWrite(ctx, repo)
+
+	log.Printf("Read-after-write sampling done for %s: %.1f%% stale, %.1f%% missing",
+		dbName, res.Visibility.StalePct, res.Visibility.MissingPct)
+}
+
+// runCacheComparison restarts the database once the other workloads are done
+// and measures the queries on a cold and then a warm cache.
+func runCacheComparison(
+	ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string, reconnect reconnectFunc,
+) *benchmark.CacheResult {
+	log.Printf("Restarting %s for the cold cache comparison...", dbName)
+
+	fresh, err := reconnect(ctx)
+	if err != nil {
+		log.Printf("Failed to reconnect to %s: %v", dbName, err)
+		return &benchmark.CacheResult{ErrorText: err.Error()}
+	}
+
+	defer func() {
+		if err := fresh.Close(); err != nil {
+			log.Printf("Failed to close %s: %v", dbName, err)
+		}
+	}()
+
+	res := runner.RunCacheComparison(ctx, repo, fresh)
+
+	log.Printf("Cold cache comparison done for %s", dbName)
+
 	return res
 }
 
+// runWriteWorkloads runs the optional workloads that modify the data set after
+// the query benchmark: read-while-write, transactions, the retention delete,
+// and last the TTL run, which leaves expiry enabled on the events table.
+func runWriteWorkloads(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string, res *benchmark.Results) {
+	if *mixed {
+		log.Printf("Benchmarking queries under ingest for %s (%d events)...", dbName, runner.EventCount)
+
+		res.Mixed = runner.RunMixed(ctx, repo)
+
+		log.Printf("Mixed benchmark done for %s: p95 %v idle, %v under load",
+			dbName, res.Mixed.Baseline.P95Duration, res.Mixed.UnderLoad.P95Duration)
+	}
+
+	if runner.TransactionCount > 0 {
+		log.Printf("Benchmarking transactions for %s (%d transactions)...", dbName, runner.TransactionCount)
+
+		res.Transactions = runner.RunTransactions(ctx, repo)
+
+		log.Printf("Transaction benchmark done for %s: %.0f/sec", dbName, res.Transactions.Throughput)
+	}
+
+	runExpiryWorkloads(ctx, runner, repo, dbName, res)
+}
+
+// runExpiryWorkloads runs the retention delete and TTL expiry benchmarks
+// enabled by flags.
+func runExpiryWorkloads(ctx context.Context, runner *benchmark.Runner, repo benchmark.Repository, dbName string, res *benchmark.Results) {
+	if *retentionDays > 0 {
+		log.Printf("Benchmarking retention deletes for %s (older than %d days)...", dbName, *retentionDays)
+
+		res.Retention = runner.RunRetention(ctx, repo, *retentionDays)
+
+		log.Printf("Retention benchmark done for %s: %d rows deleted", dbName, res.Retention.RowsDeleted)
+	}
+
+	if *ttlDays > 0 {
+		log.Printf("Benchmarking TTL expiry for %s (%d days)...", dbName, *ttlDays)
+
+		res.TTL = runner.RunTTL(ctx, repo, *ttlDays, res.Insert)
+
+		log.Printf("TTL benchmark done for %s: %d rows before expiry, %d after", dbName, res.TTL.RowsBefore, res.TTL.RowsAfter)
+	}
+}
+
+// closeRepo closes the repository of a database, logging a failure.
+func closeRepo(repo benchmark.Repository, dbName string) {
+	if err := repo.Close(); err != nil {
+		log.Printf("Failed to close %s: %v", dbName, err)
+	}
+}
+
+// newRepo connects to a database, describing its environment on the first
+// connection.
 func newRepo(ctx context.Context, dbType string, cfg *config.Config) (benchmark.Repository, error) {
+	repo, err := openRepo(ctx, dbType, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyInsertMode(repo, dbType); err != nil {
+		_ = repo.Close()
+
+		return nil, err
+	}
+
+	describeEnvironment(ctx, dbType, repo)
+
+	return repo, nil
+}
+
+// openRepo connects to a database of the type dbType. The types are
+// spread over openRepo and the functions it falls through to, one per kind
+// of database.
+func openRepo(ctx context.Context, dbType string, cfg *config.Config) (benchmark.Repository, error) {
 	switch dbType {
 	case "postgres":
 		return repository.NewPostgresRepo(ctx, &cfg.Postgres)
+	case "yugabytedb":
+		return repository.NewYugabyteDBRepo(ctx, &cfg.YugabyteDB)
+	case "clickhouse":
+		return repository.NewClickHouseRepo(ctx, &cfg.ClickHouse)
+	case "starrocks":
+		return repository.NewStarRocksRepo(ctx, &cfg.StarRocks)
+	case "doris":
+		return repository.NewDorisRepo(ctx, &cfg.Doris)
+	case "questdb":
+		return repository.NewQuestDBRepo(ctx, &cfg.QuestDB)
+	default:
+		return openNoSQLRepo(ctx, dbType, cfg)
+	}
+}
+
+// openNoSQLRepo is openRepo for the database servers without SQL.
+func openNoSQLRepo(ctx context.Context, dbType string, cfg *config.Config) (benchmark.Repository, error) {
+	switch dbType {
 	case "mongodb":
 		return repository.NewMongoDBRepo(ctx, cfg.MongoDB)
 	case "cassandra":
 		return repository.NewCassandraRepo(ctx, cfg.Cassandra)
-	case "clickhouse":
-		return repository.NewClickHouseRepo(ctx, &cfg.ClickHouse)
+	case "scylladb":
+		return repository.NewScyllaDBRepo(ctx, cfg.ScyllaDB)
+	case "pinot":
+		return repository.NewPinotRepo(ctx, &cfg.Pinot)
+	case "victoriametrics":
+		return repository.NewVictoriaMetricsRepo(ctx, &cfg.VictoriaMetrics)
+	default:
+		return openStoreRepo(ctx, dbType, cfg)
+	}
+}
+
+// openStoreRepo is openRepo for the key-value stores and message brokers.
+func openStoreRepo(ctx context.Context, dbType string, cfg *config.Config) (benchmark.Repository, error) {
+	switch dbType {
+	case "redis":
+		return repository.NewRedisRepo(ctx, &cfg.Redis)
+	case "etcd":
+		return repository.NewEtcdRepo(ctx, &cfg.Etcd)
+	case "nats":
+		return repository.NewNATSRepo(ctx, &cfg.NATS)
+	case "kafka":
+		return repository.NewKafkaRepo(ctx, &cfg.Kafka)
+	default:
+		return openEmbeddedRepo(ctx, dbType, cfg)
+	}
+}
+
+// openEmbeddedRepo is openRepo for the embedded databases.
+func openEmbeddedRepo(ctx context.Context, dbType string, cfg *config.Config) (benchmark.Repository, error) {
+	switch dbType {
+	case "sqlite":
+		return repository.NewSQLiteRepo(ctx, &cfg.SQLite)
+	case "duckdb":
+		return repository.NewDuckDBRepo(ctx, &cfg.DuckDB)
+	case "badger":
+		return repository.NewBadgerRepo(ctx, &cfg.Badger)
+	case "pebble":
+		return repository.NewPebbleRepo(ctx, &cfg.Pebble)
+	case "bbolt":
+		return repository.NewBoltRepo(ctx, &cfg.Bolt)
 	default:
 		return nil, fmt.Errorf("unsupported database type: %s", dbType)
 	}
 }
 
+// cleanupTimeout bounds the -cleanup of the databases or containers, which
+// runs even when the benchmark was interrupted.
+const cleanupTimeout = 5 * time.Minute
+
 func cleanupDatabases(ctx context.Context, cfg *config.Config, databases []string) {
+	// ctx is cancelled once the run is interrupted, and the cleanup should
+	// still drop the events it inserted.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+	defer cancel()
+
 	log.Println("Cleaning up databases...")
 
 	for _, dbName := range databases {
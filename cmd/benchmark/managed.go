@@ -5,14 +5,11 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
 	"github.com/skoredin/db-benchmark-suite/internal/config"
 	"github.com/skoredin/db-benchmark-suite/internal/orchestrator"
-	"github.com/skoredin/db-benchmark-suite/internal/reporter"
 )
 
 const (
@@ -30,12 +27,9 @@ func colorLogf(color, format string, args ...any) {
 // runManaged starts each database container sequentially, runs the benchmark,
 // stops the container, then prints a combined summary at the end.
 func runManaged() {
-	cfg, err := config.Load()
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
-	}
+	cfg := loadRunConfig()
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	ctx, stop := signalContext()
 	defer stop()
 
 	runner := newRunner()
@@ -45,24 +39,32 @@ func runManaged() {
 
 	allResults := runManagedBenchmarks(ctx, cfg, runner, databases)
 
+	stampResults(allResults)
 	printManagedResults(ctx, allResults)
+	finishRun(allResults)
 }
 
 func runManagedBenchmarks(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, databases []string) map[string]*benchmark.Results {
 	allResults := make(map[string]*benchmark.Results)
 	for _, dbName := range databases {
-		allResults[dbName] = runManagedDB(ctx, cfg, runner, dbName)
+		if ctx.Err() != nil {
+			break
+		}
+
+		allResults[dbName] = runManagedDB(ctx, cfg, runnerFor(runner, dbName), dbName)
 	}
 
 	return allResults
 }
 
 func printManagedResults(ctx context.Context, allResults map[string]*benchmark.Results) {
-	rep := reporter.New(*outputFormat, os.Stderr)
-	rep.PrintHeader()
-	rep.PrintResults(allResults)
+	printHeader(os.Stderr)
+	printReports(os.Stderr, allResults)
 
 	if *cleanupFlag {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+		defer cancel()
+
 		if err := orchestrator.Cleanup(ctx); err != nil {
 			log.Printf("Failed to cleanup orchestrator: %v", err)
 		}
@@ -108,26 +110,64 @@ func runManagedDB(ctx context.Context, cfg *config.Config, runner *benchmark.Run
 }
 
 func runManagedBenchmark(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, svc orchestrator.DBService) *benchmark.Results {
-	if err := orchestrator.StartService(ctx, svc.Service); err != nil {
-		return &benchmark.Results{Database: svc.Name, Error: err}
-	}
+	if svc.Embedded() {
+		colorLogf(cGreen, "Running benchmark for %s (embedded)...", svc.Name)
+		result := runBenchmark(ctx, cfg, runner, svc.Name, nil)
+		result.Database = svc.Name
 
-	if err := orchestrator.WaitReady(ctx, svc); err != nil {
-		if err := orchestrator.StopService(ctx, svc.Service); err != nil {
-			log.Printf("Failed to stop orchestrator: %v", err)
-		}
+		return result
+	}
 
+	if err := startService(ctx, svc); err != nil {
 		return &benchmark.Results{Database: svc.Name, Error: err}
 	}
 
 	colorLogf(cGreen, "Running benchmark for %s...", svc.Name)
-	result := runBenchmark(ctx, cfg, runner, svc.Name)
+	result := runBenchmark(ctx, cfg, runner, svc.Name, containerReconnect(cfg, svc))
 	result.Database = svc.Name
 	result.Timestamp = time.Now()
 
-	if err := orchestrator.StopService(ctx, svc.Service); err != nil {
+	if err := orchestrator.StopService(context.WithoutCancel(ctx), svc.Service); err != nil {
 		log.Printf("Failed to stop orchestrator: %v", err)
 	}
 
 	return result
 }
+
+// startService starts the container of svc and waits until it is ready,
+// stopping it again when it never is.
+func startService(ctx context.Context, svc orchestrator.DBService) error {
+	if err := orchestrator.StartService(ctx, svc.Service); err != nil {
+		return err
+	}
+
+	if err := orchestrator.WaitReady(ctx, svc); err != nil {
+		if err := orchestrator.StopService(ctx, svc.Service); err != nil {
+			log.Printf("Failed to stop orchestrator: %v", err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// containerReconnect returns the restart hook of the cold cache comparison for
+// a container service, or nil when -cold-cache is off.
+func containerReconnect(cfg *config.Config, svc orchestrator.DBService) reconnectFunc {
+	if !*coldCache {
+		return nil
+	}
+
+	return func(ctx context.Context) (benchmark.Repository, error) {
+		if err := orchestrator.RestartService(ctx, svc.Service); err != nil {
+			return nil, fmt.Errorf("failed to restart %s: %w", svc.Service, err)
+		}
+
+		if err := orchestrator.WaitReady(ctx, svc); err != nil {
+			return nil, err
+		}
+
+		return newRepo(ctx, svc.Name, cfg)
+	}
+}
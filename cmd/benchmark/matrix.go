@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// matrixConfig is the -matrix file. Every combination of its lists is run;
+// an empty or missing list falls back to the matching flag.
+type matrixConfig struct {
+	Databases  []string `json:"databases"`
+	Workers    []int    `json:"workers"`
+	BatchSizes []int    `json:"batch_sizes"`
+	Events     []int    `json:"events"`
+}
+
+func loadMatrix(path string) (*matrixConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read matrix file: %w", err)
+	}
+
+	var m matrixConfig
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse matrix file: %w", err)
+	}
+
+	for name, values := range map[string][]int{"workers": m.Workers, "batch_sizes": m.BatchSizes, "events": m.Events} {
+		for _, v := range values {
+			if v <= 0 {
+				return nil, fmt.Errorf("%s must be positive, got %d", name, v)
+			}
+		}
+	}
+
+	return &m, nil
+}
+
+// cells returns the parameters of every combination, falling back to the
+// flags for the lists the file leaves empty.
+func (m *matrixConfig) cells() []benchmark.RunParams {
+	workerCounts := orDefault(m.Workers, *workers)
+	batchSizes := orDefault(m.BatchSizes, *batchSize)
+
+	var cells []benchmark.RunParams
+
+	for _, events := range orDefault(m.Events, *eventCount) {
+		for _, batch := range batchSizes {
+			for _, w := range workerCounts {
+				cells = append(cells, benchmark.RunParams{Events: events, BatchSize: batch, Workers: w})
+			}
+		}
+	}
+
+	return cells
+}
+
+func orDefault(values []int, def int) []int {
+	if len(values) == 0 {
+		return []int{def}
+	}
+
+	return values
+}
+
+// validateMatrixFlags checks that the -matrix file loads and is not run
+// with -managed.
+func validateMatrixFlags() {
+	if *matrixFile == "" {
+		return
+	}
+
+	if _, err := loadMatrix(*matrixFile); err != nil {
+		log.Fatalf("--matrix: %v", err)
+	}
+
+	if *managed {
+		log.Fatal("--matrix runs against running databases and cannot be combined with --managed")
+	}
+}
+
+// runMatrix runs the benchmark once per database and parameter combination,
+// one run at a time so runs do not compete for the host. Results are keyed
+// by database and parameters, and the databases of the file replace the
+// -db list when it has any.
+func runMatrix(ctx context.Context, cfg *config.Config, databases []string) ([]string, map[string]*benchmark.Results) {
+	m, err := loadMatrix(*matrixFile)
+	if err != nil {
+		log.Fatalf("--matrix: %v", err)
+	}
+
+	if len(m.Databases) > 0 {
+		databases = m.Databases
+	}
+
+	results := make(map[string]*benchmark.Results)
+
+	for _, db := range databases {
+		for _, params := range m.cells() {
+			if ctx.Err() != nil {
+				return databases, results
+			}
+
+			result := runMatrixCell(ctx, cfg, db, params)
+			results[db+" "+result.Params.String()] = result
+		}
+	}
+
+	return databases, results
+}
+
+// runMatrixCell runs the benchmark of a database with one parameter
+// combination, its defaults filled in.
+func runMatrixCell(ctx context.Context, cfg *config.Config, db string, params benchmark.RunParams) *benchmark.Results {
+	runner := newRunnerWith(params.Events, params.BatchSize, params.Workers)
+	params.BatchSize, params.Workers = runner.BatchSize, runner.Workers
+
+	log.Printf("Starting matrix run for %s (%s)...", db, params)
+
+	result := runBenchmark(ctx, cfg, runner, db, nil)
+	result.Database = db
+	result.Params = &params
+
+	return result
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// liveMetrics are the metrics served on -metrics-addr while the benchmark
+// runs; nil when the endpoint is off.
+var liveMetrics *runMetrics
+
+// runMetrics are the live counters and histograms of a run, labeled by
+// database and, for queries, by scenario.
+type runMetrics struct {
+	insertedEvents *prometheus.CounterVec
+	insertErrors   *prometheus.CounterVec
+	batchLatency   *prometheus.HistogramVec
+	queryLatency   *prometheus.HistogramVec
+	queryErrors    *prometheus.CounterVec
+}
+
+func newRunMetrics(reg prometheus.Registerer) *runMetrics {
+	m := &runMetrics{
+		insertedEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "dbbench", Name: "inserted_events_total", Help: "Events inserted.",
+		}, []string{"database"}),
+		insertErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "dbbench", Name: "insert_errors_total", Help: "Insert batches that failed every attempt.",
+		}, []string{"database"}),
+		batchLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "dbbench", Name: "batch_latency_seconds", Help: "Latency of the inserted batches, retries included.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+		}, []string{"database"}),
+		queryLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "dbbench", Name: "query_latency_seconds", Help: "Latency of the successful query runs, retries included.",
+			Buckets: prometheus.ExponentialBuckets(0.0005, 2, 16),
+		}, []string{"database", "scenario"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "dbbench", Name: "query_errors_total", Help: "Query runs that failed every attempt.",
+		}, []string{"database", "scenario"}),
+	}
+
+	reg.MustRegister(m.insertedEvents, m.insertErrors, m.batchLatency, m.queryLatency, m.queryErrors)
+
+	return m
+}
+
+// startMetricsServer serves the live metrics of the run on -metrics-addr
+// until the process exits. An address that cannot be listened on fails the
+// run before any database is benchmarked.
+func startMetricsServer() {
+	if *metricsAddr == "" {
+		return
+	}
+
+	ln, err := net.Listen("tcp", *metricsAddr)
+	if err != nil {
+		log.Fatalf("--metrics-addr: %v", err)
+	}
+
+	reg := prometheus.NewRegistry()
+	liveMetrics = newRunMetrics(reg)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Metrics endpoint stopped: %v", err)
+		}
+	}()
+
+	log.Printf("Serving live metrics on http://%s/metrics", ln.Addr())
+}
+
+// withMetrics reports the inserts and queries of dbName on ctx to the live
+// metrics when they are served.
+func withMetrics(ctx context.Context, dbName string) context.Context {
+	if liveMetrics == nil {
+		return ctx
+	}
+
+	return benchmark.WithObserver(ctx, metricsObserver{m: liveMetrics, db: dbName})
+}
+
+// metricsObserver records the inserts and queries of one database.
+type metricsObserver struct {
+	m  *runMetrics
+	db string
+}
+
+func (o metricsObserver) Inserted(events int, latency time.Duration) {
+	o.m.insertedEvents.WithLabelValues(o.db).Add(float64(events))
+	o.m.batchLatency.WithLabelValues(o.db).Observe(latency.Seconds())
+}
+
+func (o metricsObserver) InsertFailed() {
+	o.m.insertErrors.WithLabelValues(o.db).Inc()
+}
+
+func (o metricsObserver) Queried(scenario string, latency time.Duration, err error) {
+	if err != nil {
+		o.m.queryErrors.WithLabelValues(o.db, scenario).Inc()
+		return
+	}
+
+	o.m.queryLatency.WithLabelValues(o.db, scenario).Observe(latency.Seconds())
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/notify"
+)
+
+// validateNotify checks the -notify URL, so a typo fails the run before any
+// database is benchmarked rather than after it.
+func validateNotify() {
+	if *notifyURL == "" {
+		return
+	}
+
+	if u, err := url.Parse(*notifyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		log.Fatal("--notify must be an http or https URL")
+	}
+}
+
+// notifyRun posts the summary of the run, and its regressions against
+// -baseline, to the -notify webhook. A failure is logged and leaves the run
+// itself alone, like one of the exports.
+func notifyRun(results map[string]*benchmark.Results) {
+	if *notifyURL == "" {
+		return
+	}
+
+	send(notify.NewMessage(results))
+}
+
+// fatalRun notifies the -notify webhook that the run failed before it had
+// any results, then logs the failure and exits like log.Fatalf.
+func fatalRun(format string, args ...any) {
+	if *notifyURL != "" {
+		send(notify.NewFailure(runID, fmt.Errorf(format, args...)))
+	}
+
+	log.Fatalf(format, args...)
+}
+
+func send(msg notify.Message) {
+	// The run context may be cancelled by now; an interrupted run notifies too.
+	if err := notify.Send(context.Background(), *notifyURL, msg); err != nil {
+		log.Printf("Failed to send the notification: %v", err)
+	} else {
+		log.Printf("Sent the %s notification", msg.Status)
+	}
+}
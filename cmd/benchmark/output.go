@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/reporter"
+)
+
+// outputFormats are the report formats of -output.
+var outputFormats = []string{"table", "json", "markdown", "csv", "template"}
+
+// outputTarget is a report format and the file it is written to, "" for the
+// console.
+type outputTarget struct {
+	format string
+	path   string
+}
+
+// outputs parses -output and -output-file once, so a bad format fails the
+// run before any database is benchmarked.
+var outputs = sync.OnceValue(func() []outputTarget {
+	targets, err := parseOutputs(*outputFormat, *outputFile)
+	if err != nil {
+		log.Fatalf("--output: %v", err)
+	}
+
+	if slices.ContainsFunc(targets, func(t outputTarget) bool { return t.format == "template" }) {
+		if *templateFile == "" {
+			log.Fatal("--output template needs --template")
+		}
+
+		reportTemplate()
+	}
+
+	return targets
+})
+
+// reportTemplate parses -template once.
+var reportTemplate = sync.OnceValue(func() reporter.Template {
+	t, err := reporter.ParseTemplate(*templateFile)
+	if err != nil {
+		log.Fatalf("--template: %v", err)
+	}
+
+	return t
+})
+
+// parseOutputs parses the comma-separated format or format:path entries of
+// -output, e.g. "table,json:results.json", and adds the -output-file path in
+// the format of its extension. At most one entry goes to the console.
+func parseOutputs(spec, file string) ([]outputTarget, error) {
+	var targets []outputTarget
+
+	for _, field := range strings.Split(spec, ",") {
+		format, path, _ := strings.Cut(strings.TrimSpace(field), ":")
+		if !slices.Contains(outputFormats, format) {
+			return nil, fmt.Errorf("unknown format %q, want one of %s", format, strings.Join(outputFormats, ", "))
+		}
+
+		if path == "" && slices.ContainsFunc(targets, func(t outputTarget) bool { return t.path == "" }) {
+			return nil, fmt.Errorf("only one format can be printed to the console, give %s a path", format)
+		}
+
+		targets = append(targets, outputTarget{format: format, path: path})
+	}
+
+	if file != "" {
+		targets = append(targets, outputTarget{format: formatOfFile(file), path: file})
+	}
+
+	return targets, nil
+}
+
+// formatOfFile picks the report format of an -output-file by its extension:
+// markdown for .md, CSV for .csv, the table for .txt and JSON otherwise.
+func formatOfFile(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return "markdown"
+	case ".csv":
+		return "csv"
+	case ".txt":
+		return "table"
+	default:
+		return "json"
+	}
+}
+
+// printHeader prints the banner of the console format, if any, to w.
+func printHeader(w io.Writer) {
+	for _, t := range outputs() {
+		if t.path == "" {
+			reporter.New(t.format, w).PrintHeader()
+		}
+	}
+}
+
+// printReports prints the results in the console format to w and writes
+// them to every -output file. A file that cannot be written is logged.
+func printReports(w io.Writer, results map[string]*benchmark.Results) {
+	for _, t := range outputs() {
+		if t.path == "" {
+			newReporter(t.format, w).PrintResults(results)
+			continue
+		}
+
+		if err := writeReport(t, results); err != nil {
+			log.Printf("Failed to write the %s report: %v", t.format, err)
+			continue
+		}
+
+		log.Printf("Wrote the %s report to %s", t.format, t.path)
+	}
+}
+
+func writeReport(t outputTarget, results map[string]*benchmark.Results) error {
+	var buf bytes.Buffer
+
+	newReporter(t.format, &buf).PrintResults(results)
+
+	if err := os.WriteFile(t.path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", t.path, err)
+	}
+
+	return nil
+}
+
+// validateRelativeTo checks that -relative-to names one of the databases.
+// The cells of a matrix are named after their parameters, so only a plain
+// database list is checked.
+func validateRelativeTo() {
+	if *relativeTo != "" && *matrixFile == "" && !slices.Contains(getDatabases(*dbType), *relativeTo) {
+		log.Fatalf("--relative-to %s is not one of the -db databases", *relativeTo)
+	}
+}
+
+// parseScoreWeights parses -score-weights; without it the summary has no
+// overall score.
+func parseScoreWeights() (map[string]float64, error) {
+	if *scoreWeights == "" {
+		return nil, nil
+	}
+
+	return reporter.ParseScoreWeights(*scoreWeights)
+}
+
+func newReporter(format string, w io.Writer) *reporter.Reporter {
+	rep := reporter.New(format, w)
+	rep.SetVerbose(*verbose)
+	rep.SetReference(*relativeTo)
+
+	if weights, err := parseScoreWeights(); err == nil {
+		rep.SetScoreWeights(weights)
+	}
+
+	if format == "template" {
+		rep.SetTemplate(reportTemplate())
+	}
+
+	return rep
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// parityTime is the reference time of the parity dataset. It is taken once,
+// to the second, so the databases of a run all get the same timestamps, also
+// those that only store whole seconds.
+var parityTime = sync.OnceValue(func() time.Time { return time.Now().Truncate(time.Second) })
+
+// runParityCheck loads the parity dataset into a database and compares its
+// event stats with the dataset in place of the regular benchmark.
+func runParityCheck(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string) *benchmark.Results {
+	repo, err := newRepo(ctx, dbName, cfg)
+	if err != nil {
+		log.Printf("Failed to initialize %s: %v", dbName, err)
+		return &benchmark.Results{Database: dbName, Error: err}
+	}
+
+	defer func() {
+		if err := repo.Close(); err != nil {
+			log.Printf("Failed to close %s: %v", dbName, err)
+		}
+	}()
+
+	log.Printf("Checking query result parity for %s (%d events)...", dbName, runner.EventCount)
+
+	res := &benchmark.Results{Database: dbName, Timestamp: time.Now(), Parity: runner.RunParity(ctx, repo, parityTime())}
+	res.Interrupted = ctx.Err() != nil
+
+	logParity(dbName, res.Parity)
+
+	return res
+}
+
+// logParity logs the outcome of the parity check of a database, naming the
+// windows whose counts differ from the dataset.
+func logParity(dbName string, p *benchmark.ParityResult) {
+	if p.OK() {
+		log.Printf("Query result parity ok for %s", dbName)
+		return
+	}
+
+	if p.ErrorText != "" {
+		log.Printf("Parity check failed for %s: %s", dbName, p.ErrorText)
+		return
+	}
+
+	var windows []string
+
+	for _, w := range p.Windows {
+		if w.ErrorText != "" || len(w.Mismatched) > 0 {
+			windows = append(windows, w.Name)
+		}
+	}
+
+	log.Printf("Query result parity mismatch for %s: %d failed batches, windows %s",
+		dbName, p.FailedBatches, strings.Join(windows, ", "))
+}
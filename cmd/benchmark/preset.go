@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"maps"
+	"slices"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// applyPreset sets the flags of the -preset workload that neither the
+// command line nor the -config file sets.
+func applyPreset() {
+	if *preset == "" {
+		return
+	}
+
+	p, err := config.LookupPreset(*preset)
+	if err != nil {
+		log.Fatalf("--preset: %v", err)
+	}
+
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	values := p.Flags()
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if set[name] {
+			continue
+		}
+
+		if err := flag.Set(name, values[name]); err != nil {
+			log.Fatalf("--preset: flag %s: %v", name, err)
+		}
+	}
+}
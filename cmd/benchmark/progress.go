@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/progress"
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// progressBars draws the -progress bars; nil when they are off.
+var progressBars progress.Writer
+
+// isTerminal reports whether f is a terminal rather than a file or a pipe.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// startProgressBars starts drawing -progress bars on stdout and returns the
+// function that stops them. Without -progress, or when stdout is not a
+// terminal, the runs log their progress as usual. While the bars are drawn,
+// log lines meant for a terminal are printed above them.
+func startProgressBars() (stop func()) {
+	if !*showProgress || !isTerminal(os.Stdout) {
+		return func() {}
+	}
+
+	pw := newProgressWriter()
+
+	if isTerminal(os.Stderr) {
+		log.SetOutput(progressLog{pw})
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		pw.Render()
+		close(done)
+	}()
+
+	progressBars = pw
+
+	return func() {
+		pw.Stop()
+		<-done
+		log.SetOutput(os.Stderr)
+
+		progressBars = nil
+	}
+}
+
+// newProgressWriter returns a writer drawing block bars with their rate and
+// ETA on stdout.
+func newProgressWriter() progress.Writer {
+	pw := progress.NewWriter()
+	pw.SetOutputWriter(os.Stdout)
+	pw.SetMessageLength(32)
+	pw.SetUpdateFrequency(200 * time.Millisecond)
+	pw.SetStyle(progress.StyleBlocks)
+	pw.Style().Visibility.ETA = true
+	pw.Style().Visibility.Speed = true
+	pw.Style().Options.TimeInProgressPrecision = time.Second
+	pw.Style().Options.TimeDonePrecision = time.Millisecond
+
+	return pw
+}
+
+// progressLog prints log lines above the progress bars.
+type progressLog struct {
+	pw progress.Writer
+}
+
+func (l progressLog) Write(p []byte) (int, error) {
+	l.pw.Log("%s", strings.TrimSuffix(string(p), "\n"))
+
+	return len(p), nil
+}
+
+// withProgress makes the insert runs of dbName on ctx draw progress bars
+// when they are on.
+func withProgress(ctx context.Context, dbName string) context.Context {
+	if progressBars == nil {
+		return ctx
+	}
+
+	return benchmark.WithProgress(ctx, databaseProgress{pw: progressBars, db: dbName})
+}
+
+// databaseProgress adds a bar for each insert run of one database.
+type databaseProgress struct {
+	pw progress.Writer
+	db string
+}
+
+func (p databaseProgress) Start(title string, total int) benchmark.ProgressTracker {
+	t := &progress.Tracker{Message: p.db + " " + title, Total: int64(total), Units: progress.UnitsDefault}
+	p.pw.AppendTracker(t)
+
+	return &progressTracker{Tracker: t, message: t.Message}
+}
+
+// progressTracker is the bar of one insert run; failed batches are counted
+// in its message.
+type progressTracker struct {
+	*progress.Tracker
+
+	mu      sync.Mutex
+	message string
+	errors  int
+}
+
+func (t *progressTracker) Add(events int) {
+	t.Increment(int64(events))
+}
+
+func (t *progressTracker) Fail() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.errors++
+	t.UpdateMessage(fmt.Sprintf("%s (%d errors)", t.message, t.errors))
+}
+
+func (t *progressTracker) Done() {
+	t.MarkAsDone()
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// customQueryConfig is one scenario of the -custom-queries file: a name, the
+// window before now it queries as a Go duration, and either a repository
+// method or raw statements keyed by database name.
+type customQueryConfig struct {
+	Name       string            `json:"name"`
+	Window     string            `json:"window"`
+	Method     string            `json:"method"`
+	Statements map[string]string `json:"statements"`
+}
+
+// customQueries loads the -custom-queries file once, or takes the scenarios
+// of the -config file without one; the scenarios are shared by every runner.
+var customQueries = sync.OnceValue(func() []benchmark.CustomQuery {
+	if *queryFile == "" {
+		queries, err := parseCustomQueries(configScenarios())
+		if err != nil {
+			log.Fatalf("--config: %v", err)
+		}
+
+		return queries
+	}
+
+	queries, err := loadCustomQueries(*queryFile)
+	if err != nil {
+		log.Fatalf("--custom-queries: %v", err)
+	}
+
+	return queries
+})
+
+func loadCustomQueries(path string) ([]benchmark.CustomQuery, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom queries file: %w", err)
+	}
+
+	var file struct {
+		Queries []customQueryConfig `json:"queries"`
+	}
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse custom queries file: %w", err)
+	}
+
+	return parseCustomQueries(file.Queries)
+}
+
+func parseCustomQueries(configs []customQueryConfig) ([]benchmark.CustomQuery, error) {
+	queries := make([]benchmark.CustomQuery, 0, len(configs))
+	seen := make(map[string]bool, len(configs))
+
+	for _, c := range configs {
+		q, err := c.parse()
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[q.Name] {
+			return nil, fmt.Errorf("duplicate query name %q", q.Name)
+		}
+
+		seen[q.Name] = true
+		queries = append(queries, q)
+	}
+
+	return queries, nil
+}
+
+// parse validates a scenario of the file and converts it for the runner.
+func (c customQueryConfig) parse() (benchmark.CustomQuery, error) {
+	if c.Name == "" {
+		return benchmark.CustomQuery{}, fmt.Errorf("every query needs a name")
+	}
+
+	if slices.Contains(benchmark.ScenarioNames(), c.Name) {
+		return benchmark.CustomQuery{}, fmt.Errorf("query %q: the name is taken by a built-in scenario", c.Name)
+	}
+
+	window, err := time.ParseDuration(c.Window)
+	if err != nil || window <= 0 {
+		return benchmark.CustomQuery{}, fmt.Errorf("query %q: window must be a positive duration such as 6h, got %q", c.Name, c.Window)
+	}
+
+	switch {
+	case (c.Method == "") == (len(c.Statements) == 0):
+		return benchmark.CustomQuery{}, fmt.Errorf("query %q: set either method or statements", c.Name)
+	case c.Method != "" && !benchmark.ValidQueryMethod(c.Method):
+		return benchmark.CustomQuery{}, fmt.Errorf("query %q: unknown method %q, expected one of %s",
+			c.Name, c.Method, strings.Join(benchmark.QueryMethods(), ", "))
+	}
+
+	return benchmark.CustomQuery{Name: c.Name, Window: window, Method: c.Method, Statements: c.Statements}, nil
+}
+
+// scenarioFilter parses -scenarios and -skip-scenarios once. Both take the
+// names of built-in scenarios and of those in the -custom-queries file.
+var scenarioFilter = sync.OnceValue(func() benchmark.ScenarioFilter {
+	known := benchmark.ScenarioNames()
+	for _, q := range customQueries() {
+		known = append(known, q.Name)
+	}
+
+	only, err := parseScenarios(*scenarios, known)
+	if err != nil {
+		log.Fatalf("--scenarios: %v", err)
+	}
+
+	skip, err := parseScenarios(*skipScenarios, known)
+	if err != nil {
+		log.Fatalf("--skip-scenarios: %v", err)
+	}
+
+	return benchmark.ScenarioFilter{Only: only, Skip: skip}
+})
+
+// parseScenarios parses a comma-separated list of scenario names into a set.
+func parseScenarios(s string, known []string) (map[string]bool, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	set := make(map[string]bool)
+
+	for _, field := range strings.Split(s, ",") {
+		name := strings.TrimSpace(field)
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("unknown scenario %q, expected one of %s", name, strings.Join(known, ", "))
+		}
+
+		set[name] = true
+	}
+
+	return set, nil
+}
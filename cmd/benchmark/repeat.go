@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// runRepeated runs the benchmark of a database -repeat times, each run on a
+// fresh schema, and returns the last run with the variance across all of
+// them. The cold cache comparison restarts the database, so it runs after
+// the last run only. A run that fails to start ends the series.
+func runRepeated(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string, reconnect reconnectFunc) *benchmark.Results {
+	var runs []*benchmark.Results
+
+	for i := 1; i <= *repeat && ctx.Err() == nil; i++ {
+		log.Printf("Run %d of %d for %s...", i, *repeat, dbName)
+
+		var rc reconnectFunc
+		if i == *repeat {
+			rc = reconnect
+		}
+
+		res := runOnce(ctx, cfg, runner, dbName, rc)
+		if res.Error != nil {
+			return res
+		}
+
+		runs = append(runs, res)
+	}
+
+	if len(runs) == 0 {
+		return &benchmark.Results{Database: dbName, Error: ctx.Err()}
+	}
+
+	last := runs[len(runs)-1]
+	last.Repeat = benchmark.SummarizeRuns(runs)
+
+	return last
+}
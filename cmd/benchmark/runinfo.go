@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// runID identifies this run in every output format, the exports and the
+// history.
+var runID = benchmark.NewRunID(runStart)
+
+// stampRun adds the run ID, its start and end, the revision of the binary
+// and the effective flags to every result, once the benchmarks are done.
+func stampRun(results map[string]*benchmark.Results) {
+	run := &benchmark.RunInfo{
+		ID:         runID,
+		StartedAt:  runStart,
+		FinishedAt: time.Now(),
+		Revision:   benchmark.Revision(),
+		Config:     effectiveConfig(),
+	}
+
+	for _, res := range results {
+		res.Run = run
+	}
+}
+
+// effectiveConfig returns the value of every flag, the defaults included.
+// Connection settings come from the environment and are left out, as they
+// carry passwords.
+func effectiveConfig() map[string]string {
+	config := make(map[string]string)
+
+	flag.VisitAll(func(f *flag.Flag) {
+		config[f.Name] = f.Value.String()
+	})
+
+	return config
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// slos loads the -slo file once, so a malformed file fails the run before
+// any database is benchmarked.
+var slos = sync.OnceValue(func() []benchmark.SLO {
+	if *sloFile == "" {
+		return nil
+	}
+
+	s, err := loadSLOs(*sloFile)
+	if err != nil {
+		log.Fatalf("--slo: %v", err)
+	}
+
+	return s
+})
+
+func loadSLOs(path string) ([]benchmark.SLO, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slo file: %w", err)
+	}
+
+	var file struct {
+		SLOs []string `json:"slos"`
+	}
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse slo file: %w", err)
+	}
+
+	if len(file.SLOs) == 0 {
+		return nil, fmt.Errorf("no slos in %s", path)
+	}
+
+	parsed := make([]benchmark.SLO, 0, len(file.SLOs))
+
+	for _, text := range file.SLOs {
+		s, err := benchmark.ParseSLO(text)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, s)
+	}
+
+	return parsed, nil
+}
+
+// logSLOViolations checks the results against the -slo file, logs every SLO
+// that was not met, and reports whether there was any.
+func logSLOViolations(results map[string]*benchmark.Results) bool {
+	if len(slos()) == 0 {
+		return false
+	}
+
+	violations := benchmark.CheckSLOs(slos(), results)
+	if len(violations) == 0 {
+		log.Printf("All %d SLOs met", len(slos()))
+		return false
+	}
+
+	log.Printf("%d SLO violation(s):", len(violations))
+
+	for _, v := range violations {
+		if v.Reason != "" {
+			log.Printf("  FAIL %s: %s (%s)", v.SLO, v.Reason, v.Database)
+			continue
+		}
+
+		log.Printf("  FAIL %s: %s was %s", v.SLO, v.Database, v.Actual)
+	}
+
+	return true
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// parseWorkerCounts parses the comma-separated worker counts of
+// -sweep-workers. An empty list turns the sweep off.
+func parseWorkerCounts(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var counts []int
+
+	for _, field := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid worker count %q", field)
+		}
+
+		counts = append(counts, n)
+	}
+
+	return counts, nil
+}
+
+// runWorkerSweep runs the insert benchmark of a database at every worker
+// count of -sweep-workers in place of the regular benchmark.
+func runWorkerSweep(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string) *benchmark.Results {
+	counts, err := parseWorkerCounts(*sweepWorkers)
+	if err != nil {
+		return &benchmark.Results{Database: dbName, Error: err}
+	}
+
+	repo, err := newRepo(ctx, dbName, cfg)
+	if err != nil {
+		log.Printf("Failed to initialize %s: %v", dbName, err)
+		return &benchmark.Results{Database: dbName, Error: err}
+	}
+
+	defer func() {
+		if err := repo.Close(); err != nil {
+			log.Printf("Failed to close %s: %v", dbName, err)
+		}
+	}()
+
+	log.Printf("Sweeping insert workers for %s over %v (%d events each)...", dbName, counts, runner.EventCount)
+
+	res := &benchmark.Results{
+		Database:  dbName,
+		Timestamp: time.Now(),
+		Warmup:    runner.Warmup(),
+		Scaling:   runner.RunWorkerSweep(ctx, repo, counts),
+	}
+	res.MarkDegraded()
+	res.Interrupted = ctx.Err() != nil
+
+	return res
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// stopTelemetry flushes and stops the OpenTelemetry providers; nil when
+// -otlp-endpoint is not set.
+var stopTelemetry func(context.Context) error
+
+// startTelemetry installs OpenTelemetry providers that export the traces and
+// metrics of the run over OTLP/HTTP to -otlp-endpoint. The usual OTEL_*
+// environment variables, such as OTEL_EXPORTER_OTLP_HEADERS, apply as well.
+func startTelemetry() {
+	if *otlpEndpoint == "" {
+		return
+	}
+
+	base := strings.TrimSuffix(*otlpEndpoint, "/")
+	res, _ := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", "dbbench")))
+
+	tp, err := newTracerProvider(context.Background(), base, res)
+	if err != nil {
+		log.Fatalf("--otlp-endpoint: %v", err)
+	}
+
+	mp, err := newMeterProvider(context.Background(), base, res)
+	if err != nil {
+		log.Fatalf("--otlp-endpoint: %v", err)
+	}
+
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+
+	stopTelemetry = func(ctx context.Context) error {
+		return errors.Join(tp.Shutdown(ctx), mp.Shutdown(ctx))
+	}
+
+	log.Printf("Exporting OpenTelemetry traces and metrics to %s", *otlpEndpoint)
+}
+
+func newTracerProvider(ctx context.Context, base string, res *resource.Resource) (*sdktrace.TracerProvider, error) {
+	exp, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(base+"/v1/traces"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp), sdktrace.WithResource(res)), nil
+}
+
+func newMeterProvider(ctx context.Context, base string, res *resource.Resource) (*sdkmetric.MeterProvider, error) {
+	exp, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpointURL(base+"/v1/metrics"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric exporter: %w", err)
+	}
+
+	reader := sdkmetric.NewPeriodicReader(exp, sdkmetric.WithInterval(10*time.Second))
+
+	return sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(res)), nil
+}
+
+// withTelemetry traces the benchmark of dbName in a span of its own when the
+// run is exported. The returned func ends the span.
+func withTelemetry(ctx context.Context, dbName string) (context.Context, func()) {
+	if stopTelemetry == nil {
+		return ctx, func() {}
+	}
+
+	ctx, span := otel.Tracer("github.com/skoredin/db-benchmark-suite/cmd/benchmark").
+		Start(benchmark.WithTelemetry(ctx, dbName), "benchmark "+dbName, trace.WithAttributes(attribute.String("db.system.name", dbName)))
+
+	return ctx, func() { span.End() }
+}
+
+// flushTelemetry sends the spans and metrics still buffered before the
+// process exits. A failure is logged and leaves the run itself alone.
+func flushTelemetry() {
+	if stopTelemetry == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := stopTelemetry(ctx); err != nil {
+		log.Printf("Failed to export telemetry: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// parseBatchBounds parses the min-max batch size range of -tune-batch.
+func parseBatchBounds(s string) (lo, hi int, err error) {
+	minText, maxText, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid batch size range %q, want min-max", s)
+	}
+
+	lo, err = strconv.Atoi(strings.TrimSpace(minText))
+	if err != nil || lo <= 0 {
+		return 0, 0, fmt.Errorf("invalid minimum batch size %q", minText)
+	}
+
+	hi, err = strconv.Atoi(strings.TrimSpace(maxText))
+	if err != nil || hi < lo {
+		return 0, 0, fmt.Errorf("invalid maximum batch size %q", maxText)
+	}
+
+	return lo, hi, nil
+}
+
+// validateTuneFlags checks -tune-batch and the modes it cannot be combined
+// with: those run their own insert runs, and a -rate caps the throughput the
+// search compares.
+func validateTuneFlags() {
+	if *tuneBatch == "" {
+		return
+	}
+
+	if _, _, err := parseBatchBounds(*tuneBatch); err != nil {
+		log.Fatalf("--tune-batch: %v", err)
+	}
+
+	if *parity || *durability || *sweepWorkers != "" || *matrixFile != "" || *rate > 0 {
+		log.Fatal("--tune-batch cannot be combined with --parity, --durability-matrix, --sweep-workers, --matrix or --rate")
+	}
+}
+
+// runTunedBenchmark searches the insert batch size of a database within the
+// -tune-batch range, then runs the regular benchmark, or its -repeat runs,
+// at the batch size found.
+func runTunedBenchmark(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string, reconnect reconnectFunc) *benchmark.Results {
+	tuning, err := tuneBatchSize(ctx, cfg, runner, dbName)
+	if err != nil {
+		log.Printf("Failed to initialize %s: %v", dbName, err)
+		return &benchmark.Results{Database: dbName, Error: err}
+	}
+
+	log.Printf("Optimal batch size for %s: %d (%.0f/sec)", dbName, tuning.Optimal, tuning.Throughput)
+
+	tuned := newRunnerWith(runner.EventCount, tuning.Optimal, *workers)
+
+	var res *benchmark.Results
+	if *repeat > 1 {
+		res = runRepeated(ctx, cfg, tuned, dbName, reconnect)
+	} else {
+		res = runOnce(ctx, cfg, tuned, dbName, reconnect)
+	}
+
+	res.BatchTuning = tuning
+
+	return res
+}
+
+// tuneBatchSize runs the batch size search on its own connection.
+func tuneBatchSize(ctx context.Context, cfg *config.Config, runner *benchmark.Runner, dbName string) (*benchmark.BatchTuningResult, error) {
+	lo, hi, err := parseBatchBounds(*tuneBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := newRepo(ctx, dbName, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := repo.Close(); err != nil {
+			log.Printf("Failed to close %s: %v", dbName, err)
+		}
+	}()
+
+	log.Printf("Tuning the insert batch size of %s within %d-%d...", dbName, lo, hi)
+
+	return runner.TuneBatchSize(ctx, repo, lo, hi), nil
+}
@@ -0,0 +1,51 @@
+package benchmark
+
+import (
+	"log"
+	"sync/atomic"
+)
+
+// minGuardOps is the number of operations an errorGuard waits for before it
+// judges the error rate, so one early failure cannot abort a run.
+const minGuardOps = 10
+
+// errorGuard aborts a workload once more than maxPct percent of its
+// operations have failed. It is safe for concurrent use, and a nil guard
+// never aborts.
+type errorGuard struct {
+	maxPct   float64
+	ops      atomic.Int64
+	failures atomic.Int64
+	tripped  atomic.Bool
+}
+
+func newErrorGuard(maxPct float64) *errorGuard {
+	if maxPct <= 0 {
+		return nil
+	}
+
+	return &errorGuard{maxPct: maxPct}
+}
+
+// observe records the outcome of one operation and reports whether the
+// workload should stop.
+func (g *errorGuard) observe(failed bool) bool {
+	if g == nil {
+		return false
+	}
+
+	ops, failures := g.ops.Add(1), g.failures.Load()
+	if failed {
+		failures = g.failures.Add(1)
+	}
+
+	if ops >= minGuardOps && float64(failures)*100 > g.maxPct*float64(ops) && g.tripped.CompareAndSwap(false, true) {
+		log.Printf("Aborting: %d of %d operations failed, over the %.1f%% error rate limit", failures, ops, g.maxPct)
+	}
+
+	return g.tripped.Load()
+}
+
+func (g *errorGuard) aborted() bool {
+	return g != nil && g.tripped.Load()
+}
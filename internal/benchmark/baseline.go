@@ -0,0 +1,184 @@
+package benchmark
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultRegressionPct is the regression threshold of every metric kind the
+// thresholds do not set.
+const DefaultRegressionPct = 10.0
+
+// Metric kinds of a baseline comparison. Throughput regresses when it
+// drops, latency and storage when they grow.
+const (
+	KindThroughput = "throughput"
+	KindLatency    = "latency"
+	KindStorage    = "storage"
+)
+
+// RegressionThresholds are the changes, in percent for the worse, past which
+// a metric counts as regressed, by metric kind.
+type RegressionThresholds map[string]float64
+
+// ParseRegressionThresholds parses a comma-separated list of thresholds in
+// percent. A bare number sets every kind, kind=number sets one kind, and
+// later entries override earlier ones: "10,storage=25".
+func ParseRegressionThresholds(s string) (RegressionThresholds, error) {
+	t := RegressionThresholds{KindThroughput: DefaultRegressionPct, KindLatency: DefaultRegressionPct, KindStorage: DefaultRegressionPct}
+
+	for _, field := range strings.Split(s, ",") {
+		kind, pct, scoped, err := parseRegressionThreshold(field)
+		if err != nil {
+			return nil, err
+		}
+
+		switch _, known := t[kind]; {
+		case !scoped:
+			for k := range t {
+				t[k] = pct
+			}
+		case known:
+			t[kind] = pct
+		default:
+			return nil, fmt.Errorf("invalid threshold %q: kind must be throughput, latency or storage", field)
+		}
+	}
+
+	return t, nil
+}
+
+// parseRegressionThreshold parses one threshold of the list; scoped is set
+// when it names a kind.
+func parseRegressionThreshold(field string) (kind string, pct float64, scoped bool, err error) {
+	kind, value, scoped := strings.Cut(strings.TrimSpace(field), "=")
+	if !scoped {
+		kind, value = "", kind
+	}
+
+	pct, err = strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil || pct < 0 {
+		return "", 0, false, fmt.Errorf("invalid threshold %q: want a non-negative percentage", field)
+	}
+
+	return kind, pct, scoped, nil
+}
+
+// BaselineDelta is the change of one metric of a database against the
+// baseline run.
+type BaselineDelta struct {
+	Metric    string  `json:"metric"` // e.g. "insert throughput", "1_day P95"
+	Kind      string  `json:"kind"`
+	Baseline  float64 `json:"baseline"` // events/sec, nanoseconds or bytes by kind
+	Current   float64 `json:"current"`
+	ChangePct float64 `json:"change_pct"` // positive when the metric grew
+	Threshold float64 `json:"threshold"`
+	Regressed bool    `json:"regressed,omitempty"`
+}
+
+// CompareBaseline sets the deltas of each result in current against the
+// result of the same name in baseline. Metrics are compared when both runs
+// have them: insert throughput and P95, the P95 of each query scenario, and
+// the total storage size. Results without a baseline, and failed or
+// interrupted runs on either side, are left alone.
+func CompareBaseline(baseline, current map[string]*Results, t RegressionThresholds) {
+	for name, res := range current {
+		base := baseline[name]
+		if !comparableRun(base) || !comparableRun(res) {
+			continue
+		}
+
+		res.Baseline = nil
+
+		for _, m := range baselineMetrics(res) {
+			if before, ok := metricOf(base, m); ok {
+				res.Baseline = append(res.Baseline, newBaselineDelta(m, before, m.value, t[m.kind]))
+			}
+		}
+	}
+}
+
+// Regressions returns the deltas of res that crossed their threshold.
+func (r *Results) Regressions() []BaselineDelta {
+	var regressed []BaselineDelta
+
+	for _, d := range r.Baseline {
+		if d.Regressed {
+			regressed = append(regressed, d)
+		}
+	}
+
+	return regressed
+}
+
+func comparableRun(res *Results) bool {
+	return res != nil && res.ErrorText == "" && res.Error == nil && !res.Interrupted
+}
+
+// baselineMetric is one compared metric of a result, with its value there.
+type baselineMetric struct {
+	name, kind string
+	value      float64
+}
+
+// baselineMetrics lists the compared metrics res has, in report order.
+func baselineMetrics(res *Results) []baselineMetric {
+	var metrics []baselineMetric
+
+	if ir := res.Insert; ir != nil && !ir.Interrupted {
+		metrics = append(metrics, baselineMetric{"insert throughput", KindThroughput, ir.Throughput})
+
+		if ir.LatencyP95 > 0 {
+			metrics = append(metrics, baselineMetric{"insert P95", KindLatency, float64(ir.LatencyP95)})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(res.Queries)) {
+		if qr := res.Queries[name]; qr.ErrorText == "" && qr.Iterations > 0 && !qr.Interrupted {
+			metrics = append(metrics, baselineMetric{name + " P95", KindLatency, float64(qr.P95Duration)})
+		}
+	}
+
+	if res.Storage != nil && res.Storage.TotalSize > 0 {
+		metrics = append(metrics, baselineMetric{"storage size", KindStorage, float64(res.Storage.TotalSize)})
+	}
+
+	return metrics
+}
+
+// Metric returns the value and kind of the named metric of r, one of those
+// a baseline comparison covers, e.g. "insert throughput", "1_day P95" or
+// "storage size". ok is false when r does not have the metric.
+func (r *Results) Metric(name string) (value float64, kind string, ok bool) {
+	for _, m := range baselineMetrics(r) {
+		if m.name == name {
+			return m.value, m.kind, true
+		}
+	}
+
+	return 0, "", false
+}
+
+// metricOf looks up the value of the metric m in the baseline result base.
+func metricOf(base *Results, m baselineMetric) (float64, bool) {
+	value, _, ok := base.Metric(m.name)
+
+	return value, ok && value > 0
+}
+
+func newBaselineDelta(m baselineMetric, before, after, threshold float64) BaselineDelta {
+	d := BaselineDelta{Metric: m.name, Kind: m.kind, Baseline: before, Current: after, Threshold: threshold}
+	d.ChangePct = (after - before) / before * 100
+
+	worse := d.ChangePct
+	if m.kind == KindThroughput {
+		worse = -worse
+	}
+
+	d.Regressed = worse > threshold
+
+	return d
+}
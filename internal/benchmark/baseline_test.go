@@ -0,0 +1,83 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRegressionThresholds(t *testing.T) {
+	th, err := ParseRegressionThresholds("10")
+	require.NoError(t, err)
+	assert.Equal(t, RegressionThresholds{KindThroughput: 10, KindLatency: 10, KindStorage: 10}, th)
+
+	th, err = ParseRegressionThresholds("5, latency=20%,storage=0")
+	require.NoError(t, err)
+	assert.Equal(t, RegressionThresholds{KindThroughput: 5, KindLatency: 20, KindStorage: 0}, th)
+
+	for _, s := range []string{"", "fast", "-5", "p95=10", "latency="} {
+		_, err := ParseRegressionThresholds(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestCompareBaseline(t *testing.T) {
+	run := func(throughput float64, p95 time.Duration, size int64) *Results {
+		return &Results{
+			Insert:  &InsertResult{Throughput: throughput, LatencyP95: 4 * time.Millisecond},
+			Queries: map[string]*QueryResult{"1_day": {Iterations: 10, P95Duration: p95}, "join": {ErrorText: "not supported"}},
+			Storage: &repository.StorageStats{TotalSize: size},
+		}
+	}
+
+	baseline := map[string]*Results{"postgres": run(100_000, 10*time.Millisecond, 1000)}
+	current := map[string]*Results{
+		"postgres":   run(85_000, 11*time.Millisecond, 1300),
+		"clickhouse": run(500_000, time.Millisecond, 100),
+	}
+
+	CompareBaseline(baseline, current, RegressionThresholds{KindThroughput: 10, KindLatency: 15, KindStorage: 50})
+
+	assert.Empty(t, current["clickhouse"].Baseline, "no baseline to compare with")
+
+	deltas := current["postgres"].Baseline
+	require.Len(t, deltas, 4)
+	assert.Equal(t, []string{"insert throughput", "insert P95", "1_day P95", "storage size"},
+		[]string{deltas[0].Metric, deltas[1].Metric, deltas[2].Metric, deltas[3].Metric})
+	assert.InDelta(t, -15, deltas[0].ChangePct, 0.001)
+	assert.True(t, deltas[0].Regressed, "throughput dropped past 10%")
+	assert.InDelta(t, 10, deltas[2].ChangePct, 0.001)
+	assert.False(t, deltas[2].Regressed, "latency grew within 15%")
+	assert.False(t, deltas[3].Regressed, "storage grew within 50%")
+
+	regressions := current["postgres"].Regressions()
+	require.Len(t, regressions, 1)
+	assert.Equal(t, "insert throughput", regressions[0].Metric)
+}
+
+func TestCompareBaselineSkipsFailedRuns(t *testing.T) {
+	baseline := map[string]*Results{"postgres": {ErrorText: "connection refused"}}
+	current := map[string]*Results{"postgres": {Insert: &InsertResult{Throughput: 1}}}
+
+	CompareBaseline(baseline, current, RegressionThresholds{})
+
+	assert.Nil(t, current["postgres"].Baseline)
+}
+
+func TestResultsMetric(t *testing.T) {
+	res := &Results{
+		Insert:  &InsertResult{Throughput: 1234},
+		Queries: map[string]*QueryResult{"1_day": {Iterations: 3, P95Duration: time.Millisecond}},
+	}
+
+	value, kind, ok := res.Metric("1_day P95")
+	require.True(t, ok)
+	assert.Equal(t, KindLatency, kind)
+	assert.InDelta(t, float64(time.Millisecond), value, 0)
+
+	_, _, ok = res.Metric("storage size")
+	assert.False(t, ok)
+}
@@ -0,0 +1,67 @@
+package benchmark
+
+import "context"
+
+// RunCacheComparison runs the query scenarios against fresh, a connection to
+// the database after a restart: first once each with no warmup, on whatever
+// the engine loads from disk, then as in RunQueries, with the warmup. The
+// sampled scenarios draw from the events inserted through repo.
+func (r *Runner) RunCacheComparison(ctx context.Context, repo, fresh Repository) *CacheResult {
+	sample := r.sampleFor(repo)
+
+	r.samplesMu.Lock()
+	r.samples[fresh] = sample
+	r.samplesMu.Unlock()
+
+	defer func() {
+		r.samplesMu.Lock()
+		delete(r.samples, fresh)
+		r.samplesMu.Unlock()
+	}()
+
+	cold := r.settings()
+	cold.QueryIterations = 1
+	cold.WarmupIterations = 0
+	cold.samples = map[Repository]*eventSample{fresh: sample}
+
+	return &CacheResult{
+		Cold: cold.RunQueries(ctx, fresh),
+		Warm: r.RunQueries(ctx, fresh),
+	}
+}
+
+// settings returns a runner with the settings of r and none of its state:
+// no sampled events and no replay offsets.
+func (r *Runner) settings() *Runner {
+	return &Runner{
+		EventCount:       r.EventCount,
+		BatchSize:        r.BatchSize,
+		Workers:          r.Workers,
+		QueryIterations:  r.QueryIterations,
+		QueryWorkers:     r.QueryWorkers,
+		Scenarios:        r.Scenarios,
+		Verify:           r.Verify,
+		WarmupIterations: r.WarmupIterations,
+		WarmupBatches:    r.WarmupBatches,
+		PreloadCount:     r.PreloadCount,
+		RampUp:           r.RampUp,
+		DuplicatePct:     r.DuplicatePct,
+		UserDist:         r.UserDist,
+		TimeDist:         r.TimeDist,
+		IDFormat:         r.IDFormat,
+		TenantCount:      r.TenantCount,
+		TenantDist:       r.TenantDist,
+		Dataset:          r.Dataset,
+		UserCount:        r.UserCount,
+		TransactionCount: r.TransactionCount,
+		ReadAfterWrite:   r.ReadAfterWrite,
+		Rate:             r.Rate,
+		SteadyState:      r.SteadyState,
+		QueryRate:        r.QueryRate,
+		MaxErrorRate:     r.MaxErrorRate,
+		OpTimeout:        r.OpTimeout,
+		Retries:          r.Retries,
+		RetryBackoff:     r.RetryBackoff,
+		CustomQueries:    r.CustomQueries,
+	}
+}
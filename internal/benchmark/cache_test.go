@@ -0,0 +1,59 @@
+package benchmark
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCacheComparison(t *testing.T) {
+	repo := &lookupMockRepository{}
+	fresh := &lookupMockRepository{}
+	runner := &Runner{EventCount: 50, BatchSize: 10, Workers: 1, QueryIterations: 4, WarmupIterations: 2}
+
+	runner.RunInsert(context.Background(), repo)
+
+	res := runner.RunCacheComparison(context.Background(), repo, fresh)
+
+	require.NotNil(t, res)
+
+	cold, ok := res.Cold["point_lookup"]
+	require.True(t, ok, "sampled scenarios use the events inserted through repo")
+	assert.Equal(t, 1, cold.Iterations)
+	assert.Equal(t, 1, res.Cold["1_day"].Iterations)
+	assert.Equal(t, 4, res.Warm["1_day"].Iterations)
+	assert.Equal(t, 4, res.Warm["point_lookup"].Iterations)
+	assert.Len(t, fresh.lookup, 1+2+4)
+	assert.Empty(t, repo.lookup)
+
+	runner.samplesMu.Lock()
+	defer runner.samplesMu.Unlock()
+
+	assert.NotContains(t, runner.samples, Repository(fresh))
+}
+
+func TestRunCacheComparisonSettings(t *testing.T) {
+	repo := &lookupMockRepository{}
+	fresh := &lookupMockRepository{}
+	runner := &Runner{
+		EventCount:       50,
+		BatchSize:        10,
+		Workers:          1,
+		QueryIterations:  3,
+		WarmupIterations: 1,
+		Scenarios:        ScenarioFilter{Only: map[string]bool{"1_hour": true, "point_lookup": true}},
+	}
+
+	runner.RunInsert(context.Background(), repo)
+
+	res := runner.RunCacheComparison(context.Background(), repo, fresh)
+
+	assert.Equal(t, []string{"1_hour", "point_lookup"}, slices.Sorted(maps.Keys(res.Cold)))
+	assert.Equal(t, slices.Sorted(maps.Keys(res.Warm)), slices.Sorted(maps.Keys(res.Cold)))
+	assert.Equal(t, 1, res.Cold["1_hour"].Iterations)
+	assert.Equal(t, 3, res.Warm["1_hour"].Iterations)
+}
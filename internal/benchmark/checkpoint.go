@@ -0,0 +1,163 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// checkpointBatches is the number of batches a resumable preload inserts
+// between two checkpoints, which bounds the work a crash can lose.
+const checkpointBatches = 100
+
+// preloadCheckpoint is the progress of a resumable preload as stored in its
+// checkpoint file. Completed counts the events of the finished chunks,
+// inserted or failed; a resume starts after them.
+type preloadCheckpoint struct {
+	Preload   int       `json:"preload"`
+	BatchSize int       `json:"batch_size"`
+	Completed int       `json:"completed"`
+	Inserted  int64     `json:"inserted"`
+	Errors    int64     `json:"errors"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// loadCheckpoint reads the checkpoint at path. A missing file, or one left
+// by a preload of another size or batch size, gives a fresh checkpoint.
+func (r *Runner) loadCheckpoint(path string) (*preloadCheckpoint, error) {
+	fresh := &preloadCheckpoint{Preload: r.PreloadCount, BatchSize: r.BatchSize}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fresh, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read preload checkpoint: %w", err)
+	}
+
+	var cp preloadCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse preload checkpoint: %w", err)
+	}
+
+	if cp.Preload != r.PreloadCount || cp.BatchSize != r.BatchSize {
+		log.Printf("Ignoring preload checkpoint %s: it was written for %d events in batches of %d", path, cp.Preload, cp.BatchSize)
+		return fresh, nil
+	}
+
+	return &cp, nil
+}
+
+// save writes the checkpoint to a temporary file and renames it over path,
+// so a crash mid-write leaves the previous checkpoint intact.
+func (cp *preloadCheckpoint) save(path string) error {
+	cp.UpdatedAt = time.Now()
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to encode preload checkpoint: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write preload checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace preload checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// CanResumePreload reports whether the checkpoint at path holds progress of
+// an unfinished preload of this runner's size; the schema must then be kept
+// for the resume instead of being recreated.
+func (r *Runner) CanResumePreload(path string) bool {
+	if path == "" || r.PreloadCount <= 0 {
+		return false
+	}
+
+	cp, err := r.loadCheckpoint(path)
+
+	return err == nil && cp.Completed > 0
+}
+
+// PreloadResumable is Preload that inserts in chunks of checkpointBatches
+// batches and records the progress in path after each one. It resumes after
+// the last recorded chunk, so a crash re-inserts at most the chunk that was
+// in flight, and removes the checkpoint once the preload is complete. An
+// empty path falls back to Preload.
+func (r *Runner) PreloadResumable(ctx context.Context, repo Repository, path string) error {
+	if path == "" || r.PreloadCount <= 0 {
+		return r.Preload(ctx, repo)
+	}
+
+	cp, err := r.loadCheckpoint(path)
+	if err != nil {
+		return err
+	}
+
+	if cp.Completed > 0 {
+		log.Printf("Resuming preload at %d / %d events from %s", cp.Completed, r.PreloadCount, path)
+	}
+
+	if err := r.preloadChunks(ctx, repo, cp, path); err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return finishPreload(cp, path)
+}
+
+// finishPreload removes the checkpoint at path of a complete preload and
+// fails it when every batch errored.
+func finishPreload(cp *preloadCheckpoint, path string) error {
+	log.Printf("Preload complete: %d events inserted, %d errors", cp.Inserted, cp.Errors)
+
+	if err := os.Remove(path); err != nil {
+		log.Printf("Failed to remove preload checkpoint: %v", err)
+	}
+
+	if cp.Errors > 0 && cp.Inserted == 0 {
+		return fmt.Errorf("preload failed: all %d batches errored", cp.Errors)
+	}
+
+	return nil
+}
+
+// preloadChunks inserts the rest of the preload a chunk at a time and saves
+// the checkpoint after each complete chunk. A chunk cut short by
+// cancellation is not recorded, so a resume inserts it again.
+func (r *Runner) preloadChunks(ctx context.Context, repo Repository, cp *preloadCheckpoint, path string) error {
+	for cp.Completed < r.PreloadCount && ctx.Err() == nil {
+		n := min(checkpointBatches*r.BatchSize, r.PreloadCount-cp.Completed)
+		inserted, errors := r.parallelInsert(ctx, repo, r.Workers, n, quietProgress{}, nil)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		cp.Completed, cp.Inserted, cp.Errors = cp.Completed+n, cp.Inserted+inserted, cp.Errors+errors
+		if err := cp.save(path); err != nil {
+			return err
+		}
+
+		log.Printf("Preload progress: %d / %d events, checkpoint saved", cp.Completed, r.PreloadCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package benchmark
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreloadResumable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "postgres.preload.json")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var inserted int64
+
+	mock := &mockRepository{
+		insertBatchFunc: func(_ context.Context, events []generator.Event) error {
+			if atomic.AddInt64(&inserted, int64(len(events))) >= 1200 {
+				cancel()
+			}
+
+			return nil
+		},
+	}
+
+	runner := &Runner{PreloadCount: 2500, BatchSize: 10, Workers: 2}
+
+	require.NoError(t, runner.PreloadResumable(ctx, mock, path))
+	assert.True(t, runner.CanResumePreload(path))
+
+	cp, err := runner.loadCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, cp.Completed, "only the first full chunk is recorded")
+	assert.Equal(t, int64(1000), cp.Inserted)
+
+	atomic.StoreInt64(&inserted, 0)
+	mock.insertBatchFunc = func(_ context.Context, events []generator.Event) error {
+		atomic.AddInt64(&inserted, int64(len(events)))
+		return nil
+	}
+
+	require.NoError(t, runner.PreloadResumable(context.Background(), mock, path))
+	assert.Equal(t, int64(1500), atomic.LoadInt64(&inserted), "the resume starts after the checkpoint")
+	assert.NoFileExists(t, path)
+	assert.False(t, runner.CanResumePreload(path))
+}
+
+func TestPreloadCheckpointMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "postgres.preload.json")
+
+	cp := &preloadCheckpoint{Preload: 5000, BatchSize: 10, Completed: 1000}
+	require.NoError(t, cp.save(path))
+
+	runner := &Runner{PreloadCount: 2500, BatchSize: 10, Workers: 1}
+	assert.False(t, runner.CanResumePreload(path), "a checkpoint of another preload size is ignored")
+
+	runner.PreloadCount = 5000
+	assert.True(t, runner.CanResumePreload(path))
+	assert.False(t, runner.CanResumePreload(""))
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	assert.False(t, runner.CanResumePreload(path))
+}
@@ -0,0 +1,154 @@
+package benchmark
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+)
+
+// CustomQuery is a query scenario defined in a -custom-queries file rather
+// than built in. It runs either a repository method over its window or, for
+// databases with an entry in Statements, that raw statement.
+type CustomQuery struct {
+	Name       string
+	Window     time.Duration     // the scenario queries the Window before now
+	Method     string            // one of QueryMethods, empty for a statement
+	Statements map[string]string // raw statement per database name
+}
+
+// queryFunc builds the query a custom scenario with a method runs over the
+// window from start to end; ok is false when repo lacks the method.
+type queryFunc func(repo Repository, start, end time.Time) (query func(context.Context) error, ok bool)
+
+// queryMethods are the repository methods a custom scenario can run, keyed
+// by the name used in the file.
+var queryMethods = map[string]queryFunc{
+	"stats": func(repo Repository, start, end time.Time) (func(context.Context) error, bool) {
+		return func(ctx context.Context) error {
+			_, err := repo.GetEventStats(ctx, start, end)
+			return err
+		}, true
+	},
+	"count": func(repo Repository, start, end time.Time) (func(context.Context) error, bool) {
+		counter, ok := repo.(CountRepository)
+
+		return func(ctx context.Context) error {
+			_, err := counter.CountEvents(ctx, start, end)
+			return err
+		}, ok
+	},
+	"top_users": func(repo Repository, start, end time.Time) (func(context.Context) error, bool) {
+		top, ok := repo.(TopUsersRepository)
+
+		return func(ctx context.Context) error {
+			_, err := top.GetTopUsers(ctx, start, end, topUsersLimit)
+			return err
+		}, ok
+	},
+	"user_groups": func(repo Repository, start, end time.Time) (func(context.Context) error, bool) {
+		groups, ok := repo.(UserGroupsRepository)
+
+		return func(ctx context.Context) error {
+			_, err := groups.GetUserGroups(ctx, start, end)
+			return err
+		}, ok
+	},
+	"payload_search": func(repo Repository, start, end time.Time) (func(context.Context) error, bool) {
+		search, ok := repo.(PayloadSearchRepository)
+
+		return func(ctx context.Context) error {
+			_, err := search.SearchPayload(ctx, searchToken, start, end)
+			return err
+		}, ok
+	},
+	"sessions": func(repo Repository, start, end time.Time) (func(context.Context) error, bool) {
+		sessions, ok := repo.(SessionRepository)
+
+		return func(ctx context.Context) error {
+			_, err := sessions.CountSessions(ctx, start, end, sessionGap)
+			return err
+		}, ok
+	},
+	"export": func(repo Repository, start, end time.Time) (func(context.Context) error, bool) {
+		exporter, ok := repo.(ExportRepository)
+
+		return func(ctx context.Context) error {
+			_, err := exporter.ExportEvents(ctx, start, end)
+			return err
+		}, ok
+	},
+}
+
+// QueryMethods returns the method names a custom query scenario can use.
+func QueryMethods() []string {
+	return slices.Sorted(maps.Keys(queryMethods))
+}
+
+// ValidQueryMethod reports whether method names a repository method custom
+// scenarios can run.
+func ValidQueryMethod(method string) bool {
+	_, ok := queryMethods[method]
+	return ok
+}
+
+// RunCustomQueries runs the custom query scenarios against repo, the
+// repository of database db, whose name selects the raw statements. A
+// scenario the database cannot run is reported as not supported.
+func (r *Runner) RunCustomQueries(ctx context.Context, repo Repository, db string) map[string]*QueryResult {
+	results := make(map[string]*QueryResult, len(r.CustomQueries))
+	now := time.Now()
+
+	for _, q := range r.CustomQueries {
+		if !r.Scenarios.selects(q.Name) {
+			continue
+		}
+
+		if qr := r.runCustom(ctx, repo, db, q, now); !qr.skipped() {
+			results[q.Name] = qr
+		}
+	}
+
+	return results
+}
+
+func (r *Runner) runCustom(ctx context.Context, repo Repository, db string, q CustomQuery, now time.Time) *QueryResult {
+	start := now.Add(-q.Window)
+
+	query, ok := q.query(repo, db, start, now)
+	if !ok {
+		return &QueryResult{QueryName: q.Name, ErrorText: "not supported"}
+	}
+
+	res := r.runScenario(ctx, q.Name, query)
+	if res.Iterations > 0 {
+		res.DateRange = dateRange(start, now)
+	}
+
+	return res
+}
+
+// query returns the query the scenario runs on database db, or false when
+// db has neither the method nor a statement it can run.
+func (q CustomQuery) query(repo Repository, db string, start, end time.Time) (func(context.Context) error, bool) {
+	if q.Method != "" {
+		method, ok := queryMethods[q.Method]
+		if !ok {
+			return nil, false
+		}
+
+		return method(repo, start, end)
+	}
+
+	statement, ok := q.Statements[db]
+	runner, canRun := repo.(StatementRepository)
+
+	if !ok || !canRun {
+		return nil, false
+	}
+
+	return func(ctx context.Context) error {
+		_, err := runner.RunStatement(ctx, statement, start, end)
+		return err
+	}, true
+}
@@ -0,0 +1,72 @@
+package benchmark
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statementMockRepository adds RunStatement to mockRepository.
+type statementMockRepository struct {
+	mockRepository
+	statements atomic.Int64
+	last       atomic.Value
+}
+
+func (m *statementMockRepository) RunStatement(_ context.Context, statement string, start, end time.Time) (int64, error) {
+	m.statements.Add(1)
+	m.last.Store(end.Sub(start))
+
+	return int64(len(statement)), nil
+}
+
+func TestRunCustomQueries(t *testing.T) {
+	mock := &statementMockRepository{}
+
+	runner := &Runner{
+		QueryIterations: 4,
+		CustomQueries: []CustomQuery{
+			{Name: "stats_6h", Window: 6 * time.Hour, Method: "stats"},
+			{Name: "count_2d", Window: 48 * time.Hour, Method: "count"},
+			{Name: "by_type", Window: 12 * time.Hour, Statements: map[string]string{"postgres": "SELECT event_type FROM events"}},
+			{Name: "mysql_only", Window: time.Hour, Statements: map[string]string{"mysql": "SELECT 1"}},
+		},
+	}
+
+	results := runner.RunCustomQueries(context.Background(), mock, "postgres")
+	require.Len(t, results, 4)
+
+	assert.Equal(t, 4, results["stats_6h"].Iterations)
+	assert.Equal(t, int64(4), atomic.LoadInt64(&mock.callCount))
+	assert.Equal(t, "not supported", results["count_2d"].ErrorText, "the mock has no count query")
+
+	assert.Equal(t, 4, results["by_type"].Iterations)
+	assert.Equal(t, int64(4), mock.statements.Load())
+	assert.Equal(t, 12*time.Hour, mock.last.Load())
+	assert.NotEmpty(t, results["by_type"].DateRange)
+
+	assert.Equal(t, "not supported", results["mysql_only"].ErrorText, "no statement for this database")
+}
+
+func TestRunCustomQueriesWithoutStatements(t *testing.T) {
+	runner := &Runner{
+		QueryIterations: 1,
+		CustomQueries:   []CustomQuery{{Name: "by_type", Window: time.Hour, Statements: map[string]string{"postgres": "SELECT 1"}}},
+	}
+
+	results := runner.RunCustomQueries(context.Background(), &mockRepository{}, "postgres")
+	assert.Equal(t, "not supported", results["by_type"].ErrorText)
+}
+
+func TestValidQueryMethod(t *testing.T) {
+	for _, method := range QueryMethods() {
+		assert.True(t, ValidQueryMethod(method), method)
+	}
+
+	assert.Contains(t, QueryMethods(), "count")
+	assert.False(t, ValidQueryMethod("drop_table"))
+}
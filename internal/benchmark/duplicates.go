@@ -0,0 +1,69 @@
+package benchmark
+
+import (
+	"math/rand/v2"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// duplicatePoolSize is the number of recently sent events duplicates are
+// drawn from.
+const duplicatePoolSize = 10000
+
+// duplicator replaces a share of every batch with events sent in earlier
+// batches, so inserts hit event IDs that already exist and exercise each
+// database's conflict handling.
+type duplicator struct {
+	pct   int
+	pool  []generator.Event
+	next  int
+	count int64
+}
+
+func newDuplicator(pct int) *duplicator {
+	if pct <= 0 {
+		return nil
+	}
+
+	return &duplicator{pct: min(pct, 100)}
+}
+
+// apply rewrites batch in place. Events of the first batch are never
+// duplicates since nothing has been sent before it.
+func (d *duplicator) apply(batch []generator.Event) {
+	if d == nil {
+		return
+	}
+
+	if len(d.pool) > 0 {
+		for i := range batch {
+			if rand.IntN(100) < d.pct {
+				batch[i] = d.pool[rand.IntN(len(d.pool))]
+				d.count++
+			}
+		}
+	}
+
+	for _, e := range batch {
+		d.remember(e)
+	}
+}
+
+func (d *duplicator) remember(e generator.Event) {
+	if len(d.pool) < duplicatePoolSize {
+		d.pool = append(d.pool, e)
+		return
+	}
+
+	d.pool[d.next] = e
+	d.next = (d.next + 1) % duplicatePoolSize
+}
+
+// duplicates returns the number of events replaced so far.
+func (d *duplicator) duplicates() int64 {
+	if d == nil {
+		return 0
+	}
+
+	return d.count
+}
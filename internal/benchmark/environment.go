@@ -0,0 +1,155 @@
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Environment is the machine and software a result was measured on, so
+// results from different machines can be told apart. The host fields are the
+// same for every database of a run; Driver, ServerVersion and Database
+// describe the database.
+type Environment struct {
+	Host        string `json:"host"`
+	OS          string `json:"os"` // GOOS/GOARCH
+	Kernel      string `json:"kernel,omitempty"`
+	CPUModel    string `json:"cpu_model,omitempty"`
+	Cores       int    `json:"cores"`
+	MemoryBytes int64  `json:"memory_bytes,omitempty"`
+	GoVersion   string `json:"go_version"`
+	// Limits of the container or cgroup the benchmark client runs in.
+	Client *ContainerLimits `json:"client_limits,omitempty"`
+	// Client library of the database as module@version.
+	Driver string `json:"driver,omitempty"`
+	// Version the database server reports, where it reports one.
+	ServerVersion string `json:"server_version,omitempty"`
+	// Connection settings that bear on the results, e.g. the consistency
+	// level, by name.
+	Settings map[string]string `json:"settings,omitempty"`
+	// Container the database runs in, when it runs in one of the suite's.
+	Database *ContainerLimits `json:"database_limits,omitempty"`
+}
+
+// ContainerLimits are the resource limits of a container; a zero field means
+// no limit.
+type ContainerLimits struct {
+	Image       string  `json:"image,omitempty"`
+	CPUs        float64 `json:"cpus,omitempty"`
+	MemoryBytes int64   `json:"memory_bytes,omitempty"`
+}
+
+// HostEnvironment returns the environment of this process, read once. The
+// CPU model, memory, kernel and cgroup limits are only known on Linux.
+var HostEnvironment = sync.OnceValue(func() Environment {
+	return hostEnvironment(os.DirFS("/"))
+})
+
+func hostEnvironment(fsys fs.FS) Environment {
+	host, _ := os.Hostname()
+
+	return Environment{
+		Host:        host,
+		OS:          runtime.GOOS + "/" + runtime.GOARCH,
+		Kernel:      strings.TrimSpace(readFile(fsys, "proc/sys/kernel/osrelease")),
+		CPUModel:    procField(fsys, "proc/cpuinfo", "model name"),
+		Cores:       runtime.NumCPU(),
+		MemoryBytes: memTotal(fsys),
+		GoVersion:   runtime.Version(),
+		Client:      cgroupLimits(fsys),
+	}
+}
+
+func readFile(fsys fs.FS, name string) string {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+// procField returns the value of the first "key: value" line of a /proc
+// file with the given key, or "".
+func procField(fsys fs.FS, name, key string) string {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return ""
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		k, v, ok := strings.Cut(sc.Text(), ":")
+		if ok && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(v)
+		}
+	}
+
+	return ""
+}
+
+// memTotal returns the memory of the host in bytes; /proc/meminfo has it in
+// kilobytes.
+func memTotal(fsys fs.FS) int64 {
+	kb, err := strconv.ParseInt(strings.TrimSuffix(procField(fsys, "proc/meminfo", "MemTotal"), " kB"), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return kb * 1024
+}
+
+// cgroupLimits returns the CPU and memory limits of the cgroup of this
+// process, cgroup v2 first and then v1, or nil when it has none.
+func cgroupLimits(fsys fs.FS) *ContainerLimits {
+	limits := &ContainerLimits{}
+
+	if quota, period, ok := strings.Cut(strings.TrimSpace(readFile(fsys, "sys/fs/cgroup/cpu.max")), " "); ok {
+		limits.CPUs = cpuQuota(quota, period)
+	} else {
+		limits.CPUs = cpuQuota(readFile(fsys, "sys/fs/cgroup/cpu/cpu.cfs_quota_us"), readFile(fsys, "sys/fs/cgroup/cpu/cpu.cfs_period_us"))
+	}
+
+	limits.MemoryBytes = memoryLimit(readFile(fsys, "sys/fs/cgroup/memory.max"))
+	if limits.MemoryBytes == 0 {
+		limits.MemoryBytes = memoryLimit(readFile(fsys, "sys/fs/cgroup/memory/memory.limit_in_bytes"))
+	}
+
+	if limits.CPUs == 0 && limits.MemoryBytes == 0 {
+		return nil
+	}
+
+	return limits
+}
+
+// cpuQuota returns the CPUs a CFS quota and period allow, or 0 for "max",
+// -1 or an unreadable quota.
+func cpuQuota(quota, period string) float64 {
+	q, err := strconv.ParseFloat(strings.TrimSpace(quota), 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+
+	p, err := strconv.ParseFloat(strings.TrimSpace(period), 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+
+	return q / p
+}
+
+// memoryLimit returns a cgroup memory limit in bytes, or 0 for "max" and
+// the near-maximum value cgroup v1 reports for no limit.
+func memoryLimit(s string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || n <= 0 || n >= 1<<62 {
+		return 0
+	}
+
+	return n
+}
@@ -0,0 +1,52 @@
+package benchmark
+
+import (
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostEnvironment(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/sys/kernel/osrelease": {Data: []byte("6.8.0-45-generic\n")},
+		"proc/cpuinfo":              {Data: []byte("processor\t: 0\nvendor_id\t: GenuineIntel\nmodel name\t: Intel(R) Xeon(R) CPU @ 2.20GHz\n")},
+		"proc/meminfo":              {Data: []byte("MemTotal:       16384000 kB\nMemFree:         1024000 kB\n")},
+		"sys/fs/cgroup/cpu.max":     {Data: []byte("200000 100000\n")},
+		"sys/fs/cgroup/memory.max":  {Data: []byte("max\n")},
+	}
+
+	env := hostEnvironment(fsys)
+	assert.Equal(t, runtime.GOOS+"/"+runtime.GOARCH, env.OS)
+	assert.Equal(t, "6.8.0-45-generic", env.Kernel)
+	assert.Equal(t, "Intel(R) Xeon(R) CPU @ 2.20GHz", env.CPUModel)
+	assert.Equal(t, int64(16384000*1024), env.MemoryBytes)
+	assert.Equal(t, runtime.Version(), env.GoVersion)
+	require.NotNil(t, env.Client)
+	assert.InDelta(t, 2.0, env.Client.CPUs, 0.001)
+	assert.Zero(t, env.Client.MemoryBytes)
+}
+
+func TestCgroupLimits(t *testing.T) {
+	assert.Nil(t, cgroupLimits(fstest.MapFS{}), "outside a cgroup")
+	assert.Nil(t, cgroupLimits(fstest.MapFS{
+		"sys/fs/cgroup/cpu.max":    {Data: []byte("max 100000\n")},
+		"sys/fs/cgroup/memory.max": {Data: []byte("max\n")},
+	}), "no limits")
+
+	v1 := cgroupLimits(fstest.MapFS{
+		"sys/fs/cgroup/cpu/cpu.cfs_quota_us":         {Data: []byte("50000\n")},
+		"sys/fs/cgroup/cpu/cpu.cfs_period_us":        {Data: []byte("100000\n")},
+		"sys/fs/cgroup/memory/memory.limit_in_bytes": {Data: []byte("1073741824\n")},
+	})
+	require.NotNil(t, v1)
+	assert.InDelta(t, 0.5, v1.CPUs, 0.001)
+	assert.Equal(t, int64(1<<30), v1.MemoryBytes)
+
+	assert.Nil(t, cgroupLimits(fstest.MapFS{
+		"sys/fs/cgroup/cpu/cpu.cfs_quota_us":         {Data: []byte("-1\n")},
+		"sys/fs/cgroup/memory/memory.limit_in_bytes": {Data: []byte("9223372036854771712\n")},
+	}), "v1 without limits")
+}
@@ -0,0 +1,76 @@
+package benchmark
+
+import (
+	"maps"
+	"math/bits"
+	"slices"
+	"time"
+)
+
+// histogramSubBuckets is the number of equal buckets each power of two of
+// the histogram is split into; a bucket is at most 1/8 of its lower bound
+// wide, so a latency read off it is within 12.5%.
+const histogramSubBuckets = 8
+
+// histogramUnit is the lower bound of the first power of two; faster
+// latencies all count in one bucket below it.
+const histogramUnit = time.Microsecond
+
+// Histogram counts latencies in log-linear buckets, HDR-style: the buckets
+// are narrow for fast latencies and wide for slow ones, with the same
+// relative precision throughout. Unlike percentiles it shows every mode of a
+// multi-modal distribution, e.g. cache hits and misses.
+type Histogram struct {
+	Count   int64             `json:"count"`
+	Buckets []HistogramBucket `json:"buckets"` // the non-empty ones, fastest first
+}
+
+// HistogramBucket counts the latencies from From up to, not including, To.
+type HistogramBucket struct {
+	From  time.Duration `json:"from"`
+	To    time.Duration `json:"to"`
+	Count int64         `json:"count"`
+}
+
+// NewHistogram returns the histogram of durations, or nil when there are none.
+func NewHistogram(durations []time.Duration) *Histogram {
+	if len(durations) == 0 {
+		return nil
+	}
+
+	counts := make(map[time.Duration]int64)
+	for _, d := range durations {
+		counts[histogramBucketStart(d)]++
+	}
+
+	h := &Histogram{Count: int64(len(durations))}
+
+	for _, from := range slices.Sorted(maps.Keys(counts)) {
+		h.Buckets = append(h.Buckets, HistogramBucket{From: from, To: histogramBucketEnd(from), Count: counts[from]})
+	}
+
+	return h
+}
+
+// histogramBucketStart returns the lower bound of the bucket d falls in.
+func histogramBucketStart(d time.Duration) time.Duration {
+	if d < histogramUnit {
+		return 0
+	}
+
+	lower := histogramUnit << (bits.Len64(uint64(d/histogramUnit)) - 1)
+	width := lower / histogramSubBuckets
+
+	return lower + (d-lower)/width*width
+}
+
+// histogramBucketEnd returns the upper bound of the bucket starting at from.
+func histogramBucketEnd(from time.Duration) time.Duration {
+	if from < histogramUnit {
+		return histogramUnit
+	}
+
+	lower := histogramUnit << (bits.Len64(uint64(from/histogramUnit)) - 1)
+
+	return from + lower/histogramSubBuckets
+}
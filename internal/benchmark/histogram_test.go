@@ -0,0 +1,42 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHistogram(t *testing.T) {
+	assert.Nil(t, NewHistogram(nil))
+
+	h := NewHistogram([]time.Duration{
+		500 * time.Nanosecond,
+		10 * time.Millisecond, 10 * time.Millisecond, 9300 * time.Microsecond,
+		100 * time.Millisecond,
+	})
+	require.NotNil(t, h)
+	assert.Equal(t, int64(5), h.Count)
+	require.Len(t, h.Buckets, 3, "10ms and 9.3ms share a bucket")
+
+	assert.Equal(t, HistogramBucket{From: 0, To: time.Microsecond, Count: 1}, h.Buckets[0])
+	assert.Equal(t, int64(3), h.Buckets[1].Count)
+	assert.Equal(t, int64(1), h.Buckets[2].Count)
+
+	for _, b := range h.Buckets[1:] {
+		assert.Less(t, b.From, b.To)
+		assert.LessOrEqual(t, float64(b.To-b.From), float64(b.From)/histogramSubBuckets)
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	for _, d := range []time.Duration{
+		time.Microsecond, 1999 * time.Nanosecond, 3 * time.Millisecond, 1234567 * time.Microsecond, time.Minute,
+	} {
+		from := histogramBucketStart(d)
+		assert.LessOrEqual(t, from, d, d)
+		assert.Less(t, d, histogramBucketEnd(from), d)
+		assert.Equal(t, from, histogramBucketStart(histogramBucketEnd(from)-1), "%s: the bucket ends where the next starts", d)
+	}
+}
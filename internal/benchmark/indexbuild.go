@@ -0,0 +1,44 @@
+package benchmark
+
+import (
+	"context"
+	"time"
+)
+
+// RunIndexBuild builds the secondary indexes the schema deferred past the
+// load and measures the build over the events loaded.
+func (r *Runner) RunIndexBuild(ctx context.Context, repo Repository) *IndexBuildResult {
+	res := &IndexBuildResult{}
+
+	builder, ok := repo.(IndexBuildRepository)
+	if !ok {
+		res.ErrorText = "not supported"
+		return res
+	}
+
+	if err := createIndexes(ctx, builder, res); err != nil {
+		res.ErrorText = err.Error()
+		return res
+	}
+
+	if s := repo.GetStorageStats(ctx); s != nil {
+		res.Rows = s.RowCount
+		res.IndexSize = s.IndexSize
+	}
+
+	if res.Duration > 0 {
+		res.Throughput = float64(res.Rows) / res.Duration.Seconds()
+	}
+
+	return res
+}
+
+// createIndexes builds the deferred indexes and records the duration of the
+// build in res.
+func createIndexes(ctx context.Context, builder IndexBuildRepository, res *IndexBuildResult) error {
+	start := time.Now()
+	err := builder.CreateIndexes(ctx)
+	res.Duration = time.Since(start)
+
+	return err
+}
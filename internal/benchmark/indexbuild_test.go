@@ -0,0 +1,49 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// indexBuildMockRepository adds deferred indexes to mockRepository.
+type indexBuildMockRepository struct {
+	mockRepository
+	built bool
+	err   error
+}
+
+func (m *indexBuildMockRepository) DeferIndexes(bool) {}
+
+func (m *indexBuildMockRepository) CreateIndexes(context.Context) error {
+	m.built = m.err == nil
+	return m.err
+}
+
+func (m *indexBuildMockRepository) GetStorageStats(context.Context) *repository.StorageStats {
+	return &repository.StorageStats{RowCount: 1000, IndexSize: 4096}
+}
+
+func TestRunIndexBuild(t *testing.T) {
+	mock := &indexBuildMockRepository{}
+
+	res := (&Runner{}).RunIndexBuild(context.Background(), mock)
+
+	assert.Empty(t, res.ErrorText)
+	assert.True(t, mock.built)
+	assert.Equal(t, int64(1000), res.Rows)
+	assert.Equal(t, int64(4096), res.IndexSize)
+	assert.Positive(t, res.Throughput)
+}
+
+func TestRunIndexBuildErrors(t *testing.T) {
+	res := (&Runner{}).RunIndexBuild(context.Background(), &mockRepository{})
+	assert.Equal(t, "not supported", res.ErrorText)
+
+	res = (&Runner{}).RunIndexBuild(context.Background(), &indexBuildMockRepository{err: errors.New("disk full")})
+	assert.Equal(t, "disk full", res.ErrorText)
+	assert.Zero(t, res.Rows)
+}
@@ -0,0 +1,201 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// Shape of the read-while-write phase: the number of query loops running
+// next to the insert workers and the range of their stats query.
+const (
+	mixedQueryWorkers = 2
+	mixedQueryRange   = time.Hour
+)
+
+// RunMixed measures query latency under ingest pressure. The one-hour stats
+// query first runs QueryIterations times on the idle database as a baseline;
+// then mixedQueryWorkers query loops run for as long as the insert workers
+// load another EventCount events.
+func (r *Runner) RunMixed(ctx context.Context, repo Repository) *MixedResult {
+	query := func(ctx context.Context) error {
+		now := time.Now()
+		_, err := repo.GetEventStats(ctx, now.Add(-mixedQueryRange), now)
+
+		return err
+	}
+
+	res := &MixedResult{Baseline: r.runScenario(ctx, "idle_1_hour", query)}
+	tl := &timeline{start: time.Now()}
+
+	res.Insert, res.UnderLoad = r.insertWhileQuerying(ctx, repo, query, tl)
+	res.InsertTimeline, res.QueryTimeline = tl.samples()
+
+	return res
+}
+
+// insertWhileQuerying runs the insert workers and the query loops together
+// and stops the query loops once the inserts are done.
+func (r *Runner) insertWhileQuerying(
+	ctx context.Context, repo Repository, query func(context.Context) error, tl *timeline,
+) (*InsertResult, *QueryResult) {
+	done := make(chan struct{})
+	durations := make([][]time.Duration, mixedQueryWorkers)
+	errors := make([]int64, mixedQueryWorkers)
+
+	var wg sync.WaitGroup
+
+	for i := range mixedQueryWorkers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			durations[i], errors[i] = queryUntil(ctx, done, query, tl)
+		}()
+	}
+
+	insert := r.timedInsert(ctx, repo, tl)
+
+	close(done)
+	wg.Wait()
+
+	return insert, newQueryResult("under_load_1_hour", slices.Concat(durations...), sum(errors))
+}
+
+// timedInsert runs the insert workers, recording each batch in tl.
+func (r *Runner) timedInsert(ctx context.Context, repo Repository, tl *timeline) *InsertResult {
+	probe := startClientProbe()
+	inserted, insertErrors := r.parallelInsert(ctx, &timedRepository{Repository: repo, tl: tl}, r.Workers, r.EventCount, quietProgress{}, nil)
+	duration := time.Since(probe.start)
+
+	return &InsertResult{
+		TotalEvents: r.EventCount,
+		Duration:    duration,
+		Throughput:  float64(inserted) / duration.Seconds(),
+		ErrorCount:  insertErrors,
+		BatchSize:   r.BatchSize,
+		WorkerCount: r.Workers,
+		Client:      probe.stop(),
+	}
+}
+
+// queryUntil runs query back to back until done is closed.
+func queryUntil(
+	ctx context.Context, done <-chan struct{}, query func(context.Context) error, tl *timeline,
+) (durations []time.Duration, errors int64) {
+	for {
+		select {
+		case <-done:
+			return durations, errors
+		default:
+		}
+
+		start := time.Now()
+		if err := query(ctx); err != nil {
+			if errors == 0 {
+				log.Printf("Query error under load: %v", err)
+			}
+
+			errors++
+
+			continue
+		}
+
+		d := time.Since(start)
+		durations = append(durations, d)
+		tl.addQuery(d)
+	}
+}
+
+func sum(values []int64) int64 {
+	var total int64
+	for _, v := range values {
+		total += v
+	}
+
+	return total
+}
+
+// timedRepository records every successful insert batch in a timeline.
+type timedRepository struct {
+	Repository
+	tl *timeline
+}
+
+func (t *timedRepository) InsertBatch(ctx context.Context, events []generator.Event) error {
+	if err := t.Repository.InsertBatch(ctx, events); err != nil {
+		return err
+	}
+
+	t.tl.addInsert(len(events))
+
+	return nil
+}
+
+// timeline buckets the inserts and query latencies of the mixed phase by the
+// second since the phase started.
+type timeline struct {
+	mu      sync.Mutex
+	start   time.Time
+	inserts []int64
+	queries [][]time.Duration
+}
+
+func (t *timeline) addInsert(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	i := t.second()
+	t.inserts = grow(t.inserts, i)
+	t.inserts[i] += int64(n)
+}
+
+func (t *timeline) addQuery(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	i := t.second()
+	t.queries = grow(t.queries, i)
+	t.queries[i] = append(t.queries[i], d)
+}
+
+func (t *timeline) second() int {
+	return int(time.Since(t.start) / time.Second)
+}
+
+// grow extends s with zero values until index i is valid.
+func grow[T any](s []T, i int) []T {
+	for len(s) <= i {
+		var zero T
+		s = append(s, zero)
+	}
+
+	return s
+}
+
+func (t *timeline) samples() ([]InsertSample, []QuerySample) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	inserts := make([]InsertSample, len(t.inserts))
+	for i, n := range t.inserts {
+		inserts[i] = InsertSample{Second: i, Events: n}
+	}
+
+	queries := make([]QuerySample, 0, len(t.queries))
+
+	for i, d := range t.queries {
+		if len(d) > 0 {
+			queries = append(queries, QuerySample{
+				Second: i, Queries: len(d), AvgDuration: AvgDuration(d), P95Duration: Percentile(d, 0.95),
+			})
+		}
+	}
+
+	return inserts, queries
+}
@@ -0,0 +1,62 @@
+package benchmark
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMixed(t *testing.T) {
+	mock := &mockRepository{
+		insertBatchFunc: func(context.Context, []generator.Event) error {
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		},
+		getEventStatsFunc: func(context.Context, time.Time, time.Time) ([]repository.EventStats, error) {
+			time.Sleep(time.Millisecond)
+			return nil, nil
+		},
+	}
+	runner := &Runner{EventCount: 200, BatchSize: 10, Workers: 2, QueryIterations: 3}
+
+	res := runner.RunMixed(context.Background(), mock)
+
+	assert.Equal(t, 3, res.Baseline.Iterations)
+	assert.Positive(t, res.UnderLoad.Iterations)
+	assert.Equal(t, 200, res.Insert.TotalEvents)
+	assert.Zero(t, res.Insert.ErrorCount)
+
+	var events int64
+	for _, s := range res.InsertTimeline {
+		events += s.Events
+	}
+
+	assert.Equal(t, int64(200), events)
+
+	var queries int
+	for _, s := range res.QueryTimeline {
+		queries += s.Queries
+	}
+
+	assert.Equal(t, res.UnderLoad.Iterations, queries)
+}
+
+func TestTimelineSamples(t *testing.T) {
+	tl := &timeline{start: time.Now().Add(-2500 * time.Millisecond)}
+	tl.addInsert(10)
+	tl.addInsert(5)
+	tl.addQuery(2 * time.Millisecond)
+
+	inserts, queries := tl.samples()
+	require.Len(t, inserts, 3)
+	assert.Equal(t, InsertSample{Second: 2, Events: 15}, inserts[2])
+	assert.Zero(t, inserts[0].Events)
+	require.Len(t, queries, 1)
+	assert.Equal(t, 2, queries[0].Second)
+	assert.Equal(t, 1, queries[0].Queries)
+}
@@ -0,0 +1,36 @@
+package benchmark
+
+import (
+	"context"
+	"time"
+)
+
+// Observer sees the inserts and queries of one database as they complete,
+// e.g. to export them as live metrics. It is called from all workers at
+// once, for warm-up and preload batches too.
+type Observer interface {
+	Inserted(events int, latency time.Duration) // a batch was inserted
+	InsertFailed()                              // a batch failed every attempt
+	Queried(scenario string, latency time.Duration, err error)
+}
+
+type observerKey struct{}
+
+// WithObserver returns a context whose inserts and queries are reported to o.
+func WithObserver(ctx context.Context, o Observer) context.Context {
+	return context.WithValue(ctx, observerKey{}, o)
+}
+
+func observerFrom(ctx context.Context) Observer {
+	if o, ok := ctx.Value(observerKey{}).(Observer); ok {
+		return o
+	}
+
+	return noObserver{}
+}
+
+type noObserver struct{}
+
+func (noObserver) Inserted(int, time.Duration)          {}
+func (noObserver) InsertFailed()                        {}
+func (noObserver) Queried(string, time.Duration, error) {}
@@ -0,0 +1,70 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingObserver counts what it is told.
+type recordingObserver struct {
+	events, batches, failed atomic.Int64
+
+	mu      sync.Mutex
+	queries map[string]int
+	errors  map[string]int
+}
+
+func (o *recordingObserver) Inserted(events int, _ time.Duration) {
+	o.events.Add(int64(events))
+	o.batches.Add(1)
+}
+
+func (o *recordingObserver) InsertFailed() { o.failed.Add(1) }
+
+func (o *recordingObserver) Queried(scenario string, _ time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err != nil {
+		o.errors[scenario]++
+		return
+	}
+
+	o.queries[scenario]++
+}
+
+func TestObserver(t *testing.T) {
+	var batches atomic.Int64
+
+	mock := &mockRepository{insertBatchFunc: func(context.Context, []generator.Event) error {
+		if batches.Add(1) == 3 {
+			return errors.New("insert failed")
+		}
+
+		return nil
+	}}
+
+	obs := &recordingObserver{queries: map[string]int{}, errors: map[string]int{}}
+	ctx := WithObserver(context.Background(), obs)
+	runner := &Runner{
+		EventCount: 100, BatchSize: 10, Workers: 2, QueryIterations: 4, WarmupBatches: 1,
+		Scenarios: ScenarioFilter{Only: map[string]bool{"1_hour": true}},
+	}
+
+	res := runner.RunInsert(ctx, mock)
+	runner.RunQueries(ctx, mock)
+
+	assert.Equal(t, int64(1), obs.failed.Load())
+	assert.Equal(t, int64(1), res.ErrorCount)
+	assert.Equal(t, int64(100), obs.events.Load(), "the warm-up batch counts, the failed one does not")
+	assert.Equal(t, int64(10), obs.batches.Load())
+	assert.Equal(t, map[string]int{"1_hour": 4}, obs.queries)
+	assert.Empty(t, obs.errors)
+}
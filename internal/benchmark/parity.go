@@ -0,0 +1,156 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+)
+
+// paritySeed seeds the dataset of the parity check; together with a shared
+// reference time it gives every database the same events.
+const paritySeed = 1
+
+// parityRange is an event stats range compared by the parity check, ending
+// at the reference time.
+type parityRange struct {
+	name   string
+	window time.Duration
+}
+
+// parityWindows returns the ranges of the parity check: those of the time
+// range scenarios, then one a day wider than the span of TimeDist, the 90
+// days of the generator by default.
+func (r *Runner) parityWindows() []parityRange {
+	return []parityRange{
+		{"1_hour", time.Hour},
+		{"1_day", 24 * time.Hour},
+		{"1_week", 7 * 24 * time.Hour},
+		{"1_month", 30 * 24 * time.Hour},
+		{"all", r.TimeDist.Span() + 24*time.Hour},
+	}
+}
+
+// RunParity loads EventCount events of the seeded parity dataset, timestamped
+// up to now, into a fresh schema and compares the per event type counts
+// GetEventStats returns for each parity window with the counts of the dataset
+// itself. Databases checked with the same now get the same dataset, so a
+// repository that skews its counts shows up as the odd one out.
+func (r *Runner) RunParity(ctx context.Context, repo Repository, now time.Time) *ParityResult {
+	res := &ParityResult{Events: r.EventCount}
+
+	if err := repo.InitSchema(ctx); err != nil {
+		res.ErrorText = err.Error()
+		return res
+	}
+
+	res.FailedBatches = r.insertParityDataset(ctx, repo, now)
+
+	windows := r.parityWindows()
+
+	for i, expected := range r.parityExpected(now, windows) {
+		if ctx.Err() != nil {
+			break
+		}
+
+		w := &ParityWindow{
+			Name:     windows[i].name,
+			Start:    now.Add(-windows[i].window),
+			End:      now,
+			Expected: expected,
+		}
+		r.countParityWindow(ctx, repo, w)
+		res.Windows = append(res.Windows, w)
+	}
+
+	return res
+}
+
+// insertParityDataset inserts the parity dataset on Workers workers and
+// returns the number of batches that failed after their retries.
+func (r *Runner) insertParityDataset(ctx context.Context, repo Repository, now time.Time) int64 {
+	batches := r.shape(generator.NewSeeded(r.EventCount, r.BatchSize, paritySeed, now)).GenerateContext(ctx)
+
+	var (
+		failed atomic.Int64
+		wg     sync.WaitGroup
+	)
+
+	for range r.Workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for batch := range batches {
+				_, err := r.retrying(ctx, func(ctx context.Context) error { return repo.InsertBatch(ctx, batch) })
+				if err != nil && failed.Add(1) == 1 {
+					log.Printf("Parity dataset insert error: %v", err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return failed.Load()
+}
+
+// parityExpected regenerates the parity dataset and counts its events per
+// event type in each of windows. Both window ends are inclusive, like the
+// BETWEEN of the SQL repositories.
+func (r *Runner) parityExpected(now time.Time, windows []parityRange) []map[string]int64 {
+	expected := make([]map[string]int64, len(windows))
+	for i := range expected {
+		expected[i] = make(map[string]int64)
+	}
+
+	for batch := range r.shape(generator.NewSeeded(r.EventCount, r.BatchSize, paritySeed, now)).Generate() {
+		for _, e := range batch {
+			for i, w := range windows {
+				if !e.CreatedAt.Before(now.Add(-w.window)) && !e.CreatedAt.After(now) {
+					expected[i][e.EventType]++
+				}
+			}
+		}
+	}
+
+	return expected
+}
+
+// countParityWindow sums the GetEventStats counts of w per event type and
+// records the event types whose count differs from the dataset.
+func (r *Runner) countParityWindow(ctx context.Context, repo Repository, w *ParityWindow) {
+	var stats []repository.EventStats
+
+	_, err := r.retrying(ctx, func(ctx context.Context) error {
+		var err error
+		stats, err = repo.GetEventStats(ctx, w.Start, w.End)
+
+		return err
+	})
+	if err != nil {
+		w.ErrorText = err.Error()
+		return
+	}
+
+	w.Counted = make(map[string]int64)
+	for _, s := range stats {
+		w.Counted[s.EventType] += s.Count
+	}
+
+	types := maps.Clone(w.Expected)
+	maps.Copy(types, w.Counted)
+
+	for _, t := range slices.Sorted(maps.Keys(types)) {
+		if w.Expected[t] != w.Counted[t] {
+			w.Mismatched = append(w.Mismatched, t)
+		}
+	}
+}
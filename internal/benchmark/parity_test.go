@@ -0,0 +1,110 @@
+package benchmark
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository keeps the inserted events and answers GetEventStats from
+// them, leaving out events older than skew past the range start to mimic a
+// repository with an off-by-some range filter.
+func memoryRepository(skew time.Duration) *mockRepository {
+	var (
+		mu     sync.Mutex
+		events []generator.Event
+	)
+
+	return &mockRepository{
+		insertBatchFunc: func(_ context.Context, batch []generator.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			events = append(events, batch...)
+
+			return nil
+		},
+		getEventStatsFunc: func(_ context.Context, start, end time.Time) ([]repository.EventStats, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			var stats []repository.EventStats
+
+			for _, e := range events {
+				if !e.CreatedAt.Before(start.Add(skew)) && !e.CreatedAt.After(end) {
+					stats = append(stats, repository.EventStats{EventType: e.EventType, Count: 1})
+				}
+			}
+
+			return stats, nil
+		},
+	}
+}
+
+func TestRunParity(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	runner := &Runner{EventCount: 2000, BatchSize: 100, Workers: 3}
+
+	res := runner.RunParity(context.Background(), memoryRepository(0), now)
+
+	require.Len(t, res.Windows, len(runner.parityWindows()))
+	assert.True(t, res.OK())
+
+	all := res.Windows[len(res.Windows)-1]
+	assert.Equal(t, "all", all.Name)
+	assert.Equal(t, all.Expected, all.Counted)
+
+	var total int64
+	for _, n := range all.Expected {
+		total += n
+	}
+
+	assert.Equal(t, int64(2000), total)
+}
+
+func TestRunParityTimeDist(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	runner := &Runner{
+		EventCount: 2000, BatchSize: 100, Workers: 2,
+		TimeDist: generator.TimeDist{Shape: generator.TimeOrdered, Days: 120, LatePct: 20, MaxLate: 48 * time.Hour},
+	}
+
+	res := runner.RunParity(context.Background(), memoryRepository(0), now)
+	require.True(t, res.OK())
+
+	var total int64
+	for _, n := range res.Windows[len(res.Windows)-1].Expected {
+		total += n
+	}
+
+	assert.Equal(t, int64(2000), total, "the all window should cover the time window and the late events")
+}
+
+func TestRunParityMismatch(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	runner := &Runner{EventCount: 2000, BatchSize: 100, Workers: 2}
+
+	res := runner.RunParity(context.Background(), memoryRepository(12*time.Hour), now)
+
+	require.Len(t, res.Windows, len(runner.parityWindows()))
+	assert.False(t, res.OK())
+	assert.NotEmpty(t, res.Windows[1].Mismatched, "1_day loses its oldest 12 hours")
+}
+
+func TestRunParitySameDataset(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	runner := &Runner{EventCount: 500, BatchSize: 50, Workers: 2}
+
+	first := runner.RunParity(context.Background(), memoryRepository(0), now)
+	second := runner.RunParity(context.Background(), memoryRepository(0), now)
+
+	for i := range first.Windows {
+		assert.Equal(t, first.Windows[i].Expected, second.Windows[i].Expected)
+	}
+}
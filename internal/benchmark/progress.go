@@ -0,0 +1,68 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+)
+
+// Progress follows the insert runs of one database, e.g. as progress bars.
+type Progress interface {
+	// Start follows an insert run of total events, titled after its phase.
+	Start(title string, total int) ProgressTracker
+}
+
+// ProgressTracker follows one insert run. It is called from all of the
+// run's workers at once.
+type ProgressTracker interface {
+	Add(events int) // a batch of events was inserted
+	Fail()          // a batch failed
+	Done()
+}
+
+type progressKey struct{}
+
+// WithProgress returns a context whose preload and measured insert runs
+// report to p in place of the periodic progress log lines.
+func WithProgress(ctx context.Context, p Progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, p)
+}
+
+// startProgress starts following an insert run of total events with the
+// Progress of ctx, or with progress log lines every interval events when
+// ctx has none.
+func startProgress(ctx context.Context, title string, total int, interval int64) ProgressTracker {
+	if p, ok := ctx.Value(progressKey{}).(Progress); ok {
+		return p.Start(title, total)
+	}
+
+	return &logProgress{total: total, interval: interval}
+}
+
+// logProgress logs the inserted count each time it passes a multiple of
+// interval.
+type logProgress struct {
+	total    int
+	interval int64
+	inserted atomic.Int64
+}
+
+func (p *logProgress) Add(events int) {
+	inserted := p.inserted.Add(int64(events))
+	prev := inserted - int64(events)
+
+	if prev/p.interval != inserted/p.interval {
+		log.Printf("Insert progress: %d / %d events", inserted, p.total)
+	}
+}
+
+func (p *logProgress) Fail() {}
+func (p *logProgress) Done() {}
+
+// quietProgress follows the insert runs that report no progress, such as
+// warm-up batches.
+type quietProgress struct{}
+
+func (quietProgress) Add(int) {}
+func (quietProgress) Fail()   {}
+func (quietProgress) Done()   {}
@@ -0,0 +1,69 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingProgress records the insert runs it follows.
+type recordingProgress struct {
+	mu     sync.Mutex
+	titles []string
+	totals []int
+	runs   []*recordingTracker
+}
+
+func (p *recordingProgress) Start(title string, total int) ProgressTracker {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t := &recordingTracker{}
+	p.titles, p.totals, p.runs = append(p.titles, title), append(p.totals, total), append(p.runs, t)
+
+	return t
+}
+
+type recordingTracker struct {
+	events, failed atomic.Int64
+	done           atomic.Bool
+}
+
+func (t *recordingTracker) Add(events int) { t.events.Add(int64(events)) }
+func (t *recordingTracker) Fail()          { t.failed.Add(1) }
+func (t *recordingTracker) Done()          { t.done.Store(true) }
+
+func TestInsertProgress(t *testing.T) {
+	var calls atomic.Int64
+
+	mock := &mockRepository{insertBatchFunc: func(context.Context, []generator.Event) error {
+		if calls.Add(1)%5 == 0 {
+			return errors.New("insert failed")
+		}
+
+		return nil
+	}}
+
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 2, PreloadCount: 50, WarmupBatches: 2}
+	progress := &recordingProgress{}
+	ctx := WithProgress(context.Background(), progress)
+
+	require.NoError(t, runner.Preload(ctx, mock))
+
+	res := runner.RunInsert(ctx, mock)
+
+	require.Len(t, progress.runs, 2, "warm-up batches are not followed")
+	assert.Equal(t, []string{"preload", "insert"}, progress.titles)
+	assert.Equal(t, []int{50, 100}, progress.totals)
+
+	run := progress.runs[1]
+	assert.True(t, run.done.Load())
+	assert.Equal(t, res.ErrorCount, run.failed.Load())
+	assert.Equal(t, int64(100)-10*res.ErrorCount, run.events.Load())
+}
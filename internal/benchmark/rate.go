@@ -0,0 +1,83 @@
+package benchmark
+
+import (
+	"math"
+	"time"
+)
+
+// pacer releases batches on the schedule of a target event rate. The
+// schedule starts with the first batch and does not slip when the database
+// falls behind: late batches are released at once until it catches up, so
+// the achieved throughput shows how far below the target the database is.
+type pacer struct {
+	rate  float64       // events per second
+	ramp  time.Duration // time the rate takes to climb linearly from zero
+	start time.Time
+	sent  int64
+}
+
+func newPacer(rate float64) *pacer {
+	if rate <= 0 {
+		return nil
+	}
+
+	return &pacer{rate: rate}
+}
+
+// wait blocks until the next batch of n events is due and returns the time
+// it was due, its intended start. An unpaced run has no schedule, so a nil
+// pacer returns the zero time at once.
+func (p *pacer) wait(n int) time.Time {
+	if p == nil {
+		return time.Time{}
+	}
+
+	if p.start.IsZero() {
+		p.start = time.Now()
+	}
+
+	due := p.start.Add(p.offset(float64(p.sent)))
+	p.sent += int64(n)
+
+	if d := time.Until(due); d > 0 {
+		time.Sleep(d)
+	}
+
+	return due
+}
+
+// withRamp makes the rate climb linearly from zero to the target over ramp
+// instead of starting at full speed.
+func (p *pacer) withRamp(ramp time.Duration) *pacer {
+	if p != nil {
+		p.ramp = ramp
+	}
+
+	return p
+}
+
+// offset returns when the next event is due, relative to the start, once
+// sent events have been released. During a ramp of T seconds the rate at t is rate*t/T, so
+// rate*t²/(2T) events are due by t; after it, the remaining events follow at
+// the full rate.
+func (p *pacer) offset(sent float64) time.Duration {
+	ramp := p.ramp.Seconds()
+	if rampEvents := p.rate * ramp / 2; sent < rampEvents {
+		return time.Duration(math.Sqrt(2*ramp*sent/p.rate) * float64(time.Second))
+	}
+
+	return time.Duration((sent/p.rate + ramp/2) * float64(time.Second))
+}
+
+// sinceDue returns the latency of an operation that ended at end measured
+// from its intended start rather than from when it actually started. A
+// stalled database delays the operations queued behind the stall, and their
+// service times alone would hide that wait (coordinated omission). ok is
+// false for unpaced operations, which have no intended start.
+func sinceDue(due, end time.Time) (d time.Duration, ok bool) {
+	if due.IsZero() {
+		return 0, false
+	}
+
+	return end.Sub(due), true
+}
@@ -0,0 +1,210 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+)
+
+// Polling of a sampled event: the wait between two reads, and how long after
+// its insert returned the event may take to become visible before it counts
+// as missing.
+const (
+	visibilityPoll    = 5 * time.Millisecond
+	visibilityTimeout = 5 * time.Second
+)
+
+// readBackFunc reports whether event can be read back from the database.
+type readBackFunc func(ctx context.Context, event generator.Event) (bool, error)
+
+// RunReadAfterWrite inserts ReadAfterWrite more batches and reads one random
+// event of each back as soon as its insert returns, polling until the event
+// is visible. It reports how many samples were visible on the first read,
+// only after a delay, or not within visibilityTimeout.
+func (r *Runner) RunReadAfterWrite(ctx context.Context, repo Repository) *ReadAfterWriteResult {
+	res := &ReadAfterWriteResult{Samples: r.ReadAfterWrite}
+
+	read := readBack(repo)
+	if read == nil {
+		res.ErrorText = "not supported"
+		return res
+	}
+
+	gen := r.newGenerator(repo, r.ReadAfterWrite*r.BatchSize, r.BatchSize)
+	r.sampleBatches(ctx, repo, read, gen.GenerateContext(ctx)).fill(res)
+
+	if replayFailed(gen) {
+		res.ErrorCount++
+	}
+
+	return res
+}
+
+// sampleBatches samples the visibility of one event of each batch on the
+// workers until batches is drained.
+func (r *Runner) sampleBatches(
+	ctx context.Context, repo Repository, read readBackFunc, batches <-chan []generator.Event,
+) *visibilitySamples {
+	samples := &visibilitySamples{}
+
+	var wg sync.WaitGroup
+
+	for range r.Workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for batch := range batches {
+				samples.record(ctx, r.sampleVisibility(ctx, repo, read, batch))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return samples
+}
+
+// readBack picks how repo reads a sampled event back: by its full primary
+// key where the repository supports it, otherwise by event ID. It returns
+// nil when repo can do neither.
+func readBack(repo Repository) readBackFunc {
+	switch rb := repo.(type) {
+	case ReadBackRepository:
+		return rb.EventVisible
+	case ExistenceRepository:
+		return func(ctx context.Context, event generator.Event) (bool, error) {
+			return rb.EventExists(ctx, event.ID)
+		}
+	case PointLookupRepository:
+		return func(ctx context.Context, event generator.Event) (bool, error) {
+			_, err := rb.GetEventByID(ctx, event.ID)
+			if errors.Is(err, repository.ErrEventNotFound) {
+				return false, nil
+			}
+
+			return err == nil, err
+		}
+	default:
+		return nil
+	}
+}
+
+// visibility is the outcome of one sample. lag is measured from the return of
+// the insert to the first read that saw the event.
+type visibility struct {
+	visible bool
+	first   bool // visible on the first read
+	lag     time.Duration
+	err     error
+}
+
+// sampleVisibility inserts batch and reads one of its events back until it is
+// visible or visibilityTimeout has passed since the insert returned.
+func (r *Runner) sampleVisibility(ctx context.Context, repo Repository, read readBackFunc, batch []generator.Event) visibility {
+	if _, err := r.retrying(ctx, func(ctx context.Context) error { return repo.InsertBatch(ctx, batch) }); err != nil {
+		return visibility{err: err}
+	}
+
+	return r.awaitVisible(ctx, read, batch[rand.IntN(len(batch))], time.Now())
+}
+
+// awaitVisible reads event back until it is visible or visibilityTimeout has
+// passed since acked, when its insert returned.
+func (r *Runner) awaitVisible(ctx context.Context, read readBackFunc, event generator.Event, acked time.Time) visibility {
+	for first := true; ; first = false {
+		visible, err := r.readOnce(ctx, read, event)
+
+		switch {
+		case err != nil:
+			return visibility{err: err}
+		case visible:
+			return visibility{visible: true, first: first, lag: time.Since(acked)}
+		case time.Since(acked) >= visibilityTimeout:
+			return visibility{}
+		}
+
+		select {
+		case <-time.After(visibilityPoll):
+		case <-ctx.Done():
+			return visibility{err: ctx.Err()}
+		}
+	}
+}
+
+// readOnce reads event back once, bounded by OpTimeout.
+func (r *Runner) readOnce(ctx context.Context, read readBackFunc, event generator.Event) (bool, error) {
+	var visible bool
+
+	err := r.attempt(ctx, func(ctx context.Context) error {
+		var err error
+		visible, err = read(ctx, event)
+
+		return err
+	})
+
+	return visible, err
+}
+
+// visibilitySamples collects the outcomes of the sampling workers.
+type visibilitySamples struct {
+	mu                          sync.Mutex
+	immediate, delayed, missing int64
+	errors                      int64
+	lags                        []time.Duration
+}
+
+// record adds one outcome. Samples cut short by the end of ctx are dropped,
+// and only the first error is logged.
+func (s *visibilitySamples) record(ctx context.Context, v visibility) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case v.err != nil:
+		s.addError(v.err)
+	case !v.visible:
+		s.missing++
+	case v.first:
+		s.immediate++
+	default:
+		s.delayed++
+		s.lags = append(s.lags, v.lag)
+	}
+}
+
+// addError counts a failed sample, logging the first one. The caller holds
+// s.mu.
+func (s *visibilitySamples) addError(err error) {
+	if s.errors == 0 {
+		log.Printf("Read-after-write sample error: %v", err)
+	}
+
+	s.errors++
+}
+
+// fill sets the counts, the shares of the samples read back, and the lag
+// percentiles of the delayed ones on res.
+func (s *visibilitySamples) fill(res *ReadAfterWriteResult) {
+	res.Immediate, res.Delayed, res.Missing, res.ErrorCount = s.immediate, s.delayed, s.missing, s.errors
+
+	if read := s.immediate + s.delayed + s.missing; read > 0 {
+		res.StalePct = float64(s.delayed+s.missing) / float64(read) * 100
+		res.MissingPct = float64(s.missing) / float64(read) * 100
+	}
+
+	res.LagP50 = Percentile(s.lags, 0.50)
+	res.LagP99 = Percentile(s.lags, 0.99)
+	res.LagMax = MaxDuration(s.lags)
+}
@@ -0,0 +1,106 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lagMockRepository makes each inserted event visible lag after its insert,
+// like a replica catching up, and fails every read when fail is set.
+type lagMockRepository struct {
+	mockRepository
+	mu       sync.Mutex
+	inserted map[string]time.Time
+	lag      time.Duration
+	fail     bool
+}
+
+func (m *lagMockRepository) InsertBatch(_ context.Context, events []generator.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.inserted == nil {
+		m.inserted = make(map[string]time.Time)
+	}
+
+	for _, e := range events {
+		m.inserted[e.ID] = time.Now()
+	}
+
+	return nil
+}
+
+func (m *lagMockRepository) EventVisible(_ context.Context, event generator.Event) (bool, error) {
+	if m.fail {
+		return false, errors.New("read timeout")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	at, ok := m.inserted[event.ID]
+
+	return ok && time.Since(at) >= m.lag, nil
+}
+
+func TestRunReadAfterWrite(t *testing.T) {
+	runner := &Runner{BatchSize: 10, Workers: 3, ReadAfterWrite: 20}
+
+	res := runner.RunReadAfterWrite(context.Background(), &lagMockRepository{})
+
+	require.Empty(t, res.ErrorText)
+	assert.Equal(t, 20, res.Samples)
+	assert.Equal(t, int64(20), res.Immediate)
+	assert.Zero(t, res.Delayed)
+	assert.Zero(t, res.StalePct)
+	assert.Zero(t, res.LagMax)
+}
+
+func TestRunReadAfterWriteDelayed(t *testing.T) {
+	runner := &Runner{BatchSize: 5, Workers: 2, ReadAfterWrite: 6}
+
+	res := runner.RunReadAfterWrite(context.Background(), &lagMockRepository{lag: 20 * time.Millisecond})
+
+	assert.Equal(t, int64(6), res.Delayed)
+	assert.Zero(t, res.Immediate)
+	assert.Zero(t, res.Missing)
+	assert.InDelta(t, 100.0, res.StalePct, 0.001)
+	assert.GreaterOrEqual(t, res.LagP50, 20*time.Millisecond)
+	assert.GreaterOrEqual(t, res.LagMax, res.LagP50)
+}
+
+func TestRunReadAfterWriteErrors(t *testing.T) {
+	runner := &Runner{BatchSize: 5, Workers: 2, ReadAfterWrite: 4}
+
+	res := runner.RunReadAfterWrite(context.Background(), &lagMockRepository{fail: true})
+
+	assert.Equal(t, int64(4), res.ErrorCount)
+	assert.Zero(t, res.Immediate+res.Delayed+res.Missing)
+	assert.Zero(t, res.StalePct)
+}
+
+func TestRunReadAfterWriteByEventID(t *testing.T) {
+	mock := &lookupMockRepository{}
+	runner := &Runner{BatchSize: 5, Workers: 2, ReadAfterWrite: 4}
+
+	res := runner.RunReadAfterWrite(context.Background(), mock)
+
+	require.Empty(t, res.ErrorText)
+	assert.Equal(t, int64(4), res.Immediate)
+	assert.Len(t, mock.exists, 4)
+}
+
+func TestRunReadAfterWriteUnsupported(t *testing.T) {
+	runner := &Runner{BatchSize: 5, Workers: 1, ReadAfterWrite: 4}
+
+	res := runner.RunReadAfterWrite(context.Background(), &mockRepository{})
+
+	assert.Equal(t, "not supported", res.ErrorText)
+}
@@ -0,0 +1,109 @@
+package benchmark
+
+import "math"
+
+// Variance summarizes one metric across the repeated runs of a benchmark.
+// StdDev is the sample standard deviation, and CV that deviation relative to
+// the mean, so spreads of metrics with different units compare directly.
+type Variance struct {
+	Runs   int     `json:"runs"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	CV     float64 `json:"cv"`
+}
+
+// NewVariance summarizes the given values. Returns nil for an empty slice.
+func NewVariance(values []float64) *Variance {
+	if len(values) == 0 {
+		return nil
+	}
+
+	v := &Variance{Runs: len(values), Min: values[0], Max: values[0]}
+
+	for _, x := range values {
+		v.Mean += x
+		v.Min = min(v.Min, x)
+		v.Max = max(v.Max, x)
+	}
+
+	v.Mean /= float64(len(values))
+	v.StdDev = sampleStdDev(values, v.Mean)
+
+	if v.Mean != 0 {
+		v.CV = v.StdDev / v.Mean
+	}
+
+	return v
+}
+
+// sampleStdDev returns the sample standard deviation of values around their
+// mean, 0 for a single value.
+func sampleStdDev(values []float64, mean float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	var sq float64
+	for _, x := range values {
+		sq += (x - mean) * (x - mean)
+	}
+
+	return math.Sqrt(sq / float64(len(values)-1))
+}
+
+// RepeatResult contains the run-to-run variance of a benchmark repeated with
+// -repeat: the insert throughput in events per second and the P95 latency of
+// each query scenario in nanoseconds. Runs that failed a metric are left out
+// of its summary, and interrupted runs out of all of them.
+type RepeatResult struct {
+	Runs       int                  `json:"runs"`
+	Throughput *Variance            `json:"throughput,omitempty"`
+	QueryP95   map[string]*Variance `json:"query_p95,omitempty"`
+}
+
+// SummarizeRuns computes the variance of the repeated runs of one database.
+func SummarizeRuns(runs []*Results) *RepeatResult {
+	var (
+		throughput []float64
+		complete   int
+	)
+
+	p95 := make(map[string][]float64)
+
+	for _, run := range runs {
+		if run.Interrupted {
+			continue
+		}
+
+		complete++
+
+		if run.Insert != nil {
+			throughput = append(throughput, run.Insert.Throughput)
+		}
+
+		for name, qr := range run.Queries {
+			if qr.ErrorText == "" && qr.Iterations > 0 {
+				p95[name] = append(p95[name], float64(qr.P95Duration))
+			}
+		}
+	}
+
+	return &RepeatResult{Runs: complete, Throughput: NewVariance(throughput), QueryP95: newVariances(p95)}
+}
+
+// newVariances summarizes the values of each key. Returns nil for an empty
+// map.
+func newVariances(values map[string][]float64) map[string]*Variance {
+	if len(values) == 0 {
+		return nil
+	}
+
+	variances := make(map[string]*Variance, len(values))
+	for name, v := range values {
+		variances[name] = NewVariance(v)
+	}
+
+	return variances
+}
@@ -0,0 +1,51 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVariance(t *testing.T) {
+	assert.Nil(t, NewVariance(nil))
+
+	v := NewVariance([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	require.NotNil(t, v)
+	assert.Equal(t, 8, v.Runs)
+	assert.InDelta(t, 5.0, v.Mean, 1e-9)
+	assert.InDelta(t, 2.138, v.StdDev, 1e-3)
+	assert.InDelta(t, 2.0, v.Min, 1e-9)
+	assert.InDelta(t, 9.0, v.Max, 1e-9)
+	assert.InDelta(t, 0.4276, v.CV, 1e-3)
+
+	single := NewVariance([]float64{3})
+	assert.Zero(t, single.StdDev)
+	assert.Zero(t, single.CV)
+}
+
+func TestSummarizeRuns(t *testing.T) {
+	runs := []*Results{
+		{
+			Insert: &InsertResult{Throughput: 900},
+			Queries: map[string]*QueryResult{
+				"1_day":  {Iterations: 10, P95Duration: 10 * time.Millisecond},
+				"7_days": {ErrorText: "not supported"},
+			},
+		},
+		{
+			Insert:  &InsertResult{Throughput: 1100},
+			Queries: map[string]*QueryResult{"1_day": {Iterations: 10, P95Duration: 20 * time.Millisecond}},
+		},
+	}
+
+	rr := SummarizeRuns(runs)
+
+	assert.Equal(t, 2, rr.Runs)
+	require.NotNil(t, rr.Throughput)
+	assert.InDelta(t, 1000.0, rr.Throughput.Mean, 1e-9)
+	require.Contains(t, rr.QueryP95, "1_day")
+	assert.Equal(t, 15*time.Millisecond, time.Duration(rr.QueryP95["1_day"].Mean))
+	assert.NotContains(t, rr.QueryP95, "7_days")
+}
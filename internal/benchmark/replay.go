@@ -0,0 +1,42 @@
+package benchmark
+
+import (
+	"log"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// replayFrom returns the offset in Dataset of the next count events for
+// repo. Each repository replays the dataset from its start, so every
+// database gets the same events, and each run of it continues where the
+// previous one stopped, so a run does not insert the events of an earlier
+// one again.
+func (r *Runner) replayFrom(repo Repository, count int) int {
+	if t, ok := repo.(*timedRepository); ok {
+		repo = t.Repository
+	}
+
+	r.replayMu.Lock()
+	defer r.replayMu.Unlock()
+
+	if r.replayed == nil {
+		r.replayed = make(map[Repository]int)
+	}
+
+	offset := r.replayed[repo]
+	r.replayed[repo] = offset + count
+
+	return offset
+}
+
+// replayFailed logs the failure of gen to read Dataset, which ended its
+// events early, and reports whether there was one. It is called once the
+// events of gen are drained.
+func replayFailed(gen *generator.Generator) bool {
+	if err := gen.Err(); err != nil {
+		log.Printf("Dataset replay ended early: %v", err)
+		return true
+	}
+
+	return false
+}
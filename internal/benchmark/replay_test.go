@@ -0,0 +1,53 @@
+package benchmark
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingRepository records the IDs of the events inserted into it.
+func recordingRepository(ids *[]string) *mockRepository {
+	var mu sync.Mutex
+
+	return &mockRepository{
+		insertBatchFunc: func(_ context.Context, events []generator.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, e := range events {
+				*ids = append(*ids, e.ID)
+			}
+
+			return nil
+		},
+	}
+}
+
+func TestRunInsertDataset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.csv")
+	require.NoError(t, os.WriteFile(path, []byte("event_id,user_id,event_type,created_at\n"+
+		"a,1,login,1735725600\nb,2,login,1735725601\nc,3,logout,1735725602\n"), 0o600))
+
+	d, err := generator.OpenDataset(path)
+	require.NoError(t, err)
+
+	runner := &Runner{EventCount: 2, BatchSize: 1, Workers: 1, Dataset: d}
+
+	var first, second []string
+
+	repo := recordingRepository(&first)
+	runner.RunInsert(context.Background(), repo)
+	runner.RunInsert(context.Background(), repo)
+	runner.RunInsert(context.Background(), recordingRepository(&second))
+
+	assert.Equal(t, []string{"a", "b", "c", "a_2"}, first, "a second run continues the replay")
+	assert.Equal(t, []string{"a", "b"}, second, "every repository replays the dataset from its start")
+}
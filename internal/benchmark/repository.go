@@ -17,3 +17,176 @@ type Repository interface {
 	Cleanup(ctx context.Context) error
 	Close() error
 }
+
+// RetentionRepository is implemented by repositories that can delete events
+// older than a cutoff for the retention benchmark.
+type RetentionRepository interface {
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (*repository.DeleteStats, error)
+}
+
+// PointLookupRepository is implemented by repositories that can fetch a single
+// event by event_id for the point_lookup scenario.
+type PointLookupRepository interface {
+	GetEventByID(ctx context.Context, eventID string) (*generator.Event, error)
+}
+
+// UserHistoryRepository is implemented by repositories that can return the
+// most recent events of one user for the user_history scenario.
+type UserHistoryRepository interface {
+	GetUserEvents(ctx context.Context, userID int64, limit int) ([]generator.Event, error)
+}
+
+// TopUsersRepository is implemented by repositories that can rank users by
+// event count for the top_users scenario.
+type TopUsersRepository interface {
+	GetTopUsers(ctx context.Context, start, end time.Time, n int) ([]repository.UserCount, error)
+}
+
+// UserGroupsRepository is implemented by repositories that can group events
+// by user_id for the high-cardinality group_by_user scenario.
+type UserGroupsRepository interface {
+	GetUserGroups(ctx context.Context, start, end time.Time) (*repository.UserGroupStats, error)
+}
+
+// PayloadSearchRepository is implemented by repositories that can count the
+// events whose payload contains a token for the payload_search scenario.
+type PayloadSearchRepository interface {
+	SearchPayload(ctx context.Context, token string, start, end time.Time) (int64, error)
+}
+
+// PaginationRepository is implemented by repositories that can read a time
+// range page by page for the pagination scenario, calling page after every
+// page fetched.
+type PaginationRepository interface {
+	ScanPages(ctx context.Context, start, end time.Time, pageSize int, page repository.PageFunc) error
+}
+
+// ExportRepository is implemented by repositories that can stream every
+// event of a time range out of the database for the export scenario,
+// returning the number of events read.
+type ExportRepository interface {
+	ExportEvents(ctx context.Context, start, end time.Time) (int64, error)
+}
+
+// SessionRepository is implemented by repositories that can count user
+// sessions with window functions for the sessions scenario. A session ends
+// after more than gap without events of the user.
+type SessionRepository interface {
+	CountSessions(ctx context.Context, start, end time.Time, gap time.Duration) (int64, error)
+}
+
+// CountRepository is implemented by repositories that can count the events
+// in a time range for the count scenario.
+type CountRepository interface {
+	CountEvents(ctx context.Context, start, end time.Time) (int64, error)
+}
+
+// ExistenceRepository is implemented by repositories that can check whether
+// an event ID is stored for the exists_by_id scenario.
+type ExistenceRepository interface {
+	EventExists(ctx context.Context, eventID string) (bool, error)
+}
+
+// TenantRepository is implemented by repositories that can store the
+// tenant_id of the events, in a schema clustered by tenant, and query the
+// events of one tenant for the tenant-scoped scenarios. Tenants reports
+// whether the configuration gave the schema the tenant_id column.
+type TenantRepository interface {
+	Tenants() bool
+	GetTenantEventStats(ctx context.Context, tenantID int64, start, end time.Time) ([]repository.EventStats, error)
+	GetTenantEvents(ctx context.Context, tenantID int64, limit int) ([]generator.Event, error)
+}
+
+// UsersRepository is implemented by repositories that can store the users
+// dimension table and join events to it for the join scenario.
+type UsersRepository interface {
+	InitUsersSchema(ctx context.Context) error
+	InsertUsers(ctx context.Context, users []generator.User) error
+	GetCountryBreakdown(ctx context.Context, start, end time.Time) ([]repository.CountryCount, error)
+}
+
+// TransactionRepository is implemented by repositories that can write an
+// event and increment its user's counter row in one transaction for the
+// transactional workload.
+type TransactionRepository interface {
+	InitTransactionSchema(ctx context.Context) error
+	InsertEventTx(ctx context.Context, event generator.Event) error
+}
+
+// TTLRepository is implemented by repositories that can expire events by
+// created_at for the TTL benchmark. EnableTTL returns the expiry method;
+// ExpireTTL returns once the database has removed the expired events, forcing
+// it to where the database allows.
+type TTLRepository interface {
+	EnableTTL(ctx context.Context, ttl time.Duration) (method string, err error)
+	ExpireTTL(ctx context.Context) error
+}
+
+// StatementRepository is implemented by repositories that can run a raw
+// statement of their query language for custom query scenarios. The
+// statement binds the start and end of the scenario window as its two
+// parameters; the number of rows it produced is returned.
+type StatementRepository interface {
+	RunStatement(ctx context.Context, statement string, start, end time.Time) (int64, error)
+}
+
+// ReadBackRepository is implemented by repositories that can check whether
+// an event just written is visible to readers by its full primary key, for
+// read-after-write sampling on databases that cannot look an event up by
+// event_id alone.
+type ReadBackRepository interface {
+	EventVisible(ctx context.Context, event generator.Event) (bool, error)
+}
+
+// VersionRepository is implemented by repositories that can report the
+// version of the database server they are connected to.
+type VersionRepository interface {
+	ServerVersion(ctx context.Context) (string, error)
+}
+
+// SettingsRepository is implemented by repositories that can report the
+// settings of their connection that bear on the results, such as the
+// consistency level, by name.
+type SettingsRepository interface {
+	Settings(ctx context.Context) (map[string]string, error)
+}
+
+// PermissionRepository is implemented by repositories that can check that
+// their user may create, write to and drop tables, as the benchmark does,
+// on a scratch table that leaves the events alone.
+type PermissionRepository interface {
+	CheckPermissions(ctx context.Context) error
+}
+
+// SchemaRepository is implemented by repositories that can check that an
+// events table created outside the benchmark has the columns it reads and
+// writes, for runs against existing data with -existing-schema.
+type SchemaRepository interface {
+	VerifySchema(ctx context.Context) error
+}
+
+// DriverRepository is implemented by repositories whose client driver is
+// chosen by the configuration; DriverModule returns the Go module of the
+// one in use.
+type DriverRepository interface {
+	DriverModule() string
+}
+
+// InsertModeRepository is implemented by repositories that can insert a
+// batch in several ways, such as prepared statements or multi-row VALUES,
+// so the ingestion paths of one database can be compared in one run.
+// SetInsertMode switches the open repository to one of InsertModes.
+type InsertModeRepository interface {
+	InsertModes() []string
+	InsertMode() string
+	SetInsertMode(mode string) error
+}
+
+// IndexBuildRepository is implemented by repositories that can create the
+// events table without its secondary indexes, as bulk loads do, and build
+// them on the loaded events. The unique event_id index the inserts rely on
+// to skip duplicates is never deferred.
+type IndexBuildRepository interface {
+	DeferIndexes(deferred bool)
+	CreateIndexes(ctx context.Context) error
+}
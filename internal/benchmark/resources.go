@@ -0,0 +1,77 @@
+package benchmark
+
+import (
+	"runtime"
+	"time"
+)
+
+// clientProbe measures the resources the benchmark process uses from the
+// moment it is started. The process is shared by every database benchmarked
+// at the same time, so concurrent runs count each other's load.
+type clientProbe struct {
+	start time.Time
+	cpu   time.Duration
+	mem   runtime.MemStats
+}
+
+func startClientProbe() *clientProbe {
+	p := &clientProbe{start: time.Now(), cpu: processCPUTime()}
+	runtime.ReadMemStats(&p.mem)
+
+	return p
+}
+
+// stop returns the resources used since the probe was started.
+func (p *clientProbe) stop() *ClientResources {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	res := &ClientResources{
+		Wall:       time.Since(p.start),
+		CPUTime:    processCPUTime() - p.cpu,
+		Cores:      runtime.NumCPU(),
+		PeakRSS:    peakRSS(),
+		Allocs:     mem.Mallocs - p.mem.Mallocs,
+		AllocBytes: mem.TotalAlloc - p.mem.TotalAlloc,
+		GCCycles:   mem.NumGC - p.mem.NumGC,
+		GCPause:    time.Duration(mem.PauseTotalNs - p.mem.PauseTotalNs),
+	}
+	res.CPUPct = cpuPct(res.CPUTime, res.Wall, res.Cores)
+
+	return res
+}
+
+// cpuPct returns cpu as a percentage of the CPU time that cores could have
+// spent in wall.
+func cpuPct(cpu, wall time.Duration, cores int) float64 {
+	if wall <= 0 || cores <= 0 {
+		return 0
+	}
+
+	return float64(cpu) / (float64(wall) * float64(cores)) * 100
+}
+
+// Add returns the resources of c and o together, as if used in one phase
+// lasting both wall times. Either may be nil.
+func (c *ClientResources) Add(o *ClientResources) *ClientResources {
+	switch {
+	case c == nil:
+		return o
+	case o == nil:
+		return c
+	}
+
+	sum := &ClientResources{
+		Wall:       c.Wall + o.Wall,
+		CPUTime:    c.CPUTime + o.CPUTime,
+		Cores:      max(c.Cores, o.Cores),
+		PeakRSS:    max(c.PeakRSS, o.PeakRSS),
+		Allocs:     c.Allocs + o.Allocs,
+		AllocBytes: c.AllocBytes + o.AllocBytes,
+		GCCycles:   c.GCCycles + o.GCCycles,
+		GCPause:    c.GCPause + o.GCPause,
+	}
+	sum.CPUPct = cpuPct(sum.CPUTime, sum.Wall, sum.Cores)
+
+	return sum
+}
@@ -0,0 +1,57 @@
+package benchmark
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientProbe(t *testing.T) {
+	probe := startClientProbe()
+
+	var sink [][]byte
+	for range 1000 {
+		sink = append(sink, make([]byte, 1024))
+	}
+
+	res := probe.stop()
+
+	assert.Len(t, sink, 1000)
+	assert.Positive(t, res.Wall)
+	assert.GreaterOrEqual(t, res.Allocs, uint64(1000))
+	assert.GreaterOrEqual(t, res.AllocBytes, uint64(1000*1024))
+	assert.Positive(t, res.Cores)
+	assert.GreaterOrEqual(t, res.CPUPct, 0.0)
+}
+
+func TestClientResourcesAdd(t *testing.T) {
+	a := &ClientResources{Wall: time.Second, CPUTime: time.Second, Cores: 4, PeakRSS: 100, Allocs: 10, GCPause: time.Millisecond}
+	b := &ClientResources{Wall: time.Second, CPUTime: 3 * time.Second, Cores: 4, PeakRSS: 300, Allocs: 5, GCCycles: 2}
+
+	sum := a.Add(b)
+
+	assert.Equal(t, 2*time.Second, sum.Wall)
+	assert.Equal(t, 4*time.Second, sum.CPUTime)
+	assert.InDelta(t, 50.0, sum.CPUPct, 0.001)
+	assert.Equal(t, int64(300), sum.PeakRSS)
+	assert.Equal(t, uint64(15), sum.Allocs)
+	assert.Equal(t, uint32(2), sum.GCCycles)
+
+	var none *ClientResources
+	assert.Same(t, b, none.Add(b))
+	assert.Same(t, a, a.Add(nil))
+}
+
+func TestRunPhasesRecordClient(t *testing.T) {
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 2, QueryIterations: 3}
+
+	insert := runner.RunInsert(context.Background(), &mockRepository{})
+	require.NotNil(t, insert.Client)
+	assert.GreaterOrEqual(t, insert.Client.Wall, insert.Duration)
+
+	query := runner.runQuery(context.Background(), &mockRepository{}, "1_hour", time.Now().Add(-time.Hour), time.Now())
+	assert.NotNil(t, query.Client)
+}
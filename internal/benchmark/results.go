@@ -2,6 +2,7 @@ package benchmark
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/skoredin/db-benchmark-suite/internal/repository"
@@ -9,13 +10,53 @@ import (
 
 // Results contains all benchmark results for a database
 type Results struct {
-	Database  string                   `json:"database"`
-	Timestamp time.Time                `json:"timestamp"`
-	Insert    *InsertResult            `json:"insert,omitempty"`
-	Queries   map[string]*QueryResult  `json:"queries,omitempty"`
-	Storage   *repository.StorageStats `json:"storage,omitempty"`
-	Error     error                    `json:"-"`
-	ErrorText string                   `json:"error,omitempty"`
+	Database     string                   `json:"database"`
+	Run          *RunInfo                 `json:"run,omitempty"` // the run the results belong to
+	Timestamp    time.Time                `json:"timestamp"`
+	Environment  *Environment             `json:"environment,omitempty"` // machine and versions the run was measured on
+	Indexes      string                   `json:"indexes,omitempty"`     // secondary index set of the schema
+	InsertMode   string                   `json:"insert_mode,omitempty"` // set on databases with several insert modes
+	Params       *RunParams               `json:"params,omitempty"`      // set on the runs of a parameter matrix
+	Warmup       *WarmupConfig            `json:"warmup,omitempty"`
+	Insert       *InsertResult            `json:"insert,omitempty"`
+	Queries      map[string]*QueryResult  `json:"queries,omitempty"`
+	Storage      *repository.StorageStats `json:"storage,omitempty"`
+	Durability   []*DurabilityResult      `json:"durability,omitempty"`
+	InsertModes  []*InsertModeResult      `json:"insert_modes,omitempty"`
+	Scaling      []*ScalingResult         `json:"scaling,omitempty"`
+	BatchTuning  *BatchTuningResult       `json:"batch_tuning,omitempty"`
+	Repeat       *RepeatResult            `json:"repeat,omitempty"` // variance across the runs of -repeat
+	IndexBuild   *IndexBuildResult        `json:"index_build,omitempty"`
+	Retention    *RetentionResult         `json:"retention,omitempty"`
+	TTL          *TTLResult               `json:"ttl,omitempty"`
+	Transactions *TransactionResult       `json:"transactions,omitempty"`
+	Visibility   *ReadAfterWriteResult    `json:"read_after_write,omitempty"`
+	Parity       *ParityResult            `json:"parity,omitempty"`
+	Mixed        *MixedResult             `json:"mixed,omitempty"`
+	Cache        *CacheResult             `json:"cache,omitempty"`
+	Baseline     []BaselineDelta          `json:"baseline,omitempty"`    // changes against the -baseline run
+	Degraded     bool                     `json:"degraded,omitempty"`    // a run was aborted on its error rate
+	Interrupted  bool                     `json:"interrupted,omitempty"` // the benchmark was cancelled and the results are partial
+	Error        error                    `json:"-"`
+	ErrorText    string                   `json:"error,omitempty"`
+}
+
+// RunParams are the insert parameters of one run of a parameter matrix
+type RunParams struct {
+	Events    int `json:"events"`
+	BatchSize int `json:"batch_size"`
+	Workers   int `json:"workers"`
+}
+
+func (p RunParams) String() string {
+	return fmt.Sprintf("events=%d batch=%d workers=%d", p.Events, p.BatchSize, p.Workers)
+}
+
+// WarmupConfig is the unmeasured work done before the measurements; its
+// samples are discarded
+type WarmupConfig struct {
+	QueryIterations int `json:"query_iterations"` // runs of each query scenario
+	InsertBatches   int `json:"insert_batches"`   // batches inserted before each insert run
 }
 
 // MarshalJSON implements json.Marshaler to serialize the Error field as a string.
@@ -30,6 +71,30 @@ func (r *Results) MarshalJSON() ([]byte, error) {
 	return json.Marshal(a)
 }
 
+// MarkDegraded flags the results as degraded when any of their insert or
+// query runs was aborted on its error rate.
+func (r *Results) MarkDegraded() {
+	aborted := r.Insert != nil && r.Insert.Aborted
+
+	for _, qr := range r.Queries {
+		aborted = aborted || qr.Aborted
+	}
+
+	for _, dr := range r.Durability {
+		aborted = aborted || (dr.Insert != nil && dr.Insert.Aborted)
+	}
+
+	for _, mr := range r.InsertModes {
+		aborted = aborted || (mr.Insert != nil && mr.Insert.Aborted)
+	}
+
+	for _, sr := range r.Scaling {
+		aborted = aborted || (sr.Insert != nil && sr.Insert.Aborted)
+	}
+
+	r.Degraded = aborted
+}
+
 // InsertResult contains insert benchmark metrics
 type InsertResult struct {
 	TotalEvents int           `json:"total_events"`
@@ -38,6 +103,68 @@ type InsertResult struct {
 	ErrorCount  int64         `json:"error_count"`
 	BatchSize   int           `json:"batch_size"`
 	WorkerCount int           `json:"worker_count"`
+	Duplicates  int64         `json:"duplicates,omitempty"`
+	TargetRate  float64       `json:"target_rate,omitempty"` // events per second the inserts were paced at
+	LatencyP50  time.Duration `json:"latency_p50,omitempty"` // insert latency of a batch
+	LatencyP95  time.Duration `json:"latency_p95,omitempty"`
+	LatencyP99  time.Duration `json:"latency_p99,omitempty"`
+	// Batch latency from the intended start of a paced run, so stalls count
+	// against the batches queued behind them; set only with TargetRate.
+	CorrectedP50 time.Duration `json:"corrected_p50,omitempty"`
+	CorrectedP95 time.Duration `json:"corrected_p95,omitempty"`
+	CorrectedP99 time.Duration `json:"corrected_p99,omitempty"`
+	// Batch latencies of the run, ramp-up excluded.
+	Histogram *Histogram `json:"histogram,omitempty"`
+	// Start of the measured run; the seconds of Timeline count from it.
+	Start time.Time `json:"start,omitzero"`
+	// Events inserted per second of the run; the last second is partial.
+	Timeline []InsertSample `json:"timeline,omitempty"`
+	// Throughput once the per-second rate settled; set with -steady-state.
+	SteadyState *SteadyStateResult `json:"steady_state,omitempty"`
+	// Start of the run during which workers or the rate climbed; its batches
+	// count toward neither Throughput nor the latencies.
+	RampUp     time.Duration `json:"ramp_up,omitempty"`
+	RampEvents int64         `json:"ramp_events,omitempty"`
+	Aborted    bool          `json:"aborted,omitempty"` // stopped early by the error rate limit
+	Retries    int64         `json:"retries,omitempty"` // failed attempts retried; ErrorCount counts batches that failed every attempt
+	// Set when the run was cancelled; TotalEvents then counts the events
+	// inserted before it stopped.
+	Interrupted bool `json:"interrupted,omitempty"`
+	// Rows the run added compared with its events; set with -verify.
+	Verify *VerifyResult `json:"verify,omitempty"`
+	// Breakdown of the run by insert worker, to spot a worker that lags
+	// behind the others.
+	Workers []WorkerStats `json:"workers,omitempty"`
+	// Load of the benchmark client during the run.
+	Client *ClientResources `json:"client,omitempty"`
+}
+
+// ClientResources is the load the benchmark process itself carried during a
+// phase. A CPUPct near 100, or long GC pauses, mean the client rather than
+// the database limited the results. PeakRSS is the high water mark of the
+// process up to the end of the phase, not of the phase alone
+type ClientResources struct {
+	Wall       time.Duration `json:"wall"`
+	CPUTime    time.Duration `json:"cpu_time"` // user plus system
+	CPUPct     float64       `json:"cpu_pct"`  // CPUTime against the wall time of all Cores
+	Cores      int           `json:"cores"`
+	PeakRSS    int64         `json:"peak_rss_bytes"`
+	Allocs     uint64        `json:"allocs"`
+	AllocBytes uint64        `json:"alloc_bytes"`
+	GCCycles   uint32        `json:"gc_cycles"`
+	GCPause    time.Duration `json:"gc_pause"`
+}
+
+// WorkerStats contains the share of an insert run done by one worker; its
+// latencies leave out the ramp-up like those of the run
+type WorkerStats struct {
+	Worker     int           `json:"worker"`
+	Events     int64         `json:"events"`
+	Batches    int64         `json:"batches"`
+	Errors     int64         `json:"errors"`
+	LatencyP50 time.Duration `json:"latency_p50,omitempty"`
+	LatencyP99 time.Duration `json:"latency_p99,omitempty"`
+	LatencyMax time.Duration `json:"latency_max,omitempty"`
 }
 
 // QueryResult contains query benchmark metrics
@@ -52,4 +179,211 @@ type QueryResult struct {
 	P99Duration time.Duration `json:"p99_duration"`
 	ErrorCount  int64         `json:"error_count"`
 	DateRange   string        `json:"date_range"`
+	Rows        int64         `json:"rows,omitempty"`        // rows read by scan scenarios
+	Throughput  float64       `json:"throughput,omitempty"`  // rows per second of scan scenarios
+	ErrorText   string        `json:"error,omitempty"`       // set instead of metrics when the database cannot run the scenario
+	Aborted     bool          `json:"aborted,omitempty"`     // stopped early by the error rate limit
+	Retries     int64         `json:"retries,omitempty"`     // failed attempts retried; ErrorCount counts queries that failed every attempt
+	Interrupted bool          `json:"interrupted,omitempty"` // cut short by a cancellation
+	Workers     int           `json:"workers,omitempty"`     // concurrent query workers, set when more than one
+	QPS         float64       `json:"qps,omitempty"`         // successful runs per second of wall time, across the workers
+	// Latency from the intended start of each run when the scenario is paced.
+	CorrectedP50 time.Duration `json:"corrected_p50,omitempty"`
+	CorrectedP95 time.Duration `json:"corrected_p95,omitempty"`
+	CorrectedP99 time.Duration `json:"corrected_p99,omitempty"`
+	// Latencies of the successful runs.
+	Histogram *Histogram `json:"histogram,omitempty"`
+	// Client process load over the measured runs, warm-up excluded.
+	Client *ClientResources `json:"client,omitempty"`
+}
+
+// BatchTuningResult is the batch size search of -tune-batch: every probe in
+// the order it ran, and the fastest batch size found within Min and Max.
+type BatchTuningResult struct {
+	Min        int          `json:"min"`
+	Max        int          `json:"max"`
+	Optimal    int          `json:"optimal"`
+	Throughput float64      `json:"throughput"` // of the optimal probe
+	Probes     []BatchProbe `json:"probes"`
+	ErrorText  string       `json:"error,omitempty"`
+}
+
+// BatchProbe is the insert throughput measured at one batch size
+type BatchProbe struct {
+	BatchSize  int     `json:"batch_size"`
+	Events     int64   `json:"events"`
+	Throughput float64 `json:"throughput"`
+	ErrorCount int64   `json:"error_count"`
+}
+
+// DurabilityResult contains the insert benchmark outcome at one durability level
+type DurabilityResult struct {
+	Level     string        `json:"level"`
+	Insert    *InsertResult `json:"insert,omitempty"`
+	ErrorText string        `json:"error,omitempty"`
+}
+
+// InsertModeResult contains the insert benchmark outcome in one insert mode
+type InsertModeResult struct {
+	Mode      string        `json:"mode"`
+	Insert    *InsertResult `json:"insert,omitempty"`
+	ErrorText string        `json:"error,omitempty"`
+}
+
+// ScalingResult contains the insert benchmark outcome at one worker count
+type ScalingResult struct {
+	Workers   int           `json:"workers"`
+	Insert    *InsertResult `json:"insert,omitempty"`
+	ErrorText string        `json:"error,omitempty"`
+}
+
+// IndexBuildResult contains the build of the secondary indexes deferred
+// past the load, and the indexed load the deferred one is compared with
+type IndexBuildResult struct {
+	Indexed    *InsertResult `json:"indexed,omitempty"` // the insert run with the indexes in place
+	Duration   time.Duration `json:"duration"`
+	Rows       int64         `json:"rows"`       // events indexed
+	Throughput float64       `json:"throughput"` // events indexed per second
+	IndexSize  int64         `json:"index_size,omitempty"`
+	ErrorText  string        `json:"error,omitempty"`
+}
+
+// RetentionResult contains retention delete metrics and the storage reclaimed
+type RetentionResult struct {
+	Cutoff         time.Time     `json:"cutoff"`
+	Method         string        `json:"method,omitempty"`
+	RowsDeleted    int64         `json:"rows_deleted"`
+	Duration       time.Duration `json:"duration"`
+	Throughput     float64       `json:"throughput"`
+	SizeBefore     int64         `json:"size_before"`
+	SizeAfter      int64         `json:"size_after"`
+	ReclaimedBytes int64         `json:"reclaimed_bytes"`
+	ErrorText      string        `json:"error,omitempty"`
+}
+
+// TTLResult contains the insert run with expiry enabled, its throughput loss
+// against the main insert run, and the rows and storage before and after the
+// database expired the old events
+type TTLResult struct {
+	TTL            time.Duration `json:"ttl"`
+	Method         string        `json:"method,omitempty"`
+	Insert         *InsertResult `json:"insert,omitempty"`
+	InsertOverhead float64       `json:"insert_overhead_pct"` // negative when the TTL run was faster
+	ExpireDuration time.Duration `json:"expire_duration"`
+	RowsBefore     int64         `json:"rows_before"`
+	RowsAfter      int64         `json:"rows_after"`
+	SizeBefore     int64         `json:"size_before"`
+	SizeAfter      int64         `json:"size_after"`
+	ErrorText      string        `json:"error,omitempty"`
+}
+
+// CacheResult contains the query results measured right after a database
+// restart, each scenario run once, and the results of the same scenarios
+// after the warmup
+type CacheResult struct {
+	Cold      map[string]*QueryResult `json:"cold,omitempty"`
+	Warm      map[string]*QueryResult `json:"warm,omitempty"`
+	ErrorText string                  `json:"error,omitempty"`
+}
+
+// TransactionResult contains the transactional workload metrics
+type TransactionResult struct {
+	Transactions int           `json:"transactions"`
+	Duration     time.Duration `json:"duration"`
+	Throughput   float64       `json:"throughput"`
+	ErrorCount   int64         `json:"error_count"`
+	WorkerCount  int           `json:"worker_count"`
+	ErrorText    string        `json:"error,omitempty"`
+	// What the transaction workers cost the client process.
+	Client *ClientResources `json:"client,omitempty"`
+}
+
+// ReadAfterWriteResult contains the read-after-write sampling metrics: how
+// many sampled events were visible on the first read after their insert,
+// only on a later one, or not within the visibility timeout, and the lag of
+// the delayed ones
+type ReadAfterWriteResult struct {
+	Samples    int           `json:"samples"`
+	Immediate  int64         `json:"immediate"`
+	Delayed    int64         `json:"delayed"`
+	Missing    int64         `json:"missing"`
+	ErrorCount int64         `json:"error_count"`
+	StalePct   float64       `json:"stale_pct"`   // samples not visible on the first read, delayed or missing
+	MissingPct float64       `json:"missing_pct"` // samples never visible
+	LagP50     time.Duration `json:"lag_p50"`
+	LagP99     time.Duration `json:"lag_p99"`
+	LagMax     time.Duration `json:"lag_max"`
+	ErrorText  string        `json:"error,omitempty"`
+}
+
+// ParityResult compares the event stats a database returned for the
+// deterministic parity dataset with the counts of the dataset itself
+type ParityResult struct {
+	Events        int             `json:"events"`
+	FailedBatches int64           `json:"failed_batches,omitempty"` // dataset batches that could not be inserted
+	Windows       []*ParityWindow `json:"windows,omitempty"`
+	ErrorText     string          `json:"error,omitempty"`
+}
+
+// OK reports whether the dataset was fully inserted and every window matched.
+func (p *ParityResult) OK() bool {
+	if p.ErrorText != "" || p.FailedBatches > 0 {
+		return false
+	}
+
+	for _, w := range p.Windows {
+		if w.ErrorText != "" || len(w.Mismatched) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParityWindow holds the per event type counts of one parity window: those of
+// the dataset, those summed from GetEventStats, and the event types whose
+// counts differ
+type ParityWindow struct {
+	Name       string           `json:"name"`
+	Start      time.Time        `json:"start"`
+	End        time.Time        `json:"end"`
+	Expected   map[string]int64 `json:"expected"`
+	Counted    map[string]int64 `json:"counted,omitempty"`
+	Mismatched []string         `json:"mismatched,omitempty"`
+	ErrorText  string           `json:"error,omitempty"`
+}
+
+// MixedResult contains the read-while-write metrics: the insert run, the
+// query latency before and during it, and per-second timelines of both sides
+type MixedResult struct {
+	Insert         *InsertResult  `json:"insert"`
+	Baseline       *QueryResult   `json:"baseline"`
+	UnderLoad      *QueryResult   `json:"under_load"`
+	InsertTimeline []InsertSample `json:"insert_timeline"`
+	QueryTimeline  []QuerySample  `json:"query_timeline"`
+}
+
+// SteadyStateResult is the steady state of an insert run: where the
+// per-second rate first held within Tolerance percent of its mean for a
+// few seconds, and the throughput from there on.
+type SteadyStateResult struct {
+	Tolerance  float64       `json:"tolerance_pct"`
+	Reached    bool          `json:"reached"`
+	After      time.Duration `json:"after,omitempty"`      // time into the run the steady state started
+	Seconds    int           `json:"seconds,omitempty"`    // full seconds of the run in steady state
+	Throughput float64       `json:"throughput,omitempty"` // events per second in steady state
+}
+
+// InsertSample is the number of events inserted in one second of an insert run
+type InsertSample struct {
+	Second int   `json:"second"`
+	Events int64 `json:"events"`
+}
+
+// QuerySample is the query latency in one second of the mixed phase
+type QuerySample struct {
+	Second      int           `json:"second"`
+	Queries     int           `json:"queries"`
+	AvgDuration time.Duration `json:"avg_duration"`
+	P95Duration time.Duration `json:"p95_duration"`
 }
@@ -0,0 +1,43 @@
+package benchmark
+
+import (
+	"context"
+	"time"
+)
+
+// retrying runs op under the operation timeout and retry policy of the
+// runner: each attempt gets OpTimeout (none when zero), and a failed attempt
+// is retried up to Retries times, waiting RetryBackoff before the first retry
+// and twice as long before each next one. It returns the number of retries
+// made and the error of the last attempt. Nothing is retried once ctx is
+// done.
+func (r *Runner) retrying(ctx context.Context, op func(context.Context) error) (retries int, err error) {
+	backoff := r.RetryBackoff
+
+	for {
+		if err = r.attempt(ctx, op); err == nil || retries >= r.Retries || ctx.Err() != nil {
+			return retries, err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return retries, err
+		}
+
+		retries++
+		backoff *= 2
+	}
+}
+
+// attempt runs op once, bounded by OpTimeout when set.
+func (r *Runner) attempt(ctx context.Context, op func(context.Context) error) error {
+	if r.OpTimeout <= 0 {
+		return op(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.OpTimeout)
+	defer cancel()
+
+	return op(ctx)
+}
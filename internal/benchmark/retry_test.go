@@ -0,0 +1,87 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrying(t *testing.T) {
+	runner := &Runner{Retries: 3, RetryBackoff: time.Millisecond}
+
+	var calls int
+
+	retries, err := runner.retrying(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection reset")
+		}
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, retries)
+
+	calls = 0
+	retries, err = runner.retrying(context.Background(), func(context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 3, retries)
+	assert.Equal(t, 4, calls)
+}
+
+func TestRetryingTimeout(t *testing.T) {
+	runner := &Runner{OpTimeout: 10 * time.Millisecond}
+
+	start := time.Now()
+	retries, err := runner.retrying(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, retries)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRunInsertRetries(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen = make(map[string]bool)
+	)
+
+	// The first attempt of every batch fails.
+	mock := &mockRepository{
+		insertBatchFunc: func(_ context.Context, events []generator.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if !seen[events[0].ID] {
+				seen[events[0].ID] = true
+				return errors.New("transient")
+			}
+
+			return nil
+		},
+	}
+
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 2, Retries: 1, RetryBackoff: time.Millisecond}
+
+	result := runner.RunInsert(context.Background(), mock)
+
+	assert.Equal(t, int64(10), result.Retries)
+	assert.Zero(t, result.ErrorCount)
+
+	queries := runner.runScenario(context.Background(), "flaky", func(context.Context) error { return nil })
+	assert.Zero(t, queries.Retries)
+}
@@ -0,0 +1,75 @@
+package benchmark
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// RunInfo identifies the run a result belongs to, so it can be traced back
+// to the binary and the settings that produced it. Every result of a run
+// shares it.
+type RunInfo struct {
+	ID         string    `json:"id"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Revision   string    `json:"revision,omitempty"` // VCS revision the binary was built from
+	// Effective value of every flag, the defaults included.
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// NewRunID returns a unique ID of a run started at started: its start time,
+// so IDs sort by it, and a random suffix, e.g. "20261016-210558-3f9a2c".
+func NewRunID(started time.Time) string {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+
+	return started.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// Revision returns the VCS revision of the binary, marked dirty when it was
+// built from a modified tree, or "" when the build has no VCS information.
+func Revision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	var rev, modified string
+
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			rev = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+
+	if rev != "" && modified == "true" {
+		rev += "-dirty"
+	}
+
+	return rev
+}
+
+// ShortRevision abbreviates a VCS revision like git does, keeping its
+// -dirty mark.
+func ShortRevision(rev string) string {
+	if rev == "" {
+		return "-"
+	}
+
+	hash, suffix, dirty := strings.Cut(rev, "-")
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+
+	if dirty {
+		hash += "-" + suffix
+	}
+
+	return hash
+}
@@ -0,0 +1,23 @@
+package benchmark
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRunID(t *testing.T) {
+	started := time.Date(2026, 10, 16, 21, 5, 58, 0, time.UTC)
+
+	id := NewRunID(started)
+	assert.Regexp(t, regexp.MustCompile(`^20261016-210558-[0-9a-f]{6}$`), id)
+	assert.NotEqual(t, id, NewRunID(started), "runs started in the same second get different IDs")
+}
+
+func TestShortRevision(t *testing.T) {
+	assert.Equal(t, "-", ShortRevision(""))
+	assert.Equal(t, "2d3b59ece6a2", ShortRevision("2d3b59ece6a20a5d4add628c607ca2690f438a04"))
+	assert.Equal(t, "2d3b59ece6a2-dirty", ShortRevision("2d3b59ece6a20a5d4add628c607ca2690f438a04-dirty"))
+}
@@ -9,16 +9,66 @@ import (
 	"time"
 
 	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// Row limits of the user_history, top_users_7d and tenant_recent_events
+// scenarios.
+const (
+	userHistoryLimit  = 50
+	topUsersLimit     = 10
+	tenantEventsLimit = 100
+)
+
+// paginationPageSize is the page size of the pagination scenario.
+const paginationPageSize = 1000
+
+// sessionGap is the inactivity that ends a user session in the sessions
+// scenario.
+const sessionGap = 30 * time.Minute
+
+// searchToken is the payload_search token; it appears in the message of the
+// generated error events.
+const searchToken = "timeout"
+
 // Runner executes insert and query benchmarks.
 type Runner struct {
 	EventCount       int
 	BatchSize        int
 	Workers          int
 	QueryIterations  int
-	WarmupIterations int
+	QueryWorkers     int            // concurrent runs of each query scenario, 0 or 1 to run them one at a time
+	Scenarios        ScenarioFilter // query scenarios to run, all when empty
+	Verify           bool           // count the rows each insert run added and compare them with its events
+	WarmupIterations int            // unmeasured runs of each query scenario
+	WarmupBatches    int            // unmeasured batches inserted before each insert run
 	PreloadCount     int
+	RampUp           time.Duration      // start of each insert run left out of its stats while the load climbs
+	DuplicatePct     int                // share of inserted events that reuse an earlier event ID
+	UserDist         generator.UserDist // distribution of the user IDs of inserted events, uniform when zero
+	TimeDist         generator.TimeDist // distribution of the created_at of inserted events
+	IDFormat         string             // format of the event IDs of inserted events, generator.IDComposite when empty
+	TenantCount      int                // tenants the inserted events are spread over, 0 for no tenant_id
+	TenantDist       generator.UserDist // distribution of the events over the TenantCount tenants, uniform when zero
+	Dataset          *generator.Dataset // events replayed in place of generated ones, nil to generate them
+	UserCount        int                // rows of the users dimension table, 0 to skip it
+	TransactionCount int                // events written by the transactional workload, 0 to skip it
+	ReadAfterWrite   int                // batches inserted and sampled for read-after-write visibility, 0 to skip it
+	Rate             float64            // target insert rate in events per second, 0 for max speed
+	SteadyState      float64            // tolerance in percent of the steady-state detection of insert runs, 0 to skip it
+	QueryRate        float64            // target rate of each query scenario in queries per second, 0 for back to back
+	MaxErrorRate     float64            // percentage of failed batches or queries that aborts a run, 0 to never abort
+	OpTimeout        time.Duration      // limit of each insert or query attempt, 0 for none
+	Retries          int                // retries of a failed insert or query before it counts as an error
+	RetryBackoff     time.Duration      // wait before the first retry, doubled for each next one
+	CustomQueries    []CustomQuery      // user-defined query scenarios run by RunCustomQueries
+
+	samplesMu sync.Mutex
+	samples   map[Repository]*eventSample
+
+	replayMu sync.Mutex
+	replayed map[Repository]int // events of Dataset each repository was handed
 }
 
 // Preload inserts seed data without measuring performance.
@@ -27,7 +77,9 @@ func (r *Runner) Preload(ctx context.Context, repo Repository) error {
 		return nil
 	}
 
-	inserted, errors := r.parallelInsert(ctx, repo, r.PreloadCount, int64(r.BatchSize)*50)
+	progress := startProgress(ctx, "preload", r.PreloadCount, int64(r.BatchSize)*50)
+	inserted, errors := r.parallelInsert(ctx, repo, r.Workers, r.PreloadCount, progress, nil)
+	progress.Done()
 	log.Printf("Preload complete: %d events inserted, %d errors", inserted, errors)
 
 	if errors > 0 && inserted == 0 {
@@ -37,109 +89,805 @@ func (r *Runner) Preload(ctx context.Context, repo Repository) error {
 	return nil
 }
 
+// LoadUsers creates the users dimension table and fills it with users 0 to
+// UserCount-1. It does nothing when UserCount is zero or the repository has
+// no users table.
+func (r *Runner) LoadUsers(ctx context.Context, repo Repository) error {
+	users, ok := repo.(UsersRepository)
+	if !ok || r.UserCount <= 0 {
+		return nil
+	}
+
+	if err := users.InitUsersSchema(ctx); err != nil {
+		return fmt.Errorf("failed to create users table: %w", err)
+	}
+
+	for batch := range generator.GenerateUsers(r.UserCount, r.BatchSize) {
+		if err := users.InsertUsers(ctx, batch); err != nil {
+			return fmt.Errorf("failed to insert users: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// newGenerator returns a generator of count events for repo in batches of
+// size: the next events of Dataset, or new events shaped by r.
+func (r *Runner) newGenerator(repo Repository, count, size int) *generator.Generator {
+	if r.Dataset != nil {
+		return r.Dataset.Generator(r.replayFrom(repo, count), count, size)
+	}
+
+	return r.shape(generator.New(count, size))
+}
+
+// shape draws the users, timestamps and tenants of the events of g from
+// UserDist, TimeDist and TenantDist, and formats their IDs as IDFormat.
+func (r *Runner) shape(g *generator.Generator) *generator.Generator {
+	return g.WithUserDist(r.UserDist).
+		WithTimeDist(r.TimeDist).
+		WithIDFormat(r.IDFormat).
+		WithTenants(r.TenantCount, r.TenantDist)
+}
+
 // RunInsert benchmarks batch inserts into the given repository.
 func (r *Runner) RunInsert(ctx context.Context, repo Repository) *InsertResult {
-	start := time.Now()
-	inserted, errors := r.parallelInsert(ctx, repo, r.EventCount, int64(r.BatchSize)*10)
-	duration := time.Since(start)
+	return r.runInsert(ctx, repo, r.Workers)
+}
+
+// runInsert benchmarks batch inserts with the given number of workers after
+// inserting the warm-up batches, which are not measured. With Verify the
+// rows the run added are then checked against the events it inserted.
+func (r *Runner) runInsert(ctx context.Context, repo Repository, workers int) *InsertResult {
+	ctx, span := telemetryFrom(ctx).start(ctx, "insert", attribute.Int("dbbench.workers", workers))
+	defer span.End()
+
+	r.warmupInsert(ctx, repo, workers)
+
+	verify := r.startVerify(ctx, repo)
+	res, inserted := r.measureInsert(ctx, repo, workers)
+	res.Verify = verify.finish(ctx, inserted, res.Duplicates)
+
+	return res
+}
+
+// measureInsert runs the measured inserts and returns their result and the
+// number of events inserted.
+func (r *Runner) measureInsert(ctx context.Context, repo Repository, workers int) (*InsertResult, int64) {
+	probe := startClientProbe()
+	load := r.newInsertLoad(probe.start, workers)
+	progress := startProgress(ctx, "insert", r.EventCount, int64(r.BatchSize)*10)
+	inserted, errors := r.parallelInsert(ctx, repo, workers, r.EventCount, progress, load)
+	duration := time.Since(probe.start)
+	progress.Done()
+	tl, _ := load.tl.samples()
+	total, interrupted := r.EventCount, ctx.Err() != nil
+
+	if interrupted {
+		total = int(inserted)
+	}
 
 	return &InsertResult{
-		TotalEvents: r.EventCount,
-		Duration:    duration,
-		Throughput:  float64(inserted) / duration.Seconds(),
-		ErrorCount:  errors,
-		BatchSize:   r.BatchSize,
-		WorkerCount: r.Workers,
+		TotalEvents:  total,
+		Duration:     duration,
+		Throughput:   load.throughput(inserted, duration),
+		ErrorCount:   errors,
+		BatchSize:    r.BatchSize,
+		WorkerCount:  workers,
+		Duplicates:   load.dup.duplicates(),
+		TargetRate:   r.Rate,
+		RampUp:       r.RampUp,
+		RampEvents:   load.rampEvents,
+		Aborted:      load.guard.aborted(),
+		Retries:      load.retries.Load(),
+		Interrupted:  interrupted,
+		LatencyP50:   Percentile(load.latencies, 0.50),
+		LatencyP95:   Percentile(load.latencies, 0.95),
+		LatencyP99:   Percentile(load.latencies, 0.99),
+		CorrectedP50: Percentile(load.corrected, 0.50),
+		CorrectedP95: Percentile(load.corrected, 0.95),
+		CorrectedP99: Percentile(load.corrected, 0.99),
+		Histogram:    NewHistogram(load.latencies),
+		Start:        probe.start,
+		Timeline:     tl,
+		SteadyState:  r.steadyState(tl),
+		Workers:      load.workerStats(),
+		Client:       probe.stop(),
+	}, inserted
+}
+
+// newInsertLoad returns the load of a measured insert run starting at start
+// on the given number of workers.
+func (r *Runner) newInsertLoad(start time.Time, workers int) *insertLoad {
+	return &insertLoad{
+		dup:     newDuplicator(r.DuplicatePct),
+		pace:    newPacer(r.Rate).withRamp(r.RampUp),
+		tl:      &timeline{start: start},
+		ramp:    r.RampUp,
+		rampEnd: start.Add(r.RampUp),
+		guard:   newErrorGuard(r.MaxErrorRate),
+		workers: make([]workerLoad, workers),
+	}
+}
+
+// warmupInsert inserts WarmupBatches batches of fresh events so the measured
+// run starts on open connections and warm caches. Their events stay in the
+// table but count toward neither the throughput nor the latencies.
+func (r *Runner) warmupInsert(ctx context.Context, repo Repository, workers int) {
+	if r.WarmupBatches <= 0 {
+		return
+	}
+
+	inserted, errors := r.parallelInsert(ctx, repo, workers, r.WarmupBatches*r.BatchSize, quietProgress{}, nil)
+	log.Printf("Insert warm-up complete: %d events inserted, %d errors", inserted, errors)
+}
+
+// Warmup returns the warm-up configuration of the runner for the results
+// metadata.
+func (r *Runner) Warmup() *WarmupConfig {
+	return &WarmupConfig{QueryIterations: r.WarmupIterations, InsertBatches: r.WarmupBatches}
+}
+
+// insertLoad shapes the batches of a measured insert run and collects the
+// latency of every successful batch, plus its latency from the intended
+// start when the run is paced, and the events inserted per second.
+// Batches that start within the ramp-up are left out of the latencies and
+// the throughput. The guard stops the run when too many batches fail, and
+// workers breaks the run down by insert worker.
+// Preloads and the mixed workload insert without one.
+type insertLoad struct {
+	dup     *duplicator
+	pace    *pacer
+	tl      *timeline
+	ramp    time.Duration
+	rampEnd time.Time
+	guard   *errorGuard
+	retries atomic.Int64
+	workers []workerLoad // indexed by worker ID
+
+	mu         sync.Mutex
+	latencies  []time.Duration
+	corrected  []time.Duration
+	rampEvents int64
+}
+
+// workerLoad is the share of an insert run done by one worker. Only that
+// worker updates it, so it needs no lock.
+type workerLoad struct {
+	events, batches, errors int64
+	latencies               []time.Duration
+}
+
+// record adds a batch of n events that worker started at start, was due at
+// due and has just finished.
+func (l *insertLoad) record(worker, n int, start, due time.Time) {
+	if l == nil {
+		return
+	}
+
+	end := time.Now()
+	l.tl.addInsert(n)
+	l.guard.observe(false)
+	l.workers[worker].add(n, start, end, l.rampEnd)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if start.Before(l.rampEnd) {
+		l.rampEvents += int64(n)
+		return
+	}
+
+	l.latencies = append(l.latencies, end.Sub(start))
+
+	if d, ok := sinceDue(due, end); ok {
+		l.corrected = append(l.corrected, d)
 	}
 }
 
-func (r *Runner) parallelInsert(ctx context.Context, repo Repository, count int, logInterval int64) (inserted, errors int64) {
-	gen := generator.New(count, r.BatchSize)
+// insertBatch inserts one batch under the retry policy, counts its retries
+// in load and reports the batch to the Observer of ctx.
+func (r *Runner) insertBatch(ctx context.Context, repo Repository, batch []generator.Event, load *insertLoad) error {
+	tel := telemetryFrom(ctx)
+	ctx, span := tel.start(ctx, "insert batch", attribute.Int("dbbench.batch.events", len(batch)))
+	start := time.Now()
+	retries, err := r.retrying(ctx, func(ctx context.Context) error {
+		return repo.InsertBatch(ctx, batch)
+	})
+	latency := time.Since(start)
+
+	span.SetAttributes(attribute.Int("dbbench.retries", retries))
+	tel.end(ctx, span, batchDuration, batchErrors, latency, err)
+
+	if load != nil {
+		load.retries.Add(int64(retries))
+	}
+
+	obs := observerFrom(ctx)
+	if err != nil {
+		obs.InsertFailed()
+		return err
+	}
+
+	obs.Inserted(len(batch), latency)
+
+	return nil
+}
+
+// fail records a failed batch of worker.
+func (l *insertLoad) fail(worker int) {
+	if l != nil {
+		l.guard.observe(true)
+		l.workers[worker].errors++
+	}
+}
+
+// add counts a batch of n events that ran from start to end, keeping its
+// latency unless it started within the ramp-up.
+func (w *workerLoad) add(n int, start, end, rampEnd time.Time) {
+	w.events += int64(n)
+	w.batches++
+
+	if !start.Before(rampEnd) {
+		w.latencies = append(w.latencies, end.Sub(start))
+	}
+}
+
+// workerStats returns the per-worker breakdown of the run. It is read once
+// the workers are done.
+func (l *insertLoad) workerStats() []WorkerStats {
+	stats := make([]WorkerStats, len(l.workers))
+
+	for i, w := range l.workers {
+		stats[i] = WorkerStats{
+			Worker:     i,
+			Events:     w.events,
+			Batches:    w.batches,
+			Errors:     w.errors,
+			LatencyP50: Percentile(w.latencies, 0.50),
+			LatencyP99: Percentile(w.latencies, 0.99),
+			LatencyMax: MaxDuration(w.latencies),
+		}
+	}
+
+	return stats
+}
+
+// rampDelay staggers the start of the workers evenly over the ramp-up, so
+// they open their connections one after another instead of all at once.
+func (l *insertLoad) rampDelay(ctx context.Context, workerID, workers int) {
+	if l == nil || l.ramp <= 0 {
+		return
+	}
+
+	select {
+	case <-time.After(l.ramp * time.Duration(workerID) / time.Duration(workers)):
+	case <-ctx.Done():
+	}
+}
+
+// throughput returns the events per second inserted after the ramp-up. A run
+// that ends within the ramp-up has no steady state, so it falls back to the
+// whole run.
+func (l *insertLoad) throughput(inserted int64, duration time.Duration) float64 {
+	if l.ramp <= 0 {
+		return float64(inserted) / duration.Seconds()
+	}
+
+	steady := duration - l.ramp
+	if steady <= 0 || inserted == l.rampEvents {
+		log.Printf("Insert run ended within the %v ramp-up; reporting whole-run throughput", l.ramp)
+		return float64(inserted) / duration.Seconds()
+	}
+
+	return float64(inserted-l.rampEvents) / steady.Seconds()
+}
+
+// scheduledBatch is a batch of events and its intended start, zero when the
+// run is not paced.
+type scheduledBatch struct {
+	events []generator.Event
+	due    time.Time
+}
+
+func (r *Runner) parallelInsert(
+	ctx context.Context, repo Repository, workers, count int, progress ProgressTracker, load *insertLoad,
+) (inserted, errors int64) {
+	gen := r.newGenerator(repo, count, r.BatchSize)
 
 	var totalInserted, totalErrors int64
 
-	batches := make(chan []generator.Event, r.Workers*2)
+	batches := make(chan scheduledBatch, workers*2)
+	wg := r.startInsertWorkers(ctx, repo, workers, batches, &totalInserted, &totalErrors, progress, load)
+
+	genCtx, stopGen := context.WithCancel(ctx)
+	defer stopGen()
 
+	go pumpBatches(genCtx, stopGen, gen.GenerateContext(genCtx), batches, load)
+
+	wg.Wait()
+
+	if replayFailed(gen) {
+		atomic.AddInt64(&totalErrors, 1)
+	}
+
+	return atomic.LoadInt64(&totalInserted), atomic.LoadInt64(&totalErrors)
+}
+
+// startInsertWorkers starts workers goroutines consuming batches, each
+// ramped up by load, and returns the group they are done on.
+func (r *Runner) startInsertWorkers(
+	ctx context.Context, repo Repository, workers int, batches <-chan scheduledBatch,
+	totalInserted, totalErrors *int64, progress ProgressTracker, load *insertLoad,
+) *sync.WaitGroup {
 	var wg sync.WaitGroup
 
-	for i := 0; i < r.Workers; i++ {
+	for i := 0; i < workers; i++ {
 		wg.Add(1)
 
 		go func(workerID int) {
 			defer wg.Done()
 
-			r.consumeBatches(ctx, repo, batches, &totalInserted, &totalErrors, count, logInterval, workerID)
+			load.rampDelay(ctx, workerID, workers)
+			r.consumeBatches(ctx, repo, batches, totalInserted, totalErrors, progress, workerID, load)
 		}(i)
 	}
 
-	go pumpBatches(gen.Generate(), batches)
-
-	wg.Wait()
-
-	return atomic.LoadInt64(&totalInserted), atomic.LoadInt64(&totalErrors)
+	return &wg
 }
 
 func (r *Runner) consumeBatches(
-	ctx context.Context, repo Repository, batches <-chan []generator.Event,
-	totalInserted, totalErrors *int64, total int, logInterval int64, workerID int,
+	ctx context.Context, repo Repository, batches <-chan scheduledBatch,
+	totalInserted, totalErrors *int64, progress ProgressTracker, workerID int, load *insertLoad,
 ) {
-	for batch := range batches {
-		if err := repo.InsertBatch(ctx, batch); err != nil {
-			if workerID >= 0 {
-				log.Printf("Worker %d insert error: %v", workerID, err)
-			}
+	sample := r.sampleFor(repo)
 
+	for scheduled := range batches {
+		if ctx.Err() != nil {
+			generator.Release(scheduled.events)
+			continue
+		}
+
+		batch := scheduled.events
+
+		if err := r.insertScheduled(ctx, repo, scheduled, workerID, load); err != nil {
 			atomic.AddInt64(totalErrors, 1)
+			progress.Fail()
+			generator.Release(batch)
 
 			continue
 		}
 
-		prev := atomic.LoadInt64(totalInserted)
-		inserted := atomic.AddInt64(totalInserted, int64(len(batch)))
+		sample.add(batch)
+		atomic.AddInt64(totalInserted, int64(len(batch)))
+		progress.Add(len(batch))
+		generator.Release(batch)
+	}
+}
 
-		if logInterval > 0 && prev/logInterval != inserted/logInterval {
-			log.Printf("Insert progress: %d / %d events", inserted, total)
-		}
+// insertScheduled inserts a batch of a worker and records its latency from
+// when it was due, or logs and records its failure.
+func (r *Runner) insertScheduled(ctx context.Context, repo Repository, scheduled scheduledBatch, workerID int, load *insertLoad) error {
+	start := time.Now()
+
+	// A batch in flight when the run is interrupted is finished rather
+	// than failed, so the partial results keep it.
+	if err := r.insertBatch(context.WithoutCancel(ctx), repo, scheduled.events, load); err != nil {
+		logInsertError(workerID, err)
+		load.fail(workerID)
+
+		return err
+	}
+
+	load.record(workerID, len(scheduled.events), start, scheduled.due)
+
+	return nil
+}
+
+// logInsertError logs a failed batch of a worker; negative worker IDs insert
+// quietly.
+func logInsertError(workerID int, err error) {
+	if workerID >= 0 {
+		log.Printf("Worker %d insert error: %v", workerID, err)
 	}
 }
 
-func pumpBatches(src <-chan []generator.Event, dst chan<- []generator.Event) {
+// pumpBatches forwards generated batches to the workers, rewriting a share
+// of their events into duplicates and holding each batch until the target
+// rate allows it when load sets either. Once the guard aborts the run or ctx
+// is done, it stops the generator of src and drops the batches still queued.
+func pumpBatches(
+	ctx context.Context, stop context.CancelFunc, src <-chan []generator.Event, dst chan<- scheduledBatch, load *insertLoad,
+) {
+	var (
+		dup   *duplicator
+		pace  *pacer
+		guard *errorGuard
+	)
+
+	if load != nil {
+		dup, pace, guard = load.dup, load.pace, load.guard
+	}
+
 	for batch := range src {
-		dst <- batch
+		if guard.aborted() || ctx.Err() != nil {
+			stop()
+			generator.Release(batch)
+
+			continue
+		}
+
+		dup.apply(batch)
+		dst <- scheduledBatch{events: batch, due: pace.wait(len(batch))}
 	}
 
 	close(dst)
 }
 
-// RunQueries benchmarks all query scenarios against the given repository.
+// RunQueries benchmarks the query scenarios selected by Scenarios against
+// the given repository.
 func (r *Runner) RunQueries(ctx context.Context, repo Repository) map[string]*QueryResult {
 	results := make(map[string]*QueryResult)
 	now := time.Now()
 
-	scenarios := []struct {
-		name  string
-		start time.Time
-	}{
-		{"1_hour", now.Add(-1 * time.Hour)},
-		{"1_day", now.Add(-24 * time.Hour)},
-		{"1_week", now.Add(-7 * 24 * time.Hour)},
-		{"1_month", now.Add(-30 * 24 * time.Hour)},
-	}
+	for _, s := range queryScenarios {
+		if !r.Scenarios.selects(s.name) {
+			continue
+		}
 
-	for _, s := range scenarios {
-		results[s.name] = r.runQuery(ctx, repo, s.name, s.start, now)
+		if qr := s.run(r, ctx, repo, now); !qr.skipped() {
+			results[qr.QueryName] = qr
+		}
 	}
 
 	return results
 }
 
+// skipped reports whether a scenario has no result: the repository cannot
+// run it, or the run was interrupted before it started.
+func (q *QueryResult) skipped() bool {
+	return q == nil || (q.Interrupted && q.Iterations == 0 && q.ErrorCount == 0)
+}
+
 func (r *Runner) runQuery(ctx context.Context, repo Repository, name string, start, end time.Time) *QueryResult {
-	for i := 0; i < r.WarmupIterations; i++ {
-		_, _ = repo.GetEventStats(ctx, start, end)
+	res := r.runScenario(ctx, name, func(ctx context.Context) error {
+		_, err := repo.GetEventStats(ctx, start, end)
+		return err
+	})
+
+	if res.Iterations > 0 {
+		res.DateRange = dateRange(start, end)
+	}
+
+	return res
+}
+
+// runPointLookup fetches sampled inserted events by event_id. It returns nil
+// when the repository has no point lookup or nothing was inserted.
+func (r *Runner) runPointLookup(ctx context.Context, repo Repository, _ time.Time) *QueryResult {
+	lookup, ok := repo.(PointLookupRepository)
+	if !ok {
+		return nil
+	}
+
+	return r.runSampled(ctx, repo, "point_lookup", func(ctx context.Context, event generator.Event) error {
+		_, err := lookup.GetEventByID(ctx, event.ID)
+		return err
+	})
+}
+
+// runExists checks for event IDs sampled from the inserted events. It returns
+// nil when the repository has no existence check or nothing was inserted.
+func (r *Runner) runExists(ctx context.Context, repo Repository, _ time.Time) *QueryResult {
+	checker, ok := repo.(ExistenceRepository)
+	if !ok {
+		return nil
+	}
+
+	return r.runSampled(ctx, repo, "exists_by_id", func(ctx context.Context, event generator.Event) error {
+		_, err := checker.EventExists(ctx, event.ID)
+		return err
+	})
+}
+
+// runUserHistory reads the newest userHistoryLimit events of users sampled
+// from the inserted events. It returns nil when the repository has no user
+// history query or nothing was inserted.
+func (r *Runner) runUserHistory(ctx context.Context, repo Repository, _ time.Time) *QueryResult {
+	history, ok := repo.(UserHistoryRepository)
+	if !ok {
+		return nil
+	}
+
+	return r.runSampled(ctx, repo, "user_history", func(ctx context.Context, event generator.Event) error {
+		_, err := history.GetUserEvents(ctx, event.UserID, userHistoryLimit)
+		return err
+	})
+}
+
+// runTopUsers ranks the topUsersLimit users by event count over the week
+// before now. It returns nil when the repository has no top users query.
+func (r *Runner) runTopUsers(ctx context.Context, repo Repository, now time.Time) *QueryResult {
+	top, ok := repo.(TopUsersRepository)
+	if !ok {
+		return nil
+	}
+
+	start := now.Add(-7 * 24 * time.Hour)
+
+	res := r.runScenario(ctx, "top_users_7d", func(ctx context.Context) error {
+		_, err := top.GetTopUsers(ctx, start, now, topUsersLimit)
+		return err
+	})
+
+	if res.Iterations > 0 {
+		res.DateRange = dateRange(start, now)
+	}
+
+	return res
+}
+
+// runUserGroups groups the month before now by user_id, about one group per
+// generated user. It returns nil when the repository has no such query.
+func (r *Runner) runUserGroups(ctx context.Context, repo Repository, now time.Time) *QueryResult {
+	groups, ok := repo.(UserGroupsRepository)
+	if !ok {
+		return nil
+	}
+
+	start := now.Add(-30 * 24 * time.Hour)
+
+	res := r.runScenario(ctx, "group_by_user_1_month", func(ctx context.Context) error {
+		_, err := groups.GetUserGroups(ctx, start, now)
+		return err
+	})
+
+	if res.Iterations > 0 {
+		res.DateRange = dateRange(start, now)
 	}
 
-	durations, errors := r.measureQuery(ctx, repo, start, end)
+	return res
+}
+
+// runPayloadSearch counts the events of the day before now whose payload
+// contains searchToken.
+func (r *Runner) runPayloadSearch(ctx context.Context, repo Repository, now time.Time) *QueryResult {
+	search, ok := repo.(PayloadSearchRepository)
+	if !ok {
+		return nil
+	}
+
+	start := now.Add(-24 * time.Hour)
+
+	res := r.runScenario(ctx, "payload_search_1_day", func(ctx context.Context) error {
+		_, err := search.SearchPayload(ctx, searchToken, start, now)
+		return err
+	})
 
+	if res.Iterations > 0 {
+		res.DateRange = dateRange(start, now)
+	}
+
+	return res
+}
+
+// runCount counts the events of the day before now. It returns nil when the
+// repository has no count query.
+func (r *Runner) runCount(ctx context.Context, repo Repository, now time.Time) *QueryResult {
+	counter, ok := repo.(CountRepository)
+	if !ok {
+		return nil
+	}
+
+	start := now.Add(-24 * time.Hour)
+
+	res := r.runScenario(ctx, "count_1_day", func(ctx context.Context) error {
+		_, err := counter.CountEvents(ctx, start, now)
+		return err
+	})
+
+	if res.Iterations > 0 {
+		res.DateRange = dateRange(start, now)
+	}
+
+	return res
+}
+
+// runJoin joins the week before now to the users table and counts the events
+// per country. It returns nil unless LoadUsers filled a users table.
+func (r *Runner) runJoin(ctx context.Context, repo Repository, now time.Time) *QueryResult {
+	users, ok := repo.(UsersRepository)
+	if !ok || r.UserCount <= 0 {
+		return nil
+	}
+
+	start := now.Add(-7 * 24 * time.Hour)
+
+	res := r.runScenario(ctx, "join_users_country_7d", func(ctx context.Context) error {
+		_, err := users.GetCountryBreakdown(ctx, start, now)
+		return err
+	})
+
+	if res.Iterations > 0 {
+		res.DateRange = dateRange(start, now)
+	}
+
+	return res
+}
+
+// runSessions counts the user sessions of the last day with window
+// functions. Unlike the other optional scenarios it reports databases that
+// cannot express the query as not supported, so the gap in analytical
+// expressiveness shows up in the report.
+func (r *Runner) runSessions(ctx context.Context, repo Repository, now time.Time) *QueryResult {
+	const name = "sessions_1_day"
+
+	sessions, ok := repo.(SessionRepository)
+	if !ok {
+		return &QueryResult{QueryName: name, ErrorText: "not supported"}
+	}
+
+	start := now.Add(-24 * time.Hour)
+
+	res := r.runScenario(ctx, name, func(ctx context.Context) error {
+		_, err := sessions.CountSessions(ctx, start, now, sessionGap)
+		return err
+	})
+
+	if res.Iterations > 0 {
+		res.DateRange = dateRange(start, now)
+	}
+
+	return res
+}
+
+// runPagination pages through the day before now in pages of
+// paginationPageSize. Every page is one latency sample, so Iterations counts
+// pages, and the rows of the successful scans over their duration give the
+// scan throughput.
+func (r *Runner) runPagination(ctx context.Context, repo Repository, now time.Time) *QueryResult {
+	pager, ok := repo.(PaginationRepository)
+	if !ok {
+		return nil
+	}
+
+	start := now.Add(-24 * time.Hour)
+	scan := func(ctx context.Context, page repository.PageFunc) error {
+		return pager.ScanPages(ctx, start, now, paginationPageSize, page)
+	}
+
+	r.warmUpScans(ctx, scan)
+
+	pages, rows, elapsed, errors, aborted := r.measureScans(ctx, scan)
+
+	res := newQueryResult("pagination_1_day", pages, errors)
+	res.Aborted, res.Interrupted = aborted, ctx.Err() != nil
+
+	if res.Iterations > 0 {
+		res.DateRange = dateRange(start, now)
+		res.Rows = rows
+		res.Throughput = float64(rows) / elapsed.Seconds()
+	}
+
+	return res
+}
+
+// runExport streams the day before now out of the database in one query.
+// Every export is one latency sample, and the rows of the successful exports
+// over their summed duration give the egress throughput.
+func (r *Runner) runExport(ctx context.Context, repo Repository, now time.Time) *QueryResult {
+	exporter, ok := repo.(ExportRepository)
+	if !ok {
+		return nil
+	}
+
+	start := now.Add(-24 * time.Hour)
+
+	for i := 0; i < r.WarmupIterations && ctx.Err() == nil; i++ {
+		_, _ = exporter.ExportEvents(ctx, start, now)
+	}
+
+	var rows atomic.Int64
+
+	res := r.measureQuery(ctx, "export_1_day", func(ctx context.Context) error {
+		n, err := exporter.ExportEvents(ctx, start, now)
+		if err == nil {
+			rows.Add(n)
+		}
+
+		return err
+	}).result("export_1_day")
+
+	if res.Iterations > 0 {
+		res.DateRange = dateRange(start, now)
+		res.Rows = rows.Load()
+		res.Throughput = float64(res.Rows) / (res.AvgDuration * time.Duration(res.Iterations)).Seconds()
+	}
+
+	return res
+}
+
+// warmUpScans runs WarmupIterations unmeasured paginated scans.
+func (r *Runner) warmUpScans(ctx context.Context, scan func(context.Context, repository.PageFunc) error) {
+	for i := 0; i < r.WarmupIterations && ctx.Err() == nil; i++ {
+		_ = scan(ctx, func(int) {})
+	}
+}
+
+// measureScans runs QueryIterations paginated scans and returns the page
+// latencies, rows and total duration of the scans that succeeded. aborted
+// is set when too many scans failed to go on.
+func (r *Runner) measureScans(
+	ctx context.Context, scan func(context.Context, repository.PageFunc) error,
+) (pages []time.Duration, rows int64, elapsed time.Duration, errors int64, aborted bool) {
+	guard := newErrorGuard(r.MaxErrorRate)
+
+	for i := 0; i < r.QueryIterations && !aborted && ctx.Err() == nil; i++ {
+		d, n, total, err := timePages(ctx, scan)
+		if aborted = guard.observe(err != nil); err != nil {
+			errors++
+
+			log.Printf("Query error: %v", err)
+
+			continue
+		}
+
+		pages, rows, elapsed = append(pages, d...), rows+n, elapsed+total
+	}
+
+	return
+}
+
+// timePages runs one paginated scan and returns the latency of every page,
+// the rows read and the duration of the whole scan.
+func timePages(
+	ctx context.Context, scan func(context.Context, repository.PageFunc) error,
+) (pages []time.Duration, rows int64, total time.Duration, err error) {
+	start := time.Now()
+	last := start
+
+	err = scan(ctx, func(n int) {
+		now := time.Now()
+		pages = append(pages, now.Sub(last))
+		last = now
+		rows += int64(n)
+	})
+
+	return pages, rows, time.Since(start), err
+}
+
+// runSampled runs a scenario whose every iteration queries a random event
+// sampled from the inserts into repo.
+func (r *Runner) runSampled(
+	ctx context.Context, repo Repository, name string, query func(context.Context, generator.Event) error,
+) *QueryResult {
+	sample := r.sampleFor(repo)
+	if _, ok := sample.pick(); !ok {
+		log.Printf("Skipping %s: no inserted events to sample", name)
+		return nil
+	}
+
+	return r.runScenario(ctx, name, func(ctx context.Context) error {
+		event, _ := sample.pick()
+		return query(ctx, event)
+	})
+}
+
+// runScenario runs query for the warmup iterations, then measures it for
+// QueryIterations.
+func (r *Runner) runScenario(ctx context.Context, name string, query func(context.Context) error) *QueryResult {
+	ctx, span := telemetryFrom(ctx).start(ctx, "scenario "+name, attribute.String("dbbench.scenario", name))
+	defer span.End()
+
+	for i := 0; i < r.WarmupIterations && ctx.Err() == nil; i++ {
+		_ = query(ctx)
+	}
+
+	return r.measureQuery(ctx, name, query).result(name)
+}
+
+// newQueryResult summarizes the latencies of a scenario.
+func newQueryResult(name string, durations []time.Duration, errors int64) *QueryResult {
 	if len(durations) == 0 {
 		return &QueryResult{QueryName: name, ErrorCount: errors}
 	}
@@ -154,26 +902,241 @@ func (r *Runner) runQuery(ctx context.Context, repo Repository, name string, sta
 		P95Duration: Percentile(durations, 0.95),
 		P99Duration: Percentile(durations, 0.99),
 		ErrorCount:  errors,
-		DateRange:   fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02")),
+		Histogram:   NewHistogram(durations),
 	}
 }
 
-func (r *Runner) measureQuery(ctx context.Context, repo Repository, start, end time.Time) (durations []time.Duration, errors int64) {
-	for i := 0; i < r.QueryIterations; i++ {
-		queryStart := time.Now()
-		_, err := repo.GetEventStats(ctx, start, end)
-		d := time.Since(queryStart)
+// withCorrected adds the percentiles of the latencies from the intended
+// start of a paced scenario; it leaves unpaced results unchanged.
+func (q *QueryResult) withCorrected(corrected []time.Duration) *QueryResult {
+	if len(corrected) > 0 {
+		q.CorrectedP50 = Percentile(corrected, 0.50)
+		q.CorrectedP95 = Percentile(corrected, 0.95)
+		q.CorrectedP99 = Percentile(corrected, 0.99)
+	}
 
-		if err != nil {
-			errors++
+	return q
+}
 
-			log.Printf("Query error: %v", err)
+func dateRange(start, end time.Time) string {
+	return fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+}
+
+// queryRuns are the measured runs of a query scenario: the latencies of the
+// successful runs, and their latencies from the intended start when paced.
+// aborted is set when the runs stopped early on too many errors, and
+// interrupted when they were cut short by a cancellation. elapsed is the
+// wall time of the measurement across all workers.
+type queryRuns struct {
+	scenario    string
+	obs         Observer
+	mu          sync.Mutex
+	durations   []time.Duration
+	corrected   []time.Duration
+	errors      int64
+	retries     int64
+	workers     int
+	elapsed     time.Duration
+	aborted     bool
+	interrupted bool
+	client      *ClientResources
+}
+
+// result summarizes the runs as the result of the named scenario.
+func (q *queryRuns) result(name string) *QueryResult {
+	res := newQueryResult(name, q.durations, q.errors).withCorrected(q.corrected)
+	res.Retries, res.Aborted, res.Interrupted, res.Client = q.retries, q.aborted, q.interrupted, q.client
+
+	if q.workers > 1 {
+		res.Workers = q.workers
+	}
+
+	if len(q.durations) > 0 && q.elapsed > 0 {
+		res.QPS = float64(len(q.durations)) / q.elapsed.Seconds()
+	}
+
+	return res
+}
+
+// record adds the outcome of one run that started at start and ended at end.
+func (q *queryRuns) record(start, end, due time.Time, retries int, err error) {
+	q.obs.Queried(q.scenario, end.Sub(start), err)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.retries += int64(retries)
+
+	if err != nil {
+		q.errors++
+		return
+	}
+
+	q.durations = append(q.durations, end.Sub(start))
+
+	if d, ok := sinceDue(due, end); ok {
+		q.corrected = append(q.corrected, d)
+	}
+}
+
+func (q *queryRuns) interrupt() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.interrupted = true
+}
+
+// measureQuery runs query QueryIterations times, spread over QueryWorkers
+// concurrent workers, back to back or on the schedule of QueryRate shared by
+// the workers, under the retry policy. The latency of a run includes its
+// retries and their backoff; a paced run that waits for a free worker counts
+// that wait in its corrected latency. Once ctx is done no further run starts,
+// but those in flight are finished. Every run is reported to the Observer of
+// ctx under scenario.
+func (r *Runner) measureQuery(ctx context.Context, scenario string, query func(context.Context) error) *queryRuns {
+	guard := newErrorGuard(r.MaxErrorRate)
+	runs := &queryRuns{scenario: scenario, obs: observerFrom(ctx), workers: max(r.QueryWorkers, 1)}
+	due := make(chan time.Time)
+
+	var wg sync.WaitGroup
+
+	probe := startClientProbe()
+
+	for range runs.workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			r.queryWorker(ctx, query, due, guard, runs)
+		}()
+	}
+
+	if r.dispatchQueries(ctx, due, guard) {
+		runs.interrupt()
+	}
+
+	wg.Wait()
+
+	runs.elapsed, runs.aborted, runs.client = time.Since(probe.start), guard.aborted(), probe.stop()
+
+	return runs
+}
+
+// dispatchQueries releases the runs to the workers, each with the time it
+// is due, and closes due once all are released or the guard aborts. It
+// reports whether ctx was done first.
+func (r *Runner) dispatchQueries(ctx context.Context, due chan<- time.Time, guard *errorGuard) (interrupted bool) {
+	defer close(due)
 
+	pace := newPacer(r.QueryRate)
+
+	for i := 0; i < r.QueryIterations && !guard.aborted(); i++ {
+		if ctx.Err() != nil {
+			return true
+		}
+
+		select {
+		case due <- pace.wait(1):
+		case <-ctx.Done():
+			return true
+		}
+	}
+
+	return false
+}
+
+// queryWorker runs the released runs of a scenario. A run released just as
+// ctx was done or the guard aborted is dropped instead.
+func (r *Runner) queryWorker(
+	ctx context.Context, query func(context.Context) error, due <-chan time.Time, guard *errorGuard, runs *queryRuns,
+) {
+	for d := range due {
+		if ctx.Err() != nil {
+			runs.interrupt()
+			continue
+		}
+
+		if guard.aborted() {
 			continue
 		}
 
-		durations = append(durations, d)
+		queryStart := time.Now()
+		retries, err := r.runTraced(ctx, runs.scenario, query)
+		end := time.Now()
+
+		if guard.observe(err != nil); err != nil {
+			log.Printf("Query error: %v", err)
+		}
+
+		runs.record(queryStart, end, d, retries, err)
 	}
+}
 
-	return
+// runTraced runs query once under the retry policy, in a span of its own
+// when ctx has telemetry. A run that started is finished even once ctx is
+// done.
+func (r *Runner) runTraced(ctx context.Context, scenario string, query func(context.Context) error) (int, error) {
+	tel := telemetryFrom(ctx)
+	name := attribute.String("dbbench.scenario", scenario)
+	ctx, span := tel.start(context.WithoutCancel(ctx), "query", name)
+	start := time.Now()
+	retries, err := r.retrying(ctx, query)
+
+	span.SetAttributes(attribute.Int("dbbench.retries", retries))
+	tel.end(ctx, span, queryDuration, queryErrors, time.Since(start), err, name)
+
+	return retries, err
+}
+
+// RunRetention deletes events created more than days ago and measures the
+// delete throughput and the storage reclaimed.
+func (r *Runner) RunRetention(ctx context.Context, repo Repository, days int) *RetentionResult {
+	res := &RetentionResult{Cutoff: time.Now().AddDate(0, 0, -days)}
+
+	deleter, ok := repo.(RetentionRepository)
+	if !ok {
+		res.ErrorText = "not supported"
+		return res
+	}
+
+	before := storageSize(ctx, repo)
+
+	if err := deleteExpired(ctx, deleter, res); err != nil {
+		res.ErrorText = err.Error()
+		return res
+	}
+
+	res.SizeBefore = before
+	res.SizeAfter = storageSize(ctx, repo)
+	res.ReclaimedBytes = res.SizeBefore - res.SizeAfter
+
+	return res
+}
+
+// deleteExpired deletes the events created before res.Cutoff and records the
+// duration, method and throughput of the delete in res.
+func deleteExpired(ctx context.Context, deleter RetentionRepository, res *RetentionResult) error {
+	start := time.Now()
+
+	stats, err := deleter.DeleteOlderThan(ctx, res.Cutoff)
+	res.Duration = time.Since(start)
+
+	if err != nil {
+		return err
+	}
+
+	res.Method = stats.Method
+	res.RowsDeleted = stats.Rows
+	res.Throughput = float64(stats.Rows) / res.Duration.Seconds()
+
+	return nil
+}
+
+func storageSize(ctx context.Context, repo Repository) int64 {
+	if s := repo.GetStorageStats(ctx); s != nil {
+		return s.TotalSize
+	}
+
+	return 0
 }
@@ -2,7 +2,9 @@ package benchmark
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -67,6 +69,239 @@ func TestRunInsert(t *testing.T) {
 	// Throughput should be based on actually inserted events
 	expectedThroughput := float64(100) / result.Duration.Seconds()
 	assert.InDelta(t, expectedThroughput, result.Throughput, 1.0)
+
+	var timelineEvents int64
+	for _, sample := range result.Timeline {
+		timelineEvents += sample.Events
+	}
+
+	assert.Equal(t, int64(100), timelineEvents)
+}
+
+func TestRunInsertUserDist(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		users = make(map[int64]int)
+	)
+
+	mock := &mockRepository{
+		insertBatchFunc: func(_ context.Context, events []generator.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, e := range events {
+				users[e.UserID]++
+			}
+
+			return nil
+		},
+	}
+
+	runner := &Runner{EventCount: 5000, BatchSize: 100, Workers: 2, UserDist: generator.UserDist{Skew: 1.5}}
+	runner.RunInsert(context.Background(), mock)
+
+	assert.Less(t, len(users), 2500, "a zipfian distribution should concentrate the events on few users")
+}
+
+func TestRunInsertIDFormat(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		ids []string
+	)
+
+	mock := &mockRepository{
+		insertBatchFunc: func(_ context.Context, events []generator.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, e := range events {
+				ids = append(ids, e.ID)
+			}
+
+			return nil
+		},
+	}
+
+	runner := &Runner{EventCount: 1000, BatchSize: 100, Workers: 2, IDFormat: generator.IDSnowflake}
+	runner.RunInsert(context.Background(), mock)
+
+	require.Len(t, ids, 1000)
+
+	for _, id := range ids {
+		assert.Regexp(t, `^\d{19}$`, id)
+	}
+}
+
+func TestRunInsertWarmup(t *testing.T) {
+	var inserted int64
+
+	mock := &mockRepository{
+		insertBatchFunc: func(_ context.Context, events []generator.Event) error {
+			atomic.AddInt64(&inserted, int64(len(events)))
+			return nil
+		},
+	}
+
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 2, WarmupBatches: 3}
+
+	result := runner.RunInsert(context.Background(), mock)
+
+	assert.Equal(t, int64(130), atomic.LoadInt64(&inserted))
+	assert.Equal(t, 100, result.TotalEvents)
+
+	var timelineEvents int64
+	for _, sample := range result.Timeline {
+		timelineEvents += sample.Events
+	}
+
+	assert.Equal(t, int64(100), timelineEvents, "warm-up batches must not be measured")
+	assert.Equal(t, &WarmupConfig{InsertBatches: 3}, runner.Warmup())
+}
+
+func TestRunInsertWorkerStats(t *testing.T) {
+	var calls int64
+
+	mock := &mockRepository{
+		insertBatchFunc: func(context.Context, []generator.Event) error {
+			if atomic.AddInt64(&calls, 1)%5 == 0 {
+				return errors.New("connection reset")
+			}
+
+			return nil
+		},
+	}
+
+	runner := &Runner{EventCount: 200, BatchSize: 10, Workers: 3}
+
+	result := runner.RunInsert(context.Background(), mock)
+
+	require.Len(t, result.Workers, 3)
+
+	var events, batches, errs int64
+
+	for i, w := range result.Workers {
+		assert.Equal(t, i, w.Worker)
+		assert.GreaterOrEqual(t, w.LatencyMax, w.LatencyP50)
+
+		events += w.Events
+		batches += w.Batches
+		errs += w.Errors
+	}
+
+	assert.Equal(t, 200-10*result.ErrorCount, events)
+	assert.Equal(t, 20-result.ErrorCount, batches)
+	assert.Equal(t, result.ErrorCount, errs)
+	assert.Positive(t, errs)
+}
+
+func TestRunInsertMaxErrorRate(t *testing.T) {
+	var calls int64
+
+	mock := &mockRepository{
+		insertBatchFunc: func(context.Context, []generator.Event) error {
+			atomic.AddInt64(&calls, 1)
+			return errors.New("connection refused")
+		},
+	}
+
+	runner := &Runner{EventCount: 1000, BatchSize: 10, Workers: 2, MaxErrorRate: 50}
+
+	result := runner.RunInsert(context.Background(), mock)
+
+	assert.True(t, result.Aborted)
+	assert.Less(t, atomic.LoadInt64(&calls), int64(100), "the remaining batches must not be inserted")
+	assert.Equal(t, atomic.LoadInt64(&calls), result.ErrorCount)
+
+	res := &Results{Insert: result}
+	res.MarkDegraded()
+	assert.True(t, res.Degraded)
+}
+
+func TestRunScenarioMaxErrorRate(t *testing.T) {
+	runner := &Runner{QueryIterations: 100, MaxErrorRate: 20}
+
+	var calls int
+
+	failing := runner.runScenario(context.Background(), "failing", func(context.Context) error {
+		calls++
+		return errors.New("relation does not exist")
+	})
+
+	assert.True(t, failing.Aborted)
+	assert.Equal(t, minGuardOps, calls)
+	assert.Equal(t, int64(minGuardOps), failing.ErrorCount)
+
+	// One failure in ten stays under a 20% limit.
+	calls = 0
+	flaky := runner.runScenario(context.Background(), "flaky", func(context.Context) error {
+		calls++
+		if calls%10 == 0 {
+			return errors.New("timeout")
+		}
+
+		return nil
+	})
+
+	assert.False(t, flaky.Aborted)
+	assert.Equal(t, 90, flaky.Iterations)
+}
+
+func TestRunInsertInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls, failed int64
+
+	mock := &mockRepository{
+		insertBatchFunc: func(ctx context.Context, _ []generator.Event) error {
+			if atomic.AddInt64(&calls, 1) == 5 {
+				cancel()
+			}
+
+			time.Sleep(time.Millisecond)
+
+			if ctx.Err() != nil {
+				atomic.AddInt64(&failed, 1)
+			}
+
+			return ctx.Err()
+		},
+	}
+
+	runner := &Runner{EventCount: 1000, BatchSize: 10, Workers: 2}
+
+	result := runner.RunInsert(ctx, mock)
+
+	assert.True(t, result.Interrupted)
+	assert.Zero(t, result.ErrorCount, "in-flight batches must finish, not fail")
+	assert.Zero(t, atomic.LoadInt64(&failed))
+	assert.Less(t, result.TotalEvents, 1000)
+	assert.Equal(t, int(atomic.LoadInt64(&calls))*10, result.TotalEvents)
+}
+
+func TestRunQueriesInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int
+
+	runner := &Runner{QueryIterations: 100}
+
+	qr := runner.runScenario(ctx, "interrupted", func(context.Context) error {
+		if calls++; calls == 10 {
+			cancel()
+		}
+
+		return nil
+	})
+
+	assert.True(t, qr.Interrupted)
+	assert.Equal(t, 10, qr.Iterations)
+	assert.False(t, qr.skipped())
+
+	results := runner.RunQueries(ctx, &mockRepository{})
+	assert.NotContains(t, results, "1_hour", "scenarios that never started must be left out")
+	assert.NotContains(t, results, "1_month")
 }
 
 func TestRunInsertWithErrors(t *testing.T) {
@@ -109,7 +344,8 @@ func TestRunQueries(t *testing.T) {
 
 	results := runner.RunQueries(context.Background(), mock)
 
-	require.Len(t, results, 4)
+	// The time-range scenarios, plus sessions_1_day marked as not supported.
+	require.Len(t, results, 5)
 
 	for _, name := range []string{"1_hour", "1_day", "1_week", "1_month"} {
 		qr, ok := results[name]
@@ -118,6 +354,8 @@ func TestRunQueries(t *testing.T) {
 		assert.Equal(t, 5, qr.Iterations)
 		assert.Equal(t, int64(0), qr.ErrorCount)
 	}
+
+	assert.Equal(t, "not supported", results["sessions_1_day"].ErrorText)
 }
 
 func TestRunQueryWarmup(t *testing.T) {
@@ -136,3 +374,519 @@ func TestRunQueryWarmup(t *testing.T) {
 	// Total calls = warmup (3) + iterations (10)
 	assert.Equal(t, int64(13), atomic.LoadInt64(&mock.callCount))
 }
+
+func TestRunQueryConcurrent(t *testing.T) {
+	var (
+		mu                  sync.Mutex
+		active, peak, calls int64
+	)
+
+	mock := &mockRepository{
+		getEventStatsFunc: func(context.Context, time.Time, time.Time) ([]repository.EventStats, error) {
+			mu.Lock()
+			active++
+			peak = max(peak, active)
+			mu.Unlock()
+
+			defer func() {
+				mu.Lock()
+				active--
+				mu.Unlock()
+			}()
+
+			time.Sleep(5 * time.Millisecond)
+
+			if atomic.AddInt64(&calls, 1)%4 == 0 {
+				return nil, errors.New("too many connections")
+			}
+
+			return nil, nil
+		},
+	}
+
+	runner := &Runner{QueryIterations: 40, QueryWorkers: 4}
+
+	qr := runner.runQuery(context.Background(), mock, "1_hour", time.Now().Add(-time.Hour), time.Now())
+
+	assert.Equal(t, 30, qr.Iterations)
+	assert.Equal(t, int64(10), qr.ErrorCount)
+	assert.Equal(t, 4, qr.Workers)
+	assert.Greater(t, peak, int64(1), "runs must overlap")
+	assert.LessOrEqual(t, peak, int64(4))
+	// Four workers finish the 40 runs of 5ms in about 50ms, well over the
+	// 200 QPS a single worker can reach.
+	assert.Greater(t, qr.QPS, 200.0)
+}
+
+func TestRunQuerySequentialQPS(t *testing.T) {
+	runner := &Runner{QueryIterations: 5}
+
+	qr := runner.runQuery(context.Background(), &mockRepository{}, "1_hour", time.Now().Add(-time.Hour), time.Now())
+
+	assert.Zero(t, qr.Workers, "a single worker is not reported")
+	assert.Positive(t, qr.QPS)
+}
+
+// retentionMockRepository adds DeleteOlderThan to mockRepository.
+type retentionMockRepository struct {
+	mockRepository
+	cutoff time.Time
+}
+
+func (m *retentionMockRepository) DeleteOlderThan(_ context.Context, cutoff time.Time) (*repository.DeleteStats, error) {
+	m.cutoff = cutoff
+	return &repository.DeleteStats{Rows: 500, Method: "drop_partition"}, nil
+}
+
+func TestRunRetention(t *testing.T) {
+	mock := &retentionMockRepository{}
+	runner := &Runner{}
+
+	result := runner.RunRetention(context.Background(), mock, 7)
+
+	require.NotNil(t, result)
+	assert.Empty(t, result.ErrorText)
+	assert.Equal(t, "drop_partition", result.Method)
+	assert.Equal(t, int64(500), result.RowsDeleted)
+	assert.Equal(t, mock.cutoff, result.Cutoff)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), result.Cutoff, time.Minute)
+	assert.Greater(t, result.Throughput, 0.0)
+}
+
+func TestRunRetentionUnsupported(t *testing.T) {
+	result := (&Runner{}).RunRetention(context.Background(), &mockRepository{}, 7)
+
+	require.NotNil(t, result)
+	assert.Equal(t, "not supported", result.ErrorText)
+	assert.Zero(t, result.RowsDeleted)
+}
+
+// lookupMockRepository adds GetEventByID, EventExists and GetUserEvents to
+// mockRepository and records the looked up IDs and users.
+type lookupMockRepository struct {
+	mockRepository
+	mu     sync.Mutex
+	lookup []string
+	exists []string
+	users  []int64
+}
+
+func (m *lookupMockRepository) EventExists(_ context.Context, eventID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.exists = append(m.exists, eventID)
+
+	return true, nil
+}
+
+func (m *lookupMockRepository) GetUserEvents(_ context.Context, userID int64, _ int) ([]generator.Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.users = append(m.users, userID)
+
+	return nil, nil
+}
+
+func (m *lookupMockRepository) GetEventByID(_ context.Context, eventID string) (*generator.Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lookup = append(m.lookup, eventID)
+
+	return &generator.Event{ID: eventID}, nil
+}
+
+func TestRunQueriesSampledScenarios(t *testing.T) {
+	mock := &lookupMockRepository{}
+	runner := &Runner{EventCount: 50, BatchSize: 10, Workers: 2, QueryIterations: 5, WarmupIterations: 1}
+
+	// Without inserted events there is nothing to look up.
+	results := runner.RunQueries(context.Background(), mock)
+	assert.NotContains(t, results, "point_lookup")
+	assert.NotContains(t, results, "user_history")
+	assert.NotContains(t, results, "exists_by_id")
+
+	inserted := make(map[string]bool)
+	users := make(map[int64]bool)
+	mock.insertBatchFunc = func(_ context.Context, events []generator.Event) error {
+		mock.mu.Lock()
+		defer mock.mu.Unlock()
+
+		for _, e := range events {
+			inserted[e.ID] = true
+			users[e.UserID] = true
+		}
+
+		return nil
+	}
+
+	runner.RunInsert(context.Background(), mock)
+
+	results = runner.RunQueries(context.Background(), mock)
+	require.Contains(t, results, "point_lookup")
+	require.Contains(t, results, "user_history")
+	assert.Equal(t, 5, results["point_lookup"].Iterations)
+	assert.Equal(t, 5, results["user_history"].Iterations)
+	require.Len(t, mock.lookup, 6)
+	require.Len(t, mock.users, 6)
+	assert.Equal(t, 5, results["exists_by_id"].Iterations)
+	require.Len(t, mock.exists, 6)
+
+	for _, id := range append(mock.lookup, mock.exists...) {
+		assert.True(t, inserted[id], "looked up an event that was never inserted: %s", id)
+	}
+
+	for _, id := range mock.users {
+		assert.True(t, users[id], "queried a user that never inserted events: %d", id)
+	}
+}
+
+func TestEventSample(t *testing.T) {
+	var s eventSample
+
+	_, ok := s.pick()
+	assert.False(t, ok)
+
+	batch := make([]generator.Event, sampleSize*3)
+	for i := range batch {
+		batch[i] = generator.Event{ID: fmt.Sprint(i), Payload: "{}"}
+	}
+
+	s.add(batch)
+
+	assert.Len(t, s.events, sampleSize)
+	assert.Equal(t, int64(sampleSize*3), s.seen)
+
+	e, ok := s.pick()
+	require.True(t, ok)
+	assert.Empty(t, e.Payload)
+}
+
+func TestRunInsertDuplicates(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen = make(map[string]int)
+	)
+
+	mock := &mockRepository{
+		insertBatchFunc: func(_ context.Context, events []generator.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, e := range events {
+				seen[e.ID]++
+			}
+
+			return nil
+		},
+	}
+
+	runner := &Runner{EventCount: 1000, BatchSize: 100, Workers: 2, DuplicatePct: 50}
+
+	result := runner.RunInsert(context.Background(), mock)
+
+	require.NotNil(t, result)
+	assert.Positive(t, result.Duplicates)
+	// The first batch has nothing to duplicate.
+	assert.LessOrEqual(t, result.Duplicates, int64(900))
+
+	var resent int64
+	for _, n := range seen {
+		resent += int64(n - 1)
+	}
+
+	assert.Equal(t, result.Duplicates, resent)
+}
+
+func TestRunInsertRate(t *testing.T) {
+	mock := &mockRepository{
+		insertBatchFunc: func(context.Context, []generator.Event) error {
+			time.Sleep(time.Millisecond)
+			return nil
+		},
+	}
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 4, Rate: 1000}
+
+	result := runner.RunInsert(context.Background(), mock)
+
+	// Ten batches at 1000 events/sec: the last one is due 90ms after the first.
+	assert.GreaterOrEqual(t, result.Duration, 90*time.Millisecond)
+	assert.LessOrEqual(t, result.Throughput, 1200.0)
+	assert.InDelta(t, 1000.0, result.TargetRate, 0)
+	assert.GreaterOrEqual(t, result.LatencyP50, time.Millisecond)
+	assert.GreaterOrEqual(t, result.LatencyP99, result.LatencyP50)
+}
+
+func TestRunInsertCorrectedLatency(t *testing.T) {
+	var calls atomic.Int64
+
+	// The first batch stalls for 50ms; the batches due during the stall wait
+	// behind it but are fast once they start.
+	mock := &mockRepository{
+		insertBatchFunc: func(context.Context, []generator.Event) error {
+			if calls.Add(1) == 1 {
+				time.Sleep(50 * time.Millisecond)
+			}
+
+			return nil
+		},
+	}
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 1, Rate: 1000}
+
+	result := runner.RunInsert(context.Background(), mock)
+
+	assert.Less(t, result.LatencyP50, 10*time.Millisecond)
+	assert.Greater(t, result.CorrectedP50, result.LatencyP50)
+	assert.GreaterOrEqual(t, result.CorrectedP99, 50*time.Millisecond)
+}
+
+func TestRunQueriesCorrectedLatency(t *testing.T) {
+	runner := &Runner{QueryIterations: 5, QueryRate: 200}
+
+	paced := runner.runScenario(context.Background(), "paced", func(context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+
+	// Each run takes 10ms but is due every 5ms, so the backlog grows.
+	assert.Greater(t, paced.CorrectedP99, paced.P99Duration)
+
+	runner.QueryRate = 0
+	unpaced := runner.runScenario(context.Background(), "unpaced", func(context.Context) error { return nil })
+	assert.Zero(t, unpaced.CorrectedP99)
+}
+
+func TestPacerDisabled(t *testing.T) {
+	pace := newPacer(0)
+	assert.Nil(t, pace)
+
+	start := time.Now()
+	pace.wait(1000)
+	assert.Less(t, time.Since(start), 10*time.Millisecond)
+}
+
+func TestPacerRamp(t *testing.T) {
+	pace := newPacer(100).withRamp(2 * time.Second)
+
+	// The rate climbs to 100/s over 2s, so the first 100 events take the
+	// whole ramp and the next 100 one more second.
+	assert.Zero(t, pace.offset(0))
+	assert.Equal(t, time.Second, pace.offset(25))
+	assert.Equal(t, 2*time.Second, pace.offset(100))
+	assert.Equal(t, 3*time.Second, pace.offset(200))
+
+	assert.Nil(t, newPacer(0).withRamp(time.Second))
+}
+
+func TestRunInsertRampUp(t *testing.T) {
+	mock := &mockRepository{
+		insertBatchFunc: func(context.Context, []generator.Event) error {
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		},
+	}
+
+	runner := &Runner{EventCount: 200, BatchSize: 10, Workers: 2, RampUp: 30 * time.Millisecond}
+
+	result := runner.RunInsert(context.Background(), mock)
+
+	assert.Equal(t, 30*time.Millisecond, result.RampUp)
+	assert.Positive(t, result.RampEvents)
+	assert.Less(t, result.RampEvents, int64(200))
+
+	steady := float64(200-result.RampEvents) / (result.Duration - result.RampUp).Seconds()
+	assert.InDelta(t, steady, result.Throughput, 1.0)
+}
+
+func TestDuplicatorDisabled(t *testing.T) {
+	dup := newDuplicator(0)
+	assert.Nil(t, dup)
+
+	batch := []generator.Event{{ID: "a"}}
+	dup.apply(batch)
+
+	assert.Equal(t, "a", batch[0].ID)
+	assert.Zero(t, dup.duplicates())
+}
+
+// topUsersMockRepository adds GetTopUsers, GetUserGroups, SearchPayload and
+// CountEvents to mockRepository.
+type topUsersMockRepository struct {
+	mockRepository
+	start, end  time.Time
+	n           int
+	groupsStart time.Time
+	token       string
+	searchStart time.Time
+	countStart  time.Time
+}
+
+func (m *topUsersMockRepository) CountEvents(_ context.Context, start, _ time.Time) (int64, error) {
+	m.countStart = start
+	return 0, nil
+}
+
+func (m *topUsersMockRepository) SearchPayload(_ context.Context, token string, start, _ time.Time) (int64, error) {
+	m.token, m.searchStart = token, start
+	return 0, nil
+}
+
+func (m *topUsersMockRepository) GetUserGroups(_ context.Context, start, _ time.Time) (*repository.UserGroupStats, error) {
+	m.groupsStart = start
+	return &repository.UserGroupStats{}, nil
+}
+
+func (m *topUsersMockRepository) GetTopUsers(_ context.Context, start, end time.Time, n int) ([]repository.UserCount, error) {
+	m.start, m.end, m.n = start, end, n
+	return nil, nil
+}
+
+func TestRunQueriesAggregations(t *testing.T) {
+	mock := &topUsersMockRepository{}
+	runner := &Runner{QueryIterations: 3}
+
+	results := runner.RunQueries(context.Background(), mock)
+
+	qr, ok := results["top_users_7d"]
+	require.True(t, ok)
+	assert.Equal(t, 3, qr.Iterations)
+	assert.Equal(t, 10, mock.n)
+	assert.Equal(t, 7*24*time.Hour, mock.end.Sub(mock.start))
+
+	qr, ok = results["group_by_user_1_month"]
+	require.True(t, ok)
+	assert.Equal(t, 3, qr.Iterations)
+	assert.Equal(t, 30*24*time.Hour, mock.end.Sub(mock.groupsStart))
+
+	qr, ok = results["payload_search_1_day"]
+	require.True(t, ok)
+	assert.Equal(t, 3, qr.Iterations)
+	assert.Equal(t, "timeout", mock.token)
+	assert.Equal(t, 24*time.Hour, mock.end.Sub(mock.searchStart))
+
+	qr, ok = results["count_1_day"]
+	require.True(t, ok)
+	assert.Equal(t, 3, qr.Iterations)
+	assert.Equal(t, 24*time.Hour, mock.end.Sub(mock.countStart))
+}
+
+// pagingMockRepository serves three pages of the given sizes per scan.
+type pagingMockRepository struct {
+	mockRepository
+	pageSize int
+}
+
+func (m *pagingMockRepository) ScanPages(_ context.Context, _, _ time.Time, pageSize int, page repository.PageFunc) error {
+	m.pageSize = pageSize
+
+	for _, n := range []int{pageSize, pageSize, 10} {
+		page(n)
+	}
+
+	return nil
+}
+
+func TestRunQueriesPagination(t *testing.T) {
+	mock := &pagingMockRepository{}
+	runner := &Runner{QueryIterations: 2}
+
+	results := runner.RunQueries(context.Background(), mock)
+
+	qr, ok := results["pagination_1_day"]
+	require.True(t, ok)
+	assert.Equal(t, 1000, mock.pageSize)
+	assert.Equal(t, 6, qr.Iterations)
+	assert.Equal(t, int64(2*2010), qr.Rows)
+	assert.Positive(t, qr.Throughput)
+
+	_, ok = runner.RunQueries(context.Background(), &mockRepository{})["pagination_1_day"]
+	assert.False(t, ok)
+}
+
+// exportMockRepository exports 500 rows per call.
+type exportMockRepository struct {
+	mockRepository
+	start, end time.Time
+}
+
+func (m *exportMockRepository) ExportEvents(_ context.Context, start, end time.Time) (int64, error) {
+	m.start, m.end = start, end
+
+	time.Sleep(time.Millisecond)
+
+	return 500, nil
+}
+
+func TestRunQueriesExport(t *testing.T) {
+	mock := &exportMockRepository{}
+	runner := &Runner{QueryIterations: 3, WarmupIterations: 1}
+
+	qr, ok := runner.RunQueries(context.Background(), mock)["export_1_day"]
+	require.True(t, ok)
+	assert.Equal(t, 24*time.Hour, mock.end.Sub(mock.start))
+	assert.Equal(t, 3, qr.Iterations)
+	assert.Equal(t, int64(1500), qr.Rows, "warmup exports are not counted")
+	assert.Positive(t, qr.Throughput)
+}
+
+// sessionsMockRepository adds CountSessions to mockRepository.
+type sessionsMockRepository struct {
+	mockRepository
+	gap time.Duration
+}
+
+func (m *sessionsMockRepository) CountSessions(_ context.Context, _, _ time.Time, gap time.Duration) (int64, error) {
+	m.gap = gap
+	return 42, nil
+}
+
+func TestRunQueriesSessions(t *testing.T) {
+	mock := &sessionsMockRepository{}
+	runner := &Runner{QueryIterations: 2}
+
+	qr, ok := runner.RunQueries(context.Background(), mock)["sessions_1_day"]
+	require.True(t, ok)
+	assert.Empty(t, qr.ErrorText)
+	assert.Equal(t, 2, qr.Iterations)
+	assert.Equal(t, 30*time.Minute, mock.gap)
+	assert.NotEmpty(t, qr.DateRange)
+}
+
+// usersMockRepository adds the users table to mockRepository.
+type usersMockRepository struct {
+	mockRepository
+	users      int
+	joinCalled bool
+}
+
+func (m *usersMockRepository) InitUsersSchema(context.Context) error { return nil }
+
+func (m *usersMockRepository) InsertUsers(_ context.Context, users []generator.User) error {
+	m.users += len(users)
+	return nil
+}
+
+func (m *usersMockRepository) GetCountryBreakdown(context.Context, time.Time, time.Time) ([]repository.CountryCount, error) {
+	m.joinCalled = true
+	return nil, nil
+}
+
+func TestRunQueriesJoin(t *testing.T) {
+	mock := &usersMockRepository{}
+	runner := &Runner{BatchSize: 10, QueryIterations: 2}
+
+	// Without a users table the join scenario is skipped.
+	require.NoError(t, runner.LoadUsers(context.Background(), mock))
+	assert.NotContains(t, runner.RunQueries(context.Background(), mock), "join_users_country_7d")
+	assert.False(t, mock.joinCalled)
+
+	runner.UserCount = 25
+	require.NoError(t, runner.LoadUsers(context.Background(), mock))
+	assert.Equal(t, 25, mock.users)
+
+	results := runner.RunQueries(context.Background(), mock)
+	require.Contains(t, results, "join_users_country_7d")
+	assert.Equal(t, 2, results["join_users_country_7d"].Iterations)
+}
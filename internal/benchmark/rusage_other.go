@@ -0,0 +1,11 @@
+//go:build !unix
+
+package benchmark
+
+import "time"
+
+// processCPUTime is not measured on this platform.
+func processCPUTime() time.Duration { return 0 }
+
+// peakRSS is not measured on this platform.
+func peakRSS() int64 { return 0 }
@@ -0,0 +1,67 @@
+package benchmark
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// sampleSize is the number of inserted events kept per repository to drive
+// lookup scenarios.
+const sampleSize = 1000
+
+// eventSample is a uniform reservoir sample of the events inserted into one
+// repository. Payloads are dropped to keep it small.
+type eventSample struct {
+	mu     sync.Mutex
+	seen   int64
+	events []generator.Event
+}
+
+func (s *eventSample) add(batch []generator.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range batch {
+		e.Payload = ""
+		s.seen++
+
+		if len(s.events) < sampleSize {
+			s.events = append(s.events, e)
+		} else if i := rand.Int64N(s.seen); i < sampleSize {
+			s.events[i] = e
+		}
+	}
+}
+
+// pick returns a random sampled event, or false if nothing was inserted.
+func (s *eventSample) pick() (generator.Event, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.events) == 0 {
+		return generator.Event{}, false
+	}
+
+	return s.events[rand.IntN(len(s.events))], true
+}
+
+// sampleFor returns the event sample of a repository, creating it on first
+// use. A runner is shared by databases benchmarked in parallel.
+func (r *Runner) sampleFor(repo Repository) *eventSample {
+	r.samplesMu.Lock()
+	defer r.samplesMu.Unlock()
+
+	if r.samples == nil {
+		r.samples = make(map[Repository]*eventSample)
+	}
+
+	s, ok := r.samples[repo]
+	if !ok {
+		s = &eventSample{}
+		r.samples[repo] = s
+	}
+
+	return s
+}
@@ -0,0 +1,32 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+)
+
+// RunWorkerSweep reruns the insert benchmark once per worker count. The
+// schema is recreated before every run, so each count starts from an empty
+// table instead of inserting on top of the previous runs.
+func (r *Runner) RunWorkerSweep(ctx context.Context, repo Repository, workers []int) []*ScalingResult {
+	results := make([]*ScalingResult, 0, len(workers))
+
+	for _, n := range workers {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sr := &ScalingResult{Workers: n}
+		results = append(results, sr)
+
+		if err := repo.InitSchema(ctx); err != nil {
+			sr.ErrorText = err.Error()
+			continue
+		}
+
+		sr.Insert = r.runInsert(ctx, repo, n)
+		log.Printf("Insert benchmark done with %d workers: %.0f/sec", n, sr.Insert.Throughput)
+	}
+
+	return results
+}
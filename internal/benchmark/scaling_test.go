@@ -0,0 +1,49 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaCountingRepository counts InitSchema calls and fails the ones listed
+// in fail.
+type schemaCountingRepository struct {
+	mockRepository
+	calls atomic.Int64
+	fail  map[int64]bool
+}
+
+func (m *schemaCountingRepository) InitSchema(context.Context) error {
+	if m.fail[m.calls.Add(1)] {
+		return errors.New("schema failed")
+	}
+
+	return nil
+}
+
+func TestRunWorkerSweep(t *testing.T) {
+	mock := &schemaCountingRepository{fail: map[int64]bool{2: true}}
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 8}
+
+	results := runner.RunWorkerSweep(context.Background(), mock, []int{1, 2, 4})
+
+	require.Len(t, results, 3)
+	assert.Equal(t, int64(3), mock.calls.Load())
+
+	assert.Equal(t, 1, results[0].Workers)
+	require.NotNil(t, results[0].Insert)
+	assert.Equal(t, 1, results[0].Insert.WorkerCount)
+	assert.Equal(t, 100, results[0].Insert.TotalEvents)
+
+	assert.Nil(t, results[1].Insert)
+	assert.Equal(t, "schema failed", results[1].ErrorText)
+
+	require.NotNil(t, results[2].Insert)
+	assert.Equal(t, 4, results[2].Insert.WorkerCount)
+	assert.Equal(t, 8, runner.Workers)
+}
@@ -0,0 +1,64 @@
+package benchmark
+
+import (
+	"context"
+	"time"
+)
+
+// ScenarioFilter selects the query scenarios a runner benchmarks, built-in
+// and custom alike: only those in Only unless it is empty, and none of
+// those in Skip. The zero value selects every scenario.
+type ScenarioFilter struct {
+	Only map[string]bool
+	Skip map[string]bool
+}
+
+func (f ScenarioFilter) selects(name string) bool {
+	return (len(f.Only) == 0 || f.Only[name]) && !f.Skip[name]
+}
+
+// queryScenario is a built-in query scenario of RunQueries. run returns nil
+// when the repository cannot run it.
+type queryScenario struct {
+	name string
+	run  func(r *Runner, ctx context.Context, repo Repository, now time.Time) *QueryResult
+}
+
+// queryScenarios are the built-in scenarios in the order they run.
+var queryScenarios = []queryScenario{
+	timeRangeScenario("1_hour", time.Hour),
+	timeRangeScenario("1_day", 24*time.Hour),
+	timeRangeScenario("1_week", 7*24*time.Hour),
+	timeRangeScenario("1_month", 30*24*time.Hour),
+	{"point_lookup", (*Runner).runPointLookup},
+	{"exists_by_id", (*Runner).runExists},
+	{"user_history", (*Runner).runUserHistory},
+	{"top_users_7d", (*Runner).runTopUsers},
+	{"group_by_user_1_month", (*Runner).runUserGroups},
+	{"payload_search_1_day", (*Runner).runPayloadSearch},
+	{"pagination_1_day", (*Runner).runPagination},
+	{"export_1_day", (*Runner).runExport},
+	{"count_1_day", (*Runner).runCount},
+	{"join_users_country_7d", (*Runner).runJoin},
+	{"sessions_1_day", (*Runner).runSessions},
+	{"tenant_stats_1_day", (*Runner).runTenantStats},
+	{"tenant_recent_events", (*Runner).runTenantEvents},
+}
+
+// timeRangeScenario is the event stats query over the window before now.
+func timeRangeScenario(name string, window time.Duration) queryScenario {
+	return queryScenario{name, func(r *Runner, ctx context.Context, repo Repository, now time.Time) *QueryResult {
+		return r.runQuery(ctx, repo, name, now.Add(-window), now)
+	}}
+}
+
+// ScenarioNames returns the names of the built-in query scenarios in the
+// order they run.
+func ScenarioNames() []string {
+	names := make([]string, len(queryScenarios))
+	for i, s := range queryScenarios {
+		names[i] = s.name
+	}
+
+	return names
+}
@@ -0,0 +1,60 @@
+package benchmark
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScenarioFilter(t *testing.T) {
+	assert.True(t, ScenarioFilter{}.selects("1_hour"))
+
+	only := ScenarioFilter{Only: map[string]bool{"1_hour": true, "point_lookup": true}}
+	assert.True(t, only.selects("point_lookup"))
+	assert.False(t, only.selects("1_month"))
+
+	skip := ScenarioFilter{Only: only.Only, Skip: map[string]bool{"point_lookup": true}}
+	assert.True(t, skip.selects("1_hour"))
+	assert.False(t, skip.selects("point_lookup"))
+}
+
+func TestRunQueriesSelectedScenarios(t *testing.T) {
+	mock := &mockRepository{}
+
+	runner := &Runner{
+		QueryIterations: 3,
+		Scenarios:       ScenarioFilter{Only: map[string]bool{"1_hour": true, "1_month": true, "errors_6h": true}},
+		CustomQueries: []CustomQuery{
+			{Name: "errors_6h", Window: 6 * time.Hour, Method: "stats"},
+			{Name: "stats_1d", Window: 24 * time.Hour, Method: "stats"},
+		},
+	}
+
+	results := runner.RunQueries(context.Background(), mock)
+	assert.Len(t, results, 2)
+	assert.Contains(t, results, "1_hour")
+	assert.Contains(t, results, "1_month")
+	assert.NotContains(t, results, "sessions_1_day", "unselected scenarios do not run at all")
+	assert.Equal(t, int64(6), atomic.LoadInt64(&mock.callCount))
+
+	custom := runner.RunCustomQueries(context.Background(), mock, "postgres")
+	assert.Len(t, custom, 1)
+	assert.Contains(t, custom, "errors_6h")
+
+	runner.Scenarios = ScenarioFilter{Skip: map[string]bool{"1_hour": true}}
+	results = runner.RunQueries(context.Background(), mock)
+	assert.NotContains(t, results, "1_hour")
+	assert.Contains(t, results, "1_day")
+}
+
+func TestScenarioNames(t *testing.T) {
+	names := ScenarioNames()
+
+	assert.Len(t, names, len(queryScenarios))
+	assert.Equal(t, []string{"1_hour", "1_day", "1_week", "1_month"}, names[:4])
+	assert.Contains(t, names, "sessions_1_day")
+	assert.Contains(t, names, "point_lookup")
+}
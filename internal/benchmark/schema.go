@@ -0,0 +1,283 @@
+package benchmark
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the version of the JSON results document. Fields are
+// only ever added within a version; removing or renaming a field, or
+// changing its type or unit, bumps it.
+const SchemaVersion = 1
+
+// humanSuffix marks the human-readable twin of a duration field, e.g.
+// "p95_duration_human": "75ms" next to "p95_duration": 75000000.
+const humanSuffix = "_human"
+
+// resultsDocument is the JSON results document: the results of each
+// database, or each cell of a parameter matrix, under its name.
+type resultsDocument struct {
+	SchemaVersion int                 `json:"schema_version"`
+	Results       map[string]*Results `json:"results"`
+}
+
+// MarshalResults encodes results as an indented JSON results document. Each
+// duration, in nanoseconds, is followed by its human-readable form.
+func MarshalResults(results map[string]*Results) ([]byte, error) {
+	raw, err := json.Marshal(resultsDocument{SchemaVersion: SchemaVersion, Results: results})
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	doc, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(withHumanDurations(doc, reflect.TypeFor[resultsDocument]()), "", "  ")
+}
+
+// DecodeResults decodes a JSON results document. The bare map of results
+// written before the document was versioned is accepted too; a document of
+// a newer schema version is not.
+func DecodeResults(data []byte) (map[string]*Results, error) {
+	var version struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+
+	if err := json.Unmarshal(data, &version); err != nil {
+		return nil, err
+	}
+
+	if version.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("results schema version %d is newer than the supported %d", version.SchemaVersion, SchemaVersion)
+	}
+
+	if version.SchemaVersion == 0 {
+		var results map[string]*Results
+		err := json.Unmarshal(data, &results)
+
+		return results, err
+	}
+
+	var doc resultsDocument
+	err := json.Unmarshal(data, &doc)
+
+	return doc.Results, err
+}
+
+// orderedObject is a JSON object that keeps the order of its members, so the
+// document reads in the order of the struct fields it was encoded from.
+type orderedObject []member
+
+type member struct {
+	key   string
+	value any
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(m.key)
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// decodeOrdered decodes the next JSON value, objects as orderedObject and
+// numbers as json.Number.
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		return decodeOrderedObject(dec)
+	case json.Delim('['):
+		return decodeOrderedArray(dec)
+	case json.Delim('}'), json.Delim(']'):
+		return nil, errors.New("unexpected end of JSON value")
+	default:
+		return tok, nil
+	}
+}
+
+// decodeOrderedObject decodes the members of an object whose opening brace
+// has been read.
+func decodeOrderedObject(dec *json.Decoder) (orderedObject, error) {
+	obj := orderedObject{}
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := decodeOrdered(dec)
+		if err != nil {
+			return nil, err
+		}
+
+		obj = append(obj, member{key: key.(string), value: value})
+	}
+
+	_, err := dec.Token()
+
+	return obj, err
+}
+
+// decodeOrderedArray decodes the elements of an array whose opening bracket
+// has been read.
+func decodeOrderedArray(dec *json.Decoder) ([]any, error) {
+	arr := []any{}
+
+	for dec.More() {
+		value, err := decodeOrdered(dec)
+		if err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, value)
+	}
+
+	_, err := dec.Token()
+
+	return arr, err
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// withHumanDurations adds the human-readable form after each duration field
+// of v, a decoded value of type t.
+func withHumanDurations(v any, t reflect.Type) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch v := v.(type) {
+	case orderedObject:
+		return objectWithHumanDurations(v, t)
+	case []any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return v
+		}
+
+		for i := range v {
+			v[i] = withHumanDurations(v[i], t.Elem())
+		}
+
+		return v
+	default:
+		return v
+	}
+}
+
+// objectWithHumanDurations adds the human-readable form after each duration
+// field of obj, a decoded object of type t.
+func objectWithHumanDurations(obj orderedObject, t reflect.Type) orderedObject {
+	out := make(orderedObject, 0, len(obj))
+
+	for _, m := range obj {
+		ft, ok := memberType(t, m.key)
+		if !ok {
+			out = append(out, m)
+			continue
+		}
+
+		out = append(out, member{key: m.key, value: withHumanDurations(m.value, ft)})
+
+		// Only fields get a twin; a map of durations keeps its keys.
+		if n, isNumber := m.value.(json.Number); isNumber && ft == durationType && t.Kind() == reflect.Struct {
+			if ns, err := n.Int64(); err == nil {
+				out = append(out, member{key: m.key + humanSuffix, value: time.Duration(ns).String()})
+			}
+		}
+	}
+
+	return out
+}
+
+// memberType returns the type of the member key of an object encoded from
+// t: a struct field by its JSON name, or the element of a map.
+func memberType(t reflect.Type, key string) (reflect.Type, bool) {
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem(), true
+	case reflect.Struct:
+		return fieldType(t, key)
+	default:
+		return nil, false
+	}
+}
+
+func fieldType(t reflect.Type, key string) (reflect.Type, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	for i := range t.NumField() {
+		if ft, ok := matchField(t.Field(i), key); ok {
+			return ft, true
+		}
+	}
+
+	return nil, false
+}
+
+// matchField returns the type of the field f encodes as key, f itself or a
+// field of an embedded struct.
+func matchField(f reflect.StructField, key string) (reflect.Type, bool) {
+	if !f.IsExported() && !f.Anonymous {
+		return nil, false
+	}
+
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+
+	switch {
+	case name == "-":
+		return nil, false
+	case name == "" && f.Anonymous:
+		if ft, ok := fieldType(f.Type, key); ok {
+			return ft, true
+		}
+	case name == "":
+		name = f.Name
+	}
+
+	if name != key {
+		return nil, false
+	}
+
+	return f.Type, true
+}
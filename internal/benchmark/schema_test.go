@@ -0,0 +1,53 @@
+package benchmark
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalResults(t *testing.T) {
+	results := map[string]*Results{
+		"postgres": {
+			Database: "postgres",
+			Insert:   &InsertResult{TotalEvents: 1000, Duration: 5 * time.Second, Workers: []WorkerStats{{Worker: 1, LatencyP50: 4 * time.Millisecond}}},
+			Queries:  map[string]*QueryResult{"1_hour": {QueryName: "1_hour", Iterations: 10, P95Duration: 75 * time.Millisecond}},
+			Storage:  &repository.StorageStats{TotalSize: 1 << 62, Details: map[string]int64{"wal_bytes": 2048}},
+		},
+	}
+
+	data, err := MarshalResults(results)
+	require.NoError(t, err)
+
+	out := string(data)
+	assert.True(t, strings.HasPrefix(out, "{\n  \"schema_version\": 1,\n  \"results\": {"), out)
+	assert.Contains(t, out, "\"duration\": 5000000000,\n        \"duration_human\": \"5s\",", "the human form follows the nanoseconds")
+	assert.Contains(t, out, `"p95_duration_human": "75ms"`)
+	assert.Contains(t, out, `"latency_p50_human": "4ms"`, "durations in slices of structs")
+	assert.Contains(t, out, `"total_size": 4611686018427387904`, "large integers keep their precision")
+	assert.NotContains(t, out, "wal_bytes_human")
+	assert.Less(t, strings.Index(out, `"database"`), strings.Index(out, `"insert"`), "fields keep their order")
+
+	decoded, err := DecodeResults(data)
+	require.NoError(t, err)
+	require.Contains(t, decoded, "postgres")
+	assert.Equal(t, 5*time.Second, decoded["postgres"].Insert.Duration)
+	assert.Equal(t, 75*time.Millisecond, decoded["postgres"].Queries["1_hour"].P95Duration)
+}
+
+func TestDecodeResults(t *testing.T) {
+	legacy, err := DecodeResults([]byte(`{"postgres": {"database": "postgres", "insert": {"duration": 1000}}}`))
+	require.NoError(t, err)
+	require.Contains(t, legacy, "postgres", "the unversioned map of earlier runs")
+	assert.Equal(t, time.Microsecond, legacy["postgres"].Insert.Duration)
+
+	_, err = DecodeResults([]byte(`{"schema_version": 99, "results": {}}`))
+	assert.ErrorContains(t, err, "newer")
+
+	_, err = DecodeResults([]byte(`not json`))
+	assert.Error(t, err)
+}
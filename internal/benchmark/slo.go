@@ -0,0 +1,250 @@
+package benchmark
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SLO is a limit on one metric of a benchmark phase, written as
+// "<database> <phase> <metric> <op> <value>", e.g. "postgres 1_day p95 <
+// 200ms" or "clickhouse insert throughput > 300k/s". The database * applies
+// the limit to every database of the run. The phase is insert or the name of
+// a query scenario.
+type SLO struct {
+	Text     string
+	Database string
+	Phase    string
+	Metric   string
+	Op       string
+	Limit    float64 // nanoseconds for latency metrics
+}
+
+// SLOViolation is an SLO a database did not meet. Actual is empty when the
+// database has no value for the metric, e.g. because it did not run the
+// phase or could not run the scenario.
+type SLOViolation struct {
+	SLO      string `json:"slo"`
+	Database string `json:"database"`
+	Actual   string `json:"actual,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// sloLatencyMetrics are the metrics compared as durations.
+var sloLatencyMetrics = map[string]bool{"p50": true, "p95": true, "p99": true, "avg": true, "min": true, "max": true}
+
+// sloMetrics are the metrics an SLO can limit, by phase.
+var sloMetrics = map[string][]string{
+	"insert": {"throughput", "p50", "p95", "p99", "errors"},
+	"query":  {"p50", "p95", "p99", "avg", "min", "max", "qps", "errors"},
+}
+
+// ParseSLO parses an SLO of the form "<database> <phase> <metric> <op>
+// <value>". Latency limits are Go durations; other limits are numbers that
+// may end in k, M or G and a "/s" unit.
+func ParseSLO(text string) (SLO, error) {
+	fields := strings.Fields(text)
+	if len(fields) != 5 {
+		return SLO{}, fmt.Errorf("invalid slo %q: want \"<database> <phase> <metric> <op> <value>\"", text)
+	}
+
+	s := SLO{Text: text, Database: fields[0], Phase: fields[1], Metric: strings.ToLower(fields[2]), Op: fields[3]}
+
+	if !slices.Contains(sloMetrics[s.kind()], s.Metric) {
+		return SLO{}, fmt.Errorf("invalid slo %q: %s metric must be one of %s", text, s.kind(), strings.Join(sloMetrics[s.kind()], ", "))
+	}
+
+	if !slices.Contains([]string{"<", "<=", ">", ">="}, s.Op) {
+		return SLO{}, fmt.Errorf("invalid slo %q: operator must be <, <=, > or >=", text)
+	}
+
+	limit, err := parseSLOLimit(fields[4], sloLatencyMetrics[s.Metric])
+	if err != nil {
+		return SLO{}, fmt.Errorf("invalid slo %q: %w", text, err)
+	}
+
+	s.Limit = limit
+
+	return s, nil
+}
+
+// kind returns the metric set of the phase of s.
+func (s SLO) kind() string {
+	if s.Phase == "insert" {
+		return "insert"
+	}
+
+	return "query"
+}
+
+func parseSLOLimit(value string, latency bool) (float64, error) {
+	if latency {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse latency limit: %w", err)
+		}
+
+		return float64(d), nil
+	}
+
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "/sec"), "/s")
+	scale := 1.0
+
+	if i := len(value) - 1; i > 0 {
+		if m, ok := map[byte]float64{'k': 1e3, 'K': 1e3, 'M': 1e6, 'G': 1e9}[value[i]]; ok {
+			value, scale = value[:i], m
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse limit: %w", err)
+	}
+
+	return n * scale, nil
+}
+
+// CheckSLOs evaluates every SLO against the results of each database it
+// applies to and returns the violations, in the order of the SLOs and then
+// of the database names. An SLO naming a database that was not run is a
+// violation too, so a gate cannot pass by accident.
+func CheckSLOs(slos []SLO, results map[string]*Results) []SLOViolation {
+	var violations []SLOViolation
+
+	for _, s := range slos {
+		databases := []string{s.Database}
+		if s.Database == "*" {
+			databases = slices.Sorted(maps.Keys(results))
+		}
+
+		for _, db := range databases {
+			if v, ok := s.check(db, results[db]); !ok {
+				violations = append(violations, v)
+			}
+		}
+	}
+
+	return violations
+}
+
+// check evaluates s against the results of one database.
+func (s SLO) check(db string, res *Results) (SLOViolation, bool) {
+	v := SLOViolation{SLO: s.Text, Database: db}
+
+	value, reason := s.value(res)
+	if reason != "" {
+		v.Reason = reason
+		return v, false
+	}
+
+	v.Actual = s.format(value)
+
+	return v, s.holds(value)
+}
+
+// holds reports whether value is within the limit of s.
+func (s SLO) holds(value float64) bool {
+	switch s.Op {
+	case "<":
+		return value < s.Limit
+	case "<=":
+		return value <= s.Limit
+	case ">":
+		return value > s.Limit
+	default:
+		return value >= s.Limit
+	}
+}
+
+// value returns the metric of s in res, or why there is none.
+func (s SLO) value(res *Results) (float64, string) {
+	switch {
+	case res == nil:
+		return 0, "database not benchmarked"
+	case res.ErrorText != "" || res.Error != nil:
+		return 0, "benchmark failed"
+	case s.Phase == "insert":
+		if res.Insert == nil {
+			return 0, "no insert result"
+		}
+
+		return insertMetric(res.Insert, s.Metric), ""
+	}
+
+	return s.queryValue(res.Queries[s.Phase])
+}
+
+// queryValue returns the metric of s in the result of its query scenario, or
+// why there is none.
+func (s SLO) queryValue(qr *QueryResult) (float64, string) {
+	switch {
+	case qr == nil:
+		return 0, "no " + s.Phase + " result"
+	case qr.ErrorText != "":
+		return 0, qr.ErrorText
+	case qr.Iterations == 0:
+		return 0, "every query failed"
+	}
+
+	return queryMetric(qr, s.Metric), ""
+}
+
+func insertMetric(ir *InsertResult, metric string) float64 {
+	switch metric {
+	case "throughput":
+		return ir.Throughput
+	case "p50":
+		return float64(ir.LatencyP50)
+	case "p95":
+		return float64(ir.LatencyP95)
+	case "p99":
+		return float64(ir.LatencyP99)
+	default:
+		return float64(ir.ErrorCount)
+	}
+}
+
+func queryMetric(qr *QueryResult, metric string) float64 {
+	switch metric {
+	case "qps":
+		return qr.QPS
+	case "errors":
+		return float64(qr.ErrorCount)
+	default:
+		return float64(queryLatency(qr, metric))
+	}
+}
+
+func queryLatency(qr *QueryResult, metric string) time.Duration {
+	switch metric {
+	case "p50":
+		return qr.P50Duration
+	case "p95":
+		return qr.P95Duration
+	case "p99":
+		return qr.P99Duration
+	case "avg":
+		return qr.AvgDuration
+	case "min":
+		return qr.MinDuration
+	default:
+		return qr.MaxDuration
+	}
+}
+
+// format renders a value of the metric of s in its unit.
+func (s SLO) format(value float64) string {
+	switch {
+	case sloLatencyMetrics[s.Metric]:
+		return time.Duration(value).Round(time.Microsecond).String()
+	case s.Metric == "throughput":
+		return fmt.Sprintf("%.0f/sec", value)
+	case s.Metric == "qps":
+		return fmt.Sprintf("%.1f", value)
+	default:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+}
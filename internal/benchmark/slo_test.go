@@ -0,0 +1,77 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSLO(t *testing.T) {
+	s, err := ParseSLO("postgres 1_day p95 < 200ms")
+	require.NoError(t, err)
+	assert.Equal(t, SLO{
+		Text: "postgres 1_day p95 < 200ms", Database: "postgres", Phase: "1_day", Metric: "p95", Op: "<", Limit: float64(200 * time.Millisecond),
+	}, s)
+
+	s, err = ParseSLO("clickhouse insert throughput > 300k/s")
+	require.NoError(t, err)
+	assert.InDelta(t, 300_000, s.Limit, 0)
+
+	s, err = ParseSLO("* 1_hour qps >= 1.5M")
+	require.NoError(t, err)
+	assert.InDelta(t, 1_500_000, s.Limit, 0)
+
+	for _, text := range []string{
+		"postgres 1_day p95 <",
+		"postgres 1_day p95 == 200ms",
+		"postgres insert qps > 10",
+		"postgres 1_day throughput > 10",
+		"postgres 1_day p95 < 200",
+		"postgres insert throughput > fast",
+	} {
+		_, err := ParseSLO(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestCheckSLOs(t *testing.T) {
+	results := map[string]*Results{
+		"postgres": {
+			Insert:  &InsertResult{Throughput: 250_000, LatencyP95: 40 * time.Millisecond},
+			Queries: map[string]*QueryResult{"1_day": {Iterations: 10, P95Duration: 150 * time.Millisecond}},
+		},
+		"clickhouse": {
+			Insert:  &InsertResult{Throughput: 400_000},
+			Queries: map[string]*QueryResult{"1_day": {Iterations: 10, P95Duration: 300 * time.Millisecond}},
+		},
+	}
+
+	var slos []SLO
+
+	for _, text := range []string{"postgres 1_day p95 < 200ms", "* insert throughput > 300k/s", "mongodb 1_day p95 < 1s"} {
+		s, err := ParseSLO(text)
+		require.NoError(t, err)
+
+		slos = append(slos, s)
+	}
+
+	violations := CheckSLOs(slos, results)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, SLOViolation{SLO: "* insert throughput > 300k/s", Database: "postgres", Actual: "250000/sec"}, violations[0])
+	assert.Equal(t, SLOViolation{SLO: "mongodb 1_day p95 < 1s", Database: "mongodb", Reason: "database not benchmarked"}, violations[1])
+}
+
+func TestCheckSLOsMissingScenario(t *testing.T) {
+	s, err := ParseSLO("postgres point_lookup p99 < 5ms")
+	require.NoError(t, err)
+
+	results := map[string]*Results{"postgres": {Queries: map[string]*QueryResult{"point_lookup": {ErrorText: "not supported"}}}}
+
+	violations := CheckSLOs([]SLO{s}, results)
+
+	require.Len(t, violations, 1)
+	assert.Equal(t, "not supported", violations[0].Reason)
+}
@@ -0,0 +1,68 @@
+package benchmark
+
+import (
+	"math"
+	"time"
+)
+
+// steadyWindow is the number of consecutive seconds of the insert timeline
+// whose rates must all lie within the tolerance of their mean for the run to
+// count as steady from the first of them.
+const steadyWindow = 5
+
+// steadyState finds the start of the steady state in the per-second
+// timeline of an insert run: the first second that opens a window of
+// steadyWindow seconds within SteadyState percent of the window mean. The
+// steady throughput covers the full seconds from there to the end of the
+// run; the last second is partial and left out. It returns nil when steady
+// state detection is off.
+func (r *Runner) steadyState(tl []InsertSample) *SteadyStateResult {
+	if r.SteadyState <= 0 {
+		return nil
+	}
+
+	res := &SteadyStateResult{Tolerance: r.SteadyState}
+	full := len(tl) - 1
+
+	for i := 0; i+steadyWindow <= full; i++ {
+		if !stable(tl[i:i+steadyWindow], r.SteadyState) {
+			continue
+		}
+
+		res.Reached = true
+		res.After = time.Duration(i) * time.Second
+		res.Seconds = full - i
+		res.Throughput = float64(sumEvents(tl[i:full])) / float64(res.Seconds)
+
+		break
+	}
+
+	return res
+}
+
+// stable reports whether every second of window is within tolerance
+// percent of the window mean. A window without inserts is not stable.
+func stable(window []InsertSample, tolerance float64) bool {
+	mean := float64(sumEvents(window)) / float64(len(window))
+	if mean == 0 {
+		return false
+	}
+
+	for _, s := range window {
+		if math.Abs(float64(s.Events)-mean) > mean*tolerance/100 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sumEvents returns the number of events inserted in the samples.
+func sumEvents(samples []InsertSample) int64 {
+	var events int64
+	for _, s := range samples {
+		events += s.Events
+	}
+
+	return events
+}
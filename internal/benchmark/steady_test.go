@@ -0,0 +1,48 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func timelineOf(events ...int64) []InsertSample {
+	tl := make([]InsertSample, len(events))
+	for i, n := range events {
+		tl[i] = InsertSample{Second: i, Events: n}
+	}
+
+	return tl
+}
+
+func TestSteadyState(t *testing.T) {
+	runner := &Runner{SteadyState: 10}
+
+	// Two seconds of warm-up, then a steady ~1000/s, then a partial second.
+	res := runner.steadyState(timelineOf(200, 600, 1000, 980, 1020, 990, 1010, 1000, 300))
+
+	require.NotNil(t, res)
+	assert.True(t, res.Reached)
+	assert.Equal(t, 2*time.Second, res.After)
+	assert.Equal(t, 6, res.Seconds)
+	assert.InDelta(t, 1000, res.Throughput, 0.001)
+}
+
+func TestSteadyStateNotReached(t *testing.T) {
+	runner := &Runner{SteadyState: 5}
+
+	res := runner.steadyState(timelineOf(100, 200, 400, 800, 1600, 3200, 6400))
+
+	require.NotNil(t, res)
+	assert.False(t, res.Reached)
+	assert.Zero(t, res.Throughput)
+
+	short := runner.steadyState(timelineOf(1000, 1000, 1000))
+	assert.False(t, short.Reached, "shorter than the window")
+}
+
+func TestSteadyStateOff(t *testing.T) {
+	assert.Nil(t, (&Runner{}).steadyState(timelineOf(1000, 1000, 1000, 1000, 1000, 1000, 1000)))
+}
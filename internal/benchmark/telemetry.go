@@ -0,0 +1,87 @@
+package benchmark
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// scope is the OpenTelemetry instrumentation scope of the runner.
+const scope = "github.com/skoredin/db-benchmark-suite/internal/benchmark"
+
+// The tracer and instruments come from the global providers, which do
+// nothing until the binary installs an SDK.
+var (
+	tracer = otel.Tracer(scope)
+	meter  = otel.Meter(scope)
+
+	batchDuration, _ = meter.Float64Histogram("dbbench.batch.duration",
+		metric.WithUnit("s"), metric.WithDescription("Latency of the inserted batches, retries included."))
+	queryDuration, _ = meter.Float64Histogram("dbbench.query.duration",
+		metric.WithUnit("s"), metric.WithDescription("Latency of the successful query runs, retries included."))
+	batchErrors, _ = meter.Int64Counter("dbbench.batch.errors",
+		metric.WithDescription("Insert batches that failed every attempt."))
+	queryErrors, _ = meter.Int64Counter("dbbench.query.errors",
+		metric.WithDescription("Query runs that failed every attempt."))
+)
+
+// telemetry traces the phases, batches and queries of one database and
+// records their latency. The latency is recorded in the context of the span
+// of the operation, so the histograms carry exemplars of the traced ones.
+type telemetry struct {
+	database attribute.KeyValue
+}
+
+type telemetryKey struct{}
+
+// WithTelemetry returns a context whose phases, insert batches and query runs
+// are traced and measured with OpenTelemetry under the database name. The
+// span of a batch or query is in the context the repository is called with.
+func WithTelemetry(ctx context.Context, database string) context.Context {
+	return context.WithValue(ctx, telemetryKey{}, &telemetry{database: attribute.String("db.system.name", database)})
+}
+
+// telemetryFrom returns the telemetry of ctx, or nil when it has none.
+func telemetryFrom(ctx context.Context) *telemetry {
+	t, _ := ctx.Value(telemetryKey{}).(*telemetry)
+	return t
+}
+
+// start starts the span of an operation; without telemetry the span does
+// nothing and ctx is returned as is.
+func (t *telemetry) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if t == nil {
+		return ctx, noop.Span{}
+	}
+
+	return tracer.Start(ctx, name, trace.WithAttributes(append(attrs, t.database)...))
+}
+
+// end ends the span of an insert batch or query run that took latency,
+// recording the latency in hist or the failure in errs.
+func (t *telemetry) end(
+	ctx context.Context, span trace.Span, hist metric.Float64Histogram, errs metric.Int64Counter, latency time.Duration, err error,
+	attrs ...attribute.KeyValue,
+) {
+	if t == nil {
+		return
+	}
+
+	opt := metric.WithAttributes(append(attrs, t.database)...)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		errs.Add(ctx, 1, opt)
+	} else {
+		hist.Record(ctx, latency.Seconds(), opt)
+	}
+
+	span.End()
+}
@@ -0,0 +1,110 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTelemetry(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	var batches atomic.Int64
+
+	mock := &mockRepository{insertBatchFunc: func(ctx context.Context, _ []generator.Event) error {
+		assert.True(t, trace.SpanFromContext(ctx).IsRecording(), "the repository is called in the span of the batch")
+
+		if batches.Add(1) == 3 {
+			return errors.New("insert failed")
+		}
+
+		return nil
+	}}
+
+	ctx := WithTelemetry(context.Background(), "postgres")
+	runner := &Runner{
+		EventCount: 50, BatchSize: 10, Workers: 1, QueryIterations: 3,
+		Scenarios: ScenarioFilter{Only: map[string]bool{"1_hour": true}},
+	}
+
+	runner.RunInsert(ctx, mock)
+	runner.RunQueries(ctx, mock)
+
+	names := map[string]int{}
+	parents := map[trace.SpanID]string{}
+
+	for _, s := range spans.Ended() {
+		names[s.Name()]++
+		parents[s.SpanContext().SpanID()] = s.Name()
+
+		assert.Contains(t, s.Attributes(), attribute.String("db.system.name", "postgres"))
+	}
+
+	assert.Equal(t, map[string]int{"insert": 1, "insert batch": 5, "scenario 1_hour": 1, "query": 3}, names)
+
+	for _, s := range spans.Ended() {
+		switch s.Name() {
+		case "insert batch":
+			assert.Equal(t, "insert", parents[s.Parent().SpanID()])
+		case "query":
+			assert.Equal(t, "scenario 1_hour", parents[s.Parent().SpanID()])
+		}
+	}
+
+	var failed int
+
+	for _, s := range spans.Ended() {
+		if s.Status().Code == codes.Error {
+			failed++
+		}
+	}
+
+	assert.Equal(t, 1, failed)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	hist := histogram(t, rm, "dbbench.batch.duration")
+	assert.Equal(t, uint64(4), hist.Count)
+	assert.NotEmpty(t, hist.Exemplars, "latencies carry exemplars of their spans")
+
+	hist = histogram(t, rm, "dbbench.query.duration")
+	assert.Equal(t, uint64(3), hist.Count)
+}
+
+// histogram returns the only data point of the named histogram.
+func histogram(t *testing.T, rm metricdata.ResourceMetrics, name string) metricdata.HistogramDataPoint[float64] {
+	t.Helper()
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				points := m.Data.(metricdata.Histogram[float64]).DataPoints
+				require.Len(t, points, 1)
+
+				return points[0]
+			}
+		}
+	}
+
+	t.Fatalf("no %s histogram", name)
+
+	return metricdata.HistogramDataPoint[float64]{}
+}
@@ -0,0 +1,56 @@
+package benchmark
+
+import (
+	"context"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// tenantScenario runs a tenant-scoped scenario: each iteration queries the
+// tenant of an event sampled from the inserts, so busy tenants are queried
+// as often as they write. It returns nil without TenantCount, and reports
+// databases whose schema has no tenant_id as not supported, like
+// sessions_1_day, so the gap shows up in the report.
+func (r *Runner) tenantScenario(
+	ctx context.Context, repo Repository, name string, query func(context.Context, TenantRepository, int64) error,
+) *QueryResult {
+	if r.TenantCount <= 0 {
+		return nil
+	}
+
+	tr, ok := repo.(TenantRepository)
+	if !ok || !tr.Tenants() {
+		return &QueryResult{QueryName: name, ErrorText: "not supported"}
+	}
+
+	return r.runSampled(ctx, repo, name, func(ctx context.Context, event generator.Event) error {
+		return query(ctx, tr, event.TenantID)
+	})
+}
+
+// runTenantStats runs the event stats query over the day before now for
+// one tenant.
+func (r *Runner) runTenantStats(ctx context.Context, repo Repository, now time.Time) *QueryResult {
+	start := now.Add(-24 * time.Hour)
+
+	res := r.tenantScenario(ctx, repo, "tenant_stats_1_day", func(ctx context.Context, tr TenantRepository, tenant int64) error {
+		_, err := tr.GetTenantEventStats(ctx, tenant, start, now)
+		return err
+	})
+
+	if res != nil && res.Iterations > 0 {
+		res.DateRange = dateRange(start, now)
+	}
+
+	return res
+}
+
+// runTenantEvents reads the newest tenantEventsLimit events of one tenant,
+// the feed of a tenant dashboard.
+func (r *Runner) runTenantEvents(ctx context.Context, repo Repository, _ time.Time) *QueryResult {
+	return r.tenantScenario(ctx, repo, "tenant_recent_events", func(ctx context.Context, tr TenantRepository, tenant int64) error {
+		_, err := tr.GetTenantEvents(ctx, tenant, tenantEventsLimit)
+		return err
+	})
+}
@@ -0,0 +1,88 @@
+package benchmark
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tenantMockRepository adds the tenant queries to mockRepository and
+// records the tenants they were asked for.
+type tenantMockRepository struct {
+	mockRepository
+	tenants bool
+
+	mu      sync.Mutex
+	queried map[int64]int
+	limit   int
+}
+
+func (m *tenantMockRepository) Tenants() bool { return m.tenants }
+
+func (m *tenantMockRepository) GetTenantEventStats(_ context.Context, tenantID int64, _, _ time.Time) ([]repository.EventStats, error) {
+	m.query(tenantID)
+	return nil, nil
+}
+
+func (m *tenantMockRepository) GetTenantEvents(_ context.Context, tenantID int64, limit int) ([]generator.Event, error) {
+	m.query(tenantID)
+	m.limit = limit
+
+	return nil, nil
+}
+
+func (m *tenantMockRepository) query(tenantID int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.queried == nil {
+		m.queried = make(map[int64]int)
+	}
+
+	m.queried[tenantID]++
+}
+
+func TestRunQueriesTenants(t *testing.T) {
+	mock := &tenantMockRepository{tenants: true}
+	runner := &Runner{EventCount: 2000, BatchSize: 100, Workers: 2, QueryIterations: 50, TenantCount: 5}
+
+	runner.RunInsert(context.Background(), mock)
+	results := runner.RunQueries(context.Background(), mock)
+
+	for _, name := range []string{"tenant_stats_1_day", "tenant_recent_events"} {
+		qr, ok := results[name]
+		require.True(t, ok, name)
+		assert.Empty(t, qr.ErrorText, name)
+		assert.Equal(t, 50, qr.Iterations, name)
+	}
+
+	assert.NotEmpty(t, results["tenant_stats_1_day"].DateRange)
+	assert.Equal(t, tenantEventsLimit, mock.limit)
+	assert.Greater(t, len(mock.queried), 1, "the tenants come from the sampled events")
+
+	for tenant := range mock.queried {
+		assert.Less(t, tenant, int64(5))
+	}
+}
+
+func TestRunQueriesTenantsNotSupported(t *testing.T) {
+	runner := &Runner{EventCount: 100, BatchSize: 100, Workers: 1, QueryIterations: 2, TenantCount: 5}
+
+	for _, repo := range []Repository{&mockRepository{}, &tenantMockRepository{}} {
+		runner.RunInsert(context.Background(), repo)
+		results := runner.RunQueries(context.Background(), repo)
+
+		assert.Equal(t, "not supported", results["tenant_stats_1_day"].ErrorText)
+		assert.Equal(t, "not supported", results["tenant_recent_events"].ErrorText)
+	}
+
+	runner.TenantCount = 0
+	results := runner.RunQueries(context.Background(), &tenantMockRepository{tenants: true})
+	assert.NotContains(t, results, "tenant_stats_1_day", "the tenant scenarios run only with tenants")
+}
@@ -0,0 +1,95 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// RunTransactions writes TransactionCount events one per transaction, each
+// together with an increment of its user's counter row, and measures the
+// committed transactions per second.
+func (r *Runner) RunTransactions(ctx context.Context, repo Repository) *TransactionResult {
+	res := &TransactionResult{Transactions: r.TransactionCount, WorkerCount: r.Workers}
+
+	txRepo, ok := repo.(TransactionRepository)
+	if !ok {
+		res.ErrorText = "not supported"
+		return res
+	}
+
+	if err := txRepo.InitTransactionSchema(ctx); err != nil {
+		res.ErrorText = err.Error()
+		return res
+	}
+
+	probe := startClientProbe()
+	committed := r.runTransactionWorkers(ctx, txRepo, r.newGenerator(repo, r.TransactionCount, r.BatchSize), res)
+
+	res.Duration, res.Client = time.Since(probe.start), probe.stop()
+	res.Throughput = float64(committed) / res.Duration.Seconds()
+
+	return res
+}
+
+// runTransactionWorkers drains the batches of gen on the workers, adding
+// their errors to res, and returns the number of committed transactions.
+func (r *Runner) runTransactionWorkers(
+	ctx context.Context, repo TransactionRepository, gen *generator.Generator, res *TransactionResult,
+) int64 {
+	batches := gen.Generate()
+
+	var (
+		committed int64
+		wg        sync.WaitGroup
+	)
+
+	for i := 0; i < r.Workers; i++ {
+		wg.Add(1)
+
+		go func(workerID int) {
+			defer wg.Done()
+
+			n, errors := runTransactionWorker(ctx, repo, batches, workerID)
+			atomic.AddInt64(&committed, n)
+			atomic.AddInt64(&res.ErrorCount, errors)
+		}(i)
+	}
+
+	wg.Wait()
+
+	if replayFailed(gen) {
+		res.ErrorCount++
+	}
+
+	return committed
+}
+
+// runTransactionWorker runs one transaction per event until batches is
+// drained. Only the first error of a worker is logged, since a failing
+// database would otherwise log once per event.
+func runTransactionWorker(
+	ctx context.Context, repo TransactionRepository, batches <-chan []generator.Event, workerID int,
+) (committed, errors int64) {
+	for batch := range batches {
+		for _, event := range batch {
+			if err := repo.InsertEventTx(ctx, event); err != nil {
+				if errors == 0 {
+					log.Printf("Worker %d transaction error: %v", workerID, err)
+				}
+
+				errors++
+
+				continue
+			}
+
+			committed++
+		}
+	}
+
+	return committed, errors
+}
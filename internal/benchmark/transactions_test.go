@@ -0,0 +1,72 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// txMockRepository adds the transactional workload to mockRepository and
+// fails every transaction when fail is set.
+type txMockRepository struct {
+	mockRepository
+	mu     sync.Mutex
+	events int
+	schema bool
+	fail   bool
+}
+
+func (m *txMockRepository) InitTransactionSchema(context.Context) error {
+	m.schema = true
+	return nil
+}
+
+func (m *txMockRepository) InsertEventTx(_ context.Context, event generator.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.fail {
+		return errors.New("serialization failure")
+	}
+
+	m.events++
+
+	return nil
+}
+
+func TestRunTransactions(t *testing.T) {
+	mock := &txMockRepository{}
+	runner := &Runner{BatchSize: 10, Workers: 3, TransactionCount: 95}
+
+	res := runner.RunTransactions(context.Background(), mock)
+
+	require.Empty(t, res.ErrorText)
+	assert.True(t, mock.schema)
+	assert.Equal(t, 95, mock.events)
+	assert.Equal(t, 95, res.Transactions)
+	assert.Equal(t, 3, res.WorkerCount)
+	assert.Zero(t, res.ErrorCount)
+	assert.Positive(t, res.Throughput)
+}
+
+func TestRunTransactionsErrors(t *testing.T) {
+	mock := &txMockRepository{fail: true}
+	runner := &Runner{BatchSize: 10, Workers: 2, TransactionCount: 50}
+
+	res := runner.RunTransactions(context.Background(), mock)
+	assert.Equal(t, int64(50), res.ErrorCount)
+	assert.Zero(t, res.Throughput)
+}
+
+func TestRunTransactionsNotSupported(t *testing.T) {
+	runner := &Runner{BatchSize: 10, Workers: 1, TransactionCount: 10}
+
+	res := runner.RunTransactions(context.Background(), &mockRepository{})
+	assert.Equal(t, "not supported", res.ErrorText)
+	assert.Zero(t, res.Duration)
+}
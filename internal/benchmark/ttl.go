@@ -0,0 +1,59 @@
+package benchmark
+
+import (
+	"context"
+	"time"
+)
+
+// RunTTL enables expiry of events older than days, inserts another
+// EventCount events with it enabled, and then waits for the database to
+// expire the old events. The insert throughput is compared with baseline,
+// the main insert run, when there is one.
+func (r *Runner) RunTTL(ctx context.Context, repo Repository, days int, baseline *InsertResult) *TTLResult {
+	res := &TTLResult{TTL: time.Duration(days) * 24 * time.Hour}
+
+	expirer, ok := repo.(TTLRepository)
+	if !ok {
+		res.ErrorText = "not supported"
+		return res
+	}
+
+	method, err := expirer.EnableTTL(ctx, res.TTL)
+	if err != nil {
+		res.ErrorText = err.Error()
+		return res
+	}
+
+	res.Method = method
+	res.Insert = r.RunInsert(ctx, repo)
+
+	if baseline != nil && baseline.Throughput > 0 {
+		res.InsertOverhead = (baseline.Throughput - res.Insert.Throughput) / baseline.Throughput * 100
+	}
+
+	expire(ctx, expirer, repo, res)
+
+	return res
+}
+
+// expire runs the expiry of the events past the TTL and records the rows and
+// storage before and after it in res.
+func expire(ctx context.Context, expirer TTLRepository, repo Repository, res *TTLResult) {
+	res.RowsBefore, res.SizeBefore = storageRowsAndSize(ctx, repo)
+	start := time.Now()
+
+	if err := expirer.ExpireTTL(ctx); err != nil {
+		res.ErrorText = err.Error()
+	}
+
+	res.ExpireDuration = time.Since(start)
+	res.RowsAfter, res.SizeAfter = storageRowsAndSize(ctx, repo)
+}
+
+func storageRowsAndSize(ctx context.Context, repo Repository) (rows, size int64) {
+	if s := repo.GetStorageStats(ctx); s != nil {
+		return s.RowCount, s.TotalSize
+	}
+
+	return 0, 0
+}
@@ -0,0 +1,65 @@
+package benchmark
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ttlMockRepository adds TTL support to mockRepository; its storage shrinks
+// to a tenth once ExpireTTL has run.
+type ttlMockRepository struct {
+	mockRepository
+	ttl     time.Duration
+	expired bool
+}
+
+func (m *ttlMockRepository) EnableTTL(_ context.Context, ttl time.Duration) (string, error) {
+	m.ttl = ttl
+	return "ttl_clause", nil
+}
+
+func (m *ttlMockRepository) ExpireTTL(context.Context) error {
+	m.expired = true
+	return nil
+}
+
+func (m *ttlMockRepository) GetStorageStats(context.Context) *repository.StorageStats {
+	if m.expired {
+		return &repository.StorageStats{RowCount: 10, TotalSize: 1000}
+	}
+
+	return &repository.StorageStats{RowCount: 100, TotalSize: 10000}
+}
+
+func TestRunTTL(t *testing.T) {
+	mock := &ttlMockRepository{}
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 2}
+	baseline := &InsertResult{Throughput: 1e12}
+
+	result := runner.RunTTL(context.Background(), mock, 7, baseline)
+
+	require.NotNil(t, result)
+	assert.Empty(t, result.ErrorText)
+	assert.Equal(t, 7*24*time.Hour, mock.ttl)
+	assert.Equal(t, "ttl_clause", result.Method)
+	require.NotNil(t, result.Insert)
+	assert.Equal(t, 100, result.Insert.TotalEvents)
+	assert.Positive(t, result.InsertOverhead)
+	assert.Equal(t, int64(100), result.RowsBefore)
+	assert.Equal(t, int64(10), result.RowsAfter)
+	assert.Equal(t, int64(10000), result.SizeBefore)
+	assert.Equal(t, int64(1000), result.SizeAfter)
+}
+
+func TestRunTTLUnsupported(t *testing.T) {
+	result := (&Runner{}).RunTTL(context.Background(), &mockRepository{}, 7, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, "not supported", result.ErrorText)
+	assert.Nil(t, result.Insert)
+}
@@ -0,0 +1,128 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// Batch size tuning: each probe inserts tuneProbeShare of EventCount, and
+// at least tuneProbeBatches batches per worker, so small batch sizes are not
+// judged on a handful of requests. A step must beat the best probe by
+// tuneMinGain to be taken, so noise does not walk the climb around.
+const (
+	tuneProbeShare   = 10
+	tuneProbeBatches = 4
+	tuneMinGain      = 0.03
+)
+
+// tuneSteps are the factors the climb tries in turn: doubling and halving
+// first, then finer steps around the best size so far.
+var tuneSteps = []float64{2, 0.5, 1.25, 0.8}
+
+// TuneBatchSize hill-climbs the insert batch size of repo between lo and hi,
+// starting at BatchSize. For each factor of tuneSteps it keeps multiplying
+// the best size by that factor while the probe at the new size is faster,
+// and stops a direction at the bounds, at a size already probed, or at the
+// first probe that is no faster. Every probe starts from a fresh schema.
+func (r *Runner) TuneBatchSize(ctx context.Context, repo Repository, lo, hi int) *BatchTuningResult {
+	res := &BatchTuningResult{Min: lo, Max: hi}
+	probed := make(map[int]bool)
+
+	best := r.probeBatchSize(ctx, repo, min(max(r.BatchSize, lo), hi), res, probed)
+
+	for _, factor := range tuneSteps {
+		for ctx.Err() == nil && res.ErrorText == "" {
+			next := min(max(int(float64(best.BatchSize)*factor), lo), hi)
+			if probed[next] {
+				break
+			}
+
+			p := r.probeBatchSize(ctx, repo, next, res, probed)
+			if p.Throughput <= best.Throughput*(1+tuneMinGain) {
+				break
+			}
+
+			best = p
+		}
+	}
+
+	res.Optimal, res.Throughput = best.BatchSize, best.Throughput
+
+	return res
+}
+
+// probeBatchSize measures the insert throughput at one batch size on a fresh
+// schema and adds the probe to res.
+func (r *Runner) probeBatchSize(ctx context.Context, repo Repository, size int, res *BatchTuningResult, probed map[int]bool) BatchProbe {
+	probed[size] = true
+	p := BatchProbe{BatchSize: size}
+
+	if err := repo.InitSchema(ctx); err != nil {
+		res.ErrorText = err.Error()
+		return p
+	}
+
+	events := max(r.EventCount/tuneProbeShare, size*r.Workers*tuneProbeBatches)
+	start := time.Now()
+	inserted, failed := r.insertProbe(ctx, repo, events, size)
+
+	p.Events, p.ErrorCount = inserted, failed
+	p.Throughput = float64(inserted) / time.Since(start).Seconds()
+	res.Probes = append(res.Probes, p)
+
+	log.Printf("Batch size %d: %.0f/sec (%d errors)", size, p.Throughput, failed)
+
+	return p
+}
+
+// insertProbe inserts events in batches of size on Workers workers and
+// returns the events inserted and the batches that failed.
+func (r *Runner) insertProbe(ctx context.Context, repo Repository, events, size int) (inserted, failed int64) {
+	gen := r.newGenerator(repo, events, size)
+	batches := gen.GenerateContext(ctx)
+
+	var (
+		ok, errs atomic.Int64
+		wg       sync.WaitGroup
+	)
+
+	for range r.Workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			r.probeWorker(ctx, repo, batches, &ok, &errs)
+		}()
+	}
+
+	wg.Wait()
+
+	if replayFailed(gen) {
+		errs.Add(1)
+	}
+
+	return ok.Load(), errs.Load()
+}
+
+// probeWorker inserts batches until the channel is drained, counting the
+// events inserted in ok and the batches that failed in errs.
+func (r *Runner) probeWorker(ctx context.Context, repo Repository, batches <-chan []generator.Event, ok, errs *atomic.Int64) {
+	for batch := range batches {
+		err := r.insertBatch(ctx, repo, batch, nil)
+		n := len(batch)
+		generator.Release(batch)
+
+		if err != nil {
+			errs.Add(1)
+			continue
+		}
+
+		ok.Add(int64(n))
+	}
+}
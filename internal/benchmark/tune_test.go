@@ -0,0 +1,76 @@
+package benchmark
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchCostRepository takes 2ms per batch of up to 100 events and 20ms per
+// larger batch, which makes 100 the fastest batch size.
+func batchCostRepository() *mockRepository {
+	return &mockRepository{insertBatchFunc: func(_ context.Context, events []generator.Event) error {
+		if len(events) > 100 {
+			time.Sleep(20 * time.Millisecond)
+		} else {
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		return nil
+	}}
+}
+
+func TestTuneBatchSize(t *testing.T) {
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 1}
+
+	res := runner.TuneBatchSize(context.Background(), batchCostRepository(), 5, 1000)
+
+	require.NotNil(t, res)
+	assert.Empty(t, res.ErrorText)
+	assert.Equal(t, 5, res.Min)
+	assert.Equal(t, 1000, res.Max)
+	assert.Equal(t, 100, res.Optimal)
+	assert.Equal(t, 10, res.Probes[0].BatchSize, "starts at the configured batch size")
+
+	seen := make(map[int]bool)
+
+	for _, p := range res.Probes {
+		assert.False(t, seen[p.BatchSize], "batch size %d probed twice", p.BatchSize)
+		seen[p.BatchSize] = true
+
+		assert.GreaterOrEqual(t, p.Events, int64(4*p.BatchSize))
+
+		if p.BatchSize == res.Optimal {
+			assert.InDelta(t, p.Throughput, res.Throughput, 0)
+		}
+	}
+}
+
+func TestTuneBatchSizeBounds(t *testing.T) {
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 1}
+
+	res := runner.TuneBatchSize(context.Background(), batchCostRepository(), 20, 40)
+
+	assert.Equal(t, 40, res.Optimal)
+	assert.Equal(t, 20, res.Probes[0].BatchSize, "clamps the start to the bounds")
+
+	for _, p := range res.Probes {
+		assert.GreaterOrEqual(t, p.BatchSize, 20)
+		assert.LessOrEqual(t, p.BatchSize, 40)
+	}
+}
+
+func TestTuneBatchSizeSchemaError(t *testing.T) {
+	mock := &schemaCountingRepository{fail: map[int64]bool{1: true}}
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 1}
+
+	res := runner.TuneBatchSize(context.Background(), mock, 5, 1000)
+
+	assert.Equal(t, "schema failed", res.ErrorText)
+	assert.Empty(t, res.Probes)
+	assert.Equal(t, int64(1), mock.calls.Load(), "stops at the first failure")
+}
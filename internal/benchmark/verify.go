@@ -0,0 +1,105 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// VerifyResult compares the events an insert run reported inserted with the
+// rows the database gained during it. With duplicates in the run, any count
+// from Inserted less Duplicates (every duplicate dropped) to Inserted (every
+// one stored) is consistent; Missing and Extra are how far the count falls
+// outside that range.
+type VerifyResult struct {
+	Inserted  int64  `json:"inserted"`
+	Counted   int64  `json:"counted"`
+	Missing   int64  `json:"missing,omitempty"`
+	Extra     int64  `json:"extra,omitempty"`
+	ErrorText string `json:"error,omitempty"` // set when the rows could not be counted
+}
+
+// OK reports whether the count matched the inserted events.
+func (v *VerifyResult) OK() bool {
+	return v.ErrorText == "" && v.Missing == 0 && v.Extra == 0
+}
+
+// verification counts the events of a repository before an insert run so
+// the rows it added can be checked afterwards.
+type verification struct {
+	r       *Runner
+	counter CountRepository
+	before  int64
+	err     error
+}
+
+// startVerify counts the events of repo ahead of an insert run. It returns
+// nil unless Verify is set.
+func (r *Runner) startVerify(ctx context.Context, repo Repository) *verification {
+	if !r.Verify {
+		return nil
+	}
+
+	v := &verification{r: r}
+	if counter, ok := repo.(CountRepository); ok {
+		v.counter = counter
+		v.before, v.err = v.count(ctx)
+	}
+
+	return v
+}
+
+// count counts every event; generated events are all in the past. The count
+// runs to completion even once ctx is done, so an interrupted run is still
+// verified.
+func (v *verification) count(ctx context.Context) (int64, error) {
+	var n int64
+
+	err := v.r.attempt(context.WithoutCancel(ctx), func(ctx context.Context) error {
+		var err error
+		n, err = v.counter.CountEvents(ctx, time.Unix(0, 0), time.Now().Add(time.Hour))
+
+		return err
+	})
+
+	return n, err
+}
+
+// finish counts the events again and compares the rows the run added with
+// the inserted events, of which duplicates reused earlier event IDs.
+func (v *verification) finish(ctx context.Context, inserted, duplicates int64) *VerifyResult {
+	if v == nil {
+		return nil
+	}
+
+	res := &VerifyResult{Inserted: inserted}
+
+	counted, err := v.counted(ctx)
+	if err != nil {
+		res.ErrorText = err.Error()
+		return res
+	}
+
+	res.Counted = counted
+	res.Missing = max(0, inserted-duplicates-res.Counted)
+	res.Extra = max(0, res.Counted-inserted)
+
+	return res
+}
+
+// counted returns the number of rows added since the count at the start.
+func (v *verification) counted(ctx context.Context) (int64, error) {
+	switch {
+	case v.counter == nil:
+		return 0, errors.New("not supported")
+	case v.err != nil:
+		return 0, v.err
+	}
+
+	after, err := v.count(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return after - v.before, nil
+}
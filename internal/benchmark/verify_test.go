@@ -0,0 +1,103 @@
+package benchmark
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingMockRepository stores event IDs so CountEvents returns real counts.
+// With dedup a repeated ID is ignored, and every dropEvery-th batch is
+// acknowledged without being stored.
+type countingMockRepository struct {
+	mockRepository
+	mu        sync.Mutex
+	ids       map[string]int
+	batches   int
+	dedup     bool
+	dropEvery int
+}
+
+func (m *countingMockRepository) InsertBatch(_ context.Context, events []generator.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.batches++
+	if m.dropEvery > 0 && m.batches%m.dropEvery == 0 {
+		return nil
+	}
+
+	if m.ids == nil {
+		m.ids = make(map[string]int)
+	}
+
+	for _, e := range events {
+		if !m.dedup || m.ids[e.ID] == 0 {
+			m.ids[e.ID]++
+		}
+	}
+
+	return nil
+}
+
+func (m *countingMockRepository) CountEvents(context.Context, time.Time, time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var n int64
+	for _, c := range m.ids {
+		n += int64(c)
+	}
+
+	return n, nil
+}
+
+func TestRunInsertVerify(t *testing.T) {
+	mock := &countingMockRepository{dedup: true}
+	runner := &Runner{EventCount: 1000, BatchSize: 50, Workers: 1, WarmupBatches: 2, DuplicatePct: 20, Verify: true}
+
+	result := runner.RunInsert(context.Background(), mock)
+
+	require.NotNil(t, result.Verify)
+	assert.True(t, result.Verify.OK(), "%+v", result.Verify)
+	assert.Equal(t, int64(1000), result.Verify.Inserted)
+	assert.Equal(t, 1000-result.Duplicates, result.Verify.Counted, "warm-up rows are not counted")
+}
+
+func TestRunInsertVerifyStoredDuplicates(t *testing.T) {
+	mock := &countingMockRepository{}
+	runner := &Runner{EventCount: 1000, BatchSize: 50, Workers: 2, DuplicatePct: 20, Verify: true}
+
+	result := runner.RunInsert(context.Background(), mock)
+
+	assert.True(t, result.Verify.OK(), "%+v", result.Verify)
+	assert.Equal(t, int64(1000), result.Verify.Counted)
+}
+
+func TestRunInsertVerifyLoss(t *testing.T) {
+	mock := &countingMockRepository{dedup: true, dropEvery: 5}
+	runner := &Runner{EventCount: 1000, BatchSize: 50, Workers: 2, Verify: true}
+
+	result := runner.RunInsert(context.Background(), mock)
+
+	assert.False(t, result.Verify.OK())
+	assert.Equal(t, int64(800), result.Verify.Counted)
+	assert.Equal(t, int64(200), result.Verify.Missing)
+	assert.Zero(t, result.Verify.Extra)
+}
+
+func TestRunInsertVerifyUnsupported(t *testing.T) {
+	runner := &Runner{EventCount: 100, BatchSize: 10, Workers: 1, Verify: true}
+
+	result := runner.RunInsert(context.Background(), &mockRepository{})
+	assert.Equal(t, "not supported", result.Verify.ErrorText)
+	assert.False(t, result.Verify.OK())
+
+	runner.Verify = false
+	assert.Nil(t, runner.RunInsert(context.Background(), &mockRepository{}).Verify)
+}
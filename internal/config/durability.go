@@ -0,0 +1,57 @@
+package config
+
+// DurabilityLevel is a named write-durability setting for a single database.
+type DurabilityLevel struct {
+	Name  string
+	Apply func(cfg *Config)
+}
+
+// DurabilityLevels returns the durability spectrum for a database, ordered
+// from the weakest (fastest) to the strongest (safest) guarantee.
+// Returns nil for databases without a defined spectrum.
+func DurabilityLevels(db string) []DurabilityLevel {
+	switch db {
+	case "postgres":
+		return []DurabilityLevel{
+			{"synchronous_commit=off", func(c *Config) { c.Postgres.SynchronousCommit = "off" }},
+			{"synchronous_commit=on", func(c *Config) { c.Postgres.SynchronousCommit = "on" }},
+		}
+	case "mongodb":
+		return []DurabilityLevel{
+			{"w:0", func(c *Config) { c.MongoDB.WriteConcern, c.MongoDB.Journal = "0", false }},
+			{"w:1", func(c *Config) { c.MongoDB.WriteConcern, c.MongoDB.Journal = "1", false }},
+			{"w:1,j:true", func(c *Config) { c.MongoDB.WriteConcern, c.MongoDB.Journal = "1", true }},
+			{"w:majority,j:true", func(c *Config) { c.MongoDB.WriteConcern, c.MongoDB.Journal = "majority", true }},
+		}
+	case "cassandra":
+		return cqlDurabilityLevels(func(c *Config) *CassandraConfig { return &c.Cassandra })
+	case "scylladb":
+		return cqlDurabilityLevels(func(c *Config) *CassandraConfig { return &c.ScyllaDB })
+	case "clickhouse":
+		return []DurabilityLevel{
+			{"insert_quorum=0", func(c *Config) { c.ClickHouse.InsertQuorum = "0" }},
+			{"insert_quorum=auto", func(c *Config) { c.ClickHouse.InsertQuorum = "auto" }},
+		}
+	case "sqlite":
+		return []DurabilityLevel{
+			{"synchronous=OFF", func(c *Config) { c.SQLite.Synchronous = "OFF" }},
+			{"synchronous=NORMAL", func(c *Config) { c.SQLite.Synchronous = "NORMAL" }},
+			{"synchronous=FULL", func(c *Config) { c.SQLite.Synchronous = "FULL" }},
+		}
+	default:
+		return nil
+	}
+}
+
+func cqlDurabilityLevels(target func(*Config) *CassandraConfig) []DurabilityLevel {
+	levels := make([]DurabilityLevel, 0, 3)
+
+	for _, consistency := range []string{"ANY", "ONE", "QUORUM"} {
+		levels = append(levels, DurabilityLevel{
+			Name:  consistency,
+			Apply: func(c *Config) { target(c).WriteConsistency = consistency },
+		})
+	}
+
+	return levels
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is a benchmark configuration file: the connection settings of each
+// database under its name, flags of the run, custom query scenarios and
+// per-database overrides of the runner parameters.
+type File struct {
+	Config `yaml:",inline"`
+
+	// Flags are the values of command-line flags by name, e.g. events:
+	// 1000000; a list becomes a comma-separated value.
+	Flags     map[string]any         `yaml:"flags"`
+	Scenarios []Scenario             `yaml:"scenarios"`
+	Overrides map[string]RunOverride `yaml:"overrides"`
+}
+
+// Scenario is a custom query scenario, like one of the -custom-queries file.
+type Scenario struct {
+	Name       string            `yaml:"name"`
+	Window     string            `yaml:"window"`
+	Method     string            `yaml:"method"`
+	Statements map[string]string `yaml:"statements"`
+}
+
+// RunOverride replaces runner parameters of the run for one database; zero
+// values keep those of the run. The keys are those of the flags.
+type RunOverride struct {
+	Events       int     `yaml:"events"`
+	Batch        int     `yaml:"batch"`
+	Workers      int     `yaml:"workers"`
+	Queries      int     `yaml:"queries"`
+	QueryWorkers int     `yaml:"query-workers"`
+	Warmup       int     `yaml:"warmup"`
+	Preload      int     `yaml:"preload"`
+	Rate         float64 `yaml:"rate"`
+	QueryRate    float64 `yaml:"query-rate"`
+}
+
+// LoadFile loads the configuration file at path. Its connection settings
+// apply over the defaults, and the environment over both, like Load.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	f := &File{Config: *defaults()}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true) // a misspelled setting fails instead of being ignored
+
+	if err := dec.Decode(f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+
+	f.applyEnv()
+
+	return f, nil
+}
+
+func (f *File) validate() error {
+	databases := Databases()
+
+	for db, o := range f.Overrides {
+		if !slices.Contains(databases, db) {
+			return fmt.Errorf("overrides: unknown database %q, expected one of %s", db, strings.Join(databases, ", "))
+		}
+
+		if o.Events < 0 || o.Batch < 0 || o.Workers < 0 || o.Queries < 0 || o.QueryWorkers < 0 ||
+			o.Warmup < 0 || o.Preload < 0 || o.Rate < 0 || o.QueryRate < 0 {
+			return fmt.Errorf("overrides: %s: values must not be negative", db)
+		}
+	}
+
+	return nil
+}
+
+// Databases returns the names of the databases with connection settings,
+// as in -db and the sections of a configuration file.
+func Databases() []string {
+	t := reflect.TypeFor[Config]()
+	names := make([]string, 0, t.NumField())
+
+	for i := range t.NumField() {
+		names = append(names, t.Field(i).Tag.Get("yaml"))
+	}
+
+	return names
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, yaml string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "bench.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(yaml), 0o600))
+
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Setenv("POSTGRES_PASSWORD", "from-env")
+
+	f, err := LoadFile(writeFile(t, `
+postgres:
+  host: pg.internal
+  password: from-file
+  synchronous_commit: "off"
+cassandra:
+  hosts: [10.0.0.1, 10.0.0.2]
+clickhouse:
+  settings:
+    max_threads: 8
+    max_insert_block_size: 1048576
+yugabytedb:
+  port: "5434"
+  pool:
+    max_open_conns: 64
+    conn_max_lifetime: 10m
+flags:
+  db: [postgres, cassandra]
+  events: 5000000
+  rate: 2.5
+scenarios:
+  - name: last_6h
+    window: 6h
+    method: stats
+overrides:
+  cassandra:
+    workers: 32
+    batch: 500
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "pg.internal", f.Postgres.Host)
+	assert.Equal(t, "from-env", f.Postgres.Password, "the environment takes precedence")
+	assert.Equal(t, "off", f.Postgres.SynchronousCommit)
+	assert.Equal(t, "5432", f.Postgres.Port, "unset settings keep their defaults")
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, f.Cassandra.Hosts)
+	assert.Equal(t, map[string]string{"max_threads": "8", "max_insert_block_size": "1048576"}, f.ClickHouse.Settings)
+	assert.Equal(t, "5434", f.YugabyteDB.Port)
+	assert.Equal(t, "yugabyte", f.YugabyteDB.User)
+	assert.Equal(t, PoolConfig{MaxOpenConns: 64, MaxIdleConns: 5, ConnMaxLifetime: 10 * time.Minute}, f.YugabyteDB.Pool)
+
+	assert.Equal(t, []any{"postgres", "cassandra"}, f.Flags["db"])
+	assert.Equal(t, 5000000, f.Flags["events"])
+	assert.Equal(t, []Scenario{{Name: "last_6h", Window: "6h", Method: "stats"}}, f.Scenarios)
+	assert.Equal(t, RunOverride{Workers: 32, Batch: 500}, f.Overrides["cassandra"])
+}
+
+func TestLoadFileEmpty(t *testing.T) {
+	f, err := LoadFile(writeFile(t, ""))
+	require.NoError(t, err)
+
+	want, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, *want, f.Config)
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := map[string]string{
+		"unknown setting":  "postgres:\n  hots: x\n",
+		"unknown section":  "postgress:\n  host: x\n",
+		"unknown override": "overrides:\n  oracle:\n    workers: 4\n",
+		"negative":         "overrides:\n  postgres:\n    workers: -1\n",
+		"malformed":        "postgres: [",
+	}
+
+	for name, yaml := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadFile(writeFile(t, yaml))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestDatabases(t *testing.T) {
+	assert.Contains(t, Databases(), "postgres")
+	assert.Contains(t, Databases(), "bbolt")
+	assert.Len(t, Databases(), 20)
+}
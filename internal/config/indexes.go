@@ -0,0 +1,39 @@
+package config
+
+// Secondary index sets selectable with -indexes. Every set keeps the unique
+// event_id index, since the inserts rely on it to skip duplicate events.
+const (
+	IndexesNone    = "none"    // no secondary indexes
+	IndexesMinimal = "minimal" // the created_at index of the range scans only
+	IndexesFull    = "full"    // every index the query scenarios use
+)
+
+// ValidIndexSet reports whether set names an index set.
+func ValidIndexSet(set string) bool {
+	switch set {
+	case IndexesNone, IndexesMinimal, IndexesFull:
+		return true
+	default:
+		return false
+	}
+}
+
+// HasIndexSets reports whether the schema of db is built from an index set;
+// the other databases have no secondary indexes to vary.
+func HasIndexSets(db string) bool {
+	switch db {
+	case "postgres", "yugabytedb", "mongodb", "clickhouse", "sqlite":
+		return true
+	default:
+		return false
+	}
+}
+
+// SetIndexes selects the index set of every database that has one.
+func (c *Config) SetIndexes(set string) {
+	c.Postgres.Indexes = set
+	c.YugabyteDB.Indexes = set
+	c.MongoDB.Indexes = set
+	c.ClickHouse.Indexes = set
+	c.SQLite.Indexes = set
+}
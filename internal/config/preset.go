@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Preset is a named workload of -preset: the size of the dataset and of
+// its batches and the concurrency it is written and queried with, so runs
+// of the same preset compare across machines and databases.
+type Preset struct {
+	Events       int
+	Batch        int
+	Preload      int // events loaded before the measured inserts
+	Workers      int
+	Queries      int
+	QueryWorkers int
+}
+
+// presets are the workloads of -preset, from a quick look to a load that
+// saturates a single node.
+var presets = map[string]Preset{
+	"small":  {Events: 100_000, Batch: 1_000, Workers: 4, Queries: 20, QueryWorkers: 1},
+	"medium": {Events: 1_000_000, Batch: 5_000, Preload: 1_000_000, Workers: 8, Queries: 100, QueryWorkers: 1},
+	"large":  {Events: 10_000_000, Batch: 10_000, Preload: 10_000_000, Workers: 16, Queries: 100, QueryWorkers: 4},
+	"stress": {Events: 50_000_000, Batch: 20_000, Preload: 50_000_000, Workers: 64, Queries: 500, QueryWorkers: 16},
+}
+
+// LookupPreset returns the preset of a name.
+func LookupPreset(name string) (Preset, error) {
+	p, ok := presets[name]
+	if !ok {
+		return Preset{}, fmt.Errorf("unknown preset %q, expected one of %s", name, strings.Join(PresetNames(), ", "))
+	}
+
+	return p, nil
+}
+
+// PresetNames returns the names of the presets, from the smallest workload.
+func PresetNames() []string {
+	return slices.SortedFunc(maps.Keys(presets), func(a, b string) int {
+		return presets[a].Events - presets[b].Events
+	})
+}
+
+// Flags returns the values of the preset by the name of their flag.
+func (p Preset) Flags() map[string]string {
+	return map[string]string{
+		"events":        fmt.Sprint(p.Events),
+		"batch":         fmt.Sprint(p.Batch),
+		"preload":       fmt.Sprint(p.Preload),
+		"workers":       fmt.Sprint(p.Workers),
+		"queries":       fmt.Sprint(p.Queries),
+		"query-workers": fmt.Sprint(p.QueryWorkers),
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupPreset(t *testing.T) {
+	p, err := LookupPreset("large")
+	require.NoError(t, err)
+	assert.Equal(t, 10_000_000, p.Events)
+	assert.Equal(t, "16", p.Flags()["workers"])
+	assert.Equal(t, "10000000", p.Flags()["preload"])
+
+	_, err = LookupPreset("huge")
+	assert.ErrorContains(t, err, "small, medium, large, stress")
+}
+
+func TestPresets(t *testing.T) {
+	for _, name := range PresetNames() {
+		p := presets[name]
+
+		assert.Positive(t, p.Events, name)
+		assert.Positive(t, p.Batch, name)
+		assert.LessOrEqual(t, p.Batch, p.Events, name)
+		assert.Positive(t, p.Workers, name)
+		assert.Positive(t, p.Queries, name)
+		assert.Positive(t, p.QueryWorkers, name)
+	}
+}
@@ -0,0 +1,137 @@
+// Package export sends the final metrics of a benchmark run to a Prometheus
+// Pushgateway or a remote-write endpoint, for dashboards that follow the
+// results across runs.
+package export
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// Sample is one final metric of a run, such as the P95 latency of a query
+// scenario on one database.
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// help describes the exported metrics. Their names differ from those of the
+// live -metrics-addr endpoint, so both can be kept in one Prometheus.
+var help = map[string]string{
+	"dbbench_result_insert_events_per_second": "Insert throughput of the run.",
+	"dbbench_result_insert_errors":            "Insert batches of the run that failed every attempt.",
+	"dbbench_result_insert_latency_seconds":   "Batch insert latency quantiles of the run.",
+	"dbbench_result_query_latency_seconds":    "Query latency quantiles of each scenario of the run.",
+	"dbbench_result_query_errors":             "Query runs of each scenario of the run that failed every attempt.",
+	"dbbench_result_storage_bytes":            "Storage size of the events table after the run.",
+	"dbbench_result_storage_index_bytes":      "Index size of the events table after the run.",
+	"dbbench_result_storage_rows":             "Rows of the events table after the run.",
+}
+
+// ParseLabels parses comma-separated name=value run labels, e.g.
+// "env=ci,branch=main".
+func ParseLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	if s == "" {
+		return labels, nil
+	}
+
+	for _, field := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("invalid label %q, want name=value", field)
+		}
+
+		if name == "database" || name == "scenario" || name == "quantile" || name == "job" {
+			return nil, fmt.Errorf("label %q is set by the export", name)
+		}
+
+		labels[name] = value
+	}
+
+	return labels, nil
+}
+
+// Samples returns the final metrics of the results, labeled with their
+// database, or matrix cell, and scenario. Databases that failed have none;
+// scenarios a database cannot run are left out.
+func Samples(results map[string]*benchmark.Results) []Sample {
+	var samples []Sample
+
+	for _, db := range slices.Sorted(maps.Keys(results)) {
+		r := results[db]
+		if r.Error != nil || r.ErrorText != "" {
+			continue
+		}
+
+		samples = append(samples, insertSamples(db, r.Insert)...)
+		samples = append(samples, querySamples(db, r.Queries)...)
+		samples = append(samples, storageSamples(db, r)...)
+	}
+
+	return samples
+}
+
+func insertSamples(db string, ir *benchmark.InsertResult) []Sample {
+	if ir == nil {
+		return nil
+	}
+
+	labels := map[string]string{"database": db}
+
+	return []Sample{
+		{"dbbench_result_insert_events_per_second", labels, ir.Throughput},
+		{"dbbench_result_insert_errors", labels, float64(ir.ErrorCount)},
+		quantile("dbbench_result_insert_latency_seconds", labels, "0.5", ir.LatencyP50.Seconds()),
+		quantile("dbbench_result_insert_latency_seconds", labels, "0.95", ir.LatencyP95.Seconds()),
+		quantile("dbbench_result_insert_latency_seconds", labels, "0.99", ir.LatencyP99.Seconds()),
+	}
+}
+
+func querySamples(db string, queries map[string]*benchmark.QueryResult) []Sample {
+	var samples []Sample
+
+	for _, name := range slices.Sorted(maps.Keys(queries)) {
+		qr := queries[name]
+		if qr.ErrorText != "" {
+			continue
+		}
+
+		labels := map[string]string{"database": db, "scenario": name}
+		samples = append(samples,
+			quantile("dbbench_result_query_latency_seconds", labels, "0.5", qr.P50Duration.Seconds()),
+			quantile("dbbench_result_query_latency_seconds", labels, "0.95", qr.P95Duration.Seconds()),
+			quantile("dbbench_result_query_latency_seconds", labels, "0.99", qr.P99Duration.Seconds()),
+			Sample{"dbbench_result_query_errors", labels, float64(qr.ErrorCount)},
+		)
+	}
+
+	return samples
+}
+
+func storageSamples(db string, r *benchmark.Results) []Sample {
+	if r.Storage == nil {
+		return nil
+	}
+
+	labels := map[string]string{"database": db}
+
+	return []Sample{
+		{"dbbench_result_storage_bytes", labels, float64(r.Storage.TotalSize)},
+		{"dbbench_result_storage_index_bytes", labels, float64(r.Storage.IndexSize)},
+		{"dbbench_result_storage_rows", labels, float64(r.Storage.RowCount)},
+	}
+}
+
+// quantile returns a sample of the latency quantile q, labeled like labels.
+func quantile(name string, labels map[string]string, q string, seconds float64) Sample {
+	l := maps.Clone(labels)
+	l["quantile"] = q
+
+	return Sample{name, l, seconds}
+}
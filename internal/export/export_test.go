@@ -0,0 +1,121 @@
+package export
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResults() map[string]*benchmark.Results {
+	return map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Insert:   &benchmark.InsertResult{Throughput: 200, ErrorCount: 1, LatencyP95: 50 * time.Millisecond},
+			Queries: map[string]*benchmark.QueryResult{
+				"1_hour":       {P95Duration: 75 * time.Millisecond},
+				"point_lookup": {ErrorText: "not supported"},
+			},
+			Storage: &repository.StorageStats{TotalSize: 1 << 30, RowCount: 1000},
+		},
+		"redis": {Database: "redis", ErrorText: "connection refused"},
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels("env=ci, branch=main")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "ci", "branch": "main"}, labels)
+
+	labels, err = ParseLabels("")
+	require.NoError(t, err)
+	assert.Empty(t, labels)
+
+	for _, spec := range []string{"env", "env=", "=ci", "database=postgres", "env=ci,job=x"} {
+		_, err := ParseLabels(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestSamples(t *testing.T) {
+	samples := Samples(testResults())
+
+	assert.Contains(t, samples, Sample{"dbbench_result_insert_events_per_second", map[string]string{"database": "postgres"}, 200})
+	assert.Contains(t, samples, Sample{"dbbench_result_insert_errors", map[string]string{"database": "postgres"}, 1})
+	assert.Contains(t, samples, Sample{
+		"dbbench_result_query_latency_seconds",
+		map[string]string{"database": "postgres", "scenario": "1_hour", "quantile": "0.95"}, 0.075,
+	})
+	assert.Contains(t, samples, Sample{"dbbench_result_storage_bytes", map[string]string{"database": "postgres"}, 1 << 30})
+
+	for _, s := range samples {
+		assert.Contains(t, help, s.Name)
+		assert.Equal(t, "postgres", s.Labels["database"], "failed databases have no samples")
+		assert.NotEqual(t, "point_lookup", s.Labels["scenario"], "unsupported scenarios have no samples")
+	}
+}
+
+func TestPush(t *testing.T) {
+	var method, path, body string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	err := Push(srv.URL, Samples(testResults()), map[string]string{"env": "ci"})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/dbbench/env/ci", path)
+	assert.Contains(t, body, "dbbench_result_insert_events_per_second")
+	assert.Contains(t, body, "1_hour")
+}
+
+func TestRemoteWrite(t *testing.T) {
+	var header http.Header
+
+	var body []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		header, body = r.Header, b
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	err := RemoteWrite(context.Background(), srv.URL, Samples(testResults()), map[string]string{"env": "ci"}, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, "snappy", header.Get("Content-Encoding"))
+	assert.Equal(t, "application/x-protobuf", header.Get("Content-Type"))
+	assert.Equal(t, "0.1.0", header.Get("X-Prometheus-Remote-Write-Version"))
+
+	msg, err := snappy.Decode(nil, body)
+	require.NoError(t, err)
+
+	for _, s := range []string{"__name__", "dbbench_result_storage_rows", "job", "dbbench", "env", "ci", "1_hour"} {
+		assert.Contains(t, string(msg), s)
+	}
+}
+
+func TestRemoteWriteRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := RemoteWrite(context.Background(), srv.URL, Samples(testResults()), nil, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "out of order sample")
+}
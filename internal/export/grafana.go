@@ -0,0 +1,129 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// dashboardPanel is a row or a time series panel of the Grafana dashboard.
+type dashboardPanel struct {
+	ID          int              `json:"id"`
+	Type        string           `json:"type"`
+	Title       string           `json:"title"`
+	GridPos     gridPos          `json:"gridPos"`
+	Datasource  *datasource      `json:"datasource,omitempty"`
+	FieldConfig *fieldConfig     `json:"fieldConfig,omitempty"`
+	Targets     []influxQLTarget `json:"targets,omitempty"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type fieldConfig struct {
+	Defaults struct {
+		Unit   string         `json:"unit"`
+		Custom map[string]any `json:"custom"`
+	} `json:"defaults"`
+	Overrides []any `json:"overrides"`
+}
+
+type influxQLTarget struct {
+	RefID        string `json:"refId"`
+	RawQuery     bool   `json:"rawQuery"`
+	ResultFormat string `json:"resultFormat"`
+	Query        string `json:"query"`
+	Alias        string `json:"alias"`
+}
+
+// influxSource is the InfluxDB datasource the dashboard is imported with.
+var influxSource = &datasource{Type: "influxdb", UID: "${DS_INFLUXDB}"}
+
+// GrafanaDashboard returns a Grafana dashboard of the results WriteLines
+// writes, with a row per measurement and a panel per field, each charted by
+// database and, for queries, by scenario. Its InfluxQL queries read the
+// datasource chosen on import.
+func GrafanaDashboard() ([]byte, error) {
+	dashboard := map[string]any{
+		"__inputs": []map[string]string{{
+			"name": "DS_INFLUXDB", "label": "InfluxDB", "type": "datasource", "pluginId": "influxdb", "pluginName": "InfluxDB",
+		}},
+		"uid":           "dbbench-results",
+		"title":         "DB Benchmark Suite results",
+		"tags":          []string{"dbbench"},
+		"schemaVersion": 39,
+		"time":          map[string]string{"from": "now-30d", "to": "now"},
+		"templating": map[string]any{"list": []map[string]any{{
+			"name": "database", "label": "Database", "type": "query", "datasource": influxSource,
+			"query":      `SHOW TAG VALUES FROM "dbbench_insert" WITH KEY = "database"`,
+			"multi":      true,
+			"includeAll": true,
+			"refresh":    2,
+		}}},
+		"panels": dashboardPanels(),
+	}
+
+	data, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dashboard: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+// dashboardPanels lays out the panels of influxSchema two to a row.
+func dashboardPanels() []dashboardPanel {
+	var panels []dashboardPanel
+
+	y := 0
+
+	for _, m := range influxSchema {
+		panels = append(panels, dashboardPanel{ID: len(panels) + 1, Type: "row", Title: m.title, GridPos: gridPos{H: 1, W: 24, Y: y}})
+		y++
+
+		for i, f := range m.fields {
+			panels = append(panels, fieldPanel(len(panels)+1, m, f, gridPos{H: 8, W: 12, X: i % 2 * 12, Y: y + i/2*8}))
+		}
+
+		y += (len(m.fields) + 1) / 2 * 8
+	}
+
+	return panels
+}
+
+// fieldPanel charts field f of measurement m, one series per combination of
+// its tags.
+func fieldPanel(id int, m measurement, f field, pos gridPos) dashboardPanel {
+	groups := make([]string, len(m.tags))
+	aliases := make([]string, len(m.tags))
+
+	for i, tag := range m.tags {
+		groups[i] = fmt.Sprintf("%q", tag)
+		aliases[i] = "$tag_" + tag
+	}
+
+	cfg := &fieldConfig{Overrides: []any{}}
+	cfg.Defaults.Unit = f.unit
+	cfg.Defaults.Custom = map[string]any{"showPoints": "always", "spanNulls": true}
+
+	return dashboardPanel{
+		ID: id, Type: "timeseries", Title: f.title, GridPos: pos, Datasource: influxSource, FieldConfig: cfg,
+		Targets: []influxQLTarget{{
+			RefID:        "A",
+			RawQuery:     true,
+			ResultFormat: "time_series",
+			Query: fmt.Sprintf(`SELECT mean(%q) FROM %q WHERE ("database" =~ /^$database$/) AND $timeFilter GROUP BY time($__interval), %s fill(none)`,
+				f.key, m.name, strings.Join(groups, ", ")),
+			Alias: strings.Join(aliases, " "),
+		}},
+	}
+}
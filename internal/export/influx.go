@@ -0,0 +1,203 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// measurement is one InfluxDB measurement of the results: its tags besides
+// the run labels, and its fields with the title and Grafana unit of their
+// dashboard panel, under a row of the dashboard titled title.
+type measurement struct {
+	name   string
+	title  string
+	tags   []string
+	fields []field
+}
+
+type field struct {
+	key   string
+	title string
+	unit  string
+}
+
+// influxSchema lists what WriteLines writes and the dashboard shows.
+// dbbench_insert_rate holds a point per second of each insert run, the
+// others a point per run.
+var influxSchema = []measurement{
+	{"dbbench_insert", "Insert", []string{"database"}, []field{
+		{"throughput", "Insert throughput", "ops"},
+		{"p50_ms", "Insert batch P50", "ms"},
+		{"p95_ms", "Insert batch P95", "ms"},
+		{"p99_ms", "Insert batch P99", "ms"},
+		{"errors", "Failed insert batches", "short"},
+		{"events", "Inserted events", "short"},
+		{"duration_ms", "Insert duration", "ms"},
+	}},
+	{"dbbench_insert_rate", "Insert rate", []string{"database"}, []field{
+		{"events", "Events inserted per second", "ops"},
+	}},
+	{"dbbench_query", "Queries", []string{"database", "scenario"}, []field{
+		{"p50_ms", "Query P50", "ms"},
+		{"p95_ms", "Query P95", "ms"},
+		{"p99_ms", "Query P99", "ms"},
+		{"avg_ms", "Query average", "ms"},
+		{"errors", "Failed queries", "short"},
+	}},
+	{"dbbench_storage", "Storage", []string{"database"}, []field{
+		{"total_bytes", "Storage size", "bytes"},
+		{"index_bytes", "Index size", "bytes"},
+		{"rows", "Rows", "short"},
+	}},
+}
+
+// WriteLines writes the results in the InfluxDB line protocol, tagged with
+// their database, or matrix cell, and the run labels. The points of a
+// database carry the time its benchmark started, or at when it has none;
+// the per-second insert rate the second it covers. Databases that failed
+// and scenarios a database cannot run are left out.
+func WriteLines(w io.Writer, results map[string]*benchmark.Results, runLabels map[string]string, at time.Time) error {
+	var buf bytes.Buffer
+
+	for _, db := range slices.Sorted(maps.Keys(results)) {
+		r := results[db]
+		if r.Error != nil || r.ErrorText != "" {
+			continue
+		}
+
+		ts := r.Timestamp
+		if ts.IsZero() {
+			ts = at
+		}
+
+		writeResultLines(&buf, withLabel(runLabels, "database", db), r, ts)
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write lines: %w", err)
+	}
+
+	return nil
+}
+
+func writeResultLines(buf *bytes.Buffer, tags map[string]string, r *benchmark.Results, ts time.Time) {
+	if ir := r.Insert; ir != nil {
+		writeLine(buf, "dbbench_insert", tags, map[string]float64{
+			"throughput": ir.Throughput, "p50_ms": ms(ir.LatencyP50), "p95_ms": ms(ir.LatencyP95), "p99_ms": ms(ir.LatencyP99),
+			"errors": float64(ir.ErrorCount), "events": float64(ir.TotalEvents), "duration_ms": ms(ir.Duration),
+		}, ts)
+
+		writeRateLines(buf, tags, ir)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(r.Queries)) {
+		if qr := r.Queries[name]; qr.ErrorText == "" {
+			writeLine(buf, "dbbench_query", withLabel(tags, "scenario", name), map[string]float64{
+				"p50_ms": ms(qr.P50Duration), "p95_ms": ms(qr.P95Duration), "p99_ms": ms(qr.P99Duration),
+				"avg_ms": ms(qr.AvgDuration), "errors": float64(qr.ErrorCount),
+			}, ts)
+		}
+	}
+
+	if st := r.Storage; st != nil {
+		writeLine(buf, "dbbench_storage", tags, map[string]float64{
+			"total_bytes": float64(st.TotalSize), "index_bytes": float64(st.IndexSize), "rows": float64(st.RowCount),
+		}, ts)
+	}
+}
+
+// writeRateLines writes a point per second of the insert run, at the second
+// it covers. Runs that do not know when they started have none.
+func writeRateLines(buf *bytes.Buffer, tags map[string]string, ir *benchmark.InsertResult) {
+	if ir.Start.IsZero() {
+		return
+	}
+
+	for _, s := range ir.Timeline {
+		at := ir.Start.Add(time.Duration(s.Second) * time.Second)
+		writeLine(buf, "dbbench_insert_rate", tags, map[string]float64{"events": float64(s.Events)}, at)
+	}
+}
+
+// writeLine writes one point, its tags sorted by key and its fields in the
+// order of the schema. Every field is a float, so a field keeps its type
+// from run to run.
+func writeLine(buf *bytes.Buffer, name string, tags map[string]string, values map[string]float64, ts time.Time) {
+	buf.WriteString(name)
+
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		buf.WriteString("," + escapeTag(k) + "=" + escapeTag(tags[k]))
+	}
+
+	sep := " "
+
+	for _, f := range schemaOf(name).fields {
+		if v, ok := values[f.key]; ok {
+			buf.WriteString(sep + f.key + "=" + strconv.FormatFloat(v, 'f', -1, 64))
+			sep = ","
+		}
+	}
+
+	buf.WriteString(" " + strconv.FormatInt(ts.UnixNano(), 10) + "\n")
+}
+
+func schemaOf(name string) measurement {
+	i := slices.IndexFunc(influxSchema, func(m measurement) bool { return m.name == name })
+	return influxSchema[i]
+}
+
+// escapeTag escapes the commas, spaces and equal signs of a tag key or value.
+var escapeTag = strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`).Replace
+
+func withLabel(labels map[string]string, name, value string) map[string]string {
+	l := maps.Clone(labels)
+	if l == nil {
+		l = make(map[string]string, 1)
+	}
+
+	l[name] = value
+
+	return l
+}
+
+// ms converts a duration to fractional milliseconds.
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Influx writes the results to the InfluxDB write endpoint url, e.g.
+// http://localhost:8086/api/v2/write?org=acme&bucket=dbbench of InfluxDB 2
+// or http://localhost:8428/write of VictoriaMetrics. token, when set, is
+// sent as the API token.
+func Influx(ctx context.Context, url, token string, results map[string]*benchmark.Results, runLabels map[string]string, at time.Time) error {
+	var body bytes.Buffer
+	if err := WriteLines(&body, results, runLabels, at); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create influx request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	if token != "" {
+		req.Header.Set("Authorization", "Token "+token)
+	}
+
+	return send(req, url)
+}
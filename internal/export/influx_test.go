@@ -0,0 +1,77 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteLines(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	results := testResults()
+	results["postgres"].Timestamp = start
+	results["postgres"].Insert.Start = start.Add(time.Minute)
+	results["postgres"].Insert.Timeline = []benchmark.InsertSample{{Second: 0, Events: 120}, {Second: 1, Events: 80}}
+	results["my db"] = &benchmark.Results{Insert: &benchmark.InsertResult{Throughput: 10}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteLines(&buf, results, map[string]string{"env": "ci"}, start.Add(time.Hour)))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	assert.Contains(t, lines, "dbbench_insert,database=postgres,env=ci throughput=200,p50_ms=0,p95_ms=50,p99_ms=0,errors=1,events=0,duration_ms=0 1700000000000000000")
+	assert.Contains(t, lines, "dbbench_insert_rate,database=postgres,env=ci events=120 1700000060000000000")
+	assert.Contains(t, lines, "dbbench_insert_rate,database=postgres,env=ci events=80 1700000061000000000")
+	assert.Contains(t, lines, "dbbench_query,database=postgres,env=ci,scenario=1_hour p50_ms=0,p95_ms=75,p99_ms=0,avg_ms=0,errors=0 1700000000000000000")
+	assert.Contains(t, lines, "dbbench_storage,database=postgres,env=ci total_bytes=1073741824,index_bytes=0,rows=1000 1700000000000000000")
+	assert.Contains(t, lines, `dbbench_insert,database=my\ db,env=ci throughput=10,p50_ms=0,p95_ms=0,p99_ms=0,errors=0,events=0,duration_ms=0 1700003600000000000`,
+		"a result without a timestamp is written at the time given")
+
+	for _, line := range lines {
+		assert.NotContains(t, line, "redis", "failed databases have no points")
+		assert.NotContains(t, line, "point_lookup", "unsupported scenarios have no points")
+	}
+}
+
+func TestInflux(t *testing.T) {
+	var auth, body string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		auth, body = r.Header.Get("Authorization"), string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	err := Influx(context.Background(), srv.URL+"/api/v2/write?org=acme&bucket=dbbench", "secret", testResults(), nil, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Token secret", auth)
+	assert.Contains(t, body, "dbbench_insert,database=postgres throughput=200")
+}
+
+func TestBundledDashboard(t *testing.T) {
+	want, err := GrafanaDashboard()
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../grafana/dbbench-influxdb.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(want), string(got), "regenerate the dashboard with make dashboard")
+
+	for _, m := range influxSchema {
+		for _, f := range m.fields {
+			assert.Contains(t, string(got), `\"`+f.key+`\") FROM \"`+m.name+`\"`)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Job is the job label the results are pushed under.
+const Job = "dbbench"
+
+// timeout limits each export request.
+const timeout = 30 * time.Second
+
+// Push replaces the metrics of the Pushgateway group of the run labels with
+// samples. Runs with the same labels share a group, so the gateway keeps the
+// latest run of each label set.
+func Push(url string, samples []Sample, runLabels map[string]string) error {
+	pusher := push.New(url, Job).
+		Collector(sampleCollector(samples)).
+		Client(&http.Client{Timeout: timeout})
+
+	for _, name := range slices.Sorted(maps.Keys(runLabels)) {
+		pusher = pusher.Grouping(name, runLabels[name])
+	}
+
+	if err := pusher.Push(); err != nil {
+		return fmt.Errorf("failed to push to %s: %w", url, err)
+	}
+
+	return nil
+}
+
+// sampleCollector exposes samples as constant gauges. It describes no
+// metrics up front, which makes it an unchecked collector.
+type sampleCollector []Sample
+
+func (c sampleCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (c sampleCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c {
+		names := slices.Sorted(maps.Keys(s.Labels))
+		values := make([]string, len(names))
+
+		for i, name := range names {
+			values[i] = s.Labels[name]
+		}
+
+		desc := prometheus.NewDesc(s.Name, help[s.Name], names, nil)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, s.Value, values...)
+	}
+}
+
+// send sends req to url and fails on a response other than 2xx, quoting the
+// start of its body.
+func send(req *http.Request, url string) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to write to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("write to %s failed: %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
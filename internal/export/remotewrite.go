@@ -0,0 +1,89 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// RemoteWrite sends samples, stamped with at, to a Prometheus remote-write
+// endpoint. Each series is labeled with job="dbbench" and the run labels
+// besides its own.
+func RemoteWrite(ctx context.Context, url string, samples []Sample, runLabels map[string]string, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := newWriteRequest(ctx, url, writeRequest(samples, runLabels, at.UnixMilli()))
+	if err != nil {
+		return err
+	}
+
+	return send(req, url)
+}
+
+// newWriteRequest creates the snappy-compressed POST of a WriteRequest
+// message to url.
+func newWriteRequest(ctx context.Context, url string, msg []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(snappy.Encode(nil, msg)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create remote-write request: %w", err)
+	}
+
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	return req, nil
+}
+
+// writeRequest encodes samples as a remote-write WriteRequest protobuf
+// message, one TimeSeries of one Sample each.
+func writeRequest(samples []Sample, runLabels map[string]string, ts int64) []byte {
+	var msg []byte
+
+	for _, s := range samples {
+		labels := make(map[string]string, len(runLabels)+len(s.Labels)+2)
+		maps.Copy(labels, runLabels)
+		maps.Copy(labels, s.Labels)
+		labels["__name__"] = s.Name
+		labels["job"] = Job
+
+		msg = appendMessage(msg, 1, timeSeries(labels, s.Value, ts))
+	}
+
+	return msg
+}
+
+// timeSeries encodes a TimeSeries of one sample; remote write wants its
+// labels sorted by name.
+func timeSeries(labels map[string]string, value float64, ts int64) []byte {
+	var series []byte
+
+	for _, name := range slices.Sorted(maps.Keys(labels)) {
+		series = appendMessage(series, 1, appendString(appendString(nil, 1, name), 2, labels[name]))
+	}
+
+	var sample []byte
+	sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+	sample = protowire.AppendFixed64(sample, math.Float64bits(value))
+	sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+	sample = protowire.AppendVarint(sample, uint64(ts))
+
+	return appendMessage(series, 2, sample)
+}
+
+func appendString(b []byte, field protowire.Number, s string) []byte {
+	return protowire.AppendString(protowire.AppendTag(b, field, protowire.BytesType), s)
+}
+
+func appendMessage(b []byte, field protowire.Number, msg []byte) []byte {
+	return protowire.AppendBytes(protowire.AppendTag(b, field, protowire.BytesType), msg)
+}
@@ -0,0 +1,536 @@
+package generator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Columns of a dataset file. user_id, event_type and created_at are
+// required; the others have defaults.
+const (
+	colEventID   = "event_id"
+	colUserID    = "user_id"
+	colEventType = "event_type"
+	colPayload   = "payload"
+	colCreatedAt = "created_at"
+	colTenantID  = "tenant_id"
+)
+
+// defaultPayload is the payload of a dataset event without one.
+const defaultPayload = "{}"
+
+// Dataset is a file of events replayed in place of generated events, so
+// every run and database gets the same data. Its events are not held in
+// memory: each generator replaying it streams them from the file.
+type Dataset struct {
+	path    string
+	len     int
+	span    time.Duration // from the oldest created_at to the newest
+	shift   time.Duration // added to each created_at, making the newest the time the file was opened
+	tenants bool          // the file has a tenant_id column
+}
+
+// OpenDataset checks the events of a CSV, JSONL or Parquet file, chosen by
+// its extension, reading it through once; the replay shifts their
+// created_at so the newest is the time the file was opened. CSV files name
+// their columns in a header row; columns other than those of an event are
+// ignored; tenant_id, 0 when absent, is one of them.
+func OpenDataset(path string) (*Dataset, error) {
+	d, err := scanDataset(path, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return d, nil
+}
+
+// scanDataset reads the events of the file at path to count them and find
+// the span of their created_at.
+func scanDataset(path string, now time.Time) (*Dataset, error) {
+	r, err := openEventReader(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = r.close() }()
+
+	span, err := scanEvents(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if span.n == 0 {
+		return nil, errors.New("no events")
+	}
+
+	return &Dataset{
+		path:    path,
+		len:     span.n,
+		span:    span.newest.Sub(span.oldest),
+		shift:   now.Sub(span.newest),
+		tenants: r.tenants(),
+	}, nil
+}
+
+// scanEvents reads the rest of the events of r and returns the span of
+// their created_at.
+func scanEvents(r eventReader) (timeSpan, error) {
+	var span timeSpan
+
+	for {
+		e, err := r.next()
+		if errors.Is(err, io.EOF) {
+			return span, nil
+		}
+
+		if err != nil {
+			return timeSpan{}, err
+		}
+
+		span.add(e.CreatedAt)
+	}
+}
+
+// timeSpan tracks the oldest and newest of a number of times.
+type timeSpan struct {
+	oldest, newest time.Time
+	n              int
+}
+
+func (s *timeSpan) add(t time.Time) {
+	if s.n == 0 || t.Before(s.oldest) {
+		s.oldest = t
+	}
+
+	if s.n == 0 || t.After(s.newest) {
+		s.newest = t
+	}
+
+	s.n++
+}
+
+// Len returns the number of events of d.
+func (d *Dataset) Len() int {
+	return d.len
+}
+
+// Tenants reports whether the events of d come with their tenant_id.
+func (d *Dataset) Tenants() bool {
+	return d.tenants
+}
+
+// Span returns how far back from now the created_at of the events of d go.
+func (d *Dataset) Span() time.Duration {
+	return d.span
+}
+
+// Generator returns a generator of total events of d in batches of
+// batchSize, starting with its event at offset. Past the last event it
+// starts over from the first, the event IDs of each pass suffixed with its
+// number so they are new events. The generator reads the file as it goes;
+// its Err reports a failure to.
+func (d *Dataset) Generator(offset, total, batchSize int) *Generator {
+	return &Generator{
+		totalEvents: total,
+		batchSize:   batchSize,
+		replay:      &replay{d: d, pos: offset},
+	}
+}
+
+// event fills in the defaults of e, the i-th event of the replay of d as
+// read from the file, and shifts its created_at.
+func (d *Dataset) event(e Event, i int) Event {
+	row, pass := i%d.len, i/d.len
+
+	if e.ID == "" {
+		e.ID = "evt_" + strconv.Itoa(row+1)
+	}
+
+	if e.Payload == "" {
+		e.Payload = defaultPayload
+	}
+
+	if pass > 0 {
+		e.ID += "_" + strconv.Itoa(pass+1)
+	}
+
+	e.CreatedAt = e.CreatedAt.Add(d.shift)
+
+	return e
+}
+
+// replay streams the events of a dataset from its file, pass after pass.
+type replay struct {
+	d   *Dataset
+	r   eventReader // reader of the current pass, nil until it is opened
+	pos int         // index of the next event of the replay, over all passes
+}
+
+// next returns the next event of the replay.
+func (p *replay) next() (Event, error) {
+	e, err := p.read()
+	if errors.Is(err, io.EOF) {
+		err = fmt.Errorf("fewer than the %d events it had when opened", p.d.len)
+	}
+
+	if err != nil {
+		return Event{}, fmt.Errorf("%s: %w", p.d.path, err)
+	}
+
+	p.pos++
+
+	return p.d.event(e, p.pos-1), nil
+}
+
+// read reads the next event from the file, opening it again at the start
+// of each pass.
+func (p *replay) read() (Event, error) {
+	if p.r == nil {
+		if err := p.open(); err != nil {
+			return Event{}, err
+		}
+	}
+
+	e, err := p.r.next()
+	if errors.Is(err, io.EOF) && p.pos%p.d.len == 0 {
+		p.close()
+
+		if err := p.open(); err != nil {
+			return Event{}, err
+		}
+
+		return p.r.next()
+	}
+
+	return e, err
+}
+
+// open opens the file at the event of pos.
+func (p *replay) open() error {
+	r, err := openEventReader(p.d.path)
+	if err != nil {
+		return err
+	}
+
+	if err := r.skip(p.pos % p.d.len); err != nil {
+		_ = r.close()
+		return err
+	}
+
+	p.r = r
+
+	return nil
+}
+
+// close closes the file, if open.
+func (p *replay) close() {
+	if p.r != nil {
+		_ = p.r.close()
+		p.r = nil
+	}
+}
+
+// eventReader reads the events of a dataset file one at a time.
+type eventReader interface {
+	// next returns the next event, io.EOF after the last one.
+	next() (Event, error)
+	// skip passes over the next n events without checking them.
+	skip(n int) error
+	// tenants reports whether the events read so far have a tenant_id.
+	tenants() bool
+	close() error
+}
+
+// openEventReader opens the file at path with the reader of its format,
+// chosen by its extension.
+func openEventReader(path string) (eventReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := newEventReader(f, strings.ToLower(filepath.Ext(path)))
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// newEventReader returns the reader of f for the format of extension ext.
+func newEventReader(f *os.File, ext string) (eventReader, error) {
+	switch ext {
+	case ".csv":
+		return newCSVReader(f)
+	case ".jsonl", ".ndjson":
+		return newJSONLReader(f), nil
+	case ".parquet":
+		return newParquetReader(f)
+	default:
+		return nil, fmt.Errorf("unknown format %q, want .csv, .jsonl, .ndjson or .parquet", ext)
+	}
+}
+
+// datasetColumns maps the columns of an event to their index in a CSV
+// header, -1 for the optional ones the file lacks.
+func datasetColumns(header []string) (map[string]int, error) {
+	cols := map[string]int{colEventID: -1, colPayload: -1, colTenantID: -1}
+
+	for i, name := range header {
+		switch name := strings.ToLower(strings.TrimSpace(name)); name {
+		case colEventID, colUserID, colEventType, colPayload, colCreatedAt, colTenantID:
+			cols[name] = i
+		}
+	}
+
+	for _, required := range []string{colUserID, colEventType, colCreatedAt} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("no %s column", required)
+		}
+	}
+
+	return cols, nil
+}
+
+// csvReader reads the events of a CSV file with a header row.
+type csvReader struct {
+	f    *os.File
+	r    *csv.Reader
+	cols map[string]int
+}
+
+func newCSVReader(f *os.File) (*csvReader, error) {
+	r := csv.NewReader(f)
+	r.ReuseRecord = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading the header: %w", err)
+	}
+
+	cols, err := datasetColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &csvReader{f: f, r: r, cols: cols}, nil
+}
+
+func (c *csvReader) next() (Event, error) {
+	record, err := c.r.Read()
+	if err != nil {
+		return Event{}, err
+	}
+
+	field := func(col string) string {
+		if i := c.cols[col]; i >= 0 {
+			return record[i]
+		}
+
+		return ""
+	}
+
+	e, err := parseEvent(field(colEventID), field(colUserID), field(colEventType),
+		field(colPayload), field(colCreatedAt), field(colTenantID))
+	if err != nil {
+		line, _ := c.r.FieldPos(0)
+		return Event{}, fmt.Errorf("line %d: %w", line, err)
+	}
+
+	return e, nil
+}
+
+func (c *csvReader) skip(n int) error {
+	for range n {
+		if _, err := c.r.Read(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *csvReader) tenants() bool {
+	return c.cols[colTenantID] >= 0
+}
+
+func (c *csvReader) close() error {
+	return c.f.Close()
+}
+
+// jsonlReader reads the events of a JSONL file.
+type jsonlReader struct {
+	f          *os.File
+	dec        *json.Decoder
+	n          int  // events read or skipped
+	hasTenants bool // an event read has a tenant_id
+}
+
+func newJSONLReader(f *os.File) *jsonlReader {
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+
+	return &jsonlReader{f: f, dec: dec}
+}
+
+func (j *jsonlReader) next() (Event, error) {
+	var obj map[string]any
+
+	err := j.dec.Decode(&obj)
+	if errors.Is(err, io.EOF) {
+		return Event{}, err
+	}
+
+	j.n++
+
+	if err != nil {
+		return Event{}, fmt.Errorf("event %d: %w", j.n, err)
+	}
+
+	if _, ok := obj[colTenantID]; ok {
+		j.hasTenants = true
+	}
+
+	e, err := jsonEvent(obj)
+	if err != nil {
+		return Event{}, fmt.Errorf("event %d: %w", j.n, err)
+	}
+
+	return e, nil
+}
+
+func (j *jsonlReader) skip(n int) error {
+	for range n {
+		var raw json.RawMessage
+		if err := j.dec.Decode(&raw); err != nil {
+			return err
+		}
+
+		j.n++
+	}
+
+	return nil
+}
+
+func (j *jsonlReader) tenants() bool {
+	return j.hasTenants
+}
+
+func (j *jsonlReader) close() error {
+	return j.f.Close()
+}
+
+// jsonEvent converts an object of a JSONL file to an event. A payload that
+// is not a string is kept as its JSON.
+func jsonEvent(obj map[string]any) (Event, error) {
+	var fields [6]string
+
+	for i, col := range []string{colEventID, colUserID, colEventType, colPayload, colCreatedAt, colTenantID} {
+		s, err := jsonField(obj, col)
+		if err != nil {
+			return Event{}, err
+		}
+
+		fields[i] = s
+	}
+
+	return parseEvent(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5])
+}
+
+// jsonField returns the text of the column col of a JSONL object, "" when it
+// is missing.
+func jsonField(obj map[string]any, col string) (string, error) {
+	switch v := obj[col].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		if col != colPayload {
+			return "", fmt.Errorf("%s is not a string or number", col)
+		}
+
+		b, err := json.Marshal(v)
+
+		return string(b), err
+	}
+}
+
+// parseEvent builds an event from the text of its columns; an empty
+// tenantID is tenant 0.
+func parseEvent(id, userID, eventType, payload, createdAt, tenantID string) (Event, error) {
+	if eventType == "" {
+		return Event{}, fmt.Errorf("no %s", colEventType)
+	}
+
+	uid, err := strconv.ParseInt(strings.TrimSpace(userID), 10, 64)
+	if err != nil {
+		return Event{}, fmt.Errorf("%s %q is not an integer", colUserID, userID)
+	}
+
+	t, err := parseEventTime(createdAt)
+	if err != nil {
+		return Event{}, err
+	}
+
+	var tenant int64
+	if tenantID = strings.TrimSpace(tenantID); tenantID != "" {
+		if tenant, err = strconv.ParseInt(tenantID, 10, 64); err != nil {
+			return Event{}, fmt.Errorf("%s %q is not an integer", colTenantID, tenantID)
+		}
+	}
+
+	return Event{ID: id, UserID: uid, EventType: eventType, Payload: payload, CreatedAt: t, TenantID: tenant}, nil
+}
+
+// eventTimeLayouts are the layouts of a textual created_at; those without a
+// zone are UTC.
+var eventTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseEventTime parses a created_at: a timestamp in one of
+// eventTimeLayouts, or a Unix time in seconds, milliseconds, microseconds or
+// nanoseconds, told apart by their magnitude.
+func parseEventTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return unixTime(n), nil
+	}
+
+	for _, layout := range eventTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%s %q is neither an RFC 3339 timestamp nor a Unix time", colCreatedAt, s)
+}
+
+func unixTime(n int64) time.Time {
+	switch abs := max(n, -n); {
+	case abs < 1e11:
+		return time.Unix(n, 0).UTC()
+	case abs < 1e14:
+		return time.UnixMilli(n).UTC()
+	case abs < 1e17:
+		return time.UnixMicro(n).UTC()
+	default:
+		return time.Unix(0, n).UTC()
+	}
+}
@@ -0,0 +1,193 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// parquetBatchRows is the number of rows read from a Parquet file at once.
+const parquetBatchRows = 64 * 1024
+
+// parquetReader reads the events of a Parquet file, a batch of rows at a
+// time. A created_at column may be a timestamp, or text or a number as in
+// the other formats; the other columns are read as text.
+type parquetReader struct {
+	pf    *file.Reader
+	fr    *pqarrow.FileReader
+	cols  map[string]int
+	rr    pqarrow.RecordReader // nil until the first batch is read
+	rec   arrow.Record         // current batch
+	row   int                  // next row of rec
+	n     int                  // rows read or skipped
+	group int                  // first row group to read, past those skipped whole
+}
+
+func newParquetReader(f *os.File) (*parquetReader, error) {
+	pf, err := file.NewParquetReader(f)
+	if err != nil {
+		return nil, err
+	}
+
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: parquetBatchRows}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, err
+	}
+
+	cols, err := parquetColumns(fr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parquetReader{pf: pf, fr: fr, cols: cols}, nil
+}
+
+// parquetColumns maps the columns of an event to their index in the schema
+// of fr, as datasetColumns does for a CSV header.
+func parquetColumns(fr *pqarrow.FileReader) (map[string]int, error) {
+	schema, err := fr.Schema()
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]string, schema.NumFields())
+	for i, field := range schema.Fields() {
+		header[i] = field.Name
+	}
+
+	return datasetColumns(header)
+}
+
+func (p *parquetReader) next() (Event, error) {
+	for p.rec == nil || p.row == int(p.rec.NumRows()) {
+		if err := p.nextBatch(); err != nil {
+			return Event{}, err
+		}
+	}
+
+	e, err := parquetEvent(p.rec, p.row, p.cols)
+	p.row++
+	p.n++
+
+	if err != nil {
+		return Event{}, fmt.Errorf("row %d: %w", p.n, err)
+	}
+
+	return e, nil
+}
+
+// skip passes over whole row groups without reading them when nothing has
+// been read yet, then over the rest of the n rows.
+func (p *parquetReader) skip(n int) error {
+	if p.rr == nil {
+		n = p.skipRowGroups(n)
+	}
+
+	for n > 0 {
+		if p.rec == nil || p.row == int(p.rec.NumRows()) {
+			if err := p.nextBatch(); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		k := min(n, int(p.rec.NumRows())-p.row)
+		p.row += k
+		p.n += k
+		n -= k
+	}
+
+	return nil
+}
+
+// skipRowGroups passes over the row groups within the next n rows and
+// returns the rows left to skip.
+func (p *parquetReader) skipRowGroups(n int) int {
+	for ; p.group < p.pf.NumRowGroups(); p.group++ {
+		rows := int(p.pf.MetaData().RowGroup(p.group).NumRows())
+		if n < rows {
+			break
+		}
+
+		n -= rows
+		p.n += rows
+	}
+
+	return n
+}
+
+// nextBatch reads the next batch of rows into rec, io.EOF after the last.
+func (p *parquetReader) nextBatch() error {
+	if p.rr == nil {
+		groups := make([]int, 0, p.pf.NumRowGroups())
+		for i := p.group; i < p.pf.NumRowGroups(); i++ {
+			groups = append(groups, i)
+		}
+
+		rr, err := p.fr.GetRecordReader(context.Background(), nil, groups)
+		if err != nil {
+			return err
+		}
+
+		p.rr = rr
+	}
+
+	if !p.rr.Next() {
+		// The reader reports the end of the file as io.EOF.
+		if err := p.rr.Err(); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		return io.EOF
+	}
+
+	p.rec, p.row = p.rr.Record(), 0
+
+	return nil
+}
+
+func (p *parquetReader) tenants() bool {
+	return p.cols[colTenantID] >= 0
+}
+
+// close closes the file as well.
+func (p *parquetReader) close() error {
+	if p.rr != nil {
+		p.rr.Release()
+	}
+
+	return p.pf.Close()
+}
+
+// parquetEvent returns the event of a row of rec.
+func parquetEvent(rec arrow.Record, row int, cols map[string]int) (Event, error) {
+	text := func(col string) string {
+		i := cols[col]
+		if i < 0 || rec.Column(i).IsNull(row) {
+			return ""
+		}
+
+		// The value may point into the buffers of the record, which are
+		// reused for the next one.
+		return strings.Clone(rec.Column(i).ValueStr(row))
+	}
+
+	createdAt := text(colCreatedAt)
+	if times, ok := rec.Column(cols[colCreatedAt]).(*array.Timestamp); ok && times.IsValid(row) {
+		createdAt = times.Value(row).ToTime(times.DataType().(*arrow.TimestampType).Unit).Format(time.RFC3339Nano)
+	}
+
+	return parseEvent(text(colEventID), text(colUserID), text(colEventType),
+		text(colPayload), createdAt, text(colTenantID))
+}
@@ -0,0 +1,221 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDataset(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+// replayEvents returns total events of the replay of d from offset.
+func replayEvents(t *testing.T, d *Dataset, offset, total int) []Event {
+	t.Helper()
+
+	gen := d.Generator(offset, total, 100)
+
+	var events []Event
+	for batch := range gen.Generate() {
+		events = append(events, batch...)
+	}
+
+	require.NoError(t, gen.Err())
+
+	return events
+}
+
+// checkDataset checks the three events every test file holds, an hour
+// apart, with the newest shifted to about now.
+func checkDataset(t *testing.T, d *Dataset) {
+	t.Helper()
+
+	require.Equal(t, 3, d.Len())
+	assert.Equal(t, 2*time.Hour, d.Span())
+
+	events := replayEvents(t, d, 0, 3)
+	assert.Equal(t, "a1", events[0].ID)
+	assert.Equal(t, int64(7), events[0].UserID)
+	assert.Equal(t, "login", events[0].EventType)
+	assert.Equal(t, `{"k": 1}`, events[0].Payload)
+	assert.Equal(t, "evt_2", events[1].ID, "an event without an ID gets one")
+	assert.Equal(t, defaultPayload, events[1].Payload)
+	assert.WithinDuration(t, time.Now(), events[2].CreatedAt, time.Minute)
+	assert.Equal(t, time.Hour, events[1].CreatedAt.Sub(events[0].CreatedAt))
+}
+
+func TestOpenDatasetCSV(t *testing.T) {
+	path := writeDataset(t, "events.csv", `user_id,event_type,created_at,event_id,payload,extra
+7,login,2025-01-01T10:00:00Z,a1,"{""k"": 1}",x
+8,logout,2025-01-01 11:00:00,,,y
+9,search,1735732800,a3,{},z
+`)
+
+	d, err := OpenDataset(path)
+	require.NoError(t, err)
+	checkDataset(t, d)
+}
+
+func TestOpenDatasetJSONL(t *testing.T) {
+	path := writeDataset(t, "events.jsonl", `{"event_id": "a1", "user_id": 7, "event_type": "login", "payload": "{\"k\": 1}", "created_at": "2025-01-01T10:00:00Z"}
+{"user_id": "8", "event_type": "logout", "created_at": 1735729200000}
+
+{"event_id": "a3", "user_id": 9, "event_type": "search", "payload": {"q": "x"}, "created_at": "2025-01-01T12:00:00.000Z"}
+`)
+
+	d, err := OpenDataset(path)
+	require.NoError(t, err)
+	checkDataset(t, d)
+	assert.JSONEq(t, `{"q": "x"}`, replayEvents(t, d, 2, 1)[0].Payload)
+}
+
+func TestOpenDatasetParquet(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "event_id", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "user_id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "event_type", Type: arrow.BinaryTypes.String},
+		{Name: "payload", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "created_at", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	b.Field(0).(*array.StringBuilder).AppendValues([]string{"a1", "", "a3"}, []bool{true, false, true})
+	b.Field(1).(*array.Int64Builder).AppendValues([]int64{7, 8, 9}, nil)
+	b.Field(2).(*array.StringBuilder).AppendValues([]string{"login", "logout", "search"}, nil)
+	b.Field(3).(*array.StringBuilder).AppendValues([]string{`{"k": 1}`, "", "{}"}, []bool{true, false, true})
+
+	for i := range 3 {
+		b.Field(4).(*array.TimestampBuilder).AppendTime(base.Add(time.Duration(i) * time.Hour))
+	}
+
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	path := filepath.Join(t.TempDir(), "events.parquet")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	w, err := pqarrow.NewFileWriter(schema, f, nil, pqarrow.DefaultWriterProps())
+	require.NoError(t, err)
+	require.NoError(t, w.Write(rec))
+	require.NoError(t, w.Close())
+
+	d, err := OpenDataset(path)
+	require.NoError(t, err)
+	checkDataset(t, d)
+}
+
+func TestOpenDatasetErrors(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"events.txt", "", "unknown format"},
+		{"events.csv", "user_id,event_type\n1,login\n", "no created_at column"},
+		{"events.csv", "user_id,event_type,created_at\nx,login,2025-01-01T10:00:00Z\n", `line 2: user_id "x" is not an integer`},
+		{"events.csv", "user_id,event_type,created_at\n1,login,yesterday\n", "is neither an RFC 3339 timestamp"},
+		{"events.csv", "user_id,event_type,created_at\n", "no events"},
+		{"events.csv", "user_id,event_type,created_at,tenant_id\n1,login,1735732800,acme\n", `tenant_id "acme" is not an integer`},
+		{"events.jsonl", `{"user_id": 1, "event_type": "login"` + "\n", "event 1"},
+		{"events.jsonl", `{"user_id": [1], "event_type": "login", "created_at": 1}` + "\n", "user_id is not a string or number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			_, err := OpenDataset(writeDataset(t, tt.name, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestDatasetGeneratorOffset(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var want []Event
+	for batch := range NewSeeded(250, 100, 1, now).Generate() {
+		want = append(want, batch...)
+	}
+
+	for _, name := range []string{"events.csv", "events.jsonl", "events.parquet"} {
+		t.Run(name, func(t *testing.T) {
+			// The Parquet file has a row group per batch of 100 events, so
+			// the offset skips two of them whole.
+			path := filepath.Join(t.TempDir(), name)
+			_, err := WriteDataset(path, NewSeeded(250, 100, 1, now).Generate(), false)
+			require.NoError(t, err)
+
+			d, err := OpenDataset(path)
+			require.NoError(t, err)
+
+			events := replayEvents(t, d, 230, 40)
+			require.Len(t, events, 40)
+			assert.Equal(t, want[230].ID, events[0].ID)
+			assert.Equal(t, want[249].ID, events[19].ID)
+			assert.Equal(t, want[0].ID+"_2", events[20].ID, "the next pass starts from the first event")
+			assert.Equal(t, want[19].ID+"_2", events[39].ID)
+		})
+	}
+}
+
+func TestDatasetGeneratorShrunkFile(t *testing.T) {
+	path := writeDataset(t, "events.csv", "user_id,event_type,created_at\n1,login,1735725600\n2,login,1735725601\n")
+
+	d, err := OpenDataset(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("user_id,event_type,created_at\n1,login,1735725600\n"), 0o600))
+
+	gen := d.Generator(0, 4, 1)
+
+	var n int
+	for batch := range gen.Generate() {
+		n += len(batch)
+	}
+
+	assert.Equal(t, 1, n)
+	assert.ErrorContains(t, gen.Err(), "fewer than the 2 events it had when opened")
+}
+
+func TestParseEventTime(t *testing.T) {
+	want := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, s := range []string{
+		"2025-01-01T10:00:00Z", "2025-01-01T12:00:00+02:00", "2025-01-01 10:00:00", "2025-01-01T10:00:00",
+		"1735725600", "1735725600000", "1735725600000000", "1735725600000000000",
+	} {
+		got, err := parseEventTime(s)
+		require.NoError(t, err, s)
+		assert.True(t, want.Equal(got), "%s parsed as %v", s, got)
+	}
+}
+
+func TestDatasetGenerator(t *testing.T) {
+	path := writeDataset(t, "events.csv", "event_id,user_id,event_type,created_at\na,1,login,1735725600\nb,2,logout,1735725660\n")
+
+	d, err := OpenDataset(path)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, e := range replayEvents(t, d, 1, 4) {
+		ids = append(ids, e.ID)
+	}
+
+	assert.Equal(t, []string{"b", "a_2", "b_2", "a_3"}, ids, "a replay past the end starts over with new IDs")
+}
@@ -0,0 +1,266 @@
+package generator
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// datasetWriter writes events in the format of a dataset file.
+type datasetWriter interface {
+	write(events []Event) error
+	close() error
+}
+
+// WriteDataset writes the events of batches to a CSV, JSONL or Parquet file,
+// chosen by its extension, in the columns OpenDataset reads, tenant_id
+// included with tenants, and returns how many it wrote. The batches are
+// released once written. The file is written as path.tmp and renamed to path
+// once complete, so a failed write leaves no partial dataset behind.
+func WriteDataset(path string, batches <-chan []Event, tenants bool) (int, error) {
+	tmp := path + ".tmp"
+
+	written, err := writeDatasetFile(tmp, strings.ToLower(filepath.Ext(path)), batches, tenants)
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp)
+
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return written, nil
+}
+
+// writeDatasetFile writes the events of batches to the file at path in the
+// format of the extension ext.
+func writeDatasetFile(path, ext string, batches <-chan []Event, tenants bool) (int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+
+	w, err := newDatasetWriter(f, ext, tenants)
+	if err != nil {
+		_ = f.Close()
+
+		return 0, err
+	}
+
+	written, err := writeBatches(w, batches)
+
+	// The Parquet writer closes the file itself.
+	err = errors.Join(err, w.close())
+	if _, ok := w.(*parquetWriter); !ok {
+		err = errors.Join(err, f.Close())
+	}
+
+	return written, err
+}
+
+// newDatasetWriter returns the writer of the format of the extension ext.
+func newDatasetWriter(f *os.File, ext string, tenants bool) (datasetWriter, error) {
+	switch ext {
+	case ".csv":
+		return newCSVWriter(f, tenants)
+	case ".jsonl", ".ndjson":
+		return &jsonlWriter{w: bufio.NewWriter(f), tenants: tenants}, nil
+	case ".parquet":
+		return newParquetWriter(f, tenants)
+	default:
+		return nil, fmt.Errorf("unknown format %q, want .csv, .jsonl, .ndjson or .parquet", ext)
+	}
+}
+
+// writeBatches writes the events of batches with w until a write fails,
+// releasing every batch, and returns how many it wrote.
+func writeBatches(w datasetWriter, batches <-chan []Event) (int, error) {
+	var err error
+
+	written := 0
+
+	for batch := range batches {
+		if err == nil {
+			err = w.write(batch)
+			written += len(batch)
+		}
+
+		Release(batch)
+	}
+
+	return written, err
+}
+
+// formatEventTime formats a created_at for the text formats.
+func formatEventTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+type csvWriter struct {
+	f       *os.File
+	w       *csv.Writer
+	tenants bool
+	record  []string
+}
+
+func newCSVWriter(f *os.File, tenants bool) (*csvWriter, error) {
+	header := []string{colEventID, colUserID, colEventType, colPayload, colCreatedAt}
+	if tenants {
+		header = append(header, colTenantID)
+	}
+
+	w := csv.NewWriter(f)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &csvWriter{f: f, w: w, tenants: tenants}, nil
+}
+
+func (c *csvWriter) write(events []Event) error {
+	for _, e := range events {
+		c.record = append(c.record[:0], e.ID, strconv.FormatInt(e.UserID, 10), e.EventType, e.Payload, formatEventTime(e.CreatedAt))
+		if c.tenants {
+			c.record = append(c.record, strconv.FormatInt(e.TenantID, 10))
+		}
+
+		if err := c.w.Write(c.record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *csvWriter) close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonlEvent is an event as a line of a JSONL file.
+type jsonlEvent struct {
+	EventID   string `json:"event_id"`
+	UserID    int64  `json:"user_id"`
+	EventType string `json:"event_type"`
+	Payload   string `json:"payload"`
+	CreatedAt string `json:"created_at"`
+	TenantID  *int64 `json:"tenant_id,omitempty"`
+}
+
+type jsonlWriter struct {
+	w       *bufio.Writer
+	tenants bool
+}
+
+func (j *jsonlWriter) write(events []Event) error {
+	enc := json.NewEncoder(j.w)
+	enc.SetEscapeHTML(false)
+
+	for _, e := range events {
+		line := jsonlEvent{e.ID, e.UserID, e.EventType, e.Payload, formatEventTime(e.CreatedAt), nil}
+		if j.tenants {
+			line.TenantID = &e.TenantID
+		}
+
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (j *jsonlWriter) close() error {
+	return j.w.Flush()
+}
+
+// parquetSchema returns the schema of the Parquet files WriteDataset
+// writes, with a tenant_id column with tenants.
+func parquetSchema(tenants bool) *arrow.Schema {
+	fields := []arrow.Field{
+		{Name: colEventID, Type: arrow.BinaryTypes.String},
+		{Name: colUserID, Type: arrow.PrimitiveTypes.Int64},
+		{Name: colEventType, Type: arrow.BinaryTypes.String},
+		{Name: colPayload, Type: arrow.BinaryTypes.String},
+		{Name: colCreatedAt, Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}},
+	}
+
+	if tenants {
+		fields = append(fields, arrow.Field{Name: colTenantID, Type: arrow.PrimitiveTypes.Int64})
+	}
+
+	return arrow.NewSchema(fields, nil)
+}
+
+// parquetWriter writes the events to a Parquet file, Snappy-compressed.
+type parquetWriter struct {
+	w       *pqarrow.FileWriter
+	b       *array.RecordBuilder
+	tenants bool
+}
+
+func newParquetWriter(f *os.File, tenants bool) (*parquetWriter, error) {
+	schema := parquetSchema(tenants)
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+
+	w, err := pqarrow.NewFileWriter(schema, f, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, err
+	}
+
+	return &parquetWriter{w: w, b: array.NewRecordBuilder(memory.DefaultAllocator, schema), tenants: tenants}, nil
+}
+
+func (p *parquetWriter) write(events []Event) error {
+	p.appendEvents(events)
+
+	rec := p.b.NewRecord()
+	defer rec.Release()
+
+	return p.w.Write(rec)
+}
+
+// appendEvents appends the columns of events to the record being built.
+func (p *parquetWriter) appendEvents(events []Event) {
+	ids := p.b.Field(0).(*array.StringBuilder)
+	users := p.b.Field(1).(*array.Int64Builder)
+	types := p.b.Field(2).(*array.StringBuilder)
+	payloads := p.b.Field(3).(*array.StringBuilder)
+	times := p.b.Field(4).(*array.TimestampBuilder)
+
+	for _, e := range events {
+		ids.Append(e.ID)
+		users.Append(e.UserID)
+		types.Append(e.EventType)
+		payloads.Append(e.Payload)
+		times.AppendTime(e.CreatedAt)
+	}
+
+	if p.tenants {
+		tenants := p.b.Field(5).(*array.Int64Builder)
+		for _, e := range events {
+			tenants.Append(e.TenantID)
+		}
+	}
+}
+
+func (p *parquetWriter) close() error {
+	p.b.Release()
+	return p.w.Close()
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDataset(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var want []Event
+	for batch := range NewSeeded(250, 100, 1, now).Generate() {
+		want = append(want, batch...)
+	}
+
+	for _, name := range []string{"events.csv", "events.jsonl", "events.parquet"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+
+			n, err := WriteDataset(path, NewSeeded(250, 100, 1, now).Generate(), false)
+			require.NoError(t, err)
+			assert.Equal(t, 250, n)
+			assert.NoFileExists(t, path+".tmp")
+
+			d, err := OpenDataset(path)
+			require.NoError(t, err)
+			require.Equal(t, len(want), d.Len())
+
+			events := replayEvents(t, d, 0, d.Len())
+			shift := events[0].CreatedAt.Sub(want[0].CreatedAt.Truncate(time.Microsecond))
+
+			for i, e := range events {
+				assert.Equal(t, want[i].ID, e.ID)
+				assert.Equal(t, want[i].UserID, e.UserID)
+				assert.Equal(t, want[i].EventType, e.EventType)
+				assert.Equal(t, want[i].Payload, e.Payload)
+				assert.Equal(t, want[i].CreatedAt.Truncate(time.Microsecond).Add(shift), e.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestWriteDatasetTenants(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var want []Event
+	for batch := range NewSeeded(250, 100, 1, now).WithTenants(5, UserDist{}).Generate() {
+		want = append(want, batch...)
+	}
+
+	for _, name := range []string{"events.csv", "events.jsonl", "events.parquet"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+
+			_, err := WriteDataset(path, NewSeeded(250, 100, 1, now).WithTenants(5, UserDist{}).Generate(), true)
+			require.NoError(t, err)
+
+			d, err := OpenDataset(path)
+			require.NoError(t, err)
+			assert.True(t, d.Tenants())
+
+			for i, e := range replayEvents(t, d, 0, d.Len()) {
+				assert.Equal(t, want[i].TenantID, e.TenantID)
+			}
+
+			_, err = WriteDataset(path, NewSeeded(10, 10, 1, now).Generate(), false)
+			require.NoError(t, err)
+
+			d, err = OpenDataset(path)
+			require.NoError(t, err)
+			assert.False(t, d.Tenants(), "no tenant_id column without tenants")
+		})
+	}
+}
+
+func TestWriteDatasetUnknownFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.txt")
+
+	_, err := WriteDataset(path, NewSeeded(10, 10, 1, time.Now()).Generate(), false)
+	require.ErrorContains(t, err, "unknown format")
+
+	assert.NoFileExists(t, path)
+	assert.NoFileExists(t, path+".tmp")
+}
+
+func TestWriteDatasetKeepsFileOnFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.csv")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0o644))
+	require.NoError(t, os.Mkdir(path+".tmp", 0o755))
+
+	_, err := WriteDataset(path, NewSeeded(10, 10, 1, time.Now()).Generate(), false)
+	require.Error(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(data), "a failed write leaves the file in place")
+}
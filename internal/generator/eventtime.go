@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Shapes of the created_at of the events of a TimeDist.
+const (
+	TimeExponential = "exponential" // spread over the window, biased toward recent days
+	TimeUniform     = "uniform"     // spread evenly over the window
+	TimeOrdered     = "ordered"     // ascending over the window, as a live stream delivers them
+)
+
+// DefaultDays is the window of created_at of a TimeDist without one.
+const DefaultDays = 90
+
+// decay is the rate of the exponential shape over its whole window; over
+// the default 90 days it is 0.05 a day.
+const decay = 4.5
+
+// TimeDist is the distribution of the created_at of generated events, all
+// in the Days before the reference time of the generator.
+type TimeDist struct {
+	Shape   string        // TimeExponential, TimeUniform or TimeOrdered; "" is TimeExponential
+	Days    int           // days back from the reference time the events span, 0 for DefaultDays
+	LatePct int           // percentage of TimeOrdered events that arrive late
+	MaxLate time.Duration // how far a late event can fall behind its place in the stream
+}
+
+// CheckTimeShape checks the name of a TimeDist shape.
+func CheckTimeShape(shape string) error {
+	switch shape {
+	case TimeExponential, TimeUniform, TimeOrdered:
+		return nil
+	default:
+		return fmt.Errorf("unknown shape %q, want %s, %s or %s", shape, TimeExponential, TimeUniform, TimeOrdered)
+	}
+}
+
+func (d TimeDist) days() int {
+	if d.Days <= 0 {
+		return DefaultDays
+	}
+
+	return d.Days
+}
+
+// Span returns how far back from the reference time the created_at of an
+// event can be, late arrivals included.
+func (d TimeDist) Span() time.Duration {
+	span := time.Duration(d.days()) * 24 * time.Hour
+	if d.Shape == TimeOrdered && d.LatePct > 0 {
+		span += d.MaxLate
+	}
+
+	return span
+}
+
+// WithTimeDist makes g spread the created_at of its events as d does, the
+// 90 days of TimeExponential by default, and returns g.
+func (g *Generator) WithTimeDist(d TimeDist) *Generator {
+	g.times = d
+	return g
+}
+
+// createdAt picks the created_at of the next event.
+func (g *Generator) createdAt() time.Time {
+	now := g.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	days := g.times.days()
+
+	switch g.times.Shape {
+	case TimeUniform:
+		return now.Add(-time.Duration(g.rand.Int63n(int64(days)*24*60*60)) * time.Second)
+	case TimeOrdered:
+		return g.orderedTime(now, days)
+	default:
+		return g.exponentialTime(now, days)
+	}
+}
+
+// exponentialTime picks a day with an exponential bias toward recent ones,
+// then a second within it.
+func (g *Generator) exponentialTime(now time.Time, days int) time.Time {
+	lambda := decay / float64(days) // lower = more spread, higher = more recent
+
+	daysAgo := min(int(-math.Log(1-g.rand.Float64())/lambda), days-1)
+
+	hoursAgo := g.rand.Intn(24)
+	minutesAgo := g.rand.Intn(60)
+	secondsAgo := g.rand.Intn(60)
+
+	return now.
+		AddDate(0, 0, -daysAgo).
+		Add(-time.Duration(hoursAgo) * time.Hour).
+		Add(-time.Duration(minutesAgo) * time.Minute).
+		Add(-time.Duration(secondsAgo) * time.Second)
+}
+
+// orderedTime places the events of g evenly over the window in the order
+// they are generated, the last one at now, and holds LatePct of them back
+// by up to MaxLate so they arrive after newer events.
+func (g *Generator) orderedTime(now time.Time, days int) time.Time {
+	window := time.Duration(days) * 24 * time.Hour
+	g.seq++
+
+	t := now.Add(-window + time.Duration(float64(window)*float64(g.seq)/float64(max(g.totalEvents, 1))))
+
+	if g.times.LatePct > 0 && g.times.MaxLate > 0 && g.rand.Intn(100) < g.times.LatePct {
+		t = t.Add(-time.Duration(1 + g.rand.Int63n(int64(g.times.MaxLate))))
+	}
+
+	return t
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTimeShape(t *testing.T) {
+	for _, shape := range []string{TimeExponential, TimeUniform, TimeOrdered} {
+		assert.NoError(t, CheckTimeShape(shape))
+	}
+
+	assert.Error(t, CheckTimeShape("normal"))
+}
+
+func TestTimeDistSpan(t *testing.T) {
+	day := 24 * time.Hour
+
+	assert.Equal(t, 90*day, TimeDist{}.Span())
+	assert.Equal(t, 7*day, TimeDist{Shape: TimeUniform, Days: 7}.Span())
+	assert.Equal(t, 7*day, TimeDist{Shape: TimeOrdered, Days: 7, MaxLate: time.Hour}.Span())
+	assert.Equal(t, 7*day+time.Hour, TimeDist{Shape: TimeOrdered, Days: 7, LatePct: 5, MaxLate: time.Hour}.Span())
+}
+
+// createdAts generates events of d and returns their created_at in the order
+// generated.
+func createdAts(t *testing.T, d TimeDist, now time.Time) []time.Time {
+	t.Helper()
+
+	var times []time.Time
+
+	for batch := range NewSeeded(5000, 500, 3, now).WithTimeDist(d).Generate() {
+		for _, e := range batch {
+			times = append(times, e.CreatedAt)
+		}
+	}
+
+	require.Len(t, times, 5000)
+
+	return times
+}
+
+func TestWithTimeDist(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, d := range []TimeDist{{Shape: TimeExponential, Days: 7}, {Shape: TimeUniform, Days: 7}} {
+		t.Run(d.Shape, func(t *testing.T) {
+			var recent int
+
+			for _, c := range createdAts(t, d, now) {
+				assert.False(t, c.After(now))
+				assert.True(t, c.After(now.Add(-d.Span())))
+
+				if c.After(now.Add(-d.Span() / 2)) {
+					recent++
+				}
+			}
+
+			assert.Greater(t, recent, 2000, "half the window should hold at least its share")
+		})
+	}
+
+	t.Run(TimeOrdered, func(t *testing.T) {
+		times := createdAts(t, TimeDist{Shape: TimeOrdered, Days: 7}, now)
+
+		assert.True(t, times[0].After(now.Add(-7*24*time.Hour)))
+		assert.Equal(t, now, times[len(times)-1])
+
+		for i := 1; i < len(times); i++ {
+			require.True(t, times[i].After(times[i-1]), "ordered events should ascend")
+		}
+	})
+}
+
+func TestWithTimeDistLate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	d := TimeDist{Shape: TimeOrdered, Days: 7, LatePct: 10, MaxLate: 6 * time.Hour}
+
+	var (
+		late   int
+		newest time.Time
+	)
+
+	for _, c := range createdAts(t, d, now) {
+		assert.False(t, c.After(now))
+		assert.True(t, c.After(now.Add(-d.Span())))
+
+		if c.Before(newest) {
+			late++
+		}
+
+		newest = maxTime(newest, c)
+	}
+
+	assert.InDelta(t, 500, late, 150, "about 10% of the events should arrive after newer ones")
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
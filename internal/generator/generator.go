@@ -1,9 +1,11 @@
 package generator
 
 import (
-	"fmt"
-	"math"
+	"context"
+	"iter"
 	"math/rand"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -13,6 +15,7 @@ type Event struct {
 	EventType string
 	Payload   string
 	CreatedAt time.Time
+	TenantID  int64 // 0 unless the generator has tenants
 }
 
 type Generator struct {
@@ -20,6 +23,47 @@ type Generator struct {
 	batchSize   int
 	current     int
 	rand        *rand.Rand
+	now         time.Time  // reference time of the timestamps, zero for the current time
+	buf         []byte     // formats the ID and payload of an event
+	zipf        *rand.Zipf // ranks of the users of a zipfian UserDist, nil for uniform
+	times       TimeDist
+	seq         int        // events generated, which places the next one of TimeOrdered
+	replay      *replay    // events handed out in place of generated ones, nil to generate them
+	err         error      // failure of replay, which ended the events early
+	idFormat    string     // format of the event IDs, "" for IDComposite
+	ids         int64      // time-ordered IDs of a seeded generator so far, which sets the time of the next
+	lastMs      int64      // millisecond of the last ULID or snowflake ID
+	lastSeq     int64      // sequence of the last snowflake ID within lastMs
+	lastRand    [2]uint64  // random bits of the last ULID, 16 and 64 of them
+	tenants     int        // tenants the events are spread over, 0 for none
+	tenantZipf  *rand.Zipf // ranks of the tenants of a zipfian distribution, nil for uniform
+}
+
+// bufSize is the initial capacity of the buffer formatting an event, enough
+// for the longest payload.
+const bufSize = 128
+
+// batchPool holds batches handed back by Release for the generators to fill
+// again, so a long run does not allocate a batch per BatchSize events.
+var batchPool sync.Pool
+
+// Release hands a batch from Generate back for reuse. The caller must be
+// done with it: the events of the batch are overwritten by a later batch.
+// Releasing a batch is optional.
+func Release(batch []Event) {
+	clear(batch)
+	batch = batch[:0]
+	batchPool.Put(&batch)
+}
+
+// newBatch returns a batch of size events, reusing a released one when it
+// is large enough.
+func newBatch(size int) []Event {
+	if b, ok := batchPool.Get().(*[]Event); ok && cap(*b) >= size {
+		return (*b)[:size]
+	}
+
+	return make([]Event, size)
 }
 
 var eventTypes = []string{
@@ -41,100 +85,224 @@ func New(totalEvents, batchSize int) *Generator {
 		batchSize:   batchSize,
 		current:     0,
 		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		buf:         make([]byte, 0, bufSize),
+	}
+}
+
+// NewSeeded is New with a fixed seed and reference time, so that every
+// generator built with the same arguments yields the same events.
+func NewSeeded(totalEvents, batchSize int, seed int64, now time.Time) *Generator {
+	return &Generator{
+		totalEvents: totalEvents,
+		batchSize:   batchSize,
+		rand:        rand.New(rand.NewSource(seed)),
+		now:         now,
+		buf:         make([]byte, 0, bufSize),
 	}
 }
 
 func (g *Generator) Generate() <-chan []Event {
+	return g.GenerateContext(context.Background())
+}
+
+// GenerateContext is Generate that stops early, closing the channel, once
+// ctx is done. The batches may come from Release.
+func (g *Generator) GenerateContext(ctx context.Context) <-chan []Event {
 	ch := make(chan []Event, 10)
 
-	go func() {
-		defer close(ch)
+	go g.generate(ctx, ch)
 
-		for g.current < g.totalEvents {
-			remaining := g.totalEvents - g.current
+	return ch
+}
 
-			size := g.batchSize
-			if remaining < size {
-				size = remaining
-			}
+// generate sends the batches of g on ch and closes it.
+func (g *Generator) generate(ctx context.Context, ch chan<- []Event) {
+	defer close(ch)
 
-			batch := make([]Event, size)
-			for i := 0; i < size; i++ {
-				batch[i] = g.generateEvent()
-			}
+	if g.replay != nil {
+		defer g.replay.close()
+	}
 
-			ch <- batch
+	for g.current < g.totalEvents && ctx.Err() == nil {
+		batch := g.nextBatch()
+		if len(batch) == 0 {
+			return // the replay failed
+		}
 
-			g.current += size
+		select {
+		case ch <- batch:
+		case <-ctx.Done():
+			return
 		}
-	}()
 
-	return ch
+		if g.err != nil {
+			return
+		}
+
+		g.current += len(batch)
+	}
 }
 
-func (g *Generator) generateEvent() Event {
-	// Generate realistic timestamps (last 90 days) with exponential bias toward recent data
-	const lambda = 0.05 // rate parameter — lower = more spread, higher = more recent
+// Err returns the error that ended the events of a dataset replay early,
+// once the channel of Generate is closed.
+func (g *Generator) Err() error {
+	return g.err
+}
 
-	daysAgo := int(-math.Log(1-g.rand.Float64()) / lambda)
-	if daysAgo > 89 {
-		daysAgo = 89
+// nextBatch returns the next batch of events, cut short when the replay
+// fails.
+func (g *Generator) nextBatch() []Event {
+	batch := newBatch(min(g.batchSize, g.totalEvents-g.current))
+
+	for i := range batch {
+		if g.replay == nil {
+			batch[i] = g.generateEvent()
+			continue
+		}
+
+		e, err := g.replay.next()
+		if err != nil {
+			g.err = err
+			return batch[:i]
+		}
+
+		batch[i] = e
 	}
 
-	hoursAgo := g.rand.Intn(24)
-	minutesAgo := g.rand.Intn(60)
-	secondsAgo := g.rand.Intn(60)
+	return batch
+}
 
-	createdAt := time.Now().
-		AddDate(0, 0, -daysAgo).
-		Add(-time.Duration(hoursAgo) * time.Hour).
-		Add(-time.Duration(minutesAgo) * time.Minute).
-		Add(-time.Duration(secondsAgo) * time.Second)
+func (g *Generator) generateEvent() Event {
+	createdAt := g.createdAt()
+	id := g.eventID(createdAt)
 
 	return Event{
-		ID:        fmt.Sprintf("evt_%d_%d", createdAt.UnixNano(), g.rand.Int63()),
-		UserID:    g.rand.Int63n(1000000), // 1M unique users
+		ID:        id,
+		UserID:    g.userID(),
 		EventType: eventTypes[g.rand.Intn(len(eventTypes))],
 		Payload:   g.generatePayload(),
 		CreatedAt: createdAt,
+		TenantID:  g.tenantID(),
 	}
 }
 
-func (g *Generator) generatePayload() string {
-	// Generate realistic JSON payload
-	templates := []string{
-		`{"page": "/home", "referrer": "google.com", "session_id": "%s"}`,
-		`{"button": "checkout", "product_id": %d, "price": %.2f}`,
-		`{"form": "contact", "fields": %d, "success": %t}`,
-		`{"endpoint": "/api/users", "method": "POST", "status": %d}`,
-		`{"error_code": "ERR_%d", "message": "Connection timeout", "retry": %d}`,
-	}
+// payloadTemplates is the number of payload shapes generatePayload picks from.
+const payloadTemplates = 5
 
-	idx := g.rand.Intn(len(templates))
-	template := templates[idx]
+// generatePayload builds a realistic JSON payload in the buffer of g, which
+// only the returned string allocates.
+func (g *Generator) generatePayload() string {
+	b := g.buf[:0]
 
-	switch idx {
+	switch g.rand.Intn(payloadTemplates) {
 	case 0:
-		return fmt.Sprintf(template, g.randomString(32))
+		b = g.appendPageViewPayload(b)
 	case 1:
-		return fmt.Sprintf(template, g.rand.Int63n(10000), g.rand.Float64()*1000)
+		b = g.appendClickPayload(b)
 	case 2:
-		return fmt.Sprintf(template, g.rand.Intn(20), g.rand.Intn(2) == 1)
+		b = g.appendFormPayload(b)
 	case 3:
-		return fmt.Sprintf(template, 200+g.rand.Intn(299))
+		b = g.appendAPICallPayload(b)
 	default:
-		return fmt.Sprintf(template, g.rand.Intn(9999), g.rand.Intn(5))
+		b = g.appendErrorPayload(b)
 	}
+
+	g.buf = b
+
+	return string(b)
+}
+
+func (g *Generator) appendPageViewPayload(b []byte) []byte {
+	b = append(b, `{"page": "/home", "referrer": "google.com", "session_id": "`...)
+	b = g.appendRandom(b, 32)
+
+	return append(b, `"}`...)
+}
+
+func (g *Generator) appendClickPayload(b []byte) []byte {
+	b = append(b, `{"button": "checkout", "product_id": `...)
+	b = strconv.AppendInt(b, g.rand.Int63n(10000), 10)
+	b = append(b, `, "price": `...)
+	b = strconv.AppendFloat(b, g.rand.Float64()*1000, 'f', 2, 64)
+
+	return append(b, '}')
+}
+
+func (g *Generator) appendFormPayload(b []byte) []byte {
+	b = append(b, `{"form": "contact", "fields": `...)
+	b = strconv.AppendInt(b, int64(g.rand.Intn(20)), 10)
+	b = append(b, `, "success": `...)
+	b = strconv.AppendBool(b, g.rand.Intn(2) == 1)
+
+	return append(b, '}')
+}
+
+func (g *Generator) appendAPICallPayload(b []byte) []byte {
+	b = append(b, `{"endpoint": "/api/users", "method": "POST", "status": `...)
+	b = strconv.AppendInt(b, int64(200+g.rand.Intn(299)), 10)
+
+	return append(b, '}')
+}
+
+func (g *Generator) appendErrorPayload(b []byte) []byte {
+	b = append(b, `{"error_code": "ERR_`...)
+	b = strconv.AppendInt(b, int64(g.rand.Intn(9999)), 10)
+	b = append(b, `", "message": "Connection timeout", "retry": `...)
+	b = strconv.AppendInt(b, int64(g.rand.Intn(5)), 10)
+
+	return append(b, '}')
 }
 
 func (g *Generator) randomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	return string(g.appendRandom(nil, length))
+}
 
-	b := make([]byte, length)
+// appendRandom appends length random alphanumeric characters to b.
+func (g *Generator) appendRandom(b []byte, length int) []byte {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
 	for i := 0; i < length; i++ {
-		b[i] = charset[g.rand.Intn(len(charset))]
+		b = append(b, charset[g.rand.Intn(len(charset))])
 	}
 
-	return string(b)
+	return b
+}
+
+// UserCount is the number of distinct user IDs, 0 to UserCount-1, that events
+// are generated for.
+const UserCount = 1000000
+
+var (
+	countries = []string{"US", "DE", "GB", "FR", "IN", "BR", "JP", "CA", "AU", "NL"}
+	plans     = []string{"free", "pro", "enterprise"}
+)
+
+// User is a row of the optional users dimension table.
+type User struct {
+	ID      int64
+	Country string
+	Plan    string
+}
+
+// GenerateUsers yields users 0 to count-1 in batches of batchSize. The
+// attributes come from a fixed seed, so every database gets the same table.
+func GenerateUsers(count, batchSize int) iter.Seq[[]User] {
+	return func(yield func([]User) bool) {
+		r := rand.New(rand.NewSource(1))
+
+		for start := 0; start < count; start += batchSize {
+			batch := make([]User, min(batchSize, count-start))
+			for i := range batch {
+				batch[i] = User{
+					ID:      int64(start + i),
+					Country: countries[r.Intn(len(countries))],
+					Plan:    plans[r.Intn(len(plans))],
+				}
+			}
+
+			if !yield(batch) {
+				return
+			}
+		}
+	}
 }
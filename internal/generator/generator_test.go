@@ -1,7 +1,10 @@
 package generator
 
 import (
+	"context"
 	"fmt"
+	"runtime"
+	"slices"
 	"testing"
 	"time"
 
@@ -40,6 +43,79 @@ func TestGenerator_Generate(t *testing.T) {
 	assert.Equal(t, totalEvents/batchSize, batchCount, "Should generate correct number of batches")
 }
 
+func TestGenerator_GenerateContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var batches int
+
+	for range New(1000000, 10).GenerateContext(ctx) {
+		if batches++; batches == 3 {
+			cancel()
+		}
+	}
+
+	assert.Less(t, batches, 100)
+}
+
+func TestNewSeeded(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	collect := func() []Event {
+		var events []Event
+		for batch := range NewSeeded(500, 64, 7, now).Generate() {
+			events = append(events, batch...)
+		}
+
+		return events
+	}
+
+	first := collect()
+	require.Len(t, first, 500)
+	assert.Equal(t, first, collect(), "same seed and reference time should give the same events")
+
+	for _, e := range first {
+		assert.False(t, e.CreatedAt.After(now))
+		assert.True(t, e.CreatedAt.After(now.AddDate(0, 0, -91)))
+	}
+}
+
+func TestRelease(t *testing.T) {
+	// With a single P the generator goroutine takes the batch the test
+	// releases from the same per-P slot of the pool.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	want := <-NewSeeded(64, 64, 7, now).Generate()
+
+	// The race detector drops some of the batches put in a sync.Pool, so
+	// reuse gets a few tries.
+	for range 10 {
+		batch := <-NewSeeded(64, 64, 7, now).Generate()
+		kept := slices.Clone(batch)
+		Release(batch)
+
+		next := <-NewSeeded(64, 64, 8, now).Generate()
+		if &next[0] != &batch[0] {
+			continue
+		}
+
+		assert.NotEqual(t, kept[0], batch[0], "the released batch should be refilled")
+		assert.Equal(t, want, kept, "events copied before Release should not change once the batch is reused")
+
+		return
+	}
+
+	t.Fatal("the next batch should reuse the backing array of the released one")
+}
+
+func TestGenerator_EventAllocations(t *testing.T) {
+	gen := New(1, 1)
+
+	// The ID and the payload are the only allocations of an event.
+	assert.LessOrEqual(t, testing.AllocsPerRun(1000, func() { _ = gen.generateEvent() }), 2.0)
+}
+
 func TestGenerator_EventTypes(t *testing.T) {
 	gen := New(1000, 100)
 	seenTypes := make(map[string]bool)
@@ -132,7 +208,7 @@ func BenchmarkGenerator_Generate(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		gen := New(1000, 100)
 		for batch := range gen.Generate() {
-			_ = batch
+			Release(batch)
 		}
 	}
 }
@@ -172,6 +248,32 @@ func TestGenerator_UserIDDistribution(t *testing.T) {
 	assert.LessOrEqual(t, len(userIDs), 1000000, "User IDs should be within range")
 }
 
+func TestGenerateUsers(t *testing.T) {
+	var (
+		users   []User
+		batches int
+	)
+
+	for batch := range GenerateUsers(25, 10) {
+		batches++
+		users = append(users, batch...)
+	}
+
+	assert.Equal(t, 3, batches)
+	require.Len(t, users, 25)
+
+	for i, u := range users {
+		assert.Equal(t, int64(i), u.ID)
+		assert.Contains(t, countries, u.Country)
+		assert.Contains(t, plans, u.Plan)
+	}
+
+	// The same seed gives every database the same table.
+	for batch := range GenerateUsers(10, 10) {
+		assert.Equal(t, users[:10], batch)
+	}
+}
+
 // Fuzz test for generator
 func FuzzGenerator(f *testing.F) {
 	f.Add(100, 10)
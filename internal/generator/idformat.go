@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Formats of the event IDs of WithIDFormat.
+const (
+	IDComposite = "composite" // evt_<created_at ns>_<random>, in created_at order
+	IDUUIDv4    = "uuidv4"    // random UUID, in no order
+	IDUUIDv7    = "uuidv7"    // UUID led by the generation time
+	IDULID      = "ulid"      // ULID led by the generation time
+	IDSnowflake = "snowflake" // int64 of the generation time, a node and a sequence
+)
+
+// Layout of a snowflake ID: 41 bits of milliseconds since snowflakeEpoch,
+// 10 of node and 12 of sequence within the millisecond.
+const (
+	snowflakeEpoch   = 1288834974657 // Twitter's, in Unix milliseconds
+	snowflakeNode    = 1
+	snowflakeSeqBits = 12
+	snowflakeSeqMax  = 1<<snowflakeSeqBits - 1
+)
+
+// crockford is the base32 alphabet of ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// CheckIDFormat checks the name of an ID format.
+func CheckIDFormat(format string) error {
+	switch format {
+	case IDComposite, IDUUIDv4, IDUUIDv7, IDULID, IDSnowflake:
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q, want %s, %s, %s, %s or %s",
+			format, IDComposite, IDUUIDv4, IDUUIDv7, IDULID, IDSnowflake)
+	}
+}
+
+// WithIDFormat makes g give its events IDs of format, IDComposite by
+// default, and returns g.
+func (g *Generator) WithIDFormat(format string) *Generator {
+	g.idFormat = format
+	return g
+}
+
+// eventID formats the ID of the next event, created at createdAt, in the
+// buffer of g.
+func (g *Generator) eventID(createdAt time.Time) string {
+	b := g.buf[:0]
+
+	switch g.idFormat {
+	case IDUUIDv4:
+		b = g.appendUUIDv4(b)
+	case IDUUIDv7:
+		b = g.appendUUIDv7(b, g.idTime())
+	case IDULID:
+		b = g.appendULID(b, g.idTime())
+	case IDSnowflake:
+		b = strconv.AppendInt(b, g.snowflake(g.idTime()), 10)
+	default:
+		b = g.appendCompositeID(b, createdAt)
+	}
+
+	g.buf = b
+
+	return string(b)
+}
+
+// appendCompositeID appends an evt_<created_at nanoseconds>_<random> ID.
+func (g *Generator) appendCompositeID(b []byte, createdAt time.Time) []byte {
+	b = append(b, "evt_"...)
+	b = strconv.AppendInt(b, createdAt.UnixNano(), 10)
+	b = append(b, '_')
+
+	return strconv.AppendInt(b, g.rand.Int63(), 10)
+}
+
+// idTime returns the time a time-ordered ID carries: the current time, or
+// for a seeded generator its reference time plus a microsecond per ID, so
+// its IDs are reproducible and still ascend.
+func (g *Generator) idTime() time.Time {
+	if g.now.IsZero() {
+		return time.Now()
+	}
+
+	g.ids++
+
+	return g.now.Add(time.Duration(g.ids) * time.Microsecond)
+}
+
+func (g *Generator) randomBytes(u *[16]byte) {
+	binary.BigEndian.PutUint64(u[:8], g.rand.Uint64())
+	binary.BigEndian.PutUint64(u[8:], g.rand.Uint64())
+}
+
+// appendUUIDv4 appends a random UUID (RFC 9562 version 4).
+func (g *Generator) appendUUIDv4(b []byte) []byte {
+	var u [16]byte
+
+	g.randomBytes(&u)
+	u[6] = u[6]&0x0f | 0x40
+
+	return appendUUID(b, &u)
+}
+
+// appendUUIDv7 appends a UUID of RFC 9562 version 7: the Unix milliseconds
+// of t, then its fraction of a millisecond in 12 bits, so the UUIDs of t in
+// ascending order sort ascending, then random bits.
+func (g *Generator) appendUUIDv7(b []byte, t time.Time) []byte {
+	var u [16]byte
+
+	g.randomBytes(&u)
+
+	ms := uint64(t.UnixMilli())
+	frac := uint64(t.Nanosecond()%int(time.Millisecond)) << 12 / uint64(time.Millisecond)
+
+	binary.BigEndian.PutUint64(u[:8], ms<<16|0x7000|frac)
+
+	return appendUUID(b, &u)
+}
+
+// appendUUID sets the variant of u and appends it in the 8-4-4-4-12 hex form.
+func appendUUID(b []byte, u *[16]byte) []byte {
+	u[8] = u[8]&0x3f | 0x80
+
+	b = hex.AppendEncode(b, u[0:4])
+	b = append(b, '-')
+	b = hex.AppendEncode(b, u[4:6])
+	b = append(b, '-')
+	b = hex.AppendEncode(b, u[6:8])
+	b = append(b, '-')
+	b = hex.AppendEncode(b, u[8:10])
+	b = append(b, '-')
+
+	return hex.AppendEncode(b, u[10:])
+}
+
+// appendULID appends a ULID: the Unix milliseconds of t in 48 bits and 80
+// random bits, as 26 characters of Crockford's base32. A ULID of the same
+// millisecond as the last one increments its random bits instead, as the
+// monotonic ULIDs of the spec do, so the ULIDs of g keep ascending.
+func (g *Generator) appendULID(b []byte, t time.Time) []byte {
+	ms := t.UnixMilli()
+
+	if ms > g.lastMs {
+		g.lastMs = ms
+		g.lastRand = [2]uint64{uint64(g.rand.Intn(1 << 16)), g.rand.Uint64()}
+	} else {
+		g.lastRand[1]++
+		if g.lastRand[1] == 0 {
+			g.lastRand[0] = (g.lastRand[0] + 1) & 0xffff
+		}
+	}
+
+	hi := uint64(g.lastMs)<<16 | g.lastRand[0]
+	lo := g.lastRand[1]
+
+	var s [26]byte
+	for i := len(s) - 1; i >= 0; i-- {
+		s[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return append(b, s[:]...)
+}
+
+// snowflake returns the snowflake ID of t. IDs of the same millisecond take
+// the next sequence number; once the sequence runs out, or when the clock
+// goes back, they borrow the millisecond after the last one, so the IDs of
+// g keep ascending.
+func (g *Generator) snowflake(t time.Time) int64 {
+	ms := t.UnixMilli() - snowflakeEpoch
+
+	switch {
+	case ms > g.lastMs:
+		g.lastMs, g.lastSeq = ms, 0
+	case g.lastSeq < snowflakeSeqMax:
+		g.lastSeq++
+	default:
+		g.lastMs, g.lastSeq = g.lastMs+1, 0
+	}
+
+	return g.lastMs<<22 | snowflakeNode<<snowflakeSeqBits | g.lastSeq
+}
@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckIDFormat(t *testing.T) {
+	for _, format := range []string{IDComposite, IDUUIDv4, IDUUIDv7, IDULID, IDSnowflake} {
+		assert.NoError(t, CheckIDFormat(format), format)
+	}
+
+	for _, format := range []string{"", "uuid", "UUIDv7"} {
+		assert.Error(t, CheckIDFormat(format), format)
+	}
+}
+
+func generateIDs(g *Generator) []string {
+	var ids []string
+
+	for batch := range g.Generate() {
+		for _, e := range batch {
+			ids = append(ids, e.ID)
+		}
+	}
+
+	return ids
+}
+
+func TestWithIDFormat(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		format  string
+		pattern string
+		ordered bool
+	}{
+		{IDComposite, `^evt_\d+_\d+$`, false},
+		{IDUUIDv4, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, false},
+		{IDUUIDv7, `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, true},
+		{IDULID, `^[0-9A-HJKMNP-TV-Z]{26}$`, true},
+		{IDSnowflake, `^\d{19}$`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			ids := generateIDs(NewSeeded(5000, 100, 1, now).WithIDFormat(tt.format))
+			require.Len(t, ids, 5000)
+
+			seen := make(map[string]bool)
+
+			for i, id := range ids {
+				assert.Regexp(t, regexp.MustCompile(tt.pattern), id)
+				assert.False(t, seen[id], "duplicate ID %s", id)
+				seen[id] = true
+
+				if tt.ordered && i > 0 {
+					assert.Less(t, ids[i-1], id, "IDs must ascend")
+				}
+			}
+
+			assert.Equal(t, ids, generateIDs(NewSeeded(5000, 100, 1, now).WithIDFormat(tt.format)),
+				"a seeded generator must give the same IDs")
+		})
+	}
+}
+
+func TestWithIDFormatWallClock(t *testing.T) {
+	for _, format := range []string{IDUUIDv7, IDULID, IDSnowflake} {
+		ids := generateIDs(New(20000, 1000).WithIDFormat(format))
+
+		for i := 1; i < len(ids); i++ {
+			if format == IDSnowflake {
+				require.Less(t, ids[i-1], ids[i], format)
+			} else {
+				// Random bits order the IDs of the same clock tick.
+				require.LessOrEqual(t, ids[i-1][:8], ids[i][:8], format)
+			}
+		}
+	}
+}
+
+func TestIDTimestamps(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 500_000_000, time.UTC)
+	g := NewSeeded(1, 1, 1, time.Now())
+
+	uuid := string(g.appendUUIDv7(nil, at))
+	ms, err := strconv.ParseInt(strings.ReplaceAll(uuid[:13], "-", ""), 16, 64)
+	require.NoError(t, err)
+	assert.Equal(t, at.UnixMilli(), ms)
+
+	ulid := string(g.appendULID(nil, at))
+	ms = 0
+
+	for _, c := range ulid[:10] {
+		ms = ms<<5 | int64(strings.IndexRune(crockford, c))
+	}
+
+	assert.Equal(t, at.UnixMilli(), ms)
+
+	id := NewSeeded(1, 1, 1, time.Now()).snowflake(at)
+	assert.Equal(t, at.UnixMilli()-snowflakeEpoch, id>>22)
+	assert.Equal(t, int64(snowflakeNode), id>>snowflakeSeqBits&1023)
+}
+
+func TestSnowflakeSequence(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	g := NewSeeded(1, 1, 1, at)
+
+	last := g.snowflake(at)
+
+	// More IDs than a millisecond has sequence numbers, then a clock that
+	// went back: each borrows a later millisecond rather than repeat one.
+	for range snowflakeSeqMax + 10 {
+		id := g.snowflake(at)
+		require.Greater(t, id, last)
+		last = id
+	}
+
+	assert.Equal(t, at.UnixMilli()-snowflakeEpoch+1, last>>22)
+	assert.Greater(t, g.snowflake(at.Add(-time.Second)), last)
+}
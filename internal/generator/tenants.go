@@ -0,0 +1,27 @@
+package generator
+
+import "math/rand"
+
+// WithTenants spreads the events of g over count tenants, 0 to count-1, as
+// d does; under a zipfian d tenant 0 is the busiest. Without tenants every
+// event has tenant 0. It returns g.
+func (g *Generator) WithTenants(count int, d UserDist) *Generator {
+	g.tenants, g.tenantZipf = count, nil
+	if count > 1 && d.Skew > 0 {
+		g.tenantZipf = rand.NewZipf(g.rand, d.Skew, 1, uint64(count-1))
+	}
+
+	return g
+}
+
+// tenantID picks the tenant of an event.
+func (g *Generator) tenantID() int64 {
+	switch {
+	case g.tenants <= 1:
+		return 0
+	case g.tenantZipf != nil:
+		return int64(g.tenantZipf.Uint64())
+	default:
+		return g.rand.Int63n(int64(g.tenants))
+	}
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tenantCounts(g *Generator) map[int64]int {
+	counts := make(map[int64]int)
+
+	for batch := range g.Generate() {
+		for _, e := range batch {
+			counts[e.TenantID]++
+		}
+	}
+
+	return counts
+}
+
+func TestWithTenants(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, map[int64]int{0: 1000}, tenantCounts(NewSeeded(1000, 100, 1, now)), "no tenants is tenant 0")
+
+	uniform := tenantCounts(NewSeeded(10000, 100, 1, now).WithTenants(10, UserDist{}))
+	assert.Len(t, uniform, 10)
+
+	for tenant, n := range uniform {
+		assert.Less(t, tenant, int64(10))
+		assert.InDelta(t, 1000, n, 200)
+	}
+
+	zipf := tenantCounts(NewSeeded(10000, 100, 1, now).WithTenants(100, UserDist{Skew: 1.5}))
+	assert.Greater(t, zipf[0], 3000, "tenant 0 is the busiest under a zipfian distribution")
+
+	for tenant := range zipf {
+		assert.Less(t, tenant, int64(100))
+	}
+}
+
+func TestWithTenantsKeepsEvents(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var plain, tenants []Event
+	for batch := range NewSeeded(100, 100, 1, now).Generate() {
+		plain = append(plain, batch...)
+	}
+
+	for batch := range NewSeeded(100, 100, 1, now).WithTenants(1, UserDist{}).Generate() {
+		tenants = append(tenants, batch...)
+	}
+
+	assert.Equal(t, plain, tenants, "a single tenant draws nothing from the seed")
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Names of the user distributions of ParseUserDist.
+const (
+	DistUniform = "uniform"
+	DistZipfian = "zipfian"
+)
+
+// defaultSkew is the skew of a zipfian distribution given without one.
+const defaultSkew = 1.1
+
+// userStride spreads the ranks of a zipfian distribution over the user IDs,
+// so the hot users are not neighbours in an index or a key range. It is
+// coprime with UserCount, which makes rank*userStride mod UserCount a
+// permutation.
+const userStride = 7919
+
+// UserDist is the distribution of the user IDs of generated events.
+type UserDist struct {
+	// Skew is the exponent s of a zipfian distribution, where the user of
+	// rank k gets events in proportion to 1/(k+1)^s. Zero is uniform.
+	Skew float64
+}
+
+// ParseUserDist parses a user distribution: uniform, or zipfian:S with a
+// skew S above 1; zipfian alone has a skew of 1.1.
+func ParseUserDist(s string) (UserDist, error) {
+	name, param, hasParam := strings.Cut(s, ":")
+
+	switch name {
+	case "", DistUniform:
+		if hasParam {
+			return UserDist{}, fmt.Errorf("%s takes no parameter", DistUniform)
+		}
+
+		return UserDist{}, nil
+	case DistZipfian:
+		if !hasParam {
+			return UserDist{Skew: defaultSkew}, nil
+		}
+
+		skew, err := strconv.ParseFloat(param, 64)
+		if err != nil || skew <= 1 {
+			return UserDist{}, fmt.Errorf("the skew of %s must be a number above 1, got %q", DistZipfian, param)
+		}
+
+		return UserDist{Skew: skew}, nil
+	default:
+		return UserDist{}, fmt.Errorf("unknown distribution %q, want %s or %s:S", name, DistUniform, DistZipfian)
+	}
+}
+
+func (d UserDist) String() string {
+	if d.Skew == 0 {
+		return DistUniform
+	}
+
+	return DistZipfian + ":" + strconv.FormatFloat(d.Skew, 'g', -1, 64)
+}
+
+// WithUserDist makes g pick the users of its events from d, uniform by
+// default, and returns g.
+func (g *Generator) WithUserDist(d UserDist) *Generator {
+	g.zipf = nil
+	if d.Skew > 0 {
+		g.zipf = rand.NewZipf(g.rand, d.Skew, 1, UserCount-1)
+	}
+
+	return g
+}
+
+// userID picks the user of an event.
+func (g *Generator) userID() int64 {
+	if g.zipf == nil {
+		return g.rand.Int63n(UserCount)
+	}
+
+	return int64(g.zipf.Uint64() * userStride % UserCount)
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserDist(t *testing.T) {
+	tests := []struct {
+		in   string
+		want UserDist
+		str  string
+	}{
+		{"", UserDist{}, "uniform"},
+		{"uniform", UserDist{}, "uniform"},
+		{"zipfian", UserDist{Skew: 1.1}, "zipfian:1.1"},
+		{"zipfian:1.5", UserDist{Skew: 1.5}, "zipfian:1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			d, err := ParseUserDist(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, d)
+			assert.Equal(t, tt.str, d.String())
+		})
+	}
+
+	for _, in := range []string{"zipfian:1", "zipfian:0.8", "zipfian:x", "uniform:2", "normal"} {
+		_, err := ParseUserDist(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestWithUserDist(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	counts := func(d UserDist) map[int64]int {
+		users := make(map[int64]int)
+
+		for batch := range NewSeeded(20000, 1000, 7, now).WithUserDist(d).Generate() {
+			for _, e := range batch {
+				require.GreaterOrEqual(t, e.UserID, int64(0))
+				require.Less(t, e.UserID, int64(UserCount))
+				users[e.UserID]++
+			}
+		}
+
+		return users
+	}
+
+	top := func(users map[int64]int) int {
+		var n int
+		for _, c := range users {
+			n = max(n, c)
+		}
+
+		return n
+	}
+
+	uniform := counts(UserDist{})
+	zipf := counts(UserDist{Skew: 1.1})
+
+	assert.Less(t, top(uniform), 10)
+	assert.Greater(t, top(zipf), 1000, "the hottest user should get a large share of the events")
+	assert.Less(t, len(zipf), len(uniform)/2)
+	assert.Equal(t, zipf, counts(UserDist{Skew: 1.1}), "a seeded generator should pick the same users")
+}
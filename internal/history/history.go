@@ -0,0 +1,362 @@
+// Package history keeps the results of past benchmark runs in a local SQLite
+// database, so runs can be listed, compared and followed over time.
+package history
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// ErrRunNotFound is returned for a run ID the store does not have.
+var ErrRunNotFound = errors.New("run not found")
+
+// schema creates the tables on first use. A run is one invocation of the
+// benchmark; its results are stored per database, or per cell of a parameter
+// matrix, as the JSON the -output json format prints.
+const schema = `
+	CREATE TABLE IF NOT EXISTS runs (
+		id INTEGER PRIMARY KEY,
+		started_at INTEGER NOT NULL,
+		finished_at INTEGER NOT NULL,
+		args TEXT NOT NULL,
+		host TEXT NOT NULL,
+		platform TEXT NOT NULL,
+		cpus INTEGER NOT NULL,
+		go_version TEXT NOT NULL,
+		revision TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS results (
+		run_id INTEGER NOT NULL REFERENCES runs (id),
+		name TEXT NOT NULL,
+		results TEXT NOT NULL,
+		PRIMARY KEY (run_id, name)
+	);
+`
+
+// Run is the metadata of one stored run.
+type Run struct {
+	ID         int64     `json:"id"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Args       []string  `json:"args"` // command line flags
+	Host       string    `json:"host"`
+	Platform   string    `json:"platform"` // GOOS/GOARCH
+	CPUs       int       `json:"cpus"`
+	GoVersion  string    `json:"go_version"`
+	Revision   string    `json:"revision,omitempty"` // VCS revision the binary was built from
+	Names      []string  `json:"names"`              // databases or matrix cells of the run
+}
+
+// NewRun describes a run of this process started at started with args,
+// finishing now.
+func NewRun(started time.Time, args []string) Run {
+	host, _ := os.Hostname()
+
+	return Run{
+		StartedAt:  started,
+		FinishedAt: time.Now(),
+		Args:       args,
+		Host:       host,
+		Platform:   runtime.GOOS + "/" + runtime.GOARCH,
+		CPUs:       runtime.NumCPU(),
+		GoVersion:  runtime.Version(),
+		Revision:   benchmark.Revision(),
+	}
+}
+
+// Store is a history database.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the history database at path, creating it and its directory
+// when they do not exist yet. A leading ~ stands for the home directory.
+func Open(ctx context.Context, path string) (*Store, error) {
+	path, err := ExpandHome(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("failed to create history schema: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// ExpandHome replaces a leading ~ of path with the home directory.
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Append stores run with its results in one transaction and returns the ID
+// the run was given.
+func (s *Store) Append(ctx context.Context, run Run, results map[string]*benchmark.Results) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin history transaction: %w", err)
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	id, err := insertRun(ctx, tx, run)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertResults(ctx, tx, id, results); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit history: %w", err)
+	}
+
+	return id, nil
+}
+
+// insertRun inserts run and returns the ID it was given.
+func insertRun(ctx context.Context, tx *sql.Tx, run Run) (int64, error) {
+	args, err := json.Marshal(run.Args)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode run args: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx,
+		"INSERT INTO runs (started_at, finished_at, args, host, platform, cpus, go_version, revision) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		run.StartedAt.UnixNano(), run.FinishedAt.UnixNano(), string(args), run.Host, run.Platform, run.CPUs, run.GoVersion, run.Revision)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert run: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get run id: %w", err)
+	}
+
+	return id, nil
+}
+
+// insertResults inserts the results of the run with the given ID.
+func insertResults(ctx context.Context, tx *sql.Tx, runID int64, results map[string]*benchmark.Results) error {
+	for name, r := range results {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s results: %w", name, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, "INSERT INTO results (run_id, name, results) VALUES (?, ?, ?)", runID, name, string(data)); err != nil {
+			return fmt.Errorf("failed to insert %s results: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Runs returns the limit most recent runs, newest first.
+func (s *Store) Runs(ctx context.Context, limit int) ([]Run, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT r.id, r.started_at, r.finished_at, r.args, r.host, r.platform, r.cpus, r.go_version, r.revision,
+			COALESCE((SELECT group_concat(name, ',') FROM (SELECT name FROM results WHERE run_id = r.id ORDER BY name)), '')
+		FROM runs r
+		ORDER BY r.id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []Run
+
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+func scanRun(rows *sql.Rows) (Run, error) {
+	var (
+		run               Run
+		started, finished int64
+		args, names       string
+	)
+
+	if err := rows.Scan(&run.ID, &started, &finished, &args, &run.Host, &run.Platform, &run.CPUs, &run.GoVersion, &run.Revision, &names); err != nil {
+		return Run{}, fmt.Errorf("failed to scan run: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(args), &run.Args); err != nil {
+		return Run{}, fmt.Errorf("failed to decode args of run %d: %w", run.ID, err)
+	}
+
+	run.StartedAt, run.FinishedAt = time.Unix(0, started), time.Unix(0, finished)
+
+	if names != "" {
+		run.Names = strings.Split(names, ",")
+	}
+
+	return run, nil
+}
+
+// Results returns the results of the run with the given ID, keyed by
+// database or matrix cell.
+func (s *Store) Results(ctx context.Context, runID int64) (map[string]*benchmark.Results, error) {
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM runs WHERE id = ?)", runID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to look up run %d: %w", runID, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("run %d: %w", runID, ErrRunNotFound)
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT name, results FROM results WHERE run_id = ?", runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query results of run %d: %w", runID, err)
+	}
+	defer rows.Close()
+
+	return scanResults(rows, runID)
+}
+
+// scanResults decodes the results rows of the run with the given ID.
+func scanResults(rows *sql.Rows, runID int64) (map[string]*benchmark.Results, error) {
+	results := make(map[string]*benchmark.Results)
+
+	for rows.Next() {
+		var name, data string
+		if err := rows.Scan(&name, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan results: %w", err)
+		}
+
+		var r benchmark.Results
+		if err := json.Unmarshal([]byte(data), &r); err != nil {
+			return nil, fmt.Errorf("failed to decode %s results of run %d: %w", name, runID, err)
+		}
+
+		results[name] = &r
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read results of run %d: %w", runID, err)
+	}
+
+	return results, nil
+}
+
+// Point is the value of a metric in one run.
+type Point struct {
+	RunID     int64     `json:"run_id"`
+	StartedAt time.Time `json:"started_at"`
+	Value     float64   `json:"value"`
+}
+
+// Trend returns the value of the named metric of the results stored under
+// name in the limit most recent runs that have it, oldest first. Metrics are
+// those of a baseline comparison, e.g. "insert throughput" or "1_day P95".
+func (s *Store) Trend(ctx context.Context, name, metric string, limit int) ([]Point, string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT r.id, r.started_at, res.results
+		FROM results res JOIN runs r ON r.id = res.run_id
+		WHERE res.name = ?
+		ORDER BY r.id DESC`, name)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query %s results: %w", name, err)
+	}
+	defer rows.Close()
+
+	var (
+		points []Point
+		kind   string
+	)
+
+	for rows.Next() && len(points) < limit {
+		p, k, ok, err := scanPoint(rows, metric)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if ok {
+			points, kind = append(points, p), k
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to read %s results: %w", name, err)
+	}
+
+	slices.Reverse(points)
+
+	return points, kind, nil
+}
+
+// scanPoint reads the metric out of a result row of Trend.
+func scanPoint(rows *sql.Rows, metric string) (Point, string, bool, error) {
+	var (
+		p       Point
+		started int64
+		data    string
+	)
+
+	if err := rows.Scan(&p.RunID, &started, &data); err != nil {
+		return Point{}, "", false, fmt.Errorf("failed to scan results: %w", err)
+	}
+
+	var r benchmark.Results
+	if err := json.Unmarshal([]byte(data), &r); err != nil {
+		return Point{}, "", false, fmt.Errorf("failed to decode results of run %d: %w", p.RunID, err)
+	}
+
+	value, kind, ok := r.Metric(metric)
+	p.StartedAt, p.Value = time.Unix(0, started), value
+
+	return p, kind, ok, nil
+}
@@ -0,0 +1,102 @@
+package history
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := Open(context.Background(), filepath.Join(t.TempDir(), "nested", "history.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
+
+func results(throughput float64, p95 time.Duration) map[string]*benchmark.Results {
+	return map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Insert:   &benchmark.InsertResult{TotalEvents: 1000, Throughput: throughput},
+			Queries:  map[string]*benchmark.QueryResult{"1_day": {Iterations: 5, P95Duration: p95}},
+		},
+		"redis": {Database: "redis", ErrorText: "connection refused"},
+	}
+}
+
+func TestStoreAppendAndRuns(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	started := time.Now().Add(-time.Minute)
+
+	first, err := store.Append(ctx, NewRun(started, []string{"-db", "postgres,redis"}), results(1000, time.Millisecond))
+	require.NoError(t, err)
+
+	second, err := store.Append(ctx, NewRun(started, nil), results(900, time.Millisecond))
+	require.NoError(t, err)
+	assert.Greater(t, second, first)
+
+	runs, err := store.Runs(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+
+	assert.Equal(t, second, runs[0].ID, "newest first")
+	assert.Equal(t, []string{"-db", "postgres,redis"}, runs[1].Args)
+	assert.Equal(t, []string{"postgres", "redis"}, runs[1].Names)
+	assert.Equal(t, started.UnixNano(), runs[1].StartedAt.UnixNano())
+	assert.NotEmpty(t, runs[1].Platform)
+	assert.Positive(t, runs[1].CPUs)
+
+	runs, err = store.Runs(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+}
+
+func TestStoreResults(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	id, err := store.Append(ctx, NewRun(time.Now(), nil), results(1000, 3*time.Millisecond))
+	require.NoError(t, err)
+
+	got, err := store.Results(ctx, id)
+	require.NoError(t, err)
+	require.Contains(t, got, "postgres")
+
+	assert.InDelta(t, 1000, got["postgres"].Insert.Throughput, 0)
+	assert.Equal(t, 3*time.Millisecond, got["postgres"].Queries["1_day"].P95Duration)
+	assert.Equal(t, "connection refused", got["redis"].ErrorText)
+
+	_, err = store.Results(ctx, id+1)
+	require.ErrorIs(t, err, ErrRunNotFound)
+}
+
+func TestStoreTrend(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	for _, throughput := range []float64{1000, 1100, 1200} {
+		_, err := store.Append(ctx, NewRun(time.Now(), nil), results(throughput, time.Millisecond))
+		require.NoError(t, err)
+	}
+
+	points, kind, err := store.Trend(ctx, "postgres", "insert throughput", 2)
+	require.NoError(t, err)
+	assert.Equal(t, benchmark.KindThroughput, kind)
+	require.Len(t, points, 2)
+	assert.InDelta(t, 1100, points[0].Value, 0, "oldest of the last two first")
+	assert.InDelta(t, 1200, points[1].Value, 0)
+
+	points, _, err = store.Trend(ctx, "redis", "insert throughput", 10)
+	require.NoError(t, err)
+	assert.Empty(t, points)
+}
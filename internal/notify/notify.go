@@ -0,0 +1,266 @@
+// Package notify posts a summary of a finished benchmark run to a webhook,
+// such as a Slack incoming webhook, so long unattended runs report back.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// timeout limits the notification request.
+const timeout = 30 * time.Second
+
+// Status is the outcome of a run.
+type Status string
+
+const (
+	StatusCompleted   Status = "completed"
+	StatusRegressed   Status = "regressed"   // completed with regressions against the baseline
+	StatusInterrupted Status = "interrupted" // cancelled, the results are partial
+	StatusFailed      Status = "failed"      // no database completed its benchmark
+)
+
+// Message is the notification of a run. Generic webhooks receive all of it
+// as JSON; Slack receives Text alone.
+type Message struct {
+	RunID       string       `json:"run_id,omitempty"`
+	Status      Status       `json:"status"`
+	Text        string       `json:"text"`
+	Databases   []Database   `json:"databases,omitempty"`
+	Regressions []Regression `json:"regressions,omitempty"`
+}
+
+// Database is the outcome of one database, or matrix cell, of a run.
+type Database struct {
+	Name       string  `json:"name"`
+	Throughput float64 `json:"throughput,omitempty"` // insert events/sec
+	Error      string  `json:"error,omitempty"`
+}
+
+// Regression is a metric of a database that regressed against the baseline.
+type Regression struct {
+	Database  string  `json:"database"`
+	Metric    string  `json:"metric"`
+	ChangePct float64 `json:"change_pct"`
+	Threshold float64 `json:"threshold"`
+}
+
+// NewMessage summarizes the results of a run.
+func NewMessage(results map[string]*benchmark.Results) Message {
+	msg := Message{RunID: runID(results)}
+
+	failed := 0
+	interrupted := false
+
+	for _, name := range slices.Sorted(maps.Keys(results)) {
+		res := results[name]
+
+		db := Database{Name: name, Error: errorText(res)}
+		if db.Error != "" {
+			failed++
+		} else if res.Insert != nil {
+			db.Throughput = res.Insert.Throughput
+		}
+
+		msg.Databases = append(msg.Databases, db)
+		interrupted = interrupted || res.Interrupted
+
+		for _, d := range res.Regressions() {
+			msg.Regressions = append(msg.Regressions, Regression{name, d.Metric, d.ChangePct, d.Threshold})
+		}
+	}
+
+	msg.Status = status(failed == len(results), interrupted, len(msg.Regressions) > 0)
+	msg.Text = text(msg, duration(results))
+
+	return msg
+}
+
+// status returns the outcome of a run from whether every database failed,
+// the run was interrupted or it regressed, in that order of precedence.
+func status(failed, interrupted, regressed bool) Status {
+	switch {
+	case failed:
+		return StatusFailed
+	case interrupted:
+		return StatusInterrupted
+	case regressed:
+		return StatusRegressed
+	default:
+		return StatusCompleted
+	}
+}
+
+// runID returns the ID of the run, stamped on the results, or "" when they
+// were not stamped.
+func runID(results map[string]*benchmark.Results) string {
+	for _, name := range slices.Sorted(maps.Keys(results)) {
+		if run := results[name].Run; run != nil {
+			return run.ID
+		}
+	}
+
+	return ""
+}
+
+// NewFailure is the notification of a run that stopped before it had any
+// results.
+func NewFailure(runID string, err error) Message {
+	msg := Message{RunID: runID, Status: StatusFailed}
+	msg.Text = fmt.Sprintf("%s failed: %v", title(runID), err)
+
+	return msg
+}
+
+func errorText(res *benchmark.Results) string {
+	if res.ErrorText != "" {
+		return res.ErrorText
+	}
+
+	if res.Error != nil {
+		return res.Error.Error()
+	}
+
+	return ""
+}
+
+// duration returns how long the run took, or 0 when it was not stamped.
+func duration(results map[string]*benchmark.Results) time.Duration {
+	for _, res := range results {
+		if res.Run != nil {
+			return res.Run.FinishedAt.Sub(res.Run.StartedAt).Round(time.Second)
+		}
+	}
+
+	return 0
+}
+
+func title(runID string) string {
+	if runID == "" {
+		return "Benchmark run"
+	}
+
+	return "Benchmark run " + runID
+}
+
+// text renders msg as Slack mrkdwn, which reads as plain text elsewhere.
+func text(msg Message, took time.Duration) string {
+	var b strings.Builder
+
+	b.WriteString(title(msg.RunID) + " " + string(msg.Status))
+
+	if took > 0 {
+		fmt.Fprintf(&b, " in %s", took)
+	}
+
+	for _, db := range msg.Databases {
+		switch {
+		case db.Error != "":
+			fmt.Fprintf(&b, "\n• %s: failed: %s", db.Name, db.Error)
+		case db.Throughput > 0:
+			fmt.Fprintf(&b, "\n• %s: %.0f events/sec", db.Name, db.Throughput)
+		default:
+			fmt.Fprintf(&b, "\n• %s: done", db.Name)
+		}
+	}
+
+	writeRegressions(&b, msg.Regressions)
+
+	return b.String()
+}
+
+// writeRegressions lists the regressions, if any, under a heading.
+func writeRegressions(b *strings.Builder, regressions []Regression) {
+	if len(regressions) == 0 {
+		return
+	}
+
+	b.WriteString("\n*Regressions*")
+
+	for _, r := range regressions {
+		fmt.Fprintf(b, "\n• %s %s: %+.1f%% (threshold %g%%)", r.Database, r.Metric, r.ChangePct, r.Threshold)
+	}
+}
+
+// Send posts msg to the webhook at rawURL: its text alone to a Slack
+// incoming webhook, the whole message as JSON to any other.
+func Send(ctx context.Context, rawURL string, msg Message) error {
+	var payload any = msg
+	if isSlack(rawURL) {
+		payload = struct {
+			Text string `json:"text"`
+		}{msg.Text}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return post(ctx, rawURL, body)
+}
+
+// post posts the JSON body to the webhook at rawURL.
+func post(ctx context.Context, rawURL string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to notify %s: %w", redact(rawURL), unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		reply, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notification to %s failed: %s: %s", redact(rawURL), resp.Status, strings.TrimSpace(string(reply)))
+	}
+
+	return nil
+}
+
+// unwrapURLError returns the cause of a *url.Error, which quotes the whole
+// URL, and any other error as is.
+func unwrapURLError(err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return ue.Err
+	}
+
+	return err
+}
+
+func isSlack(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Hostname() == "hooks.slack.com"
+}
+
+// redact returns the scheme and host of a webhook URL: its path is the
+// secret of a Slack webhook, and logs should not carry it.
+func redact(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "the webhook"
+	}
+
+	return u.Scheme + "://" + u.Host
+}
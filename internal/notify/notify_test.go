@@ -0,0 +1,103 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResults() map[string]*benchmark.Results {
+	started := time.Date(2026, 10, 16, 21, 5, 58, 0, time.UTC)
+	run := &benchmark.RunInfo{ID: "20261016-210558-3f9a2c", StartedAt: started, FinishedAt: started.Add(3*time.Minute + 14*time.Second)}
+
+	return map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Run:      run,
+			Insert:   &benchmark.InsertResult{Throughput: 1234.4},
+			Baseline: []benchmark.BaselineDelta{
+				{Metric: "insert throughput", ChangePct: -12.5, Threshold: 10, Regressed: true},
+				{Metric: "1_day P95", ChangePct: 3, Threshold: 10},
+			},
+		},
+		"redis": {Database: "redis", Run: run, Error: errors.New("connection refused")},
+	}
+}
+
+func TestNewMessage(t *testing.T) {
+	msg := NewMessage(testResults())
+
+	assert.Equal(t, "20261016-210558-3f9a2c", msg.RunID)
+	assert.Equal(t, StatusRegressed, msg.Status)
+	assert.Equal(t, []Regression{{"postgres", "insert throughput", -12.5, 10}}, msg.Regressions)
+	assert.Equal(t, "Benchmark run 20261016-210558-3f9a2c regressed in 3m14s\n"+
+		"• postgres: 1234 events/sec\n"+
+		"• redis: failed: connection refused\n"+
+		"*Regressions*\n"+
+		"• postgres insert throughput: -12.5% (threshold 10%)", msg.Text)
+}
+
+func TestNewMessageStatus(t *testing.T) {
+	results := testResults()
+	results["postgres"].Baseline = nil
+	assert.Equal(t, StatusCompleted, NewMessage(results).Status)
+
+	results["postgres"].Interrupted = true
+	assert.Equal(t, StatusInterrupted, NewMessage(results).Status)
+
+	results["postgres"].Error = errors.New("schema")
+	assert.Equal(t, StatusFailed, NewMessage(results).Status, "no database completed")
+
+	assert.Equal(t, StatusFailed, NewMessage(nil).Status)
+}
+
+func TestNewFailure(t *testing.T) {
+	msg := NewFailure("20261016-210558-3f9a2c", errors.New("Failed to load config: bad port"))
+
+	assert.Equal(t, StatusFailed, msg.Status)
+	assert.Equal(t, "Benchmark run 20261016-210558-3f9a2c failed: Failed to load config: bad port", msg.Text)
+}
+
+func TestSend(t *testing.T) {
+	var body map[string]any
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	}))
+	defer srv.Close()
+
+	require.NoError(t, Send(context.Background(), srv.URL+"/hook", NewMessage(testResults())))
+
+	assert.Equal(t, "regressed", body["status"])
+	assert.Equal(t, "20261016-210558-3f9a2c", body["run_id"])
+	assert.Len(t, body["databases"], 2)
+	assert.Len(t, body["regressions"], 1)
+}
+
+func TestSendError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	err := Send(context.Background(), srv.URL+"/services/T000/B000/secret", NewFailure("", errors.New("boom")))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403 Forbidden: invalid_token")
+	assert.NotContains(t, err.Error(), "secret", "the webhook path stays out of the logs")
+}
+
+func TestIsSlack(t *testing.T) {
+	assert.True(t, isSlack("https://hooks.slack.com/services/T000/B000/XXX"))
+	assert.False(t, isSlack("https://example.com/hooks.slack.com"))
+}
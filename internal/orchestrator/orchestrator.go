@@ -35,36 +35,51 @@ func logErrf(format string, args ...any) {
 // DBService describes how to start and health check a database container.
 type DBService struct {
 	Name       string
-	Service    string   // docker-compose service name
+	Service    string   // docker-compose service name, empty for embedded databases
 	ReadyCheck []string // command to verify readiness (passed to docker exec)
 }
 
+// Embedded reports whether the database runs in-process and needs no container.
+func (s DBService) Embedded() bool {
+	return s.Service == ""
+}
+
 // DefaultServices returns the standard list of databases in benchmark order.
 func DefaultServices() []DBService {
 	return []DBService{
-		{
-			Name:       "postgres",
-			Service:    "postgres",
-			ReadyCheck: []string{"docker", "exec", "benchmark-postgres", "pg_isready", "-U", "benchmark"},
-		},
-		{
-			Name:       "mongodb",
-			Service:    "mongodb",
-			ReadyCheck: []string{"docker", "exec", "benchmark-mongodb", "mongosh", "--quiet", "--eval", "db.adminCommand('ping').ok"},
-		},
-		{
-			Name:       "clickhouse",
-			Service:    "clickhouse",
-			ReadyCheck: []string{"docker", "exec", "benchmark-clickhouse", "clickhouse-client", "--query", "SELECT 1"},
-		},
-		{
-			Name:       "cassandra",
-			Service:    "cassandra",
-			ReadyCheck: []string{"docker", "exec", "benchmark-cassandra", "cqlsh", "-e", "DESCRIBE KEYSPACES"},
-		},
+		containerService("postgres", "docker", "exec", "benchmark-postgres", "pg_isready", "-U", "benchmark"),
+		containerService("mongodb", "docker", "exec", "benchmark-mongodb",
+			"mongosh", "--quiet", "--eval", "db.adminCommand('ping').ok"),
+		containerService("yugabytedb", "docker", "exec", "benchmark-yugabytedb",
+			"bin/ysqlsh", "-h", "yugabytedb", "-c", "SELECT 1"),
+		containerService("clickhouse", "docker", "exec", "benchmark-clickhouse",
+			"clickhouse-client", "--query", "SELECT 1"),
+		containerService("starrocks", "curl", "-sf", "-o", "/dev/null", "http://localhost:8040/api/health"),
+		containerService("doris", "curl", "-sf", "-o", "/dev/null", "http://localhost:8041/api/health"),
+		containerService("pinot", "curl", "-sf", "-o", "/dev/null", "http://localhost:8099/health"),
+		containerService("cassandra", "docker", "exec", "benchmark-cassandra", "cqlsh", "-e", "DESCRIBE KEYSPACES"),
+		containerService("scylladb", "docker", "exec", "benchmark-scylladb", "cqlsh", "-e", "DESCRIBE KEYSPACES"),
+		containerService("questdb", "curl", "-sf", "-o", "/dev/null", "http://localhost:9100/exec?query=SELECT%201"),
+		containerService("victoriametrics", "curl", "-sf", "-o", "/dev/null", "http://localhost:8428/health"),
+		containerService("redis", "docker", "exec", "benchmark-redis", "redis-cli", "ping"),
+		containerService("etcd", "docker", "exec", "benchmark-etcd", "etcdctl", "endpoint", "health"),
+		containerService("nats", "curl", "-sf", "http://localhost:8222/healthz?js-enabled-only=true"),
+		containerService("kafka", "docker", "exec", "benchmark-kafka",
+			"/opt/kafka/bin/kafka-broker-api-versions.sh", "--bootstrap-server", "localhost:9092"),
+		{Name: "sqlite"},
+		{Name: "duckdb"},
+		{Name: "badger"},
+		{Name: "pebble"},
+		{Name: "bbolt"},
 	}
 }
 
+// containerService is a database run in the Docker Compose service of the
+// same name, ready once the check command succeeds.
+func containerService(name string, check ...string) DBService {
+	return DBService{Name: name, Service: name, ReadyCheck: check}
+}
+
 // ServiceByName returns the DBService for a given database name.
 func ServiceByName(name string) (DBService, bool) {
 	for _, s := range DefaultServices() {
@@ -103,6 +118,14 @@ func StopService(ctx context.Context, service string) error {
 	return rm.Run()
 }
 
+// RestartService restarts a running docker-compose service, keeping its
+// volumes, so the database starts with empty caches over the same data.
+func RestartService(ctx context.Context, service string) error {
+	logWarnf("Restarting %s...", service)
+
+	return exec.CommandContext(ctx, "docker-compose", "restart", service).Run()
+}
+
 // WaitReady polls the readiness check until it succeeds or the context is canceled.
 func WaitReady(ctx context.Context, svc DBService) error {
 	logInfof("Waiting for %s to be ready...", svc.Name)
@@ -140,6 +163,41 @@ func runReadyCheck(ctx context.Context, args []string) error {
 	return exec.CommandContext(ctx, args[0], args[1:]...).Run()
 }
 
+// Limits are the image and resource limits of a running container; a zero
+// CPUs or Memory means no limit.
+type Limits struct {
+	Image  string
+	CPUs   float64
+	Memory int64 // bytes
+}
+
+// ContainerLimits inspects the container of a service, named
+// benchmark-<service> in docker-compose.yml.
+func ContainerLimits(ctx context.Context, svc DBService) (Limits, error) {
+	if svc.Embedded() {
+		return Limits{}, fmt.Errorf("%s runs in-process", svc.Name)
+	}
+
+	out, err := exec.CommandContext(ctx, "docker", "inspect", "--format",
+		"{{.Config.Image}} {{.HostConfig.NanoCpus}} {{.HostConfig.Memory}}", "benchmark-"+svc.Service).Output()
+	if err != nil {
+		return Limits{}, err
+	}
+
+	var (
+		l        Limits
+		nanoCPUs int64
+	)
+
+	if _, err := fmt.Sscan(string(out), &l.Image, &nanoCPUs, &l.Memory); err != nil {
+		return Limits{}, fmt.Errorf("unexpected docker inspect output %q: %w", out, err)
+	}
+
+	l.CPUs = float64(nanoCPUs) / 1e9
+
+	return l, nil
+}
+
 // Cleanup tears down all docker-compose services and removes volumes.
 func Cleanup(ctx context.Context) error {
 	logWarnf("Cleaning up containers and volumes...")
@@ -0,0 +1,138 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"log"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/skoredin/db-benchmark-suite/internal/repository"
+)
+
+// csvHeader is the header of the CSV output: one row per metric, in long
+// form, so it loads into a spreadsheet or a data frame as it is. Scenario is
+// empty outside the query phase, and run_id when the results have no run.
+var csvHeader = []string{"database", "phase", "scenario", "metric", "value", "unit", "run_id"}
+
+// csvMetric is one value of the CSV output.
+type csvMetric struct {
+	name  string
+	value float64
+	unit  string
+}
+
+// printCSV prints the insert, query and storage metrics of every database.
+// Scenarios a database cannot run and databases that failed have no rows.
+func (r *Reporter) printCSV(results map[string]*benchmark.Results) {
+	w := csv.NewWriter(r.w)
+	_ = w.Write(csvHeader)
+
+	for _, db := range sortedKeys(results) {
+		_ = w.WriteAll(csvRows(db, results[db]))
+	}
+
+	if err := w.Error(); err != nil {
+		log.Println(err)
+	}
+}
+
+func csvRows(db string, result *benchmark.Results) [][]string {
+	var rows [][]string
+
+	var runID string
+	if result.Run != nil {
+		runID = result.Run.ID
+	}
+
+	add := func(phase, scenario string, metrics []csvMetric) {
+		for _, m := range metrics {
+			rows = append(rows, []string{db, phase, scenario, m.name, strconv.FormatFloat(m.value, 'f', -1, 64), m.unit, runID})
+		}
+	}
+
+	if result.Insert != nil {
+		add("insert", "", insertCSV(result.Insert))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(result.Queries)) {
+		if qr := result.Queries[name]; qr.ErrorText == "" {
+			add("query", name, queryCSV(qr))
+		}
+	}
+
+	if result.Storage != nil {
+		add("storage", "", storageCSV(result.Storage))
+	}
+
+	return rows
+}
+
+// ms converts a duration to fractional milliseconds.
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func insertCSV(ir *benchmark.InsertResult) []csvMetric {
+	return []csvMetric{
+		{"events", float64(ir.TotalEvents), "events"},
+		{"duration", ms(ir.Duration), "ms"},
+		{"throughput", ir.Throughput, "events/s"},
+		{"errors", float64(ir.ErrorCount), "batches"},
+		{"batch_size", float64(ir.BatchSize), "events"},
+		{"workers", float64(ir.WorkerCount), "workers"},
+		{"latency_p50", ms(ir.LatencyP50), "ms"},
+		{"latency_p95", ms(ir.LatencyP95), "ms"},
+		{"latency_p99", ms(ir.LatencyP99), "ms"},
+	}
+}
+
+// queryCSV adds the scanned rows and the QPS only for the scenarios that
+// measure them.
+func queryCSV(qr *benchmark.QueryResult) []csvMetric {
+	metrics := []csvMetric{
+		{"iterations", float64(qr.Iterations), "queries"},
+		{"avg", ms(qr.AvgDuration), "ms"},
+		{"min", ms(qr.MinDuration), "ms"},
+		{"max", ms(qr.MaxDuration), "ms"},
+		{"p50", ms(qr.P50Duration), "ms"},
+		{"p95", ms(qr.P95Duration), "ms"},
+		{"p99", ms(qr.P99Duration), "ms"},
+		{"errors", float64(qr.ErrorCount), "queries"},
+	}
+
+	if qr.Rows > 0 {
+		metrics = append(metrics, csvMetric{"rows", float64(qr.Rows), "rows"}, csvMetric{"scan_throughput", qr.Throughput, "rows/s"})
+	}
+
+	if qr.QPS > 0 {
+		metrics = append(metrics, csvMetric{"qps", qr.QPS, "queries/s"})
+	}
+
+	return metrics
+}
+
+// storageCSV adds the engine-specific details after the common metrics;
+// details ending in "_bytes" are sizes.
+func storageCSV(s *repository.StorageStats) []csvMetric {
+	metrics := []csvMetric{
+		{"total_size", float64(s.TotalSize), "bytes"},
+		{"index_size", float64(s.IndexSize), "bytes"},
+		{"compression", s.CompressionPct, "%"},
+		{"rows", float64(s.RowCount), "rows"},
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(s.Details)) {
+		unit := ""
+		if strings.HasSuffix(key, "_bytes") {
+			unit = "bytes"
+		}
+
+		metrics = append(metrics, csvMetric{key, float64(s.Details[key]), unit})
+	}
+
+	return metrics
+}
@@ -0,0 +1,44 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintCSV(t *testing.T) {
+	results := sampleResults()
+	results["postgres"].Queries["point_lookup"] = &benchmark.QueryResult{ErrorText: "not supported"}
+	results["postgres"].Storage.Details = map[string]int64{"wal_bytes": 2048, "segments": 3}
+	results["postgres"].Run = &benchmark.RunInfo{ID: "20261016-210558-3f9a2c"}
+	results["redis"] = &benchmark.Results{Database: "redis", ErrorText: "connection refused"}
+
+	var buf bytes.Buffer
+
+	rep := New("csv", &buf)
+	rep.PrintHeader()
+	rep.PrintResults(results)
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, rows)
+
+	assert.Equal(t, csvHeader, rows[0], "no banner above the header")
+	assert.Contains(t, rows, []string{"postgres", "insert", "", "throughput", "200", "events/s", "20261016-210558-3f9a2c"})
+	assert.Contains(t, rows, []string{"postgres", "insert", "", "duration", "5000", "ms", "20261016-210558-3f9a2c"})
+	assert.Contains(t, rows, []string{"postgres", "query", "1_hour", "p95", "75", "ms", "20261016-210558-3f9a2c"})
+	assert.Contains(t, rows, []string{"postgres", "storage", "", "total_size", "1073741824", "bytes", "20261016-210558-3f9a2c"})
+	assert.Contains(t, rows, []string{"postgres", "storage", "", "compression", "42.5", "%", "20261016-210558-3f9a2c"})
+	assert.Contains(t, rows, []string{"postgres", "storage", "", "wal_bytes", "2048", "bytes", "20261016-210558-3f9a2c"})
+	assert.Contains(t, rows, []string{"postgres", "storage", "", "segments", "3", "", "20261016-210558-3f9a2c"})
+
+	for _, row := range rows[1:] {
+		assert.Len(t, row, len(csvHeader))
+		assert.NotEqual(t, "point_lookup", row[2], "unsupported scenarios have no rows")
+		assert.NotEqual(t, "redis", row[0], "failed databases have no rows")
+	}
+}
@@ -0,0 +1,163 @@
+package reporter
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+var environmentHeader = table.Row{"Database", "Server", "Driver", "Container", "Settings"}
+
+// hostNote describes the host of the first result that records one; all
+// databases of a run share it.
+func hostNote(databases []string, results map[string]*benchmark.Results) string {
+	for _, db := range databases {
+		if env := results[db].Environment; env != nil {
+			return "Host: " + hostLabel(env)
+		}
+	}
+
+	return ""
+}
+
+// hostLabel describes the host of env, e.g. "db-1, linux/amd64, 8 cores,
+// 16.00 GB RAM, go1.24.0".
+func hostLabel(env *benchmark.Environment) string {
+	parts := []string{env.Host, env.OS}
+	if env.Kernel != "" {
+		parts = append(parts, "kernel "+env.Kernel)
+	}
+
+	if env.CPUModel != "" {
+		parts = append(parts, env.CPUModel)
+	}
+
+	parts = append(parts, fmt.Sprintf("%d cores", env.Cores))
+
+	if env.MemoryBytes > 0 {
+		parts = append(parts, formatBytes(env.MemoryBytes)+" RAM")
+	}
+
+	if env.Client != nil {
+		parts = append(parts, "limited to "+limitsLabel(env.Client))
+	}
+
+	return strings.Join(append(parts, env.GoVersion), ", ")
+}
+
+// limitsLabel describes container limits, e.g. "postgres:16, 2 CPUs, 1.00 GB".
+func limitsLabel(l *benchmark.ContainerLimits) string {
+	var parts []string
+
+	if l.Image != "" {
+		parts = append(parts, l.Image)
+	}
+
+	if l.CPUs > 0 {
+		parts = append(parts, fmt.Sprintf("%.4g CPUs", l.CPUs))
+	}
+
+	if l.MemoryBytes > 0 {
+		parts = append(parts, formatBytes(l.MemoryBytes))
+	}
+
+	if len(parts) == 0 {
+		return "-"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func environmentRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		env := results[db].Environment
+		if env == nil {
+			continue
+		}
+
+		row := table.Row{db, orDash(env.ServerVersion), orDash(env.Driver), "-", orDash(settingsLabel(env.Settings))}
+		if env.Database != nil {
+			row[3] = limitsLabel(env.Database)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// settingsLabel lists connection settings by name, e.g.
+// "consistency=LOCAL_ONE write_consistency=QUORUM".
+func settingsLabel(settings map[string]string) string {
+	pairs := make([]string, 0, len(settings))
+	for _, name := range slices.Sorted(maps.Keys(settings)) {
+		pairs = append(pairs, name+"="+settings[name])
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+func (r *Reporter) printEnvironmentTable(databases []string, results map[string]*benchmark.Results) {
+	rows := environmentRows(databases, results)
+	if len(rows) == 0 {
+		return
+	}
+
+	t := r.newTable("ENVIRONMENT")
+	t.AppendHeader(environmentHeader)
+	t.AppendRows(rows)
+	t.Render()
+	r.printLine("  " + hostNote(databases, results))
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownEnvironment(databases []string, results map[string]*benchmark.Results) {
+	rows := environmentRows(databases, results)
+	if len(rows) == 0 {
+		return
+	}
+
+	r.printLine("\n## Environment")
+	r.printLine("\n" + hostNote(databases, results) + "\n")
+
+	t := r.newTable("")
+	t.AppendHeader(environmentHeader)
+	t.AppendRows(rows)
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+// printMarkdownConfig prints the effective flags of the run folded away, so
+// a report pasted into an issue carries what it takes to reproduce it.
+func (r *Reporter) printMarkdownConfig(databases []string, results map[string]*benchmark.Results) {
+	config := runConfig(databases, results)
+	if len(config) == 0 {
+		return
+	}
+
+	r.printLine("\n<details>\n<summary>Configuration</summary>\n")
+
+	t := r.newTable("")
+	t.AppendHeader(table.Row{"Flag", "Value"})
+
+	for _, name := range slices.Sorted(maps.Keys(config)) {
+		t.AppendRow(table.Row{"-" + name, config[name]})
+	}
+
+	t.RenderMarkdown()
+	r.printLine("\n</details>")
+}
@@ -0,0 +1,155 @@
+package reporter
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// histogramWidth is the most characters a histogram bar takes.
+const histogramWidth = 40
+
+var histogramHeader = table.Row{"Database", "Phase", "Latency", "Count", "Share", "Distribution"}
+
+// latencyBin is a row of a rendered histogram: the count of the buckets
+// within one power of two.
+type latencyBin struct {
+	from, to time.Duration
+	count    int64
+}
+
+// latencyBins merges the buckets of h into a bin per power of two, from the
+// fastest to the slowest, with the empty ones in between so gaps between
+// modes show.
+func latencyBins(h *benchmark.Histogram) []latencyBin {
+	var bins []latencyBin
+
+	for _, b := range h.Buckets {
+		for len(bins) == 0 || b.From >= bins[len(bins)-1].to {
+			from := binStart(b.From)
+			if len(bins) > 0 {
+				from = bins[len(bins)-1].to
+			}
+
+			bins = append(bins, latencyBin{from: from, to: max(2*from, time.Microsecond)})
+		}
+
+		bins[len(bins)-1].count += b.Count
+	}
+
+	return bins
+}
+
+// binStart returns the power of two of microseconds at or below d, or 0 for
+// the latencies under a microsecond.
+func binStart(d time.Duration) time.Duration {
+	if d < time.Microsecond {
+		return 0
+	}
+
+	from := time.Microsecond
+	for 2*from <= d {
+		from *= 2
+	}
+
+	return from
+}
+
+func histogramRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		res := results[db]
+
+		if res.Insert != nil && res.Insert.Histogram != nil {
+			rows = append(rows, histogramGroup(db, "insert batch", res.Insert.Histogram)...)
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(res.Queries)) {
+			if h := res.Queries[name].Histogram; h != nil {
+				rows = append(rows, histogramGroup(db, name, h)...)
+			}
+		}
+	}
+
+	return rows
+}
+
+// histogramGroup renders one histogram as a row per bin, naming the database
+// and phase on the first.
+func histogramGroup(db, phase string, h *benchmark.Histogram) []table.Row {
+	bins := latencyBins(h)
+
+	var peak int64
+	for _, b := range bins {
+		peak = max(peak, b.count)
+	}
+
+	rows := make([]table.Row, 0, len(bins))
+
+	for i, b := range bins {
+		row := table.Row{"", "", fmt.Sprintf("%s - %s", roundLatency(b.from), roundLatency(b.to)), b.count,
+			fmt.Sprintf("%.1f%%", float64(b.count)/float64(h.Count)*100), histogramBar(b.count, peak)}
+		if i == 0 {
+			row[0], row[1] = db, phase
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// histogramBar draws count as a bar against the peak count; any count at all
+// shows at least a sliver.
+func histogramBar(count, peak int64) string {
+	if count == 0 {
+		return ""
+	}
+
+	n := int(count * histogramWidth / peak)
+	if n == 0 {
+		return "▏"
+	}
+
+	return strings.Repeat("█", n)
+}
+
+// roundLatency rounds d to three significant digits, e.g. 8.19ms rather
+// than 8.192ms.
+func roundLatency(d time.Duration) time.Duration {
+	if d < time.Microsecond {
+		return d
+	}
+
+	unit := time.Duration(math.Pow10(int(math.Log10(float64(d))) - 2))
+
+	return d.Round(unit)
+}
+
+func (r *Reporter) printHistogramTable(databases []string, results map[string]*benchmark.Results) {
+	rows := histogramRows(databases, results)
+	if !r.verbose || len(rows) == 0 {
+		return
+	}
+
+	t := r.newTable("LATENCY HISTOGRAMS")
+	t.AppendHeader(histogramHeader)
+
+	for _, row := range rows {
+		if row[0] != "" && t.Length() > 0 {
+			t.AppendSeparator()
+		}
+
+		t.AppendRow(row)
+	}
+
+	t.Render()
+	r.printLine()
+}
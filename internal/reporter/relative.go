@@ -0,0 +1,92 @@
+package reporter
+
+import (
+	"fmt"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// referenceLabel marks the row of the reference database itself.
+const referenceLabel = "reference"
+
+// reference is the database the table and markdown output compare the
+// others with: insert throughput, query P95 and storage size. Its zero value
+// compares nothing.
+type reference struct {
+	name   string
+	result *benchmark.Results
+}
+
+// reference returns the database set with SetReference, unless it is not in
+// the results or failed.
+func (r *Reporter) reference(results map[string]*benchmark.Results) reference {
+	result, ok := results[r.relativeTo]
+	if !ok || result.Error != nil || result.ErrorText != "" {
+		return reference{}
+	}
+
+	return reference{name: r.relativeTo, result: result}
+}
+
+func (ref reference) set() bool {
+	return ref.result != nil
+}
+
+func (ref reference) header() string {
+	return "vs " + ref.name
+}
+
+// insert compares the insert throughput of db with the reference's.
+func (ref reference) insert(db string, ir *benchmark.InsertResult) string {
+	if db == ref.name {
+		return referenceLabel
+	}
+
+	if ref.result.Insert == nil {
+		return "-"
+	}
+
+	return speedup(ir.Throughput, ref.result.Insert.Throughput)
+}
+
+// query compares the P95 latency of db on the named scenario with the
+// reference's; a lower latency is faster.
+func (ref reference) query(db, name string, qr *benchmark.QueryResult) string {
+	if db == ref.name {
+		return referenceLabel
+	}
+
+	rq, ok := ref.result.Queries[name]
+	if !ok || rq.ErrorText != "" {
+		return "-"
+	}
+
+	return speedup(rq.P95Duration.Seconds(), qr.P95Duration.Seconds())
+}
+
+// storage compares the storage size of db with the reference's.
+func (ref reference) storage(db string, result *benchmark.Results) string {
+	if db == ref.name {
+		return referenceLabel
+	}
+
+	base := ref.result.Storage
+	if base == nil || base.TotalSize == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%+.0f%% storage", (float64(result.Storage.TotalSize)/float64(base.TotalSize)-1)*100)
+}
+
+// speedup labels how many times faster a rate is than the reference rate,
+// or how many times slower when it is lower, e.g. "2.4× faster".
+func speedup(rate, refRate float64) string {
+	switch {
+	case rate <= 0 || refRate <= 0:
+		return "-"
+	case rate >= refRate:
+		return fmt.Sprintf("%.1f× faster", rate/refRate)
+	default:
+		return fmt.Sprintf("%.1f× slower", refRate/rate)
+	}
+}
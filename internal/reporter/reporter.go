@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"maps"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -14,19 +18,42 @@ import (
 )
 
 type Reporter struct {
-	format string
-	w      io.Writer
+	format     string
+	w          io.Writer
+	verbose    bool
+	relativeTo string
+	weights    map[string]float64 // of the overall score; nil leaves it out
+	template   Template           // of the template output
 }
 
 func New(format string, w io.Writer) *Reporter {
 	return &Reporter{format: format, w: w}
 }
 
+// SetVerbose adds the per-worker insert breakdown to the table and markdown
+// output, and the latency histograms to the table. JSON always carries both.
+func (r *Reporter) SetVerbose(verbose bool) {
+	r.verbose = verbose
+}
+
+// SetReference adds a column to the insert, query and storage tables of the
+// table and markdown output that compares each database with db, e.g.
+// "2.4× faster" or "+38% storage".
+func (r *Reporter) SetReference(db string) {
+	r.relativeTo = db
+}
+
 func (r *Reporter) printLine(a ...any) {
 	_, _ = fmt.Fprintln(r.w, a...)
 }
 
+// PrintHeader prints the banner above the results; CSV and template output
+// have none, so they can be read as they are.
 func (r *Reporter) PrintHeader() {
+	if r.format == "csv" || r.format == "template" {
+		return
+	}
+
 	r.printLine()
 	r.printLine("  Database Benchmark Suite")
 	r.printLine()
@@ -38,11 +65,35 @@ func (r *Reporter) PrintResults(results map[string]*benchmark.Results) {
 		r.printJSON(results)
 	case "markdown":
 		r.printMarkdown(results)
+	case "csv":
+		r.printCSV(results)
+	case "template":
+		r.printTemplate(results)
 	default:
 		r.printTable(results)
 	}
 }
 
+// PrintComparison prints only the baseline deltas of the results, e.g. for a
+// comparison of two stored runs.
+func (r *Reporter) PrintComparison(results map[string]*benchmark.Results) {
+	databases := sortedKeys(results)
+
+	switch r.format {
+	case "json":
+		deltas := make(map[string][]benchmark.BaselineDelta, len(results))
+		for _, db := range databases {
+			deltas[db] = results[db].Baseline
+		}
+
+		r.printJSONValue(deltas)
+	case "markdown":
+		r.printMarkdownBaseline(databases, results)
+	default:
+		r.printBaselineTable(databases, results)
+	}
+}
+
 func (r *Reporter) newTable(title string) table.Writer {
 	t := table.NewWriter()
 	t.SetOutputMirror(r.w)
@@ -57,29 +108,64 @@ func (r *Reporter) newTable(title string) table.Writer {
 
 func (r *Reporter) printTable(results map[string]*benchmark.Results) {
 	databases := sortedKeys(results)
+
+	for _, note := range []string{runNote(databases, results), interruptedNote(databases, results), warmupNote(databases, results)} {
+		if note != "" {
+			r.printLine("  " + note)
+			r.printLine()
+		}
+	}
+
+	r.printWriteTables(databases, results)
+	r.printReadTables(databases, results)
+}
+
+// printWriteTables prints the tables of the insert runs and of the modes that
+// repeat or compare them.
+func (r *Reporter) printWriteTables(databases []string, results map[string]*benchmark.Results) {
 	r.printInsertTable(databases, results)
+	r.printWorkerTable(databases, results)
+	r.printClientTable(databases, results)
+	r.printMatrixTable(databases, results)
+	r.printTimelineTable(databases, results)
+	r.printSteadyStateTable(databases, results)
+	r.printDurabilityTable(databases, results)
+	r.printInsertModeTable(databases, results)
+	r.printIndexBuildTable(databases, results)
+	r.printScalingTable(databases, results)
+	r.printBatchTuningTable(databases, results)
+	r.printVarianceTable(databases, results)
+	r.printParityTable(databases, results)
+}
+
+// printReadTables prints the tables of the queries and the other workloads,
+// then storage, the baseline comparison, the summary and the environment.
+func (r *Reporter) printReadTables(databases []string, results map[string]*benchmark.Results) {
 	r.printQueryTables(databases, results)
+	r.printHistogramTable(databases, results)
+	r.printMixedTable(databases, results)
+	r.printTransactionTable(databases, results)
+	r.printReadAfterWriteTable(databases, results)
+	r.printRetentionTable(databases, results)
+	r.printTTLTable(databases, results)
+	r.printCacheTable(databases, results)
 	r.printStorageTable(databases, results)
+	r.printBaselineTable(databases, results)
+	r.printSummaryTable(databases, results)
+	r.printEnvironmentTable(databases, results)
 }
 
 func (r *Reporter) printInsertTable(databases []string, results map[string]*benchmark.Results) {
 	t := r.newTable("INSERT BENCHMARK")
-	t.AppendHeader(table.Row{"Database", "Events", "Duration", "Throughput", "Errors", "Workers", "Batch"})
+	cols := newInsertColumns(results, r.reference(results))
+	t.AppendHeader(cols.header())
 
 	for _, db := range databases {
 		result := results[db]
 		if result.Error != nil {
 			t.AppendRow(table.Row{db, "ERROR", result.Error, "", "", "", ""})
 		} else if result.Insert != nil {
-			t.AppendRow(table.Row{
-				db,
-				result.Insert.TotalEvents,
-				result.Insert.Duration.Round(time.Millisecond),
-				fmt.Sprintf("%.0f/sec", result.Insert.Throughput),
-				result.Insert.ErrorCount,
-				result.Insert.WorkerCount,
-				result.Insert.BatchSize,
-			})
+			t.AppendRow(cols.row(db, result))
 		}
 	}
 
@@ -87,19 +173,276 @@ func (r *Reporter) printInsertTable(databases []string, results map[string]*benc
 	r.printLine()
 }
 
+func (r *Reporter) printWorkerTable(databases []string, results map[string]*benchmark.Results) {
+	if !r.verbose || !hasWorkerStats(results) {
+		return
+	}
+
+	t := r.newTable("INSERT WORKERS")
+	t.AppendHeader(workerHeader)
+	t.AppendRows(workerRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printClientTable(databases []string, results map[string]*benchmark.Results) {
+	rows := clientRows(databases, results)
+	if !r.verbose || len(rows) == 0 {
+		return
+	}
+
+	t := r.newTable("CLIENT RESOURCES")
+	t.AppendHeader(clientHeader)
+	t.AppendRows(rows)
+	t.Render()
+	r.printLine()
+}
+
+// insertColumns are the optional columns of the insert table, shown when any
+// result has a value for them.
+type insertColumns struct {
+	duplicates bool
+	rate       bool
+	ramp       bool
+	indexes    bool
+	insertMode bool
+	verify     bool
+	ref        reference
+}
+
+func newInsertColumns(results map[string]*benchmark.Results, ref reference) insertColumns {
+	return insertColumns{
+		duplicates: hasDuplicates(results),
+		rate:       hasTargetRate(results),
+		ramp:       hasRampUp(results),
+		indexes:    hasIndexes(results),
+		insertMode: hasInsertMode(results),
+		verify:     hasVerify(results),
+		ref:        ref,
+	}
+}
+
+func (c insertColumns) header() table.Row {
+	header := c.appendLoadHeader(table.Row{"Database", "Events", "Duration", "Throughput", "Errors", "Workers", "Batch"})
+
+	if c.indexes {
+		header = append(header, "Indexes")
+	}
+
+	if c.insertMode {
+		header = append(header, "Insert Mode")
+	}
+
+	if c.verify {
+		header = append(header, "Verified")
+	}
+
+	if c.ref.set() {
+		header = append(header, c.ref.header())
+	}
+
+	return header
+}
+
+// appendLoadHeader appends the headers of the columns on how the load was
+// applied.
+func (c insertColumns) appendLoadHeader(header table.Row) table.Row {
+	if c.duplicates {
+		header = append(header, "Duplicates")
+	}
+
+	if c.rate {
+		header = append(header, "Target", "Batch P50", "Batch P99", "Corrected P99")
+	}
+
+	if c.ramp {
+		header = append(header, "Ramp-up", "Ramp Events")
+	}
+
+	return header
+}
+
+func (c insertColumns) row(db string, result *benchmark.Results) table.Row {
+	insert := result.Insert
+	row := table.Row{
+		db,
+		insert.TotalEvents,
+		insert.Duration.Round(time.Millisecond),
+		fmt.Sprintf("%.0f/sec", insert.Throughput),
+		errorsLabel(insert.ErrorCount, insert.Retries, insert.Aborted),
+		insert.WorkerCount,
+		insert.BatchSize,
+	}
+
+	row = c.appendLoadCells(row, insert)
+
+	if c.indexes {
+		row = append(row, indexSetLabel(result))
+	}
+
+	if c.insertMode {
+		row = append(row, insertModeLabel(result))
+	}
+
+	if c.verify {
+		row = append(row, verifyLabel(result.Insert.Verify))
+	}
+
+	if c.ref.set() {
+		row = append(row, c.ref.insert(db, result.Insert))
+	}
+
+	return row
+}
+
+// appendLoadCells appends the cells of the columns on how the load was
+// applied: duplicates, target rate and ramp-up.
+func (c insertColumns) appendLoadCells(row table.Row, insert *benchmark.InsertResult) table.Row {
+	if c.duplicates {
+		row = append(row, insert.Duplicates)
+	}
+
+	if c.rate {
+		row = append(row,
+			targetRateLabel(insert.TargetRate),
+			insert.LatencyP50.Round(time.Millisecond),
+			insert.LatencyP99.Round(time.Millisecond),
+			insert.CorrectedP99.Round(time.Millisecond),
+		)
+	}
+
+	if c.ramp {
+		row = append(row, insert.RampUp, insert.RampEvents)
+	}
+
+	return row
+}
+
+func (r *Reporter) printMatrixTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasParams(results) {
+		return
+	}
+
+	t := r.newTable("PARAMETER MATRIX")
+	t.AppendHeader(matrixHeader)
+	t.AppendRows(matrixRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printTimelineTable(databases []string, results map[string]*benchmark.Results) {
+	rows := timelineRows(databases, results)
+	if len(rows) == 0 {
+		return
+	}
+
+	t := r.newTable("INSERT THROUGHPUT TIMELINE")
+	t.AppendHeader(table.Row{"Database", "Seconds", "Peak", "Low", "Events/sec"})
+	t.AppendRows(rows)
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printSteadyStateTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasSteadyState(results) {
+		return
+	}
+
+	t := r.newTable("STEADY STATE")
+	t.AppendHeader(steadyStateHeader)
+	t.AppendRows(steadyStateRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printDurabilityTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasDurability(results) {
+		return
+	}
+
+	t := r.newTable("DURABILITY MATRIX")
+	t.AppendHeader(table.Row{"Database", "Durability", "Throughput", "vs Fastest", "Duration", "Errors"})
+	t.AppendRows(durabilityRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printInsertModeTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasInsertModes(results) {
+		return
+	}
+
+	t := r.newTable("INSERT MODES")
+	t.AppendHeader(table.Row{"Database", "Insert Mode", "Throughput", "vs Fastest", "Duration", "Errors"})
+	t.AppendRows(insertModeRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printIndexBuildTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasIndexBuild(results) {
+		return
+	}
+
+	t := r.newTable("DEFERRED INDEX BUILD")
+	t.AppendHeader(indexBuildHeader)
+	t.AppendRows(indexBuildRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printScalingTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasScaling(results) {
+		return
+	}
+
+	t := r.newTable("WORKER SCALING")
+	t.AppendHeader(scalingHeader)
+	t.AppendRows(scalingRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printBatchTuningTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasBatchTuning(results) {
+		return
+	}
+
+	t := r.newTable("BATCH SIZE TUNING")
+	t.AppendHeader(batchTuningHeader)
+	t.AppendRows(batchTuningRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printVarianceTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasRepeat(results) {
+		return
+	}
+
+	t := r.newTable("RUN-TO-RUN VARIANCE")
+	t.AppendHeader(varianceHeader)
+	t.AppendRows(varianceRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
 func (r *Reporter) printQueryTables(databases []string, results map[string]*benchmark.Results) {
 	for _, queryName := range sortedQueryNames(results) {
 		t := r.newTable(queryName + " QUERY")
-		t.AppendHeader(table.Row{"Database", "Avg", "Min", "Max", "P50", "P95", "P99", "Errors"})
+		cols := newQueryColumns(results, queryName, r.reference(results))
+		header := cols.header(table.Row{"Database", "Avg", "Min", "Max", "P50", "P95", "P99", "Errors"})
+		t.AppendHeader(header)
 
 		for _, db := range databases {
-			result := results[db]
-			if result.Queries == nil {
-				continue
-			}
+			qr, exists := results[db].Queries[queryName]
 
-			if qr, exists := result.Queries[queryName]; exists {
-				t.AppendRow(table.Row{
+			switch {
+			case !exists:
+			case qr.ErrorText != "":
+				t.AppendRow(errorRow(db, qr.ErrorText, len(header)))
+			default:
+				t.AppendRow(cols.row(db, table.Row{
 					db,
 					qr.AvgDuration.Round(time.Millisecond),
 					qr.MinDuration.Round(time.Millisecond),
@@ -107,8 +450,8 @@ func (r *Reporter) printQueryTables(databases []string, results map[string]*benc
 					qr.P50Duration.Round(time.Millisecond),
 					qr.P95Duration.Round(time.Millisecond),
 					qr.P99Duration.Round(time.Millisecond),
-					qr.ErrorCount,
-				})
+					errorsLabel(qr.ErrorCount, qr.Retries, qr.Aborted),
+				}, qr))
 			}
 		}
 
@@ -117,20 +460,98 @@ func (r *Reporter) printQueryTables(databases []string, results map[string]*benc
 	}
 }
 
+func (r *Reporter) printMixedTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasMixed(results) {
+		return
+	}
+
+	t := r.newTable("READ WHILE WRITE")
+	t.AppendHeader(mixedHeader)
+	t.AppendRows(mixedRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printTransactionTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasTransactions(results) {
+		return
+	}
+
+	t := r.newTable("TRANSACTION BENCHMARK")
+	t.AppendHeader(transactionHeader)
+	t.AppendRows(transactionRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printParityTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasParity(results) {
+		return
+	}
+
+	t := r.newTable("QUERY RESULT PARITY")
+	t.AppendHeader(parityHeader)
+	t.AppendRows(parityRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printReadAfterWriteTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasReadAfterWrite(results) {
+		return
+	}
+
+	t := r.newTable("READ-AFTER-WRITE VISIBILITY")
+	t.AppendHeader(readAfterWriteHeader)
+	t.AppendRows(readAfterWriteRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printRetentionTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasRetention(results) {
+		return
+	}
+
+	t := r.newTable("RETENTION BENCHMARK")
+	t.AppendHeader(retentionHeader)
+	t.AppendRows(retentionRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printTTLTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasTTL(results) {
+		return
+	}
+
+	t := r.newTable("TTL BENCHMARK")
+	t.AppendHeader(ttlHeader)
+	t.AppendRows(ttlRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+func (r *Reporter) printCacheTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasCache(results) {
+		return
+	}
+
+	t := r.newTable("COLD VS WARM CACHE")
+	t.AppendHeader(cacheHeader)
+	t.AppendRows(cacheRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
 func (r *Reporter) printStorageTable(databases []string, results map[string]*benchmark.Results) {
 	t := r.newTable("STORAGE STATISTICS")
-	t.AppendHeader(table.Row{"Database", "Total Size", "Index Size", "Compression", "Row Count"})
+	ref := r.reference(results)
+	t.AppendHeader(storageHeader(results, "Row Count", ref))
 
 	for _, db := range databases {
-		result := results[db]
-		if result.Storage != nil {
-			t.AppendRow(table.Row{
-				db,
-				formatBytes(result.Storage.TotalSize),
-				formatBytes(result.Storage.IndexSize),
-				fmt.Sprintf("%.1f%%", result.Storage.CompressionPct),
-				result.Storage.RowCount,
-			})
+		if row := storageRow(db, results, ref); row != nil {
+			t.AppendRow(row)
 		}
 	}
 
@@ -138,20 +559,95 @@ func (r *Reporter) printStorageTable(databases []string, results map[string]*ben
 	r.printLine()
 }
 
+func (r *Reporter) printBaselineTable(databases []string, results map[string]*benchmark.Results) {
+	if !hasBaseline(results) {
+		return
+	}
+
+	t := r.newTable("BASELINE COMPARISON")
+	t.AppendHeader(baselineHeader)
+	t.AppendRows(baselineRows(databases, results))
+	t.Render()
+	r.printLine()
+}
+
+// printJSON prints the versioned results document; see
+// benchmark.MarshalResults.
 func (r *Reporter) printJSON(results map[string]*benchmark.Results) {
+	data, err := benchmark.MarshalResults(results)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	r.printLine(string(data))
+}
+
+func (r *Reporter) printJSONValue(v any) {
 	encoder := json.NewEncoder(r.w)
 	encoder.SetIndent("", "  ")
 
-	if err := encoder.Encode(results); err != nil {
+	if err := encoder.Encode(v); err != nil {
 		log.Println(err)
 	}
 }
 
 func (r *Reporter) printMarkdown(results map[string]*benchmark.Results) {
 	databases := sortedKeys(results)
+
+	r.printMarkdownNotes(databases, results)
+	r.printMarkdownWrites(databases, results)
+	r.printMarkdownReads(databases, results)
+}
+
+// printMarkdownNotes prints the notes on the run, the interrupted one in bold.
+func (r *Reporter) printMarkdownNotes(databases []string, results map[string]*benchmark.Results) {
+	if note := runNote(databases, results); note != "" {
+		r.printLine("\n_" + note + "_")
+	}
+
+	if note := interruptedNote(databases, results); note != "" {
+		r.printLine("\n**" + note + "**")
+	}
+
+	if note := warmupNote(databases, results); note != "" {
+		r.printLine("\n_" + note + "_")
+	}
+}
+
+// printMarkdownWrites prints the sections of the insert runs and of the modes
+// that repeat or compare them.
+func (r *Reporter) printMarkdownWrites(databases []string, results map[string]*benchmark.Results) {
 	r.printMarkdownInsert(databases, results)
+	r.printMarkdownWorkers(databases, results)
+	r.printMarkdownClient(databases, results)
+	r.printMarkdownMatrix(databases, results)
+	r.printMarkdownSteadyState(databases, results)
+	r.printMarkdownDurability(databases, results)
+	r.printMarkdownInsertModes(databases, results)
+	r.printMarkdownIndexBuild(databases, results)
+	r.printMarkdownScaling(databases, results)
+	r.printMarkdownBatchTuning(databases, results)
+	r.printMarkdownVariance(databases, results)
+	r.printMarkdownParity(databases, results)
+}
+
+// printMarkdownReads prints the sections of the queries and the other
+// workloads, then storage, the baseline comparison, the summary, the
+// environment and the configuration.
+func (r *Reporter) printMarkdownReads(databases []string, results map[string]*benchmark.Results) {
 	r.printMarkdownQueries(databases, results)
+	r.printMarkdownMixed(databases, results)
+	r.printMarkdownTransactions(databases, results)
+	r.printMarkdownReadAfterWrite(databases, results)
+	r.printMarkdownRetention(databases, results)
+	r.printMarkdownTTL(databases, results)
+	r.printMarkdownCache(databases, results)
 	r.printMarkdownStorage(databases, results)
+	r.printMarkdownBaseline(databases, results)
+	r.printMarkdownSummary(databases, results)
+	r.printMarkdownEnvironment(databases, results)
+	r.printMarkdownConfig(databases, results)
 }
 
 func (r *Reporter) printMarkdownInsert(databases []string, results map[string]*benchmark.Results) {
@@ -160,20 +656,16 @@ func (r *Reporter) printMarkdownInsert(databases []string, results map[string]*b
 
 	t.Style().Options.SeparateColumns = true
 
-	t.AppendHeader(table.Row{"Database", "Events", "Duration", "Throughput", "Errors"})
+	cols := newInsertColumns(results, r.reference(results))
+	header := cols.markdownHeader()
+	t.AppendHeader(header)
 
 	for _, db := range databases {
 		result := results[db]
 		if result.Error != nil {
-			t.AppendRow(table.Row{db, "ERROR", "-", "-", "-"})
+			t.AppendRow(errorRow(db, "ERROR", len(header)))
 		} else if result.Insert != nil {
-			t.AppendRow(table.Row{
-				db,
-				result.Insert.TotalEvents,
-				result.Insert.Duration.Round(time.Second),
-				fmt.Sprintf("%.0f/sec", result.Insert.Throughput),
-				result.Insert.ErrorCount,
-			})
+			t.AppendRow(cols.markdownRow(db, result))
 		}
 	}
 
@@ -182,69 +674,1799 @@ func (r *Reporter) printMarkdownInsert(databases []string, results map[string]*b
 	r.printLine()
 }
 
-func (r *Reporter) printMarkdownQueries(databases []string, results map[string]*benchmark.Results) {
-	for _, queryName := range sortedQueryNames(results) {
-		_, _ = fmt.Fprintf(r.w, "\n### %s Query\n\n", queryName)
+// markdownHeader is the header of the shorter markdown insert table, which
+// leaves out workers, batch size and duplicates.
+func (c insertColumns) markdownHeader() table.Row {
+	header := table.Row{"Database", "Events", "Duration", "Throughput", "Errors"}
 
-		t := r.newTable("")
-		t.AppendHeader(table.Row{"Database", "Avg", "Min", "Max", "P95", "P99"})
+	if c.rate {
+		header = append(header, "Target", "Batch P99", "Corrected P99")
+	}
 
-		for _, db := range databases {
-			result := results[db]
-			if result.Queries == nil {
-				continue
-			}
+	if c.ramp {
+		header = append(header, "Ramp-up")
+	}
 
-			if qr, exists := result.Queries[queryName]; exists {
-				t.AppendRow(table.Row{
-					db,
-					qr.AvgDuration.Round(time.Millisecond),
-					qr.MinDuration.Round(time.Millisecond),
-					qr.MaxDuration.Round(time.Millisecond),
-					qr.P95Duration.Round(time.Millisecond),
-					qr.P99Duration.Round(time.Millisecond),
-				})
-			}
-		}
+	if c.indexes {
+		header = append(header, "Indexes")
+	}
 
-		t.RenderMarkdown()
-		r.printLine()
+	if c.insertMode {
+		header = append(header, "Insert Mode")
+	}
+
+	if c.verify {
+		header = append(header, "Verified")
 	}
+
+	if c.ref.set() {
+		header = append(header, c.ref.header())
+	}
+
+	return header
 }
 
-func (r *Reporter) printMarkdownStorage(databases []string, results map[string]*benchmark.Results) {
-	r.printLine("\n## Storage Statistics")
+func (c insertColumns) markdownRow(db string, result *benchmark.Results) table.Row {
+	row := table.Row{
+		db,
+		result.Insert.TotalEvents,
+		result.Insert.Duration.Round(time.Second),
+		fmt.Sprintf("%.0f/sec", result.Insert.Throughput),
+		errorsLabel(result.Insert.ErrorCount, result.Insert.Retries, result.Insert.Aborted),
+	}
 
-	t := r.newTable("")
-	t.AppendHeader(table.Row{"Database", "Total Size", "Index Size", "Compression", "Rows"})
+	if c.rate {
+		row = append(row,
+			targetRateLabel(result.Insert.TargetRate),
+			result.Insert.LatencyP99.Round(time.Millisecond),
+			result.Insert.CorrectedP99.Round(time.Millisecond),
+		)
+	}
 
-	for _, db := range databases {
-		result := results[db]
-		if result.Storage != nil {
-			t.AppendRow(table.Row{
-				db,
-				formatBytes(result.Storage.TotalSize),
-				formatBytes(result.Storage.IndexSize),
-				fmt.Sprintf("%.1f%%", result.Storage.CompressionPct),
-				result.Storage.RowCount,
-			})
-		}
+	if c.ramp {
+		row = append(row, result.Insert.RampUp)
 	}
 
-	t.RenderMarkdown()
-	r.printLine()
-}
+	if c.indexes {
+		row = append(row, indexSetLabel(result))
+	}
 
-func sortedKeys(results map[string]*benchmark.Results) []string {
-	databases := make([]string, 0, len(results))
+	if c.insertMode {
+		row = append(row, insertModeLabel(result))
+	}
 
-	for db := range results {
-		databases = append(databases, db)
+	if c.verify {
+		row = append(row, verifyLabel(result.Insert.Verify))
 	}
 
-	sort.Strings(databases)
+	if c.ref.set() {
+		row = append(row, c.ref.insert(db, result.Insert))
+	}
 
-	return databases
+	return row
+}
+
+func (r *Reporter) printMarkdownSteadyState(databases []string, results map[string]*benchmark.Results) {
+	if !hasSteadyState(results) {
+		return
+	}
+
+	r.printLine("\n## Steady State")
+
+	t := r.newTable("")
+	t.AppendHeader(steadyStateHeader)
+	t.AppendRows(steadyStateRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownDurability(databases []string, results map[string]*benchmark.Results) {
+	if !hasDurability(results) {
+		return
+	}
+
+	r.printLine("\n## Durability Matrix")
+
+	t := r.newTable("")
+	t.AppendHeader(table.Row{"Database", "Durability", "Throughput", "vs Fastest", "Duration", "Errors"})
+	t.AppendRows(durabilityRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownInsertModes(databases []string, results map[string]*benchmark.Results) {
+	if !hasInsertModes(results) {
+		return
+	}
+
+	r.printLine("\n## Insert Modes")
+
+	t := r.newTable("")
+	t.AppendHeader(table.Row{"Database", "Insert Mode", "Throughput", "vs Fastest", "Duration", "Errors"})
+	t.AppendRows(insertModeRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownIndexBuild(databases []string, results map[string]*benchmark.Results) {
+	if !hasIndexBuild(results) {
+		return
+	}
+
+	r.printLine("\n## Deferred Index Build")
+
+	t := r.newTable("")
+	t.AppendHeader(indexBuildHeader)
+	t.AppendRows(indexBuildRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownMatrix(databases []string, results map[string]*benchmark.Results) {
+	if !hasParams(results) {
+		return
+	}
+
+	r.printLine("\n## Parameter Matrix")
+
+	t := r.newTable("")
+	t.AppendHeader(matrixHeader)
+	t.AppendRows(matrixRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownScaling(databases []string, results map[string]*benchmark.Results) {
+	if !hasScaling(results) {
+		return
+	}
+
+	r.printLine("\n## Worker Scaling")
+
+	t := r.newTable("")
+	t.AppendHeader(scalingHeader)
+	t.AppendRows(scalingRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownBatchTuning(databases []string, results map[string]*benchmark.Results) {
+	if !hasBatchTuning(results) {
+		return
+	}
+
+	r.printLine("\n## Batch Size Tuning")
+
+	t := r.newTable("")
+	t.AppendHeader(batchTuningHeader)
+	t.AppendRows(batchTuningRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownVariance(databases []string, results map[string]*benchmark.Results) {
+	if !hasRepeat(results) {
+		return
+	}
+
+	r.printLine("\n## Run-to-Run Variance")
+
+	t := r.newTable("")
+	t.AppendHeader(varianceHeader)
+	t.AppendRows(varianceRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownQueries(databases []string, results map[string]*benchmark.Results) {
+	for _, queryName := range sortedQueryNames(results) {
+		r.printMarkdownQuery(databases, results, queryName)
+	}
+}
+
+func (r *Reporter) printMarkdownQuery(databases []string, results map[string]*benchmark.Results, queryName string) {
+	_, _ = fmt.Fprintf(r.w, "\n### %s Query\n\n", queryName)
+
+	t := r.newTable("")
+	cols := newQueryColumns(results, queryName, r.reference(results))
+	header := cols.header(table.Row{"Database", "Avg", "Min", "Max", "P95", "P99"})
+	t.AppendHeader(header)
+
+	for _, db := range databases {
+		qr, exists := results[db].Queries[queryName]
+
+		switch {
+		case !exists:
+		case qr.ErrorText != "":
+			t.AppendRow(errorRow(db, qr.ErrorText, len(header)))
+		default:
+			t.AppendRow(cols.row(db, table.Row{
+				db,
+				qr.AvgDuration.Round(time.Millisecond),
+				qr.MinDuration.Round(time.Millisecond),
+				qr.MaxDuration.Round(time.Millisecond),
+				qr.P95Duration.Round(time.Millisecond),
+				qr.P99Duration.Round(time.Millisecond),
+			}, qr))
+		}
+	}
+
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownMixed(databases []string, results map[string]*benchmark.Results) {
+	if !hasMixed(results) {
+		return
+	}
+
+	r.printLine("\n## Read While Write")
+
+	t := r.newTable("")
+	t.AppendHeader(mixedHeader)
+	t.AppendRows(mixedRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownWorkers(databases []string, results map[string]*benchmark.Results) {
+	if !r.verbose || !hasWorkerStats(results) {
+		return
+	}
+
+	r.printLine("\n## Insert Workers")
+
+	t := r.newTable("")
+	t.AppendHeader(workerHeader)
+	t.AppendRows(workerRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownClient(databases []string, results map[string]*benchmark.Results) {
+	rows := clientRows(databases, results)
+	if !r.verbose || len(rows) == 0 {
+		return
+	}
+
+	r.printLine("\n## Client Resources")
+
+	t := r.newTable("")
+	t.AppendHeader(clientHeader)
+	t.AppendRows(rows)
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownTransactions(databases []string, results map[string]*benchmark.Results) {
+	if !hasTransactions(results) {
+		return
+	}
+
+	r.printLine("\n## Transactions")
+
+	t := r.newTable("")
+	t.AppendHeader(transactionHeader)
+	t.AppendRows(transactionRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownParity(databases []string, results map[string]*benchmark.Results) {
+	if !hasParity(results) {
+		return
+	}
+
+	r.printLine("\n## Query Result Parity")
+
+	t := r.newTable("")
+	t.AppendHeader(parityHeader)
+	t.AppendRows(parityRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownReadAfterWrite(databases []string, results map[string]*benchmark.Results) {
+	if !hasReadAfterWrite(results) {
+		return
+	}
+
+	r.printLine("\n## Read-After-Write Visibility")
+
+	t := r.newTable("")
+	t.AppendHeader(readAfterWriteHeader)
+	t.AppendRows(readAfterWriteRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownRetention(databases []string, results map[string]*benchmark.Results) {
+	if !hasRetention(results) {
+		return
+	}
+
+	r.printLine("\n## Retention")
+
+	t := r.newTable("")
+	t.AppendHeader(retentionHeader)
+	t.AppendRows(retentionRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownTTL(databases []string, results map[string]*benchmark.Results) {
+	if !hasTTL(results) {
+		return
+	}
+
+	r.printLine("\n## TTL Expiry")
+
+	t := r.newTable("")
+	t.AppendHeader(ttlHeader)
+	t.AppendRows(ttlRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownCache(databases []string, results map[string]*benchmark.Results) {
+	if !hasCache(results) {
+		return
+	}
+
+	r.printLine("\n## Cold vs Warm Cache")
+
+	t := r.newTable("")
+	t.AppendHeader(cacheHeader)
+	t.AppendRows(cacheRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownStorage(databases []string, results map[string]*benchmark.Results) {
+	r.printLine("\n## Storage Statistics")
+
+	t := r.newTable("")
+	ref := r.reference(results)
+	t.AppendHeader(storageHeader(results, "Rows", ref))
+
+	for _, db := range databases {
+		if row := storageRow(db, results, ref); row != nil {
+			t.AppendRow(row)
+		}
+	}
+
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+func (r *Reporter) printMarkdownBaseline(databases []string, results map[string]*benchmark.Results) {
+	if !hasBaseline(results) {
+		return
+	}
+
+	r.printLine("\n## Baseline Comparison")
+
+	t := r.newTable("")
+	t.AppendHeader(baselineHeader)
+	t.AppendRows(baselineRows(databases, results))
+	t.RenderMarkdown()
+	r.printLine()
+}
+
+// storageHeader adds a Details column only when some database reports
+// engine-specific storage metrics, and a column comparing the size with that
+// of ref when it is set.
+func storageHeader(results map[string]*benchmark.Results, rowsLabel string, ref reference) table.Row {
+	header := table.Row{"Database", "Total Size", "Index Size", "Compression", rowsLabel}
+	if ref.set() {
+		header = append(header, ref.header())
+	}
+
+	if hasStorageDetails(results) {
+		header = append(header, "Details")
+	}
+
+	return header
+}
+
+func storageRow(db string, results map[string]*benchmark.Results, ref reference) table.Row {
+	storage := results[db].Storage
+	if storage == nil {
+		return nil
+	}
+
+	row := table.Row{
+		db,
+		formatBytes(storage.TotalSize),
+		formatBytes(storage.IndexSize),
+		fmt.Sprintf("%.1f%%", storage.CompressionPct),
+		storage.RowCount,
+	}
+
+	if ref.set() {
+		row = append(row, ref.storage(db, results[db]))
+	}
+
+	if hasStorageDetails(results) {
+		row = append(row, formatDetails(storage.Details))
+	}
+
+	return row
+}
+
+func hasStorageDetails(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Storage != nil && len(result.Storage.Details) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatDetails renders details as "key=value" pairs sorted by key, with
+// "_bytes" values formatted as sizes.
+func formatDetails(details map[string]int64) string {
+	keys := make([]string, 0, len(details))
+	for k := range details {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+
+	for _, k := range keys {
+		value := strconv.FormatInt(details[k], 10)
+		if strings.HasSuffix(k, "_bytes") {
+			value = formatBytes(details[k])
+		}
+
+		parts = append(parts, k+"="+value)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// interruptedNote names the databases whose benchmark was cancelled before
+// it finished, so their numbers are not mistaken for complete runs.
+func interruptedNote(databases []string, results map[string]*benchmark.Results) string {
+	var partial []string
+
+	for _, db := range databases {
+		if results[db].Interrupted {
+			partial = append(partial, db)
+		}
+	}
+
+	if len(partial) == 0 {
+		return ""
+	}
+
+	return "INTERRUPTED: partial results for " + strings.Join(partial, ", ")
+}
+
+// warmupNote describes the warm-up of the first result that records one; all
+// databases of a run share the flags it comes from.
+func warmupNote(databases []string, results map[string]*benchmark.Results) string {
+	for _, db := range databases {
+		if w := results[db].Warmup; w != nil {
+			return fmt.Sprintf("Warm-up: %d batches before each insert run and %d runs of each query scenario, not measured",
+				w.InsertBatches, w.QueryIterations)
+		}
+	}
+
+	return ""
+}
+
+// runNote identifies the run of the first result that records one; all
+// databases of a run share it.
+func runNote(databases []string, results map[string]*benchmark.Results) string {
+	for _, db := range databases {
+		if run := results[db].Run; run != nil {
+			return fmt.Sprintf("Run %s, revision %s, %s to %s (%s)", run.ID, benchmark.ShortRevision(run.Revision),
+				run.StartedAt.Format(time.DateTime), run.FinishedAt.Format(time.DateTime),
+				run.FinishedAt.Sub(run.StartedAt).Round(time.Second))
+		}
+	}
+
+	return ""
+}
+
+// runConfig returns the effective flags of the first result that records
+// them.
+func runConfig(databases []string, results map[string]*benchmark.Results) map[string]string {
+	for _, db := range databases {
+		if run := results[db].Run; run != nil {
+			return run.Config
+		}
+	}
+
+	return nil
+}
+
+func hasDuplicates(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Insert != nil && result.Insert.Duplicates > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasTargetRate(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Insert != nil && result.Insert.TargetRate > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasRampUp(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Insert != nil && result.Insert.RampUp > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasVerify reports whether any insert run was verified against a row count.
+func hasVerify(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Insert != nil && result.Insert.Verify != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyLabel summarizes the row count check of an insert run: "ok", the
+// rows missing or extra, or why the rows could not be counted.
+func verifyLabel(v *benchmark.VerifyResult) string {
+	switch {
+	case v == nil:
+		return "-"
+	case v.ErrorText != "":
+		return v.ErrorText
+	case v.Missing > 0:
+		return fmt.Sprintf("%d missing", v.Missing)
+	case v.Extra > 0:
+		return fmt.Sprintf("%d extra", v.Extra)
+	default:
+		return "ok"
+	}
+}
+
+// targetRateLabel returns the paced insert rate, or "max" for an unpaced run.
+func targetRateLabel(rate float64) string {
+	if rate <= 0 {
+		return "max"
+	}
+
+	return fmt.Sprintf("%.0f/sec", rate)
+}
+
+// hasIndexes reports whether any result is labeled with the index set its
+// schema was created with.
+func hasIndexes(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Indexes != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indexSetLabel returns the index set of a result, or a dash for databases
+// without secondary indexes.
+func indexSetLabel(result *benchmark.Results) string {
+	if result.Indexes == "" {
+		return "-"
+	}
+
+	return result.Indexes
+}
+
+// hasInsertMode reports whether any result is labeled with the insert mode
+// its events were written in.
+func hasInsertMode(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.InsertMode != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// insertModeLabel returns the insert mode of a result, or a dash for
+// databases with a single one.
+func insertModeLabel(result *benchmark.Results) string {
+	if result.InsertMode == "" {
+		return "-"
+	}
+
+	return result.InsertMode
+}
+
+// hasScanThroughput reports whether a query measured scan throughput on any
+// database, which only the pagination and export scans do.
+func hasScanThroughput(results map[string]*benchmark.Results, queryName string) bool {
+	for _, result := range results {
+		if qr, ok := result.Queries[queryName]; ok && qr.Throughput > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasCorrected reports whether a paced run of the query recorded latencies
+// from the intended start on any database.
+func hasCorrected(results map[string]*benchmark.Results, queryName string) bool {
+	for _, result := range results {
+		if qr, ok := result.Queries[queryName]; ok && qr.CorrectedP99 > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasQueryWorkers reports whether the query ran on concurrent workers on
+// any database.
+func hasQueryWorkers(results map[string]*benchmark.Results, queryName string) bool {
+	for _, result := range results {
+		if qr, ok := result.Queries[queryName]; ok && qr.Workers > 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// queryColumns are the optional columns of a query table, shown when the
+// query has a value for them on any database.
+type queryColumns struct {
+	name       string
+	throughput bool
+	corrected  bool
+	workers    bool
+	ref        reference
+}
+
+func newQueryColumns(results map[string]*benchmark.Results, queryName string, ref reference) queryColumns {
+	return queryColumns{
+		name:       queryName,
+		throughput: hasScanThroughput(results, queryName),
+		corrected:  hasCorrected(results, queryName),
+		workers:    hasQueryWorkers(results, queryName),
+		ref:        ref,
+	}
+}
+
+// header appends the optional columns to the columns every query table has.
+func (c queryColumns) header(header table.Row) table.Row {
+	if c.throughput {
+		header = append(header, "Scan")
+	}
+
+	if c.corrected {
+		header = append(header, "Corrected P99")
+	}
+
+	if c.workers {
+		header = append(header, "Workers", "QPS")
+	}
+
+	if c.ref.set() {
+		header = append(header, c.ref.header())
+	}
+
+	return header
+}
+
+func (c queryColumns) row(db string, row table.Row, qr *benchmark.QueryResult) table.Row {
+	if c.throughput {
+		row = append(row, fmt.Sprintf("%.0f rows/sec", qr.Throughput))
+	}
+
+	if c.corrected {
+		row = append(row, qr.CorrectedP99.Round(time.Millisecond))
+	}
+
+	if c.workers {
+		row = append(row, max(qr.Workers, 1), fmt.Sprintf("%.1f", qr.QPS))
+	}
+
+	if c.ref.set() {
+		row = append(row, c.ref.query(db, c.name, qr))
+	}
+
+	return row
+}
+
+// errorsLabel returns the error count of a run, followed by its retries
+// when any attempt was retried and flagged when the run was aborted on its
+// error rate.
+func errorsLabel(errors, retries int64, aborted bool) any {
+	if retries == 0 && !aborted {
+		return errors
+	}
+
+	label := strconv.FormatInt(errors, 10)
+
+	if retries > 0 {
+		label += fmt.Sprintf(" (%d retried)", retries)
+	}
+
+	if aborted {
+		label += " (aborted)"
+	}
+
+	return label
+}
+
+// errorRow shows errorText in place of the metrics of a database, padded
+// with dashes to the given number of columns.
+func errorRow(db, errorText string, columns int) table.Row {
+	row := table.Row{db, errorText}
+	for len(row) < columns {
+		row = append(row, "-")
+	}
+
+	return row
+}
+
+func hasDurability(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if len(result.Durability) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// durabilityRows renders one row per database and durability level, with
+// throughput relative to the fastest level of the same database.
+func durabilityRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		var fastest float64
+
+		for _, dr := range results[db].Durability {
+			if dr.Insert != nil && dr.Insert.Throughput > fastest {
+				fastest = dr.Insert.Throughput
+			}
+		}
+
+		for _, dr := range results[db].Durability {
+			if dr.Insert == nil {
+				rows = append(rows, table.Row{db, dr.Level, "ERROR", "-", "-", dr.ErrorText})
+				continue
+			}
+
+			relative := "-"
+			if fastest > 0 {
+				relative = fmt.Sprintf("%.0f%%", dr.Insert.Throughput/fastest*100)
+			}
+
+			rows = append(rows, table.Row{
+				db,
+				dr.Level,
+				fmt.Sprintf("%.0f/sec", dr.Insert.Throughput),
+				relative,
+				dr.Insert.Duration.Round(time.Millisecond),
+				errorsLabel(dr.Insert.ErrorCount, dr.Insert.Retries, dr.Insert.Aborted),
+			})
+		}
+	}
+
+	return rows
+}
+
+func hasInsertModes(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if len(result.InsertModes) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// insertModeRows renders one row per database and insert mode, with
+// throughput relative to the fastest mode of the same database.
+func insertModeRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		var fastest float64
+
+		for _, mr := range results[db].InsertModes {
+			if mr.Insert != nil && mr.Insert.Throughput > fastest {
+				fastest = mr.Insert.Throughput
+			}
+		}
+
+		for _, mr := range results[db].InsertModes {
+			if mr.Insert == nil {
+				rows = append(rows, table.Row{db, mr.Mode, "ERROR", "-", "-", mr.ErrorText})
+				continue
+			}
+
+			relative := "-"
+			if fastest > 0 {
+				relative = fmt.Sprintf("%.0f%%", mr.Insert.Throughput/fastest*100)
+			}
+
+			rows = append(rows, table.Row{
+				db,
+				mr.Mode,
+				fmt.Sprintf("%.0f/sec", mr.Insert.Throughput),
+				relative,
+				mr.Insert.Duration.Round(time.Millisecond),
+				errorsLabel(mr.Insert.ErrorCount, mr.Insert.Retries, mr.Insert.Aborted),
+			})
+		}
+	}
+
+	return rows
+}
+
+var matrixHeader = table.Row{"Database", "Events", "Batch", "Workers", "Throughput", "Batch P99", "1_day P95"}
+
+func hasParams(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Params != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matrixRows compares the runs of a parameter matrix side by side: the insert
+// throughput and batch latency, and the one-day stats query as a read probe.
+func matrixRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, key := range databases {
+		result := results[key]
+		if result.Params == nil {
+			continue
+		}
+
+		rows = append(rows, matrixRow(result))
+	}
+
+	return rows
+}
+
+// matrixRow returns the row of a matrix run: its parameters, insert
+// throughput and P99, and 1_day query P95.
+func matrixRow(result *benchmark.Results) table.Row {
+	p := result.Params
+	row := table.Row{result.Database, p.Events, p.BatchSize, p.Workers, "-", "-", "-"}
+
+	switch {
+	case result.Error != nil:
+		row[4] = "ERROR: " + result.Error.Error()
+	case result.Insert != nil:
+		row[4] = fmt.Sprintf("%.0f/sec", result.Insert.Throughput)
+		row[5] = result.Insert.LatencyP99.Round(time.Millisecond)
+	}
+
+	if qr, ok := result.Queries["1_day"]; ok && qr.ErrorText == "" && qr.Iterations > 0 {
+		row[6] = qr.P95Duration.Round(time.Millisecond)
+	}
+
+	return row
+}
+
+var scalingHeader = table.Row{"Database", "Workers", "Throughput", "Speedup", "Efficiency", "Batch P99", "Errors", "Curve"}
+
+// scalingBarWidth is the length of the curve bar of the fastest worker count.
+const scalingBarWidth = 20
+
+func hasScaling(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if len(result.Scaling) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scalingRows renders one row per worker count of each database. Speedup is
+// the throughput relative to the first successful count of the sweep, and
+// efficiency that speedup per added worker: 100% is linear scaling. The
+// curve is a bar scaled to the database's highest throughput.
+func scalingRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		var base *benchmark.ScalingResult
+
+		var peak float64
+
+		for _, sr := range results[db].Scaling {
+			if sr.Insert != nil {
+				if base == nil {
+					base = sr
+				}
+
+				peak = max(peak, sr.Insert.Throughput)
+			}
+		}
+
+		for _, sr := range results[db].Scaling {
+			if sr.Insert == nil {
+				rows = append(rows, table.Row{db, sr.Workers, "ERROR", "-", "-", "-", "-", sr.ErrorText})
+				continue
+			}
+
+			rows = append(rows, scalingRow(db, sr, base, peak))
+		}
+	}
+
+	return rows
+}
+
+func scalingRow(db string, sr, base *benchmark.ScalingResult, peak float64) table.Row {
+	speedup, efficiency, bar := "-", "-", ""
+
+	if base.Insert.Throughput > 0 {
+		ratio := sr.Insert.Throughput / base.Insert.Throughput
+		speedup = fmt.Sprintf("%.2fx", ratio)
+		efficiency = fmt.Sprintf("%.0f%%", ratio/(float64(sr.Workers)/float64(base.Workers))*100)
+	}
+
+	if peak > 0 {
+		bar = strings.Repeat("█", int(sr.Insert.Throughput/peak*scalingBarWidth+0.5))
+	}
+
+	return table.Row{
+		db,
+		sr.Workers,
+		fmt.Sprintf("%.0f/sec", sr.Insert.Throughput),
+		speedup,
+		efficiency,
+		sr.Insert.LatencyP99.Round(time.Millisecond),
+		errorsLabel(sr.Insert.ErrorCount, sr.Insert.Retries, sr.Insert.Aborted),
+		bar,
+	}
+}
+
+var varianceHeader = table.Row{"Database", "Metric", "Runs", "Mean", "StdDev", "Min", "Max", "CV"}
+
+func hasRepeat(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Repeat != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// varianceRows renders the spread of the insert throughput and of the P95
+// latency of every query scenario across the repeated runs of a database.
+func varianceRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	rate := func(x float64) any { return fmt.Sprintf("%.0f/sec", x) }
+	latency := func(x float64) any { return time.Duration(x).Round(time.Microsecond) }
+
+	for _, db := range databases {
+		rr := results[db].Repeat
+		if rr == nil {
+			continue
+		}
+
+		if v := rr.Throughput; v != nil {
+			rows = append(rows, varianceRow(db, "insert throughput", v, rate))
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(rr.QueryP95)) {
+			rows = append(rows, varianceRow(db, name+" P95", rr.QueryP95[name], latency))
+		}
+	}
+
+	return rows
+}
+
+func varianceRow(db, metric string, v *benchmark.Variance, format func(float64) any) table.Row {
+	return table.Row{db, metric, v.Runs, format(v.Mean), format(v.StdDev), format(v.Min), format(v.Max), fmt.Sprintf("%.1f%%", v.CV*100)}
+}
+
+var batchTuningHeader = table.Row{"Database", "Batch Size", "Events", "Throughput", "vs Optimal", "Errors", "Optimal"}
+
+func hasBatchTuning(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.BatchTuning != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// batchTuningRows renders the probes of the batch size search of each
+// database in the order they ran, marking the batch size the search settled
+// on. A search that failed gets one row with its error.
+func batchTuningRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		bt := results[db].BatchTuning
+		if bt == nil {
+			continue
+		}
+
+		if bt.ErrorText != "" {
+			rows = append(rows, table.Row{db, fmt.Sprintf("%d-%d", bt.Min, bt.Max), "-", "-", "-", "-", "error: " + bt.ErrorText})
+		}
+
+		for _, p := range bt.Probes {
+			rows = append(rows, batchTuningRow(db, p, bt))
+		}
+	}
+
+	return rows
+}
+
+func batchTuningRow(db string, p benchmark.BatchProbe, bt *benchmark.BatchTuningResult) table.Row {
+	change, mark := "-", ""
+	if bt.Throughput > 0 {
+		change = fmt.Sprintf("%+.1f%%", (p.Throughput-bt.Throughput)/bt.Throughput*100)
+	}
+
+	if p.BatchSize == bt.Optimal {
+		change, mark = "-", "✓"
+	}
+
+	return table.Row{db, p.BatchSize, p.Events, fmt.Sprintf("%.0f/sec", p.Throughput), change, p.ErrorCount, mark}
+}
+
+var steadyStateHeader = table.Row{"Database", "Throughput", "Steady Throughput", "vs Overall", "Steady After", "Steady Seconds"}
+
+func hasSteadyState(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Insert != nil && result.Insert.SteadyState != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// steadyStateRows renders the overall and the steady-state throughput of
+// each insert run next to each other, and where the steady state began. A
+// run whose rate never settled within the tolerance says so.
+func steadyStateRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		ir := results[db].Insert
+		if ir == nil || ir.SteadyState == nil {
+			continue
+		}
+
+		overall, ss := fmt.Sprintf("%.0f/sec", ir.Throughput), ir.SteadyState
+		if !ss.Reached {
+			rows = append(rows, table.Row{db, overall, fmt.Sprintf("not within ±%g%%", ss.Tolerance), "-", "-", "-"})
+			continue
+		}
+
+		change := "-"
+		if ir.Throughput > 0 {
+			change = fmt.Sprintf("%+.1f%%", (ss.Throughput-ir.Throughput)/ir.Throughput*100)
+		}
+
+		rows = append(rows, table.Row{db, overall, fmt.Sprintf("%.0f/sec", ss.Throughput), change, ss.After, ss.Seconds})
+	}
+
+	return rows
+}
+
+var mixedHeader = table.Row{"Database", "Insert Throughput", "Idle P50", "Idle P95", "Load P50", "Load P95", "P95 Slowdown", "Query Errors"}
+
+func hasMixed(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Mixed != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mixedRows renders the query latency of each database before and during
+// the ingest; the per-second timelines are only in the JSON output.
+func mixedRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		m := results[db].Mixed
+		if m == nil {
+			continue
+		}
+
+		rows = append(rows, table.Row{
+			db,
+			fmt.Sprintf("%.0f/sec", m.Insert.Throughput),
+			m.Baseline.P50Duration.Round(time.Millisecond),
+			m.Baseline.P95Duration.Round(time.Millisecond),
+			m.UnderLoad.P50Duration.Round(time.Millisecond),
+			m.UnderLoad.P95Duration.Round(time.Millisecond),
+			slowdown(m.Baseline.P95Duration, m.UnderLoad.P95Duration),
+			m.Baseline.ErrorCount + m.UnderLoad.ErrorCount,
+		})
+	}
+
+	return rows
+}
+
+// sparklineWidth is the most characters a throughput sparkline takes; longer
+// timelines are averaged down to it.
+const sparklineWidth = 60
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// timelineRows renders the per-second insert throughput of each database as
+// a sparkline scaled to its own peak. Low leaves out the last second, which
+// is partial, unless it is the only one.
+func timelineRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		insert := results[db].Insert
+		if insert == nil || len(insert.Timeline) == 0 {
+			continue
+		}
+
+		events := make([]int64, len(insert.Timeline))
+		for i, sample := range insert.Timeline {
+			events[i] = sample.Events
+		}
+
+		full := events
+		if len(full) > 1 {
+			full = full[:len(full)-1]
+		}
+
+		rows = append(rows, table.Row{
+			db,
+			len(events),
+			fmt.Sprintf("%d/sec", slices.Max(events)),
+			fmt.Sprintf("%d/sec", slices.Min(full)),
+			sparkline(events, sparklineWidth),
+		})
+	}
+
+	return rows
+}
+
+// sparkline draws values as block characters from lowest (zero) to highest
+// (the peak), averaging adjacent values when there are more than width.
+func sparkline(values []int64, width int) string {
+	buckets := downsample(values, width)
+	peak := slices.Max(buckets)
+
+	var b strings.Builder
+
+	for _, v := range buckets {
+		level := 0
+		if peak > 0 {
+			level = int(v * float64(len(sparkLevels)-1) / peak)
+		}
+
+		b.WriteRune(sparkLevels[level])
+	}
+
+	return b.String()
+}
+
+// downsample averages values into at most width buckets.
+func downsample(values []int64, width int) []float64 {
+	n := min(len(values), width)
+	buckets := make([]float64, n)
+
+	for i := range n {
+		lo, hi := i*len(values)/n, (i+1)*len(values)/n
+
+		var sum int64
+		for _, v := range values[lo:hi] {
+			sum += v
+		}
+
+		buckets[i] = float64(sum) / float64(hi-lo)
+	}
+
+	return buckets
+}
+
+// slowdown formats the ratio of a latency to its baseline, such as the
+// loaded to the idle latency.
+func slowdown(base, d time.Duration) string {
+	if base <= 0 || d <= 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.1fx", float64(d)/float64(base))
+}
+
+var workerHeader = table.Row{"Database", "Worker", "Events", "Share", "Batches", "Errors", "P50", "P99", "Max"}
+
+func hasWorkerStats(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Insert != nil && len(result.Insert.Workers) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// workerRows renders one row per insert worker of each database. Share is
+// the worker's part of the events the run inserted, so a worker held back
+// by a slow connection stands out below 100/workers percent.
+func workerRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		if results[db].Insert == nil {
+			continue
+		}
+
+		workers := results[db].Insert.Workers
+
+		var total int64
+		for _, w := range workers {
+			total += w.Events
+		}
+
+		for _, w := range workers {
+			share := "-"
+			if total > 0 {
+				share = fmt.Sprintf("%.1f%%", float64(w.Events)/float64(total)*100)
+			}
+
+			rows = append(rows, table.Row{
+				db, w.Worker, w.Events, share, w.Batches, w.Errors,
+				w.LatencyP50.Round(time.Microsecond), w.LatencyP99.Round(time.Microsecond), w.LatencyMax.Round(time.Microsecond),
+			})
+		}
+	}
+
+	return rows
+}
+
+var clientHeader = table.Row{"Database", "Phase", "CPU Time", "CPU", "Cores", "Peak RSS", "Allocated", "GC Cycles", "GC Pause"}
+
+// clientRows renders the client process load of each phase of each database.
+// The query scenarios are added up into one queries phase.
+func clientRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		result := results[db]
+
+		var queries *benchmark.ClientResources
+		for _, name := range slices.Sorted(maps.Keys(result.Queries)) {
+			queries = queries.Add(result.Queries[name].Client)
+		}
+
+		phases := []struct {
+			name   string
+			client *benchmark.ClientResources
+		}{
+			{"insert", insertClient(result.Insert)},
+			{"queries", queries},
+			{"mixed", mixedClient(result.Mixed)},
+			{"transactions", transactionClient(result.Transactions)},
+		}
+
+		for _, p := range phases {
+			if c := p.client; c != nil {
+				rows = append(rows, table.Row{
+					db, p.name, c.CPUTime.Round(time.Millisecond), fmt.Sprintf("%.0f%%", c.CPUPct), c.Cores,
+					formatBytes(c.PeakRSS), formatBytes(int64(c.AllocBytes)), c.GCCycles, c.GCPause.Round(time.Microsecond),
+				})
+			}
+		}
+	}
+
+	return rows
+}
+
+func insertClient(ir *benchmark.InsertResult) *benchmark.ClientResources {
+	if ir == nil {
+		return nil
+	}
+
+	return ir.Client
+}
+
+func mixedClient(mr *benchmark.MixedResult) *benchmark.ClientResources {
+	if mr == nil {
+		return nil
+	}
+
+	return insertClient(mr.Insert)
+}
+
+func transactionClient(tr *benchmark.TransactionResult) *benchmark.ClientResources {
+	if tr == nil {
+		return nil
+	}
+
+	return tr.Client
+}
+
+var transactionHeader = table.Row{"Database", "Transactions", "Duration", "Throughput", "Errors", "Workers"}
+
+func hasTransactions(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Transactions != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// transactionRows renders one row per database that ran the transactional
+// workload; unsupported and failed runs show the error in place of a count.
+func transactionRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		tr := results[db].Transactions
+		if tr == nil {
+			continue
+		}
+
+		if tr.ErrorText != "" {
+			rows = append(rows, table.Row{db, tr.ErrorText, "-", "-", "-", "-"})
+			continue
+		}
+
+		rows = append(rows, table.Row{
+			db,
+			tr.Transactions,
+			tr.Duration.Round(time.Millisecond),
+			fmt.Sprintf("%.0f/sec", tr.Throughput),
+			tr.ErrorCount,
+			tr.WorkerCount,
+		})
+	}
+
+	return rows
+}
+
+var parityHeader = table.Row{"Database", "Window", "Expected", "Counted", "Status"}
+
+func hasParity(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Parity != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parityRows renders one row per parity window of each database, with the
+// event types whose counts differ from the dataset in the status. Failed
+// dataset batches get a row of their own, since they skew every window.
+func parityRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		p := results[db].Parity
+		if p == nil {
+			continue
+		}
+
+		if p.ErrorText != "" {
+			rows = append(rows, table.Row{db, p.ErrorText, "-", "-", "-"})
+			continue
+		}
+
+		if p.FailedBatches > 0 {
+			rows = append(rows, table.Row{db, "insert", p.Events, "-", fmt.Sprintf("%d batches failed", p.FailedBatches)})
+		}
+
+		for _, w := range p.Windows {
+			rows = append(rows, parityWindowRow(db, w))
+		}
+	}
+
+	return rows
+}
+
+func parityWindowRow(db string, w *benchmark.ParityWindow) table.Row {
+	expected := sumCounts(w.Expected)
+
+	switch {
+	case w.ErrorText != "":
+		return table.Row{db, w.Name, expected, "-", w.ErrorText}
+	case len(w.Mismatched) > 0:
+		return table.Row{db, w.Name, expected, sumCounts(w.Counted), "mismatch: " + strings.Join(w.Mismatched, ", ")}
+	default:
+		return table.Row{db, w.Name, expected, sumCounts(w.Counted), "ok"}
+	}
+}
+
+func sumCounts(counts map[string]int64) int64 {
+	var total int64
+	for _, n := range counts {
+		total += n
+	}
+
+	return total
+}
+
+var readAfterWriteHeader = table.Row{"Database", "Samples", "Immediate", "Delayed", "Missing", "Errors", "Stale", "Lag P50", "Lag P99", "Lag Max"}
+
+func hasReadAfterWrite(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Visibility != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readAfterWriteRows renders one row per database that sampled read-after-write
+// visibility. The lag columns cover the delayed samples only and show "-"
+// when every sample was visible on its first read.
+func readAfterWriteRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		v := results[db].Visibility
+		if v == nil {
+			continue
+		}
+
+		if v.ErrorText != "" {
+			rows = append(rows, table.Row{db, v.ErrorText, "-", "-", "-", "-", "-", "-", "-", "-"})
+			continue
+		}
+
+		lag := func(d time.Duration) any {
+			if v.Delayed == 0 {
+				return "-"
+			}
+
+			return d.Round(time.Microsecond)
+		}
+
+		rows = append(rows, table.Row{
+			db,
+			v.Samples,
+			v.Immediate,
+			v.Delayed,
+			fmt.Sprintf("%d (%.1f%%)", v.Missing, v.MissingPct),
+			v.ErrorCount,
+			fmt.Sprintf("%.1f%%", v.StalePct),
+			lag(v.LagP50),
+			lag(v.LagP99),
+			lag(v.LagMax),
+		})
+	}
+
+	return rows
+}
+
+var indexBuildHeader = table.Row{
+	"Database", "Deferred Load", "Indexed Load", "Load Speedup", "Index Build", "Events Indexed", "Build Rate", "Index Size",
+}
+
+func hasIndexBuild(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.IndexBuild != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indexBuildRows renders one row per database that deferred its indexes:
+// the load without and with them, and the build that followed.
+func indexBuildRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		if results[db].IndexBuild != nil {
+			rows = append(rows, indexBuildRow(db, results[db]))
+		}
+	}
+
+	return rows
+}
+
+func indexBuildRow(db string, result *benchmark.Results) table.Row {
+	ib := result.IndexBuild
+	if ib.ErrorText != "" {
+		return table.Row{db, ib.ErrorText, "-", "-", "-", "-", "-", "-"}
+	}
+
+	var deferredRate, indexedRate float64
+
+	deferred, indexed := "-", "-"
+
+	if result.Insert != nil {
+		deferredRate = result.Insert.Throughput
+		deferred = fmt.Sprintf("%.0f/sec", deferredRate)
+	}
+
+	if ib.Indexed != nil {
+		indexedRate = ib.Indexed.Throughput
+		indexed = fmt.Sprintf("%.0f/sec", indexedRate)
+	}
+
+	return table.Row{
+		db,
+		deferred,
+		indexed,
+		speedup(deferredRate, indexedRate),
+		ib.Duration.Round(time.Millisecond),
+		ib.Rows,
+		fmt.Sprintf("%.0f/sec", ib.Throughput),
+		formatBytes(ib.IndexSize),
+	}
+}
+
+var retentionHeader = table.Row{"Database", "Method", "Rows Deleted", "Duration", "Throughput", "Size Before", "Size After", "Reclaimed"}
+
+func hasRetention(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Retention != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// retentionRows renders one row per database that ran the retention
+// benchmark; unsupported and failed runs show the error in place of a method.
+func retentionRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		rr := results[db].Retention
+		if rr == nil {
+			continue
+		}
+
+		if rr.ErrorText != "" {
+			rows = append(rows, table.Row{db, rr.ErrorText, "-", "-", "-", "-", "-", "-"})
+			continue
+		}
+
+		rows = append(rows, table.Row{
+			db,
+			rr.Method,
+			rr.RowsDeleted,
+			rr.Duration.Round(time.Millisecond),
+			fmt.Sprintf("%.0f/sec", rr.Throughput),
+			formatBytes(rr.SizeBefore),
+			formatBytes(rr.SizeAfter),
+			formatBytes(rr.ReclaimedBytes),
+		})
+	}
+
+	return rows
+}
+
+var ttlHeader = table.Row{
+	"Database", "Method", "TTL", "Insert Throughput", "Insert Overhead", "Expire Time", "Rows Before", "Rows After", "Size Before", "Size After",
+}
+
+func hasTTL(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.TTL != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ttlRows renders one row per database that ran the TTL benchmark. A failed
+// expiry still shows the insert run, with the error in the Expire Time column.
+func ttlRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		tr := results[db].TTL
+		if tr == nil {
+			continue
+		}
+
+		if tr.Insert == nil {
+			rows = append(rows, table.Row{db, tr.ErrorText, "-", "-", "-", "-", "-", "-", "-", "-"})
+			continue
+		}
+
+		expire := tr.ExpireDuration.Round(time.Millisecond).String()
+		if tr.ErrorText != "" {
+			expire = tr.ErrorText
+		}
+
+		rows = append(rows, table.Row{
+			db,
+			tr.Method,
+			tr.TTL,
+			fmt.Sprintf("%.0f/sec", tr.Insert.Throughput),
+			fmt.Sprintf("%+.1f%%", tr.InsertOverhead),
+			expire,
+			tr.RowsBefore,
+			tr.RowsAfter,
+			formatBytes(tr.SizeBefore),
+			formatBytes(tr.SizeAfter),
+		})
+	}
+
+	return rows
+}
+
+var cacheHeader = table.Row{"Database", "Query", "Cold", "Warm P50", "Cold / Warm"}
+
+func hasCache(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if result.Cache != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cacheRows renders one row per query of every database that ran the cache
+// comparison.
+func cacheRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		cr := results[db].Cache
+
+		switch {
+		case cr == nil:
+		case cr.ErrorText != "":
+			rows = append(rows, table.Row{db, cr.ErrorText, "-", "-", "-"})
+		default:
+			rows = append(rows, cacheQueryRows(db, cr)...)
+		}
+	}
+
+	return rows
+}
+
+// cacheQueryRows compares the single cold run of each query with its warm
+// median, skipping queries that failed or did not run in either phase.
+func cacheQueryRows(db string, cr *benchmark.CacheResult) []table.Row {
+	names := make([]string, 0, len(cr.Cold))
+	for name := range cr.Cold {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var rows []table.Row
+
+	for _, name := range names {
+		cold, warm := cr.Cold[name], cr.Warm[name]
+		if cold.Iterations == 0 || warm == nil || warm.Iterations == 0 {
+			continue
+		}
+
+		rows = append(rows, table.Row{
+			db,
+			name,
+			cold.AvgDuration.Round(time.Millisecond),
+			warm.P50Duration.Round(time.Millisecond),
+			slowdown(warm.P50Duration, cold.AvgDuration),
+		})
+	}
+
+	return rows
+}
+
+// sortedKeys orders the results by key, and the runs of a parameter matrix
+// of one database by their events, batch size and worker count.
+var baselineHeader = table.Row{"Database", "Metric", "Baseline", "Current", "Change", "Status"}
+
+func hasBaseline(results map[string]*benchmark.Results) bool {
+	for _, result := range results {
+		if len(result.Baseline) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// baselineRows renders one row per metric compared with the baseline run.
+// The status names the threshold a regressed metric crossed.
+func baselineRows(databases []string, results map[string]*benchmark.Results) []table.Row {
+	var rows []table.Row
+
+	for _, db := range databases {
+		for _, d := range results[db].Baseline {
+			status := "ok"
+			if d.Regressed {
+				status = fmt.Sprintf("REGRESSION (>%g%%)", d.Threshold)
+			}
+
+			rows = append(rows, table.Row{
+				db, d.Metric, FormatMetric(d.Kind, d.Baseline), FormatMetric(d.Kind, d.Current), fmt.Sprintf("%+.1f%%", d.ChangePct), status,
+			})
+		}
+	}
+
+	return rows
+}
+
+// FormatMetric renders a value of a metric of the given kind, as Results.Metric
+// returns it, in its unit.
+func FormatMetric(kind string, v float64) string {
+	switch kind {
+	case benchmark.KindLatency:
+		return time.Duration(v).Round(time.Microsecond).String()
+	case benchmark.KindStorage:
+		return formatBytes(int64(v))
+	default:
+		return fmt.Sprintf("%.0f/sec", v)
+	}
+}
+
+func sortedKeys(results map[string]*benchmark.Results) []string {
+	databases := make([]string, 0, len(results))
+
+	for db := range results {
+		databases = append(databases, db)
+	}
+
+	sort.Slice(databases, func(i, j int) bool {
+		a, b := results[databases[i]], results[databases[j]]
+		if a.Params == nil || b.Params == nil || a.Database != b.Database {
+			return databases[i] < databases[j]
+		}
+
+		return paramsLess(a.Params, b.Params)
+	})
+
+	return databases
+}
+
+func paramsLess(a, b *benchmark.RunParams) bool {
+	if a.Events != b.Events {
+		return a.Events < b.Events
+	}
+
+	if a.BatchSize != b.BatchSize {
+		return a.BatchSize < b.BatchSize
+	}
+
+	return a.Workers < b.Workers
 }
 
 func sortedQueryNames(results map[string]*benchmark.Results) []string {
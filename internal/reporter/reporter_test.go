@@ -77,10 +77,19 @@ func TestPrintJSON(t *testing.T) {
 
 	err := json.Unmarshal(output, &parsed)
 	require.NoError(t, err, "output should be valid JSON")
+	assert.InDelta(t, benchmark.SchemaVersion, parsed["schema_version"], 0)
 
-	pg, ok := parsed["postgres"].(map[string]any)
+	results, ok := parsed["results"].(map[string]any)
+	require.True(t, ok)
+
+	pg, ok := results["postgres"].(map[string]any)
 	require.True(t, ok)
 	assert.Equal(t, "postgres", pg["database"])
+
+	insert, ok := pg["insert"].(map[string]any)
+	require.True(t, ok)
+	assert.InDelta(t, 5e9, insert["duration"], 0)
+	assert.Equal(t, "5s", insert["duration_human"])
 }
 
 func TestPrintMarkdown(t *testing.T) {
@@ -127,3 +136,1101 @@ func TestFormatBytes(t *testing.T) {
 		assert.Equal(t, tt.expected, formatBytes(tt.bytes), "formatBytes(%d)", tt.bytes)
 	}
 }
+
+func TestFormatDetails(t *testing.T) {
+	details := map[string]int64{"tablets": 3, "sst_bytes": 2048}
+
+	assert.Equal(t, "sst_bytes=2.00 KB, tablets=3", formatDetails(details))
+}
+
+func TestPrintStorageDetails(t *testing.T) {
+	results := sampleResults()
+	results["postgres"].Storage.Details = map[string]int64{"tablets": 3}
+
+	var buf bytes.Buffer
+
+	rep := New("table", &buf)
+	rep.PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "Details")
+	assert.Contains(t, output, "tablets=3")
+}
+
+func TestPrintDurabilityMatrix(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Durability: []*benchmark.DurabilityResult{
+				{Level: "synchronous_commit=off", Insert: &benchmark.InsertResult{Throughput: 400, Duration: time.Second}},
+				{Level: "synchronous_commit=on", Insert: &benchmark.InsertResult{Throughput: 100, Duration: 4 * time.Second}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "DURABILITY MATRIX")
+	assert.Contains(t, output, "synchronous_commit=off")
+	assert.Contains(t, output, "100%")
+	assert.Contains(t, output, "25%")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "DURABILITY MATRIX")
+}
+
+func TestPrintInsertModes(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"cassandra": {
+			Database: "cassandra",
+			InsertModes: []*benchmark.InsertModeResult{
+				{Mode: "concurrent", Insert: &benchmark.InsertResult{Throughput: 400, Duration: time.Second}},
+				{Mode: "sequential", Insert: &benchmark.InsertResult{Throughput: 100, Duration: 4 * time.Second}},
+				{Mode: "batch", ErrorText: "batch too large"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "INSERT MODES")
+	assert.Contains(t, output, "sequential")
+	assert.Contains(t, output, "25%")
+	assert.Contains(t, output, "batch too large")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Insert Modes")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "INSERT MODES")
+}
+
+func TestPrintIndexBuild(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Insert:   &benchmark.InsertResult{Throughput: 480, Duration: time.Second},
+			IndexBuild: &benchmark.IndexBuildResult{
+				Indexed:    &benchmark.InsertResult{Throughput: 200},
+				Duration:   2 * time.Second,
+				Rows:       1000,
+				Throughput: 500,
+				IndexSize:  2 * 1024 * 1024,
+			},
+		},
+		"mongodb": {Database: "mongodb", IndexBuild: &benchmark.IndexBuildResult{ErrorText: "not supported"}},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "DEFERRED INDEX BUILD")
+	assert.Contains(t, output, "2.4× faster")
+	assert.Contains(t, output, "2s")
+	assert.Contains(t, output, "not supported")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Deferred Index Build")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "DEFERRED INDEX BUILD")
+}
+
+func TestInsertModeColumn(t *testing.T) {
+	results := sampleResults()
+	results["postgres"].InsertMode = "values"
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "Insert Mode")
+	assert.Contains(t, buf.String(), "values")
+}
+
+func TestPrintRetention(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Retention: &benchmark.RetentionResult{
+				Method:         "drop_partition+delete",
+				RowsDeleted:    1000,
+				Duration:       time.Second,
+				Throughput:     1000,
+				SizeBefore:     2 * 1024 * 1024,
+				SizeAfter:      1024 * 1024,
+				ReclaimedBytes: 1024 * 1024,
+			},
+		},
+		"redis": {Database: "redis", Retention: &benchmark.RetentionResult{ErrorText: "not supported"}},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "RETENTION BENCHMARK")
+	assert.Contains(t, output, "drop_partition+delete")
+	assert.Contains(t, output, "1000/sec")
+	assert.Contains(t, output, "not supported")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Retention")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "RETENTION BENCHMARK")
+}
+
+func TestPrintInsertDuplicates(t *testing.T) {
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "Duplicates")
+
+	results := sampleResults()
+	for _, result := range results {
+		if result.Insert != nil {
+			result.Insert.Duplicates = 4242
+		}
+	}
+
+	buf.Reset()
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "Duplicates")
+	assert.Contains(t, buf.String(), "4242")
+}
+
+func TestPrintScanThroughput(t *testing.T) {
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "rows/sec")
+
+	results := sampleResults()
+	results["postgres"].Queries["pagination_1_day"] = &benchmark.QueryResult{
+		QueryName:  "pagination_1_day",
+		Iterations: 100,
+		Rows:       100000,
+		Throughput: 250000,
+	}
+
+	buf.Reset()
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "250000 rows/sec")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "250000 rows/sec")
+}
+
+func TestPrintTransactions(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Transactions: &benchmark.TransactionResult{
+				Transactions: 5000,
+				Duration:     2 * time.Second,
+				Throughput:   2500,
+				WorkerCount:  4,
+			},
+		},
+		"clickhouse": {Database: "clickhouse", Transactions: &benchmark.TransactionResult{ErrorText: "not supported"}},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "TRANSACTION BENCHMARK")
+	assert.Contains(t, output, "2500/sec")
+	assert.Contains(t, output, "not supported")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Transactions")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "TRANSACTION BENCHMARK")
+}
+
+func TestPrintWorkers(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Insert: &benchmark.InsertResult{
+				TotalEvents: 1000,
+				Workers: []benchmark.WorkerStats{
+					{Worker: 0, Events: 750, Batches: 75, LatencyP50: 4 * time.Millisecond},
+					{Worker: 1, Events: 250, Batches: 25, Errors: 3, LatencyP50: 30 * time.Millisecond},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	assert.NotContains(t, buf.String(), "INSERT WORKERS", "the breakdown is verbose output only")
+
+	buf.Reset()
+
+	rep := New("table", &buf)
+	rep.SetVerbose(true)
+	rep.PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "INSERT WORKERS")
+	assert.Contains(t, output, "75.0%")
+	assert.Contains(t, output, "25.0%")
+	assert.Contains(t, output, "30ms")
+
+	buf.Reset()
+
+	rep = New("markdown", &buf)
+	rep.SetVerbose(true)
+	rep.PrintResults(results)
+	assert.Contains(t, buf.String(), "## Insert Workers")
+}
+
+func TestPrintClientResources(t *testing.T) {
+	client := &benchmark.ClientResources{CPUTime: 1500 * time.Millisecond, CPUPct: 92, Cores: 8, PeakRSS: 256 << 20, AllocBytes: 1 << 30, GCCycles: 40}
+
+	results := map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Insert:   &benchmark.InsertResult{TotalEvents: 1000, Client: client},
+			Queries: map[string]*benchmark.QueryResult{
+				"1_hour": {QueryName: "1_hour", Client: &benchmark.ClientResources{Wall: time.Second, CPUTime: time.Second, Cores: 8, GCCycles: 1}},
+				"1_day":  {QueryName: "1_day", Client: &benchmark.ClientResources{Wall: time.Second, CPUTime: time.Second, Cores: 8, GCCycles: 2}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	assert.NotContains(t, buf.String(), "CLIENT RESOURCES")
+
+	buf.Reset()
+
+	rep := New("table", &buf)
+	rep.SetVerbose(true)
+	rep.PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "CLIENT RESOURCES")
+	assert.Contains(t, output, "92%")
+	assert.Contains(t, output, "12%", "the scenarios add up into one queries row")
+	assert.Contains(t, output, "queries")
+}
+
+func TestPrintParity(t *testing.T) {
+	window := func(name string, counted int64, mismatched ...string) *benchmark.ParityWindow {
+		return &benchmark.ParityWindow{
+			Name:       name,
+			Expected:   map[string]int64{"login": 40, "search": 60},
+			Counted:    map[string]int64{"login": 40, "search": counted},
+			Mismatched: mismatched,
+		}
+	}
+
+	results := map[string]*benchmark.Results{
+		"postgres":  {Database: "postgres", Parity: &benchmark.ParityResult{Events: 100, Windows: []*benchmark.ParityWindow{window("1_day", 60)}}},
+		"cassandra": {Database: "cassandra", Parity: &benchmark.ParityResult{Events: 100, Windows: []*benchmark.ParityWindow{window("1_day", 75, "search")}}},
+		"redis":     {Database: "redis", Parity: &benchmark.ParityResult{Events: 100, FailedBatches: 2}},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "QUERY RESULT PARITY")
+	assert.Contains(t, output, "mismatch: search")
+	assert.Contains(t, output, "115")
+	assert.Contains(t, output, "2 batches failed")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Query Result Parity")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "PARITY")
+}
+
+func TestPrintReadAfterWrite(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"cassandra": {
+			Database: "cassandra",
+			Visibility: &benchmark.ReadAfterWriteResult{
+				Samples:    200,
+				Immediate:  190,
+				Delayed:    8,
+				Missing:    2,
+				StalePct:   5,
+				MissingPct: 1,
+				LagP50:     12 * time.Millisecond,
+				LagP99:     40 * time.Millisecond,
+				LagMax:     40 * time.Millisecond,
+			},
+		},
+		"postgres": {Database: "postgres", Visibility: &benchmark.ReadAfterWriteResult{Samples: 200, Immediate: 200}},
+		"redis":    {Database: "redis", Visibility: &benchmark.ReadAfterWriteResult{ErrorText: "not supported"}},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "READ-AFTER-WRITE VISIBILITY")
+	assert.Contains(t, output, "2 (1.0%)")
+	assert.Contains(t, output, "5.0%")
+	assert.Contains(t, output, "12ms")
+	assert.Contains(t, output, "not supported")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Read-After-Write Visibility")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "READ-AFTER-WRITE")
+}
+
+func TestPrintMixed(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Mixed: &benchmark.MixedResult{
+				Insert:    &benchmark.InsertResult{Throughput: 5000},
+				Baseline:  &benchmark.QueryResult{P50Duration: 8 * time.Millisecond, P95Duration: 10 * time.Millisecond},
+				UnderLoad: &benchmark.QueryResult{P50Duration: 20 * time.Millisecond, P95Duration: 35 * time.Millisecond},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "READ WHILE WRITE")
+	assert.Contains(t, output, "5000/sec")
+	assert.Contains(t, output, "3.5x")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Read While Write")
+
+	assert.Equal(t, "-", slowdown(0, time.Second))
+}
+
+func TestPrintTTL(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"clickhouse": {
+			Database: "clickhouse",
+			TTL: &benchmark.TTLResult{
+				TTL:            7 * 24 * time.Hour,
+				Method:         "ttl_clause",
+				Insert:         &benchmark.InsertResult{Throughput: 90000},
+				InsertOverhead: 10,
+				RowsBefore:     2000,
+				RowsAfter:      800,
+				SizeBefore:     4096,
+				SizeAfter:      2048,
+			},
+		},
+		"redis": {Database: "redis", TTL: &benchmark.TTLResult{ErrorText: "not supported"}},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "TTL BENCHMARK")
+	assert.Contains(t, output, "90000/sec")
+	assert.Contains(t, output, "+10.0%")
+	assert.Contains(t, output, "not supported")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## TTL Expiry")
+}
+
+func TestPrintUnsupportedQuery(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Queries: map[string]*benchmark.QueryResult{
+				"sessions_1_day": {QueryName: "sessions_1_day", Iterations: 5, AvgDuration: 40 * time.Millisecond},
+			},
+		},
+		"cassandra": {
+			Database: "cassandra",
+			Queries: map[string]*benchmark.QueryResult{
+				"sessions_1_day": {QueryName: "sessions_1_day", ErrorText: "not supported"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "not supported")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "| cassandra | not supported | - | - | - | - |")
+}
+
+func TestPrintCache(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Cache: &benchmark.CacheResult{
+				Cold: map[string]*benchmark.QueryResult{
+					"1_day":          {Iterations: 1, AvgDuration: 120 * time.Millisecond},
+					"sessions_1_day": {ErrorText: "not supported"},
+				},
+				Warm: map[string]*benchmark.QueryResult{
+					"1_day": {Iterations: 5, P50Duration: 10 * time.Millisecond},
+				},
+			},
+		},
+		"mongodb": {Database: "mongodb", Cache: &benchmark.CacheResult{ErrorText: "failed to restart mongodb"}},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "COLD VS WARM CACHE")
+	assert.Contains(t, output, "12.0x")
+	assert.Contains(t, output, "failed to restart mongodb")
+	assert.NotContains(t, output, "sessions_1_day")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Cold vs Warm Cache")
+}
+
+func TestPrintIndexSet(t *testing.T) {
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "Indexes")
+
+	results := sampleResults()
+	results["postgres"].Indexes = "minimal"
+
+	buf.Reset()
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "Indexes")
+	assert.Contains(t, buf.String(), "minimal")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "| minimal")
+}
+
+func TestPrintTargetRate(t *testing.T) {
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "Target")
+
+	results := sampleResults()
+	results["postgres"].Insert.TargetRate = 5000
+	results["postgres"].Insert.LatencyP99 = 42 * time.Millisecond
+	results["postgres"].Insert.CorrectedP99 = 314 * time.Millisecond
+
+	buf.Reset()
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "Batch P99")
+	assert.Contains(t, buf.String(), "5000/sec")
+	assert.Contains(t, buf.String(), "42ms")
+	assert.Contains(t, buf.String(), "314ms")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "5000/sec")
+	assert.Contains(t, buf.String(), "314ms")
+}
+
+func TestPrintCorrectedQueryLatency(t *testing.T) {
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "Corrected P99")
+
+	results := sampleResults()
+	for _, qr := range results["postgres"].Queries {
+		qr.CorrectedP99 = 271 * time.Millisecond
+	}
+
+	buf.Reset()
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "Corrected P99")
+	assert.Contains(t, buf.String(), "271ms")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "271ms")
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▄█", sparkline([]int64{0, 50, 100}, 10))
+	assert.Equal(t, "▁▁", sparkline([]int64{0, 0}, 10))
+	// Six values averaged into three buckets: 0, 50 and 100.
+	assert.Equal(t, "▁▄█", sparkline([]int64{0, 0, 40, 60, 100, 100}, 3))
+}
+
+func TestPrintInsertTimeline(t *testing.T) {
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "INSERT THROUGHPUT TIMELINE")
+
+	results := sampleResults()
+	results["postgres"].Insert.Timeline = []benchmark.InsertSample{
+		{Second: 0, Events: 900}, {Second: 1, Events: 100}, {Second: 2, Events: 50},
+	}
+
+	buf.Reset()
+	New("table", &buf).PrintResults(results)
+	output := buf.String()
+	assert.Contains(t, output, "INSERT THROUGHPUT TIMELINE")
+	assert.Contains(t, output, "900/sec")
+	assert.Contains(t, output, "100/sec")
+	assert.Contains(t, output, "█")
+}
+
+func TestPrintScaling(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Scaling: []*benchmark.ScalingResult{
+				{Workers: 1, Insert: &benchmark.InsertResult{Throughput: 1000}},
+				{Workers: 2, Insert: &benchmark.InsertResult{Throughput: 1500}},
+				{Workers: 4, ErrorText: "too many connections"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	output := buf.String()
+	assert.Contains(t, output, "WORKER SCALING")
+	assert.Contains(t, output, "1.50x")
+	assert.Contains(t, output, "75%")
+	assert.Contains(t, output, strings.Repeat("█", 20))
+	assert.Contains(t, output, "too many connections")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Worker Scaling")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "WORKER SCALING")
+}
+
+func TestPrintAborted(t *testing.T) {
+	results := sampleResults()
+	results["postgres"].Insert.ErrorCount = 12
+	results["postgres"].Insert.Aborted = true
+	results["postgres"].MarkDegraded()
+	results["postgres"].Insert.Retries = 7
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "12 (7 retried) (aborted)")
+
+	buf.Reset()
+	New("json", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), `"degraded": true`)
+}
+
+func TestPrintRampUp(t *testing.T) {
+	results := sampleResults()
+	results["postgres"].Insert.RampUp = 5 * time.Second
+	results["postgres"].Insert.RampEvents = 12000
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	output := buf.String()
+	assert.Contains(t, output, "Ramp-up")
+	assert.Contains(t, output, "12000")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "Ramp-up")
+}
+
+func TestPrintInterrupted(t *testing.T) {
+	results := sampleResults()
+	results["postgres"].Interrupted = true
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "INTERRUPTED: partial results for postgres")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "**INTERRUPTED: partial results for postgres**")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "INTERRUPTED")
+}
+
+func TestPrintWarmup(t *testing.T) {
+	results := sampleResults()
+	results["postgres"].Warmup = &benchmark.WarmupConfig{QueryIterations: 5, InsertBatches: 3}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "Warm-up: 3 batches before each insert run and 5 runs of each query scenario")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "_Warm-up: 3 batches")
+
+	buf.Reset()
+	New("json", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), `"insert_batches": 3`)
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "Warm-up")
+}
+
+func TestPrintVariance(t *testing.T) {
+	results := sampleResults()
+	results["postgres"].Repeat = benchmark.SummarizeRuns([]*benchmark.Results{
+		{Insert: &benchmark.InsertResult{Throughput: 900}},
+		{Insert: &benchmark.InsertResult{Throughput: 1100}},
+	})
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	output := buf.String()
+	assert.Contains(t, output, "RUN-TO-RUN VARIANCE")
+	assert.Contains(t, output, "insert throughput")
+	assert.Contains(t, output, "1000/sec")
+	assert.Contains(t, output, "14.1%")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Run-to-Run Variance")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "RUN-TO-RUN VARIANCE")
+}
+
+func TestPrintMatrix(t *testing.T) {
+	results := make(map[string]*benchmark.Results)
+
+	for _, w := range []int{16, 2} {
+		params := &benchmark.RunParams{Events: 1000, BatchSize: 100, Workers: w}
+		results["sqlite "+params.String()] = &benchmark.Results{
+			Database: "sqlite",
+			Params:   params,
+			Insert:   &benchmark.InsertResult{Throughput: float64(w * 100)},
+		}
+	}
+
+	keys := sortedKeys(results)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "sqlite events=1000 batch=100 workers=2", keys[0])
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	output := buf.String()
+	assert.Contains(t, output, "PARAMETER MATRIX")
+	assert.Contains(t, output, "1600/sec")
+	assert.Less(t, strings.Index(output, "200/sec"), strings.Index(output, "1600/sec"))
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Parameter Matrix")
+}
+
+func TestPrintQueryWorkers(t *testing.T) {
+	results := sampleResults()
+	for _, qr := range results["postgres"].Queries {
+		qr.Workers, qr.QPS = 8, 1234.5
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	output := buf.String()
+	assert.Contains(t, output, "QPS")
+	assert.Contains(t, output, "1234.5")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "| QPS")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "QPS")
+}
+
+func TestPrintVerify(t *testing.T) {
+	results := sampleResults()
+	results["postgres"].Insert.Verify = &benchmark.VerifyResult{Inserted: 1000, Counted: 990, Missing: 10}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	output := buf.String()
+	assert.Contains(t, output, "Verified")
+	assert.Contains(t, output, "10 missing")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "10 missing")
+
+	buf.Reset()
+	New("json", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), `"missing": 10`)
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "Verified")
+}
+
+func TestVerifyLabel(t *testing.T) {
+	assert.Equal(t, "ok", verifyLabel(&benchmark.VerifyResult{Inserted: 10, Counted: 10}))
+	assert.Equal(t, "3 extra", verifyLabel(&benchmark.VerifyResult{Inserted: 10, Counted: 13, Extra: 3}))
+	assert.Equal(t, "not supported", verifyLabel(&benchmark.VerifyResult{ErrorText: "not supported"}))
+	assert.Equal(t, "-", verifyLabel(nil))
+}
+
+func TestPrintBaseline(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {
+			Database: "postgres",
+			Baseline: []benchmark.BaselineDelta{
+				{Metric: "insert throughput", Kind: benchmark.KindThroughput, Baseline: 100_000, Current: 80_000, ChangePct: -20, Threshold: 10, Regressed: true},
+				{
+					Metric: "1_day P95", Kind: benchmark.KindLatency,
+					Baseline: float64(10 * time.Millisecond), Current: float64(9 * time.Millisecond), ChangePct: -10, Threshold: 10,
+				},
+				{Metric: "storage size", Kind: benchmark.KindStorage, Baseline: 2 << 20, Current: 2 << 20, Threshold: 10},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "BASELINE COMPARISON")
+	assert.Contains(t, output, "100000/sec")
+	assert.Contains(t, output, "-20.0%")
+	assert.Contains(t, output, "REGRESSION (>10%)")
+	assert.Contains(t, output, "9ms")
+	assert.Contains(t, output, "2.00 MB")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Baseline Comparison")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "BASELINE")
+}
+
+func TestPrintSteadyState(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {Database: "postgres", Insert: &benchmark.InsertResult{
+			Throughput:  800,
+			SteadyState: &benchmark.SteadyStateResult{Tolerance: 10, Reached: true, After: 3 * time.Second, Seconds: 20, Throughput: 1000},
+		}},
+		"mongodb": {Database: "mongodb", Insert: &benchmark.InsertResult{
+			Throughput:  500,
+			SteadyState: &benchmark.SteadyStateResult{Tolerance: 10},
+		}},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "STEADY STATE")
+	assert.Contains(t, output, "1000/sec")
+	assert.Contains(t, output, "+25.0%")
+	assert.Contains(t, output, "not within ±10%")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Steady State")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "STEADY STATE")
+}
+
+func TestPrintBatchTuning(t *testing.T) {
+	results := map[string]*benchmark.Results{
+		"postgres": {Database: "postgres", BatchTuning: &benchmark.BatchTuningResult{
+			Min: 100, Max: 100000, Optimal: 20000, Throughput: 50000,
+			Probes: []benchmark.BatchProbe{
+				{BatchSize: 10000, Events: 100000, Throughput: 40000},
+				{BatchSize: 20000, Events: 100000, Throughput: 50000},
+				{BatchSize: 40000, Events: 320000, Throughput: 45000, ErrorCount: 2},
+			},
+		}},
+		"mongodb": {Database: "mongodb", BatchTuning: &benchmark.BatchTuningResult{Min: 100, Max: 100000, ErrorText: "schema failed"}},
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "BATCH SIZE TUNING")
+	assert.Contains(t, output, "-20.0%")
+	assert.Contains(t, output, "-10.0%")
+	assert.Contains(t, output, "✓")
+	assert.Contains(t, output, "error: schema failed")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "## Batch Size Tuning")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "BATCH SIZE TUNING")
+}
+
+func TestPrintRelative(t *testing.T) {
+	results := sampleResults()
+	results["clickhouse"] = &benchmark.Results{
+		Database: "clickhouse",
+		Insert:   &benchmark.InsertResult{TotalEvents: 1000, Duration: 2 * time.Second, Throughput: 480},
+		Queries: map[string]*benchmark.QueryResult{
+			"1_hour": {QueryName: "1_hour", Iterations: 10, P95Duration: 150 * time.Millisecond},
+		},
+		Storage: &repository.StorageStats{TotalSize: 1024 * 1024 * 1024 * 138 / 100},
+	}
+
+	for _, format := range []string{"table", "markdown"} {
+		var buf bytes.Buffer
+
+		rep := New(format, &buf)
+		rep.SetReference("postgres")
+		rep.PrintResults(results)
+
+		out := buf.String()
+		assert.Contains(t, out, "vs postgres", format)
+		assert.Contains(t, out, "reference", format)
+		assert.Contains(t, out, "2.4× faster", format)
+		assert.Contains(t, out, "2.0× slower", format)
+		assert.Contains(t, out, "+38% storage", format)
+	}
+
+	var buf bytes.Buffer
+
+	rep := New("table", &buf)
+	rep.SetReference("mysql")
+	rep.PrintResults(results)
+	assert.NotContains(t, buf.String(), "vs mysql", "a reference without results compares nothing")
+}
+
+func TestSpeedup(t *testing.T) {
+	assert.Equal(t, "2.4× faster", speedup(480, 200))
+	assert.Equal(t, "1.0× faster", speedup(200, 200))
+	assert.Equal(t, "4.0× slower", speedup(50, 200))
+	assert.Equal(t, "-", speedup(0, 200))
+}
+
+func TestPrintSummary(t *testing.T) {
+	results := sampleResults()
+	results["clickhouse"] = &benchmark.Results{
+		Database: "clickhouse",
+		Insert:   &benchmark.InsertResult{TotalEvents: 1000, Duration: time.Second, Throughput: 400},
+		Queries: map[string]*benchmark.QueryResult{
+			"1_hour": {QueryName: "1_hour", Iterations: 10, P95Duration: 150 * time.Millisecond},
+		},
+		Storage: &repository.StorageStats{TotalSize: 512 * 1024 * 1024, RowCount: 1000},
+	}
+
+	for _, format := range []string{"table", "markdown"} {
+		var buf bytes.Buffer
+
+		rep := New(format, &buf)
+		rep.SetScoreWeights(map[string]float64{"ingest": 2})
+		rep.PrintResults(results)
+
+		out := strings.ToLower(buf.String())
+		assert.Contains(t, out, "summary", format)
+		assert.Contains(t, out, "clickhouse (400/sec)", format)
+		assert.Contains(t, out, "postgres (75ms)", format)
+		assert.Contains(t, out, "overall score", format)
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), "SUMMARY")
+	assert.NotContains(t, buf.String(), "OVERALL SCORE", "no weights, no overall score")
+
+	buf.Reset()
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "SUMMARY", "a single database ranks nothing")
+}
+
+func TestOverallScores(t *testing.T) {
+	cats := []category{
+		{name: categoryIngest, ranked: []ranking{{db: "a", score: 100}, {db: "b", score: 50}}},
+		{name: "1_hour", ranked: []ranking{{db: "b", score: 100}, {db: "a", score: 25}}},
+		{name: "1_day", ranked: []ranking{{db: "b", score: 100}, {db: "a", score: 75}}},
+		{name: categoryStorage, ranked: []ranking{{db: "a", score: 100}}},
+	}
+
+	scores := overallScores([]string{"a", "b"}, cats, map[string]float64{})
+	require.Len(t, scores, 2)
+	assert.Equal(t, "a", scores[0].db)
+	assert.InDelta(t, 50.0, scores[0].queries, 0.01)
+	assert.InDelta(t, (100+25*0.5+75*0.5+100)/3, scores[0].total, 0.01)
+	assert.InDelta(t, (50+100)/3.0, scores[1].total, 0.01)
+
+	scores = overallScores([]string{"a", "b"}, cats, map[string]float64{"ingest": 0, "storage": 0, "1_day": 2})
+	assert.Equal(t, "b", scores[0].db)
+	assert.InDelta(t, (100+100*2)/3.0, scores[0].total, 0.01)
+}
+
+func TestParseScoreWeights(t *testing.T) {
+	weights, err := ParseScoreWeights("ingest=2, queries=1,storage=0.5")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"ingest": 2, "queries": 1, "storage": 0.5}, weights)
+
+	for _, s := range []string{"ingest", "=2", "ingest=fast", "storage=-1"} {
+		_, err := ParseScoreWeights(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestPrintHistogram(t *testing.T) {
+	durations := []time.Duration{3 * time.Millisecond, 3 * time.Millisecond, 3 * time.Millisecond, 200 * time.Millisecond}
+	results := sampleResults()
+	results["postgres"].Queries["1_hour"].Histogram = benchmark.NewHistogram(durations)
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	assert.NotContains(t, buf.String(), "LATENCY HISTOGRAMS", "histograms are verbose output only")
+
+	buf.Reset()
+
+	rep := New("table", &buf)
+	rep.SetVerbose(true)
+	rep.PrintResults(results)
+
+	output := buf.String()
+	assert.Contains(t, output, "LATENCY HISTOGRAMS")
+	assert.Contains(t, output, "2.05ms - 4.1ms")
+	assert.Contains(t, output, "75.0%")
+	assert.Contains(t, output, strings.Repeat("█", histogramWidth))
+	assert.Contains(t, output, "131ms - 262ms")
+	assert.Contains(t, output, "65.5ms - 131ms", "the empty bins between the modes show")
+}
+
+func TestLatencyBins(t *testing.T) {
+	bins := latencyBins(benchmark.NewHistogram([]time.Duration{500 * time.Nanosecond, 3 * time.Microsecond}))
+	assert.Equal(t, []latencyBin{
+		{from: 0, to: time.Microsecond, count: 1},
+		{from: time.Microsecond, to: 2 * time.Microsecond},
+		{from: 2 * time.Microsecond, to: 4 * time.Microsecond, count: 1},
+	}, bins)
+}
+
+func TestPrintEnvironment(t *testing.T) {
+	results := sampleResults()
+	results["postgres"].Environment = &benchmark.Environment{
+		Host: "bench-1", OS: "linux/amd64", CPUModel: "AMD EPYC 7B13", Cores: 8, MemoryBytes: 32 << 30, GoVersion: "go1.25.0",
+		Driver: "github.com/lib/pq@v1.11.2", ServerVersion: "16.2",
+		Settings: map[string]string{"write_consistency": "QUORUM", "consistency": "LOCAL_ONE"},
+		Database: &benchmark.ContainerLimits{Image: "postgres:16", CPUs: 2, MemoryBytes: 1 << 30},
+	}
+
+	for _, format := range []string{"table", "markdown"} {
+		var buf bytes.Buffer
+
+		New(format, &buf).PrintResults(results)
+
+		out := buf.String()
+		assert.Contains(t, strings.ToUpper(out), "ENVIRONMENT", format)
+		assert.Contains(t, out, "Host: bench-1, linux/amd64, AMD EPYC 7B13, 8 cores, 32.00 GB RAM, go1.25.0", format)
+		assert.Contains(t, out, "github.com/lib/pq@v1.11.2", format)
+		assert.Contains(t, out, "postgres:16, 2 CPUs, 1.00 GB", format)
+		assert.Contains(t, out, "consistency=LOCAL_ONE write_consistency=QUORUM", format)
+	}
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(sampleResults())
+	assert.NotContains(t, buf.String(), "ENVIRONMENT")
+}
+
+func TestPrintRun(t *testing.T) {
+	started := time.Date(2026, 10, 16, 21, 5, 58, 0, time.UTC)
+	results := sampleResults()
+	results["postgres"].Run = &benchmark.RunInfo{
+		ID: "20261016-210558-3f9a2c", StartedAt: started, FinishedAt: started.Add(3*time.Minute + 14*time.Second),
+		Revision: "2d3b59ece6a20a5d4add628c607ca2690f438a04", Config: map[string]string{"events": "1000000", "db": "postgres"},
+	}
+
+	note := "Run 20261016-210558-3f9a2c, revision 2d3b59ece6a2, 2026-10-16 21:05:58 to 2026-10-16 21:09:12 (3m14s)"
+
+	var buf bytes.Buffer
+
+	New("table", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), note)
+	assert.NotContains(t, buf.String(), "-events", "the flags are in JSON and markdown only")
+
+	buf.Reset()
+	New("markdown", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), note)
+	assert.Contains(t, buf.String(), "<summary>Configuration</summary>")
+	assert.Contains(t, buf.String(), "| -events | 1000000 |")
+
+	buf.Reset()
+	New("json", &buf).PrintResults(results)
+	assert.Contains(t, buf.String(), `"id": "20261016-210558-3f9a2c"`)
+}
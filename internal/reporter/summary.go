@@ -0,0 +1,388 @@
+package reporter
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// Categories of the summary besides the query scenarios, which are ranked
+// under their own names and weighted together as queries.
+const (
+	categoryIngest  = "ingest"
+	categoryQueries = "queries"
+	categoryStorage = "storage"
+)
+
+// category ranks the databases with a value for one category, best first.
+// The best scores 100, the others in proportion, so a database half as fast
+// scores 50.
+type category struct {
+	name   string
+	metric string
+	ranked []ranking
+}
+
+type ranking struct {
+	db    string
+	value string
+	score float64
+}
+
+// ParseScoreWeights parses the comma-separated category=weight pairs of the
+// overall score, e.g. "ingest=2,queries=1,storage=0.5". A query scenario can
+// be weighted on its own, e.g. "1_day=2"; the queries weight is shared by the
+// scenarios that are not. Categories left out weigh 1.
+func ParseScoreWeights(s string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+
+	for _, field := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid weight %q, want category=weight", field)
+		}
+
+		w, err := strconv.ParseFloat(value, 64)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid weight %q of %s, want a number of at least 0", value, name)
+		}
+
+		weights[name] = w
+	}
+
+	return weights, nil
+}
+
+// SetScoreWeights adds an overall score, weighted by category, to the
+// summary of the table and markdown output.
+func (r *Reporter) SetScoreWeights(weights map[string]float64) {
+	r.weights = weights
+}
+
+// summaryValues are the values the summary ranks: ingest throughput, the
+// P95 of each query scenario in seconds and storage bytes per row.
+type summaryValues struct {
+	ingest, storage map[string]float64
+	queries         map[string]map[string]float64
+}
+
+func (v *summaryValues) add(db string, res *benchmark.Results) {
+	if res.Insert != nil && res.Insert.Throughput > 0 {
+		v.ingest[db] = res.Insert.Throughput
+	}
+
+	if st := res.Storage; st != nil && st.TotalSize > 0 && st.RowCount > 0 {
+		v.storage[db] = float64(st.TotalSize) / float64(st.RowCount)
+	}
+
+	for name, qr := range res.Queries {
+		if qr.ErrorText != "" || qr.Iterations == 0 || qr.P95Duration <= 0 {
+			continue
+		}
+
+		if v.queries[name] == nil {
+			v.queries[name] = make(map[string]float64)
+		}
+
+		v.queries[name][db] = qr.P95Duration.Seconds()
+	}
+}
+
+// summaryCategories ranks the databases that ran by ingest throughput, by
+// the P95 of each query scenario and by storage per row.
+func summaryCategories(databases []string, results map[string]*benchmark.Results) []category {
+	v := summaryValues{ingest: map[string]float64{}, storage: map[string]float64{}, queries: map[string]map[string]float64{}}
+
+	for _, db := range databases {
+		if res := results[db]; res.Error == nil && res.ErrorText == "" {
+			v.add(db, res)
+		}
+	}
+
+	cats := []category{rank(categoryIngest, "throughput", v.ingest, true, func(x float64) string { return fmt.Sprintf("%.0f/sec", x) })}
+
+	for _, name := range slices.Sorted(maps.Keys(v.queries)) {
+		cats = append(cats, rank(name, "P95", v.queries[name], false, func(x float64) string {
+			return time.Duration(x * float64(time.Second)).Round(time.Microsecond).String()
+		}))
+	}
+
+	cats = append(cats, rank(categoryStorage, "bytes/row", v.storage, false, func(x float64) string { return fmt.Sprintf("%.1f B", x) }))
+
+	return slices.DeleteFunc(cats, func(c category) bool { return len(c.ranked) == 0 })
+}
+
+// rank orders the databases by value, the highest first when higher is
+// better, and scores them against the best.
+func rank(name, metric string, values map[string]float64, higherBetter bool, format func(float64) string) category {
+	c := category{name: name, metric: metric}
+	if len(values) == 0 {
+		return c
+	}
+
+	best := slices.Min(slices.Collect(maps.Values(values)))
+	if higherBetter {
+		best = slices.Max(slices.Collect(maps.Values(values)))
+	}
+
+	for db, v := range values {
+		score := best / v * 100
+		if higherBetter {
+			score = v / best * 100
+		}
+
+		c.ranked = append(c.ranked, ranking{db: db, value: format(v), score: score})
+	}
+
+	slices.SortFunc(c.ranked, func(a, b ranking) int {
+		if a.score != b.score {
+			return cmpDesc(a.score, b.score)
+		}
+
+		return strings.Compare(a.db, b.db)
+	})
+
+	return c
+}
+
+func cmpDesc(a, b float64) int {
+	if a > b {
+		return -1
+	}
+
+	return 1
+}
+
+// overallScore is the weighted score of one database, with its score in the
+// ingest, queries and storage categories. A category it has no value for
+// scores 0.
+type overallScore struct {
+	db                       string
+	ingest, queries, storage float64
+	total                    float64
+}
+
+// overallScores weighs the category scores of each database, best first.
+func overallScores(databases []string, cats []category, weights map[string]float64) []overallScore {
+	weightOf := categoryWeights(cats, weights)
+
+	var scores []overallScore
+
+	for _, db := range rankedDatabases(databases, cats) {
+		scores = append(scores, scoreDatabase(db, cats, weightOf))
+	}
+
+	slices.SortStableFunc(scores, func(a, b overallScore) int { return cmpDesc(a.total, b.total) })
+
+	return scores
+}
+
+func scoreDatabase(db string, cats []category, weightOf map[string]float64) overallScore {
+	s := overallScore{db: db}
+
+	var overall, queries weightedMean
+
+	for _, c := range cats {
+		score, w := scoreOf(c, db), weightOf[c.name]
+		overall.add(score, w)
+
+		switch c.name {
+		case categoryIngest:
+			s.ingest = score
+		case categoryStorage:
+			s.storage = score
+		default:
+			queries.add(score, w)
+		}
+	}
+
+	s.total, s.queries = overall.value(), queries.value()
+
+	return s
+}
+
+// weightedMean accumulates a weighted mean of scores.
+type weightedMean struct {
+	sum, weight float64
+}
+
+func (m *weightedMean) add(value, weight float64) {
+	m.sum += weight * value
+	m.weight += weight
+}
+
+// value returns the mean, 0 without weight.
+func (m weightedMean) value() float64 {
+	return safeDiv(m.sum, m.weight)
+}
+
+// categoryWeights returns the weight of each category: its own, or for a
+// query scenario without one, its share of the queries weight.
+func categoryWeights(cats []category, weights map[string]float64) map[string]float64 {
+	isShared := func(c category) bool {
+		_, own := weights[c.name]
+		return !own && c.name != categoryIngest && c.name != categoryStorage
+	}
+
+	share := queryShare(weights, len(slices.DeleteFunc(slices.Clone(cats), func(c category) bool { return !isShared(c) })))
+	weightOf := make(map[string]float64, len(cats))
+
+	for _, c := range cats {
+		w, own := weights[c.name]
+
+		switch {
+		case own:
+			weightOf[c.name] = w
+		case isShared(c):
+			weightOf[c.name] = share
+		default:
+			weightOf[c.name] = 1
+		}
+	}
+
+	return weightOf
+}
+
+// queryShare returns the weight of each of the shared query scenarios: the
+// queries weight, 1 by default, split between them.
+func queryShare(weights map[string]float64, shared int) float64 {
+	queriesWeight, ok := weights[categoryQueries]
+	if !ok {
+		queriesWeight = 1
+	}
+
+	return queriesWeight / float64(shared)
+}
+
+// rankedDatabases returns the databases ranked in any category.
+func rankedDatabases(databases []string, cats []category) []string {
+	return slices.DeleteFunc(slices.Clone(databases), func(db string) bool {
+		return !slices.ContainsFunc(cats, func(c category) bool { return scoreOf(c, db) > 0 })
+	})
+}
+
+func scoreOf(c category, db string) float64 {
+	if i := slices.IndexFunc(c.ranked, func(r ranking) bool { return r.db == db }); i >= 0 {
+		return c.ranked[i].score
+	}
+
+	return 0
+}
+
+func safeDiv(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+
+	return a / b
+}
+
+// summaryHeader has a column per place, as many as the longest ranking.
+func summaryHeader(cats []category) table.Row {
+	header := table.Row{"Category", "Metric"}
+
+	places := 0
+	for _, c := range cats {
+		places = max(places, len(c.ranked))
+	}
+
+	for i := range places {
+		header = append(header, fmt.Sprintf("#%d", i+1))
+	}
+
+	return header
+}
+
+func summaryRows(cats []category) []table.Row {
+	rows := make([]table.Row, 0, len(cats))
+
+	for _, c := range cats {
+		row := table.Row{c.name, c.metric}
+		for _, r := range c.ranked {
+			row = append(row, fmt.Sprintf("%s (%s)", r.db, r.value))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+var scoreHeader = table.Row{"Rank", "Database", "Ingest", "Queries", "Storage", "Score"}
+
+func scoreRows(scores []overallScore) []table.Row {
+	rows := make([]table.Row, 0, len(scores))
+
+	for i, s := range scores {
+		rows = append(rows, table.Row{
+			i + 1, s.db, fmt.Sprintf("%.0f", s.ingest), fmt.Sprintf("%.0f", s.queries), fmt.Sprintf("%.0f", s.storage),
+			fmt.Sprintf("%.1f", s.total),
+		})
+	}
+
+	return rows
+}
+
+// hasSummary reports whether there is anything to rank: at least two
+// databases with results.
+func hasSummary(databases []string, cats []category) bool {
+	return len(rankedDatabases(databases, cats)) >= 2
+}
+
+func (r *Reporter) printSummaryTable(databases []string, results map[string]*benchmark.Results) {
+	cats := summaryCategories(databases, results)
+	if !hasSummary(databases, cats) {
+		return
+	}
+
+	t := r.newTable("SUMMARY")
+	t.AppendHeader(summaryHeader(cats))
+	t.AppendRows(summaryRows(cats))
+	t.Render()
+	r.printLine()
+
+	if r.weights != nil {
+		t = r.newTable("OVERALL SCORE")
+		t.AppendHeader(scoreHeader)
+		t.AppendRows(scoreRows(overallScores(databases, cats, r.weights)))
+		t.Render()
+		r.printLine()
+	}
+}
+
+func (r *Reporter) printMarkdownSummary(databases []string, results map[string]*benchmark.Results) {
+	cats := summaryCategories(databases, results)
+	if !hasSummary(databases, cats) {
+		return
+	}
+
+	r.printLine("\n## Summary")
+
+	t := r.newTable("")
+	t.AppendHeader(summaryHeader(cats))
+	t.AppendRows(summaryRows(cats))
+	t.RenderMarkdown()
+	r.printLine()
+
+	r.printMarkdownScore(databases, cats)
+}
+
+func (r *Reporter) printMarkdownScore(databases []string, cats []category) {
+	if r.weights == nil {
+		return
+	}
+
+	r.printLine("\n## Overall Score")
+
+	t := r.newTable("")
+	t.AppendHeader(scoreHeader)
+	t.AppendRows(scoreRows(overallScores(databases, cats, r.weights)))
+	t.RenderMarkdown()
+	r.printLine()
+}
@@ -0,0 +1,86 @@
+package reporter
+
+import (
+	"encoding/json"
+	htmltemplate "html/template"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/benchmark"
+)
+
+// Template is a user template of the template output, text/template or
+// html/template.
+type Template interface {
+	Execute(w io.Writer, data any) error
+}
+
+// TemplateData is what a template of the template output is executed with.
+// Ranging over Results visits the databases in name order.
+type TemplateData struct {
+	SchemaVersion int
+	Run           *benchmark.RunInfo // nil for results without one
+	Databases     []string           // names of Results, sorted
+	Results       map[string]*benchmark.Results
+}
+
+// templateFuncs are the helpers a template can call besides the builtins.
+var templateFuncs = map[string]any{
+	"ms":    ms,
+	"round": func(d time.Duration) time.Duration { return d.Round(time.Millisecond) },
+	"bytes": formatBytes,
+	"json": func(v any) (string, error) {
+		b, err := json.MarshalIndent(v, "", "  ")
+		return string(b), err
+	},
+}
+
+// ParseTemplate parses the template file at path: as html/template, which
+// escapes the values it prints, for .html and .htm files, and as
+// text/template otherwise.
+func ParseTemplate(path string) (Template, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(path)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		return htmltemplate.New(name).Funcs(templateFuncs).Parse(string(src))
+	default:
+		return template.New(name).Funcs(templateFuncs).Parse(string(src))
+	}
+}
+
+// SetTemplate sets the template of the template output.
+func (r *Reporter) SetTemplate(t Template) {
+	r.template = t
+}
+
+func (r *Reporter) printTemplate(results map[string]*benchmark.Results) {
+	if r.template == nil {
+		log.Println("template output without a template")
+		return
+	}
+
+	databases := sortedKeys(results)
+
+	data := TemplateData{SchemaVersion: benchmark.SchemaVersion, Databases: databases, Results: results}
+	for _, db := range databases {
+		if run := results[db].Run; run != nil {
+			data.Run = run
+			break
+		}
+	}
+
+	if err := r.template.Execute(r.w, data); err != nil {
+		log.Printf("Failed to execute the template: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package reporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplate(t *testing.T, name, src string) Template {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(src), 0o600))
+
+	tmpl, err := ParseTemplate(path)
+	require.NoError(t, err)
+
+	return tmpl
+}
+
+func TestPrintTemplate(t *testing.T) {
+	tmpl := writeTemplate(t, "report.tmpl",
+		`v{{.SchemaVersion}}{{range $db, $r := .Results}} {{$db}}={{printf "%.0f" $r.Insert.Throughput}}/s p95={{round (index $r.Queries "1_hour").P95Duration}}{{end}}`)
+
+	var buf bytes.Buffer
+
+	rep := New("template", &buf)
+	rep.SetTemplate(tmpl)
+	rep.PrintHeader()
+	rep.PrintResults(sampleResults())
+
+	assert.Equal(t, "v1 postgres=200/s p95=75ms", buf.String(), "no banner, only the template")
+}
+
+func TestPrintHTMLTemplate(t *testing.T) {
+	tmpl := writeTemplate(t, "report.html", `<p>{{.Databases}}</p>`)
+
+	results := sampleResults()
+	results["<b>"] = results["postgres"]
+
+	var buf bytes.Buffer
+
+	rep := New("template", &buf)
+	rep.SetTemplate(tmpl)
+	rep.PrintResults(results)
+
+	assert.Equal(t, "<p>[&lt;b&gt; postgres]</p>", buf.String())
+}
+
+func TestParseTemplateError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("{{.Results"), 0o600))
+
+	_, err := ParseTemplate(path)
+	require.Error(t, err)
+
+	_, err = ParseTemplate(filepath.Join(t.TempDir(), "missing.tmpl"))
+	require.Error(t, err)
+}
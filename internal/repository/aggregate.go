@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"sort"
+	"time"
+)
+
+// hourlyKey identifies an (hour, event type) bucket when stats are
+// aggregated client-side; hour is the bucket start in unix nanoseconds.
+type hourlyKey struct {
+	hour      int64
+	eventType string
+}
+
+// statsAggregator computes the hourly event stats in Go for backends without
+// server-side GROUP BY, counting events and distinct users within [start, end].
+type statsAggregator struct {
+	start, end int64
+	counts     map[hourlyKey]int64
+	users      map[hourlyKey]map[int64]struct{}
+}
+
+func newStatsAggregator(start, end time.Time) *statsAggregator {
+	return &statsAggregator{
+		start:  start.UnixNano(),
+		end:    end.UnixNano(),
+		counts: make(map[hourlyKey]int64),
+		users:  make(map[hourlyKey]map[int64]struct{}),
+	}
+}
+
+// add records one event; createdAt is in unix nanoseconds.
+func (a *statsAggregator) add(createdAt, userID int64, eventType string) {
+	if createdAt < a.start || createdAt > a.end {
+		return
+	}
+
+	key := hourlyKey{
+		hour:      createdAt - createdAt%int64(time.Hour),
+		eventType: eventType,
+	}
+
+	a.counts[key]++
+
+	if a.users[key] == nil {
+		a.users[key] = make(map[int64]struct{})
+	}
+
+	a.users[key][userID] = struct{}{}
+}
+
+func (a *statsAggregator) result() []EventStats {
+	stats := make([]EventStats, 0, len(a.counts))
+
+	for key, count := range a.counts {
+		stats = append(stats, EventStats{
+			Hour:        time.Unix(0, key.hour).UTC(),
+			EventType:   key.eventType,
+			Count:       count,
+			UniqueUsers: int64(len(a.users[key])),
+		})
+	}
+
+	sortEventStats(stats)
+
+	return stats
+}
+
+// sortEventStats orders stats newest hour first, then by event type, matching
+// the ORDER BY hour DESC of the SQL backends.
+func sortEventStats(stats []EventStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if !stats[i].Hour.Equal(stats[j].Hour) {
+			return stats[i].Hour.After(stats[j].Hour)
+		}
+
+		return stats[i].EventType < stats[j].EventType
+	})
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsAggregator(t *testing.T) {
+	hour := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) int64 { return hour.Add(d).UnixNano() }
+
+	agg := newStatsAggregator(hour, hour.Add(2*time.Hour))
+	agg.add(at(time.Minute), 1, "click")
+	agg.add(at(2*time.Minute), 1, "click")
+	agg.add(at(3*time.Minute), 2, "click")
+	agg.add(at(90*time.Minute), 3, "view")
+	agg.add(at(-time.Minute), 4, "view") // before the range
+
+	stats := agg.result()
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, hour.Add(time.Hour), stats[0].Hour)
+	assert.Equal(t, "view", stats[0].EventType)
+	assert.Equal(t, int64(1), stats[0].Count)
+
+	assert.Equal(t, hour, stats[1].Hour)
+	assert.Equal(t, "click", stats[1].EventType)
+	assert.Equal(t, int64(3), stats[1].Count)
+	assert.Equal(t, int64(2), stats[1].UniqueUsers)
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/dgraph-io/badger/v4/options"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// BadgerRepo stores events in an embedded BadgerDB using the shared
+// time-ordered key layout. Values are small enough to stay inline in the LSM
+// tree, where SST blocks are compressed.
+type BadgerRepo struct {
+	db  *badger.DB
+	cfg *config.BadgerConfig
+}
+
+func NewBadgerRepo(_ context.Context, cfg *config.BadgerConfig) (*BadgerRepo, error) {
+	compression, err := badgerCompression(cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	path := cfg.Path
+	if cfg.InMemory() {
+		path = ""
+	}
+
+	opts := badger.DefaultOptions(path).
+		WithInMemory(cfg.InMemory()).
+		WithCompression(compression).
+		WithSyncWrites(cfg.SyncWrites).
+		WithLogger(nil)
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open badger database: %w", err)
+	}
+
+	return &BadgerRepo{db: db, cfg: cfg}, nil
+}
+
+func badgerCompression(name string) (options.CompressionType, error) {
+	switch strings.ToLower(name) {
+	case "", "zstd":
+		return options.ZSTD, nil
+	case "snappy":
+		return options.Snappy, nil
+	case "none":
+		return options.None, nil
+	default:
+		return options.None, fmt.Errorf("unknown badger compression %q", name)
+	}
+}
+
+// InitSchema removes all existing keys; Badger has no schema.
+func (r *BadgerRepo) InitSchema(_ context.Context) error {
+	return r.db.DropAll()
+}
+
+func (r *BadgerRepo) InsertBatch(_ context.Context, events []generator.Event) error {
+	wb := r.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	for i := range events {
+		if err := wb.Set(kvEventKey(&events[i]), encodeKVValue(&events[i])); err != nil {
+			return err
+		}
+	}
+
+	return wb.Flush()
+}
+
+// GetEventStats iterates the key range [start, end] and aggregates in Go.
+func (r *BadgerRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	agg := newStatsAggregator(start, end)
+	upper := kvTimeKey(end.Add(time.Nanosecond))
+
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{PrefetchValues: true, PrefetchSize: 1000, Prefix: kvEventPrefix})
+		defer it.Close()
+
+		for it.Seek(kvTimeKey(start)); it.Valid(); it.Next() {
+			item := it.Item()
+			if bytes.Compare(item.Key(), upper) >= 0 {
+				break
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			err := item.Value(func(value []byte) error {
+				userID, eventType, err := decodeKVValue(value)
+				if err != nil {
+					return err
+				}
+
+				agg.add(kvKeyTime(item.Key()), userID, eventType)
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return agg.result(), nil
+}
+
+// GetStorageStats reports the on-disk size of flushed SSTables and the value
+// log files. Data still in the memtable is not counted until it is flushed.
+func (r *BadgerRepo) GetStorageStats(_ context.Context) *StorageStats {
+	var lsm, vlog int64
+
+	for _, table := range r.db.Tables() {
+		lsm += int64(table.OnDiskSize)
+	}
+
+	if !r.cfg.InMemory() {
+		vlog = dirSizeByExt(r.cfg.Path, ".vlog")
+	}
+
+	stats := &StorageStats{
+		TotalSize: lsm + vlog,
+		Details:   map[string]int64{"lsm_bytes": lsm, "vlog_bytes": vlog},
+	}
+
+	_ = r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: kvEventPrefix})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			stats.RowCount++
+		}
+
+		return nil
+	})
+
+	return stats
+}
+
+// dirSizeByExt sums the sizes of files under dir with the given extension.
+func dirSizeByExt(dir, ext string) int64 {
+	var total int64
+
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && filepath.Ext(path) == ext {
+			total += fileSize(path)
+		}
+
+		return nil
+	})
+
+	return total
+}
+
+// DeleteOlderThan writes a tombstone per expired key in write batches of
+// retentionBatchSize. Badger has no range delete; space returns only after
+// compaction and value log GC.
+func (r *BadgerRepo) DeleteOlderThan(_ context.Context, cutoff time.Time) (*DeleteStats, error) {
+	var keys [][]byte
+
+	upper := kvTimeKey(cutoff)
+
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: kvEventPrefix})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid() && bytes.Compare(it.Item().Key(), upper) < 0; it.Next() {
+			keys = append(keys, it.Item().KeyCopy(nil))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for chunk := range slices.Chunk(keys, retentionBatchSize) {
+		wb := r.db.NewWriteBatch()
+
+		for _, key := range chunk {
+			if err := wb.Delete(key); err != nil {
+				wb.Cancel()
+				return nil, err
+			}
+		}
+
+		if err := wb.Flush(); err != nil {
+			return nil, fmt.Errorf("failed to delete events: %w", err)
+		}
+	}
+
+	return &DeleteStats{Rows: int64(len(keys)), Method: retentionDelete}, nil
+}
+
+func (r *BadgerRepo) Cleanup(_ context.Context) error {
+	return r.db.DropAll()
+}
+
+func (r *BadgerRepo) Close() error {
+	return r.db.Close()
+}
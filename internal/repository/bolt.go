@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	boltBucketPrefix = "d:"
+	boltDayLayout    = "20060102"
+)
+
+// BoltRepo stores events in an embedded bbolt (B+tree) database, one bucket
+// per UTC day. Keys inside a bucket use the shared time-ordered layout, so a
+// stats query seeks a cursor in each day bucket of the range.
+type BoltRepo struct {
+	db   *bolt.DB
+	path string
+}
+
+func NewBoltRepo(_ context.Context, cfg *config.BoltConfig) (*BoltRepo, error) {
+	db, err := bolt.Open(cfg.Path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bbolt database: %w", err)
+	}
+
+	db.NoSync = cfg.NoSync
+
+	return &BoltRepo{db: db, path: cfg.Path}, nil
+}
+
+// boltDayBucket returns the name of the bucket holding events from t's UTC day.
+func boltDayBucket(t time.Time) []byte {
+	return []byte(boltBucketPrefix + t.UTC().Format(boltDayLayout))
+}
+
+// InitSchema drops every day bucket.
+func (r *BoltRepo) InitSchema(_ context.Context) error {
+	return r.deleteBuckets()
+}
+
+// InsertBatch writes the batch in a single read-write transaction; bbolt
+// serializes writers, so concurrent workers queue on the database lock.
+func (r *BoltRepo) InsertBatch(_ context.Context, events []generator.Event) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		for i := range events {
+			bucket, err := tx.CreateBucketIfNotExists(boltDayBucket(events[i].CreatedAt))
+			if err != nil {
+				return err
+			}
+
+			if err := bucket.Put(kvEventKey(&events[i]), encodeKVValue(&events[i])); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *BoltRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	agg := newStatsAggregator(start, end)
+	upper := kvTimeKey(end.Add(time.Nanosecond))
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		firstDay := start.UTC().Truncate(24 * time.Hour)
+
+		for day := firstDay; !day.After(end); day = day.Add(24 * time.Hour) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			bucket := tx.Bucket(boltDayBucket(day))
+			if bucket == nil {
+				continue
+			}
+
+			if err := scanBoltBucket(bucket.Cursor(), kvTimeKey(start), upper, agg); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return agg.result(), nil
+}
+
+func scanBoltBucket(c *bolt.Cursor, lower, upper []byte, agg *statsAggregator) error {
+	for k, v := c.Seek(lower); k != nil && bytes.Compare(k, upper) < 0; k, v = c.Next() {
+		userID, eventType, err := decodeKVValue(v)
+		if err != nil {
+			return err
+		}
+
+		agg.add(kvKeyTime(k), userID, eventType)
+	}
+
+	return nil
+}
+
+// GetStorageStats reports the database file size, with the number of day
+// buckets as a detail.
+func (r *BoltRepo) GetStorageStats(_ context.Context) *StorageStats {
+	stats := &StorageStats{
+		TotalSize: fileSize(r.path),
+		Details:   map[string]int64{},
+	}
+
+	_ = r.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			if bytes.HasPrefix(name, []byte(boltBucketPrefix)) {
+				stats.RowCount += int64(b.Stats().KeyN)
+				stats.Details["day_buckets"]++
+			}
+
+			return nil
+		})
+	})
+
+	return stats
+}
+
+func (r *BoltRepo) deleteBuckets() error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		var names [][]byte
+
+		err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			if bytes.HasPrefix(name, []byte(boltBucketPrefix)) {
+				names = append(names, bytes.Clone(name))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *BoltRepo) Cleanup(_ context.Context) error {
+	return r.deleteBuckets()
+}
+
+func (r *BoltRepo) Close() error {
+	return r.db.Close()
+}
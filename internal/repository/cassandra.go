@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,18 +19,91 @@ func cqlQuoteIdentifier(s string) string {
 }
 
 type CassandraRepo struct {
-	session *gocql.Session
+	session           *gocql.Session
+	consistency       gocql.Consistency
+	writeConsistency  gocql.Consistency
+	readConsistency   gocql.Consistency
+	insertMode        string // concurrent, batch or sequential
+	insertConcurrency int
+	batchRows         int
+	insertCfg         config.CassandraConfig // insert_concurrency and batch_rows as configured, for SetInsertMode
+	protoVersion      int
+	localDC           string
+	keyspace          string
+	table             string        // name of the events table
+	ttl               time.Duration // event-time TTL of new writes, 0 when off
 }
 
 func NewCassandraRepo(_ context.Context, cfg config.CassandraConfig) (*CassandraRepo, error) {
-	cluster := newCassandraCluster(cfg)
+	cluster, err := newCassandraCluster(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return openCQLRepo(cluster, cfg)
+}
+
+// openCQLRepo creates the keyspace if needed and opens a session bound to it.
+func openCQLRepo(cluster *gocql.ClusterConfig, cfg config.CassandraConfig) (*CassandraRepo, error) {
+	r, err := newCQLRepo(cluster, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.session, err = openKeyspace(cluster, cfg); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// newCQLRepo returns a repository with the validated settings of cfg,
+// before it connects.
+func newCQLRepo(cluster *gocql.ClusterConfig, cfg config.CassandraConfig) (*CassandraRepo, error) {
+	table, err := tableName(cfg.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	writeConsistency, err := parseConsistency(cfg.WriteConsistency, gocql.LocalOne)
+	if err != nil {
+		return nil, err
+	}
+
+	insertMode, insertConcurrency, batchRows, err := cqlInsertSettings(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	readConsistency, err := parseConsistency(cfg.ReadConsistency, gocql.LocalOne)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CassandraRepo{
+		consistency:       cluster.Consistency,
+		writeConsistency:  writeConsistency,
+		readConsistency:   readConsistency,
+		insertMode:        insertMode,
+		insertConcurrency: insertConcurrency,
+		batchRows:         batchRows,
+		insertCfg:         config.CassandraConfig{InsertConcurrency: cfg.InsertConcurrency, BatchRows: cfg.BatchRows},
+		protoVersion:      cluster.ProtoVersion,
+		localDC:           cfg.LocalDC,
+		keyspace:          cfg.Keyspace,
+		table:             table,
+	}, nil
+}
 
+// openKeyspace creates the keyspace of cfg if needed and returns a session
+// bound to it.
+func openKeyspace(cluster *gocql.ClusterConfig, cfg config.CassandraConfig) (*gocql.Session, error) {
 	session, err := cluster.CreateSession()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cassandra session: %w", err)
 	}
 
-	if err := createKeyspace(session, cfg.Keyspace); err != nil {
+	if err := createKeyspace(session, cfg); err != nil {
 		session.Close()
 		return nil, err
 	}
@@ -42,31 +117,113 @@ func NewCassandraRepo(_ context.Context, cfg config.CassandraConfig) (*Cassandra
 		return nil, fmt.Errorf("failed to reconnect to keyspace: %w", err)
 	}
 
-	return &CassandraRepo{session: session}, nil
+	return session, nil
 }
 
-func newCassandraCluster(cfg config.CassandraConfig) *gocql.ClusterConfig {
+// parseConsistency parses a CQL consistency level name, returning def when empty.
+func parseConsistency(s string, def gocql.Consistency) (gocql.Consistency, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	c, err := gocql.ParseConsistencyWrapper(s)
+	if err != nil {
+		return def, fmt.Errorf("invalid cassandra consistency %q: %w", s, err)
+	}
+
+	return c, nil
+}
+
+func newCassandraCluster(cfg config.CassandraConfig) (*gocql.ClusterConfig, error) {
 	cluster := gocql.NewCluster(cfg.Hosts...)
+
+	if err := setCQLPort(cluster, cfg.Port); err != nil {
+		return nil, err
+	}
+
+	if err := setCQLPolicies(cluster, cfg); err != nil {
+		return nil, err
+	}
+
 	cluster.Keyspace = "system"
-	cluster.Consistency = gocql.LocalOne
-	cluster.ProtoVersion = 4
+	cluster.ProtoVersion = cfg.ProtoVersion
 	cluster.ConnectTimeout = 10 * time.Second
 	cluster.Timeout = 30 * time.Second
-	cluster.NumConns = 2
-	cluster.DisableInitialHostLookup = true
+	if cfg.NumConns > 0 {
+		cluster.NumConns = cfg.NumConns
+	}
+
+	if cfg.Username != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{Username: cfg.Username, Password: cfg.Password}
+	}
+
+	return cluster, nil
+}
+
+// setCQLPort sets the port of the cluster, when one is configured.
+func setCQLPort(cluster *gocql.ClusterConfig, port string) error {
+	if port == "" {
+		return nil
+	}
+
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("invalid cassandra port %q: %w", port, err)
+	}
+
+	cluster.Port = n
+
+	return nil
+}
+
+// setCQLPolicies sets how the cluster picks hosts, its consistency and its
+// retries.
+func setCQLPolicies(cluster *gocql.ClusterConfig, cfg config.CassandraConfig) error {
+	policy, err := cqlHostPolicy(cfg)
+	if err != nil {
+		return err
+	}
+
+	consistency, err := parseConsistency(cfg.Consistency, gocql.LocalOne)
+	if err != nil {
+		return err
+	}
+
+	cluster.Consistency = consistency
+	cluster.PoolConfig.HostSelectionPolicy = policy
+
+	// A single contact point is taken as the whole cluster, as the peers a
+	// node in a container reports are not reachable from the host. Token and
+	// datacenter awareness need the ring metadata the lookup reads.
+	cluster.DisableInitialHostLookup = len(cfg.Hosts) == 1 && cfg.HostPolicy != config.HostPolicyTokenAware && cfg.LocalDC == ""
 	cluster.RetryPolicy = &gocql.ExponentialBackoffRetryPolicy{NumRetries: 3, Min: 500 * time.Millisecond, Max: 5 * time.Second}
 
-	return cluster
+	return nil
+}
+
+// cqlHostPolicy returns the node selection policy of cfg: round robin or
+// token-aware, over the nodes of the local datacenter first when it is set.
+func cqlHostPolicy(cfg config.CassandraConfig) (gocql.HostSelectionPolicy, error) {
+	fallback := gocql.RoundRobinHostPolicy()
+	if cfg.LocalDC != "" {
+		fallback = gocql.DCAwareRoundRobinPolicy(cfg.LocalDC)
+	}
+
+	switch cfg.HostPolicy {
+	case "", config.HostPolicyRoundRobin:
+		return fallback, nil
+	case config.HostPolicyTokenAware:
+		return gocql.TokenAwareHostPolicy(fallback, gocql.ShuffleReplicas()), nil
+	default:
+		return nil, fmt.Errorf("invalid host_policy %q: must be %s or %s", cfg.HostPolicy, config.HostPolicyRoundRobin, config.HostPolicyTokenAware)
+	}
 }
 
-func createKeyspace(session *gocql.Session, keyspace string) error {
+func createKeyspace(session *gocql.Session, cfg config.CassandraConfig) error {
 	keyspaceQuery := fmt.Sprintf(`
 		CREATE KEYSPACE IF NOT EXISTS "%s"
-		WITH replication = {
-			'class': 'SimpleStrategy',
-			'replication_factor': 1
-		}
-	`, cqlQuoteIdentifier(keyspace))
+		WITH replication = %s
+	`, cqlQuoteIdentifier(cfg.Keyspace), cqlReplication(cfg))
 
 	if err := session.Query(keyspaceQuery).Exec(); err != nil {
 		return fmt.Errorf("failed to create keyspace: %w", err)
@@ -75,8 +232,20 @@ func createKeyspace(session *gocql.Session, keyspace string) error {
 	return nil
 }
 
+// cqlReplication returns the replication of the keyspace: replicas in the
+// local datacenter when it is set, SimpleStrategy otherwise.
+func cqlReplication(cfg config.CassandraConfig) string {
+	rf := max(cfg.ReplicationFactor, 1)
+
+	if cfg.LocalDC != "" {
+		return fmt.Sprintf("{'class': 'NetworkTopologyStrategy', '%s': %d}", strings.ReplaceAll(cfg.LocalDC, "'", "''"), rf)
+	}
+
+	return fmt.Sprintf("{'class': 'SimpleStrategy', 'replication_factor': %d}", rf)
+}
+
 func (r *CassandraRepo) InitSchema(ctx context.Context) error {
-	_ = r.session.Query("DROP TABLE IF EXISTS events").WithContext(ctx).Exec()
+	_ = r.session.Query(tableSQL("DROP TABLE IF EXISTS events", r.table)).WithContext(ctx).Exec()
 
 	schema := `
 		CREATE TABLE IF NOT EXISTS events (
@@ -95,22 +264,25 @@ func (r *CassandraRepo) InitSchema(ctx context.Context) error {
 		}
 	`
 
-	return r.session.Query(schema).WithContext(ctx).Exec()
+	return r.session.Query(tableSQL(schema, r.table)).WithContext(ctx).Exec()
 }
 
-func (r *CassandraRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
-	for _, event := range events {
-		bucket := event.CreatedAt.Format("20060102")
-		if err := r.session.Query(`
-			INSERT INTO events (date_bucket, created_at, event_id, user_id, event_type, payload)
-			VALUES (?, ?, ?, ?, ?, ?)`,
-			bucket, event.CreatedAt, event.ID, event.UserID, event.EventType, event.Payload,
-		).WithContext(ctx).Exec(); err != nil {
-			return err
-		}
+// EventVisible reads event back by its full primary key at the read
+// consistency, so a sample read after its insert sees what a reader at that
+// level would.
+func (r *CassandraRepo) EventVisible(ctx context.Context, event generator.Event) (bool, error) {
+	var id string
+
+	err := r.session.Query(tableSQL(`
+		SELECT event_id FROM events
+		WHERE date_bucket = ? AND event_type = ? AND created_at = ? AND event_id = ?`, r.table),
+		event.CreatedAt.Format("20060102"), event.EventType, event.CreatedAt, event.ID,
+	).WithContext(ctx).Consistency(r.readConsistency).Scan(&id)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return false, nil
 	}
 
-	return nil
+	return err == nil, err
 }
 
 func (r *CassandraRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
@@ -127,7 +299,7 @@ func (r *CassandraRepo) GetEventStats(ctx context.Context, start, end time.Time)
 			GROUP BY date_bucket, event_type
 		`
 
-		iter := r.session.Query(query, bucket).WithContext(ctx).Iter()
+		iter := r.session.Query(tableSQL(query, r.table), bucket).WithContext(ctx).Iter()
 
 		var (
 			dateBucket string
@@ -154,20 +326,152 @@ func (r *CassandraRepo) GetEventStats(ctx context.Context, start, end time.Time)
 	return stats, nil
 }
 
+// GetUserGroups reads user_id and created_at from every day bucket in
+// [start, end] and counts per user client-side; CQL can only group by
+// primary key columns, so all groups live in the benchmark process.
+func (r *CassandraRepo) GetUserGroups(ctx context.Context, start, end time.Time) (*UserGroupStats, error) {
+	counts := make(map[int64]int64)
+
+	for day := start.Truncate(24 * time.Hour); !day.After(end); day = day.AddDate(0, 0, 1) {
+		iter := r.session.Query(tableSQL(`SELECT user_id, created_at FROM events WHERE date_bucket = ?`, r.table),
+			day.Format("20060102")).WithContext(ctx).Iter()
+
+		var (
+			userID    int64
+			createdAt time.Time
+		)
+
+		for iter.Scan(&userID, &createdAt) {
+			if !createdAt.Before(start) && !createdAt.After(end) {
+				counts[userID]++
+			}
+		}
+
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	g := &UserGroupStats{Groups: int64(len(counts))}
+	for _, n := range counts {
+		g.MaxCount = max(g.MaxCount, n)
+	}
+
+	return g, nil
+}
+
+// ScanPages reads every day bucket of [start, end] in pages of pageSize,
+// resuming each page from the paging state of the previous one. Rows outside
+// the range are dropped client-side and not counted.
+func (r *CassandraRepo) ScanPages(ctx context.Context, start, end time.Time, pageSize int, page PageFunc) error {
+	for day := start.Truncate(24 * time.Hour); !day.After(end); day = day.AddDate(0, 0, 1) {
+		var state []byte
+
+		for {
+			var (
+				rows int
+				err  error
+			)
+
+			if rows, state, err = r.scanPage(ctx, day.Format("20060102"), start, end, pageSize, state); err != nil {
+				return err
+			}
+
+			page(rows)
+
+			if len(state) == 0 {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// scanPage reads one page of a day bucket and returns the rows in [start, end]
+// and the paging state of the next page, empty after the last one.
+func (r *CassandraRepo) scanPage(
+	ctx context.Context, bucket string, start, end time.Time, pageSize int, state []byte,
+) (int, []byte, error) {
+	iter := r.session.Query(tableSQL(`SELECT event_id, user_id, event_type, payload, created_at FROM events WHERE date_bucket = ?`, r.table),
+		bucket).WithContext(ctx).PageSize(pageSize).PageState(state).Iter()
+	next := iter.PageState()
+
+	var (
+		e    generator.Event
+		rows int
+	)
+
+	for iter.Scan(&e.ID, &e.UserID, &e.EventType, &e.Payload, &e.CreatedAt) {
+		if !e.CreatedAt.Before(start) && !e.CreatedAt.After(end) {
+			rows++
+		}
+	}
+
+	return rows, next, iter.Close()
+}
+
+// ExportEvents reads every day bucket of [start, end], letting the driver
+// fetch the following pages while the current one is decoded. Rows outside
+// the range are dropped client-side as in ScanPages.
+func (r *CassandraRepo) ExportEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	var n int64
+
+	for day := start.Truncate(24 * time.Hour); !day.After(end); day = day.AddDate(0, 0, 1) {
+		iter := r.session.Query(tableSQL(`SELECT event_id, user_id, event_type, payload, created_at FROM events WHERE date_bucket = ?`, r.table),
+			day.Format("20060102")).WithContext(ctx).PageSize(exportPageSize).Prefetch(0.5).Iter()
+
+		var e generator.Event
+
+		for iter.Scan(&e.ID, &e.UserID, &e.EventType, &e.Payload, &e.CreatedAt) {
+			if !e.CreatedAt.Before(start) && !e.CreatedAt.After(end) {
+				n++
+			}
+		}
+
+		if err := iter.Close(); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// EnableTTL makes later inserts expire ttl after their created_at. TTL is
+// set per write, so rows stored before the call never expire. A zero
+// gc_grace_seconds lets TWCS drop fully expired SSTables on its next
+// compaction instead of keeping tombstones for ten days.
+func (r *CassandraRepo) EnableTTL(ctx context.Context, ttl time.Duration) (string, error) {
+	if err := r.session.Query(tableSQL("ALTER TABLE events WITH gc_grace_seconds = 0", r.table)).WithContext(ctx).Exec(); err != nil {
+		return "", fmt.Errorf("failed to alter events table: %w", err)
+	}
+
+	r.ttl = ttl
+
+	return ttlMethodCQL, nil
+}
+
+// ExpireTTL has nothing to wait for: expired cells are filtered on read as
+// soon as their TTL passes, and the disk space is reclaimed by compactions
+// that CQL cannot trigger.
+func (r *CassandraRepo) ExpireTTL(context.Context) error {
+	return nil
+}
+
 func (r *CassandraRepo) GetStorageStats(ctx context.Context) *StorageStats {
 	var stats StorageStats
 
-	if err := r.session.Query("SELECT COUNT(*) FROM events").WithContext(ctx).Scan(&stats.RowCount); err != nil {
+	if err := r.session.Query(tableSQL("SELECT COUNT(*) FROM events", r.table)).WithContext(ctx).Scan(&stats.RowCount); err != nil {
 		return &stats
 	}
 
 	sizeQuery := `
 		SELECT mean_partition_size, partitions_count
 		FROM system.size_estimates
-		WHERE keyspace_name = 'events'
-		AND table_name = 'events'
+		WHERE keyspace_name = ?
+		AND table_name = ?
 	`
-	iter := r.session.Query(sizeQuery).Iter()
+	iter := r.session.Query(sizeQuery, r.keyspace, r.table).Iter()
 
 	var meanSize, partCount int64
 
@@ -191,10 +495,94 @@ func (r *CassandraRepo) GetStorageStats(ctx context.Context) *StorageStats {
 }
 
 func (r *CassandraRepo) Cleanup(ctx context.Context) error {
-	return r.session.Query("TRUNCATE TABLE events").WithContext(ctx).Exec()
+	return r.session.Query(tableSQL("TRUNCATE TABLE events", r.table)).WithContext(ctx).Exec()
 }
 
 func (r *CassandraRepo) Close() error {
 	r.session.Close()
 	return nil
 }
+
+// ServerVersion returns the release_version of the node the query lands on.
+// ScyllaDB reports the Cassandra version it is compatible with.
+func (r *CassandraRepo) ServerVersion(ctx context.Context) (string, error) {
+	var v string
+	err := r.session.Query("SELECT release_version FROM system.local").WithContext(ctx).Scan(&v)
+
+	return v, err
+}
+
+// Settings returns the consistency levels and protocol the session runs
+// with; they change both the throughput and the guarantees measured.
+func (r *CassandraRepo) Settings(context.Context) (map[string]string, error) {
+	settings := map[string]string{
+		"consistency":       r.consistency.String(),
+		"write_consistency": r.writeConsistency.String(),
+		"read_consistency":  r.readConsistency.String(),
+		"insert_mode":       r.insertMode,
+	}
+
+	if r.insertMode != cqlInsertSequential {
+		settings["insert_concurrency"] = strconv.Itoa(r.insertConcurrency)
+	}
+
+	if r.insertMode == cqlInsertBatch {
+		settings["batch_rows"] = strconv.Itoa(r.batchRows)
+	}
+
+	if r.protoVersion > 0 {
+		settings["protocol_version"] = strconv.Itoa(r.protoVersion)
+	}
+
+	if r.localDC != "" {
+		settings["local_dc"] = r.localDC
+	}
+
+	return settings, nil
+}
+
+// CheckPermissions checks that the user can create, write to and drop a
+// table in the keyspace.
+func (r *CassandraRepo) CheckPermissions(ctx context.Context) error {
+	_ = r.session.Query("DROP TABLE IF EXISTS " + checkTable).WithContext(ctx).Exec()
+
+	if err := r.session.Query("CREATE TABLE " + checkTable + " (id int PRIMARY KEY)").WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("%w: %w", ErrCreateDenied, err)
+	}
+
+	insertErr := r.session.Query("INSERT INTO "+checkTable+" (id) VALUES (?)", 1).
+		WithContext(ctx).Consistency(r.writeConsistency).Exec()
+
+	if err := r.session.Query("DROP TABLE " + checkTable).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("%w: %w", ErrDropDenied, err)
+	}
+
+	if insertErr != nil {
+		return fmt.Errorf("%w: %w", ErrWriteDenied, insertErr)
+	}
+
+	return nil
+}
+
+// VerifySchema checks that the events table exists in the keyspace with the
+// columns of an event and its date_bucket partition key. Its name is
+// unquoted, so folded to lower case.
+func (r *CassandraRepo) VerifySchema(ctx context.Context) error {
+	iter := r.session.Query("SELECT column_name FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ?",
+		r.keyspace, strings.ToLower(r.table)).WithContext(ctx).Iter()
+
+	var (
+		columns []string
+		c       string
+	)
+
+	for iter.Scan(&c) {
+		columns = append(columns, c)
+	}
+
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	return checkColumns(r.table, columns, "date_bucket")
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// Insert modes of Cassandra and ScyllaDB:
+//   - concurrent executes the prepared INSERT of each event asynchronously,
+//     up to insert_concurrency in flight, each routed to a replica of its
+//     partition under the token-aware policy.
+//   - batch groups the events by partition into unlogged batches of up to
+//     batch_rows, executed concurrently the same way, so each goes to one
+//     replica set without a coordinator fanning it out.
+//   - sequential executes one INSERT at a time, waiting for each.
+const (
+	cqlInsertConcurrent = "concurrent"
+	cqlInsertBatch      = "batch"
+	cqlInsertSequential = "sequential"
+)
+
+// cqlInsertSettings validates the insert mode of cfg, returning it with the
+// statements in flight and the rows per batch it uses.
+func cqlInsertSettings(cfg config.CassandraConfig) (mode string, concurrency, batchRows int, err error) {
+	mode = cfg.InsertMode
+	if mode == "" {
+		mode = cqlInsertConcurrent
+	}
+
+	switch mode {
+	case cqlInsertConcurrent, cqlInsertBatch:
+		if cfg.InsertConcurrency < 1 {
+			return "", 0, 0, fmt.Errorf("insert_concurrency must be positive, got %d", cfg.InsertConcurrency)
+		}
+
+		if mode == cqlInsertBatch && cfg.BatchRows < 1 {
+			return "", 0, 0, fmt.Errorf("batch_rows must be positive, got %d", cfg.BatchRows)
+		}
+
+		return mode, cfg.InsertConcurrency, cfg.BatchRows, nil
+	case cqlInsertSequential:
+		return mode, 1, 0, nil
+	default:
+		return "", 0, 0, fmt.Errorf("unsupported cassandra insert mode: %s (must be %s, %s or %s)",
+			mode, cqlInsertConcurrent, cqlInsertBatch, cqlInsertSequential)
+	}
+}
+
+// InsertModes returns the insert modes SetInsertMode takes.
+func (r *CassandraRepo) InsertModes() []string {
+	return []string{cqlInsertConcurrent, cqlInsertBatch, cqlInsertSequential}
+}
+
+// InsertMode returns the insert mode in use.
+func (r *CassandraRepo) InsertMode() string {
+	return r.insertMode
+}
+
+// SetInsertMode switches the inserts to another mode with the concurrency
+// and batch size configured.
+func (r *CassandraRepo) SetInsertMode(mode string) error {
+	cfg := r.insertCfg
+	cfg.InsertMode = mode
+
+	mode, concurrency, batchRows, err := cqlInsertSettings(cfg)
+	if err != nil {
+		return err
+	}
+
+	r.insertMode, r.insertConcurrency, r.batchRows = mode, concurrency, batchRows
+
+	return nil
+}
+
+func (r *CassandraRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	now := time.Now()
+
+	switch r.insertMode {
+	case cqlInsertBatch:
+		partitions := cqlPartitionBatches(events, r.batchRows)
+
+		return runBounded(ctx, len(partitions), r.insertConcurrency, func(ctx context.Context, i int) error {
+			batch := r.session.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+			batch.SetConsistency(r.writeConsistency)
+
+			for _, event := range partitions[i] {
+				stmt, args := r.insertStatement(event, now)
+				batch.Query(stmt, args...)
+			}
+
+			return r.session.ExecuteBatch(batch)
+		})
+	default:
+		return runBounded(ctx, len(events), r.insertConcurrency, func(ctx context.Context, i int) error {
+			stmt, args := r.insertStatement(&events[i], now)
+
+			return r.session.Query(stmt, args...).WithContext(ctx).Consistency(r.writeConsistency).Exec()
+		})
+	}
+}
+
+// insertStatement returns the INSERT of one event and its values, adding
+// USING TTL when the TTL benchmark has enabled expiry. gocql prepares the
+// statement once per connection and binds the values in the binary format.
+func (r *CassandraRepo) insertStatement(event *generator.Event, now time.Time) (string, []any) {
+	const insert = `
+			INSERT INTO events (date_bucket, created_at, event_id, user_id, event_type, payload)
+			VALUES (?, ?, ?, ?, ?, ?)`
+
+	args := []any{event.CreatedAt.Format("20060102"), event.CreatedAt, event.ID, event.UserID, event.EventType, event.Payload}
+
+	if r.ttl > 0 {
+		return tableSQL(insert+" USING TTL ?", r.table), append(args, remainingTTL(event.CreatedAt, r.ttl, now))
+	}
+
+	return tableSQL(insert, r.table), args
+}
+
+// cqlPartitionBatches groups events by their date_bucket partition, in the
+// order the partitions first appear, and splits each group into batches of
+// up to rows events.
+func cqlPartitionBatches(events []generator.Event, rows int) [][]*generator.Event {
+	var buckets []string
+
+	byBucket := make(map[string][]*generator.Event)
+
+	for i := range events {
+		bucket := events[i].CreatedAt.Format("20060102")
+		if _, ok := byBucket[bucket]; !ok {
+			buckets = append(buckets, bucket)
+		}
+
+		byBucket[bucket] = append(byBucket[bucket], &events[i])
+	}
+
+	var batches [][]*generator.Event
+
+	for _, bucket := range buckets {
+		batches = slices.AppendSeq(batches, slices.Chunk(byBucket[bucket], rows))
+	}
+
+	return batches
+}
+
+// runBounded calls fn for 0..n-1 with up to limit calls in flight. The first
+// error cancels the context of the other calls and is returned.
+func runBounded(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, limit)
+	)
+
+	for i := range n {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return context.Cause(ctx)
+		}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			if err := fn(ctx, i); err != nil {
+				cancel(err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	return context.Cause(ctx)
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCassandraCluster(t *testing.T) {
+	cluster, err := newCassandraCluster(config.CassandraConfig{Hosts: []string{"127.0.0.1"}, Port: "9042", NumConns: 4})
+	require.NoError(t, err)
+	assert.True(t, cluster.DisableInitialHostLookup, "a single contact point is the whole cluster")
+	assert.Equal(t, 4, cluster.NumConns)
+
+	cluster, err = newCassandraCluster(config.CassandraConfig{Hosts: []string{"10.0.0.1", "10.0.0.2"}})
+	require.NoError(t, err)
+	assert.False(t, cluster.DisableInitialHostLookup, "the rest of the cluster is discovered")
+
+	cluster, err = newCassandraCluster(config.CassandraConfig{Hosts: []string{"127.0.0.1"}, LocalDC: "dc1"})
+	require.NoError(t, err)
+	assert.False(t, cluster.DisableInitialHostLookup, "datacenter awareness needs the ring")
+
+	_, err = newCassandraCluster(config.CassandraConfig{Hosts: []string{"127.0.0.1"}, Port: "cql"})
+	assert.Error(t, err)
+}
+
+func TestNewCassandraClusterSettings(t *testing.T) {
+	cluster, err := newCassandraCluster(config.CassandraConfig{
+		Hosts: []string{"127.0.0.1"}, Consistency: "QUORUM", ProtoVersion: 5, Username: "cassandra", Password: "secret",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, gocql.Quorum, cluster.Consistency)
+	assert.Equal(t, 5, cluster.ProtoVersion)
+	assert.Equal(t, gocql.PasswordAuthenticator{Username: "cassandra", Password: "secret"}, cluster.Authenticator)
+
+	cluster, err = newCassandraCluster(config.CassandraConfig{Hosts: []string{"127.0.0.1"}})
+	require.NoError(t, err)
+	assert.Equal(t, gocql.LocalOne, cluster.Consistency)
+	assert.Nil(t, cluster.Authenticator)
+
+	_, err = newCassandraCluster(config.CassandraConfig{Hosts: []string{"127.0.0.1"}, Consistency: "MOST"})
+	assert.Error(t, err)
+}
+
+func TestCQLReplication(t *testing.T) {
+	assert.Equal(t, "{'class': 'SimpleStrategy', 'replication_factor': 1}", cqlReplication(config.CassandraConfig{}))
+	assert.Equal(t, "{'class': 'NetworkTopologyStrategy', 'dc1': 3}",
+		cqlReplication(config.CassandraConfig{LocalDC: "dc1", ReplicationFactor: 3}))
+}
+
+func TestCassandraSettings(t *testing.T) {
+	repo := &CassandraRepo{
+		consistency: gocql.LocalOne, writeConsistency: gocql.Quorum, readConsistency: gocql.One, protoVersion: 4, localDC: "dc1",
+		insertMode: cqlInsertBatch, insertConcurrency: 64, batchRows: 50,
+	}
+
+	settings, err := repo.Settings(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"consistency": "LOCAL_ONE", "write_consistency": "QUORUM", "read_consistency": "ONE",
+		"protocol_version": "4", "local_dc": "dc1",
+		"insert_mode": "batch", "insert_concurrency": "64", "batch_rows": "50",
+	}, settings)
+}
+
+func TestCQLInsertSettings(t *testing.T) {
+	mode, concurrency, _, err := cqlInsertSettings(config.CassandraConfig{InsertConcurrency: 64})
+	require.NoError(t, err)
+	assert.Equal(t, cqlInsertConcurrent, mode)
+	assert.Equal(t, 64, concurrency)
+
+	mode, concurrency, _, err = cqlInsertSettings(config.CassandraConfig{InsertMode: cqlInsertSequential})
+	require.NoError(t, err)
+	assert.Equal(t, cqlInsertSequential, mode)
+	assert.Equal(t, 1, concurrency)
+
+	_, _, _, err = cqlInsertSettings(config.CassandraConfig{InsertMode: cqlInsertBatch, InsertConcurrency: 8})
+	assert.ErrorContains(t, err, "batch_rows")
+
+	_, _, _, err = cqlInsertSettings(config.CassandraConfig{InsertMode: "logged"})
+	assert.ErrorContains(t, err, "unsupported cassandra insert mode")
+}
+
+func TestCassandraSetInsertMode(t *testing.T) {
+	repo := &CassandraRepo{
+		insertMode: cqlInsertSequential, insertConcurrency: 1,
+		insertCfg: config.CassandraConfig{InsertConcurrency: 64, BatchRows: 50},
+	}
+
+	require.NoError(t, repo.SetInsertMode(cqlInsertBatch))
+	assert.Equal(t, cqlInsertBatch, repo.InsertMode())
+	assert.Equal(t, 64, repo.insertConcurrency, "the configured concurrency is restored")
+	assert.Equal(t, 50, repo.batchRows)
+
+	assert.Error(t, repo.SetInsertMode("logged"))
+	assert.Equal(t, cqlInsertBatch, repo.InsertMode())
+}
+
+func TestCQLPartitionBatches(t *testing.T) {
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []generator.Event{
+		{ID: "a", CreatedAt: day},
+		{ID: "b", CreatedAt: day.Add(24 * time.Hour)},
+		{ID: "c", CreatedAt: day.Add(time.Hour)},
+		{ID: "d", CreatedAt: day.Add(2 * time.Hour)},
+	}
+
+	var ids [][]string
+	for _, batch := range cqlPartitionBatches(events, 2) {
+		var b []string
+		for _, e := range batch {
+			b = append(b, e.ID)
+		}
+
+		ids = append(ids, b)
+	}
+
+	assert.Equal(t, [][]string{{"a", "c"}, {"d"}, {"b"}}, ids)
+}
+
+func TestRunBounded(t *testing.T) {
+	var (
+		mu                    sync.Mutex
+		inFlight, peak, calls int
+	)
+
+	err := runBounded(t.Context(), 50, 4, func(context.Context, int) error {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		calls++
+		mu.Unlock()
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 50, calls)
+	assert.LessOrEqual(t, peak, 4)
+
+	boom := errors.New("boom")
+	err = runBounded(t.Context(), 50, 4, func(_ context.Context, i int) error {
+		if i == 3 {
+			return boom
+		}
+
+		return nil
+	})
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestCQLHostPolicy(t *testing.T) {
+	policy, err := cqlHostPolicy(config.CassandraConfig{HostPolicy: config.HostPolicyTokenAware, LocalDC: "dc1"})
+	require.NoError(t, err)
+	assert.IsType(t, gocql.TokenAwareHostPolicy(nil), policy)
+
+	policy, err = cqlHostPolicy(config.CassandraConfig{LocalDC: "dc1"})
+	require.NoError(t, err)
+	assert.IsType(t, gocql.DCAwareRoundRobinPolicy("dc1"), policy)
+
+	_, err = cqlHostPolicy(config.CassandraConfig{HostPolicy: "nearest"})
+	assert.Error(t, err)
+}
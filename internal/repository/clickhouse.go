@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"math"
 	"time"
@@ -13,20 +15,159 @@ import (
 )
 
 type ClickHouseRepo struct {
-	conn driver.Conn
+	conn       driver.Conn
+	insertMode string // row, column, async or async_wait
+	engine     string
+	indexes    string // secondary index set
+	table      string // name of the events table
+	tenants    bool   // events carry a tenant_id leading the sorting key
 }
 
+// Table engines selectable with CLICKHOUSE_ENGINE.
+const (
+	chEngineMergeTree          = "MergeTree"
+	chEngineReplacingMergeTree = "ReplacingMergeTree"
+	chEngineSummingMergeTree   = "SummingMergeTree"
+	chEngineNull               = "Null"
+)
+
+// Insert modes selectable with CLICKHOUSE_INSERT_MODE.
+const (
+	chInsertRow       = "row"
+	chInsertColumn    = "column"
+	chInsertAsync     = "async"
+	chInsertAsyncWait = "async_wait"
+)
+
 func NewClickHouseRepo(ctx context.Context, cfg *config.ClickHouseConfig) (*ClickHouseRepo, error) {
+	repo, err := newClickHouseRepo(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := createClickHouseDB(ctx, cfg); err != nil {
 		return nil, err
 	}
 
-	return connectClickHouse(ctx, cfg)
+	if repo.conn, err = connectClickHouse(ctx, cfg); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// newClickHouseRepo returns a repository with the validated settings of
+// cfg, before it connects.
+func newClickHouseRepo(cfg *config.ClickHouseConfig) (*ClickHouseRepo, error) {
+	engine := chEngine(cfg.Engine)
+
+	indexes, err := indexSet(cfg.Indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := chConnOpenStrategy(cfg.ConnStrategy); err != nil {
+		return nil, err
+	}
+
+	insertMode, err := chInsertMode(cfg.InsertMode)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := tableName(cfg.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := clickHouseSchema(engine, indexes, cfg.Tenants); err != nil {
+		return nil, err
+	}
+
+	return &ClickHouseRepo{insertMode: insertMode, engine: engine, indexes: indexes, table: table, tenants: cfg.Tenants}, nil
+}
+
+// chEngine returns the table engine, MergeTree when empty.
+func chEngine(engine string) string {
+	if engine == "" {
+		return chEngineMergeTree
+	}
+
+	return engine
+}
+
+// chInsertMode checks an insert mode, row when empty.
+func chInsertMode(mode string) (string, error) {
+	switch mode {
+	case "":
+		return chInsertRow, nil
+	case chInsertRow, chInsertColumn, chInsertAsync, chInsertAsyncWait:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unsupported clickhouse insert mode: %s (must be %s, %s, %s or %s)",
+			mode, chInsertRow, chInsertColumn, chInsertAsync, chInsertAsyncWait)
+	}
+}
+
+// chInsertSettings returns the settings each batch of an insert mode is
+// sent with, nil for those that leave the session settings alone: async
+// inserts, acknowledged once buffered or, with async_wait, once flushed.
+func chInsertSettings(mode string) clickhouse.Settings {
+	switch mode {
+	case chInsertAsync:
+		return clickhouse.Settings{"async_insert": 1, "wait_for_async_insert": 0}
+	case chInsertAsyncWait:
+		return clickhouse.Settings{"async_insert": 1, "wait_for_async_insert": 1}
+	default:
+		return nil
+	}
+}
+
+// InsertModes returns the insert modes SetInsertMode takes.
+func (r *ClickHouseRepo) InsertModes() []string {
+	return []string{chInsertRow, chInsertColumn, chInsertAsync, chInsertAsyncWait}
+}
+
+// InsertMode returns the insert mode in use.
+func (r *ClickHouseRepo) InsertMode() string {
+	if r.insertMode == "" {
+		return chInsertRow
+	}
+
+	return r.insertMode
+}
+
+// SetInsertMode switches batches to row or column appends, or to row
+// appends inserted asynchronously.
+func (r *ClickHouseRepo) SetInsertMode(mode string) error {
+	mode, err := chInsertMode(mode)
+	if err != nil {
+		return err
+	}
+
+	r.insertMode = mode
+
+	return nil
+}
+
+// chConnOpenStrategy parses the order connections are opened to the nodes
+// in: each in turn while it is up, round robin, or at random.
+func chConnOpenStrategy(s string) (clickhouse.ConnOpenStrategy, error) {
+	switch s {
+	case "", "in_order":
+		return clickhouse.ConnOpenInOrder, nil
+	case "round_robin":
+		return clickhouse.ConnOpenRoundRobin, nil
+	case "random":
+		return clickhouse.ConnOpenRandom, nil
+	default:
+		return 0, fmt.Errorf("invalid clickhouse conn_open_strategy %q: must be in_order, round_robin or random", s)
+	}
 }
 
 func createClickHouseDB(ctx context.Context, cfg *config.ClickHouseConfig) error {
 	initConn, err := clickhouse.Open(&clickhouse.Options{
-		Addr: []string{fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)},
+		Addr: cfg.Nodes(),
 		Auth: clickhouse.Auth{
 			Database: "default",
 			Username: cfg.User,
@@ -47,22 +188,52 @@ func createClickHouseDB(ctx context.Context, cfg *config.ClickHouseConfig) error
 	return initConn.Close()
 }
 
-func connectClickHouse(ctx context.Context, cfg *config.ClickHouseConfig) (*ClickHouseRepo, error) {
+// chSettings returns the session settings of cfg: those its fields set,
+// then its Settings over them.
+func chSettings(cfg *config.ClickHouseConfig) clickhouse.Settings {
+	settings := clickhouse.Settings{
+		"max_execution_time": 60,
+	}
+
+	if cfg.InsertQuorum != "" {
+		settings["insert_quorum"] = cfg.InsertQuorum
+	}
+
+	if cfg.AsyncInsert {
+		settings["async_insert"] = 1
+		settings["wait_for_async_insert"] = 0
+
+		if cfg.WaitForAsyncInsert {
+			settings["wait_for_async_insert"] = 1
+		}
+	}
+
+	for name, value := range cfg.Settings {
+		settings[name] = value
+	}
+
+	return settings
+}
+
+func connectClickHouse(ctx context.Context, cfg *config.ClickHouseConfig) (driver.Conn, error) {
+	strategy, err := chConnOpenStrategy(cfg.ConnStrategy)
+	if err != nil {
+		return nil, err
+	}
+
 	conn, err := clickhouse.Open(&clickhouse.Options{
-		Addr: []string{fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)},
+		Addr: cfg.Nodes(),
 		Auth: clickhouse.Auth{
 			Database: cfg.Database,
 			Username: cfg.User,
 			Password: cfg.Password,
 		},
-		Settings: clickhouse.Settings{
-			"max_execution_time": 60,
-		},
+		Settings:         chSettings(cfg),
 		DialTimeout:      5 * time.Second,
-		MaxOpenConns:     10,
-		MaxIdleConns:     5,
-		ConnMaxLifetime:  time.Hour,
-		ConnOpenStrategy: clickhouse.ConnOpenInOrder,
+		MaxOpenConns:     cfg.Pool.MaxOpenConns,
+		MaxIdleConns:     cfg.Pool.MaxIdleConns,
+		ConnMaxLifetime:  cfg.Pool.ConnMaxLifetime,
+		ConnOpenStrategy: strategy,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to clickhouse: %w", err)
@@ -74,72 +245,255 @@ func connectClickHouse(ctx context.Context, cfg *config.ClickHouseConfig) (*Clic
 		return nil, fmt.Errorf("failed to ping clickhouse: %w", err)
 	}
 
-	return &ClickHouseRepo{conn: conn}, nil
+	return conn, nil
 }
 
 func (r *ClickHouseRepo) InitSchema(ctx context.Context) error {
-	if err := r.conn.Exec(ctx, "DROP TABLE IF EXISTS events"); err != nil {
+	if err := r.conn.Exec(ctx, tableSQL("DROP TABLE IF EXISTS events", r.table)); err != nil {
+		return err
+	}
+
+	schema, err := clickHouseSchema(r.engine, r.indexes, r.tenants)
+	if err != nil {
 		return err
 	}
 
-	schema := `
-		CREATE TABLE IF NOT EXISTS events (
+	return r.conn.Exec(ctx, tableSQL(schema, r.table))
+}
+
+// clickHouseSchema returns the events table DDL for a table engine and index
+// set. The sorting key is the only index below full, which adds a data
+// skipping index on the payload. With tenants, tenant_id leads the sorting
+// key, so the events of a tenant are stored together. Engines:
+//   - ReplacingMergeTree adds event_id to the sorting key, so rewritten events
+//     collapse on merge and the stats query reads with FINAL.
+//   - SummingMergeTree collapses rows sharing (event_type, created_at, user_id)
+//     into one with a summed cnt column, pre-aggregating at one-second grain.
+//   - Null discards every insert, isolating client and network overhead.
+func clickHouseSchema(engine, indexes string, tenants bool) (string, error) {
+	columns := `
 			event_id String,
 			user_id UInt64,
 			event_type LowCardinality(String),
 			payload String,
-			created_at DateTime
-		) ENGINE = MergeTree()
+			created_at DateTime`
+	orderBy := "event_type, created_at, user_id"
+
+	if tenants {
+		columns += ",\n\t\t\ttenant_id UInt64"
+		orderBy = "tenant_id, " + orderBy
+	}
+
+	engineClause, column, sortKey, err := chEngineClause(engine)
+	if err != nil {
+		return "", err
+	}
+
+	columns += column
+	orderBy += sortKey
+
+	if engine == chEngineNull {
+		return "CREATE TABLE IF NOT EXISTS events (" + columns + "\n\t\t) ENGINE = Null", nil
+	}
+
+	return `
+		CREATE TABLE IF NOT EXISTS events (` + columns + chTokenIndex(indexes) + `
+		) ENGINE = ` + engineClause + `
 		PARTITION BY toYYYYMM(created_at)
-		ORDER BY (event_type, created_at, user_id)
+		ORDER BY (` + orderBy + `)
 		SETTINGS index_granularity = 8192
-	`
+	`, nil
+}
 
-	return r.conn.Exec(ctx, schema)
+// chEngineClause returns the ENGINE clause of a table engine and the column
+// definition and sorting key columns it adds, each with a leading separator.
+func chEngineClause(engine string) (clause, column, sortKey string, err error) {
+	switch engine {
+	case chEngineMergeTree:
+		return "MergeTree()", "", "", nil
+	case chEngineReplacingMergeTree:
+		return "ReplacingMergeTree()", "", ", event_id", nil
+	case chEngineSummingMergeTree:
+		return "SummingMergeTree(cnt)", ",\n\t\t\tcnt UInt32 DEFAULT 1", "", nil
+	case chEngineNull:
+		return "Null", "", "", nil
+	default:
+		return "", "", "", fmt.Errorf("unsupported clickhouse engine: %s", engine)
+	}
+}
+
+// chTokenIndex returns the token bloom filter definition backing hasToken()
+// in the payload search with the full index set; Null has no parts to index.
+func chTokenIndex(indexes string) string {
+	if indexes != config.IndexesFull {
+		return ""
+	}
+
+	return ",\n\t\t\tINDEX idx_payload_tokens payload TYPE tokenbf_v1(8192, 3, 0) GRANULARITY 4"
+}
+
+// clickHouseStatsSource returns the FROM target and count expression for the
+// stats query, so deduplicated and pre-aggregated engines return exact counts
+// before background merges have run.
+func clickHouseStatsSource(engine string) (from, count string) {
+	switch engine {
+	case chEngineReplacingMergeTree:
+		return "events FINAL", "count()"
+	case chEngineSummingMergeTree:
+		return "events", "sum(cnt)"
+	default:
+		return "events", "count()"
+	}
 }
 
 func (r *ClickHouseRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
-	batch, err := r.conn.PrepareBatch(ctx, "INSERT INTO events")
+	columns := sqlEventColumns
+	if r.tenants {
+		columns += ", tenant_id"
+	}
+
+	if settings := chInsertSettings(r.insertMode); settings != nil {
+		ctx = clickhouse.Context(ctx, clickhouse.WithSettings(settings))
+	}
+
+	batch, err := r.conn.PrepareBatch(ctx, tableSQL("INSERT INTO events ("+columns+")", r.table))
 	if err != nil {
 		return err
 	}
 
+	if r.insertMode == chInsertColumn {
+		if err := appendColumns(batch, events, r.tenants); err != nil {
+			return err
+		}
+
+		return batch.Send()
+	}
+
+	if err := appendRows(batch, events, r.tenants); err != nil {
+		return err
+	}
+
+	return batch.Send()
+}
+
+// appendRows appends events to batch one row at a time.
+func appendRows(batch driver.Batch, events []generator.Event, tenants bool) error {
+	row := make([]any, 0, 6)
+
 	for _, event := range events {
-		err := batch.Append(
+		row = append(row[:0],
 			event.ID,
 			safeInt64ToUint64(event.UserID),
 			event.EventType,
 			event.Payload,
 			event.CreatedAt,
 		)
-		if err != nil {
+		if tenants {
+			row = append(row, safeInt64ToUint64(event.TenantID))
+		}
+
+		if err := batch.Append(row...); err != nil {
 			return err
 		}
 	}
 
-	return batch.Send()
+	return nil
+}
+
+// appendColumns appends events to the block of batch as one slice per
+// column, in the column order of the INSERT, sparing the driver the
+// conversion of each row.
+func appendColumns(batch driver.Batch, events []generator.Event, tenants bool) error {
+	var (
+		ids       = make([]string, len(events))
+		users     = make([]uint64, len(events))
+		types     = make([]string, len(events))
+		payloads  = make([]string, len(events))
+		createdAt = make([]time.Time, len(events))
+	)
+
+	for i := range events {
+		ids[i] = events[i].ID
+		users[i] = safeInt64ToUint64(events[i].UserID)
+		types[i] = events[i].EventType
+		payloads[i] = events[i].Payload
+		createdAt[i] = events[i].CreatedAt
+	}
+
+	columns := []any{ids, users, types, payloads, createdAt}
+
+	if tenants {
+		columns = append(columns, tenantColumn(events))
+	}
+
+	for i, column := range columns {
+		if err := batch.Column(i).Append(column); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tenantColumn returns the tenant_id column of events.
+func tenantColumn(events []generator.Event) []uint64 {
+	tenantIDs := make([]uint64, len(events))
+	for i := range events {
+		tenantIDs[i] = safeInt64ToUint64(events[i].TenantID)
+	}
+
+	return tenantIDs
 }
 
 func (r *ClickHouseRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	return r.eventStats(ctx, "created_at BETWEEN ? AND ?", start, end)
+}
+
+// GetTenantEventStats is GetEventStats over the events of one tenant, which
+// the sorting key narrows to the granules of the tenant.
+func (r *ClickHouseRepo) GetTenantEventStats(ctx context.Context, tenantID int64, start, end time.Time) ([]EventStats, error) {
+	return r.eventStats(ctx, "tenant_id = ? AND created_at BETWEEN ? AND ?", safeInt64ToUint64(tenantID), start, end)
+}
+
+// GetTenantEvents reads the newest events of a tenant from the granules of
+// the tenant, sorting the events of all its event types.
+func (r *ClickHouseRepo) GetTenantEvents(ctx context.Context, tenantID int64, limit int) ([]generator.Event, error) {
+	return r.queryEvents(ctx, tenantEventsQuery, safeInt64ToUint64(tenantID), limit)
+}
+
+// Tenants reports whether the events table has the tenant_id column.
+func (r *ClickHouseRepo) Tenants() bool {
+	return r.tenants
+}
+
+// eventStats runs the stats query over the events matching where.
+func (r *ClickHouseRepo) eventStats(ctx context.Context, where string, args ...any) ([]EventStats, error) {
+	from, count := clickHouseStatsSource(r.engine)
 	query := `
 		SELECT
 			toStartOfHour(created_at) as hour,
 			event_type,
-			count() as cnt,
+			` + count + ` as cnt,
 			uniq(user_id) as unique_users
-		FROM events
-		WHERE created_at BETWEEN ? AND ?
+		FROM ` + from + `
+		WHERE ` + where + `
 		GROUP BY hour, event_type
 		ORDER BY hour DESC
 	`
 
-	rows, err := r.conn.Query(ctx, query, start, end)
+	rows, err := r.conn.Query(ctx, tableSQL(query, r.table), args...)
 	if err != nil {
 		return nil, err
 	}
 
 	defer func() { _ = rows.Close() }()
 
+	return scanClickHouseStats(rows)
+}
+
+// scanClickHouseStats reads the rows of the stats query, whose counts are
+// unsigned.
+func scanClickHouseStats(rows driver.Rows) ([]EventStats, error) {
 	var stats []EventStats
 
 	for rows.Next() {
@@ -160,6 +514,269 @@ func (r *ClickHouseRepo) GetEventStats(ctx context.Context, start, end time.Time
 	return stats, rows.Err()
 }
 
+// GetEventByID scans for the event; event_id is not part of the sorting key,
+// so only the column's granule skipping helps.
+func (r *ClickHouseRepo) GetEventByID(ctx context.Context, eventID string) (*generator.Event, error) {
+	return scanClickHouseEvent(r.conn.QueryRow(ctx, tableSQL(eventByIDQuery, r.table), eventID))
+}
+
+// GetUserEvents scans for the user's events; user_id is the last column of
+// the sorting key, so every granule of every event type is read.
+func (r *ClickHouseRepo) GetUserEvents(ctx context.Context, userID int64, limit int) ([]generator.Event, error) {
+	return r.queryEvents(ctx, userEventsQuery, safeInt64ToUint64(userID), limit)
+}
+
+// ScanPages reads the events in [start, end] in pages of pageSize with keyset
+// pagination on (created_at, event_id). created_at has second precision, so
+// event_id orders the rows of one second.
+func (r *ClickHouseRepo) ScanPages(ctx context.Context, start, end time.Time, pageSize int, page PageFunc) error {
+	return keysetPages(start, pageSize, page, func(after time.Time, afterID string) ([]generator.Event, error) {
+		return r.queryEvents(ctx, eventsPageQuery, after, after, afterID, end, pageSize)
+	})
+}
+
+// ExportEvents streams every event in [start, end]; the native protocol
+// sends the result in column blocks that the driver decodes row by row.
+func (r *ClickHouseRepo) ExportEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	rows, err := r.conn.Query(ctx, tableSQL(exportQuery, r.table), start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	return drainEvents(rows, scanClickHouseEvent)
+}
+
+// CountSessions counts the sessions in [start, end] with lagInFrame over a
+// one-row frame. lagInFrame returns the zero DateTime for the first event of
+// a user, which is always more than the gap away.
+func (r *ClickHouseRepo) CountSessions(ctx context.Context, start, end time.Time, gap time.Duration) (int64, error) {
+	from, _ := clickHouseStatsSource(r.engine)
+
+	var n uint64
+
+	err := r.conn.QueryRow(ctx, tableSQL(`
+		SELECT count() FROM (
+			SELECT created_at, lagInFrame(created_at) OVER (
+				PARTITION BY user_id ORDER BY created_at ROWS BETWEEN 1 PRECEDING AND CURRENT ROW
+			) AS prev
+			FROM `+from+`
+			WHERE created_at BETWEEN ? AND ?
+		) WHERE dateDiff('second', prev, created_at) > ?
+	`, r.table), start, end, int64(gap/time.Second)).Scan(&n)
+
+	return safeUint64ToInt64(n), err
+}
+
+// queryEvents runs query and reads every row with scanClickHouseEvent.
+func (r *ClickHouseRepo) queryEvents(ctx context.Context, query string, args ...any) ([]generator.Event, error) {
+	rows, err := r.conn.Query(ctx, tableSQL(query, r.table), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var events []generator.Event
+
+	for rows.Next() {
+		e, err := scanClickHouseEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, *e)
+	}
+
+	return events, rows.Err()
+}
+
+// scanClickHouseEvent is scanSQLEvent for the unsigned user_id column.
+func scanClickHouseEvent(row rowScanner) (*generator.Event, error) {
+	var (
+		e      generator.Event
+		userID uint64
+	)
+
+	err := row.Scan(&e.ID, &userID, &e.EventType, &e.Payload, &e.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEventNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	e.UserID = safeUint64ToInt64(userID)
+
+	return &e, nil
+}
+
+// GetTopUsers returns the n users with the most events in [start, end],
+// counting with the same engine-aware source as the stats query.
+func (r *ClickHouseRepo) GetTopUsers(ctx context.Context, start, end time.Time, n int) ([]UserCount, error) {
+	from, count := clickHouseStatsSource(r.engine)
+
+	rows, err := r.conn.Query(ctx, tableSQL(`
+		SELECT user_id, toUInt64(`+count+`) AS cnt
+		FROM `+from+`
+		WHERE created_at BETWEEN ? AND ?
+		GROUP BY user_id
+		ORDER BY cnt DESC, user_id
+		LIMIT ?
+	`, r.table), start, end, n)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var top []UserCount
+
+	for rows.Next() {
+		var userID, cnt uint64
+		if err := rows.Scan(&userID, &cnt); err != nil {
+			return nil, err
+		}
+
+		top = append(top, UserCount{UserID: safeUint64ToInt64(userID), Count: safeUint64ToInt64(cnt)})
+	}
+
+	return top, rows.Err()
+}
+
+// GetUserGroups groups the events in [start, end] by user_id. The aggregation
+// state stays in memory unless external GROUP BY is enabled on the server.
+func (r *ClickHouseRepo) GetUserGroups(ctx context.Context, start, end time.Time) (*UserGroupStats, error) {
+	from, count := clickHouseStatsSource(r.engine)
+
+	var groups, maxCount uint64
+
+	err := r.conn.QueryRow(ctx, tableSQL(`
+		SELECT count(), max(cnt)
+		FROM (
+			SELECT user_id, toUInt64(`+count+`) AS cnt
+			FROM `+from+`
+			WHERE created_at BETWEEN ? AND ?
+			GROUP BY user_id
+		)
+	`, r.table), start, end).Scan(&groups, &maxCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserGroupStats{Groups: safeUint64ToInt64(groups), MaxCount: safeUint64ToInt64(maxCount)}, nil
+}
+
+// SearchPayload counts the events in [start, end] whose payload contains
+// token as a whole word, skipping granules through the token bloom filter.
+func (r *ClickHouseRepo) SearchPayload(ctx context.Context, token string, start, end time.Time) (int64, error) {
+	return r.countWhere(ctx, "created_at BETWEEN ? AND ? AND hasToken(payload, ?)", start, end, token)
+}
+
+// CountEvents counts the events in [start, end] with the engine-aware count.
+func (r *ClickHouseRepo) CountEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return r.countWhere(ctx, "created_at BETWEEN ? AND ?", start, end)
+}
+
+// RunStatement runs a custom query scenario statement through the native
+// driver with the window bound to its two parameters, and returns the rows
+// it produced.
+func (r *ClickHouseRepo) RunStatement(ctx context.Context, statement string, start, end time.Time) (int64, error) {
+	rows, err := r.conn.Query(ctx, tableSQL(statement, r.table), start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	return countRows(rows)
+}
+
+// EventExists reports whether an event with the ID is stored; event_id is not
+// in the sorting key, so the check scans until the first match.
+func (r *ClickHouseRepo) EventExists(ctx context.Context, eventID string) (bool, error) {
+	var n uint64
+	err := r.conn.QueryRow(ctx, tableSQL(existsQuery, r.table), eventID).Scan(&n)
+
+	return n > 0, err
+}
+
+// InitUsersSchema recreates the users dimension table sorted by user_id.
+func (r *ClickHouseRepo) InitUsersSchema(ctx context.Context) error {
+	if err := r.conn.Exec(ctx, "DROP TABLE IF EXISTS users"); err != nil {
+		return err
+	}
+
+	return r.conn.Exec(ctx, `
+		CREATE TABLE users (
+			user_id UInt64,
+			country LowCardinality(String),
+			plan LowCardinality(String)
+		) ENGINE = MergeTree()
+		ORDER BY user_id
+	`)
+}
+
+func (r *ClickHouseRepo) InsertUsers(ctx context.Context, users []generator.User) error {
+	batch, err := r.conn.PrepareBatch(ctx, "INSERT INTO users (user_id, country, plan)")
+	if err != nil {
+		return err
+	}
+
+	for _, u := range users {
+		if err := batch.Append(safeInt64ToUint64(u.ID), u.Country, u.Plan); err != nil {
+			return err
+		}
+	}
+
+	return batch.Send()
+}
+
+// GetCountryBreakdown joins the events in [start, end] to users and counts
+// them per country. The right-hand users table is built into the in-memory
+// hash table.
+func (r *ClickHouseRepo) GetCountryBreakdown(ctx context.Context, start, end time.Time) ([]CountryCount, error) {
+	from, count := clickHouseStatsSource(r.engine)
+
+	rows, err := r.conn.Query(ctx, tableSQL(`
+		SELECT users.country, toInt64(`+count+`) AS n
+		FROM `+from+` INNER JOIN users ON users.user_id = events.user_id
+		WHERE events.created_at BETWEEN ? AND ?
+		GROUP BY users.country
+		ORDER BY n DESC
+	`, r.table), start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var counts []CountryCount
+
+	for rows.Next() {
+		var c CountryCount
+		if err := rows.Scan(&c.Country, &c.Count); err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+// countWhere counts the events matching where, exactly for every engine.
+func (r *ClickHouseRepo) countWhere(ctx context.Context, where string, args ...any) (int64, error) {
+	from, count := clickHouseStatsSource(r.engine)
+
+	var n uint64
+	err := r.conn.QueryRow(ctx, tableSQL("SELECT toUInt64("+count+") FROM "+from+" WHERE "+where, r.table), args...).Scan(&n)
+
+	return safeUint64ToInt64(n), err
+}
+
 func (r *ClickHouseRepo) GetStorageStats(ctx context.Context) *StorageStats {
 	var stats StorageStats
 
@@ -174,7 +791,7 @@ func (r *ClickHouseRepo) GetStorageStats(ctx context.Context) *StorageStats {
 		AND active = 1
 	`
 
-	row := r.conn.QueryRow(ctx, query)
+	row := r.conn.QueryRow(ctx, tableSQL(query, r.table))
 
 	var totalBytes, totalRows uint64
 
@@ -187,14 +804,138 @@ func (r *ClickHouseRepo) GetStorageStats(ctx context.Context) *StorageStats {
 
 	stats.TotalSize = safeUint64ToInt64(totalBytes)
 	stats.RowCount = safeUint64ToInt64(totalRows)
-	stats.CompressionPct = (1 - compressionRatio) * 100
+
+	// The Null engine has no parts, so the ratio is 0/0.
+	if !math.IsNaN(compressionRatio) {
+		stats.CompressionPct = (1 - compressionRatio) * 100
+	}
 	stats.IndexSize = 0
 
 	return &stats
 }
 
+// DeleteOlderThan drops the monthly partitions that end at or before the
+// cutoff, then removes the older rows of the boundary partition with a
+// lightweight DELETE, which masks rows until merges rewrite the parts.
+func (r *ClickHouseRepo) DeleteOlderThan(ctx context.Context, cutoff time.Time) (*DeleteStats, error) {
+	if r.engine == chEngineNull {
+		return nil, errors.New("the Null engine stores no rows to delete")
+	}
+
+	dropped, err := r.dropPartitionsBefore(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	var remaining uint64
+	if err := r.conn.QueryRow(ctx, tableSQL("SELECT count() FROM events WHERE created_at < ?", r.table), cutoff).Scan(&remaining); err != nil {
+		return nil, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	if remaining > 0 {
+		if err := r.conn.Exec(ctx, tableSQL("DELETE FROM events WHERE created_at < ?", r.table), cutoff); err != nil {
+			return nil, fmt.Errorf("failed to delete events: %w", err)
+		}
+	}
+
+	deleted := safeUint64ToInt64(remaining)
+
+	return &DeleteStats{Rows: dropped + deleted, Method: partitionDeleteMethod(dropped > 0, deleted)}, nil
+}
+
+// dropPartitionsBefore drops every toYYYYMM partition that ends at or before
+// the cutoff and returns the number of rows its active parts held.
+func (r *ClickHouseRepo) dropPartitionsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	partitions, err := r.partitionRows(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var dropped int64
+
+	for id, n := range partitions {
+		if end, err := monthPartitionEnd(id); err != nil || end.After(cutoff) {
+			continue
+		}
+
+		if err := r.conn.Exec(ctx, tableSQL("ALTER TABLE events DROP PARTITION ID '"+id+"'", r.table)); err != nil {
+			return dropped, fmt.Errorf("failed to drop partition %s: %w", id, err)
+		}
+
+		dropped += safeUint64ToInt64(n)
+	}
+
+	return dropped, nil
+}
+
+// EnableTTL adds a TTL clause on created_at. Materializing it for the rows
+// already stored is left to ExpireTTL, so the insert run that follows is not
+// competing with a background mutation.
+func (r *ClickHouseRepo) EnableTTL(ctx context.Context, ttl time.Duration) (string, error) {
+	if r.engine == chEngineNull {
+		return "", errors.New("the Null engine stores no rows to expire")
+	}
+
+	ctx = clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{"materialize_ttl_after_modify": 0}))
+
+	query := fmt.Sprintf("ALTER TABLE events MODIFY TTL created_at + INTERVAL %d SECOND", ttlSeconds(ttl))
+	if err := r.conn.Exec(ctx, tableSQL(query, r.table)); err != nil {
+		return "", fmt.Errorf("failed to set ttl: %w", err)
+	}
+
+	return ttlMethodClause, nil
+}
+
+// ExpireTTL materializes the TTL on every part and waits for the mutation,
+// which rewrites the parts without the expired rows.
+func (r *ClickHouseRepo) ExpireTTL(ctx context.Context) error {
+	ctx = clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{"mutations_sync": 2}))
+
+	if err := r.conn.Exec(ctx, tableSQL("ALTER TABLE events MATERIALIZE TTL", r.table)); err != nil {
+		return fmt.Errorf("failed to materialize ttl: %w", err)
+	}
+
+	return nil
+}
+
+// partitionRows returns the row count of the active parts per partition ID.
+func (r *ClickHouseRepo) partitionRows(ctx context.Context) (map[string]uint64, error) {
+	rows, err := r.conn.Query(ctx, tableSQL(`
+		SELECT partition_id, sum(rows)
+		FROM system.parts
+		WHERE database = currentDatabase() AND table = 'events' AND active = 1
+		GROUP BY partition_id
+	`, r.table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	partitions := map[string]uint64{}
+
+	for rows.Next() {
+		var (
+			id string
+			n  uint64
+		)
+
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+
+		partitions[id] = n
+	}
+
+	return partitions, rows.Err()
+}
+
 func (r *ClickHouseRepo) Cleanup(ctx context.Context) error {
-	return r.conn.Exec(ctx, "TRUNCATE TABLE events")
+	if r.engine == chEngineNull {
+		return nil // nothing is stored
+	}
+
+	return r.conn.Exec(ctx, tableSQL("TRUNCATE TABLE events", r.table))
 }
 
 func (r *ClickHouseRepo) Close() error {
@@ -216,3 +957,102 @@ func safeUint64ToInt64(v uint64) int64 {
 
 	return int64(v)
 }
+
+// ServerVersion returns the version of the ClickHouse server.
+func (r *ClickHouseRepo) ServerVersion(ctx context.Context) (string, error) {
+	var v string
+	err := r.conn.QueryRow(ctx, "SELECT version()").Scan(&v)
+
+	return v, err
+}
+
+// Settings returns the session settings that differ from the server
+// defaults, as the server applies them: those of the connection and of the
+// user's settings profile.
+func (r *ClickHouseRepo) Settings(ctx context.Context) (map[string]string, error) {
+	rows, err := r.conn.Query(ctx, "SELECT name, value FROM system.settings WHERE changed")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings := make(map[string]string)
+
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+
+		settings[name] = value
+	}
+
+	settings["insert_mode"] = r.InsertMode()
+	for name, value := range chInsertSettings(r.insertMode) {
+		settings[name] = fmt.Sprint(value)
+	}
+
+	return settings, rows.Err()
+}
+
+// CheckPermissions checks that the user can create, write to and drop a
+// table in the database.
+func (r *ClickHouseRepo) CheckPermissions(ctx context.Context) error {
+	_ = r.conn.Exec(ctx, "DROP TABLE IF EXISTS "+checkTable)
+
+	if err := r.conn.Exec(ctx, "CREATE TABLE "+checkTable+" (id UInt64) ENGINE = Memory"); err != nil {
+		return fmt.Errorf("%w: %w", ErrCreateDenied, err)
+	}
+
+	insertErr := r.conn.Exec(ctx, "INSERT INTO "+checkTable+" (id) VALUES (1)")
+
+	if err := r.conn.Exec(ctx, "DROP TABLE "+checkTable); err != nil {
+		return fmt.Errorf("%w: %w", ErrDropDenied, err)
+	}
+
+	if insertErr != nil {
+		return fmt.Errorf("%w: %w", ErrWriteDenied, insertErr)
+	}
+
+	return nil
+}
+
+// VerifySchema checks that the events table exists in the database with the
+// columns of an event, tenant_id with tenants and cnt under SummingMergeTree.
+func (r *ClickHouseRepo) VerifySchema(ctx context.Context) error {
+	columns, err := r.tableColumns(ctx)
+	if err != nil {
+		return err
+	}
+
+	extra := tenantColumns(r.tenants)
+	if r.engine == chEngineSummingMergeTree {
+		extra = append(extra, "cnt")
+	}
+
+	return checkColumns(r.table, columns, extra...)
+}
+
+// tableColumns returns the column names of the events table, none when it
+// does not exist.
+func (r *ClickHouseRepo) tableColumns(ctx context.Context) ([]string, error) {
+	rows, err := r.conn.Query(ctx, "SELECT name FROM system.columns WHERE database = currentDatabase() AND table = ?", r.table)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var columns []string
+
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, err
+		}
+
+		columns = append(columns, c)
+	}
+
+	return columns, rows.Err()
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickHouseSchema(t *testing.T) {
+	schema, err := clickHouseSchema(chEngineMergeTree, config.IndexesFull, false)
+	require.NoError(t, err)
+	assert.Contains(t, schema, "ENGINE = MergeTree()")
+	assert.Contains(t, schema, "ORDER BY (event_type, created_at, user_id)")
+	assert.NotContains(t, schema, "cnt")
+	assert.Contains(t, schema, "INDEX idx_payload_tokens payload TYPE tokenbf_v1")
+
+	schema, err = clickHouseSchema(chEngineReplacingMergeTree, config.IndexesFull, false)
+	require.NoError(t, err)
+	assert.Contains(t, schema, "ENGINE = ReplacingMergeTree()")
+	assert.Contains(t, schema, "ORDER BY (event_type, created_at, user_id, event_id)")
+
+	schema, err = clickHouseSchema(chEngineSummingMergeTree, config.IndexesFull, false)
+	require.NoError(t, err)
+	assert.Contains(t, schema, "ENGINE = SummingMergeTree(cnt)")
+	assert.Contains(t, schema, "cnt UInt32 DEFAULT 1")
+
+	schema, err = clickHouseSchema(chEngineNull, config.IndexesFull, false)
+	require.NoError(t, err)
+	assert.Contains(t, schema, "ENGINE = Null")
+	assert.NotContains(t, schema, "ORDER BY")
+	assert.NotContains(t, schema, "INDEX")
+
+	schema, err = clickHouseSchema(chEngineMergeTree, config.IndexesMinimal, false)
+	require.NoError(t, err)
+	assert.NotContains(t, schema, "INDEX")
+
+	_, err = clickHouseSchema("Log", config.IndexesFull, false)
+	assert.Error(t, err)
+}
+
+func TestClickHouseSchemaTenants(t *testing.T) {
+	schema, err := clickHouseSchema(chEngineMergeTree, config.IndexesMinimal, true)
+	require.NoError(t, err)
+	assert.Contains(t, schema, "tenant_id UInt64")
+	assert.Contains(t, schema, "ORDER BY (tenant_id, event_type, created_at, user_id)")
+
+	schema, err = clickHouseSchema(chEngineNull, config.IndexesMinimal, true)
+	require.NoError(t, err)
+	assert.Contains(t, schema, "tenant_id UInt64", "Null takes the inserts of every schema")
+}
+
+func TestClickHouseStatsSource(t *testing.T) {
+	from, count := clickHouseStatsSource(chEngineReplacingMergeTree)
+	assert.Equal(t, "events FINAL", from)
+	assert.Equal(t, "count()", count)
+
+	from, count = clickHouseStatsSource(chEngineSummingMergeTree)
+	assert.Equal(t, "events", from)
+	assert.Equal(t, "sum(cnt)", count)
+
+	from, count = clickHouseStatsSource(chEngineMergeTree)
+	assert.Equal(t, "events", from)
+	assert.Equal(t, "count()", count)
+}
+
+func TestChConnOpenStrategy(t *testing.T) {
+	for s, want := range map[string]clickhouse.ConnOpenStrategy{
+		"":            clickhouse.ConnOpenInOrder,
+		"in_order":    clickhouse.ConnOpenInOrder,
+		"round_robin": clickhouse.ConnOpenRoundRobin,
+		"random":      clickhouse.ConnOpenRandom,
+	} {
+		got, err := chConnOpenStrategy(s)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, s)
+	}
+
+	_, err := chConnOpenStrategy("nearest")
+	assert.Error(t, err)
+}
+
+func TestChInsertMode(t *testing.T) {
+	for mode, want := range map[string]string{"": "row", "row": "row", "column": "column", "async": "async", "async_wait": "async_wait"} {
+		got, err := chInsertMode(mode)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, mode)
+	}
+
+	_, err := chInsertMode("columnar")
+	assert.Error(t, err)
+
+	repo := &ClickHouseRepo{}
+	assert.Equal(t, "row", repo.InsertMode())
+	require.NoError(t, repo.SetInsertMode("column"))
+	assert.Equal(t, "column", repo.InsertMode())
+	assert.Error(t, repo.SetInsertMode("columnar"))
+	assert.Equal(t, "column", repo.InsertMode())
+}
+
+func TestChInsertSettings(t *testing.T) {
+	assert.Nil(t, chInsertSettings(chInsertRow))
+	assert.Nil(t, chInsertSettings(chInsertColumn))
+	assert.Equal(t, clickhouse.Settings{"async_insert": 1, "wait_for_async_insert": 0}, chInsertSettings(chInsertAsync))
+	assert.Equal(t, clickhouse.Settings{"async_insert": 1, "wait_for_async_insert": 1}, chInsertSettings(chInsertAsyncWait))
+}
+
+func TestChSettings(t *testing.T) {
+	settings := chSettings(&config.ClickHouseConfig{
+		InsertQuorum: "2",
+		AsyncInsert:  true,
+		Settings:     map[string]string{"wait_for_async_insert": "1", "max_threads": "8"},
+	})
+
+	assert.Equal(t, clickhouse.Settings{
+		"max_execution_time":    60,
+		"insert_quorum":         "2",
+		"async_insert":          1,
+		"wait_for_async_insert": "1",
+		"max_threads":           "8",
+	}, settings, "the settings take precedence over the fields")
+
+	settings = chSettings(&config.ClickHouseConfig{AsyncInsert: true, WaitForAsyncInsert: true})
+	assert.Equal(t, 1, settings["wait_for_async_insert"])
+
+	settings = chSettings(&config.ClickHouseConfig{WaitForAsyncInsert: true})
+	assert.NotContains(t, settings, "wait_for_async_insert", "it applies only to async inserts")
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Count and existence queries shared by the SQL backends, in "?" and "$n"
+// bind styles. The "?" existence check counts a subquery limited to one row,
+// which every dialect accepts; PostgreSQL uses EXISTS.
+const (
+	countQuery        = "SELECT COUNT(*) FROM events WHERE created_at BETWEEN ? AND ?"
+	countQueryDollar  = "SELECT COUNT(*) FROM events WHERE created_at BETWEEN $1 AND $2"
+	existsQuery       = "SELECT COUNT(*) FROM (SELECT 1 FROM events WHERE event_id = ? LIMIT 1) e"
+	existsQueryDollar = "SELECT CASE WHEN EXISTS (SELECT 1 FROM events WHERE event_id = $1) THEN 1 ELSE 0 END"
+)
+
+// querySQLCount runs a query returning a single count.
+func querySQLCount(ctx context.Context, db *sql.DB, query string, args ...any) (int64, error) {
+	var n int64
+	err := db.QueryRowContext(ctx, query, args...).Scan(&n)
+
+	return n, err
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// DorisRepo ingests events through stream load and queries them over the
+// MySQL protocol. Batches are sent straight to the backend HTTP port, so the
+// frontend redirect does not need to be reachable from the host.
+type DorisRepo struct {
+	db     *sql.DB
+	loader *streamLoader
+	table  string // name of the events table
+}
+
+func NewDorisRepo(ctx context.Context, cfg *config.StarRocksConfig) (*DorisRepo, error) {
+	table, err := tableName(cfg.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := openMySQLWire(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to doris: %w", err)
+	}
+
+	return &DorisRepo{
+		db:     db,
+		loader: newStreamLoader(cfg.Host, cfg.HTTPPort, cfg.Database, table, cfg.User, cfg.Password),
+		table:  table,
+	}, nil
+}
+
+func (r *DorisRepo) InitSchema(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, tableSQL("DROP TABLE IF EXISTS events FORCE", r.table)); err != nil {
+		return err
+	}
+
+	schema := `
+		CREATE TABLE events (
+			created_at DATETIME(6) NOT NULL,
+			event_type VARCHAR(50) NOT NULL,
+			user_id BIGINT NOT NULL,
+			event_id VARCHAR(255) NOT NULL,
+			payload STRING
+		)
+		DUPLICATE KEY(created_at, event_type)
+		AUTO PARTITION BY RANGE (date_trunc(created_at, 'month')) ()
+		DISTRIBUTED BY HASH(user_id) BUCKETS 8
+		PROPERTIES ("replication_num" = "1")
+	`
+
+	_, err := r.db.ExecContext(ctx, tableSQL(schema, r.table))
+
+	return err
+}
+
+func (r *DorisRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	return r.loader.load(ctx, events)
+}
+
+func (r *DorisRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	return queryMySQLWireEventStats(ctx, r.db, r.table, "date_trunc(created_at, 'hour')", start, end)
+}
+
+// GetEventByID scans for the event; event_id is not part of the sort key.
+func (r *DorisRepo) GetEventByID(ctx context.Context, eventID string) (*generator.Event, error) {
+	return scanSQLEvent(r.db.QueryRowContext(ctx, tableSQL(eventByIDQuery, r.table), eventID))
+}
+
+// GetUserEvents reads the user's events from the single bucket user_id hashes
+// to in each partition.
+func (r *DorisRepo) GetUserEvents(ctx context.Context, userID int64, limit int) ([]generator.Event, error) {
+	return querySQLEvents(ctx, r.db, scanSQLEvent, tableSQL(userEventsQuery, r.table), userID, limit)
+}
+
+// GetTopUsers returns the n users with the most events in [start, end].
+func (r *DorisRepo) GetTopUsers(ctx context.Context, start, end time.Time, n int) ([]UserCount, error) {
+	return querySQLTopUsers(ctx, r.db, tableSQL(topUsersQuery, r.table), start.UTC(), end.UTC(), n)
+}
+
+// GetUserGroups groups the events in [start, end] by user_id.
+func (r *DorisRepo) GetUserGroups(ctx context.Context, start, end time.Time) (*UserGroupStats, error) {
+	return querySQLUserGroups(ctx, r.db, tableSQL(userGroupsQuery, r.table), start.UTC(), end.UTC())
+}
+
+// SearchPayload counts the events in [start, end] whose payload contains
+// token, scanning the payload column.
+func (r *DorisRepo) SearchPayload(ctx context.Context, token string, start, end time.Time) (int64, error) {
+	return querySQLPayloadSearch(ctx, r.db, tableSQL(payloadSearchQuery, r.table), start.UTC(), end.UTC(), token)
+}
+
+// ScanPages pages through [start, end] by (created_at, event_id) keys in UTC.
+func (r *DorisRepo) ScanPages(ctx context.Context, start, end time.Time, pageSize int, page PageFunc) error {
+	return keysetPages(start, pageSize, page, func(after time.Time, afterID string) ([]generator.Event, error) {
+		return querySQLEvents(ctx, r.db, scanSQLEvent, tableSQL(eventsPageQuery, r.table), after.UTC(), after.UTC(), afterID, end.UTC(), pageSize)
+	})
+}
+
+// ExportEvents streams every event in [start, end] in UTC.
+func (r *DorisRepo) ExportEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return exportSQLEvents(ctx, r.db, scanSQLEvent, tableSQL(exportQuery, r.table), start.UTC(), end.UTC())
+}
+
+// CountSessions counts the sessions in [start, end] with LAG.
+func (r *DorisRepo) CountSessions(ctx context.Context, start, end time.Time, gap time.Duration) (int64, error) {
+	return querySQLCount(ctx, r.db, tableSQL(sessionsQueryMySQL, r.table), start.UTC(), end.UTC(), int64(gap/time.Second))
+}
+
+// CountEvents counts the events in [start, end].
+func (r *DorisRepo) CountEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return querySQLCount(ctx, r.db, tableSQL(countQuery, r.table), start.UTC(), end.UTC())
+}
+
+// RunStatement runs a custom query scenario statement with the window bound
+// in UTC.
+func (r *DorisRepo) RunStatement(ctx context.Context, statement string, start, end time.Time) (int64, error) {
+	return runSQLStatement(ctx, r.db, tableSQL(statement, r.table), start.UTC(), end.UTC())
+}
+
+// EventExists reports whether an event with the ID is stored.
+func (r *DorisRepo) EventExists(ctx context.Context, eventID string) (bool, error) {
+	n, err := querySQLCount(ctx, r.db, tableSQL(existsQuery, r.table), eventID)
+	return n > 0, err
+}
+
+// InitUsersSchema recreates the users dimension table with a UNIQUE KEY model,
+// bucketed by user_id like events.
+func (r *DorisRepo) InitUsersSchema(ctx context.Context) error {
+	return initMySQLWireUsers(ctx, r.db, "UNIQUE KEY")
+}
+
+// InsertUsers writes the users with multi-row INSERTs, one load per chunk.
+func (r *DorisRepo) InsertUsers(ctx context.Context, users []generator.User) error {
+	return insertSQLUsers(ctx, r.db, users, false)
+}
+
+// GetCountryBreakdown joins the events in [start, end] to users and counts
+// them per country.
+func (r *DorisRepo) GetCountryBreakdown(ctx context.Context, start, end time.Time) ([]CountryCount, error) {
+	return querySQLCountryBreakdown(ctx, r.db, tableSQL(countryBreakdownQuery, r.table), start.UTC(), end.UTC())
+}
+
+// GetStorageStats sums the local and remote data size of every tablet replica
+// listed by SHOW TABLETS.
+func (r *DorisRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	stats := &StorageStats{Details: map[string]int64{}}
+
+	if sums, tablets, err := r.sumTablets(ctx, "LocalDataSize", "RemoteDataSize"); err == nil {
+		stats.TotalSize = sums["LocalDataSize"] + sums["RemoteDataSize"]
+		stats.Details["tablets"] = tablets
+
+		if sums["RemoteDataSize"] > 0 {
+			stats.Details["remote_bytes"] = sums["RemoteDataSize"]
+		}
+	}
+
+	_ = r.db.QueryRowContext(ctx, tableSQL(`SELECT COUNT(*) FROM events`, r.table)).Scan(&stats.RowCount)
+
+	return stats
+}
+
+// sumTablets sums the named columns of SHOW TABLETS, whose column set varies
+// between Doris versions, and returns the number of rows seen.
+func (r *DorisRepo) sumTablets(ctx context.Context, columns ...string) (map[string]int64, int64, error) {
+	rows, err := r.db.QueryContext(ctx, tableSQL("SHOW TABLETS FROM events", r.table))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return sumColumns(rows, names, columns)
+}
+
+// sumColumns sums the named columns of rows, read as text, and counts the
+// rows; names are the columns rows has.
+func sumColumns(rows *sql.Rows, names, columns []string) (map[string]int64, int64, error) {
+	values := make([]sql.NullString, len(names))
+	dest := make([]any, len(names))
+
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	sums := make(map[string]int64, len(columns))
+
+	var count int64
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, 0, err
+		}
+
+		count++
+
+		for i, name := range names {
+			if slices.Contains(columns, name) {
+				n, _ := strconv.ParseInt(values[i].String, 10, 64)
+				sums[name] += n
+			}
+		}
+	}
+
+	return sums, count, rows.Err()
+}
+
+func (r *DorisRepo) Cleanup(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, tableSQL("TRUNCATE TABLE events", r.table))
+	return err
+}
+
+func (r *DorisRepo) Close() error {
+	r.loader.close()
+	return r.db.Close()
+}
+
+// ServerVersion returns version_comment, e.g. "Doris version doris-2.1.0";
+// VERSION() is the MySQL version the server poses as.
+func (r *DorisRepo) ServerVersion(ctx context.Context) (string, error) {
+	return querySQLVersion(ctx, r.db, "SELECT @@version_comment")
+}
+
+// VerifySchema checks that the events table exists in the database with the
+// columns of an event.
+func (r *DorisRepo) VerifySchema(ctx context.Context) error {
+	return verifySQLSchema(ctx, r.db, r.table, nil, `
+		SELECT COLUMN_NAME FROM information_schema.columns
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+	`, r.table)
+}
@@ -0,0 +1,279 @@
+//go:build duckdb
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marcboeker/go-duckdb/v2"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// DuckDBRepo stores events in an embedded DuckDB database, loading batches
+// through the Appender API instead of INSERT statements.
+type DuckDBRepo struct {
+	db    *sql.DB
+	cfg   *config.DuckDBConfig
+	table string // name of the events table
+}
+
+func NewDuckDBRepo(ctx context.Context, cfg *config.DuckDBConfig) (*DuckDBRepo, error) {
+	table, err := tableName(cfg.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	connector, err := duckdb.NewConnector(cfg.DSN(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open duckdb database: %w", err)
+	}
+
+	db := sql.OpenDB(connector)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("failed to ping duckdb: %w", err)
+	}
+
+	return &DuckDBRepo{db: db, cfg: cfg, table: table}, nil
+}
+
+func (r *DuckDBRepo) InitSchema(ctx context.Context) error {
+	schema := `
+		DROP TABLE IF EXISTS events;
+
+		CREATE TABLE events (
+			event_id VARCHAR NOT NULL,
+			user_id BIGINT NOT NULL,
+			event_type VARCHAR NOT NULL,
+			payload VARCHAR,
+			created_at TIMESTAMP NOT NULL
+		);
+	`
+
+	_, err := r.db.ExecContext(ctx, tableSQL(schema, r.table))
+
+	return err
+}
+
+func (r *DuckDBRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	return conn.Raw(func(driverConn any) error {
+		dc, ok := driverConn.(driver.Conn)
+		if !ok {
+			return errors.New("unexpected duckdb driver connection type")
+		}
+
+		appender, err := duckdb.NewAppenderFromConn(dc, "", r.table)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			err := appender.AppendRow(event.ID, event.UserID, event.EventType, event.Payload, event.CreatedAt)
+			if err != nil {
+				_ = appender.Close()
+				return err
+			}
+		}
+
+		// Close flushes the appended rows into the table.
+		return appender.Close()
+	})
+}
+
+func (r *DuckDBRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	query := `
+		SELECT
+			date_trunc('hour', created_at) AS hour,
+			event_type,
+			COUNT(*) AS count,
+			COUNT(DISTINCT user_id) AS unique_users
+		FROM events
+		WHERE created_at BETWEEN ? AND ?
+		GROUP BY hour, event_type
+		ORDER BY hour DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, tableSQL(query, r.table), start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var stats []EventStats
+
+	for rows.Next() {
+		var s EventStats
+		if err := rows.Scan(&s.Hour, &s.EventType, &s.Count, &s.UniqueUsers); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetEventByID scans for the event; the table has no index on event_id.
+func (r *DuckDBRepo) GetEventByID(ctx context.Context, eventID string) (*generator.Event, error) {
+	return scanSQLEvent(r.db.QueryRowContext(ctx, tableSQL(eventByIDQuery, r.table), eventID))
+}
+
+// GetUserEvents scans for the user's events and sorts the matches.
+func (r *DuckDBRepo) GetUserEvents(ctx context.Context, userID int64, limit int) ([]generator.Event, error) {
+	return querySQLEvents(ctx, r.db, scanSQLEvent, tableSQL(userEventsQuery, r.table), userID, limit)
+}
+
+// GetTopUsers returns the n users with the most events in [start, end].
+func (r *DuckDBRepo) GetTopUsers(ctx context.Context, start, end time.Time, n int) ([]UserCount, error) {
+	return querySQLTopUsers(ctx, r.db, tableSQL(topUsersQuery, r.table), start, end, n)
+}
+
+// GetUserGroups groups the events in [start, end] by user_id.
+func (r *DuckDBRepo) GetUserGroups(ctx context.Context, start, end time.Time) (*UserGroupStats, error) {
+	return querySQLUserGroups(ctx, r.db, tableSQL(userGroupsQuery, r.table), start, end)
+}
+
+// SearchPayload counts the events in [start, end] whose payload contains
+// token, scanning the payload column.
+func (r *DuckDBRepo) SearchPayload(ctx context.Context, token string, start, end time.Time) (int64, error) {
+	return querySQLPayloadSearch(ctx, r.db, tableSQL(payloadSearchQuery, r.table), start, end, token)
+}
+
+// ScanPages pages through [start, end] by (created_at, event_id) keys.
+func (r *DuckDBRepo) ScanPages(ctx context.Context, start, end time.Time, pageSize int, page PageFunc) error {
+	return keysetPages(start, pageSize, page, func(after time.Time, afterID string) ([]generator.Event, error) {
+		return querySQLEvents(ctx, r.db, scanSQLEvent, tableSQL(eventsPageQuery, r.table), after, after, afterID, end, pageSize)
+	})
+}
+
+// ExportEvents streams every event in [start, end] from a columnar scan.
+func (r *DuckDBRepo) ExportEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return exportSQLEvents(ctx, r.db, scanSQLEvent, tableSQL(exportQuery, r.table), start, end)
+}
+
+// CountSessions counts the sessions in [start, end] with LAG.
+func (r *DuckDBRepo) CountSessions(ctx context.Context, start, end time.Time, gap time.Duration) (int64, error) {
+	return querySQLCount(ctx, r.db, tableSQL(sessionsQueryDuckDB, r.table), start, end, int64(gap/time.Second))
+}
+
+// CountEvents counts the events in [start, end].
+func (r *DuckDBRepo) CountEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return querySQLCount(ctx, r.db, tableSQL(countQuery, r.table), start, end)
+}
+
+// RunStatement runs a custom query scenario statement with the window bound
+// to its two parameters.
+func (r *DuckDBRepo) RunStatement(ctx context.Context, statement string, start, end time.Time) (int64, error) {
+	return runSQLStatement(ctx, r.db, tableSQL(statement, r.table), start, end)
+}
+
+// EventExists scans for the event ID until the first match.
+func (r *DuckDBRepo) EventExists(ctx context.Context, eventID string) (bool, error) {
+	n, err := querySQLCount(ctx, r.db, tableSQL(existsQuery, r.table), eventID)
+	return n > 0, err
+}
+
+// InitUsersSchema recreates the users dimension table.
+func (r *DuckDBRepo) InitUsersSchema(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		CREATE OR REPLACE TABLE users (
+			user_id BIGINT PRIMARY KEY,
+			country VARCHAR NOT NULL,
+			plan VARCHAR NOT NULL
+		)
+	`)
+
+	return err
+}
+
+func (r *DuckDBRepo) InsertUsers(ctx context.Context, users []generator.User) error {
+	return insertSQLUsers(ctx, r.db, users, false)
+}
+
+// GetCountryBreakdown joins the events in [start, end] to users with a hash
+// join and counts them per country.
+func (r *DuckDBRepo) GetCountryBreakdown(ctx context.Context, start, end time.Time) ([]CountryCount, error) {
+	return querySQLCountryBreakdown(ctx, r.db, tableSQL(countryBreakdownQuery, r.table), start, end)
+}
+
+// GetStorageStats reports the size of the database file and its WAL, or the
+// allocated block size for an in-memory database.
+func (r *DuckDBRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	var stats StorageStats
+
+	if r.cfg.InMemory() {
+		_ = r.db.QueryRowContext(ctx,
+			`SELECT total_blocks * block_size FROM pragma_database_size()`,
+		).Scan(&stats.TotalSize)
+	} else {
+		stats.TotalSize = fileSize(r.cfg.Path) + fileSize(r.cfg.Path+".wal")
+	}
+
+	_ = r.db.QueryRowContext(ctx, tableSQL(`SELECT COUNT(*) FROM events`, r.table)).Scan(&stats.RowCount)
+
+	return &stats
+}
+
+// DeleteOlderThan deletes older rows in batches selected by rowid.
+func (r *DuckDBRepo) DeleteOlderThan(ctx context.Context, cutoff time.Time) (*DeleteStats, error) {
+	deleted, err := deleteInBatches(ctx, func(ctx context.Context) (int64, error) {
+		res, err := r.db.ExecContext(ctx, tableSQL(`
+			DELETE FROM events
+			WHERE rowid IN (SELECT rowid FROM events WHERE created_at < ? LIMIT ?)
+		`, r.table), cutoff, retentionBatchSize)
+		if err != nil {
+			return 0, err
+		}
+
+		return res.RowsAffected()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete events: %w", err)
+	}
+
+	return &DeleteStats{Rows: deleted, Method: retentionDelete}, nil
+}
+
+func (r *DuckDBRepo) Cleanup(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, tableSQL("DELETE FROM events", r.table))
+	return err
+}
+
+func (r *DuckDBRepo) Close() error {
+	return r.db.Close()
+}
+
+// ServerVersion returns the version of the DuckDB library.
+func (r *DuckDBRepo) ServerVersion(ctx context.Context) (string, error) {
+	return querySQLVersion(ctx, r.db, "SELECT version()")
+}
+
+// CheckPermissions checks that the database file can be written to.
+func (r *DuckDBRepo) CheckPermissions(ctx context.Context) error {
+	return checkSQLPermissions(ctx, r.db, "CREATE TABLE "+checkTable+" (id BIGINT)")
+}
+
+// VerifySchema checks that the events table exists with the columns of an
+// event.
+func (r *DuckDBRepo) VerifySchema(ctx context.Context) error {
+	return verifySQLSchema(ctx, r.db, r.table, nil, `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ?
+	`, r.table)
+}
@@ -0,0 +1,96 @@
+//go:build !duckdb
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// errDuckDBDisabled is returned by builds without the duckdb tag, which keeps
+// the default binary free of cgo and the bundled DuckDB library.
+var errDuckDBDisabled = errors.New("duckdb support not compiled in; rebuild with -tags duckdb")
+
+type DuckDBRepo struct{}
+
+func NewDuckDBRepo(context.Context, *config.DuckDBConfig) (*DuckDBRepo, error) {
+	return nil, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) InitSchema(context.Context) error { return errDuckDBDisabled }
+
+func (r *DuckDBRepo) InsertBatch(context.Context, []generator.Event) error {
+	return errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) GetEventStats(context.Context, time.Time, time.Time) ([]EventStats, error) {
+	return nil, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) GetEventByID(context.Context, string) (*generator.Event, error) {
+	return nil, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) GetUserEvents(context.Context, int64, int) ([]generator.Event, error) {
+	return nil, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) GetTopUsers(context.Context, time.Time, time.Time, int) ([]UserCount, error) {
+	return nil, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) GetUserGroups(context.Context, time.Time, time.Time) (*UserGroupStats, error) {
+	return nil, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) SearchPayload(context.Context, string, time.Time, time.Time) (int64, error) {
+	return 0, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) ScanPages(context.Context, time.Time, time.Time, int, PageFunc) error {
+	return errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) ExportEvents(context.Context, time.Time, time.Time) (int64, error) {
+	return 0, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) CountSessions(context.Context, time.Time, time.Time, time.Duration) (int64, error) {
+	return 0, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) CountEvents(context.Context, time.Time, time.Time) (int64, error) {
+	return 0, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) RunStatement(context.Context, string, time.Time, time.Time) (int64, error) {
+	return 0, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) EventExists(context.Context, string) (bool, error) {
+	return false, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) InitUsersSchema(context.Context) error {
+	return errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) InsertUsers(context.Context, []generator.User) error {
+	return errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) GetCountryBreakdown(context.Context, time.Time, time.Time) ([]CountryCount, error) {
+	return nil, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) DeleteOlderThan(context.Context, time.Time) (*DeleteStats, error) {
+	return nil, errDuckDBDisabled
+}
+
+func (r *DuckDBRepo) GetStorageStats(context.Context) *StorageStats { return &StorageStats{} }
+func (r *DuckDBRepo) Cleanup(context.Context) error                 { return errDuckDBDisabled }
+func (r *DuckDBRepo) Close() error                                  { return nil }
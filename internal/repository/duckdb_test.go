@@ -0,0 +1,49 @@
+//go:build duckdb
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuckDBRepo_InsertAndStats(t *testing.T) {
+	ctx := context.Background()
+
+	repo, err := NewDuckDBRepo(ctx, &config.DuckDBConfig{Path: ":memory:"})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = repo.Close() })
+
+	require.NoError(t, repo.InitSchema(ctx))
+
+	now := time.Now().UTC().Truncate(time.Hour)
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", Payload: "{}", CreatedAt: now.Add(10 * time.Minute)},
+		{ID: "b", UserID: 2, EventType: "login", Payload: "{}", CreatedAt: now.Add(20 * time.Minute)},
+		{ID: "c", UserID: 1, EventType: "logout", Payload: "{}", CreatedAt: now.Add(-50 * time.Minute)},
+	}
+
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	stats, err := repo.GetEventStats(ctx, now.Add(-2*time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	assert.True(t, now.Equal(stats[0].Hour))
+	assert.Equal(t, "login", stats[0].EventType)
+	assert.Equal(t, int64(2), stats[0].Count)
+	assert.Equal(t, int64(2), stats[0].UniqueUsers)
+
+	storage := repo.GetStorageStats(ctx)
+	assert.Equal(t, int64(3), storage.RowCount)
+
+	require.NoError(t, repo.Cleanup(ctx))
+	assert.Equal(t, int64(0), repo.GetStorageStats(ctx).RowCount)
+}
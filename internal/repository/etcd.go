@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const etcdRangePage = 10000
+
+// EtcdRepo writes events with the shared key-value layout into etcd, one
+// transaction per chunk of MaxTxnOps puts. etcd is a consistent metadata store:
+// every write goes through Raft and is kept as an MVCC revision until
+// compaction, which is what this backend exists to measure.
+type EtcdRepo struct {
+	client    *clientv3.Client
+	maxTxnOps int
+}
+
+func NewEtcdRepo(ctx context.Context, cfg *config.EtcdConfig) (*EtcdRepo, error) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   cfg.Endpoints,
+		DialTimeout: 10 * time.Second,
+		Context:     ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to etcd: %w", err)
+	}
+
+	if _, err := client.Status(ctx, cfg.Endpoints[0]); err != nil {
+		_ = client.Close()
+
+		return nil, fmt.Errorf("failed to reach etcd: %w", err)
+	}
+
+	return &EtcdRepo{client: client, maxTxnOps: max(cfg.MaxTxnOps, 1)}, nil
+}
+
+// InitSchema deletes all event keys and compacts and defragments the backend,
+// so the storage stats start from an empty database.
+func (r *EtcdRepo) InitSchema(ctx context.Context) error {
+	return r.reset(ctx)
+}
+
+// InsertBatch commits the batch as a sequence of transactions, since etcd
+// rejects transactions with more than --max-txn-ops operations.
+func (r *EtcdRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	for chunk := range slices.Chunk(events, r.maxTxnOps) {
+		ops := make([]clientv3.Op, len(chunk))
+		for i := range chunk {
+			ops[i] = clientv3.OpPut(string(kvEventKey(&chunk[i])), string(encodeKVValue(&chunk[i])))
+		}
+
+		if _, err := r.client.Txn(ctx).Then(ops...).Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetEventStats range-reads [start, end] in pages and aggregates in Go.
+func (r *EtcdRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	agg := newStatsAggregator(start, end)
+	from := string(kvTimeKey(start))
+	to := string(kvTimeKey(end.Add(time.Nanosecond)))
+
+	for {
+		resp, err := r.client.Get(ctx, from, clientv3.WithRange(to), clientv3.WithLimit(etcdRangePage))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, kv := range resp.Kvs {
+			userID, eventType, err := decodeKVValue(kv.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			agg.add(kvKeyTime(kv.Key), userID, eventType)
+		}
+
+		if !resp.More || len(resp.Kvs) == 0 {
+			return agg.result(), nil
+		}
+
+		// Continue right after the last key returned.
+		from = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+}
+
+// CountEvents counts the keys of [start, end] with a count-only range read,
+// which returns no values.
+func (r *EtcdRepo) CountEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	resp, err := r.client.Get(ctx, string(kvTimeKey(start)),
+		clientv3.WithRange(string(kvTimeKey(end.Add(time.Nanosecond)))), clientv3.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.Count, nil
+}
+
+// GetStorageStats reports the backend database size from the status endpoint,
+// with the part in use and the current revision as details. The gap between
+// db size and in-use size is history and free pages that only compaction and
+// defragmentation reclaim.
+func (r *EtcdRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	stats := &StorageStats{Details: map[string]int64{}}
+
+	for _, endpoint := range r.client.Endpoints() {
+		status, err := r.client.Status(ctx, endpoint)
+		if err != nil {
+			continue
+		}
+
+		stats.TotalSize += status.DbSize
+		stats.Details["db_in_use_bytes"] += status.DbSizeInUse
+		stats.Details["revision"] = max(stats.Details["revision"], status.Header.Revision)
+	}
+
+	resp, err := r.client.Get(ctx, string(kvEventPrefix),
+		clientv3.WithRange(string(kvEventUpperBound)), clientv3.WithCountOnly())
+	if err == nil {
+		stats.RowCount = resp.Count
+	}
+
+	return stats
+}
+
+// reset deletes every event key and returns the freed pages to the filesystem.
+func (r *EtcdRepo) reset(ctx context.Context) error {
+	_, err := r.deleteRange(ctx, kvEventPrefix, kvEventUpperBound)
+	return err
+}
+
+// DeleteOlderThan deletes the expired keys with one range delete, then
+// compacts and defragments so the freed space shows in the storage stats.
+func (r *EtcdRepo) DeleteOlderThan(ctx context.Context, cutoff time.Time) (*DeleteStats, error) {
+	deleted, err := r.deleteRange(ctx, kvEventPrefix, kvTimeKey(cutoff))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeleteStats{Rows: deleted, Method: retentionDeleteRange}, nil
+}
+
+// deleteRange deletes the keys in [from, to), compacts away their history and
+// defragments every endpoint.
+func (r *EtcdRepo) deleteRange(ctx context.Context, from, to []byte) (int64, error) {
+	resp, err := r.client.Delete(ctx, string(from), clientv3.WithRange(string(to)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete events: %w", err)
+	}
+
+	// Compacting an already compacted revision fails; nothing is left to reclaim.
+	_, err = r.client.Compact(ctx, resp.Header.Revision, clientv3.WithCompactPhysical())
+	if err != nil && !errors.Is(err, rpctypes.ErrCompacted) {
+		return 0, fmt.Errorf("failed to compact etcd: %w", err)
+	}
+
+	for _, endpoint := range r.client.Endpoints() {
+		if _, err := r.client.Defragment(ctx, endpoint); err != nil {
+			return 0, fmt.Errorf("failed to defragment %s: %w", endpoint, err)
+		}
+	}
+
+	return resp.Deleted, nil
+}
+
+func (r *EtcdRepo) Cleanup(ctx context.Context) error {
+	return r.reset(ctx)
+}
+
+func (r *EtcdRepo) Close() error {
+	return r.client.Close()
+}
+
+// ServerVersion returns the version of the first endpoint.
+func (r *EtcdRepo) ServerVersion(ctx context.Context) (string, error) {
+	status, err := r.client.Status(ctx, r.client.Endpoints()[0])
+	if err != nil {
+		return "", err
+	}
+
+	return status.Version, nil
+}
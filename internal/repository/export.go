@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// Export queries shared by the SQL backends, in "?" and "$n" bind styles.
+// There is no ORDER BY: an export consumer takes the rows in whatever order
+// the database streams them.
+const (
+	exportQuery       = "SELECT " + sqlEventColumns + " FROM events WHERE created_at BETWEEN ? AND ?"
+	exportQueryDollar = "SELECT " + sqlEventColumns + " FROM events WHERE created_at BETWEEN $1 AND $2"
+)
+
+// exportPageSize is the rows fetched per round trip by the backends that
+// take a fetch size, the MongoDB batch size and the CQL page size.
+const exportPageSize = 5000
+
+// eventRows is a row cursor of database/sql or the ClickHouse driver.
+type eventRows interface {
+	rowScanner
+	Next() bool
+	Err() error
+}
+
+// drainEvents decodes every row of rows with scan without keeping the events
+// and returns the number of rows read.
+func drainEvents(rows eventRows, scan func(rowScanner) (*generator.Event, error)) (int64, error) {
+	var n int64
+
+	for rows.Next() {
+		if _, err := scan(rows); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, rows.Err()
+}
+
+// exportSQLEvents streams the rows of an export query through database/sql.
+func exportSQLEvents(
+	ctx context.Context, db *sql.DB, scan func(rowScanner) (*generator.Event, error), query string, args ...any,
+) (int64, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	return drainEvents(rows, scan)
+}
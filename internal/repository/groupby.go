@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Top users queries shared by the SQL backends, in "?" and "$n" bind styles.
+// Ties are broken by user_id so every database returns the same users.
+//
+// The user groups queries count events per user and only return a summary of
+// the groups, so the server materializes every group without shipping them.
+const (
+	topUsersQuery = `
+		SELECT user_id, COUNT(*) AS cnt
+		FROM events
+		WHERE created_at BETWEEN ? AND ?
+		GROUP BY user_id
+		ORDER BY cnt DESC, user_id
+		LIMIT ?
+	`
+	topUsersQueryDollar = `
+		SELECT user_id, COUNT(*) AS cnt
+		FROM events
+		WHERE created_at BETWEEN $1 AND $2
+		GROUP BY user_id
+		ORDER BY cnt DESC, user_id
+		LIMIT $3
+	`
+	userGroupsQuery = `
+		SELECT COUNT(*), COALESCE(MAX(cnt), 0)
+		FROM (
+			SELECT user_id, COUNT(*) AS cnt
+			FROM events
+			WHERE created_at BETWEEN ? AND ?
+			GROUP BY user_id
+		) AS per_user
+	`
+	userGroupsQueryDollar = `
+		SELECT COUNT(*), COALESCE(MAX(cnt), 0)
+		FROM (
+			SELECT user_id, COUNT(*) AS cnt
+			FROM events
+			WHERE created_at BETWEEN $1 AND $2
+			GROUP BY user_id
+		) AS per_user
+	`
+)
+
+// querySQLTopUsers runs a top users query and reads (user_id, count) rows.
+func querySQLTopUsers(ctx context.Context, db *sql.DB, query string, start, end any, n int) ([]UserCount, error) {
+	rows, err := db.QueryContext(ctx, query, start, end, n)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var top []UserCount
+
+	for rows.Next() {
+		var u UserCount
+		if err := rows.Scan(&u.UserID, &u.Count); err != nil {
+			return nil, err
+		}
+
+		top = append(top, u)
+	}
+
+	return top, rows.Err()
+}
+
+// querySQLUserGroups runs a user groups query.
+func querySQLUserGroups(ctx context.Context, db *sql.DB, query string, start, end any) (*UserGroupStats, error) {
+	var g UserGroupStats
+	if err := db.QueryRowContext(ctx, query, start, end).Scan(&g.Groups, &g.MaxCount); err != nil {
+		return nil, err
+	}
+
+	return &g, nil
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// indexSet validates a configured secondary index set; empty selects the
+// full set the schemas had before sets existed.
+func indexSet(set string) (string, error) {
+	if set == "" {
+		return config.IndexesFull, nil
+	}
+
+	if !config.ValidIndexSet(set) {
+		return "", fmt.Errorf("unsupported index set: %s", set)
+	}
+
+	return set, nil
+}
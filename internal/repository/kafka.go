@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const kafkaCreateRetries = 30
+
+// KafkaRepo produces events to a topic keyed by event ID, with created_at as
+// the record timestamp and the shared key-value encoding as the value. The
+// stats query is an end-to-end consume: a fresh consumer reads every partition
+// from its start offset to the end offset captured when the query begins and
+// aggregates client-side.
+type KafkaRepo struct {
+	client     *kgo.Client
+	admin      *kadm.Client
+	brokers    []string
+	topic      string
+	partitions int32
+}
+
+func NewKafkaRepo(ctx context.Context, cfg *config.KafkaConfig) (*KafkaRepo, error) {
+	acks, err := kafkaAcks(cfg.Acks)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []kgo.Opt{kgo.SeedBrokers(cfg.Brokers...), kgo.RequiredAcks(acks)}
+	if acks != kgo.AllISRAcks() {
+		// Idempotent writes require acks=all.
+		opts = append(opts, kgo.DisableIdempotentWrite())
+	}
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+
+	if err := client.Ping(ctx); err != nil {
+		client.Close()
+
+		return nil, fmt.Errorf("failed to ping kafka: %w", err)
+	}
+
+	return &KafkaRepo{
+		client:     client,
+		admin:      kadm.NewClient(client),
+		brokers:    cfg.Brokers,
+		topic:      cfg.Topic,
+		partitions: int32(max(cfg.Partitions, 1)),
+	}, nil
+}
+
+// kafkaAcks maps KAFKA_ACKS to the producer acknowledgement level.
+func kafkaAcks(acks string) (kgo.Acks, error) {
+	switch acks {
+	case "all", "-1":
+		return kgo.AllISRAcks(), nil
+	case "1":
+		return kgo.LeaderAck(), nil
+	case "0":
+		return kgo.NoAck(), nil
+	default:
+		return kgo.Acks{}, fmt.Errorf("unsupported kafka acks: %s", acks)
+	}
+}
+
+// InitSchema recreates the topic. Topic deletion completes asynchronously, so
+// creation is retried while the broker still reports the old topic.
+func (r *KafkaRepo) InitSchema(ctx context.Context) error {
+	if _, err := r.admin.DeleteTopic(ctx, r.topic); err != nil && !errors.Is(err, kerr.UnknownTopicOrPartition) {
+		return fmt.Errorf("failed to delete topic: %w", err)
+	}
+
+	for range kafkaCreateRetries {
+		_, err := r.admin.CreateTopic(ctx, r.partitions, 1, nil, r.topic)
+		if !errors.Is(err, kerr.TopicAlreadyExists) {
+			if err != nil {
+				return fmt.Errorf("failed to create topic: %w", err)
+			}
+
+			return nil
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	return fmt.Errorf("topic %s still exists after deletion", r.topic)
+}
+
+func (r *KafkaRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	records := make([]*kgo.Record, len(events))
+	for i := range events {
+		records[i] = &kgo.Record{
+			Topic:     r.topic,
+			Key:       []byte(events[i].ID),
+			Value:     encodeKVValue(&events[i]),
+			Timestamp: events[i].CreatedAt,
+		}
+	}
+
+	return r.client.ProduceSync(ctx, records...).FirstErr()
+}
+
+// GetEventStats consumes the whole topic with a new client and aggregates by
+// record timestamp.
+func (r *KafkaRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	agg := newStatsAggregator(start, end)
+
+	remaining, from, err := r.partitionRanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(remaining) == 0 {
+		return agg.result(), nil
+	}
+
+	consumer, err := kgo.NewClient(
+		kgo.SeedBrokers(r.brokers...),
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{r.topic: from}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer consumer.Close()
+
+	if err := consumeKafka(ctx, consumer, agg, remaining); err != nil {
+		return nil, err
+	}
+
+	return agg.result(), nil
+}
+
+// partitionRanges returns the end offset and the consume start offset of
+// every partition of the topic that holds records.
+func (r *KafkaRepo) partitionRanges(ctx context.Context) (map[int32]int64, map[int32]kgo.Offset, error) {
+	starts, ends, err := r.offsets(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	remaining := make(map[int32]int64)
+	from := make(map[int32]kgo.Offset)
+
+	ends.Each(func(o kadm.ListedOffset) {
+		if first, ok := starts.Lookup(o.Topic, o.Partition); ok && o.Offset > first.Offset {
+			remaining[o.Partition] = o.Offset
+			from[o.Partition] = kgo.NewOffset().At(first.Offset)
+		}
+	})
+
+	return remaining, from, nil
+}
+
+// consumeKafka polls until every partition in remaining has been read up to
+// its end offset.
+func consumeKafka(ctx context.Context, consumer *kgo.Client, agg *statsAggregator, remaining map[int32]int64) error {
+	for len(remaining) > 0 {
+		fetches := consumer.PollFetches(ctx)
+		if err := fetches.Err0(); err != nil {
+			return err
+		}
+
+		var decodeErr error
+
+		fetches.EachRecord(func(rec *kgo.Record) {
+			userID, eventType, err := decodeKVValue(rec.Value)
+			if err != nil {
+				decodeErr = err
+				return
+			}
+
+			agg.add(rec.Timestamp.UnixNano(), userID, eventType)
+
+			if end, ok := remaining[rec.Partition]; ok && rec.Offset+1 >= end {
+				delete(remaining, rec.Partition)
+			}
+		})
+
+		if decodeErr != nil {
+			return decodeErr
+		}
+	}
+
+	return nil
+}
+
+func (r *KafkaRepo) offsets(ctx context.Context) (starts, ends kadm.ListedOffsets, err error) {
+	if starts, err = r.admin.ListStartOffsets(ctx, r.topic); err != nil {
+		return nil, nil, err
+	}
+
+	if ends, err = r.admin.ListEndOffsets(ctx, r.topic); err != nil {
+		return nil, nil, err
+	}
+
+	return starts, ends, errors.Join(starts.Error(), ends.Error())
+}
+
+// GetStorageStats sums the log segment sizes of the topic's partitions across
+// all brokers and log directories; the record count is the sum of offset
+// ranges.
+func (r *KafkaRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	stats := &StorageStats{Details: map[string]int64{"partitions": int64(r.partitions)}}
+
+	if dirs, err := r.admin.DescribeAllLogDirs(ctx, nil); err == nil {
+		dirs.Each(func(dir kadm.DescribedLogDir) {
+			for _, p := range dir.Topics[r.topic] {
+				stats.TotalSize += p.Size
+			}
+		})
+	}
+
+	starts, ends, err := r.offsets(ctx)
+	if err != nil {
+		return stats
+	}
+
+	ends.Each(func(o kadm.ListedOffset) {
+		if first, ok := starts.Lookup(o.Topic, o.Partition); ok {
+			stats.RowCount += o.Offset - first.Offset
+		}
+	})
+
+	return stats
+}
+
+// Cleanup deletes every record up to the current end offsets, keeping the topic.
+func (r *KafkaRepo) Cleanup(ctx context.Context) error {
+	ends, err := r.admin.ListEndOffsets(ctx, r.topic)
+	if err != nil {
+		return err
+	}
+
+	resps, err := r.admin.DeleteRecords(ctx, ends.Offsets())
+	if err != nil {
+		return err
+	}
+
+	return resps.Error()
+}
+
+func (r *KafkaRepo) Close() error {
+	r.client.Close()
+	return nil
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestKafkaAcks(t *testing.T) {
+	for in, want := range map[string]kgo.Acks{
+		"all": kgo.AllISRAcks(),
+		"-1":  kgo.AllISRAcks(),
+		"1":   kgo.LeaderAck(),
+		"0":   kgo.NoAck(),
+	} {
+		acks, err := kafkaAcks(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, acks, in)
+	}
+
+	_, err := kafkaAcks("quorum")
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// The embedded key-value backends share one layout. Keys are
+// kvEventPrefix + big-endian created_at (unix nanoseconds) + event ID, so a
+// time range is a contiguous key range and scans come back in time order.
+// Values hold the remaining fields in a compact binary form.
+var kvEventPrefix = []byte("e:")
+
+// kvEventUpperBound is the first key after every event key.
+var kvEventUpperBound = []byte("e;")
+
+const kvTimeKeyLen = 2 + 8
+
+var errKVValueTruncated = errors.New("truncated event value")
+
+// kvTimeKey returns the smallest key for events created at t.
+func kvTimeKey(t time.Time) []byte {
+	key := make([]byte, kvTimeKeyLen, kvTimeKeyLen+32)
+	copy(key, kvEventPrefix)
+	binary.BigEndian.PutUint64(key[len(kvEventPrefix):], uint64(t.UnixNano()))
+
+	return key
+}
+
+func kvEventKey(event *generator.Event) []byte {
+	return append(kvTimeKey(event.CreatedAt), event.ID...)
+}
+
+// kvKeyTime extracts created_at in unix nanoseconds from an event key.
+func kvKeyTime(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key[len(kvEventPrefix):kvTimeKeyLen]))
+}
+
+// encodeKVValue writes user_id, event_type and payload; the event ID and
+// creation time live in the key.
+func encodeKVValue(event *generator.Event) []byte {
+	buf := make([]byte, 0, binary.MaxVarintLen64*2+len(event.EventType)+len(event.Payload))
+	buf = binary.AppendVarint(buf, event.UserID)
+	buf = binary.AppendUvarint(buf, uint64(len(event.EventType)))
+	buf = append(buf, event.EventType...)
+
+	return append(buf, event.Payload...)
+}
+
+// decodeKVValue reads the fields needed by the stats query, skipping the payload.
+func decodeKVValue(value []byte) (userID int64, eventType string, err error) {
+	userID, n := binary.Varint(value)
+	if n <= 0 {
+		return 0, "", errKVValueTruncated
+	}
+
+	value = value[n:]
+
+	typeLen, n := binary.Uvarint(value)
+	if n <= 0 || uint64(len(value)-n) < typeLen {
+		return 0, "", errKVValueTruncated
+	}
+
+	return userID, string(value[n : n+int(typeLen)]), nil
+}
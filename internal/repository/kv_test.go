@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVEventKeyOrdering(t *testing.T) {
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	early := generator.Event{ID: "evt_b", CreatedAt: at}
+	late := generator.Event{ID: "evt_a", CreatedAt: at.Add(time.Nanosecond)}
+
+	assert.Negative(t, bytes.Compare(kvEventKey(&early), kvEventKey(&late)))
+	assert.Negative(t, bytes.Compare(kvTimeKey(at), kvEventKey(&early)))
+	assert.Equal(t, at.UnixNano(), kvKeyTime(kvEventKey(&early)))
+}
+
+func TestKVValueRoundTrip(t *testing.T) {
+	event := generator.Event{UserID: -42, EventType: "purchase", Payload: `{"amount": 10}`}
+
+	userID, eventType, err := decodeKVValue(encodeKVValue(&event))
+	require.NoError(t, err)
+	assert.Equal(t, int64(-42), userID)
+	assert.Equal(t, "purchase", eventType)
+
+	_, _, err = decodeKVValue([]byte{0x02, 0x10, 'x'})
+	require.ErrorIs(t, err, errKVValueTruncated)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// ErrEventNotFound is returned by point lookups for an unknown event ID.
+var ErrEventNotFound = errors.New("event not found")
+
+// sqlEventColumns is the column list read by scanSQLEvent.
+const sqlEventColumns = "event_id, user_id, event_type, payload, created_at"
+
+// Lookup queries shared by the SQL backends, in "?" and "$n" bind styles.
+const (
+	eventByIDQuery        = "SELECT " + sqlEventColumns + " FROM events WHERE event_id = ? LIMIT 1"
+	eventByIDQueryDollar  = "SELECT " + sqlEventColumns + " FROM events WHERE event_id = $1 LIMIT 1"
+	userEventsQuery       = "SELECT " + sqlEventColumns + " FROM events WHERE user_id = ? ORDER BY created_at DESC LIMIT ?"
+	userEventsQueryDollar = "SELECT " + sqlEventColumns + " FROM events WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2"
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanSQLEvent reads one row of sqlEventColumns, mapping sql.ErrNoRows to
+// ErrEventNotFound.
+func scanSQLEvent(row rowScanner) (*generator.Event, error) {
+	var e generator.Event
+	if err := row.Scan(&e.ID, &e.UserID, &e.EventType, &e.Payload, &e.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEventNotFound
+		}
+
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// querySQLEvents runs query and reads every row with scan.
+func querySQLEvents(
+	ctx context.Context, db *sql.DB, scan func(rowScanner) (*generator.Event, error), query string, args ...any,
+) ([]generator.Event, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var events []generator.Event
+
+	for rows.Next() {
+		e, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, *e)
+	}
+
+	return events, rows.Err()
+}
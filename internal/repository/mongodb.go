@@ -2,7 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/skoredin/db-benchmark-suite/internal/config"
@@ -10,15 +17,104 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
 )
 
 type MongoDBRepo struct {
-	client     *mongo.Client
-	collection *mongo.Collection
+	client       *mongo.Client
+	collection   *mongo.Collection
+	ttl          time.Duration // expireAfterSeconds of the created_at index, 0 when off
+	indexes      string        // secondary index set
+	deferred     bool          // InitSchema leaves the secondary indexes to CreateIndexes
+	insertMode   string        // insert_many or bulk_write
+	writeConcern *writeconcern.WriteConcern
+	compressors  []string
 }
 
+// Insert modes selectable with MONGODB_INSERT_MODE.
+const (
+	mongoInsertMany = "insert_many"
+	mongoBulkWrite  = "bulk_write"
+)
+
+// The TTL monitor wakes up every 60 seconds, so expiry is polled for a few
+// of its passes before giving up.
+const (
+	mongoTTLPollInterval = 5 * time.Second
+	mongoTTLTimeout      = 5 * time.Minute
+)
+
 func NewMongoDBRepo(ctx context.Context, cfg config.MongoDBConfig) (*MongoDBRepo, error) {
-	client, err := mongo.Connect(options.Client().ApplyURI(cfg.URI))
+	repo, name, err := newMongoDBRepo(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOpts, err := mongoClientOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := connectMongo(ctx, clientOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	repo.client = client
+	repo.collection = client.Database(cfg.Database).Collection(name)
+	repo.writeConcern = clientOpts.WriteConcern
+	repo.compressors = clientOpts.Compressors
+
+	return repo, nil
+}
+
+// newMongoDBRepo returns a repository with the validated settings of cfg,
+// before it connects, and the name of its collection.
+func newMongoDBRepo(cfg config.MongoDBConfig) (*MongoDBRepo, string, error) {
+	indexes, err := indexSet(cfg.Indexes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name, err := collectionName(cfg.Collection)
+	if err != nil {
+		return nil, "", err
+	}
+
+	insertMode, err := mongoInsertMode(cfg.InsertMode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &MongoDBRepo{indexes: indexes, insertMode: insertMode}, name, nil
+}
+
+// mongoClientOptions returns the client options of cfg: its URI, write
+// concern and compressors.
+func mongoClientOptions(cfg config.MongoDBConfig) (*options.ClientOptions, error) {
+	if err := checkMongoCompressors(cfg.Compressors); err != nil {
+		return nil, err
+	}
+
+	clientOpts := options.Client().ApplyURI(cfg.URI)
+	if wc := mongoWriteConcern(cfg); wc != nil {
+		if !wc.IsValid() {
+			return nil, errors.New("mongodb write concern w:0 cannot be journaled")
+		}
+
+		clientOpts.SetWriteConcern(wc)
+	}
+
+	if len(cfg.Compressors) > 0 {
+		clientOpts.SetCompressors(cfg.Compressors)
+	}
+
+	return clientOpts, nil
+}
+
+// connectMongo connects a client with opts and checks the server answers.
+func connectMongo(ctx context.Context, opts *options.ClientOptions) (*mongo.Client, error) {
+	client, err := mongo.Connect(opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to mongodb: %w", err)
 	}
@@ -29,56 +125,163 @@ func NewMongoDBRepo(ctx context.Context, cfg config.MongoDBConfig) (*MongoDBRepo
 		return nil, fmt.Errorf("failed to ping mongodb: %w", err)
 	}
 
-	collection := client.Database(cfg.Database).Collection("events")
+	return client, nil
+}
+
+// mongoInsertMode checks the insert mode, defaulting it to insert_many.
+func mongoInsertMode(mode string) (string, error) {
+	switch mode {
+	case "", mongoInsertMany:
+		return mongoInsertMany, nil
+	case mongoBulkWrite:
+		return mongoBulkWrite, nil
+	default:
+		return "", fmt.Errorf("unsupported mongodb insert mode: %s (must be %s or %s)", mode, mongoInsertMany, mongoBulkWrite)
+	}
+}
+
+// InsertModes returns the insert modes SetInsertMode takes.
+func (r *MongoDBRepo) InsertModes() []string {
+	return []string{mongoInsertMany, mongoBulkWrite}
+}
+
+// InsertMode returns the insert mode in use.
+func (r *MongoDBRepo) InsertMode() string {
+	return r.insertMode
+}
+
+// SetInsertMode switches the inserts to InsertMany or BulkWrite.
+func (r *MongoDBRepo) SetInsertMode(mode string) error {
+	mode, err := mongoInsertMode(mode)
+	if err != nil {
+		return err
+	}
+
+	r.insertMode = mode
+
+	return nil
+}
+
+// checkMongoCompressors checks that the driver has each wire compressor.
+func checkMongoCompressors(compressors []string) error {
+	for _, c := range compressors {
+		if !slices.Contains([]string{"snappy", "zstd", "zlib"}, c) {
+			return fmt.Errorf("unsupported mongodb compressor: %s (must be snappy, zstd or zlib)", c)
+		}
+	}
 
-	return &MongoDBRepo{
-		client:     client,
-		collection: collection,
-	}, nil
+	return nil
+}
+
+// mongoWriteConcern builds the client write concern from config.
+// Returns nil when neither w nor j is set, leaving the URI/driver default.
+func mongoWriteConcern(cfg config.MongoDBConfig) *writeconcern.WriteConcern {
+	if cfg.WriteConcern == "" && !cfg.Journal {
+		return nil
+	}
+
+	wc := &writeconcern.WriteConcern{}
+
+	if cfg.WriteConcern != "" {
+		if n, err := strconv.Atoi(cfg.WriteConcern); err == nil {
+			wc.W = n
+		} else {
+			wc.W = cfg.WriteConcern
+		}
+	}
+
+	if cfg.Journal {
+		journal := true
+		wc.Journal = &journal
+	}
+
+	return wc
 }
 
 func (r *MongoDBRepo) InitSchema(ctx context.Context) error {
 	_ = r.collection.Drop(ctx)
 
+	indexes := mongoIndexes(r.indexes)
+	if r.deferred {
+		indexes = indexes[:1]
+	}
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
+
+	return err
+}
+
+// DeferIndexes makes InitSchema create the collection with only its unique
+// event_id index, leaving the secondary ones to CreateIndexes.
+func (r *MongoDBRepo) DeferIndexes(deferred bool) {
+	r.deferred = deferred
+}
+
+// CreateIndexes builds the secondary indexes InitSchema deferred.
+func (r *MongoDBRepo) CreateIndexes(ctx context.Context) error {
+	indexes := mongoIndexes(r.indexes)[1:]
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
+
+	return err
+}
+
+// mongoIndexes returns the indexes of an index set. The unique event_id index
+// turns duplicate inserts into skipped writes, so every set has it; the TTL
+// benchmark converts the created_at index and fails without it.
+func mongoIndexes(set string) []mongo.IndexModel {
 	indexes := []mongo.IndexModel{
 		{
 			Keys:    bson.D{{Key: "event_id", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
-		{
+	}
+
+	if set != config.IndexesNone {
+		indexes = append(indexes, mongo.IndexModel{
 			Keys: bson.D{{Key: "created_at", Value: 1}},
-		},
-		{
-			Keys: bson.D{
-				{Key: "event_type", Value: 1},
-				{Key: "created_at", Value: 1},
-			},
-		},
-		{
-			Keys: bson.D{{Key: "user_id", Value: 1}},
-		},
+		})
 	}
 
-	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
+	if set == config.IndexesFull {
+		indexes = append(indexes,
+			mongo.IndexModel{
+				Keys: bson.D{
+					{Key: "event_type", Value: 1},
+					{Key: "created_at", Value: 1},
+				},
+			},
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "user_id", Value: 1}},
+			},
+		)
+	}
 
-	return err
+	return indexes
 }
 
 func (r *MongoDBRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
-	docs := make([]bson.M, len(events))
-	for i, event := range events {
-		docs[i] = bson.M{
-			"event_id":   event.ID,
-			"user_id":    event.UserID,
-			"event_type": event.EventType,
-			"payload":    event.Payload,
-			"created_at": event.CreatedAt,
+	var err error
+
+	if r.insertMode == mongoBulkWrite {
+		models := make([]mongo.WriteModel, len(events))
+		for i := range events {
+			models[i] = mongo.NewInsertOneModel().SetDocument(eventDocument(&events[i]))
+		}
+
+		_, err = r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	} else {
+		docs := make([]bson.M, len(events))
+		for i := range events {
+			docs[i] = eventDocument(&events[i])
 		}
-	}
 
-	opts := options.InsertMany().SetOrdered(false)
+		_, err = r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	}
 
-	_, err := r.collection.InsertMany(ctx, docs, opts)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			return nil
@@ -90,6 +293,16 @@ func (r *MongoDBRepo) InsertBatch(ctx context.Context, events []generator.Event)
 	return nil
 }
 
+func eventDocument(event *generator.Event) bson.M {
+	return bson.M{
+		"event_id":   event.ID,
+		"user_id":    event.UserID,
+		"event_type": event.EventType,
+		"payload":    event.Payload,
+		"created_at": event.CreatedAt,
+	}
+}
+
 func (r *MongoDBRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
 	pipeline := eventStatsPipeline(start, end)
 
@@ -160,11 +373,301 @@ func decodeEventStats(ctx context.Context, cursor *mongo.Cursor) ([]EventStats,
 	return stats, cursor.Err()
 }
 
+// mongoEvent is the stored document shape of an event.
+type mongoEvent struct {
+	EventID   string    `bson:"event_id"`
+	UserID    int64     `bson:"user_id"`
+	EventType string    `bson:"event_type"`
+	Payload   string    `bson:"payload"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func (d *mongoEvent) event() *generator.Event {
+	return &generator.Event{
+		ID:        d.EventID,
+		UserID:    d.UserID,
+		EventType: d.EventType,
+		Payload:   d.Payload,
+		CreatedAt: d.CreatedAt,
+	}
+}
+
+// GetEventByID looks an event up through the event_id unique index.
+func (r *MongoDBRepo) GetEventByID(ctx context.Context, eventID string) (*generator.Event, error) {
+	var doc mongoEvent
+
+	err := r.collection.FindOne(ctx, bson.D{{Key: "event_id", Value: eventID}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrEventNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.event(), nil
+}
+
+// EventExists counts the documents with the event ID, stopping at the first
+// one found in the unique event_id index.
+func (r *MongoDBRepo) EventExists(ctx context.Context, eventID string) (bool, error) {
+	n, err := r.collection.CountDocuments(ctx, bson.D{{Key: "event_id", Value: eventID}}, options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+// GetUserEvents reads the newest events of a user, filtering through the
+// user_id index and sorting the matches in memory.
+func (r *MongoDBRepo) GetUserEvents(ctx context.Context, userID int64, limit int) ([]generator.Event, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.D{{Key: "user_id", Value: userID}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var events []generator.Event
+
+	for cursor.Next(ctx) {
+		var doc mongoEvent
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		events = append(events, *doc.event())
+	}
+
+	return events, cursor.Err()
+}
+
+// ScanPages reads the events in [start, end] through one cursor in created_at
+// order with a batch size of pageSize; every getMore batch is one page.
+func (r *MongoDBRepo) ScanPages(ctx context.Context, start, end time.Time, pageSize int, page PageFunc) error {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetBatchSize(int32(min(pageSize, math.MaxInt32)))
+
+	cursor, err := r.collection.Find(ctx, bson.D{
+		{Key: "created_at", Value: bson.D{
+			{Key: "$gte", Value: start},
+			{Key: "$lte", Value: end},
+		}},
+	}, opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = cursor.Close(ctx) }()
+
+	rows := 0
+
+	for cursor.Next(ctx) {
+		var doc mongoEvent
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		if rows++; cursor.RemainingBatchLength() == 0 {
+			page(rows)
+			rows = 0
+		}
+	}
+
+	return cursor.Err()
+}
+
+// ExportEvents streams every event in [start, end] through one unsorted
+// cursor, fetching exportPageSize documents per getMore.
+func (r *MongoDBRepo) ExportEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	cursor, err := r.collection.Find(ctx, bson.D{
+		{Key: "created_at", Value: bson.D{
+			{Key: "$gte", Value: start},
+			{Key: "$lte", Value: end},
+		}},
+	}, options.Find().SetBatchSize(exportPageSize))
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var n int64
+
+	for cursor.Next(ctx) {
+		var doc mongoEvent
+		if err := cursor.Decode(&doc); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, cursor.Err()
+}
+
+// CountSessions counts the sessions in [start, end] with $setWindowFields,
+// shifting each user's previous created_at onto every document.
+func (r *MongoDBRepo) CountSessions(ctx context.Context, start, end time.Time, gap time.Duration) (int64, error) {
+	cursor, err := r.collection.Aggregate(ctx, sessionsPipeline(start, end, gap), options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var res struct {
+		Sessions int64 `bson:"sessions"`
+	}
+
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&res); err != nil {
+			return 0, err
+		}
+	}
+
+	return res.Sessions, cursor.Err()
+}
+
+// sessionsPipeline matches the range, attaches the previous created_at of the
+// user with $shift, and counts the documents that start a session.
+func sessionsPipeline(start, end time.Time, gap time.Duration) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "created_at", Value: bson.D{
+				{Key: "$gte", Value: start},
+				{Key: "$lte", Value: end},
+			}},
+		}}},
+		{{Key: "$setWindowFields", Value: bson.D{
+			{Key: "partitionBy", Value: "$user_id"},
+			{Key: "sortBy", Value: bson.D{{Key: "created_at", Value: 1}}},
+			{Key: "output", Value: bson.D{
+				{Key: "prev", Value: bson.D{{Key: "$shift", Value: bson.D{
+					{Key: "output", Value: "$created_at"},
+					{Key: "by", Value: -1},
+				}}}},
+			}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "$expr", Value: bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "$eq", Value: bson.A{"$prev", nil}}},
+			bson.D{{Key: "$gt", Value: bson.A{bson.D{{Key: "$subtract", Value: bson.A{"$created_at", "$prev"}}}, gap.Milliseconds()}}},
+		}}}}}}},
+		{{Key: "$count", Value: "sessions"}},
+	}
+}
+
+// GetTopUsers returns the n users with the most events in [start, end].
+func (r *MongoDBRepo) GetTopUsers(ctx context.Context, start, end time.Time, n int) ([]UserCount, error) {
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "created_at", Value: bson.D{
+				{Key: "$gte", Value: start},
+				{Key: "$lte", Value: end},
+			}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$user_id"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: n}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var top []UserCount
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			UserID int64 `bson:"_id"`
+			Count  int64 `bson:"count"`
+		}
+
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		top = append(top, UserCount{UserID: doc.UserID, Count: doc.Count})
+	}
+
+	return top, cursor.Err()
+}
+
+// GetUserGroups groups the events in [start, end] by user_id. The $group
+// stage may exceed the 100 MB stage memory limit, so it may spill to disk.
+func (r *MongoDBRepo) GetUserGroups(ctx context.Context, start, end time.Time) (*UserGroupStats, error) {
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "created_at", Value: bson.D{
+				{Key: "$gte", Value: start},
+				{Key: "$lte", Value: end},
+			}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$user_id"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "groups", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "max_count", Value: bson.D{{Key: "$max", Value: "$count"}}},
+		}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var g UserGroupStats
+
+	if cursor.Next(ctx) {
+		var doc struct {
+			Groups   int64 `bson:"groups"`
+			MaxCount int64 `bson:"max_count"`
+		}
+
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		g = UserGroupStats{Groups: doc.Groups, MaxCount: doc.MaxCount}
+	}
+
+	return &g, cursor.Err()
+}
+
+// SearchPayload counts the events in [start, end] whose payload contains
+// token. An unanchored $regex cannot use an index, so every payload in the
+// range is matched.
+func (r *MongoDBRepo) SearchPayload(ctx context.Context, token string, start, end time.Time) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.D{
+		{Key: "created_at", Value: bson.D{
+			{Key: "$gte", Value: start},
+			{Key: "$lte", Value: end},
+		}},
+		{Key: "payload", Value: bson.Regex{Pattern: regexp.QuoteMeta(token)}},
+	})
+}
+
+// CountEvents counts the documents in [start, end] through the created_at
+// index.
+func (r *MongoDBRepo) CountEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.D{
+		{Key: "created_at", Value: bson.D{
+			{Key: "$gte", Value: start},
+			{Key: "$lte", Value: end},
+		}},
+	})
+}
+
 func (r *MongoDBRepo) GetStorageStats(ctx context.Context) *StorageStats {
 	var result bson.M
 
 	err := r.collection.Database().RunCommand(ctx, bson.D{
-		{Key: "collStats", Value: "events"},
+		{Key: "collStats", Value: r.collection.Name()},
 	}).Decode(&result)
 	if err != nil {
 		return &StorageStats{}
@@ -205,6 +708,181 @@ func bsonToInt64(m bson.M, key string) int64 {
 	}
 }
 
+// DeleteOlderThan removes older documents with a single deleteMany served by
+// the created_at index.
+func (r *MongoDBRepo) DeleteOlderThan(ctx context.Context, cutoff time.Time) (*DeleteStats, error) {
+	res, err := r.collection.DeleteMany(ctx, bson.M{"created_at": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete events: %w", err)
+	}
+
+	return &DeleteStats{Rows: res.DeletedCount, Method: retentionDelete}, nil
+}
+
+// EnableTTL turns the created_at index into a TTL index with collMod, so
+// the TTL monitor removes stored and future documents once they are older
+// than ttl.
+func (r *MongoDBRepo) EnableTTL(ctx context.Context, ttl time.Duration) (string, error) {
+	cmd := bson.D{
+		{Key: "collMod", Value: r.collection.Name()},
+		{Key: "index", Value: bson.D{
+			{Key: "keyPattern", Value: bson.D{{Key: "created_at", Value: 1}}},
+			{Key: "expireAfterSeconds", Value: ttlSeconds(ttl)},
+		}},
+	}
+
+	if err := r.collection.Database().RunCommand(ctx, cmd).Err(); err != nil {
+		return "", fmt.Errorf("failed to create ttl index: %w", err)
+	}
+
+	r.ttl = ttl
+
+	return ttlMethodIndex, nil
+}
+
+// ExpireTTL waits until the TTL monitor has removed every expired document.
+func (r *MongoDBRepo) ExpireTTL(ctx context.Context) error {
+	deadline := time.Now().Add(mongoTTLTimeout)
+
+	for {
+		filter := bson.M{"created_at": bson.M{"$lt": time.Now().Add(-r.ttl)}}
+
+		n, err := r.collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to count expired events: %w", err)
+		}
+
+		if n == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%d expired events left after %v", n, mongoTTLTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(mongoTTLPollInterval):
+		}
+	}
+}
+
+// users is the collection of the users dimension table, keyed by user_id as
+// _id.
+func (r *MongoDBRepo) users() *mongo.Collection {
+	return r.collection.Database().Collection("users")
+}
+
+func (r *MongoDBRepo) InitUsersSchema(ctx context.Context) error {
+	return r.users().Drop(ctx)
+}
+
+func (r *MongoDBRepo) InsertUsers(ctx context.Context, users []generator.User) error {
+	docs := make([]bson.D, len(users))
+	for i, u := range users {
+		docs[i] = bson.D{{Key: "_id", Value: u.ID}, {Key: "country", Value: u.Country}, {Key: "plan", Value: u.Plan}}
+	}
+
+	_, err := r.users().InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+
+	return err
+}
+
+// GetCountryBreakdown joins the events in [start, end] to users with a
+// $lookup on _id, one index probe per event, and counts them per country.
+func (r *MongoDBRepo) GetCountryBreakdown(ctx context.Context, start, end time.Time) ([]CountryCount, error) {
+	cursor, err := r.collection.Aggregate(ctx, countryBreakdownPipeline(start, end))
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		Country string `bson:"_id"`
+		Count   int64  `bson:"count"`
+	}
+
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	counts := make([]CountryCount, len(docs))
+	for i, d := range docs {
+		counts[i] = CountryCount{Country: d.Country, Count: d.Count}
+	}
+
+	return counts, nil
+}
+
+func countryBreakdownPipeline(start, end time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "created_at", Value: bson.D{
+				{Key: "$gte", Value: start},
+				{Key: "$lte", Value: end},
+			}},
+		}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "users"},
+			{Key: "localField", Value: "user_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "user"},
+		}}},
+		{{Key: "$unwind", Value: "$user"}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$user.country"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
+	}
+}
+
+// counters is the per-user counter collection of the transactional workload,
+// keyed by user_id as _id.
+func (r *MongoDBRepo) counters() *mongo.Collection {
+	return r.collection.Database().Collection("user_counters")
+}
+
+// InitTransactionSchema drops the counter collection. Multi-document
+// transactions need a replica set or a sharded cluster, so a standalone
+// server is rejected up front instead of failing every transaction.
+func (r *MongoDBRepo) InitTransactionSchema(ctx context.Context) error {
+	var hello bson.M
+	if err := r.client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return fmt.Errorf("failed to read mongodb topology: %w", err)
+	}
+
+	if _, ok := hello["setName"]; !ok && hello["msg"] != "isdbgrid" {
+		return errors.New("transactions require a replica set or sharded cluster")
+	}
+
+	return r.counters().Drop(ctx)
+}
+
+// InsertEventTx inserts the event and upserts its user's counter document in
+// one transaction, retried by the driver on transient errors.
+func (r *MongoDBRepo) InsertEventTx(ctx context.Context, event generator.Event) error {
+	sess, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+
+	defer sess.EndSession(ctx)
+
+	_, err = sess.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+		if _, err := r.collection.InsertOne(ctx, eventDocument(&event)); err != nil {
+			return nil, err
+		}
+
+		return r.counters().UpdateOne(ctx,
+			bson.D{{Key: "_id", Value: event.UserID}},
+			bson.D{{Key: "$inc", Value: bson.D{{Key: "events", Value: 1}}}},
+			options.UpdateOne().SetUpsert(true))
+	})
+
+	return err
+}
+
 func (r *MongoDBRepo) Cleanup(ctx context.Context) error {
 	return r.collection.Drop(ctx)
 }
@@ -215,3 +893,87 @@ func (r *MongoDBRepo) Close() error {
 
 	return r.client.Disconnect(ctx)
 }
+
+// ServerVersion returns the version buildInfo reports.
+func (r *MongoDBRepo) ServerVersion(ctx context.Context) (string, error) {
+	var info struct {
+		Version string `bson:"version"`
+	}
+
+	err := r.client.Database("admin").RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&info)
+
+	return info.Version, err
+}
+
+// Settings reports the write concern, wire compressors and insert mode of
+// the connection, those of the URI included, so runs are compared at the
+// same durability.
+func (r *MongoDBRepo) Settings(context.Context) (map[string]string, error) {
+	return mongoSettings(r.writeConcern, r.compressors, r.insertMode), nil
+}
+
+func mongoSettings(wc *writeconcern.WriteConcern, compressors []string, insertMode string) map[string]string {
+	settings := map[string]string{
+		"write_concern": "default",
+		"compressors":   "none",
+		"insert_mode":   insertMode,
+	}
+
+	if wc != nil && wc.W != nil {
+		settings["write_concern"] = fmt.Sprint(wc.W)
+	}
+
+	if wc != nil && wc.Journal != nil {
+		settings["journal"] = strconv.FormatBool(*wc.Journal)
+	}
+
+	if len(compressors) > 0 {
+		settings["compressors"] = strings.Join(compressors, ",")
+	}
+
+	return settings
+}
+
+// CheckPermissions checks that the user can write to and drop a collection
+// of the database.
+func (r *MongoDBRepo) CheckPermissions(ctx context.Context) error {
+	collection := r.collection.Database().Collection(checkTable)
+
+	_, insertErr := collection.InsertOne(ctx, bson.M{"_id": 1})
+
+	if err := collection.Drop(ctx); err != nil {
+		return fmt.Errorf("%w: %w", ErrDropDenied, err)
+	}
+
+	if insertErr != nil {
+		return fmt.Errorf("%w: %w", ErrWriteDenied, insertErr)
+	}
+
+	return nil
+}
+
+// VerifySchema checks that the events collection exists and, as documents
+// carry their own fields, that one of its documents has those of an event.
+func (r *MongoDBRepo) VerifySchema(ctx context.Context) error {
+	names, err := r.collection.Database().ListCollectionNames(ctx, bson.D{{Key: "name", Value: r.collection.Name()}})
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		return checkColumns(r.collection.Name(), nil)
+	}
+
+	var doc bson.M
+
+	err = r.collection.FindOne(ctx, bson.D{}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return checkColumns(r.collection.Name(), slices.Collect(maps.Keys(doc)))
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+)
+
+func TestMongoWriteConcern(t *testing.T) {
+	assert.Nil(t, mongoWriteConcern(config.MongoDBConfig{}), "the URI or driver default applies")
+
+	wc := mongoWriteConcern(config.MongoDBConfig{WriteConcern: "0"})
+	assert.Equal(t, 0, wc.W)
+	assert.False(t, wc.Acknowledged())
+
+	wc = mongoWriteConcern(config.MongoDBConfig{WriteConcern: "majority", Journal: true})
+	assert.Equal(t, "majority", wc.W)
+	require.NotNil(t, wc.Journal)
+	assert.True(t, *wc.Journal)
+
+	assert.False(t, mongoWriteConcern(config.MongoDBConfig{WriteConcern: "0", Journal: true}).IsValid())
+}
+
+func TestMongoInsertMode(t *testing.T) {
+	for mode, want := range map[string]string{"": "insert_many", "insert_many": "insert_many", "bulk_write": "bulk_write"} {
+		got, err := mongoInsertMode(mode)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, mode)
+	}
+
+	_, err := mongoInsertMode("insert_one")
+	assert.Error(t, err)
+
+	repo := &MongoDBRepo{insertMode: mongoInsertMany}
+	require.NoError(t, repo.SetInsertMode(mongoBulkWrite))
+	assert.Equal(t, mongoBulkWrite, repo.InsertMode())
+	assert.Error(t, repo.SetInsertMode("insert_one"))
+}
+
+func TestCheckMongoCompressors(t *testing.T) {
+	require.NoError(t, checkMongoCompressors(nil))
+	require.NoError(t, checkMongoCompressors([]string{"zstd", "snappy", "zlib"}))
+	assert.Error(t, checkMongoCompressors([]string{"zstd", "lz4"}))
+}
+
+func TestMongoSettings(t *testing.T) {
+	journal := true
+	assert.Equal(t, map[string]string{
+		"write_concern": "default",
+		"compressors":   "none",
+		"insert_mode":   "insert_many",
+	}, mongoSettings(nil, nil, mongoInsertMany))
+
+	assert.Equal(t, map[string]string{
+		"write_concern": "majority",
+		"journal":       "true",
+		"compressors":   "zstd,snappy",
+		"insert_mode":   "bulk_write",
+	}, mongoSettings(&writeconcern.WriteConcern{W: "majority", Journal: &journal}, []string{"zstd", "snappy"}, mongoBulkWrite))
+}
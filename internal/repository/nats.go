@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+const (
+	natsStream     = "EVENTS"
+	natsSubject    = "events."
+	natsFetchBatch = 1000
+	natsStallWait  = 30 * time.Second
+)
+
+// NATSRepo publishes events to a JetStream stream, one subject per event type,
+// with the event ID as the message ID so redelivered batches are deduplicated
+// within the stream's duplicate window. A stream has no query language, so the
+// stats query replays the stream through an ordered consumer and aggregates
+// client-side; its latency is the consumer-side aggregation latency.
+type NATSRepo struct {
+	conn *nats.Conn
+	js   jetstream.JetStream
+}
+
+func NewNATSRepo(_ context.Context, cfg *config.NATSConfig) (*NATSRepo, error) {
+	conn, err := nats.Connect(cfg.URL, nats.Name("db-benchmark-suite"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+
+	js, err := jetstream.New(conn, jetstream.WithPublishAsyncMaxPending(max(cfg.MaxPending, 1)))
+	if err != nil {
+		conn.Close()
+
+		return nil, fmt.Errorf("failed to create jetstream context: %w", err)
+	}
+
+	return &NATSRepo{conn: conn, js: js}, nil
+}
+
+// InitSchema recreates the stream with file storage.
+func (r *NATSRepo) InitSchema(ctx context.Context) error {
+	if err := r.js.DeleteStream(ctx, natsStream); err != nil && !errors.Is(err, jetstream.ErrStreamNotFound) {
+		return fmt.Errorf("failed to delete stream: %w", err)
+	}
+
+	_, err := r.js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:     natsStream,
+		Subjects: []string{natsSubject + ">"},
+		Storage:  jetstream.FileStorage,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create stream: %w", err)
+	}
+
+	return nil
+}
+
+// encodeNATSMessage prefixes the shared key-value encoding with created_at in
+// big-endian unix nanoseconds; JetStream timestamps are publish times.
+func encodeNATSMessage(event *generator.Event) []byte {
+	data := binary.BigEndian.AppendUint64(nil, uint64(event.CreatedAt.UnixNano()))
+	return append(data, encodeKVValue(event)...)
+}
+
+func decodeNATSMessage(data []byte) (createdAt, userID int64, eventType string, err error) {
+	if len(data) < 8 {
+		return 0, 0, "", errKVValueTruncated
+	}
+
+	userID, eventType, err = decodeKVValue(data[8:])
+
+	return int64(binary.BigEndian.Uint64(data)), userID, eventType, err
+}
+
+// InsertBatch publishes the batch asynchronously and waits for every ack.
+func (r *NATSRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	futures := make([]jetstream.PubAckFuture, 0, len(events))
+
+	for i := range events {
+		future, err := r.js.PublishMsgAsync(&nats.Msg{
+			Subject: natsSubject + events[i].EventType,
+			Data:    encodeNATSMessage(&events[i]),
+		}, jetstream.WithMsgID(events[i].ID), jetstream.WithStallWait(natsStallWait))
+		if err != nil {
+			return err
+		}
+
+		futures = append(futures, future)
+	}
+
+	for _, future := range futures {
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// GetEventStats replays the stream up to its current last sequence.
+func (r *NATSRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	agg := newStatsAggregator(start, end)
+
+	stream, err := r.js.Stream(ctx, natsStream)
+	if err != nil {
+		return nil, err
+	}
+
+	last := stream.CachedInfo().State.LastSeq
+	if stream.CachedInfo().State.Msgs == 0 {
+		return agg.result(), nil
+	}
+
+	consumer, err := stream.OrderedConsumer(ctx, jetstream.OrderedConsumerConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := replayNATSStream(consumer, agg, last); err != nil {
+		return nil, err
+	}
+
+	return agg.result(), nil
+}
+
+// replayNATSStream feeds the messages of the stream up to sequence last into
+// the aggregator.
+func replayNATSStream(consumer jetstream.Consumer, agg *statsAggregator, last uint64) error {
+	for seq := uint64(0); seq < last; {
+		batch, err := consumer.Fetch(natsFetchBatch, jetstream.FetchMaxWait(5*time.Second))
+		if err != nil {
+			return err
+		}
+
+		prev := seq
+		if seq, err = aggregateNATSBatch(agg, batch, seq); err != nil {
+			return err
+		}
+
+		if seq == prev {
+			return fmt.Errorf("stream replay stalled at sequence %d of %d", seq, last)
+		}
+	}
+
+	return nil
+}
+
+// aggregateNATSBatch feeds a fetched batch into the aggregator and returns the
+// highest stream sequence seen.
+func aggregateNATSBatch(agg *statsAggregator, batch jetstream.MessageBatch, seq uint64) (uint64, error) {
+	for msg := range batch.Messages() {
+		createdAt, userID, eventType, err := decodeNATSMessage(msg.Data())
+		if err != nil {
+			return seq, err
+		}
+
+		agg.add(createdAt, userID, eventType)
+
+		if meta, err := msg.Metadata(); err == nil {
+			seq = max(seq, meta.Sequence.Stream)
+		}
+	}
+
+	if err := batch.Error(); err != nil && !errors.Is(err, nats.ErrTimeout) {
+		return seq, err
+	}
+
+	return seq, nil
+}
+
+// GetStorageStats reports the stream's stored bytes and messages, with the
+// number of subjects and consumers as details.
+func (r *NATSRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	stream, err := r.js.Stream(ctx, natsStream)
+	if err != nil {
+		return &StorageStats{}
+	}
+
+	state := stream.CachedInfo().State
+
+	return &StorageStats{
+		TotalSize: int64(state.Bytes),
+		RowCount:  int64(state.Msgs),
+		Details: map[string]int64{
+			"subjects":  int64(state.NumSubjects),
+			"consumers": int64(state.Consumers),
+		},
+	}
+}
+
+func (r *NATSRepo) Cleanup(ctx context.Context) error {
+	stream, err := r.js.Stream(ctx, natsStream)
+	if err != nil {
+		return err
+	}
+
+	return stream.Purge(ctx)
+}
+
+func (r *NATSRepo) Close() error {
+	return r.conn.Drain()
+}
+
+// ServerVersion returns the version the server announced on connect.
+func (r *NATSRepo) ServerVersion(context.Context) (string, error) {
+	return r.conn.ConnectedServerVersion(), nil
+}
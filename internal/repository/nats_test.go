@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNATSMessageRoundTrip(t *testing.T) {
+	event := generator.Event{
+		ID:        "a",
+		UserID:    42,
+		EventType: "purchase",
+		Payload:   `{"amount":10}`,
+		CreatedAt: time.Date(2024, 1, 2, 15, 4, 5, 6, time.UTC),
+	}
+
+	createdAt, userID, eventType, err := decodeNATSMessage(encodeNATSMessage(&event))
+	require.NoError(t, err)
+
+	assert.Equal(t, event.CreatedAt.UnixNano(), createdAt)
+	assert.Equal(t, int64(42), userID)
+	assert.Equal(t, "purchase", eventType)
+
+	_, _, _, err = decodeNATSMessage([]byte{1, 2, 3})
+	assert.ErrorIs(t, err, errKVValueTruncated)
+}
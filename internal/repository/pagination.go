@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// PageFunc is called by a paginated scan after every page with the number of
+// events the page held.
+type PageFunc func(rows int)
+
+// Keyset pagination queries shared by the SQL backends, in "?" and "$n" bind
+// styles. A page starts after the (created_at, event_id) key of the last row
+// of the previous page; the expanded OR form is understood by every dialect.
+const (
+	eventsPageQuery = "SELECT " + sqlEventColumns + ` FROM events
+		WHERE (created_at > ? OR (created_at = ? AND event_id > ?)) AND created_at <= ?
+		ORDER BY created_at, event_id LIMIT ?`
+	eventsPageQueryDollar = "SELECT " + sqlEventColumns + ` FROM events
+		WHERE (created_at > $1 OR (created_at = $1 AND event_id > $2)) AND created_at <= $3
+		ORDER BY created_at, event_id LIMIT $4`
+)
+
+// keysetPages reads a range starting at start in pages of pageSize. fetch
+// runs one page query for the rows after the given key and the scan stops at
+// the first short page.
+func keysetPages(
+	start time.Time, pageSize int, page PageFunc, fetch func(after time.Time, afterID string) ([]generator.Event, error),
+) error {
+	after, afterID := start, ""
+
+	for {
+		events, err := fetch(after, afterID)
+		if err != nil {
+			return err
+		}
+
+		page(len(events))
+
+		if len(events) < pageSize {
+			return nil
+		}
+
+		last := events[len(events)-1]
+		after, afterID = last.CreatedAt, last.ID
+	}
+}
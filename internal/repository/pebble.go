@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// PebbleRepo stores events in an embedded Pebble (RocksDB-style LSM) database
+// using the shared time-ordered key layout.
+type PebbleRepo struct {
+	db    *pebble.DB
+	write *pebble.WriteOptions
+}
+
+func NewPebbleRepo(_ context.Context, cfg *config.PebbleConfig) (*PebbleRepo, error) {
+	opts := &pebble.Options{}
+	if cfg.InMemory() {
+		opts.FS = vfs.NewMem()
+	}
+
+	db, err := pebble.Open(cfg.Path, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pebble database: %w", err)
+	}
+
+	write := pebble.NoSync
+	if cfg.Sync {
+		write = pebble.Sync
+	}
+
+	return &PebbleRepo{db: db, write: write}, nil
+}
+
+// InitSchema deletes all event keys and compacts the range away so earlier
+// runs do not leave tombstones behind.
+func (r *PebbleRepo) InitSchema(_ context.Context) error {
+	if err := r.db.DeleteRange(kvEventPrefix, kvEventUpperBound, pebble.Sync); err != nil {
+		return err
+	}
+
+	return r.db.Compact(kvEventPrefix, kvEventUpperBound, true)
+}
+
+func (r *PebbleRepo) InsertBatch(_ context.Context, events []generator.Event) error {
+	batch := r.db.NewBatch()
+	defer func() { _ = batch.Close() }()
+
+	for i := range events {
+		if err := batch.Set(kvEventKey(&events[i]), encodeKVValue(&events[i]), nil); err != nil {
+			return err
+		}
+	}
+
+	return batch.Commit(r.write)
+}
+
+// GetEventStats range-scans [start, end] and aggregates in Go.
+func (r *PebbleRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	it, err := r.db.NewIterWithContext(ctx, &pebble.IterOptions{
+		LowerBound: kvTimeKey(start),
+		UpperBound: kvTimeKey(end.Add(time.Nanosecond)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = it.Close() }()
+
+	agg := newStatsAggregator(start, end)
+
+	for it.First(); it.Valid(); it.Next() {
+		userID, eventType, err := decodeKVValue(it.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		agg.add(kvKeyTime(it.Key()), userID, eventType)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return agg.result(), nil
+}
+
+// GetStorageStats reports the total SSTable size across levels, with the
+// SSTable count, WAL and memtable sizes as details.
+func (r *PebbleRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	m := r.db.Metrics()
+	total := m.Total()
+
+	stats := &StorageStats{
+		TotalSize: total.Size,
+		Details: map[string]int64{
+			"sstables":       total.NumFiles,
+			"wal_bytes":      int64(m.WAL.PhysicalSize),
+			"memtable_bytes": int64(m.MemTable.Size),
+		},
+	}
+
+	it, err := r.db.NewIterWithContext(ctx, &pebble.IterOptions{
+		LowerBound: kvEventPrefix,
+		UpperBound: kvEventUpperBound,
+	})
+	if err != nil {
+		return stats
+	}
+
+	for it.First(); it.Valid(); it.Next() {
+		stats.RowCount++
+	}
+
+	_ = it.Close()
+
+	return stats
+}
+
+// DeleteOlderThan counts the expired keys, writes a single range tombstone
+// over them and compacts the range so the storage stats show the reclaim.
+func (r *PebbleRepo) DeleteOlderThan(ctx context.Context, cutoff time.Time) (*DeleteStats, error) {
+	upper := kvTimeKey(cutoff)
+
+	it, err := r.db.NewIterWithContext(ctx, &pebble.IterOptions{LowerBound: kvEventPrefix, UpperBound: upper})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows int64
+	for it.First(); it.Valid(); it.Next() {
+		rows++
+	}
+
+	if err := it.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := r.db.DeleteRange(kvEventPrefix, upper, r.write); err != nil {
+		return nil, fmt.Errorf("failed to delete events: %w", err)
+	}
+
+	if err := r.db.Compact(kvEventPrefix, upper, true); err != nil {
+		return nil, fmt.Errorf("failed to compact deleted range: %w", err)
+	}
+
+	return &DeleteStats{Rows: rows, Method: retentionDeleteRange}, nil
+}
+
+func (r *PebbleRepo) Cleanup(_ context.Context) error {
+	return r.db.DeleteRange(kvEventPrefix, kvEventUpperBound, pebble.Sync)
+}
+
+func (r *PebbleRepo) Close() error {
+	return r.db.Close()
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPebbleRepo_InsertAndStats(t *testing.T) {
+	ctx := context.Background()
+
+	repo, err := NewPebbleRepo(ctx, &config.PebbleConfig{Path: ":memory:"})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = repo.Close() })
+
+	require.NoError(t, repo.InitSchema(ctx))
+
+	now := time.Now().UTC().Truncate(time.Hour)
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", Payload: "{}", CreatedAt: now.Add(10 * time.Minute)},
+		{ID: "b", UserID: 2, EventType: "login", Payload: "{}", CreatedAt: now.Add(20 * time.Minute)},
+		{ID: "c", UserID: 1, EventType: "logout", Payload: "{}", CreatedAt: now.Add(-50 * time.Minute)},
+		{ID: "d", UserID: 3, EventType: "login", Payload: "{}", CreatedAt: now.Add(-3 * time.Hour)},
+	}
+
+	require.NoError(t, repo.InsertBatch(ctx, events))
+	// Rewriting an event overwrites its key.
+	require.NoError(t, repo.InsertBatch(ctx, events[:1]))
+
+	stats, err := repo.GetEventStats(ctx, now.Add(-2*time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, now, stats[0].Hour)
+	assert.Equal(t, "login", stats[0].EventType)
+	assert.Equal(t, int64(2), stats[0].Count)
+	assert.Equal(t, int64(2), stats[0].UniqueUsers)
+	assert.Equal(t, now.Add(-time.Hour), stats[1].Hour)
+
+	assert.Equal(t, int64(4), repo.GetStorageStats(ctx).RowCount)
+
+	require.NoError(t, repo.Cleanup(ctx))
+	assert.Equal(t, int64(0), repo.GetStorageStats(ctx).RowCount)
+}
+
+func TestPebbleRepo_DeleteOlderThan(t *testing.T) {
+	ctx := context.Background()
+
+	repo, err := NewPebbleRepo(ctx, &config.PebbleConfig{Path: ":memory:"})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = repo.Close() })
+
+	now := time.Now().UTC()
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now.AddDate(0, 0, -10)},
+		{ID: "b", UserID: 2, EventType: "login", CreatedAt: now.AddDate(0, 0, -8)},
+		{ID: "c", UserID: 3, EventType: "login", CreatedAt: now.AddDate(0, 0, -1)},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	stats, err := repo.DeleteOlderThan(ctx, now.AddDate(0, 0, -7))
+	require.NoError(t, err)
+	assert.Equal(t, &DeleteStats{Rows: 2, Method: retentionDeleteRange}, stats)
+	assert.Equal(t, int64(1), repo.GetStorageStats(ctx).RowCount)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// checkTable is the scratch table the permission checks create and drop,
+// beside the events table, which they leave alone.
+const checkTable = "dbbench_check"
+
+// Errors of the permission checks, wrapping the error of the database.
+var (
+	ErrCreateDenied = errors.New("cannot create a table")
+	ErrWriteDenied  = errors.New("cannot write to a table")
+	ErrDropDenied   = errors.New("cannot drop a table")
+)
+
+// checkSQLPermissions creates the scratch table with the create statement,
+// writes a row to it and drops it, as the benchmark does with the events.
+func checkSQLPermissions(ctx context.Context, db *sql.DB, create string) error {
+	// A check interrupted earlier may have left the table behind.
+	_, _ = db.ExecContext(ctx, "DROP TABLE IF EXISTS "+checkTable)
+
+	if _, err := db.ExecContext(ctx, create); err != nil {
+		return fmt.Errorf("%w: %w", ErrCreateDenied, err)
+	}
+
+	_, insertErr := db.ExecContext(ctx, "INSERT INTO "+checkTable+" (id) VALUES (1)")
+
+	if _, err := db.ExecContext(ctx, "DROP TABLE "+checkTable); err != nil {
+		return fmt.Errorf("%w: %w", ErrDropDenied, err)
+	}
+
+	if insertErr != nil {
+		return fmt.Errorf("%w: %w", ErrWriteDenied, insertErr)
+	}
+
+	return nil
+}
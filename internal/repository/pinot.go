@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+const pinotSchema = `{
+	"schemaName": "events",
+	"dimensionFieldSpecs": [
+		{"name": "event_id", "dataType": "STRING"},
+		{"name": "user_id", "dataType": "LONG"},
+		{"name": "event_type", "dataType": "STRING"},
+		{"name": "payload", "dataType": "STRING", "maxLength": 65536}
+	],
+	"dateTimeFieldSpecs": [
+		{"name": "created_at", "dataType": "LONG", "format": "1:MILLISECONDS:EPOCH", "granularity": "1:MILLISECONDS"}
+	]
+}`
+
+const pinotTableConfig = `{
+	"tableName": "events",
+	"tableType": "OFFLINE",
+	"segmentsConfig": {"timeColumnName": "created_at", "replication": "1", "schemaName": "events"},
+	"tableIndexConfig": {"invertedIndexColumns": ["event_type"], "rangeIndexColumns": ["created_at"]},
+	"tenants": {},
+	"metadata": {}
+}`
+
+// PinotRepo pushes each batch to the controller as a JSON file that becomes
+// one offline segment, and queries the broker's SQL endpoint.
+type PinotRepo struct {
+	http          *http.Client
+	controllerURL string
+	brokerURL     string
+	runID         int64
+	segmentSeq    atomic.Int64
+}
+
+func NewPinotRepo(ctx context.Context, cfg *config.PinotConfig) (*PinotRepo, error) {
+	r := &PinotRepo{
+		http:          &http.Client{Timeout: 5 * time.Minute},
+		controllerURL: strings.TrimSuffix(cfg.ControllerURL, "/"),
+		brokerURL:     strings.TrimSuffix(cfg.BrokerURL, "/"),
+		runID:         time.Now().UnixNano(),
+	}
+
+	if _, err := r.do(ctx, http.MethodGet, r.controllerURL+"/health", nil, ""); err != nil {
+		return nil, fmt.Errorf("failed to reach pinot controller: %w", err)
+	}
+
+	return r, nil
+}
+
+// do sends a request and returns the response body, failing on non-2xx status.
+func (r *PinotRepo) do(ctx context.Context, method, target string, body io.Reader, contentType string) ([]byte, error) {
+	req, err := newPinotRequest(ctx, method, target, body, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("pinot %s %s failed: %s: %s", method, target, resp.Status, bytes.TrimSpace(raw))
+	}
+
+	return raw, nil
+}
+
+// newPinotRequest returns a request with body of contentType, if any.
+func newPinotRequest(ctx context.Context, method, target string, body io.Reader, contentType string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return req, nil
+}
+
+func (r *PinotRepo) InitSchema(ctx context.Context) error {
+	// Deleting a table or schema that does not exist returns 404; ignore it.
+	_, _ = r.do(ctx, http.MethodDelete, r.controllerURL+"/tables/events?type=offline", nil, "")
+	_, _ = r.do(ctx, http.MethodDelete, r.controllerURL+"/schemas/events", nil, "")
+
+	if _, err := r.do(ctx, http.MethodPost, r.controllerURL+"/schemas",
+		strings.NewReader(pinotSchema), "application/json"); err != nil {
+		return fmt.Errorf("failed to create pinot schema: %w", err)
+	}
+
+	if _, err := r.do(ctx, http.MethodPost, r.controllerURL+"/tables",
+		strings.NewReader(pinotTableConfig), "application/json"); err != nil {
+		return fmt.Errorf("failed to create pinot table: %w", err)
+	}
+
+	return nil
+}
+
+type pinotRow struct {
+	EventID   string `json:"event_id"`
+	UserID    int64  `json:"user_id"`
+	EventType string `json:"event_type"`
+	Payload   string `json:"payload"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// InsertBatch uploads the batch as newline-delimited JSON through
+// /ingestFromFile. Each batch gets a unique fixed segment name so batches
+// covering the same time range do not replace each other.
+func (r *PinotRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	part, err := mw.CreateFormFile("file", "events.json")
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(part)
+	for _, event := range events {
+		if err := enc.Encode(pinotRow{
+			EventID:   event.ID,
+			UserID:    event.UserID,
+			EventType: event.EventType,
+			Payload:   event.Payload,
+			CreatedAt: event.CreatedAt.UnixMilli(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	_, err = r.do(ctx, http.MethodPost, r.ingestURL(), &body, mw.FormDataContentType())
+
+	return err
+}
+
+func (r *PinotRepo) ingestURL() string {
+	batchConfig, _ := json.Marshal(map[string]string{
+		"inputFormat":              "json",
+		"segmentNameGeneratorType": "fixed",
+		"segmentName":              fmt.Sprintf("events_%d_%d", r.runID, r.segmentSeq.Add(1)),
+	})
+
+	return r.controllerURL + "/ingestFromFile?tableNameWithType=events_OFFLINE&batchConfigMapStr=" +
+		url.QueryEscape(string(batchConfig))
+}
+
+type pinotQueryResponse struct {
+	ResultTable struct {
+		Rows [][]any `json:"rows"`
+	} `json:"resultTable"`
+	Exceptions []struct {
+		Message string `json:"message"`
+	} `json:"exceptions"`
+}
+
+// query runs SQL on the broker; numeric cells are decoded as json.Number.
+func (r *PinotRepo) query(ctx context.Context, sql string) ([][]any, error) {
+	payload, err := json.Marshal(map[string]string{"sql": sql})
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := r.do(ctx, http.MethodPost, r.brokerURL+"/query/sql", bytes.NewReader(payload), "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePinotRows(raw)
+}
+
+func parsePinotRows(raw []byte) ([][]any, error) {
+	var resp pinotQueryResponse
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	if err := dec.Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode pinot response: %w", err)
+	}
+
+	if len(resp.Exceptions) > 0 {
+		return nil, fmt.Errorf("pinot query failed: %s", resp.Exceptions[0].Message)
+	}
+
+	return resp.ResultTable.Rows, nil
+}
+
+func (r *PinotRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	// Pinot applies LIMIT 10 by default, so the limit must be explicit.
+	sql := fmt.Sprintf(`
+		SELECT
+			DATETRUNC('HOUR', created_at) AS hour,
+			event_type,
+			COUNT(*) AS cnt,
+			DISTINCTCOUNT(user_id) AS unique_users
+		FROM events
+		WHERE created_at BETWEEN %d AND %d
+		GROUP BY DATETRUNC('HOUR', created_at), event_type
+		ORDER BY DATETRUNC('HOUR', created_at) DESC
+		LIMIT 1000000
+	`, start.UnixMilli(), end.UnixMilli())
+
+	rows, err := r.query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	return pinotEventStats(rows)
+}
+
+func pinotEventStats(rows [][]any) ([]EventStats, error) {
+	stats := make([]EventStats, 0, len(rows))
+
+	for _, row := range rows {
+		if len(row) != 4 {
+			return nil, fmt.Errorf("unexpected pinot row width %d", len(row))
+		}
+
+		var (
+			s    EventStats
+			hour int64
+			err  error
+		)
+
+		if hour, err = pinotInt(row[0]); err != nil {
+			return nil, err
+		}
+
+		if s.Count, err = pinotInt(row[2]); err != nil {
+			return nil, err
+		}
+
+		if s.UniqueUsers, err = pinotInt(row[3]); err != nil {
+			return nil, err
+		}
+
+		s.Hour = time.UnixMilli(hour).UTC()
+		s.EventType = fmt.Sprint(row[1])
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
+
+func pinotInt(v any) (int64, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("unexpected pinot value %v", v)
+	}
+
+	return n.Int64()
+}
+
+type pinotTableSize struct {
+	ReportedSizeInBytes int64 `json:"reportedSizeInBytes"`
+	OfflineSegments     struct {
+		Segments map[string]json.RawMessage `json:"segments"`
+	} `json:"offlineSegments"`
+}
+
+// GetStorageStats reports the segment size from the controller's table size
+// API and the segment count as a detail.
+func (r *PinotRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	stats := &StorageStats{Details: map[string]int64{}}
+
+	if raw, err := r.do(ctx, http.MethodGet, r.controllerURL+"/tables/events/size", nil, ""); err == nil {
+		var size pinotTableSize
+		if json.Unmarshal(raw, &size) == nil {
+			stats.TotalSize = size.ReportedSizeInBytes
+			stats.Details["segments"] = int64(len(size.OfflineSegments.Segments))
+		}
+	}
+
+	if rows, err := r.query(ctx, "SELECT COUNT(*) FROM events"); err == nil && len(rows) == 1 && len(rows[0]) == 1 {
+		stats.RowCount, _ = pinotInt(rows[0][0])
+	}
+
+	return stats
+}
+
+func (r *PinotRepo) Cleanup(ctx context.Context) error {
+	_, err := r.do(ctx, http.MethodDelete, r.controllerURL+"/segments/events?type=OFFLINE", nil, "")
+	return err
+}
+
+func (r *PinotRepo) Close() error {
+	r.http.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePinotEventStats(t *testing.T) {
+	raw := []byte(`{
+		"resultTable": {
+			"dataSchema": {"columnNames": ["hour", "event_type", "cnt", "unique_users"]},
+			"rows": [[1704207600000, "click", 12, 7], [1704204000000, "view", 3, 3]]
+		},
+		"exceptions": []
+	}`)
+
+	rows, err := parsePinotRows(raw)
+	require.NoError(t, err)
+
+	stats, err := pinotEventStats(rows)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC), stats[0].Hour)
+	assert.Equal(t, "click", stats[0].EventType)
+	assert.Equal(t, int64(12), stats[0].Count)
+	assert.Equal(t, int64(7), stats[0].UniqueUsers)
+}
+
+func TestParsePinotRowsException(t *testing.T) {
+	_, err := parsePinotRows([]byte(`{"exceptions": [{"errorCode": 150, "message": "SQLParsingError"}]}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SQLParsingError")
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// setPool sizes the connection pool of db.
+func setPool(db *sql.DB, pool config.PoolConfig) {
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
@@ -12,28 +14,147 @@ import (
 )
 
 type PostgresRepo struct {
-	db *sql.DB
+	db         *sql.DB
+	reads      *sql.DB // the read replicas the queries go to, db without any
+	driver     string  // pq or pgx
+	insertMode string  // prepared or values
+	valuesRows int     // events per INSERT in the values insert mode
+	flavor     string
+	jsonb      bool          // payload stored as JSONB with a GIN index
+	trgm       bool          // trigram index for the payload search
+	indexes    string        // secondary index set
+	deferred   bool          // InitSchema leaves the secondary indexes to CreateIndexes
+	table      string        // name of the events table
+	window     time.Duration // span of the created_at of the events the partitions cover
+	tenants    bool          // events carry a tenant_id the schema is clustered by
+	ttl        time.Duration
 }
 
+// pgJSONBFilter is the payload predicate added to the stats query in JSONB
+// mode; containment is served by the GIN index.
+const pgJSONBFilter = `payload @> '{"method": "POST"}'`
+
 func NewPostgresRepo(ctx context.Context, cfg *config.PostgresConfig) (*PostgresRepo, error) {
-	db, err := sql.Open("postgres", cfg.DSN())
+	r, err := newPostgresRepo(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	driverName, err := postgresDriver(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.db, r.reads, err = openPostgres(ctx, cfg, driverName); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// newPostgresRepo returns a repository with the validated settings of cfg,
+// before it connects.
+func newPostgresRepo(cfg *config.PostgresConfig) (*PostgresRepo, error) {
+	flavor, err := postgresFlavor(cfg.Flavor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePostgresPayloadType(cfg.PayloadType); err != nil {
+		return nil, err
+	}
+
+	indexes, err := indexSet(cfg.Indexes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+		return nil, err
 	}
 
-	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	table, err := tableName(cfg.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	insertMode, err := postgresInsertMode(cfg.InsertMode, cfg.ValuesRows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresRepo{
+		driver:     cfg.Driver,
+		insertMode: insertMode,
+		valuesRows: cfg.ValuesRows,
+		flavor:     flavor,
+		jsonb:      cfg.PayloadType == "jsonb",
+		trgm:       cfg.TrigramIndex,
+		indexes:    indexes,
+		table:      table,
+		window:     cfg.EventWindow,
+		tenants:    cfg.Tenants,
+	}, nil
+}
+
+// postgresFlavor checks a flavor, plain PostgreSQL when empty.
+func postgresFlavor(flavor string) (string, error) {
+	if flavor == "" {
+		flavor = pgFlavorPostgres
+	}
+
+	return flavor, validatePostgresFlavor(flavor)
+}
+
+// openPostgres opens the pool of cfg with driverName, and the pool of its
+// read replicas; without replicas the queries read from the first.
+func openPostgres(ctx context.Context, cfg *config.PostgresConfig, driverName string) (db, reads *sql.DB, err error) {
+	db, err = sql.Open(driverName, cfg.DSN())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	setPool(db, cfg.Pool)
 
 	// Test connection
 	if err := db.PingContext(ctx); err != nil {
 		_ = db.Close()
 
-		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+		return nil, nil, fmt.Errorf("failed to ping postgres: %w", err)
 	}
 
-	return &PostgresRepo{db: db}, nil
+	reads, err = openPostgresReplicas(ctx, cfg)
+	if err != nil {
+		_ = db.Close()
+
+		return nil, nil, err
+	}
+
+	if reads == nil {
+		reads = db
+	}
+
+	return db, reads, nil
+}
+
+// openPostgresReplicas opens a pool over the read replicas of cfg, spreading
+// its connections across them, or returns nil without replicas.
+func openPostgresReplicas(ctx context.Context, cfg *config.PostgresConfig) (*sql.DB, error) {
+	dsn := cfg.ReplicaDSN()
+	if dsn == "" {
+		return nil, nil
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres replica connection: %w", err)
+	}
+
+	setPool(db, cfg.Pool)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("failed to ping postgres replicas: %w", err)
+	}
+
+	return db, nil
 }
 
 func (r *PostgresRepo) InitSchema(ctx context.Context) error {
@@ -45,12 +166,16 @@ func (r *PostgresRepo) InitSchema(ctx context.Context) error {
 			event_id VARCHAR(255) NOT NULL,
 			user_id BIGINT NOT NULL,
 			event_type VARCHAR(50) NOT NULL,
-			payload TEXT,
-			created_at TIMESTAMP NOT NULL
-		) PARTITION BY RANGE (created_at);
+			payload ` + r.payloadType() + `,
+			created_at TIMESTAMP NOT NULL` + r.tenantColumn() + `
+		)` + pgDistributedBy(r.flavor, r.distribution()) + ` PARTITION BY RANGE (created_at);
 	`
 
-	if _, err := r.db.ExecContext(ctx, schema); err != nil {
+	if _, err := r.db.ExecContext(ctx, tableSQL(schema, r.table)); err != nil {
+		return err
+	}
+
+	if err := r.distributeTable(ctx); err != nil {
 		return err
 	}
 
@@ -59,25 +184,110 @@ func (r *PostgresRepo) InitSchema(ctx context.Context) error {
 	}
 
 	// Create indexes on the partitioned table
-	indexes := `
-		CREATE INDEX idx_events_created_at ON events USING brin(created_at) WITH (pages_per_range = 32);
-		CREATE INDEX idx_events_type_time ON events(event_type, created_at);
-		CREATE INDEX idx_events_user_id ON events(user_id);
-		CREATE UNIQUE INDEX idx_events_event_id ON events(event_id, created_at);
-	`
+	indexes := pgUniqueIndex(r.flavor, r.distribution())
+	if !r.deferred {
+		indexes += r.secondaryIndexes()
+	}
 
-	_, err := r.db.ExecContext(ctx, indexes)
+	_, err := r.db.ExecContext(ctx, tableSQL(indexes, r.table))
 
 	return err
 }
 
+// secondaryIndexes returns the DDL of the index set with the opt-in
+// payload indexes.
+func (r *PostgresRepo) secondaryIndexes() string {
+	indexes := pgSecondaryIndexes(r.indexes)
+
+	if r.tenants && r.indexes != config.IndexesNone {
+		indexes += "CREATE INDEX idx_events_tenant_time ON events(tenant_id, created_at);"
+	}
+
+	if r.jsonb {
+		indexes += "CREATE INDEX idx_events_payload ON events USING gin(payload);"
+	}
+
+	if r.trgm {
+		indexes += `
+			CREATE EXTENSION IF NOT EXISTS pg_trgm;
+			CREATE INDEX idx_events_payload_trgm ON events USING gin((payload::text) gin_trgm_ops);
+		`
+	}
+
+	return indexes
+}
+
+// DeferIndexes makes InitSchema create the table without its secondary
+// indexes, which CreateIndexes then builds on the loaded events.
+func (r *PostgresRepo) DeferIndexes(deferred bool) {
+	r.deferred = deferred
+}
+
+// CreateIndexes builds the secondary indexes InitSchema deferred.
+func (r *PostgresRepo) CreateIndexes(ctx context.Context) error {
+	indexes := r.secondaryIndexes()
+	if indexes == "" {
+		return nil
+	}
+
+	_, err := r.db.ExecContext(ctx, tableSQL(indexes, r.table))
+
+	return err
+}
+
+// pgUniqueIndex returns the DDL of the unique index, which backs the ON
+// CONFLICT of the inserts, so every index set has it.
+func pgUniqueIndex(flavor, distribution string) string {
+	return `
+		CREATE UNIQUE INDEX idx_events_event_id ON events(` + pgUniqueKey(flavor, distribution) + `);
+	`
+}
+
+func pgSecondaryIndexes(set string) string {
+	var indexes string
+
+	if set != config.IndexesNone {
+		indexes += "CREATE INDEX idx_events_created_at ON events USING brin(created_at) WITH (pages_per_range = 32);"
+	}
+
+	if set == config.IndexesFull {
+		indexes += `
+			CREATE INDEX idx_events_type_time ON events(event_type, created_at);
+			CREATE INDEX idx_events_user_id ON events(user_id);
+		`
+	}
+
+	return indexes
+}
+
+func validatePostgresPayloadType(payloadType string) error {
+	switch payloadType {
+	case "", "text", "jsonb":
+		return nil
+	default:
+		return fmt.Errorf("unsupported postgres payload type: %s", payloadType)
+	}
+}
+
+func (r *PostgresRepo) payloadType() string {
+	if r.jsonb {
+		return "JSONB"
+	}
+
+	return "TEXT"
+}
+
+// pgPartitionMonths is the number of months before the current one that get
+// a partition whatever the event window.
+const pgPartitionMonths = 4
+
 func (r *PostgresRepo) createPartitions(ctx context.Context) error {
 	now := time.Now()
-	for i := -4; i <= 0; i++ {
+	for i := -pgMonthsBack(now, r.window); i <= 0; i++ {
 		start := time.Date(now.Year(), now.Month()+time.Month(i), 1, 0, 0, 0, 0, time.UTC)
 		end := start.AddDate(0, 1, 0)
 
-		partName := "events_" + start.Format("200601")
+		partName := r.table + "_" + start.Format("200601")
 		if err := r.createPartition(ctx, partName, start, end); err != nil {
 			return err
 		}
@@ -86,9 +296,18 @@ func (r *PostgresRepo) createPartitions(ctx context.Context) error {
 	return nil
 }
 
+// pgMonthsBack returns the number of monthly partitions before the current
+// one that hold the events of window before now, pgPartitionMonths at least.
+func pgMonthsBack(now time.Time, window time.Duration) int {
+	oldest := now.Add(-window)
+	months := (now.Year()-oldest.Year())*12 + int(now.Month()-oldest.Month())
+
+	return max(pgPartitionMonths, months)
+}
+
 func (r *PostgresRepo) createPartition(ctx context.Context, name string, start, end time.Time) error {
 	query := "CREATE TABLE IF NOT EXISTS " + pq.QuoteIdentifier(name) +
-		" PARTITION OF events FOR VALUES FROM (" + pq.QuoteLiteral(start.Format("2006-01-02")) +
+		" PARTITION OF " + pq.QuoteIdentifier(r.table) + " FOR VALUES FROM (" + pq.QuoteLiteral(start.Format("2006-01-02")) +
 		") TO (" + pq.QuoteLiteral(end.Format("2006-01-02")) + ")"
 
 	_, err := r.db.ExecContext(ctx, query)
@@ -100,6 +319,20 @@ func (r *PostgresRepo) createPartition(ctx context.Context, name string, start,
 }
 
 func (r *PostgresRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	if r.insertMode == pgInsertValues {
+		return r.insertBatchValues(ctx, events)
+	}
+
+	if r.driver == pgDriverPGX {
+		return r.insertBatchPGX(ctx, events)
+	}
+
+	return r.insertBatchPrepared(ctx, events)
+}
+
+// insertBatchPrepared inserts the events one by one with a prepared
+// statement in one transaction.
+func (r *PostgresRepo) insertBatchPrepared(ctx context.Context, events []generator.Event) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -107,26 +340,17 @@ func (r *PostgresRepo) InsertBatch(ctx context.Context, events []generator.Event
 
 	defer func() { _ = tx.Rollback() }()
 
-	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO events (event_id, user_id, event_type, payload, created_at)
-		VALUES ($1, $2, $3, $4, $5)
-		ON CONFLICT (event_id, created_at) DO NOTHING
-	`)
+	stmt, err := tx.PrepareContext(ctx, r.insertEventQuery())
 	if err != nil {
 		return err
 	}
 
 	defer func() { _ = stmt.Close() }()
 
-	for _, event := range events {
-		_, err := stmt.ExecContext(ctx,
-			event.ID,
-			event.UserID,
-			event.EventType,
-			event.Payload,
-			event.CreatedAt,
-		)
-		if err != nil {
+	args := make([]any, 0, r.insertColumnCount())
+
+	for i := range events {
+		if _, err := stmt.ExecContext(ctx, r.insertArgs(args[:0], &events[i])...); err != nil {
 			return err
 		}
 	}
@@ -134,7 +358,80 @@ func (r *PostgresRepo) InsertBatch(ctx context.Context, events []generator.Event
 	return tx.Commit()
 }
 
+func (r *PostgresRepo) insertEventQuery() string {
+	params := make([]string, r.insertColumnCount())
+	for i := range params {
+		params[i] = "$" + strconv.Itoa(i+1)
+	}
+
+	return tableSQL(`
+		INSERT INTO events (`+r.insertColumns()+`)
+		VALUES (`+strings.Join(params, ", ")+`)
+		ON CONFLICT (`+pgUniqueKey(r.flavor, r.distribution())+`) DO NOTHING
+	`, r.table)
+}
+
+// insertColumns returns the columns an insert writes, those of an event
+// and tenant_id with tenants.
+func (r *PostgresRepo) insertColumns() string {
+	if r.tenants {
+		return sqlEventColumns + ", tenant_id"
+	}
+
+	return sqlEventColumns
+}
+
+func (r *PostgresRepo) insertColumnCount() int {
+	return strings.Count(r.insertColumns(), ",") + 1
+}
+
+// insertArgs appends the values of the insert columns of event to args.
+func (r *PostgresRepo) insertArgs(args []any, event *generator.Event) []any {
+	args = append(args, event.ID, event.UserID, event.EventType, event.Payload, event.CreatedAt)
+	if r.tenants {
+		args = append(args, event.TenantID)
+	}
+
+	return args
+}
+
+// tenantColumn returns the definition of the tenant_id column with tenants.
+func (r *PostgresRepo) tenantColumn() string {
+	if !r.tenants {
+		return ""
+	}
+
+	return ",\n\t\t\ttenant_id BIGINT NOT NULL"
+}
+
 func (r *PostgresRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	return r.eventStats(ctx, "created_at BETWEEN $1 AND $2", start, end)
+}
+
+// GetTenantEventStats is GetEventStats over the events of one tenant,
+// through idx_events_tenant_time, on the one node of Citus and Greenplum
+// that holds the tenant.
+func (r *PostgresRepo) GetTenantEventStats(ctx context.Context, tenantID int64, start, end time.Time) ([]EventStats, error) {
+	return r.eventStats(ctx, "tenant_id = $1 AND created_at BETWEEN $2 AND $3", tenantID, start, end)
+}
+
+// GetTenantEvents reads the newest events of a tenant through
+// idx_events_tenant_time.
+func (r *PostgresRepo) GetTenantEvents(ctx context.Context, tenantID int64, limit int) ([]generator.Event, error) {
+	return querySQLEvents(ctx, r.reads, scanSQLEvent, tableSQL(tenantEventsQueryDollar, r.table), tenantID, limit)
+}
+
+// Tenants reports whether the events table has the tenant_id column.
+func (r *PostgresRepo) Tenants() bool {
+	return r.tenants
+}
+
+// eventStats runs the stats query over the events matching where.
+func (r *PostgresRepo) eventStats(ctx context.Context, where string, args ...any) ([]EventStats, error) {
+	if r.jsonb {
+		where += " AND " + pgJSONBFilter
+	}
+
 	query := `
 		SELECT 
 			date_trunc('hour', created_at) as hour,
@@ -142,12 +439,12 @@ func (r *PostgresRepo) GetEventStats(ctx context.Context, start, end time.Time)
 			COUNT(*) as count,
 			COUNT(DISTINCT user_id) as unique_users
 		FROM events
-		WHERE created_at BETWEEN $1 AND $2
+		WHERE ` + where + `
 		GROUP BY hour, event_type
 		ORDER BY hour DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, start, end)
+	rows, err := r.reads.QueryContext(ctx, tableSQL(query, r.table), args...)
 	if err != nil {
 		return nil, err
 	}
@@ -168,33 +465,329 @@ func (r *PostgresRepo) GetEventStats(ctx context.Context, start, end time.Time)
 	return stats, rows.Err()
 }
 
+// GetEventByID looks an event up through the event_id unique index, probing
+// every partition since the lookup carries no created_at.
+func (r *PostgresRepo) GetEventByID(ctx context.Context, eventID string) (*generator.Event, error) {
+	return scanSQLEvent(r.reads.QueryRowContext(ctx, tableSQL(eventByIDQueryDollar, r.table), eventID))
+}
+
+// GetUserEvents reads the newest events of a user through idx_events_user_id.
+func (r *PostgresRepo) GetUserEvents(ctx context.Context, userID int64, limit int) ([]generator.Event, error) {
+	return querySQLEvents(ctx, r.reads, scanSQLEvent, tableSQL(userEventsQueryDollar, r.table), userID, limit)
+}
+
+// GetTopUsers returns the n users with the most events in [start, end].
+func (r *PostgresRepo) GetTopUsers(ctx context.Context, start, end time.Time, n int) ([]UserCount, error) {
+	return querySQLTopUsers(ctx, r.reads, tableSQL(topUsersQueryDollar, r.table), start, end, n)
+}
+
+// GetUserGroups groups the events in [start, end] by user_id.
+func (r *PostgresRepo) GetUserGroups(ctx context.Context, start, end time.Time) (*UserGroupStats, error) {
+	return querySQLUserGroups(ctx, r.reads, tableSQL(userGroupsQueryDollar, r.table), start, end)
+}
+
+// SearchPayload counts the events in [start, end] whose payload contains
+// token, through the trigram index when POSTGRES_TRGM_INDEX is set.
+func (r *PostgresRepo) SearchPayload(ctx context.Context, token string, start, end time.Time) (int64, error) {
+	return querySQLPayloadSearch(ctx, r.reads, tableSQL(payloadSearchQueryDollar, r.table), start, end, token)
+}
+
+// ScanPages reads the events in [start, end] in pages of pageSize with keyset
+// pagination on (created_at, event_id). No index covers the key, so every
+// page sorts the rows after the previous one.
+func (r *PostgresRepo) ScanPages(ctx context.Context, start, end time.Time, pageSize int, page PageFunc) error {
+	return keysetPages(start, pageSize, page, func(after time.Time, afterID string) ([]generator.Event, error) {
+		return querySQLEvents(ctx, r.reads, scanSQLEvent, tableSQL(eventsPageQueryDollar, r.table), after, afterID, end, pageSize)
+	})
+}
+
+// ExportEvents streams every event in [start, end] from the partitions the
+// range overlaps in a single query.
+func (r *PostgresRepo) ExportEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return exportSQLEvents(ctx, r.reads, scanSQLEvent, tableSQL(exportQueryDollar, r.table), start, end)
+}
+
+// CountSessions counts the sessions in [start, end] with LAG over each
+// user's events; the window sorts the whole range by user_id and created_at.
+func (r *PostgresRepo) CountSessions(ctx context.Context, start, end time.Time, gap time.Duration) (int64, error) {
+	return querySQLCount(ctx, r.reads, tableSQL(sessionsQueryDollar, r.table), start, end, int64(gap/time.Second))
+}
+
+// CountEvents counts the events in [start, end] across the partitions the
+// range overlaps.
+func (r *PostgresRepo) CountEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return querySQLCount(ctx, r.reads, tableSQL(countQueryDollar, r.table), start, end)
+}
+
+// RunStatement runs a custom query scenario statement, binding the window
+// as $1 and $2, and returns the rows it produced.
+func (r *PostgresRepo) RunStatement(ctx context.Context, statement string, start, end time.Time) (int64, error) {
+	return runSQLStatement(ctx, r.reads, tableSQL(statement, r.table), start, end)
+}
+
+// EventExists checks for an event ID with EXISTS, which stops at the first
+// match in the event_id index.
+func (r *PostgresRepo) EventExists(ctx context.Context, eventID string) (bool, error) {
+	n, err := querySQLCount(ctx, r.reads, tableSQL(existsQueryDollar, r.table), eventID)
+	return n > 0, err
+}
+
+// InitUsersSchema recreates the users dimension table. Greenplum distributes
+// it by user_id like events without tenants, and Citus replicates it to
+// every worker as a reference table, so the join stays local to each shard.
+func (r *PostgresRepo) InitUsersSchema(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		DROP TABLE IF EXISTS users;
+
+		CREATE TABLE users (
+			user_id BIGINT PRIMARY KEY,
+			country VARCHAR(2) NOT NULL,
+			plan VARCHAR(16) NOT NULL
+		)`+pgDistributedBy(r.flavor, pgDistUser))
+	if err != nil {
+		return err
+	}
+
+	if r.flavor == pgFlavorCitus {
+		_, err = r.db.ExecContext(ctx, `SELECT create_reference_table('users')`)
+	}
+
+	return err
+}
+
+func (r *PostgresRepo) InsertUsers(ctx context.Context, users []generator.User) error {
+	return insertSQLUsers(ctx, r.db, users, true)
+}
+
+// GetCountryBreakdown joins the events in [start, end] to users and counts
+// them per country.
+func (r *PostgresRepo) GetCountryBreakdown(ctx context.Context, start, end time.Time) ([]CountryCount, error) {
+	return querySQLCountryBreakdown(ctx, r.reads, tableSQL(countryBreakdownQueryDollar, r.table), start, end)
+}
+
+// InitTransactionSchema recreates the per-user counter table of the
+// transactional workload. Citus distributes it by user_id like events, so
+// each transaction touches the shards of a single user; with tenants the
+// events are distributed by tenant_id, and it touches two.
+func (r *PostgresRepo) InitTransactionSchema(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		DROP TABLE IF EXISTS user_counters;
+
+		CREATE TABLE user_counters (
+			user_id BIGINT PRIMARY KEY,
+			events BIGINT NOT NULL
+		)`+pgDistributedBy(r.flavor, pgDistUser))
+	if err != nil {
+		return err
+	}
+
+	if r.flavor == pgFlavorCitus {
+		_, err = r.db.ExecContext(ctx, `SELECT create_distributed_table('user_counters', 'user_id')`)
+	}
+
+	return err
+}
+
+// InsertEventTx inserts the event and upserts its user's counter row in one
+// transaction.
+func (r *PostgresRepo) InsertEventTx(ctx context.Context, event generator.Event) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, r.insertEventQuery(), r.insertArgs(nil, &event)...)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_counters (user_id, events) VALUES ($1, 1)
+		ON CONFLICT (user_id) DO UPDATE SET events = user_counters.events + 1
+	`, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (r *PostgresRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	switch r.flavor {
+	case pgFlavorCitus:
+		return r.citusStorageStats(ctx)
+	case pgFlavorGreenplum:
+		return r.greenplumStorageStats(ctx)
+	default:
+		return r.partitionStorageStats(ctx)
+	}
+}
+
+func (r *PostgresRepo) partitionStorageStats(ctx context.Context) *StorageStats {
 	var stats StorageStats
 
 	// Sum sizes across all partitions (pg_total_relation_size on a
 	// partitioned parent may return 0 in some PostgreSQL versions).
-	err := r.db.QueryRowContext(ctx, `
+	err := r.db.QueryRowContext(ctx, tableSQL(`
 		SELECT
 			COALESCE(SUM(pg_total_relation_size(inhrelid::regclass)), 0),
 			COALESCE(SUM(pg_indexes_size(inhrelid::regclass)), 0)
 		FROM pg_inherits
 		WHERE inhparent = 'events'::regclass
-	`).Scan(&stats.TotalSize, &stats.IndexSize)
+	`, r.table)).Scan(&stats.TotalSize, &stats.IndexSize)
 	if err != nil {
 		return &StorageStats{}
 	}
 
 	// Row count separately to avoid mixing aggregate with system functions
-	_ = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM events`).Scan(&stats.RowCount)
+	_ = r.db.QueryRowContext(ctx, tableSQL(`SELECT COUNT(*) FROM events`, r.table)).Scan(&stats.RowCount)
 
 	return &stats
 }
 
+// DeleteOlderThan drops the monthly partitions that end at or before the
+// cutoff and deletes the remaining older rows in batches.
+func (r *PostgresRepo) DeleteOlderThan(ctx context.Context, cutoff time.Time) (*DeleteStats, error) {
+	dropped, err := r.dropPartitionsBefore(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := deleteInBatches(ctx, func(ctx context.Context) (int64, error) {
+		res, err := r.db.ExecContext(ctx, tableSQL(`
+			DELETE FROM events
+			WHERE created_at < $1
+			AND id IN (SELECT id FROM events WHERE created_at < $1 LIMIT $2)
+		`, r.table), cutoff, retentionBatchSize)
+		if err != nil {
+			return 0, err
+		}
+
+		return res.RowsAffected()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete events: %w", err)
+	}
+
+	return &DeleteStats{Rows: dropped + deleted, Method: partitionDeleteMethod(dropped > 0, deleted)}, nil
+}
+
+// EnableTTL records the TTL for ExpireTTL. PostgreSQL has no native row
+// expiry; deployments schedule the retention delete with pg_cron instead.
+func (r *PostgresRepo) EnableTTL(_ context.Context, ttl time.Duration) (string, error) {
+	r.ttl = ttl
+
+	return ttlMethodJob, nil
+}
+
+// ExpireTTL runs one pass of the scheduled job: partition drops followed by
+// batched deletes of the rows older than the TTL.
+func (r *PostgresRepo) ExpireTTL(ctx context.Context) error {
+	_, err := r.DeleteOlderThan(ctx, time.Now().Add(-r.ttl))
+
+	return err
+}
+
+// dropPartitionsBefore drops every partition whose range ends at or before
+// the cutoff and returns the number of rows they held.
+func (r *PostgresRepo) dropPartitionsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	partitions, err := r.partitionNames(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var rows int64
+
+	for _, name := range partitions {
+		end, err := monthPartitionEnd(strings.TrimPrefix(name, r.table+"_"))
+		if err != nil || end.After(cutoff) {
+			continue
+		}
+
+		var n int64
+		if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+pq.QuoteIdentifier(name)).Scan(&n); err != nil {
+			return rows, fmt.Errorf("failed to count partition %s: %w", name, err)
+		}
+
+		if _, err := r.db.ExecContext(ctx, "DROP TABLE "+pq.QuoteIdentifier(name)); err != nil {
+			return rows, fmt.Errorf("failed to drop partition %s: %w", name, err)
+		}
+
+		rows += n
+	}
+
+	return rows, nil
+}
+
+func (r *PostgresRepo) partitionNames(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, tableSQL(`
+		SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'events'::regclass
+	`, r.table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var names []string
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
 func (r *PostgresRepo) Cleanup(ctx context.Context) error {
-	_, err := r.db.ExecContext(ctx, "TRUNCATE TABLE events")
+	_, err := r.db.ExecContext(ctx, tableSQL("TRUNCATE TABLE events", r.table))
 	return err
 }
 
 func (r *PostgresRepo) Close() error {
+	if r.reads != r.db {
+		_ = r.reads.Close()
+	}
+
 	return r.db.Close()
 }
+
+// ServerVersion returns server_version, e.g. "16.2"; YugabyteDB reports
+// its PostgreSQL layer with its own version after it, e.g. "11.2-YB-2.20.1.0-b0".
+func (r *PostgresRepo) ServerVersion(ctx context.Context) (string, error) {
+	return querySQLVersion(ctx, r.db, "SHOW server_version")
+}
+
+// CheckPermissions checks that the user can create, write to and drop a
+// table, and that the read replicas answer.
+func (r *PostgresRepo) CheckPermissions(ctx context.Context) error {
+	if err := checkSQLPermissions(ctx, r.db, "CREATE TABLE "+checkTable+" (id bigint)"); err != nil {
+		return err
+	}
+
+	if r.reads != r.db {
+		if err := r.reads.PingContext(ctx); err != nil {
+			return fmt.Errorf("failed to reach the read replicas: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// VerifySchema checks that the events table exists in the current schema
+// with the columns of an event, and tenant_id with tenants. Its name is
+// unquoted, so folded to lower case.
+func (r *PostgresRepo) VerifySchema(ctx context.Context) error {
+	return verifySQLSchema(ctx, r.db, r.table, tenantColumns(r.tenants), `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1
+	`, strings.ToLower(r.table))
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// Postgres flavors selected by POSTGRES_FLAVOR. Citus and Greenplum shard the
+// events table across worker nodes or segments by user_id, or tenant_id with
+// tenants, so the unique key has to include the distribution column.
+const (
+	pgFlavorPostgres  = "postgres"
+	pgFlavorCitus     = "citus"
+	pgFlavorGreenplum = "greenplum"
+)
+
+func validatePostgresFlavor(flavor string) error {
+	switch flavor {
+	case pgFlavorPostgres, pgFlavorCitus, pgFlavorGreenplum:
+		return nil
+	default:
+		return fmt.Errorf("unsupported postgres flavor: %s", flavor)
+	}
+}
+
+// Distribution columns of Citus and Greenplum. Distributing the events by
+// tenant keeps the events of a tenant on one node, as multi-tenant
+// applications shard.
+const (
+	pgDistUser   = "user_id"
+	pgDistTenant = "tenant_id"
+)
+
+// distribution returns the distribution column of the events table.
+func (r *PostgresRepo) distribution() string {
+	if r.tenants {
+		return pgDistTenant
+	}
+
+	return pgDistUser
+}
+
+// pgUniqueKey returns the columns of the unique index used for ON CONFLICT.
+func pgUniqueKey(flavor, distribution string) string {
+	if flavor == pgFlavorPostgres {
+		return "event_id, created_at"
+	}
+
+	return "event_id, created_at, " + distribution
+}
+
+// pgDistributedBy returns the Greenplum distribution clause, which must precede
+// PARTITION BY in CREATE TABLE.
+func pgDistributedBy(flavor, distribution string) string {
+	if flavor == pgFlavorGreenplum {
+		return " DISTRIBUTED BY (" + distribution + ")"
+	}
+
+	return ""
+}
+
+// distributeTable turns the partitioned events table into a Citus distributed
+// table; partitions created afterwards are distributed the same way.
+func (r *PostgresRepo) distributeTable(ctx context.Context) error {
+	if r.flavor != pgFlavorCitus {
+		return nil
+	}
+
+	query := `SELECT create_distributed_table('events', '` + r.distribution() + `')`
+	if _, err := r.db.ExecContext(ctx, tableSQL(query, r.table)); err != nil {
+		return fmt.Errorf("failed to distribute events table: %w", err)
+	}
+
+	return nil
+}
+
+// citusStorageStats sums shard sizes on the workers for every partition;
+// pg_total_relation_size only sees the empty shell on the coordinator.
+func (r *PostgresRepo) citusStorageStats(ctx context.Context) *StorageStats {
+	stats := &StorageStats{Details: map[string]int64{}}
+
+	var tableSize, shards, workers int64
+
+	err := r.db.QueryRowContext(ctx, tableSQL(`
+		SELECT
+			COALESCE(SUM(citus_total_relation_size(inhrelid::regclass)), 0),
+			COALESCE(SUM(citus_table_size(inhrelid::regclass)), 0)
+		FROM pg_inherits
+		WHERE inhparent = 'events'::regclass
+	`, r.table)).Scan(&stats.TotalSize, &tableSize)
+	if err != nil {
+		return &StorageStats{}
+	}
+
+	stats.IndexSize = stats.TotalSize - tableSize
+
+	if r.db.QueryRowContext(ctx, tableSQL(`
+		SELECT COUNT(*) FROM pg_dist_shard
+		WHERE logicalrelid IN (SELECT inhrelid FROM pg_inherits WHERE inhparent = 'events'::regclass)
+	`, r.table)).Scan(&shards) == nil {
+		stats.Details["shards"] = shards
+	}
+
+	if r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM citus_get_active_worker_nodes()`).Scan(&workers) == nil {
+		stats.Details["workers"] = workers
+	}
+
+	_ = r.db.QueryRowContext(ctx, tableSQL(`SELECT COUNT(*) FROM events`, r.table)).Scan(&stats.RowCount)
+
+	return stats
+}
+
+// greenplumStorageStats reports partition sizes, which Greenplum already sums
+// across segments, plus the number of primary segments.
+func (r *PostgresRepo) greenplumStorageStats(ctx context.Context) *StorageStats {
+	stats := r.partitionStorageStats(ctx)
+
+	var segments int64
+	if r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM gp_segment_configuration WHERE role = 'p' AND content >= 0
+	`).Scan(&segments) == nil {
+		stats.Details = map[string]int64{"segments": segments}
+	}
+
+	return stats
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// Drivers of PostgreSQL and YugabyteDB. Both serve the queries through
+// database/sql; pgx inserts a batch with its native batch API.
+const (
+	pgDriverPQ  = "pq"
+	pgDriverPGX = "pgx"
+)
+
+// postgresDriver returns the database/sql driver name of the driver of cfg.
+// pgx tries several hosts in order only, so it takes no load balancing
+// across them nor replicas, which rely on it.
+func postgresDriver(cfg *config.PostgresConfig) (string, error) {
+	switch cfg.Driver {
+	case "", pgDriverPQ:
+		return "postgres", nil
+	case pgDriverPGX:
+		if cfg.LoadBalanceHosts != "" || len(cfg.Replicas) > 0 {
+			return "", errors.New("the pgx driver supports neither load_balance_hosts nor replicas")
+		}
+
+		return "pgx", nil
+	default:
+		return "", fmt.Errorf("unsupported postgres driver: %s (must be %s or %s)", cfg.Driver, pgDriverPQ, pgDriverPGX)
+	}
+}
+
+// insertBatchPGX sends the inserts of a batch as one pgx batch, in a single
+// round trip within a transaction, with the parameters in the binary
+// format.
+func (r *PostgresRepo) insertBatchPGX(ctx context.Context, events []generator.Event) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	query := r.insertEventQuery()
+
+	batch := &pgx.Batch{}
+	for i := range events {
+		event := &events[i]
+		batch.Queue(query, r.insertArgs(nil, event)...)
+	}
+
+	return conn.Raw(func(driverConn any) error {
+		pc := driverConn.(*stdlib.Conn).Conn()
+
+		return pgx.BeginFunc(ctx, pc, func(tx pgx.Tx) error {
+			return tx.SendBatch(ctx, batch).Close()
+		})
+	})
+}
+
+// DriverModule returns the Go module of the driver in use.
+func (r *PostgresRepo) DriverModule() string {
+	if r.driver == pgDriverPGX {
+		return "github.com/jackc/pgx/v5"
+	}
+
+	return "github.com/lib/pq"
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresFlavorSchema(t *testing.T) {
+	assert.Equal(t, "event_id, created_at", pgUniqueKey(pgFlavorPostgres, pgDistUser))
+	assert.Equal(t, "event_id, created_at, user_id", pgUniqueKey(pgFlavorCitus, pgDistUser))
+	assert.Equal(t, "event_id, created_at, user_id", pgUniqueKey(pgFlavorGreenplum, pgDistUser))
+
+	assert.Empty(t, pgDistributedBy(pgFlavorPostgres, pgDistUser))
+	assert.Empty(t, pgDistributedBy(pgFlavorCitus, pgDistUser))
+	assert.Equal(t, " DISTRIBUTED BY (user_id)", pgDistributedBy(pgFlavorGreenplum, pgDistUser))
+}
+
+func TestPostgresTenantSchema(t *testing.T) {
+	repo := &PostgresRepo{flavor: pgFlavorCitus, indexes: config.IndexesMinimal, tenants: true}
+
+	assert.Equal(t, pgDistTenant, repo.distribution())
+	assert.Equal(t, "event_id, created_at, tenant_id", pgUniqueKey(repo.flavor, repo.distribution()))
+	assert.Equal(t, " DISTRIBUTED BY (tenant_id)", pgDistributedBy(pgFlavorGreenplum, repo.distribution()))
+	assert.Contains(t, repo.tenantColumn(), "tenant_id BIGINT NOT NULL")
+	assert.Contains(t, repo.secondaryIndexes(), "idx_events_tenant_time ON events(tenant_id, created_at)")
+
+	repo.indexes = config.IndexesNone
+	assert.NotContains(t, repo.secondaryIndexes(), "idx_events_tenant_time")
+
+	repo.tenants = false
+	assert.Equal(t, pgDistUser, repo.distribution())
+	assert.Empty(t, repo.tenantColumn())
+}
+
+func TestNewPostgresRepo_UnknownFlavor(t *testing.T) {
+	_, err := NewPostgresRepo(context.Background(), &config.PostgresConfig{Flavor: "cockroach"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported postgres flavor")
+}
+
+func TestNewPostgresRepo_UnknownPayloadType(t *testing.T) {
+	_, err := NewPostgresRepo(context.Background(), &config.PostgresConfig{PayloadType: "xml"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported postgres payload type")
+}
+
+func TestPostgresIndexSets(t *testing.T) {
+	assert.Contains(t, pgUniqueIndex(pgFlavorPostgres, pgDistUser), "CREATE UNIQUE INDEX idx_events_event_id ON events(event_id, created_at)")
+
+	none := (&PostgresRepo{indexes: config.IndexesNone}).secondaryIndexes()
+	assert.NotContains(t, none, "idx_events_created_at")
+
+	minimal := (&PostgresRepo{indexes: config.IndexesMinimal}).secondaryIndexes()
+	assert.Contains(t, minimal, "idx_events_created_at")
+	assert.NotContains(t, minimal, "idx_events_type_time")
+
+	full := (&PostgresRepo{indexes: config.IndexesFull}).secondaryIndexes()
+	assert.Contains(t, full, "idx_events_type_time")
+	assert.Contains(t, full, "idx_events_user_id")
+}
+
+func TestPostgresSecondaryIndexes(t *testing.T) {
+	repo := &PostgresRepo{indexes: config.IndexesNone}
+	assert.Empty(t, repo.secondaryIndexes())
+
+	repo.jsonb = true
+	assert.Contains(t, repo.secondaryIndexes(), "idx_events_payload ON events USING gin(payload)")
+	assert.NotContains(t, repo.secondaryIndexes(), "UNIQUE", "the unique index is never deferred")
+}
+
+func TestNewPostgresRepo_UnknownIndexSet(t *testing.T) {
+	_, err := NewPostgresRepo(context.Background(), &config.PostgresConfig{Indexes: "all"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported index set")
+}
+
+func TestPgMonthsBack(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	assert.Equal(t, pgPartitionMonths, pgMonthsBack(now, 0))
+	assert.Equal(t, pgPartitionMonths, pgMonthsBack(now, 90*day))
+	assert.Equal(t, 6, pgMonthsBack(now, 180*day), "April 20 is six months back")
+	assert.Equal(t, 12, pgMonthsBack(now, 365*day))
+}
+
+func TestPostgresDriver(t *testing.T) {
+	name, err := postgresDriver(&config.PostgresConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres", name)
+
+	name, err = postgresDriver(&config.PostgresConfig{Driver: pgDriverPGX, TargetSessionAttrs: "read-write"})
+	require.NoError(t, err)
+	assert.Equal(t, "pgx", name)
+
+	_, err = postgresDriver(&config.PostgresConfig{Driver: pgDriverPGX, Replicas: []string{"replica1"}})
+	assert.Error(t, err)
+
+	_, err = NewPostgresRepo(context.Background(), &config.PostgresConfig{Driver: "pgx/v4"})
+	assert.ErrorContains(t, err, "unsupported postgres driver")
+}
+
+func TestPostgresInsertMode(t *testing.T) {
+	mode, err := postgresInsertMode("", 1000)
+	require.NoError(t, err)
+	assert.Equal(t, pgInsertPrepared, mode, "prepared by default")
+
+	mode, err = postgresInsertMode(pgInsertValues, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, pgInsertValues, mode)
+
+	_, err = postgresInsertMode(pgInsertValues, pgMaxValuesRows+1)
+	assert.ErrorContains(t, err, "values_rows")
+
+	_, err = postgresInsertMode("copy", 1000)
+	assert.ErrorContains(t, err, "unsupported postgres insert mode")
+}
+
+func TestPostgresSetInsertMode(t *testing.T) {
+	repo := &PostgresRepo{insertMode: pgInsertPrepared, valuesRows: 500}
+
+	require.NoError(t, repo.SetInsertMode(pgInsertValues))
+	assert.Equal(t, pgInsertValues, repo.InsertMode())
+	assert.Error(t, repo.SetInsertMode("copy"))
+	assert.Equal(t, pgInsertValues, repo.InsertMode(), "a rejected mode leaves the mode alone")
+
+	repo.valuesRows = 0
+	require.NoError(t, repo.SetInsertMode(pgInsertPrepared))
+	assert.ErrorContains(t, repo.SetInsertMode(pgInsertValues), "values_rows")
+}
+
+func TestPostgresInsertValuesQuery(t *testing.T) {
+	repo := &PostgresRepo{flavor: pgFlavorPostgres, table: "events_ci"}
+
+	assert.Equal(t,
+		"INSERT INTO events_ci (event_id, user_id, event_type, payload, created_at) "+
+			"VALUES ($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10) ON CONFLICT (event_id, created_at) DO NOTHING",
+		repo.insertValuesQuery(2))
+
+	repo.tenants = true
+
+	assert.Equal(t,
+		"INSERT INTO events_ci (event_id, user_id, event_type, payload, created_at, tenant_id) "+
+			"VALUES ($1, $2, $3, $4, $5, $6), ($7, $8, $9, $10, $11, $12) ON CONFLICT (event_id, created_at) DO NOTHING",
+		repo.insertValuesQuery(2))
+	assert.Contains(t, repo.insertEventQuery(), "VALUES ($1, $2, $3, $4, $5, $6)")
+	assert.Len(t, repo.insertArgs(nil, &generator.Event{TenantID: 3}), 6)
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// Insert modes of PostgreSQL and YugabyteDB: one prepared statement
+// execution per event, or multi-row INSERT ... VALUES statements as ORMs
+// generate them.
+const (
+	pgInsertPrepared = "prepared"
+	pgInsertValues   = "values"
+)
+
+// pgMaxValuesRows is the most events an INSERT can hold: the protocol
+// limits a statement to 65535 parameters, five per event.
+const pgMaxValuesRows = 65535 / 5
+
+// postgresInsertMode validates an insert mode, defaulting it to prepared,
+// and the events per INSERT the values mode takes.
+func postgresInsertMode(mode string, valuesRows int) (string, error) {
+	switch mode {
+	case "", pgInsertPrepared:
+		return pgInsertPrepared, nil
+	case pgInsertValues:
+		if valuesRows < 1 || valuesRows > pgMaxValuesRows {
+			return "", fmt.Errorf("values_rows must be between 1 and %d", pgMaxValuesRows)
+		}
+
+		return pgInsertValues, nil
+	default:
+		return "", fmt.Errorf("unsupported postgres insert mode: %s (must be %s or %s)", mode, pgInsertPrepared, pgInsertValues)
+	}
+}
+
+// InsertModes returns the insert modes SetInsertMode takes.
+func (r *PostgresRepo) InsertModes() []string {
+	return []string{pgInsertPrepared, pgInsertValues}
+}
+
+// InsertMode returns the insert mode in use.
+func (r *PostgresRepo) InsertMode() string {
+	return r.insertMode
+}
+
+// SetInsertMode switches the inserts to prepared statements or multi-row
+// VALUES of values_rows events.
+func (r *PostgresRepo) SetInsertMode(mode string) error {
+	mode, err := postgresInsertMode(mode, r.valuesRows)
+	if err != nil {
+		return err
+	}
+
+	r.insertMode = mode
+
+	return nil
+}
+
+// insertBatchValues inserts a batch in one transaction with INSERT
+// statements of up to valuesRows events each.
+func (r *PostgresRepo) insertBatchValues(ctx context.Context, events []generator.Event) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	args := make([]any, 0, r.insertColumnCount()*min(len(events), r.valuesRows))
+
+	for chunk := range slices.Chunk(events, r.valuesRows) {
+		args = args[:0]
+		for i := range chunk {
+			args = r.insertArgs(args, &chunk[i])
+		}
+
+		if _, err := tx.ExecContext(ctx, r.insertValuesQuery(len(chunk)), args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertValuesQuery returns the INSERT of rows events, numbering their
+// parameters in order.
+func (r *PostgresRepo) insertValuesQuery(rows int) string {
+	var b strings.Builder
+
+	columns := r.insertColumnCount()
+
+	b.WriteString("INSERT INTO " + r.table + " (" + r.insertColumns() + ") VALUES ")
+
+	for i := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteByte('(')
+
+		for col := range columns {
+			if col > 0 {
+				b.WriteString(", ")
+			}
+
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(i*columns + col + 1))
+		}
+
+		b.WriteByte(')')
+	}
+
+	b.WriteString(" ON CONFLICT (" + pgUniqueKey(r.flavor, r.distribution()) + ") DO NOTHING")
+
+	return b.String()
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// QuestDBRepo ingests events over InfluxDB Line Protocol (ILP) via HTTP and
+// runs queries over the PostgreSQL wire protocol.
+type QuestDBRepo struct {
+	db       *sql.DB
+	http     *http.Client
+	writeURL string
+	table    string // name of the events table
+}
+
+func NewQuestDBRepo(ctx context.Context, cfg *config.QuestDBConfig) (*QuestDBRepo, error) {
+	table, err := tableName(cfg.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("postgres", cfg.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open questdb connection: %w", err)
+	}
+
+	setPool(db, cfg.Pool)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("failed to ping questdb: %w", err)
+	}
+
+	return &QuestDBRepo{
+		db:       db,
+		http:     &http.Client{Timeout: 60 * time.Second},
+		writeURL: fmt.Sprintf("http://%s:%s/write?precision=n", cfg.Host, cfg.HTTPPort),
+		table:    table,
+	}, nil
+}
+
+func (r *QuestDBRepo) InitSchema(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, tableSQL("DROP TABLE IF EXISTS events", r.table)); err != nil {
+		return err
+	}
+
+	schema := `
+		CREATE TABLE events (
+			event_id VARCHAR,
+			user_id LONG,
+			event_type SYMBOL,
+			payload VARCHAR,
+			created_at TIMESTAMP
+		) TIMESTAMP(created_at) PARTITION BY DAY WAL
+		DEDUP UPSERT KEYS(created_at, event_id)
+	`
+
+	_, err := r.db.ExecContext(ctx, tableSQL(schema, r.table))
+
+	return err
+}
+
+func (r *QuestDBRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	var buf bytes.Buffer
+
+	for i := range events {
+		writeILPLine(&buf, r.table, &events[i])
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.writeURL, &buf)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("questdb ilp write failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// writeILPLine appends one event to table as an ILP line, using event_type as
+// a symbol (tag) and the creation time in nanoseconds as the designated
+// timestamp.
+func writeILPLine(buf *bytes.Buffer, table string, event *generator.Event) {
+	buf.WriteString(table)
+	buf.WriteString(",event_type=")
+	buf.WriteString(ilpEscapeTag(event.EventType))
+	buf.WriteString(" event_id=")
+	buf.WriteString(ilpQuoteString(event.ID))
+	buf.WriteString(",user_id=")
+	buf.WriteString(strconv.FormatInt(event.UserID, 10))
+	buf.WriteString("i,payload=")
+	buf.WriteString(ilpQuoteString(event.Payload))
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatInt(event.CreatedAt.UnixNano(), 10))
+	buf.WriteByte('\n')
+}
+
+var (
+	ilpTagEscaper    = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `, "\n", `\n`, `\`, `\\`)
+	ilpStringEscaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`, "\n", `\n`)
+)
+
+func ilpEscapeTag(s string) string {
+	return ilpTagEscaper.Replace(s)
+}
+
+func ilpQuoteString(s string) string {
+	return `"` + ilpStringEscaper.Replace(s) + `"`
+}
+
+func (r *QuestDBRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	query := `
+		SELECT
+			created_at AS hour,
+			event_type,
+			count() AS cnt,
+			count_distinct(user_id) AS unique_users
+		FROM events
+		WHERE created_at BETWEEN $1 AND $2
+		SAMPLE BY 1h ALIGN TO CALENDAR
+		ORDER BY hour DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, tableSQL(query, r.table), start.UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var stats []EventStats
+
+	for rows.Next() {
+		var s EventStats
+		if err := rows.Scan(&s.Hour, &s.EventType, &s.Count, &s.UniqueUsers); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+func (r *QuestDBRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	var stats StorageStats
+
+	err := r.db.QueryRowContext(ctx, tableSQL(`
+		SELECT diskSize, rowCount
+		FROM table_storage()
+		WHERE tableName = 'events'
+	`, r.table)).Scan(&stats.TotalSize, &stats.RowCount)
+	if err != nil {
+		return &StorageStats{}
+	}
+
+	return &stats
+}
+
+func (r *QuestDBRepo) Cleanup(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, tableSQL("TRUNCATE TABLE events", r.table))
+	return err
+}
+
+func (r *QuestDBRepo) Close() error {
+	r.http.CloseIdleConnections()
+	return r.db.Close()
+}
+
+// ServerVersion returns the build information of the server; its
+// server_version is that of the PostgreSQL wire protocol.
+func (r *QuestDBRepo) ServerVersion(ctx context.Context) (string, error) {
+	return querySQLVersion(ctx, r.db, "SELECT build()")
+}
+
+// VerifySchema checks that the events table exists with the columns of an
+// event.
+func (r *QuestDBRepo) VerifySchema(ctx context.Context) error {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, "SELECT count() > 0 FROM tables() WHERE table_name = $1", r.table).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return checkColumns(r.table, nil)
+	}
+
+	// table_columns takes a literal; the name is a plain identifier.
+	return verifySQLSchema(ctx, r.db, r.table, nil, `SELECT "column" FROM table_columns('`+r.table+`')`)
+}
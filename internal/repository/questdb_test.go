@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteILPLine(t *testing.T) {
+	event := generator.Event{
+		ID:        "evt_1",
+		UserID:    42,
+		EventType: "page view",
+		Payload:   `{"page": "/home"}`,
+		CreatedAt: time.Unix(0, 1700000000123456789),
+	}
+
+	var buf bytes.Buffer
+
+	writeILPLine(&buf, "events", &event)
+
+	assert.Equal(t,
+		`events,event_type=page\ view event_id="evt_1",user_id=42i,payload="{\"page\": \"/home\"}" 1700000000123456789`+"\n",
+		buf.String(),
+	)
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+const (
+	redisKeyPrefix  = "events:"
+	redisHourLayout = "2006010215"
+	redisScanCount  = 1000
+	redisRangeCount = 10000
+)
+
+// RedisRepo appends events to Redis Streams, one stream per UTC hour. Stream
+// entry IDs are assigned by the server, so the event time is stored as a field
+// and the stats query aggregates entries client-side.
+type RedisRepo struct {
+	client *redis.Client
+}
+
+func NewRedisRepo(ctx context.Context, cfg *config.RedisConfig) (*RedisRepo, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+		PoolSize: cfg.PoolSize,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return &RedisRepo{client: client}, nil
+}
+
+// redisStreamKey returns the stream holding events created in t's UTC hour.
+func redisStreamKey(t time.Time) string {
+	return redisKeyPrefix + t.UTC().Format(redisHourLayout)
+}
+
+// InitSchema drops any existing event streams; streams are created on first XADD.
+func (r *RedisRepo) InitSchema(ctx context.Context) error {
+	return r.deleteStreams(ctx)
+}
+
+func (r *RedisRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	pipe := r.client.Pipeline()
+
+	for _, event := range events {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: redisStreamKey(event.CreatedAt),
+			Values: []any{
+				"event_id", event.ID,
+				"user_id", event.UserID,
+				"event_type", event.EventType,
+				"payload", event.Payload,
+				"created_at", event.CreatedAt.UnixNano(),
+			},
+		})
+	}
+
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+func (r *RedisRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	agg := newStatsAggregator(start, end)
+	add := func(msg redis.XMessage) { addRedisMessage(agg, msg) }
+
+	for hour := start.UTC().Truncate(time.Hour); !hour.After(end); hour = hour.Add(time.Hour) {
+		if err := r.scanStream(ctx, redisStreamKey(hour), add); err != nil {
+			return nil, err
+		}
+	}
+
+	return agg.result(), nil
+}
+
+// scanStream walks a stream in pages of redisRangeCount entries.
+func (r *RedisRepo) scanStream(ctx context.Context, key string, fn func(redis.XMessage)) error {
+	from := "-"
+
+	for {
+		msgs, err := r.client.XRangeN(ctx, key, from, "+", redisRangeCount).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range msgs {
+			fn(msg)
+		}
+
+		if len(msgs) < redisRangeCount {
+			return nil
+		}
+
+		from = "(" + msgs[len(msgs)-1].ID
+	}
+}
+
+// addRedisMessage feeds one stream entry into the aggregator.
+func addRedisMessage(agg *statsAggregator, msg redis.XMessage) {
+	createdAt, err := strconv.ParseInt(fmt.Sprint(msg.Values["created_at"]), 10, 64)
+	if err != nil {
+		return
+	}
+
+	userID, err := strconv.ParseInt(fmt.Sprint(msg.Values["user_id"]), 10, 64)
+	if err != nil {
+		return
+	}
+
+	agg.add(createdAt, userID, fmt.Sprint(msg.Values["event_type"]))
+}
+
+// GetStorageStats reports used_memory from INFO memory and the total stream length.
+func (r *RedisRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	var stats StorageStats
+
+	if info, err := r.client.Info(ctx, "memory").Result(); err == nil {
+		stats.TotalSize = parseRedisInfoInt(info, "used_memory")
+	}
+
+	keys, err := r.streamKeys(ctx)
+	if err != nil {
+		return &stats
+	}
+
+	pipe := r.client.Pipeline()
+	lens := make([]*redis.IntCmd, len(keys))
+
+	for i, key := range keys {
+		lens[i] = pipe.XLen(ctx, key)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return &stats
+	}
+
+	for _, l := range lens {
+		stats.RowCount += l.Val()
+	}
+
+	return &stats
+}
+
+// parseRedisInfoInt returns an integer field from INFO output, or 0 if absent.
+func parseRedisInfoInt(info, field string) int64 {
+	for _, line := range strings.Split(info, "\n") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(line), field+":")
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0
+		}
+
+		return n
+	}
+
+	return 0
+}
+
+func (r *RedisRepo) streamKeys(ctx context.Context) ([]string, error) {
+	var keys []string
+
+	iter := r.client.Scan(ctx, 0, redisKeyPrefix+"*", redisScanCount).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+
+	return keys, iter.Err()
+}
+
+func (r *RedisRepo) deleteStreams(ctx context.Context) error {
+	keys, err := r.streamKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	for len(keys) > 0 {
+		n := min(len(keys), redisScanCount)
+		if err := r.client.Unlink(ctx, keys[:n]...).Err(); err != nil {
+			return err
+		}
+
+		keys = keys[n:]
+	}
+
+	return nil
+}
+
+func (r *RedisRepo) Cleanup(ctx context.Context) error {
+	return r.deleteStreams(ctx)
+}
+
+func (r *RedisRepo) Close() error {
+	return r.client.Close()
+}
+
+// ServerVersion returns the redis_version of INFO server.
+func (r *RedisRepo) ServerVersion(ctx context.Context) (string, error) {
+	info, err := r.client.InfoMap(ctx, "server").Result()
+	if err != nil {
+		return "", err
+	}
+
+	return info["Server"]["redis_version"], nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRedisInfoInt(t *testing.T) {
+	info := "# Memory\r\nused_memory:1048576\r\nused_memory_human:1.00M\r\nused_memory_rss:2097152\r\n"
+
+	assert.Equal(t, int64(1048576), parseRedisInfoInt(info, "used_memory"))
+	assert.Equal(t, int64(2097152), parseRedisInfoInt(info, "used_memory_rss"))
+	assert.Equal(t, int64(0), parseRedisInfoInt(info, "maxmemory"))
+}
+
+func TestAddRedisMessage(t *testing.T) {
+	hour := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	agg := newStatsAggregator(hour, hour.Add(time.Hour))
+
+	addRedisMessage(agg, redis.XMessage{Values: map[string]any{
+		"user_id":    "7",
+		"event_type": "click",
+		"created_at": fmt.Sprint(hour.Add(time.Minute).UnixNano()),
+	}})
+	addRedisMessage(agg, redis.XMessage{Values: map[string]any{"user_id": "x"}})
+
+	stats := agg.result()
+	require.Len(t, stats, 1)
+	assert.Equal(t, "click", stats[0].EventType)
+	assert.Equal(t, int64(1), stats[0].UniqueUsers)
+}
+
+func TestRedisStreamKey(t *testing.T) {
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.FixedZone("X", 3600))
+	assert.Equal(t, "events:2024010214", redisStreamKey(at))
+}
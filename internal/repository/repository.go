@@ -16,6 +16,10 @@ type StorageStats struct {
 	IndexSize      int64   `json:"index_size"`
 	CompressionPct float64 `json:"compression_pct"`
 	RowCount       int64   `json:"row_count"`
+
+	// Details holds engine-specific metrics such as tablet counts. Keys
+	// ending in "_bytes" are rendered as sizes.
+	Details map[string]int64 `json:"details,omitempty"`
 }
 
 // TotalSizeGB returns total size in gigabytes.
@@ -27,3 +31,28 @@ func (s *StorageStats) TotalSizeGB() float64 {
 func (s *StorageStats) IndexSizeGB() float64 {
 	return float64(s.IndexSize) / (1024 * 1024 * 1024)
 }
+
+// DeleteStats reports the outcome of a retention delete.
+type DeleteStats struct {
+	Rows   int64  `json:"rows"`
+	Method string `json:"method"`
+}
+
+// UserCount is the event count of one user.
+type UserCount struct {
+	UserID int64 `json:"user_id"`
+	Count  int64 `json:"count"`
+}
+
+// UserGroupStats summarizes a per-user GROUP BY: the number of distinct
+// users and the largest per-user event count.
+type UserGroupStats struct {
+	Groups   int64 `json:"groups"`
+	MaxCount int64 `json:"max_count"`
+}
+
+// CountryCount is the event count of the users of one country.
+type CountryCount struct {
+	Country string `json:"country"`
+	Count   int64  `json:"count"`
+}
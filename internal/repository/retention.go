@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Retention delete methods reported in DeleteStats. Backends that drop whole
+// partitions fall back to row deletes for the partition holding the cutoff.
+const (
+	retentionDropPartition = "drop_partition"
+	retentionDelete        = "delete"
+	retentionDeleteRange   = "delete_range"
+)
+
+// retentionBatchSize bounds the rows removed by one DELETE statement, keeping
+// transactions and lock hold times short like a production retention job.
+const retentionBatchSize = 10000
+
+// deleteInBatches calls deleteBatch until it removes fewer than
+// retentionBatchSize rows and returns the total removed.
+func deleteInBatches(ctx context.Context, deleteBatch func(context.Context) (int64, error)) (int64, error) {
+	var total int64
+
+	for {
+		n, err := deleteBatch(ctx)
+		if err != nil {
+			return total, err
+		}
+
+		total += n
+
+		if n < retentionBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// partitionDeleteMethod names the method of a partition drop followed by row
+// deletes in the partition holding the cutoff.
+func partitionDeleteMethod(droppedPartitions bool, rowsDeleted int64) string {
+	switch {
+	case droppedPartitions && rowsDeleted > 0:
+		return retentionDropPartition + "+" + retentionDelete
+	case droppedPartitions:
+		return retentionDropPartition
+	default:
+		return retentionDelete
+	}
+}
+
+// monthPartitionEnd returns the exclusive upper bound of a monthly partition
+// named by its YYYYMM key.
+func monthPartitionEnd(key string) (time.Time, error) {
+	start, err := time.Parse("200601", key)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse partition key %q: %w", key, err)
+	}
+
+	return start.AddDate(0, 1, 0), nil
+}
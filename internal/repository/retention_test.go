@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteInBatches(t *testing.T) {
+	remaining := int64(2*retentionBatchSize + 5)
+
+	var calls int
+
+	total, err := deleteInBatches(context.Background(), func(context.Context) (int64, error) {
+		calls++
+		n := min(remaining, retentionBatchSize)
+		remaining -= n
+
+		return n, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2*retentionBatchSize+5), total)
+	assert.Equal(t, 3, calls)
+}
+
+func TestPartitionDeleteMethod(t *testing.T) {
+	assert.Equal(t, "drop_partition+delete", partitionDeleteMethod(true, 10))
+	assert.Equal(t, "drop_partition", partitionDeleteMethod(true, 0))
+	assert.Equal(t, "delete", partitionDeleteMethod(false, 10))
+}
+
+func TestMonthPartitionEnd(t *testing.T) {
+	end, err := monthPartitionEnd("202512")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), end)
+
+	_, err = monthPartitionEnd("default")
+	assert.Error(t, err)
+}
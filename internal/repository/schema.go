@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Errors of the checks of an existing schema.
+var (
+	ErrTableMissing   = errors.New("table does not exist")
+	ErrColumnsMissing = errors.New("table lacks columns")
+)
+
+// eventColumns are the columns of an event, which every events table has.
+var eventColumns = []string{"event_id", "user_id", "event_type", "payload", "created_at"}
+
+// checkColumns checks that the columns of table include those of an event
+// and extra, taking no columns at all for a missing table.
+func checkColumns(table string, columns []string, extra ...string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("%w: %s", ErrTableMissing, table)
+	}
+
+	var missing []string
+
+	for _, want := range append(slices.Clone(eventColumns), extra...) {
+		if !slices.ContainsFunc(columns, func(c string) bool { return strings.EqualFold(c, want) }) {
+			missing = append(missing, want)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s has no %s", ErrColumnsMissing, table, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// verifySQLSchema runs a query returning the column names of table, one per
+// row, and checks them for those of an event and extra.
+func verifySQLSchema(ctx context.Context, db *sql.DB, table string, extra []string, query string, args ...any) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var columns []string
+
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return err
+		}
+
+		columns = append(columns, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return checkColumns(table, columns, extra...)
+}
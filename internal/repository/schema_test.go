@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckColumns(t *testing.T) {
+	columns := []string{"id", "event_id", "user_id", "event_type", "payload", "CREATED_AT"}
+
+	assert.NoError(t, checkColumns("events", columns))
+	assert.ErrorIs(t, checkColumns("events", nil), ErrTableMissing)
+
+	err := checkColumns("events", columns, "date_bucket")
+	assert.ErrorIs(t, err, ErrColumnsMissing)
+	assert.ErrorContains(t, err, "events has no date_bucket")
+
+	err = checkColumns("events", columns[:3])
+	assert.ErrorContains(t, err, "no event_type, payload, created_at")
+}
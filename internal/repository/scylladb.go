@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// NewScyllaDBRepo connects to ScyllaDB using the same schema and queries as
+// the Cassandra repository. Its configuration defaults to token-aware
+// routing, so each statement goes straight to a replica owning its
+// partition.
+func NewScyllaDBRepo(_ context.Context, cfg config.CassandraConfig) (*CassandraRepo, error) {
+	cluster, err := newCassandraCluster(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return openCQLRepo(cluster, cfg)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Payload search queries shared by the SQL backends, in "?" and "$n" bind
+// styles. The pattern comes from likePattern.
+const (
+	payloadSearchQuery = `
+		SELECT COUNT(*) FROM events
+		WHERE created_at BETWEEN ? AND ? AND payload LIKE ?
+	`
+	payloadSearchQueryDollar = `
+		SELECT COUNT(*) FROM events
+		WHERE created_at BETWEEN $1 AND $2 AND payload::text LIKE $3
+	`
+)
+
+// likePattern returns a LIKE pattern matching token anywhere in the value.
+// Escape syntax differs between dialects, so tokens are plain words without
+// LIKE wildcards.
+func likePattern(token string) string {
+	return "%" + token + "%"
+}
+
+// querySQLPayloadSearch counts the events in a range whose payload contains
+// token.
+func querySQLPayloadSearch(ctx context.Context, db *sql.DB, query string, start, end any, token string) (int64, error) {
+	return querySQLCount(ctx, db, query, start, end, likePattern(token))
+}
@@ -0,0 +1,23 @@
+package repository
+
+// sessionsSQL builds the sessionization query of a SQL dialect. LAG finds the
+// previous event of the same user in the range, and a session starts at the
+// first event of a user or after an idle gap longer than the bound number of
+// seconds. elapsed is the dialect's expression for the seconds from prev to
+// created_at.
+func sessionsSQL(rangePredicate, elapsed, gapBind string) string {
+	return `SELECT COUNT(*) FROM (
+			SELECT created_at, LAG(created_at) OVER (PARTITION BY user_id ORDER BY created_at) AS prev
+			FROM events WHERE ` + rangePredicate + `
+		) s WHERE prev IS NULL OR ` + elapsed + ` > ` + gapBind
+}
+
+// Sessionization queries of the SQL dialects; all bind the range and then the
+// gap in seconds.
+var (
+	sessionsQueryDollar = sessionsSQL("created_at BETWEEN $1 AND $2", "EXTRACT(EPOCH FROM created_at - prev)", "$3")
+	sessionsQueryMySQL  = sessionsSQL("created_at BETWEEN ? AND ?", "TIMESTAMPDIFF(SECOND, prev, created_at)", "?")
+	sessionsQueryDuckDB = sessionsSQL("created_at BETWEEN ? AND ?", "date_diff('second', prev, created_at)", "?")
+	// SQLite stores created_at as unix nanoseconds.
+	sessionsQuerySQLite = sessionsSQL("created_at BETWEEN ? AND ?", "(created_at - prev) / 1000000000", "?")
+)
@@ -0,0 +1,424 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// SQLiteRepo stores events in an embedded SQLite database. Timestamps are
+// kept as unix nanoseconds so hourly buckets are plain integer arithmetic.
+type SQLiteRepo struct {
+	db       *sql.DB
+	cfg      *config.SQLiteConfig
+	indexes  string // secondary index set
+	deferred bool   // InitSchema leaves the secondary indexes to CreateIndexes
+	table    string // name of the events table
+}
+
+func NewSQLiteRepo(ctx context.Context, cfg *config.SQLiteConfig) (*SQLiteRepo, error) {
+	indexes, err := indexSet(cfg.Indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := tableName(cfg.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", cfg.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY churn
+	// between workers and keeps an in-memory database alive for the whole run.
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("failed to ping sqlite: %w", err)
+	}
+
+	return &SQLiteRepo{db: db, cfg: cfg, indexes: indexes, table: table}, nil
+}
+
+func (r *SQLiteRepo) InitSchema(ctx context.Context) error {
+	schema := `
+		DROP TABLE IF EXISTS events;
+
+		CREATE TABLE events (
+			id INTEGER PRIMARY KEY,
+			event_id TEXT NOT NULL,
+			user_id INTEGER NOT NULL,
+			event_type TEXT NOT NULL,
+			payload TEXT,
+			created_at INTEGER NOT NULL
+		);
+
+		CREATE UNIQUE INDEX idx_events_event_id ON events(event_id);
+	`
+
+	if !r.deferred {
+		schema += sqliteSecondaryIndexes(r.indexes)
+	}
+
+	_, err := r.db.ExecContext(ctx, tableSQL(schema, r.table))
+
+	return err
+}
+
+// sqliteSecondaryIndexes returns the index DDL of an index set. The unique
+// index backs INSERT OR IGNORE, so the schema always has it.
+func sqliteSecondaryIndexes(set string) string {
+	var indexes string
+
+	if set != config.IndexesNone {
+		indexes += "CREATE INDEX idx_events_created_at ON events(created_at);"
+	}
+
+	if set == config.IndexesFull {
+		indexes += `
+			CREATE INDEX idx_events_type_time ON events(event_type, created_at);
+			CREATE INDEX idx_events_user_id ON events(user_id);
+		`
+	}
+
+	return indexes
+}
+
+// DeferIndexes makes InitSchema create the table without its secondary
+// indexes, which CreateIndexes then builds on the loaded events.
+func (r *SQLiteRepo) DeferIndexes(deferred bool) {
+	r.deferred = deferred
+}
+
+// CreateIndexes builds the secondary indexes InitSchema deferred.
+func (r *SQLiteRepo) CreateIndexes(ctx context.Context) error {
+	indexes := sqliteSecondaryIndexes(r.indexes)
+	if indexes == "" {
+		return nil
+	}
+
+	_, err := r.db.ExecContext(ctx, tableSQL(indexes, r.table))
+
+	return err
+}
+
+const sqliteInsertEventQuery = `
+	INSERT OR IGNORE INTO events (event_id, user_id, event_type, payload, created_at)
+	VALUES (?, ?, ?, ?, ?)
+`
+
+func (r *SQLiteRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, tableSQL(sqliteInsertEventQuery, r.table))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = stmt.Close() }()
+
+	for _, event := range events {
+		_, err := stmt.ExecContext(ctx,
+			event.ID,
+			event.UserID,
+			event.EventType,
+			event.Payload,
+			event.CreatedAt.UnixNano(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *SQLiteRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	query := `
+		SELECT
+			(created_at / ?) * ? AS hour,
+			event_type,
+			COUNT(*) AS count,
+			COUNT(DISTINCT user_id) AS unique_users
+		FROM events
+		WHERE created_at BETWEEN ? AND ?
+		GROUP BY hour, event_type
+		ORDER BY hour DESC
+	`
+
+	hour := int64(time.Hour)
+
+	rows, err := r.db.QueryContext(ctx, tableSQL(query, r.table), hour, hour, start.UnixNano(), end.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var stats []EventStats
+
+	for rows.Next() {
+		var (
+			s      EventStats
+			hourNs int64
+		)
+
+		if err := rows.Scan(&hourNs, &s.EventType, &s.Count, &s.UniqueUsers); err != nil {
+			return nil, err
+		}
+
+		s.Hour = time.Unix(0, hourNs).UTC()
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetEventByID looks an event up through the event_id unique index.
+func (r *SQLiteRepo) GetEventByID(ctx context.Context, eventID string) (*generator.Event, error) {
+	return scanSQLiteEvent(r.db.QueryRowContext(ctx, tableSQL(eventByIDQuery, r.table), eventID))
+}
+
+// GetUserEvents reads the newest events of a user through idx_events_user_id.
+func (r *SQLiteRepo) GetUserEvents(ctx context.Context, userID int64, limit int) ([]generator.Event, error) {
+	return querySQLEvents(ctx, r.db, scanSQLiteEvent, tableSQL(userEventsQuery, r.table), userID, limit)
+}
+
+// GetTopUsers returns the n users with the most events in [start, end].
+func (r *SQLiteRepo) GetTopUsers(ctx context.Context, start, end time.Time, n int) ([]UserCount, error) {
+	return querySQLTopUsers(ctx, r.db, tableSQL(topUsersQuery, r.table), start.UnixNano(), end.UnixNano(), n)
+}
+
+// GetUserGroups groups the events in [start, end] by user_id.
+func (r *SQLiteRepo) GetUserGroups(ctx context.Context, start, end time.Time) (*UserGroupStats, error) {
+	return querySQLUserGroups(ctx, r.db, tableSQL(userGroupsQuery, r.table), start.UnixNano(), end.UnixNano())
+}
+
+// SearchPayload counts the events in [start, end] whose payload contains
+// token, scanning the payloads of the range.
+func (r *SQLiteRepo) SearchPayload(ctx context.Context, token string, start, end time.Time) (int64, error) {
+	return querySQLPayloadSearch(ctx, r.db, tableSQL(payloadSearchQuery, r.table), start.UnixNano(), end.UnixNano(), token)
+}
+
+// ScanPages pages through [start, end] by (created_at, event_id) keys, bound
+// as unix nanoseconds like the stored column.
+func (r *SQLiteRepo) ScanPages(ctx context.Context, start, end time.Time, pageSize int, page PageFunc) error {
+	return keysetPages(start, pageSize, page, func(after time.Time, afterID string) ([]generator.Event, error) {
+		return querySQLEvents(ctx, r.db, scanSQLiteEvent, tableSQL(eventsPageQuery, r.table), after.UnixNano(), after.UnixNano(), afterID, end.UnixNano(), pageSize)
+	})
+}
+
+// ExportEvents streams every event in [start, end], read through the
+// created_at index.
+func (r *SQLiteRepo) ExportEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return exportSQLEvents(ctx, r.db, scanSQLiteEvent, tableSQL(exportQuery, r.table), start.UnixNano(), end.UnixNano())
+}
+
+// CountSessions counts the sessions in [start, end] with LAG, comparing the
+// nanosecond timestamps in whole seconds.
+func (r *SQLiteRepo) CountSessions(ctx context.Context, start, end time.Time, gap time.Duration) (int64, error) {
+	return querySQLCount(ctx, r.db, tableSQL(sessionsQuerySQLite, r.table), start.UnixNano(), end.UnixNano(), int64(gap/time.Second))
+}
+
+// CountEvents counts the events in [start, end].
+func (r *SQLiteRepo) CountEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return querySQLCount(ctx, r.db, tableSQL(countQuery, r.table), start.UnixNano(), end.UnixNano())
+}
+
+// RunStatement runs a custom query scenario statement. The window is bound
+// as unix nanoseconds, the type of created_at.
+func (r *SQLiteRepo) RunStatement(ctx context.Context, statement string, start, end time.Time) (int64, error) {
+	return runSQLStatement(ctx, r.db, tableSQL(statement, r.table), start.UnixNano(), end.UnixNano())
+}
+
+// EventExists reports whether an event with the ID is stored.
+func (r *SQLiteRepo) EventExists(ctx context.Context, eventID string) (bool, error) {
+	n, err := querySQLCount(ctx, r.db, tableSQL(existsQuery, r.table), eventID)
+	return n > 0, err
+}
+
+// InitUsersSchema recreates the users dimension table, keyed by the rowid.
+func (r *SQLiteRepo) InitUsersSchema(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		DROP TABLE IF EXISTS users;
+
+		CREATE TABLE users (
+			user_id INTEGER PRIMARY KEY,
+			country TEXT NOT NULL,
+			plan TEXT NOT NULL
+		);
+	`)
+
+	return err
+}
+
+func (r *SQLiteRepo) InsertUsers(ctx context.Context, users []generator.User) error {
+	return insertSQLUsers(ctx, r.db, users, false)
+}
+
+// GetCountryBreakdown joins the events in [start, end] to users and counts
+// them per country.
+func (r *SQLiteRepo) GetCountryBreakdown(ctx context.Context, start, end time.Time) ([]CountryCount, error) {
+	return querySQLCountryBreakdown(ctx, r.db, tableSQL(countryBreakdownQuery, r.table), start.UnixNano(), end.UnixNano())
+}
+
+// InitTransactionSchema recreates the per-user counter table of the
+// transactional workload.
+func (r *SQLiteRepo) InitTransactionSchema(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		DROP TABLE IF EXISTS user_counters;
+
+		CREATE TABLE user_counters (
+			user_id INTEGER PRIMARY KEY,
+			events INTEGER NOT NULL
+		);
+	`)
+
+	return err
+}
+
+// InsertEventTx inserts the event and upserts its user's counter row in one
+// transaction. The single connection serializes the workers' transactions.
+func (r *SQLiteRepo) InsertEventTx(ctx context.Context, event generator.Event) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, tableSQL(sqliteInsertEventQuery, r.table),
+		event.ID, event.UserID, event.EventType, event.Payload, event.CreatedAt.UnixNano())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_counters (user_id, events) VALUES (?, 1)
+		ON CONFLICT (user_id) DO UPDATE SET events = events + 1
+	`, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// scanSQLiteEvent is scanSQLEvent for created_at stored as unix nanoseconds.
+func scanSQLiteEvent(row rowScanner) (*generator.Event, error) {
+	var (
+		e         generator.Event
+		createdAt int64
+	)
+
+	err := row.Scan(&e.ID, &e.UserID, &e.EventType, &e.Payload, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEventNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	e.CreatedAt = time.Unix(0, createdAt).UTC()
+
+	return &e, nil
+}
+
+// GetStorageStats reports the on-disk size of the database file and its WAL,
+// or the allocated page size for an in-memory database.
+func (r *SQLiteRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	var stats StorageStats
+
+	if r.cfg.InMemory() {
+		_ = r.db.QueryRowContext(ctx,
+			`SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()`,
+		).Scan(&stats.TotalSize)
+	} else {
+		stats.TotalSize = fileSize(r.cfg.Path) + fileSize(r.cfg.Path+"-wal")
+	}
+
+	// dbstat is optional in SQLite builds; leave the index size at zero without it.
+	_ = r.db.QueryRowContext(ctx,
+		tableSQL(`SELECT COALESCE(SUM(pgsize), 0) FROM dbstat WHERE name LIKE 'idx_events_%'`, r.table),
+	).Scan(&stats.IndexSize)
+
+	_ = r.db.QueryRowContext(ctx, tableSQL(`SELECT COUNT(*) FROM events`, r.table)).Scan(&stats.RowCount)
+
+	return &stats
+}
+
+// DeleteOlderThan deletes older rows in batches. Freed pages stay in the
+// database file for reuse, so the file does not shrink without a VACUUM.
+func (r *SQLiteRepo) DeleteOlderThan(ctx context.Context, cutoff time.Time) (*DeleteStats, error) {
+	deleted, err := deleteInBatches(ctx, func(ctx context.Context) (int64, error) {
+		res, err := r.db.ExecContext(ctx, tableSQL(`
+			DELETE FROM events
+			WHERE id IN (SELECT id FROM events WHERE created_at < ? LIMIT ?)
+		`, r.table), cutoff.UnixNano(), retentionBatchSize)
+		if err != nil {
+			return 0, err
+		}
+
+		return res.RowsAffected()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete events: %w", err)
+	}
+
+	return &DeleteStats{Rows: deleted, Method: retentionDelete}, nil
+}
+
+func (r *SQLiteRepo) Cleanup(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, tableSQL("DELETE FROM events", r.table))
+	return err
+}
+
+func (r *SQLiteRepo) Close() error {
+	return r.db.Close()
+}
+
+// fileSize returns the size of a file in bytes, or 0 if it does not exist.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+
+	return info.Size()
+}
+
+// ServerVersion returns the version of the SQLite library.
+func (r *SQLiteRepo) ServerVersion(ctx context.Context) (string, error) {
+	return querySQLVersion(ctx, r.db, "SELECT sqlite_version()")
+}
+
+// CheckPermissions checks that the database file can be written to.
+func (r *SQLiteRepo) CheckPermissions(ctx context.Context) error {
+	return checkSQLPermissions(ctx, r.db, "CREATE TABLE "+checkTable+" (id INTEGER)")
+}
+
+// VerifySchema checks that the events table exists with the columns of an
+// event.
+func (r *SQLiteRepo) VerifySchema(ctx context.Context) error {
+	return verifySQLSchema(ctx, r.db, r.table, nil, "SELECT name FROM pragma_table_info(?)", r.table)
+}
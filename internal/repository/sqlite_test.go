@@ -0,0 +1,479 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSQLiteRepo(t *testing.T) *SQLiteRepo {
+	t.Helper()
+
+	ctx := context.Background()
+
+	repo, err := NewSQLiteRepo(ctx, &config.SQLiteConfig{Path: ":memory:", JournalMode: "WAL", Synchronous: "OFF"})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = repo.Close() })
+
+	require.NoError(t, repo.InitSchema(ctx))
+
+	return repo
+}
+
+func TestSQLiteRepo_InsertAndStats(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	now := time.Now().UTC().Truncate(time.Hour)
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", Payload: "{}", CreatedAt: now.Add(10 * time.Minute)},
+		{ID: "b", UserID: 2, EventType: "login", Payload: "{}", CreatedAt: now.Add(20 * time.Minute)},
+		{ID: "c", UserID: 1, EventType: "logout", Payload: "{}", CreatedAt: now.Add(-50 * time.Minute)},
+	}
+
+	require.NoError(t, repo.InsertBatch(ctx, events))
+	// Duplicate event IDs are ignored.
+	require.NoError(t, repo.InsertBatch(ctx, events[:1]))
+
+	stats, err := repo.GetEventStats(ctx, now.Add(-2*time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, now, stats[0].Hour)
+	assert.Equal(t, "login", stats[0].EventType)
+	assert.Equal(t, int64(2), stats[0].Count)
+	assert.Equal(t, int64(2), stats[0].UniqueUsers)
+	assert.Equal(t, now.Add(-time.Hour), stats[1].Hour)
+
+	storage := repo.GetStorageStats(ctx)
+	assert.Equal(t, int64(3), storage.RowCount)
+	assert.Positive(t, storage.TotalSize)
+
+	require.NoError(t, repo.Cleanup(ctx))
+	assert.Equal(t, int64(0), repo.GetStorageStats(ctx).RowCount)
+}
+
+func TestSQLiteRepo_DeleteOlderThan(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	now := time.Now().UTC()
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now.AddDate(0, 0, -10)},
+		{ID: "b", UserID: 2, EventType: "login", CreatedAt: now.AddDate(0, 0, -8)},
+		{ID: "c", UserID: 3, EventType: "login", CreatedAt: now.AddDate(0, 0, -1)},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	stats, err := repo.DeleteOlderThan(ctx, now.AddDate(0, 0, -7))
+	require.NoError(t, err)
+	assert.Equal(t, &DeleteStats{Rows: 2, Method: retentionDelete}, stats)
+	assert.Equal(t, int64(1), repo.GetStorageStats(ctx).RowCount)
+}
+
+func TestSQLiteRepo_GetEventByID(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	event := generator.Event{ID: "a", UserID: 7, EventType: "login", Payload: "{}", CreatedAt: time.Unix(1700000000, 5).UTC()}
+	require.NoError(t, repo.InsertBatch(ctx, []generator.Event{event}))
+
+	got, err := repo.GetEventByID(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, &event, got)
+
+	_, err = repo.GetEventByID(ctx, "missing")
+	assert.ErrorIs(t, err, ErrEventNotFound)
+}
+
+func TestSQLiteRepo_GetUserEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	now := time.Now().UTC()
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "b", UserID: 1, EventType: "search", CreatedAt: now.Add(-1 * time.Hour)},
+		{ID: "c", UserID: 1, EventType: "logout", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "d", UserID: 2, EventType: "login", CreatedAt: now},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	got, err := repo.GetUserEvents(ctx, 1, 2)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "b", got[0].ID)
+	assert.Equal(t, "c", got[1].ID)
+}
+
+func TestSQLiteRepo_GetTopUsers(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	now := time.Now().UTC()
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "b", UserID: 2, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "c", UserID: 2, EventType: "search", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "d", UserID: 3, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "e", UserID: 3, EventType: "login", CreatedAt: now.AddDate(0, 0, -10)},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	top, err := repo.GetTopUsers(ctx, now.AddDate(0, 0, -7), now, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []UserCount{{UserID: 2, Count: 2}, {UserID: 1, Count: 1}}, top)
+}
+
+func TestSQLiteRepo_GetUserGroups(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	now := time.Now().UTC()
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "b", UserID: 2, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "c", UserID: 2, EventType: "search", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "d", UserID: 3, EventType: "login", CreatedAt: now.AddDate(0, 0, -40)},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	groups, err := repo.GetUserGroups(ctx, now.AddDate(0, 0, -30), now)
+	require.NoError(t, err)
+	assert.Equal(t, &UserGroupStats{Groups: 2, MaxCount: 2}, groups)
+}
+
+func TestSQLiteRepo_SearchPayload(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	now := time.Now().UTC()
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "error", Payload: `{"message": "Connection timeout"}`, CreatedAt: now.Add(-time.Hour)},
+		{ID: "b", UserID: 2, EventType: "click", Payload: `{"button": "checkout"}`, CreatedAt: now.Add(-time.Hour)},
+		{ID: "c", UserID: 3, EventType: "error", Payload: `{"message": "Connection timeout"}`, CreatedAt: now.AddDate(0, 0, -2)},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	n, err := repo.SearchPayload(ctx, "timeout", now.Add(-24*time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestSQLiteRepo_ScanPages(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	now := time.Now().UTC()
+
+	// Four events share a timestamp, so the pages must break ties by event_id.
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "b", UserID: 1, EventType: "login", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "c", UserID: 1, EventType: "login", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "d", UserID: 1, EventType: "login", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "e", UserID: 2, EventType: "click", CreatedAt: now.Add(-time.Hour)},
+		{ID: "f", UserID: 3, EventType: "click", CreatedAt: now.AddDate(0, 0, -2)},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	var pages []int
+
+	err := repo.ScanPages(ctx, now.Add(-24*time.Hour), now, 2, func(rows int) { pages = append(pages, rows) })
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 2, 1}, pages)
+}
+
+func TestSQLiteRepo_ExportEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	now := time.Now().UTC()
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "b", UserID: 2, EventType: "click", CreatedAt: now.Add(-23 * time.Hour)},
+		{ID: "c", UserID: 3, EventType: "click", CreatedAt: now.AddDate(0, 0, -2)},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	n, err := repo.ExportEvents(ctx, now.Add(-24*time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+}
+
+func TestSQLiteRepo_CountSessions(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	now := time.Now().UTC()
+
+	// User 1 has two sessions split by a 40 minute gap, user 2 one session.
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "b", UserID: 1, EventType: "click", CreatedAt: now.Add(-3*time.Hour + 10*time.Minute)},
+		{ID: "c", UserID: 1, EventType: "click", CreatedAt: now.Add(-3*time.Hour + 50*time.Minute)},
+		{ID: "d", UserID: 2, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "e", UserID: 2, EventType: "click", CreatedAt: now.Add(-time.Hour + 29*time.Minute)},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	n, err := repo.CountSessions(ctx, now.Add(-24*time.Hour), now, 30*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
+func TestSQLiteRepo_CountAndExists(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	now := time.Now().UTC()
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "b", UserID: 2, EventType: "login", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "c", UserID: 3, EventType: "login", CreatedAt: now.AddDate(0, 0, -2)},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	n, err := repo.CountEvents(ctx, now.Add(-24*time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	ok, err := repo.EventExists(ctx, "c")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.EventExists(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestSQLiteRepo_GetCountryBreakdown(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	require.NoError(t, repo.InitUsersSchema(ctx))
+	require.NoError(t, repo.InsertUsers(ctx, []generator.User{
+		{ID: 1, Country: "US", Plan: "free"},
+		{ID: 2, Country: "DE", Plan: "pro"},
+		{ID: 3, Country: "US", Plan: "pro"},
+	}))
+
+	now := time.Now().UTC()
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "b", UserID: 3, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "c", UserID: 2, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "d", UserID: 2, EventType: "login", CreatedAt: now.AddDate(0, 0, -10)},
+		{ID: "e", UserID: 9, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	counts, err := repo.GetCountryBreakdown(ctx, now.AddDate(0, 0, -7), now)
+	require.NoError(t, err)
+	assert.Equal(t, []CountryCount{{Country: "US", Count: 2}, {Country: "DE", Count: 1}}, counts)
+}
+
+func TestSQLiteRepo_InsertEventTx(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	require.NoError(t, repo.InitTransactionSchema(ctx))
+
+	now := time.Now().UTC()
+	for _, e := range []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now},
+		{ID: "b", UserID: 1, EventType: "click", CreatedAt: now},
+		{ID: "c", UserID: 2, EventType: "login", CreatedAt: now},
+	} {
+		require.NoError(t, repo.InsertEventTx(ctx, e))
+	}
+
+	var events int64
+	require.NoError(t, repo.db.QueryRowContext(ctx, "SELECT events FROM user_counters WHERE user_id = 1").Scan(&events))
+	assert.Equal(t, int64(2), events)
+	assert.Equal(t, int64(3), repo.GetStorageStats(ctx).RowCount)
+}
+
+func TestSQLiteRepo_IndexSets(t *testing.T) {
+	ctx := context.Background()
+
+	for set, want := range map[string][]string{
+		config.IndexesNone:    {"idx_events_event_id"},
+		config.IndexesMinimal: {"idx_events_created_at", "idx_events_event_id"},
+		config.IndexesFull:    {"idx_events_created_at", "idx_events_event_id", "idx_events_type_time", "idx_events_user_id"},
+	} {
+		t.Run(set, func(t *testing.T) {
+			repo, err := NewSQLiteRepo(ctx, &config.SQLiteConfig{Path: ":memory:", JournalMode: "WAL", Synchronous: "OFF", Indexes: set})
+			require.NoError(t, err)
+
+			t.Cleanup(func() { _ = repo.Close() })
+
+			require.NoError(t, repo.InitSchema(ctx))
+
+			rows, err := repo.db.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = 'events' ORDER BY name")
+			require.NoError(t, err)
+
+			defer func() { _ = rows.Close() }()
+
+			var names []string
+
+			for rows.Next() {
+				var name string
+				require.NoError(t, rows.Scan(&name))
+
+				names = append(names, name)
+			}
+
+			require.NoError(t, rows.Err())
+			assert.Equal(t, want, names)
+		})
+	}
+}
+
+func TestSQLiteRepo_RunStatement(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	now := time.Now().UTC()
+	events := []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", CreatedAt: now.Add(-time.Hour)},
+		{ID: "b", UserID: 2, EventType: "click", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "c", UserID: 3, EventType: "click", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "d", UserID: 4, EventType: "click", CreatedAt: now.AddDate(0, 0, -2)},
+	}
+	require.NoError(t, repo.InsertBatch(ctx, events))
+
+	n, err := repo.RunStatement(ctx,
+		"SELECT event_type, COUNT(*) FROM events WHERE created_at BETWEEN ? AND ? GROUP BY event_type", now.Add(-24*time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n, "one row per event type in the window")
+
+	_, err = repo.RunStatement(ctx, "SELECT * FROM missing", now, now)
+	assert.Error(t, err)
+}
+
+func TestSQLiteRepo_CheckPermissions(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	require.NoError(t, repo.InsertBatch(ctx, []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", Payload: "{}", CreatedAt: time.Now().UTC()},
+	}))
+	require.NoError(t, repo.CheckPermissions(ctx))
+	require.NoError(t, repo.CheckPermissions(ctx), "the scratch table is dropped")
+
+	stats := repo.GetStorageStats(ctx)
+	assert.Equal(t, int64(1), stats.RowCount, "the events are left alone")
+
+	var tables int
+	require.NoError(t, repo.db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE name = ?", checkTable).Scan(&tables))
+	assert.Zero(t, tables)
+}
+
+func TestCheckSQLPermissionsDenied(t *testing.T) {
+	repo := newTestSQLiteRepo(t)
+
+	err := checkSQLPermissions(context.Background(), repo.db, "CREATE TABLE "+checkTable+" (id INTEGER CHECK (id > 1))")
+	assert.ErrorIs(t, err, ErrWriteDenied)
+
+	err = checkSQLPermissions(context.Background(), repo.db, "CREATE TABLE events (id INTEGER)")
+	assert.ErrorIs(t, err, ErrCreateDenied)
+}
+
+func TestSQLiteRepo_Table(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "shared.db")
+
+	open := func(table string) *SQLiteRepo {
+		repo, err := NewSQLiteRepo(ctx, &config.SQLiteConfig{Path: path, Table: table, JournalMode: "WAL", Synchronous: "OFF"})
+		require.NoError(t, err)
+
+		t.Cleanup(func() { _ = repo.Close() })
+
+		require.NoError(t, repo.InitSchema(ctx))
+
+		return repo
+	}
+
+	a, b := open(""), open("events_b")
+	now := time.Now().UTC()
+
+	require.NoError(t, a.InsertBatch(ctx, []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", Payload: "{}", CreatedAt: now},
+	}))
+	require.NoError(t, b.InsertBatch(ctx, []generator.Event{
+		{ID: "a", UserID: 1, EventType: "login", Payload: "{}", CreatedAt: now},
+		{ID: "b", UserID: 2, EventType: "login", Payload: "{}", CreatedAt: now},
+	}))
+
+	assert.Equal(t, int64(1), a.GetStorageStats(ctx).RowCount)
+	assert.Equal(t, int64(2), b.GetStorageStats(ctx).RowCount)
+
+	require.NoError(t, b.Cleanup(ctx))
+	assert.Equal(t, int64(1), a.GetStorageStats(ctx).RowCount, "the other instance's table is left alone")
+
+	_, err := NewSQLiteRepo(ctx, &config.SQLiteConfig{Path: path, Table: "events; DROP TABLE users"})
+	assert.Error(t, err)
+}
+
+func TestSQLiteRepo_VerifySchema(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	require.NoError(t, repo.VerifySchema(ctx))
+
+	_, err := repo.db.ExecContext(ctx, "ALTER TABLE events DROP COLUMN payload")
+	require.NoError(t, err)
+	assert.ErrorIs(t, repo.VerifySchema(ctx), ErrColumnsMissing)
+
+	_, err = repo.db.ExecContext(ctx, "DROP TABLE events")
+	require.NoError(t, err)
+	assert.ErrorIs(t, repo.VerifySchema(ctx), ErrTableMissing)
+}
+
+func TestSQLiteRepo_DeferIndexes(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteRepo(t)
+
+	indexNames := func() []string {
+		rows, err := repo.db.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = 'events' ORDER BY name")
+		require.NoError(t, err)
+
+		defer func() { _ = rows.Close() }()
+
+		var names []string
+
+		for rows.Next() {
+			var name string
+			require.NoError(t, rows.Scan(&name))
+
+			names = append(names, name)
+		}
+
+		require.NoError(t, rows.Err())
+
+		return names
+	}
+
+	repo.DeferIndexes(true)
+	require.NoError(t, repo.InitSchema(ctx))
+	assert.Equal(t, []string{"idx_events_event_id"}, indexNames(), "the unique index is never deferred")
+
+	for batch := range generator.New(100, 50).Generate() {
+		require.NoError(t, repo.InsertBatch(ctx, batch))
+	}
+
+	require.NoError(t, repo.CreateIndexes(ctx))
+	assert.Equal(t, []string{"idx_events_created_at", "idx_events_event_id", "idx_events_type_time", "idx_events_user_id"}, indexNames())
+
+	repo.DeferIndexes(false)
+	require.NoError(t, repo.InitSchema(ctx))
+	assert.Len(t, indexNames(), 4)
+}
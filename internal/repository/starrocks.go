@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql" // registers the "mysql" database/sql driver
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// StarRocksRepo ingests events through stream load and queries them over the
+// MySQL protocol, using the same columns as the ClickHouse schema.
+type StarRocksRepo struct {
+	db       *sql.DB
+	loader   *streamLoader
+	database string
+	table    string // name of the events table
+}
+
+func NewStarRocksRepo(ctx context.Context, cfg *config.StarRocksConfig) (*StarRocksRepo, error) {
+	table, err := tableName(cfg.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := openMySQLWire(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to starrocks: %w", err)
+	}
+
+	return &StarRocksRepo{
+		db:       db,
+		loader:   newStreamLoader(cfg.Host, cfg.HTTPPort, cfg.Database, table, cfg.User, cfg.Password),
+		database: cfg.Database,
+		table:    table,
+	}, nil
+}
+
+// openMySQLWire creates the configured database if needed and returns a pool
+// connected to it.
+func openMySQLWire(ctx context.Context, cfg *config.StarRocksConfig) (*sql.DB, error) {
+	if err := createMySQLWireDatabase(ctx, cfg); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("mysql", cfg.DSN())
+	if err != nil {
+		return nil, err
+	}
+
+	setPool(db, cfg.Pool)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// createMySQLWireDatabase creates the configured database if needed, over
+// a connection to none.
+func createMySQLWireDatabase(ctx context.Context, cfg *config.StarRocksConfig) error {
+	bootstrap := *cfg
+	bootstrap.Database = ""
+
+	db, err := sql.Open("mysql", bootstrap.DSN())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, "CREATE DATABASE IF NOT EXISTS `"+cfg.Database+"`")
+	_ = db.Close()
+
+	if err != nil {
+		return fmt.Errorf("failed to create database: %w", err)
+	}
+
+	return nil
+}
+
+func (r *StarRocksRepo) InitSchema(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, tableSQL("DROP TABLE IF EXISTS events FORCE", r.table)); err != nil {
+		return err
+	}
+
+	schema := `
+		CREATE TABLE events (
+			created_at DATETIME NOT NULL,
+			event_type VARCHAR(50) NOT NULL,
+			user_id BIGINT NOT NULL,
+			event_id VARCHAR(255) NOT NULL,
+			payload STRING
+		)
+		DUPLICATE KEY(created_at, event_type)
+		PARTITION BY date_trunc('month', created_at)
+		DISTRIBUTED BY HASH(user_id) BUCKETS 8
+		PROPERTIES ("replication_num" = "1")
+	`
+
+	_, err := r.db.ExecContext(ctx, tableSQL(schema, r.table))
+
+	return err
+}
+
+func (r *StarRocksRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	return r.loader.load(ctx, events)
+}
+
+func (r *StarRocksRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	return queryMySQLWireEventStats(ctx, r.db, r.table, "date_trunc('hour', created_at)", start, end)
+}
+
+// initMySQLWireUsers recreates the users table shared by StarRocks and Doris;
+// keyModel is the key clause of the engine's upsert table model.
+func initMySQLWireUsers(ctx context.Context, db *sql.DB, keyModel string) error {
+	if _, err := db.ExecContext(ctx, "DROP TABLE IF EXISTS users FORCE"); err != nil {
+		return err
+	}
+
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE users (
+			user_id BIGINT NOT NULL,
+			country VARCHAR(2) NOT NULL,
+			plan VARCHAR(16) NOT NULL
+		)
+		`+keyModel+`(user_id)
+		DISTRIBUTED BY HASH(user_id) BUCKETS 8
+		PROPERTIES ("replication_num" = "1")
+	`)
+
+	return err
+}
+
+// queryMySQLWireEventStats runs the hourly stats query shared by StarRocks and
+// Doris against table; hourExpr truncates created_at to the hour in the
+// engine's dialect.
+func queryMySQLWireEventStats(ctx context.Context, db *sql.DB, table, hourExpr string, start, end time.Time) ([]EventStats, error) {
+	query := `
+		SELECT
+			` + hourExpr + ` AS hour,
+			event_type,
+			COUNT(*) AS count,
+			COUNT(DISTINCT user_id) AS unique_users
+		FROM events
+		WHERE created_at BETWEEN ? AND ?
+		GROUP BY hour, event_type
+		ORDER BY hour DESC
+	`
+
+	rows, err := db.QueryContext(ctx, tableSQL(query, table), start.UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var stats []EventStats
+
+	for rows.Next() {
+		var s EventStats
+		if err := rows.Scan(&s.Hour, &s.EventType, &s.Count, &s.UniqueUsers); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetEventByID scans for the event; event_id is not part of the sort key.
+func (r *StarRocksRepo) GetEventByID(ctx context.Context, eventID string) (*generator.Event, error) {
+	return scanSQLEvent(r.db.QueryRowContext(ctx, tableSQL(eventByIDQuery, r.table), eventID))
+}
+
+// GetUserEvents reads the user's events from the single bucket user_id hashes
+// to in each partition.
+func (r *StarRocksRepo) GetUserEvents(ctx context.Context, userID int64, limit int) ([]generator.Event, error) {
+	return querySQLEvents(ctx, r.db, scanSQLEvent, tableSQL(userEventsQuery, r.table), userID, limit)
+}
+
+// GetTopUsers returns the n users with the most events in [start, end].
+func (r *StarRocksRepo) GetTopUsers(ctx context.Context, start, end time.Time, n int) ([]UserCount, error) {
+	return querySQLTopUsers(ctx, r.db, tableSQL(topUsersQuery, r.table), start.UTC(), end.UTC(), n)
+}
+
+// GetUserGroups groups the events in [start, end] by user_id.
+func (r *StarRocksRepo) GetUserGroups(ctx context.Context, start, end time.Time) (*UserGroupStats, error) {
+	return querySQLUserGroups(ctx, r.db, tableSQL(userGroupsQuery, r.table), start.UTC(), end.UTC())
+}
+
+// SearchPayload counts the events in [start, end] whose payload contains
+// token, scanning the payload column.
+func (r *StarRocksRepo) SearchPayload(ctx context.Context, token string, start, end time.Time) (int64, error) {
+	return querySQLPayloadSearch(ctx, r.db, tableSQL(payloadSearchQuery, r.table), start.UTC(), end.UTC(), token)
+}
+
+// ScanPages pages through [start, end] by (created_at, event_id) keys, with
+// the times bound in UTC like the other StarRocks queries.
+func (r *StarRocksRepo) ScanPages(ctx context.Context, start, end time.Time, pageSize int, page PageFunc) error {
+	return keysetPages(start, pageSize, page, func(after time.Time, afterID string) ([]generator.Event, error) {
+		return querySQLEvents(ctx, r.db, scanSQLEvent, tableSQL(eventsPageQuery, r.table), after.UTC(), after.UTC(), afterID, end.UTC(), pageSize)
+	})
+}
+
+// ExportEvents streams every event in [start, end] over the MySQL protocol,
+// which sends the result set row by row.
+func (r *StarRocksRepo) ExportEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return exportSQLEvents(ctx, r.db, scanSQLEvent, tableSQL(exportQuery, r.table), start.UTC(), end.UTC())
+}
+
+// CountSessions counts the sessions in [start, end] with LAG, shuffling the
+// rows by user_id between the backends to evaluate the window.
+func (r *StarRocksRepo) CountSessions(ctx context.Context, start, end time.Time, gap time.Duration) (int64, error) {
+	return querySQLCount(ctx, r.db, tableSQL(sessionsQueryMySQL, r.table), start.UTC(), end.UTC(), int64(gap/time.Second))
+}
+
+// CountEvents counts the events in [start, end], pruned to the partitions
+// the range overlaps.
+func (r *StarRocksRepo) CountEvents(ctx context.Context, start, end time.Time) (int64, error) {
+	return querySQLCount(ctx, r.db, tableSQL(countQuery, r.table), start.UTC(), end.UTC())
+}
+
+// RunStatement runs a custom query scenario statement with the window bound
+// in UTC, like the built-in queries.
+func (r *StarRocksRepo) RunStatement(ctx context.Context, statement string, start, end time.Time) (int64, error) {
+	return runSQLStatement(ctx, r.db, tableSQL(statement, r.table), start.UTC(), end.UTC())
+}
+
+// EventExists reports whether an event with the ID is stored; event_id is not
+// a key column, so the check scans until the first match.
+func (r *StarRocksRepo) EventExists(ctx context.Context, eventID string) (bool, error) {
+	n, err := querySQLCount(ctx, r.db, tableSQL(existsQuery, r.table), eventID)
+	return n > 0, err
+}
+
+// InitUsersSchema recreates the users dimension table with a PRIMARY KEY model,
+// bucketed by user_id like events.
+func (r *StarRocksRepo) InitUsersSchema(ctx context.Context) error {
+	return initMySQLWireUsers(ctx, r.db, "PRIMARY KEY")
+}
+
+// InsertUsers writes the users with multi-row INSERTs, one load per chunk.
+func (r *StarRocksRepo) InsertUsers(ctx context.Context, users []generator.User) error {
+	return insertSQLUsers(ctx, r.db, users, false)
+}
+
+// GetCountryBreakdown joins the events in [start, end] to users and counts
+// them per country.
+func (r *StarRocksRepo) GetCountryBreakdown(ctx context.Context, start, end time.Time) ([]CountryCount, error) {
+	return querySQLCountryBreakdown(ctx, r.db, tableSQL(countryBreakdownQuery, r.table), start.UTC(), end.UTC())
+}
+
+// GetStorageStats sums the data size of every tablet of the events table as
+// reported by the backends.
+func (r *StarRocksRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	stats := &StorageStats{Details: map[string]int64{}}
+
+	var tablets int64
+
+	err := r.db.QueryRowContext(ctx, tableSQL(`
+		SELECT COALESCE(SUM(t.DATA_SIZE), 0), COUNT(*)
+		FROM information_schema.be_tablets t
+		JOIN information_schema.tables_config c ON t.TABLE_ID = c.TABLE_ID
+		WHERE c.TABLE_SCHEMA = ? AND c.TABLE_NAME = 'events'
+	`, r.table), r.database).Scan(&stats.TotalSize, &tablets)
+	if err == nil {
+		stats.Details["tablets"] = tablets
+	}
+
+	_ = r.db.QueryRowContext(ctx, tableSQL(`SELECT COUNT(*) FROM events`, r.table)).Scan(&stats.RowCount)
+
+	return stats
+}
+
+func (r *StarRocksRepo) Cleanup(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, tableSQL("TRUNCATE TABLE events", r.table))
+	return err
+}
+
+func (r *StarRocksRepo) Close() error {
+	r.loader.close()
+	return r.db.Close()
+}
+
+// ServerVersion returns version_comment, e.g. "StarRocks version 3.2.3";
+// VERSION() is the MySQL version the server poses as.
+func (r *StarRocksRepo) ServerVersion(ctx context.Context) (string, error) {
+	return querySQLVersion(ctx, r.db, "SELECT @@version_comment")
+}
+
+// VerifySchema checks that the events table exists in the database with the
+// columns of an event.
+func (r *StarRocksRepo) VerifySchema(ctx context.Context) error {
+	return verifySQLSchema(ctx, r.db, r.table, nil, `
+		SELECT COLUMN_NAME FROM information_schema.columns
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+	`, r.table)
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// rowCursor is a row cursor of database/sql or the ClickHouse driver.
+type rowCursor interface {
+	Next() bool
+	Err() error
+}
+
+// countRows reads every row of rows without decoding them and returns the
+// number read.
+func countRows(rows rowCursor) (int64, error) {
+	var n int64
+
+	for rows.Next() {
+		n++
+	}
+
+	return n, rows.Err()
+}
+
+// runSQLStatement runs a user-defined statement through database/sql and
+// returns the number of rows it produced.
+func runSQLStatement(ctx context.Context, db *sql.DB, statement string, args ...any) (int64, error) {
+	rows, err := db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	return countRows(rows)
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// streamLoadTimeLayout is the DATETIME literal format accepted by stream load.
+const streamLoadTimeLayout = "2006-01-02 15:04:05.000000"
+
+// streamLoader sends batches to the HTTP stream load API shared by StarRocks
+// and Apache Doris. The frontend answers with a redirect to a backend node,
+// which must receive the credentials and the body again.
+type streamLoader struct {
+	http     *http.Client
+	url      string
+	user     string
+	password string
+}
+
+func newStreamLoader(host, port, database, table, user, password string) *streamLoader {
+	l := &streamLoader{
+		url:      fmt.Sprintf("http://%s:%s/api/%s/%s/_stream_load", host, port, database, table),
+		user:     user,
+		password: password,
+	}
+
+	l.http = &http.Client{
+		Timeout: 5 * time.Minute,
+		CheckRedirect: func(req *http.Request, _ []*http.Request) error {
+			// net/http drops Authorization when redirecting to another host.
+			req.SetBasicAuth(l.user, l.password)
+			return nil
+		},
+	}
+
+	return l
+}
+
+type streamLoadRow struct {
+	EventID   string `json:"event_id"`
+	UserID    int64  `json:"user_id"`
+	EventType string `json:"event_type"`
+	Payload   string `json:"payload"`
+	CreatedAt string `json:"created_at"`
+}
+
+type streamLoadResponse struct {
+	Status   string `json:"Status"`
+	Message  string `json:"Message"`
+	ErrorURL string `json:"ErrorURL"`
+}
+
+// encodeStreamLoadBody encodes events as the JSON array expected with
+// strip_outer_array=true.
+func encodeStreamLoadBody(events []generator.Event) ([]byte, error) {
+	rows := make([]streamLoadRow, len(events))
+
+	for i, event := range events {
+		rows[i] = streamLoadRow{
+			EventID:   event.ID,
+			UserID:    event.UserID,
+			EventType: event.EventType,
+			Payload:   event.Payload,
+			CreatedAt: event.CreatedAt.UTC().Format(streamLoadTimeLayout),
+		}
+	}
+
+	return json.Marshal(rows)
+}
+
+func (l *streamLoader) load(ctx context.Context, events []generator.Event) error {
+	body, err := encodeStreamLoadBody(events)
+	if err != nil {
+		return err
+	}
+
+	req, err := l.newRequest(ctx, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := l.http.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	return checkStreamLoadResponse(resp)
+}
+
+// newRequest returns the stream load request of a JSON array of rows.
+func (l *streamLoader) newRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	// A bytes.Reader body lets net/http replay it on the 307 redirect.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, l.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(l.user, l.password)
+	req.Header.Set("Expect", "100-continue")
+	req.Header.Set("format", "json")
+	req.Header.Set("strip_outer_array", "true")
+
+	return req, nil
+}
+
+func checkStreamLoadResponse(resp *http.Response) error {
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return err
+	}
+
+	var result streamLoadResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return fmt.Errorf("stream load failed: %s: %s", resp.Status, bytes.TrimSpace(raw))
+	}
+
+	// "Publish Timeout" means the data is committed but not yet visible.
+	if result.Status != "Success" && result.Status != "Publish Timeout" {
+		return fmt.Errorf("stream load failed: %s: %s %s", result.Status, result.Message, result.ErrorURL)
+	}
+
+	return nil
+}
+
+func (l *streamLoader) close() {
+	l.http.CloseIdleConnections()
+}
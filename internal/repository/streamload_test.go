@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeStreamLoadBody(t *testing.T) {
+	events := []generator.Event{{
+		ID:        "evt_1",
+		UserID:    42,
+		EventType: "click",
+		Payload:   `{"page": "/home"}`,
+		CreatedAt: time.Date(2024, 1, 2, 15, 4, 5, 123456000, time.UTC),
+	}}
+
+	body, err := encodeStreamLoadBody(events)
+	require.NoError(t, err)
+
+	assert.JSONEq(t,
+		`[{"event_id":"evt_1","user_id":42,"event_type":"click","payload":"{\"page\": \"/home\"}","created_at":"2024-01-02 15:04:05.123456"}]`,
+		string(body),
+	)
+}
+
+func TestCheckStreamLoadResponse(t *testing.T) {
+	response := func(status int, body string) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	require.NoError(t, checkStreamLoadResponse(response(http.StatusOK, `{"Status": "Success"}`)))
+	require.NoError(t, checkStreamLoadResponse(response(http.StatusOK, `{"Status": "Publish Timeout"}`)))
+
+	err := checkStreamLoadResponse(response(http.StatusOK, `{"Status": "Fail", "Message": "too many filtered rows"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too many filtered rows")
+
+	err = checkStreamLoadResponse(response(http.StatusUnauthorized, "denied"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "denied")
+}
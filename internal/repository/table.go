@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultTable names the events table, collection or CQL table unless the
+// configuration renames it.
+const defaultTable = "events"
+
+// tableNamePattern is lower case only: the SQL and CQL databases fold
+// unquoted identifiers to lower case, so a mixed-case name would create one
+// table and then look up another.
+var tableNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// collectionNamePattern is tableNamePattern for MongoDB, whose collection
+// names are case-sensitive.
+var collectionNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// tableName validates the configured name of the events table, returning
+// the default for an empty one. It must be a plain lower-case identifier,
+// as it is also the prefix of the indexes and partitions named after the
+// table.
+func tableName(name string) (string, error) {
+	return checkName(name, tableNamePattern, "lower-case letters, digits and underscores")
+}
+
+// collectionName validates the configured name of the MongoDB events
+// collection, returning the default for an empty one.
+func collectionName(name string) (string, error) {
+	return checkName(name, collectionNamePattern, "letters, digits and underscores")
+}
+
+func checkName(name string, pattern *regexp.Regexp, want string) (string, error) {
+	if name == "" {
+		return defaultTable, nil
+	}
+
+	if !pattern.MatchString(name) {
+		return "", fmt.Errorf("invalid table name %q: must be %s", name, want)
+	}
+
+	return name, nil
+}
+
+// tableSQL renames the events table of a statement written against it to
+// table: every "events" in the statement, the table and the indexes,
+// partitions and views named after it, becomes table. Statements on other
+// tables, such as those naming an events column, must not go through it.
+func tableSQL(query, table string) string {
+	if table == "" || table == defaultTable {
+		return query
+	}
+
+	return strings.ReplaceAll(query, defaultTable, table)
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableName(t *testing.T) {
+	name, err := tableName("")
+	require.NoError(t, err)
+	assert.Equal(t, "events", name)
+
+	name, err = tableName("ci_run_7")
+	require.NoError(t, err)
+	assert.Equal(t, "ci_run_7", name)
+
+	for _, bad := range []string{"7events", "events-ci", "events; DROP TABLE users", `"events"`, "public.events", "CiRun"} {
+		_, err := tableName(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestCollectionName(t *testing.T) {
+	name, err := collectionName("")
+	require.NoError(t, err)
+	assert.Equal(t, "events", name)
+
+	name, err = collectionName("CiRun")
+	require.NoError(t, err)
+	assert.Equal(t, "CiRun", name, "collection names are case-sensitive")
+
+	_, err = collectionName("ci-run")
+	assert.Error(t, err)
+}
+
+func TestTableSQL(t *testing.T) {
+	const query = "CREATE INDEX idx_events_user_id ON events (user_id)"
+
+	assert.Equal(t, query, tableSQL(query, ""))
+	assert.Equal(t, query, tableSQL(query, "events"))
+	assert.Equal(t, "CREATE INDEX idx_ci_7_user_id ON ci_7 (user_id)", tableSQL(query, "ci_7"))
+}
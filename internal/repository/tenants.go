@@ -0,0 +1,17 @@
+package repository
+
+// Tenant queries shared by the SQL backends, in "?" and "$n" bind styles.
+const (
+	tenantEventsQuery       = "SELECT " + sqlEventColumns + " FROM events WHERE tenant_id = ? ORDER BY created_at DESC LIMIT ?"
+	tenantEventsQueryDollar = "SELECT " + sqlEventColumns + " FROM events WHERE tenant_id = $1 ORDER BY created_at DESC LIMIT $2"
+)
+
+// tenantColumns returns the columns beyond those of an event that the events
+// table has with tenants.
+func tenantColumns(tenants bool) []string {
+	if !tenants {
+		return nil
+	}
+
+	return []string{"tenant_id"}
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"math"
+	"time"
+)
+
+// TTL methods returned by EnableTTL.
+const (
+	ttlMethodCQL    = "cql_ttl"    // per-write USING TTL
+	ttlMethodIndex  = "ttl_index"  // background TTL monitor
+	ttlMethodClause = "ttl_clause" // rows dropped on merge
+	// ttlMethodJob is the retention delete run as a scheduled job would run
+	// it, for databases without native expiry.
+	ttlMethodJob = "emulated_job"
+)
+
+// ttlSeconds returns the whole seconds of ttl, at least one.
+func ttlSeconds(ttl time.Duration) int64 {
+	return max(1, int64(ttl/time.Second))
+}
+
+// remainingTTL returns the seconds until an event created at createdAt
+// expires under ttl. Expiry runs on event time rather than write time, so
+// already expired events get the minimum TTL of one second.
+func remainingTTL(createdAt time.Time, ttl time.Duration, now time.Time) int {
+	left := createdAt.Add(ttl).Sub(now)
+
+	return int(math.Max(1, math.Ceil(left.Seconds())))
+}
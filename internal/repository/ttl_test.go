@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemainingTTL(t *testing.T) {
+	now := time.Now()
+	ttl := 7 * 24 * time.Hour
+
+	assert.Equal(t, 24*3600, remainingTTL(now.AddDate(0, 0, -6), ttl, now))
+	assert.Equal(t, 1, remainingTTL(now.AddDate(0, 0, -30), ttl, now))
+	assert.Equal(t, 1, remainingTTL(now.Add(-ttl), ttl, now))
+}
+
+func TestTTLSeconds(t *testing.T) {
+	assert.Equal(t, int64(86400), ttlSeconds(24*time.Hour))
+	assert.Equal(t, int64(1), ttlSeconds(time.Millisecond))
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// usersInsertChunk is the row count of one multi-row users INSERT, which
+// keeps the bind parameters below SQLite's limit.
+const usersInsertChunk = 1000
+
+// Country breakdown joins shared by the SQL backends, in "?" and "$n" bind
+// styles.
+const (
+	countryBreakdownQuery = `
+		SELECT u.country, COUNT(*) AS cnt
+		FROM events e JOIN users u ON u.user_id = e.user_id
+		WHERE e.created_at BETWEEN ? AND ?
+		GROUP BY u.country
+		ORDER BY cnt DESC
+	`
+	countryBreakdownQueryDollar = `
+		SELECT u.country, COUNT(*) AS cnt
+		FROM events e JOIN users u ON u.user_id = e.user_id
+		WHERE e.created_at BETWEEN $1 AND $2
+		GROUP BY u.country
+		ORDER BY cnt DESC
+	`
+)
+
+// insertSQLUsers writes users with multi-row INSERTs; dollar selects "$n"
+// placeholders instead of "?".
+func insertSQLUsers(ctx context.Context, db *sql.DB, users []generator.User, dollar bool) error {
+	for chunk := range slices.Chunk(users, usersInsertChunk) {
+		var b strings.Builder
+
+		b.WriteString("INSERT INTO users (user_id, country, plan) VALUES ")
+
+		args := make([]any, 0, 3*len(chunk))
+
+		for i, u := range chunk {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+
+			if dollar {
+				fmt.Fprintf(&b, "($%d, $%d, $%d)", 3*i+1, 3*i+2, 3*i+3)
+			} else {
+				b.WriteString("(?, ?, ?)")
+			}
+
+			args = append(args, u.ID, u.Country, u.Plan)
+		}
+
+		if _, err := db.ExecContext(ctx, b.String(), args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// querySQLCountryBreakdown runs a country breakdown join and reads the
+// per-country counts.
+func querySQLCountryBreakdown(ctx context.Context, db *sql.DB, query string, start, end any) ([]CountryCount, error) {
+	rows, err := db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var counts []CountryCount
+
+	for rows.Next() {
+		var c CountryCount
+		if err := rows.Scan(&c.Country, &c.Count); err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
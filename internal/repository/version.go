@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// querySQLVersion runs a query returning the server version as a single
+// string.
+func querySQLVersion(ctx context.Context, db *sql.DB, query string) (string, error) {
+	var v string
+	err := db.QueryRowContext(ctx, query).Scan(&v)
+
+	return strings.TrimSpace(v), err
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+)
+
+// VictoriaMetricsRepo maps every event to a sample of value 1 in the "events"
+// series, labelled with event_type and a user bucket (user_id modulo a fixed
+// count). Per-user labels would explode cardinality, so unique users are
+// reported as the number of distinct user buckets that saw events.
+type VictoriaMetricsRepo struct {
+	http        *http.Client
+	baseURL     string
+	userBuckets int64
+}
+
+func NewVictoriaMetricsRepo(ctx context.Context, cfg *config.VictoriaMetricsConfig) (*VictoriaMetricsRepo, error) {
+	r := &VictoriaMetricsRepo{
+		http:        &http.Client{Timeout: 5 * time.Minute},
+		baseURL:     strings.TrimSuffix(cfg.URL, "/"),
+		userBuckets: int64(max(cfg.UserBuckets, 1)),
+	}
+
+	if _, err := r.do(ctx, http.MethodGet, "/health", nil); err != nil {
+		return nil, fmt.Errorf("failed to reach victoriametrics: %w", err)
+	}
+
+	return r, nil
+}
+
+func (r *VictoriaMetricsRepo) do(ctx context.Context, method, path string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("victoriametrics %s %s failed: %s: %s", method, path, resp.Status, bytes.TrimSpace(raw))
+	}
+
+	return raw, nil
+}
+
+// InitSchema deletes any previously imported events series; series are
+// created implicitly on import.
+func (r *VictoriaMetricsRepo) InitSchema(ctx context.Context) error {
+	return r.deleteSeries(ctx)
+}
+
+type vmSeriesKey struct {
+	eventType  string
+	userBucket int64
+}
+
+type vmImportLine struct {
+	Metric     map[string]string `json:"metric"`
+	Values     []float64         `json:"values"`
+	Timestamps []int64           `json:"timestamps"`
+}
+
+// encodeVMImport groups events by series and writes one JSON line per series
+// in the /api/v1/import format.
+func encodeVMImport(w io.Writer, events []generator.Event, userBuckets int64) error {
+	series := make(map[vmSeriesKey]*vmImportLine)
+
+	for _, event := range events {
+		key := vmSeriesKey{eventType: event.EventType, userBucket: event.UserID % userBuckets}
+
+		line, ok := series[key]
+		if !ok {
+			line = &vmImportLine{Metric: map[string]string{
+				"__name__":    "events",
+				"event_type":  key.eventType,
+				"user_bucket": strconv.FormatInt(key.userBucket, 10),
+			}}
+			series[key] = line
+		}
+
+		line.Values = append(line.Values, 1)
+		line.Timestamps = append(line.Timestamps, event.CreatedAt.UnixMilli())
+	}
+
+	enc := json.NewEncoder(w)
+	for _, line := range series {
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *VictoriaMetricsRepo) InsertBatch(ctx context.Context, events []generator.Event) error {
+	var body bytes.Buffer
+
+	if err := encodeVMImport(&body, events, r.userBuckets); err != nil {
+		return err
+	}
+
+	_, err := r.do(ctx, http.MethodPost, "/api/v1/import", &body)
+
+	return err
+}
+
+type vmQueryRangeResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][2]any          `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// queryRange evaluates a MetricsQL expression at hourly steps and returns the
+// value per (hour, event_type). The point at time T covers (T-1h, T], so it is
+// attributed to the hour starting at T-1h.
+func (r *VictoriaMetricsRepo) queryRange(ctx context.Context, query string, start, end time.Time) (map[hourlyKey]int64, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", strconv.FormatInt(start.UTC().Truncate(time.Hour).Add(time.Hour).Unix(), 10))
+	params.Set("end", strconv.FormatInt(end.Unix(), 10))
+	params.Set("step", "1h")
+
+	raw, err := r.do(ctx, http.MethodGet, "/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseVMQueryRange(raw)
+}
+
+func parseVMQueryRange(raw []byte) (map[hourlyKey]int64, error) {
+	var resp vmQueryRangeResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode victoriametrics response: %w", err)
+	}
+
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("victoriametrics query failed: %s", resp.Error)
+	}
+
+	values := make(map[hourlyKey]int64)
+
+	for _, series := range resp.Data.Result {
+		for _, point := range series.Values {
+			hour, value, err := parseVMPoint(point)
+			if err != nil {
+				return nil, err
+			}
+
+			values[hourlyKey{hour: hour, eventType: series.Metric["event_type"]}] = value
+		}
+	}
+
+	return values, nil
+}
+
+// parseVMPoint returns the start of the hour a query_range point covers, in
+// Unix nanoseconds, and its value.
+func parseVMPoint(point [2]any) (int64, int64, error) {
+	ts, ok := point[0].(float64)
+	if !ok {
+		return 0, 0, fmt.Errorf("unexpected victoriametrics timestamp %v", point[0])
+	}
+
+	value, err := strconv.ParseFloat(fmt.Sprint(point[1]), 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return time.Unix(int64(ts), 0).Add(-time.Hour).UnixNano(), int64(value), nil
+}
+
+func (r *VictoriaMetricsRepo) GetEventStats(ctx context.Context, start, end time.Time) ([]EventStats, error) {
+	counts, err := r.queryRange(ctx, `sum by (event_type) (count_over_time(events[1h]))`, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := r.queryRange(ctx, `count by (event_type) (count_over_time(events[1h]))`, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]EventStats, 0, len(counts))
+
+	for key, count := range counts {
+		stats = append(stats, EventStats{
+			Hour:        time.Unix(0, key.hour).UTC(),
+			EventType:   key.eventType,
+			Count:       count,
+			UniqueUsers: buckets[key],
+		})
+	}
+
+	sortEventStats(stats)
+
+	return stats, nil
+}
+
+// GetStorageStats flushes in-memory buffers and sums vm_data_size_bytes and
+// vm_rows from the /metrics endpoint; indexdb parts count as index size.
+func (r *VictoriaMetricsRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	_, _ = r.do(ctx, http.MethodGet, "/internal/force_flush", nil)
+
+	raw, err := r.do(ctx, http.MethodGet, "/metrics", nil)
+	if err != nil {
+		return &StorageStats{}
+	}
+
+	return &StorageStats{
+		TotalSize: sumPromMetric(raw, "vm_data_size_bytes", ""),
+		IndexSize: sumPromMetric(raw, "vm_data_size_bytes", `type="indexdb/`),
+		RowCount:  sumPromMetric(raw, "vm_rows", `type="storage/`),
+	}
+}
+
+// sumPromMetric sums the samples of a metric in Prometheus text exposition
+// format whose label set contains labelFilter (all samples if empty).
+func sumPromMetric(raw []byte, name, labelFilter string) int64 {
+	var total float64
+
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		labels, value, ok := parsePromSample(scanner.Text(), name)
+		if !ok || !strings.Contains(labels, labelFilter) {
+			continue
+		}
+
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			total += v
+		}
+	}
+
+	return int64(total)
+}
+
+// parsePromSample splits a sample line of the metric name into its label set,
+// braces included, and value; ok is false for other lines.
+func parsePromSample(line, name string) (labels, value string, ok bool) {
+	rest, ok := strings.CutPrefix(line, name)
+	if !ok {
+		return "", "", false
+	}
+
+	if strings.HasPrefix(rest, "{") {
+		end := strings.LastIndex(rest, "}") + 1
+		labels, rest = rest[:end], rest[end:]
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || rest[0] != ' ' {
+		return "", "", false // a longer metric name sharing the prefix
+	}
+
+	return labels, fields[0], true
+}
+
+func (r *VictoriaMetricsRepo) deleteSeries(ctx context.Context) error {
+	_, err := r.do(ctx, http.MethodPost, "/api/v1/admin/tsdb/delete_series?match[]=events", nil)
+	return err
+}
+
+func (r *VictoriaMetricsRepo) Cleanup(ctx context.Context) error {
+	return r.deleteSeries(ctx)
+}
+
+func (r *VictoriaMetricsRepo) Close() error {
+	r.http.CloseIdleConnections()
+	return nil
+}
+
+// ServerVersion returns the short_version label of vm_app_version, e.g.
+// "v1.96.0".
+func (r *VictoriaMetricsRepo) ServerVersion(ctx context.Context) (string, error) {
+	raw, err := r.do(ctx, http.MethodGet, "/metrics", nil)
+	if err != nil {
+		return "", err
+	}
+
+	if v, ok := vmAppVersion(raw); ok {
+		return v, nil
+	}
+
+	return "", errors.New("no vm_app_version in /metrics")
+}
+
+// vmAppVersion returns the short_version label of the vm_app_version metric
+// in Prometheus text exposition format.
+func vmAppVersion(raw []byte) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		labels, ok := strings.CutPrefix(scanner.Text(), "vm_app_version{")
+		if !ok {
+			continue
+		}
+
+		if _, v, ok := strings.Cut(labels, `short_version="`); ok {
+			v, _, _ = strings.Cut(v, `"`)
+			return v, true
+		}
+	}
+
+	return "", false
+}
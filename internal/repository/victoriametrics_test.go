@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeVMImport(t *testing.T) {
+	at := time.UnixMilli(1704207600000)
+	events := []generator.Event{
+		{UserID: 1, EventType: "click", CreatedAt: at},
+		{UserID: 5, EventType: "click", CreatedAt: at.Add(time.Second)},
+		{UserID: 2, EventType: "click", CreatedAt: at},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, encodeVMImport(&buf, events, 4))
+
+	lines := map[string]vmImportLine{}
+
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line vmImportLine
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+
+		lines[line.Metric["user_bucket"]] = line
+	}
+
+	require.Len(t, lines, 2)
+	assert.Equal(t, []int64{1704207600000, 1704207601000}, lines["1"].Timestamps)
+	assert.Equal(t, []float64{1, 1}, lines["1"].Values)
+	assert.Equal(t, "events", lines["2"].Metric["__name__"])
+	assert.Equal(t, "click", lines["2"].Metric["event_type"])
+}
+
+func TestParseVMQueryRange(t *testing.T) {
+	raw := []byte(`{"status": "success", "data": {"resultType": "matrix", "result": [
+		{"metric": {"event_type": "click"}, "values": [[1704211200, "12"], [1704214800, "3"]]}
+	]}}`)
+
+	values, err := parseVMQueryRange(raw)
+	require.NoError(t, err)
+
+	hour := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	assert.Equal(t, int64(12), values[hourlyKey{hour: hour.UnixNano(), eventType: "click"}])
+	assert.Equal(t, int64(3), values[hourlyKey{hour: hour.Add(time.Hour).UnixNano(), eventType: "click"}])
+}
+
+func TestSumPromMetric(t *testing.T) {
+	raw := []byte(`# HELP vm_rows
+vm_rows{type="storage/inmemory"} 10
+vm_rows{type="storage/small"} 90
+vm_rows{type="indexdb/file"} 1000
+vm_rows_added_to_storage_total 5000
+vm_data_size_bytes{type="storage/small"} 2048
+vm_data_size_bytes{type="indexdb/file"} 512
+`)
+
+	assert.Equal(t, int64(100), sumPromMetric(raw, "vm_rows", `type="storage/`))
+	assert.Equal(t, int64(2560), sumPromMetric(raw, "vm_data_size_bytes", ""))
+	assert.Equal(t, int64(512), sumPromMetric(raw, "vm_data_size_bytes", `type="indexdb/`))
+}
+
+func TestVMAppVersion(t *testing.T) {
+	raw := []byte(`vm_app_uptime_seconds 12
+vm_app_version{version="victoria-metrics-20240109-181229-tags-v1.96.0-0-g8d7cd0c9f", short_version="v1.96.0"} 1
+`)
+
+	v, ok := vmAppVersion(raw)
+	assert.True(t, ok)
+	assert.Equal(t, "v1.96.0", v)
+
+	_, ok = vmAppVersion([]byte("vm_rows 10\n"))
+	assert.False(t, ok)
+}
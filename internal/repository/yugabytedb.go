@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/skoredin/db-benchmark-suite/internal/config"
+)
+
+// YugabyteDBRepo benchmarks YugabyteDB's PostgreSQL-compatible YSQL API. It
+// reuses the Postgres inserts and queries; the schema drops BRIN (unsupported
+// on DocDB) and partitioning in favor of range-sharded tablets, and storage is
+// read from tablet metrics because pg_total_relation_size does not cover DocDB.
+type YugabyteDBRepo struct {
+	*PostgresRepo
+	http       *http.Client
+	metricsURL string
+	database   string
+}
+
+func NewYugabyteDBRepo(ctx context.Context, cfg *config.YugabyteDBConfig) (*YugabyteDBRepo, error) {
+	pg, err := NewPostgresRepo(ctx, &cfg.PostgresConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to yugabytedb: %w", err)
+	}
+
+	return &YugabyteDBRepo{
+		PostgresRepo: pg,
+		http:         &http.Client{Timeout: 10 * time.Second},
+		metricsURL:   fmt.Sprintf("http://%s:%s/metrics", cfg.Host, cfg.TServerWebPort),
+		database:     cfg.Database,
+	}, nil
+}
+
+func (r *YugabyteDBRepo) InitSchema(ctx context.Context) error {
+	schema := `
+		DROP TABLE IF EXISTS events CASCADE;
+
+		CREATE TABLE events (
+			id BIGSERIAL,
+			event_id VARCHAR(255) NOT NULL,
+			user_id BIGINT NOT NULL,
+			event_type VARCHAR(50) NOT NULL,
+			payload TEXT,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (created_at ASC, event_id ASC)
+		);
+	`
+
+	if !r.deferred {
+		schema += ybSecondaryIndexes(r.indexes)
+	}
+
+	_, err := r.db.ExecContext(ctx, tableSQL(schema, r.table))
+
+	return err
+}
+
+// CreateIndexes builds the secondary indexes InitSchema deferred,
+// backfilling them from the loaded events.
+func (r *YugabyteDBRepo) CreateIndexes(ctx context.Context) error {
+	indexes := ybSecondaryIndexes(r.indexes)
+	if indexes == "" {
+		return nil
+	}
+
+	_, err := r.db.ExecContext(ctx, tableSQL(indexes, r.table))
+
+	return err
+}
+
+// ybSecondaryIndexes returns the index DDL of an index set. The
+// range-sharded primary key already serves created_at ranges, so minimal
+// adds nothing over none.
+func ybSecondaryIndexes(set string) string {
+	if set != config.IndexesFull {
+		return ""
+	}
+
+	return `
+		CREATE INDEX idx_events_type_time ON events(event_type HASH, created_at ASC);
+		CREATE INDEX idx_events_user_id ON events(user_id HASH);
+	`
+}
+
+// GetStorageStats sums SST file sizes reported by the tablet server for every
+// tablet of the events table and its indexes.
+func (r *YugabyteDBRepo) GetStorageStats(ctx context.Context) *StorageStats {
+	stats := &StorageStats{Details: map[string]int64{}}
+
+	_ = r.db.QueryRowContext(ctx, tableSQL(`SELECT COUNT(*) FROM events`, r.table)).Scan(&stats.RowCount)
+
+	var tablets sql.NullInt64
+	if err := r.db.QueryRowContext(ctx,
+		tableSQL(`SELECT num_tablets FROM yb_table_properties('events'::regclass)`, r.table),
+	).Scan(&tablets); err == nil && tablets.Valid {
+		stats.Details["tablets"] = tablets.Int64
+	}
+
+	sizes, err := r.tabletSizes(ctx)
+	if err != nil {
+		return stats
+	}
+
+	stats.TotalSize = sizes[r.table]
+
+	// Each index is a table of its own, named after the events table.
+	for table, size := range sizes {
+		if strings.HasPrefix(table, "idx_"+r.table+"_") {
+			stats.IndexSize += size
+		}
+	}
+
+	stats.TotalSize += stats.IndexSize
+
+	return stats
+}
+
+// ybMetricEntity is one entry of the tablet server /metrics JSON endpoint.
+type ybMetricEntity struct {
+	Type       string            `json:"type"`
+	Attributes map[string]string `json:"attributes"`
+	Metrics    []struct {
+		Name  string `json:"name"`
+		Value int64  `json:"value"`
+	} `json:"metrics"`
+}
+
+// tabletSizes returns the current SST size per table name in the events
+// namespace, summed over all tablets hosted by the tablet server.
+func (r *YugabyteDBRepo) tabletSizes(ctx context.Context) (map[string]int64, error) {
+	entities, err := r.tabletMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]int64)
+
+	for _, e := range entities {
+		if e.Type != "tablet" || e.Attributes["namespace_name"] != r.database {
+			continue
+		}
+
+		for _, m := range e.Metrics {
+			sizes[e.Attributes["table_name"]] += m.Value
+		}
+	}
+
+	return sizes, nil
+}
+
+// tabletMetrics fetches the SST size metrics of the tablet server.
+func (r *YugabyteDBRepo) tabletMetrics(ctx context.Context) ([]ybMetricEntity, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		r.metricsURL+"?metrics=rocksdb_current_version_sst_files_size", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		reply, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("tablet metrics from %s: %s: %s", r.metricsURL, resp.Status, strings.TrimSpace(string(reply)))
+	}
+
+	var entities []ybMetricEntity
+	if err := json.NewDecoder(resp.Body).Decode(&entities); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+
+func (r *YugabyteDBRepo) Close() error {
+	r.http.CloseIdleConnections()
+	return r.PostgresRepo.Close()
+}
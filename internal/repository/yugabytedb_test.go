@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYugabyteTabletSizes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"type": "tablet", "attributes": {"namespace_name": "yugabyte", "table_name": "events"},
+			 "metrics": [{"name": "rocksdb_current_version_sst_files_size", "value": 100}]},
+			{"type": "tablet", "attributes": {"namespace_name": "yugabyte", "table_name": "events"},
+			 "metrics": [{"name": "rocksdb_current_version_sst_files_size", "value": 50}]},
+			{"type": "tablet", "attributes": {"namespace_name": "yugabyte", "table_name": "idx_events_user_id"},
+			 "metrics": [{"name": "rocksdb_current_version_sst_files_size", "value": 20}]},
+			{"type": "tablet", "attributes": {"namespace_name": "other", "table_name": "events"},
+			 "metrics": [{"name": "rocksdb_current_version_sst_files_size", "value": 999}]},
+			{"type": "server", "attributes": {}, "metrics": []}
+		]`))
+	}))
+	defer srv.Close()
+
+	repo := &YugabyteDBRepo{http: srv.Client(), metricsURL: srv.URL + "/metrics", database: "yugabyte"}
+
+	sizes, err := repo.tabletSizes(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{"events": 150, "idx_events_user_id": 20}, sizes)
+}
+
+func TestYugabyteTabletSizesHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "metrics disabled", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	repo := &YugabyteDBRepo{http: srv.Client(), metricsURL: srv.URL + "/metrics", database: "yugabyte"}
+
+	_, err := repo.tabletSizes(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503 Service Unavailable: metrics disabled")
+}